@@ -15,6 +15,7 @@ var (
 	archIncludeExternal bool
 	archRefresh         bool
 	archFormat          string
+	archMinSymbols      int
 )
 
 var archCmd = &cobra.Command{
@@ -32,7 +33,9 @@ Examples:
   ckb arch
   ckb arch --depth=3
   ckb arch --include-external-deps
-  ckb arch --refresh`,
+  ckb arch --refresh
+  ckb arch --min-symbols=10
+  ckb arch --format=mermaid > architecture.mmd`,
 	Run: runArch,
 }
 
@@ -40,7 +43,8 @@ func init() {
 	archCmd.Flags().IntVar(&archDepth, "depth", 2, "Maximum dependency depth")
 	archCmd.Flags().BoolVar(&archIncludeExternal, "include-external-deps", false, "Include external dependencies")
 	archCmd.Flags().BoolVar(&archRefresh, "refresh", false, "Bypass cache and recompute")
-	archCmd.Flags().StringVar(&archFormat, "format", "json", "Output format (json, human)")
+	archCmd.Flags().StringVar(&archFormat, "format", "json", "Output format (json, human, mermaid)")
+	archCmd.Flags().IntVar(&archMinSymbols, "min-symbols", 0, "Fold modules with fewer symbols into a misc module per top-level directory (0 keeps all)")
 	rootCmd.AddCommand(archCmd)
 }
 
@@ -57,6 +61,10 @@ func runArch(cmd *cobra.Command, args []string) {
 		Depth:               archDepth,
 		IncludeExternalDeps: archIncludeExternal,
 		Refresh:             archRefresh,
+		MinSymbols:          archMinSymbols,
+	}
+	if archFormat == query.ArchitectureFormatMermaid {
+		opts.Format = query.ArchitectureFormatMermaid
 	}
 	response, err := engine.GetArchitecture(ctx, opts)
 	if err != nil {
@@ -64,18 +72,22 @@ func runArch(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Convert to CLI response format
-	cliResponse := convertArchResponse(response)
+	if opts.Format == query.ArchitectureFormatMermaid {
+		fmt.Print(response.Mermaid)
+	} else {
+		// Convert to CLI response format
+		cliResponse := convertArchResponse(response)
+
+		// Format and output
+		output, err := FormatResponse(cliResponse, OutputFormat(archFormat))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+			os.Exit(1)
+		}
 
-	// Format and output
-	output, err := FormatResponse(cliResponse, OutputFormat(archFormat))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
-		os.Exit(1)
+		fmt.Println(output)
 	}
 
-	fmt.Println(output)
-
 	logger.Debug("Architecture query completed", map[string]interface{}{
 		"modules":  len(response.Modules),
 		"duration": time.Since(start).Milliseconds(),
@@ -84,30 +96,34 @@ func runArch(cmd *cobra.Command, args []string) {
 
 // ArchitectureResponseCLI contains architecture overview for CLI output
 type ArchitectureResponseCLI struct {
-	Modules         []ModuleSummaryCLI  `json:"modules"`
-	DependencyGraph []DependencyEdgeCLI `json:"dependencyGraph"`
-	Entrypoints     []EntrypointCLI     `json:"entrypoints"`
-	Provenance      *ProvenanceCLI      `json:"provenance,omitempty"`
+	Modules          []ModuleSummaryCLI  `json:"modules"`
+	DependencyGraph  []DependencyEdgeCLI `json:"dependencyGraph"`
+	Entrypoints      []EntrypointCLI     `json:"entrypoints"`
+	Cycles           [][]string          `json:"cycles,omitempty"`
+	CollapsedModules int                 `json:"collapsedModules,omitempty"`
+	Provenance       *ProvenanceCLI      `json:"provenance,omitempty"`
 }
 
 // ModuleSummaryCLI provides module statistics
 type ModuleSummaryCLI struct {
-	ModuleID      string `json:"moduleId"`
-	Name          string `json:"name"`
-	RootPath      string `json:"rootPath"`
-	Language      string `json:"language,omitempty"`
-	FileCount     int    `json:"fileCount"`
-	SymbolCount   int    `json:"symbolCount"`
-	IncomingEdges int    `json:"incomingEdges"`
-	OutgoingEdges int    `json:"outgoingEdges"`
+	ModuleID       string `json:"moduleId"`
+	Name           string `json:"name"`
+	RootPath       string `json:"rootPath"`
+	Language       string `json:"language,omitempty"`
+	FileCount      int    `json:"fileCount"`
+	SymbolCount    int    `json:"symbolCount"`
+	IncomingEdges  int    `json:"incomingEdges"`
+	OutgoingEdges  int    `json:"outgoingEdges"`
+	CollapsedCount int    `json:"collapsedCount,omitempty"`
 }
 
 // DependencyEdgeCLI represents a module dependency
 type DependencyEdgeCLI struct {
-	From     string `json:"from"`
-	To       string `json:"to"`
-	Kind     string `json:"kind"`
-	Strength int    `json:"strength"`
+	From          string         `json:"from"`
+	To            string         `json:"to"`
+	Kind          string         `json:"kind"`
+	Strength      int            `json:"strength"`
+	KindStrengths map[string]int `json:"kindStrengths,omitempty"`
 }
 
 // EntrypointCLI represents an entry point file
@@ -122,24 +138,26 @@ func convertArchResponse(resp *query.GetArchitectureResponse) *ArchitectureRespo
 	modules := make([]ModuleSummaryCLI, 0, len(resp.Modules))
 	for _, m := range resp.Modules {
 		modules = append(modules, ModuleSummaryCLI{
-			ModuleID:      m.ModuleId,
-			Name:          m.Name,
-			RootPath:      m.Path,
-			Language:      m.Language,
-			FileCount:     m.FileCount,
-			SymbolCount:   m.SymbolCount,
-			IncomingEdges: m.IncomingEdges,
-			OutgoingEdges: m.OutgoingEdges,
+			ModuleID:       m.ModuleId,
+			Name:           m.Name,
+			RootPath:       m.Path,
+			Language:       m.Language,
+			FileCount:      m.FileCount,
+			SymbolCount:    m.SymbolCount,
+			IncomingEdges:  m.IncomingEdges,
+			OutgoingEdges:  m.OutgoingEdges,
+			CollapsedCount: m.CollapsedCount,
 		})
 	}
 
 	edges := make([]DependencyEdgeCLI, 0, len(resp.DependencyGraph))
 	for _, e := range resp.DependencyGraph {
 		edges = append(edges, DependencyEdgeCLI{
-			From:     e.From,
-			To:       e.To,
-			Kind:     e.Kind,
-			Strength: e.Strength,
+			From:          e.From,
+			To:            e.To,
+			Kind:          e.Kind,
+			Strength:      e.Strength,
+			KindStrengths: e.KindStrengths,
 		})
 	}
 
@@ -154,9 +172,11 @@ func convertArchResponse(resp *query.GetArchitectureResponse) *ArchitectureRespo
 	}
 
 	result := &ArchitectureResponseCLI{
-		Modules:         modules,
-		DependencyGraph: edges,
-		Entrypoints:     entrypoints,
+		Modules:          modules,
+		DependencyGraph:  edges,
+		Entrypoints:      entrypoints,
+		Cycles:           resp.Cycles,
+		CollapsedModules: resp.CollapsedModules,
 	}
 
 	if resp.Provenance != nil {
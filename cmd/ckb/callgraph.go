@@ -29,15 +29,16 @@ Direction options:
 Examples:
   ckb callgraph 'scip-go gomod myproject myproject/pkg/MyFunction'
   ckb callgraph --direction=callers --depth=2 'symbol-id'
-  ckb callgraph --format=human 'symbol-id'`,
+  ckb callgraph --format=human 'symbol-id'
+  ckb callgraph --format=dot 'symbol-id' | dot -Tsvg > graph.svg`,
 	Args: cobra.ExactArgs(1),
 	Run:  runCallgraph,
 }
 
 func init() {
 	callgraphCmd.Flags().StringVar(&callgraphDirection, "direction", "both", "Direction to traverse (callers, callees, both)")
-	callgraphCmd.Flags().IntVar(&callgraphDepth, "depth", 1, "Maximum depth to traverse (1-4)")
-	callgraphCmd.Flags().StringVar(&callgraphFormat, "format", "json", "Output format (json, human)")
+	callgraphCmd.Flags().IntVar(&callgraphDepth, "depth", 0, "Maximum depth to traverse (0 = configured default, see traversal.callGraph)")
+	callgraphCmd.Flags().StringVar(&callgraphFormat, "format", "json", "Output format (json, human, dot)")
 	rootCmd.AddCommand(callgraphCmd)
 }
 
@@ -55,21 +56,28 @@ func runCallgraph(cmd *cobra.Command, args []string) {
 		Direction: callgraphDirection,
 		Depth:     callgraphDepth,
 	}
+	if callgraphFormat == query.CallGraphFormatDOT {
+		opts.OutputFormat = query.CallGraphFormatDOT
+	}
 	response, err := engine.GetCallGraph(ctx, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting call graph: %v\n", err)
 		os.Exit(1)
 	}
 
-	cliResponse := convertCallgraphResponse(response)
+	if opts.OutputFormat == query.CallGraphFormatDOT {
+		fmt.Print(response.Dot)
+	} else {
+		cliResponse := convertCallgraphResponse(response)
 
-	output, err := FormatResponse(cliResponse, OutputFormat(callgraphFormat))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
-		os.Exit(1)
-	}
+		output, err := FormatResponse(cliResponse, OutputFormat(callgraphFormat))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+			os.Exit(1)
+		}
 
-	fmt.Println(output)
+		fmt.Println(output)
+	}
 
 	logger.Debug("Callgraph query completed", map[string]interface{}{
 		"symbolId":  symbolId,
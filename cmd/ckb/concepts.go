@@ -11,8 +11,12 @@ import (
 )
 
 var (
-	conceptsFormat string
-	conceptsLimit  int
+	conceptsFormat           string
+	conceptsLimit            int
+	conceptsExcludeGenerated bool
+	conceptsRespectGitignore bool
+	conceptsMinOccurrences   int
+	conceptsMinFiles         int
 )
 
 var conceptsCmd = &cobra.Command{
@@ -25,6 +29,7 @@ Helps understand domain vocabulary and main ideas in the codebase.
 Examples:
   ckb concepts
   ckb concepts --limit=8
+  ckb concepts --min-occurrences=5 --min-files=3
   ckb concepts --format=human`,
 	Run: runConcepts,
 }
@@ -32,6 +37,10 @@ Examples:
 func init() {
 	conceptsCmd.Flags().StringVar(&conceptsFormat, "format", "json", "Output format (json, human)")
 	conceptsCmd.Flags().IntVar(&conceptsLimit, "limit", 12, "Maximum concepts to return (max 12)")
+	conceptsCmd.Flags().BoolVar(&conceptsExcludeGenerated, "exclude-generated", true, "Ignore generated code")
+	conceptsCmd.Flags().BoolVar(&conceptsRespectGitignore, "respect-gitignore", true, "Skip .gitignore'd paths when deriving concepts from file names")
+	conceptsCmd.Flags().IntVar(&conceptsMinOccurrences, "min-occurrences", 2, "Drop concepts seen fewer times (applied before the limit)")
+	conceptsCmd.Flags().IntVar(&conceptsMinFiles, "min-files", 1, "Drop concepts found in fewer files (applied before the limit)")
 	rootCmd.AddCommand(conceptsCmd)
 }
 
@@ -44,7 +53,11 @@ func runConcepts(cmd *cobra.Command, args []string) {
 	ctx := newContext()
 
 	opts := query.ListKeyConceptsOptions{
-		Limit: conceptsLimit,
+		Limit:            conceptsLimit,
+		ExcludeGenerated: conceptsExcludeGenerated,
+		RespectGitignore: conceptsRespectGitignore,
+		MinOccurrences:   conceptsMinOccurrences,
+		MinFiles:         conceptsMinFiles,
 	}
 	response, err := engine.ListKeyConcepts(ctx, opts)
 	if err != nil {
@@ -90,18 +103,15 @@ type ConceptCLI struct {
 func convertConceptsResponse(resp *query.ListKeyConceptsResponse) *ConceptsResponseCLI {
 	concepts := make([]ConceptCLI, 0, len(resp.Concepts))
 	for _, c := range resp.Concepts {
-		concept := ConceptCLI{
+		concepts = append(concepts, ConceptCLI{
 			Name:        c.Name,
 			Category:    c.Category,
 			Occurrences: c.Occurrences,
 			Files:       c.Files,
 			Symbols:     c.Symbols,
 			Description: c.Description,
-		}
-		if c.Ranking != nil {
-			concept.Score = c.Ranking.Score
-		}
-		concepts = append(concepts, concept)
+			Score:       c.Score,
+		})
 	}
 
 	result := &ConceptsResponseCLI{
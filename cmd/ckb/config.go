@@ -158,6 +158,7 @@ func outputConfigHuman(result *config.LoadResult, diffOnly bool) {
 		printConfigSection("  queryTtlSeconds", cfg.Cache.QueryTtlSeconds, defaults.Cache.QueryTtlSeconds)
 		printConfigSection("  viewTtlSeconds", cfg.Cache.ViewTtlSeconds, defaults.Cache.ViewTtlSeconds)
 		printConfigSection("  negativeTtlSeconds", cfg.Cache.NegativeTtlSeconds, defaults.Cache.NegativeTtlSeconds)
+		printConfigSection("  callGraphEntries", cfg.Cache.CallGraphEntries, defaults.Cache.CallGraphEntries)
 
 		fmt.Println("\nbudget:")
 		printConfigSection("  maxModules", cfg.Budget.MaxModules, defaults.Budget.MaxModules)
@@ -228,6 +229,9 @@ func printConfigDiff(cfg, defaults *config.Config) {
 	if cfg.Cache.NegativeTtlSeconds != defaults.Cache.NegativeTtlSeconds {
 		diffs = append(diffs, fmt.Sprintf("cache.negativeTtlSeconds: %d (default: %d)", cfg.Cache.NegativeTtlSeconds, defaults.Cache.NegativeTtlSeconds))
 	}
+	if cfg.Cache.CallGraphEntries != defaults.Cache.CallGraphEntries {
+		diffs = append(diffs, fmt.Sprintf("cache.callGraphEntries: %d (default: %d)", cfg.Cache.CallGraphEntries, defaults.Cache.CallGraphEntries))
+	}
 
 	// Budget
 	if cfg.Budget.MaxModules != defaults.Budget.MaxModules {
@@ -66,7 +66,7 @@ func init() {
 	// List flags
 	decisionsCmd.Flags().StringVar(&decisionsStatus, "status", "", "Filter by status (proposed, accepted, deprecated, superseded)")
 	decisionsCmd.Flags().StringVar(&decisionsModule, "module", "", "Filter by affected module")
-	decisionsCmd.Flags().StringVar(&decisionsSearch, "search", "", "Search in title and content")
+	decisionsCmd.Flags().StringVar(&decisionsSearch, "search", "", "Rank decisions containing every search term by relevance")
 	decisionsCmd.Flags().IntVar(&decisionsLimit, "limit", 50, "Maximum decisions to return")
 	decisionsCmd.Flags().StringVar(&decisionsFormat, "format", "human", "Output format (json, human)")
 
@@ -17,6 +17,13 @@ var (
 	diffSummaryHead      string
 	diffSummaryTimeStart string
 	diffSummaryTimeEnd   string
+	diffSummaryErrors    bool
+	diffSummaryBaseIndex string
+	diffSummaryExclude   []string
+	diffSummaryComplex   bool
+	diffSummaryCycloMax  int
+	diffSummaryCogMax    int
+	diffSummaryMergeBase bool
 )
 
 var diffSummaryCmd = &cobra.Command{
@@ -31,7 +38,12 @@ Examples:
   ckb diff-summary
   ckb diff-summary --commit=abc1234
   ckb diff-summary --base=main --head=feature/my-branch
+  ckb diff-summary --base=main --head=feature/my-branch --merge-base
   ckb diff-summary --start=2024-01-01 --end=2024-06-30
+  ckb diff-summary --base=main --head=HEAD --check-errors
+  ckb diff-summary --base=main --head=HEAD --base-index=/tmp/main.scip
+  ckb diff-summary --base=main --head=HEAD --exclude-paths='vendor/**,**/*.pb.go'
+  ckb diff-summary --base=main --head=HEAD --complexity --cyclomatic-threshold=10
   ckb diff-summary --format=human`,
 	Run: runDiffSummary,
 }
@@ -41,8 +53,15 @@ func init() {
 	diffSummaryCmd.Flags().StringVar(&diffSummaryCommit, "commit", "", "Single commit hash to analyze")
 	diffSummaryCmd.Flags().StringVar(&diffSummaryBase, "base", "", "Base commit/ref for range (use with --head)")
 	diffSummaryCmd.Flags().StringVar(&diffSummaryHead, "head", "", "Head commit/ref for range (use with --base)")
+	diffSummaryCmd.Flags().BoolVar(&diffSummaryMergeBase, "merge-base", false, "Diff the range from the merge base of --base and --head (base...head)")
 	diffSummaryCmd.Flags().StringVar(&diffSummaryTimeStart, "start", "", "Start date for time window (ISO8601 or YYYY-MM-DD)")
 	diffSummaryCmd.Flags().StringVar(&diffSummaryTimeEnd, "end", "", "End date for time window (ISO8601 or YYYY-MM-DD)")
+	diffSummaryCmd.Flags().BoolVar(&diffSummaryErrors, "check-errors", false, "Flag ignored errors in added Go lines")
+	diffSummaryCmd.Flags().StringVar(&diffSummaryBaseIndex, "base-index", "", "SCIP index built at the base, for comparing public signatures")
+	diffSummaryCmd.Flags().StringSliceVar(&diffSummaryExclude, "exclude-paths", nil, "Glob patterns for files to leave out of the summary (comma-separated or repeated)")
+	diffSummaryCmd.Flags().BoolVar(&diffSummaryComplex, "complexity", false, "Flag functions whose complexity crossed a threshold or grew sharply")
+	diffSummaryCmd.Flags().IntVar(&diffSummaryCycloMax, "cyclomatic-threshold", 15, "Per-function cyclomatic complexity limit (with --complexity)")
+	diffSummaryCmd.Flags().IntVar(&diffSummaryCogMax, "cognitive-threshold", 20, "Per-function cognitive complexity limit (with --complexity)")
 	rootCmd.AddCommand(diffSummaryCmd)
 }
 
@@ -54,15 +73,23 @@ func runDiffSummary(cmd *cobra.Command, args []string) {
 	engine := mustGetEngine(repoRoot, logger)
 	ctx := newContext()
 
-	opts := query.SummarizeDiffOptions{}
+	opts := query.SummarizeDiffOptions{
+		CheckErrorHandling:  diffSummaryErrors,
+		BaseIndexPath:       diffSummaryBaseIndex,
+		ExcludePaths:        diffSummaryExclude,
+		IncludeComplexity:   diffSummaryComplex,
+		CyclomaticThreshold: diffSummaryCycloMax,
+		CognitiveThreshold:  diffSummaryCogMax,
+	}
 
 	// Determine which selector to use
 	if diffSummaryCommit != "" {
 		opts.Commit = diffSummaryCommit
 	} else if diffSummaryBase != "" && diffSummaryHead != "" {
 		opts.CommitRange = &query.CommitRangeSelector{
-			Base: diffSummaryBase,
-			Head: diffSummaryHead,
+			Base:      diffSummaryBase,
+			Head:      diffSummaryHead,
+			MergeBase: diffSummaryMergeBase,
 		}
 	} else if diffSummaryTimeStart != "" || diffSummaryTimeEnd != "" {
 		opts.TimeWindow = &query.TimeWindowSelector{
@@ -131,12 +158,15 @@ type DiffSymbolAffectedCLI struct {
 	ChangeType   string `json:"changeType"`
 	IsPublicAPI  bool   `json:"isPublicApi"`
 	IsEntrypoint bool   `json:"isEntrypoint"`
+
+	SignatureChange *query.SignatureDelta `json:"signatureChange,omitempty"`
 }
 
 type DiffRiskSignalCLI struct {
 	Type        string  `json:"type"`
 	Severity    string  `json:"severity"`
 	FilePath    string  `json:"filePath"`
+	Line        int     `json:"line,omitempty"`
 	Description string  `json:"description"`
 	Confidence  float64 `json:"confidence"`
 }
@@ -172,6 +202,8 @@ func convertDiffSummaryResponse(resp *query.SummarizeDiffResponse) *DiffSummaryR
 			ChangeType:   s.ChangeType,
 			IsPublicAPI:  s.IsPublicAPI,
 			IsEntrypoint: s.IsEntrypoint,
+
+			SignatureChange: s.SignatureChange,
 		})
 	}
 
@@ -181,6 +213,7 @@ func convertDiffSummaryResponse(resp *query.SummarizeDiffResponse) *DiffSummaryR
 			Type:        r.Type,
 			Severity:    r.Severity,
 			FilePath:    r.FilePath,
+			Line:        r.Line,
 			Description: r.Description,
 			Confidence:  r.Confidence,
 		})
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"ckb/internal/docs"
+	"ckb/internal/query"
 )
 
 var (
@@ -18,6 +19,8 @@ var (
 	docsExported    bool
 	docsLimit       int
 	docsFailUnder   float64
+	docsScope       string
+	docsBacklog     int
 )
 
 var docsCmd = &cobra.Command{
@@ -118,13 +121,16 @@ var docsCoverageCmd = &cobra.Command{
 	Long: `Show documentation coverage statistics.
 
 Reports how many symbols are documented and which high-centrality
-symbols are missing documentation.
+symbols are missing documentation. When a SCIP index is available, public
+symbols' doc comments are also scored and the undocumented or poorly
+documented ones are listed, most-used first.
 
 Use --fail-under to enforce a minimum coverage threshold in CI.
 
 Examples:
   ckb docs coverage
   ckb docs coverage --exported-only
+  ckb docs coverage --scope=internal/api --backlog=20
   ckb docs coverage --fail-under=80  # Exit 1 if coverage < 80%`,
 	Run: runDocsCoverage,
 }
@@ -154,6 +160,8 @@ func init() {
 	docsCoverageCmd.Flags().BoolVar(&docsExported, "exported-only", false, "Only count exported symbols")
 	docsCoverageCmd.Flags().IntVar(&docsLimit, "top", 10, "Number of top undocumented symbols")
 	docsCoverageCmd.Flags().Float64Var(&docsFailUnder, "fail-under", 0, "Exit 1 if coverage below threshold (0-100)")
+	docsCoverageCmd.Flags().StringVar(&docsScope, "scope", "", "Path prefix for the doc-comment backlog")
+	docsCoverageCmd.Flags().IntVar(&docsBacklog, "backlog", 50, "Maximum symbols in the doc-comment backlog")
 
 	docsCmd.AddCommand(docsIndexCmd)
 	docsCmd.AddCommand(docsSymbolCmd)
@@ -359,7 +367,12 @@ func runDocsCoverage(cmd *cobra.Command, args []string) {
 	repoRoot := mustGetRepoRoot()
 	engine := mustGetEngine(repoRoot, logger)
 
-	report, err := engine.GetDocCoverage(docsExported, docsLimit)
+	report, err := engine.GetDocCoverage(newContext(), query.DocCoverageOptions{
+		ExportedOnly: docsExported,
+		TopN:         docsLimit,
+		Scope:        docsScope,
+		Limit:        docsBacklog,
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting coverage: %v\n", err)
 		os.Exit(1)
@@ -370,6 +383,31 @@ func runDocsCoverage(cmd *cobra.Command, args []string) {
 		Documented:      report.Documented,
 		Undocumented:    report.Undocumented,
 		CoveragePercent: report.CoveragePercent,
+		Limitations:     report.Limitations,
+	}
+
+	if report.SymbolDocs != nil {
+		symbolDocs := &SymbolDocCoverageCLI{
+			PublicSymbols:    report.SymbolDocs.PublicSymbols,
+			Documented:       report.SymbolDocs.Documented,
+			PoorlyDocumented: report.SymbolDocs.PoorlyDocumented,
+			Undocumented:     report.SymbolDocs.Undocumented,
+			Truncated:        report.SymbolDocs.Truncated,
+		}
+		for _, b := range report.SymbolDocs.Backlog {
+			symbolDocs.Backlog = append(symbolDocs.Backlog, SymbolDocScoreCLI{
+				SymbolID:       b.SymbolId,
+				Name:           b.Name,
+				Kind:           b.Kind,
+				File:           b.FileId,
+				Line:           b.Line,
+				Score:          b.Score,
+				Quality:        b.Quality,
+				Issues:         b.Issues,
+				ReferenceCount: b.ReferenceCount,
+			})
+		}
+		response.SymbolDocs = symbolDocs
 	}
 
 	for _, u := range report.TopUndocumented {
@@ -466,11 +504,34 @@ type StaleRefCLI struct {
 }
 
 type DocsCoverageResponseCLI struct {
-	TotalSymbols    int              `json:"totalSymbols"`
-	Documented      int              `json:"documented"`
-	Undocumented    int              `json:"undocumented"`
-	CoveragePercent float64          `json:"coveragePercent"`
-	TopUndocumented []UndocSymbolCLI `json:"topUndocumented,omitempty"`
+	TotalSymbols    int                   `json:"totalSymbols"`
+	Documented      int                   `json:"documented"`
+	Undocumented    int                   `json:"undocumented"`
+	CoveragePercent float64               `json:"coveragePercent"`
+	TopUndocumented []UndocSymbolCLI      `json:"topUndocumented,omitempty"`
+	SymbolDocs      *SymbolDocCoverageCLI `json:"symbolDocs,omitempty"`
+	Limitations     []string              `json:"limitations,omitempty"`
+}
+
+type SymbolDocCoverageCLI struct {
+	PublicSymbols    int                 `json:"publicSymbols"`
+	Documented       int                 `json:"documented"`
+	PoorlyDocumented int                 `json:"poorlyDocumented"`
+	Undocumented     int                 `json:"undocumented"`
+	Backlog          []SymbolDocScoreCLI `json:"backlog"`
+	Truncated        bool                `json:"truncated,omitempty"`
+}
+
+type SymbolDocScoreCLI struct {
+	SymbolID       string   `json:"symbolId"`
+	Name           string   `json:"name"`
+	Kind           string   `json:"kind"`
+	File           string   `json:"file"`
+	Line           int      `json:"line,omitempty"`
+	Score          float64  `json:"score"`
+	Quality        string   `json:"quality"`
+	Issues         []string `json:"issues,omitempty"`
+	ReferenceCount int      `json:"referenceCount"`
 }
 
 type UndocSymbolCLI struct {
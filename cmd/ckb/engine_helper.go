@@ -101,8 +101,8 @@ func newContext() context.Context {
 // When format is "json", logs go to stderr (human-readable) so stdout has clean JSON output.
 func newLogger(format string) *logging.Logger {
 	output := os.Stdout
-	if format == "json" {
-		output = os.Stderr // Keep stdout clean for JSON data
+	if format == "json" || format == "dot" || format == "csv" || format == "mermaid" || format == "prometheus" {
+		output = os.Stderr // Keep stdout clean for machine-readable data
 	}
 	return logging.NewLogger(logging.Config{
 		Format: logging.HumanFormat, // Always human-readable logs
@@ -14,6 +14,7 @@ var (
 	entrypointsFormat       string
 	entrypointsModuleFilter string
 	entrypointsLimit        int
+	entrypointsMaxFanOut    int
 )
 
 var entrypointsCmd = &cobra.Command{
@@ -31,6 +32,7 @@ Examples:
   ckb entrypoints
   ckb entrypoints --module=internal/api
   ckb entrypoints --limit=50
+  ckb entrypoints --max-fan-out=60
   ckb entrypoints --format=human`,
 	Run: runEntrypoints,
 }
@@ -39,6 +41,7 @@ func init() {
 	entrypointsCmd.Flags().StringVar(&entrypointsFormat, "format", "json", "Output format (json, human)")
 	entrypointsCmd.Flags().StringVar(&entrypointsModuleFilter, "module", "", "Filter to specific module")
 	entrypointsCmd.Flags().IntVar(&entrypointsLimit, "limit", 30, "Maximum entrypoints to return")
+	entrypointsCmd.Flags().IntVar(&entrypointsMaxFanOut, "max-fan-out", 0, "Flag entrypoints calling more functions than this (default from config, 100)")
 	rootCmd.AddCommand(entrypointsCmd)
 }
 
@@ -53,6 +56,7 @@ func runEntrypoints(cmd *cobra.Command, args []string) {
 	opts := query.ListEntrypointsOptions{
 		ModuleFilter: entrypointsModuleFilter,
 		Limit:        entrypointsLimit,
+		MaxFanOut:    entrypointsMaxFanOut,
 	}
 	response, err := engine.ListEntrypoints(ctx, opts)
 	if err != nil {
@@ -92,6 +96,7 @@ type EntrypointDetailCLI struct {
 	Location       *LocationCLI `json:"location,omitempty"`
 	DetectionBasis string       `json:"detectionBasis"`
 	FanOut         int          `json:"fanOut"`
+	HighFanOut     bool         `json:"highFanOut,omitempty"`
 	Score          float64      `json:"score"`
 }
 
@@ -115,6 +120,7 @@ func convertEntrypointsResponse(resp *query.ListEntrypointsResponse) *Entrypoint
 		}
 		if e.Ranking != nil {
 			entry.Score = e.Ranking.Score
+			entry.HighFanOut = e.Ranking.Signals["highFanOut"] == true
 		}
 		entrypoints = append(entrypoints, entry)
 	}
@@ -72,6 +72,8 @@ func formatHuman(resp interface{}) (string, error) {
 		return formatTraceHuman(v)
 	case *JobsListResponseCLI:
 		return formatJobsListHuman(v)
+	case *SelftestResponseCLI:
+		return formatSelftestHuman(v)
 	default:
 		// For types without human formatters, output JSON with a note
 		json, err := formatJSON(resp)
@@ -341,6 +343,17 @@ func formatSymbolHuman(resp *SymbolResponseCLI) (string, error) {
 		b.WriteString(fmt.Sprintf("\nModule: %s\n", resp.Module.ModuleID))
 	}
 
+	if resp.SourceSnippet != nil {
+		b.WriteString("\nSource:\n")
+		for i, line := range resp.SourceSnippet.Lines {
+			marker := " "
+			if resp.SourceSnippet.StartLine+i == resp.SourceSnippet.Line {
+				marker = ">"
+			}
+			b.WriteString(fmt.Sprintf("%s %5d  %s\n", marker, resp.SourceSnippet.StartLine+i, line))
+		}
+	}
+
 	return b.String(), nil
 }
 
@@ -371,6 +384,10 @@ func formatRefsHuman(resp *ReferencesResponseCLI) (string, error) {
 		}
 	}
 
+	if resp.NextCursor != "" {
+		b.WriteString(fmt.Sprintf("\nMore references: --cursor=%s\n", resp.NextCursor))
+	}
+
 	return b.String(), nil
 }
 
@@ -382,6 +399,9 @@ func formatArchHuman(resp *ArchitectureResponseCLI) (string, error) {
 	b.WriteString(strings.Repeat("=", 60) + "\n\n")
 
 	b.WriteString(fmt.Sprintf("Modules: %d\n", len(resp.Modules)))
+	if resp.CollapsedModules > 0 {
+		b.WriteString(fmt.Sprintf("Collapsed: %d small modules folded into misc modules\n", resp.CollapsedModules))
+	}
 	b.WriteString(fmt.Sprintf("Dependencies: %d\n", len(resp.DependencyGraph)))
 	b.WriteString(fmt.Sprintf("Entrypoints: %d\n\n", len(resp.Entrypoints)))
 
@@ -390,6 +410,9 @@ func formatArchHuman(resp *ArchitectureResponseCLI) (string, error) {
 		b.WriteString(fmt.Sprintf("  %s (%s)\n", m.Name, m.Language))
 		b.WriteString(fmt.Sprintf("    Path: %s\n", m.RootPath))
 		b.WriteString(fmt.Sprintf("    Files: %d, Symbols: %d\n", m.FileCount, m.SymbolCount))
+		if m.CollapsedCount > 0 {
+			b.WriteString(fmt.Sprintf("    Collapsed: %d modules\n", m.CollapsedCount))
+		}
 		b.WriteString(fmt.Sprintf("    Deps: %d incoming, %d outgoing\n\n", m.IncomingEdges, m.OutgoingEdges))
 	}
 
@@ -412,7 +435,11 @@ func formatImpactHuman(resp *ImpactResponseCLI) (string, error) {
 
 	if resp.RiskScore != nil {
 		b.WriteString(fmt.Sprintf("Risk Level: %s (score: %.2f)\n", resp.RiskScore.Level, resp.RiskScore.Score))
-		b.WriteString(fmt.Sprintf("Explanation: %s\n\n", resp.RiskScore.Explanation))
+		b.WriteString(fmt.Sprintf("Explanation: %s\n", resp.RiskScore.Explanation))
+		if resp.RiskScore.Narrative != "" {
+			b.WriteString(fmt.Sprintf("Why: %s\n", resp.RiskScore.Narrative))
+		}
+		b.WriteString("\n")
 	}
 
 	b.WriteString(fmt.Sprintf("Direct Impact: %d symbols\n", len(resp.DirectImpact)))
@@ -636,6 +663,9 @@ func formatHotspotsHuman(resp *HotspotsResponseCLI) (string, error) {
 		b.WriteString(fmt.Sprintf("%d. %s\n", i+1, h.FilePath))
 		b.WriteString(fmt.Sprintf("   Score: %.2f, Risk: %s\n", h.Score, h.RiskLevel))
 		b.WriteString(fmt.Sprintf("   Changes: %d, Authors: %d\n", h.Churn.ChangeCount, h.Churn.AuthorCount))
+		for _, a := range h.TopAuthors {
+			b.WriteString(fmt.Sprintf("     %s: %d commits (%.1f%%)\n", a.Author, a.Commits, a.Percentage))
+		}
 		b.WriteString("\n")
 	}
 
@@ -726,7 +756,11 @@ func formatTraceHuman(resp *TraceResponseCLI) (string, error) {
 			if node.Kind != "" {
 				kind = fmt.Sprintf(" (%s)", node.Kind)
 			}
-			b.WriteString(fmt.Sprintf("%s→ %s%s\n", indent, node.Name, kind))
+			approx := ""
+			if !node.Resolved {
+				approx = " [unresolved]"
+			}
+			b.WriteString(fmt.Sprintf("%s→ %s%s%s\n", indent, node.Name, kind, approx))
 		}
 		b.WriteString("\n")
 	}
@@ -775,3 +809,33 @@ func formatJobsListHuman(resp *JobsListResponseCLI) (string, error) {
 
 	return b.String(), nil
 }
+
+// formatSelftestHuman formats self-test results in human-readable format
+func formatSelftestHuman(resp *SelftestResponseCLI) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("Determinism Self-Test\n")
+	b.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	for _, r := range resp.Results {
+		switch {
+		case r.Error != "":
+			b.WriteString(fmt.Sprintf("  ? %-20s error: %s\n", r.Tool, r.Error))
+		case r.Deterministic:
+			b.WriteString(fmt.Sprintf("  ✓ %s\n", r.Tool))
+		default:
+			b.WriteString(fmt.Sprintf("  ✗ %-20s differs at %s\n", r.Tool, r.FirstDiffField))
+		}
+	}
+
+	b.WriteString("\n")
+	if resp.Passed {
+		b.WriteString("PASSED: all tools produced identical output\n")
+	} else if resp.FirstDiff != nil {
+		b.WriteString(fmt.Sprintf("FAILED: first difference in %s at %s\n", resp.FirstDiff.Tool, resp.FirstDiff.FirstDiffField))
+	} else {
+		b.WriteString("FAILED: some tools could not be verified\n")
+	}
+
+	return b.String(), nil
+}
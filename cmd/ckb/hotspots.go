@@ -16,6 +16,8 @@ var (
 	hotspotsLimit     int
 	hotspotsTimeStart string
 	hotspotsTimeEnd   string
+	hotspotsAuthors   bool
+	hotspotsGenerated bool
 )
 
 var hotspotsCmd = &cobra.Command{
@@ -31,14 +33,18 @@ Examples:
   ckb hotspots --scope=internal/api
   ckb hotspots --limit=50
   ckb hotspots --start=2024-01-01 --end=2024-06-30
-  ckb hotspots --format=human`,
+  ckb hotspots --format=human
+  ckb hotspots --format=csv > hotspots.csv
+  ckb hotspots --include-authors`,
 	Run: runHotspots,
 }
 
 func init() {
-	hotspotsCmd.Flags().StringVar(&hotspotsFormat, "format", "json", "Output format (json, human)")
+	hotspotsCmd.Flags().StringVar(&hotspotsFormat, "format", "json", "Output format (json, human, csv)")
 	hotspotsCmd.Flags().StringVar(&hotspotsScope, "scope", "", "Module path to focus on")
 	hotspotsCmd.Flags().IntVar(&hotspotsLimit, "limit", 20, "Maximum hotspots to return (max 50)")
+	hotspotsCmd.Flags().BoolVar(&hotspotsGenerated, "exclude-generated", true, "Drop generated files from the ranking")
+	hotspotsCmd.Flags().BoolVar(&hotspotsAuthors, "include-authors", false, "Show the top authors per hotspot (slower: one git call per file)")
 	hotspotsCmd.Flags().StringVar(&hotspotsTimeStart, "start", "", "Start date (ISO8601 or YYYY-MM-DD)")
 	hotspotsCmd.Flags().StringVar(&hotspotsTimeEnd, "end", "", "End date (ISO8601 or YYYY-MM-DD)")
 	rootCmd.AddCommand(hotspotsCmd)
@@ -53,8 +59,13 @@ func runHotspots(cmd *cobra.Command, args []string) {
 	ctx := newContext()
 
 	opts := query.GetHotspotsOptions{
-		Scope: hotspotsScope,
-		Limit: hotspotsLimit,
+		Scope:            hotspotsScope,
+		Limit:            hotspotsLimit,
+		IncludeAuthors:   hotspotsAuthors,
+		ExcludeGenerated: hotspotsGenerated,
+	}
+	if hotspotsFormat == query.HotspotsFormatCSV {
+		opts.Format = query.HotspotsFormatCSV
 	}
 
 	if hotspotsTimeStart != "" || hotspotsTimeEnd != "" {
@@ -70,15 +81,19 @@ func runHotspots(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	cliResponse := convertHotspotsResponse(response)
+	if opts.Format == query.HotspotsFormatCSV {
+		fmt.Print(response.Csv)
+	} else {
+		cliResponse := convertHotspotsResponse(response)
 
-	output, err := FormatResponse(cliResponse, OutputFormat(hotspotsFormat))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
-		os.Exit(1)
-	}
+		output, err := FormatResponse(cliResponse, OutputFormat(hotspotsFormat))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+			os.Exit(1)
+		}
 
-	fmt.Println(output)
+		fmt.Println(output)
+	}
 
 	logger.Debug("Hotspots query completed", map[string]interface{}{
 		"count":    len(response.Hotspots),
@@ -97,13 +112,17 @@ type HotspotsResponseCLI struct {
 }
 
 type HotspotCLI struct {
-	FilePath  string          `json:"filePath"`
-	Role      string          `json:"role,omitempty"`
-	Language  string          `json:"language,omitempty"`
-	Churn     HotspotChurnCLI `json:"churn"`
-	Recency   string          `json:"recency"`
-	RiskLevel string          `json:"riskLevel"`
-	Score     float64         `json:"score"`
+	FilePath  string                 `json:"filePath"`
+	Role      string                 `json:"role,omitempty"`
+	Language  string                 `json:"language,omitempty"`
+	Churn     HotspotChurnCLI        `json:"churn"`
+	Coupling  *query.HotspotCoupling `json:"coupling,omitempty"`
+	Recency   string                 `json:"recency"`
+	Trend     string                 `json:"trend,omitempty"`
+	RiskLevel string                 `json:"riskLevel"`
+	Score     float64                `json:"score"`
+
+	TopAuthors []query.AuthorChurn `json:"topAuthors,omitempty"`
 }
 
 type HotspotChurnCLI struct {
@@ -126,8 +145,11 @@ func convertHotspotsResponse(resp *query.GetHotspotsResponse) *HotspotsResponseC
 				AverageChanges: h.Churn.AverageChanges,
 				Score:          h.Churn.Score,
 			},
-			Recency:   h.Recency,
-			RiskLevel: h.RiskLevel,
+			Coupling:   h.Coupling,
+			Recency:    h.Recency,
+			Trend:      h.Trend,
+			RiskLevel:  h.RiskLevel,
+			TopAuthors: h.TopAuthors,
 		}
 		if h.Ranking != nil {
 			hotspot.Score = h.Ranking.Score
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"ckb/internal/impact"
 	"ckb/internal/query"
 )
 
@@ -14,6 +15,8 @@ var (
 	impactDepth        int
 	impactIncludeTests bool
 	impactFormat       string
+	impactChangeKind   string
+	impactNewName      string
 )
 
 var impactCmd = &cobra.Command{
@@ -30,15 +33,19 @@ Provides:
 Examples:
   ckb impact symbol-123
   ckb impact symbol-123 --depth=3
-  ckb impact symbol-123 --include-tests`,
+  ckb impact symbol-123 --include-tests
+  ckb impact symbol-123 --change-kind=delete
+  ckb impact symbol-123 --change-kind=rename --new-name=Resolve`,
 	Args: cobra.ExactArgs(1),
 	Run:  runImpact,
 }
 
 func init() {
-	impactCmd.Flags().IntVar(&impactDepth, "depth", 2, "Maximum impact depth")
+	impactCmd.Flags().IntVar(&impactDepth, "depth", 0, "Maximum impact depth (0 = configured default, see traversal.impact)")
 	impactCmd.Flags().BoolVar(&impactIncludeTests, "include-tests", false, "Include test dependencies")
 	impactCmd.Flags().StringVar(&impactFormat, "format", "json", "Output format (json, human)")
+	impactCmd.Flags().StringVar(&impactChangeKind, "change-kind", "modify", "Change to analyze (modify, delete, rename)")
+	impactCmd.Flags().StringVar(&impactNewName, "new-name", "", "New name of the symbol, with --change-kind=rename")
 	rootCmd.AddCommand(impactCmd)
 }
 
@@ -56,6 +63,8 @@ func runImpact(cmd *cobra.Command, args []string) {
 		SymbolId:     symbolID,
 		Depth:        impactDepth,
 		IncludeTests: impactIncludeTests,
+		ChangeKind:   impactChangeKind,
+		NewName:      impactNewName,
 	}
 	response, err := engine.AnalyzeImpact(ctx, opts)
 	if err != nil {
@@ -84,13 +93,16 @@ func runImpact(cmd *cobra.Command, args []string) {
 
 // ImpactResponseCLI contains impact analysis results for CLI output
 type ImpactResponseCLI struct {
-	SymbolID         string            `json:"symbolId"`
-	Symbol           *SymbolInfoCLI    `json:"symbol,omitempty"`
-	RiskScore        *RiskScoreCLI     `json:"riskScore,omitempty"`
-	DirectImpact     []ImpactItemCLI   `json:"directImpact"`
-	TransitiveImpact []ImpactItemCLI   `json:"transitiveImpact,omitempty"`
-	ModulesAffected  []ModuleImpactCLI `json:"modulesAffected"`
-	Provenance       *ProvenanceCLI    `json:"provenance,omitempty"`
+	SymbolID         string                 `json:"symbolId"`
+	Symbol           *SymbolInfoCLI         `json:"symbol,omitempty"`
+	RiskScore        *RiskScoreCLI          `json:"riskScore,omitempty"`
+	DirectImpact     []ImpactItemCLI        `json:"directImpact"`
+	TransitiveImpact []ImpactItemCLI        `json:"transitiveImpact,omitempty"`
+	ModulesAffected  []ModuleImpactCLI      `json:"modulesAffected"`
+	ChangeKind       string                 `json:"changeKind,omitempty"`
+	NewName          string                 `json:"newName,omitempty"`
+	RiskThresholds   *impact.RiskThresholds `json:"riskThresholds,omitempty"`
+	Provenance       *ProvenanceCLI         `json:"provenance,omitempty"`
 }
 
 // RiskScoreCLI describes risk assessment
@@ -98,6 +110,7 @@ type RiskScoreCLI struct {
 	Level       string          `json:"level"`
 	Score       float64         `json:"score"`
 	Explanation string          `json:"explanation"`
+	Narrative   string          `json:"narrative,omitempty"`
 	Factors     []RiskFactorCLI `json:"factors,omitempty"`
 }
 
@@ -106,6 +119,7 @@ type RiskFactorCLI struct {
 	Name   string  `json:"name"`
 	Value  float64 `json:"value"`
 	Weight float64 `json:"weight"`
+	Detail string  `json:"detail,omitempty"`
 }
 
 // ImpactItemCLI represents an affected symbol
@@ -117,6 +131,8 @@ type ImpactItemCLI struct {
 	ModuleID   string       `json:"moduleId"`
 	Location   *LocationCLI `json:"location,omitempty"`
 	Confidence float64      `json:"confidence"`
+	Breaking   bool         `json:"breaking,omitempty"`
+	UpdateKind string       `json:"updateKind,omitempty"`
 }
 
 // ModuleImpactCLI shows impact on a specific module
@@ -137,6 +153,8 @@ func convertImpactResponse(symbolID string, resp *query.AnalyzeImpactResponse) *
 			Distance:   item.Distance,
 			ModuleID:   item.ModuleId,
 			Confidence: item.Confidence,
+			Breaking:   item.Breaking,
+			UpdateKind: item.UpdateKind,
 		}
 		if item.Location != nil {
 			impactItem.Location = &LocationCLI{
@@ -158,6 +176,8 @@ func convertImpactResponse(symbolID string, resp *query.AnalyzeImpactResponse) *
 			Distance:   item.Distance,
 			ModuleID:   item.ModuleId,
 			Confidence: item.Confidence,
+			Breaking:   item.Breaking,
+			UpdateKind: item.UpdateKind,
 		}
 		if item.Location != nil {
 			impactItem.Location = &LocationCLI{
@@ -185,6 +205,9 @@ func convertImpactResponse(symbolID string, resp *query.AnalyzeImpactResponse) *
 		DirectImpact:     directImpact,
 		TransitiveImpact: transitiveImpact,
 		ModulesAffected:  modulesAffected,
+		ChangeKind:       resp.ChangeKind,
+		NewName:          resp.NewName,
+		RiskThresholds:   resp.RiskThresholds,
 	}
 
 	if resp.Symbol != nil {
@@ -210,12 +233,14 @@ func convertImpactResponse(symbolID string, resp *query.AnalyzeImpactResponse) *
 				Name:   f.Name,
 				Value:  f.Value,
 				Weight: f.Weight,
+				Detail: f.Detail,
 			})
 		}
 		result.RiskScore = &RiskScoreCLI{
 			Level:       resp.RiskScore.Level,
 			Score:       resp.RiskScore.Score,
 			Explanation: resp.RiskScore.Explanation,
+			Narrative:   resp.RiskScore.Narrative,
 			Factors:     factors,
 		}
 	}
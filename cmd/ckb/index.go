@@ -196,11 +196,10 @@ func runIndex(cmd *cobra.Command, args []string) {
 	}
 
 	// Build command - some languages need special handling
-	command := indexer.Command
-	switch lang {
-	case project.LangCpp:
-		cppCmd, err := project.BuildCppCommand(repoRoot, indexCompdb)
-		if err != nil || cppCmd == "" {
+	command, err := project.BuildIndexCommand(repoRoot, lang, indexPath, indexCompdb)
+	if err != nil {
+		switch lang {
+		case project.LangCpp:
 			fmt.Fprintln(os.Stderr, "compile_commands.json not found.")
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "Generate it with CMake:")
@@ -208,19 +207,16 @@ func runIndex(cmd *cobra.Command, args []string) {
 			fmt.Fprintln(os.Stderr, "")
 			fmt.Fprintln(os.Stderr, "Or specify path:")
 			fmt.Fprintln(os.Stderr, "  ckb index --lang cpp --compdb build/compile_commands.json")
-			os.Exit(1)
-		}
-		command = cppCmd
-
-	case project.LangRuby:
-		rubyCmd, err := project.BuildRubyCommand(repoRoot)
-		if err != nil {
+		case project.LangRuby:
 			fmt.Fprintln(os.Stderr, "bundle not found. Install Bundler:")
 			fmt.Fprintln(os.Stderr, "  gem install bundler")
-			os.Exit(1)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
-		command = rubyCmd
+		os.Exit(1)
+	}
 
+	switch lang {
 	case project.LangPHP:
 		warning, err := project.ValidatePHPSetup(repoRoot)
 		if warning != "" {
@@ -242,8 +238,6 @@ func runIndex(cmd *cobra.Command, args []string) {
 			fmt.Fprintf(os.Stderr, "Error creating index directory %s: %v\n", outputDir, err)
 			os.Exit(1)
 		}
-		// Add --output flag to use configured path
-		command = fmt.Sprintf("%s --output %s", command, indexPath)
 	}
 
 	// Check if indexer is installed
@@ -12,14 +12,17 @@ import (
 )
 
 var (
-	modulesFormat          string
-	modulesPath            string //nolint:unused // reserved for future use
-	modulesName            string
-	annotateResponsibility string
-	annotateCapabilities   string
-	annotateTags           string
-	annotatePublicPaths    string
-	annotateInternalPaths  string
+	modulesFormat           string
+	modulesPath             string //nolint:unused // reserved for future use
+	modulesName             string
+	modulesExcludeGenerated bool
+	modulesRespectGitignore bool
+	annotateResponsibility  string
+	annotateCapabilities    string
+	annotateTags            string
+	annotatePublicPaths     string
+	annotateInternalPaths   string
+	annotateAPISymbols      string
 	// Responsibilities subcommand flags
 	respModuleId     string //nolint:unused // reserved for future use
 	respIncludeFiles bool
@@ -79,6 +82,8 @@ func init() {
 	// Overview flags
 	modulesCmd.Flags().StringVar(&modulesFormat, "format", "json", "Output format (json, human)")
 	modulesCmd.Flags().StringVar(&modulesName, "name", "", "Optional friendly name for the module")
+	modulesCmd.Flags().BoolVar(&modulesExcludeGenerated, "exclude-generated", true, "Leave generated files out of the file count")
+	modulesCmd.Flags().BoolVar(&modulesRespectGitignore, "respect-gitignore", true, "Leave .gitignore'd paths out of the file count")
 
 	// Annotate flags
 	modulesAnnotateCmd.Flags().StringVar(&modulesFormat, "format", "json", "Output format (json, human)")
@@ -87,6 +92,7 @@ func init() {
 	modulesAnnotateCmd.Flags().StringVar(&annotateTags, "tags", "", "Comma-separated list of tags")
 	modulesAnnotateCmd.Flags().StringVar(&annotatePublicPaths, "public-paths", "", "Comma-separated list of public API paths")
 	modulesAnnotateCmd.Flags().StringVar(&annotateInternalPaths, "internal-paths", "", "Comma-separated list of internal paths")
+	modulesAnnotateCmd.Flags().StringVar(&annotateAPISymbols, "api-symbols", "", "Comma-separated symbol names or glob patterns forming the curated public API")
 
 	// Responsibilities subcommand flags
 	modulesResponsibilitiesCmd.Flags().StringVar(&respFormat, "format", "json", "Output format (json, human)")
@@ -112,8 +118,10 @@ func runModulesOverview(cmd *cobra.Command, args []string) {
 	ctx := newContext()
 
 	opts := query.ModuleOverviewOptions{
-		Path: path,
-		Name: modulesName,
+		Path:             path,
+		Name:             modulesName,
+		ExcludeGenerated: modulesExcludeGenerated,
+		RespectGitignore: modulesRespectGitignore,
 	}
 	response, err := engine.GetModuleOverview(ctx, opts)
 	if err != nil {
@@ -162,6 +170,9 @@ func runModulesAnnotate(cmd *cobra.Command, args []string) {
 	if annotateInternalPaths != "" {
 		input.InternalPaths = splitAndTrim(annotateInternalPaths)
 	}
+	if annotateAPISymbols != "" {
+		input.APISymbols = splitAndTrim(annotateAPISymbols)
+	}
 
 	result, err := engine.AnnotateModule(input)
 	if err != nil {
@@ -255,6 +266,7 @@ type AnnotateModuleResponseCLI struct {
 type AnnotateBoundariesCLI struct {
 	Public   []string `json:"public,omitempty"`
 	Internal []string `json:"internal,omitempty"`
+	API      []string `json:"api,omitempty"`
 }
 
 func convertAnnotateModuleResponse(resp *query.AnnotateModuleResult) *AnnotateModuleResponseCLI {
@@ -271,6 +283,7 @@ func convertAnnotateModuleResponse(resp *query.AnnotateModuleResult) *AnnotateMo
 		result.Boundaries = &AnnotateBoundariesCLI{
 			Public:   resp.Boundaries.Public,
 			Internal: resp.Boundaries.Internal,
+			API:      resp.Boundaries.API,
 		}
 	}
 
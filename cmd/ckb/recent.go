@@ -16,6 +16,8 @@ var (
 	recentLimit        int
 	recentTimeStart    string
 	recentTimeEnd      string
+	recentBase         string
+	recentHead         string
 )
 
 var recentCmd = &cobra.Command{
@@ -23,13 +25,15 @@ var recentCmd = &cobra.Command{
 	Short: "Find recently relevant files and symbols",
 	Long: `Find what matters now - files/symbols with recent activity that may need attention.
 
-Default time window is 7 days.
+Default time window is 7 days. Use --base and --head to analyze the
+commits between two refs instead, e.g. two release tags.
 
 Examples:
   ckb recent
   ckb recent --module=internal/api
   ckb recent --limit=50
   ckb recent --start=2024-01-01 --end=2024-01-31
+  ckb recent --base=v1.2.0 --head=v1.3.0
   ckb recent --format=human`,
 	Run: runRecent,
 }
@@ -40,6 +44,8 @@ func init() {
 	recentCmd.Flags().IntVar(&recentLimit, "limit", 20, "Maximum results to return")
 	recentCmd.Flags().StringVar(&recentTimeStart, "start", "", "Start date (ISO8601 or YYYY-MM-DD)")
 	recentCmd.Flags().StringVar(&recentTimeEnd, "end", "", "End date (ISO8601 or YYYY-MM-DD)")
+	recentCmd.Flags().StringVar(&recentBase, "base", "", "Base ref of a commit range (use with --head)")
+	recentCmd.Flags().StringVar(&recentHead, "head", "", "Head ref of a commit range (use with --base)")
 	rootCmd.AddCommand(recentCmd)
 }
 
@@ -63,6 +69,13 @@ func runRecent(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if recentBase != "" || recentHead != "" {
+		opts.CommitRange = &query.CommitRangeSelector{
+			Base: recentBase,
+			Head: recentHead,
+		}
+	}
+
 	response, err := engine.RecentlyRelevant(ctx, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting recent items: %v\n", err)
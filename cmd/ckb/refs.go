@@ -17,10 +17,14 @@ func looksLikeSCIPID(s string) bool {
 }
 
 var (
-	refsScope       string
-	refsIncludeTest bool
-	refsLimit       int
-	refsFormat      string
+	refsScope           string
+	refsIncludeTest     bool
+	refsLimit           int
+	refsFormat          string
+	refsDynamicDispatch bool
+	refsExternalOnly    bool
+	refsCursor          string
+	refsKinds           []string
 )
 
 var refsCmd = &cobra.Command{
@@ -32,15 +36,22 @@ Examples:
   ckb refs symbol-123
   ckb refs symbol-123 --scope=api-module
   ckb refs symbol-123 --include-tests
-  ckb refs symbol-123 --limit=100`,
+  ckb refs symbol-123 --external-only
+  ckb refs symbol-123 --kind=call
+  ckb refs symbol-123 --limit=100
+  ckb refs symbol-123 --cursor=<nextCursor>`,
 	Args: cobra.ExactArgs(1),
 	Run:  runRefs,
 }
 
 func init() {
-	refsCmd.Flags().StringVar(&refsScope, "scope", "", "Limit search to module ID")
+	refsCmd.Flags().StringVar(&refsScope, "scope", "", "Limit search to a path prefix, glob (e.g. **/handlers/*.go) or re:<regexp>")
 	refsCmd.Flags().BoolVar(&refsIncludeTest, "include-tests", false, "Include test file references")
 	refsCmd.Flags().IntVar(&refsLimit, "limit", 100, "Maximum number of references")
+	refsCmd.Flags().BoolVar(&refsDynamicDispatch, "dynamic-dispatch", false, "Include calls made through interface methods the symbol implements")
+	refsCmd.Flags().BoolVar(&refsExternalOnly, "external-only", false, "Only show references from outside the symbol's module")
+	refsCmd.Flags().StringVar(&refsCursor, "cursor", "", "Resume after a previous page (its nextCursor)")
+	refsCmd.Flags().StringSliceVar(&refsKinds, "kind", nil, "Only show references of this kind, e.g. call or import (can be specified multiple times; default all)")
 	refsCmd.Flags().StringVar(&refsFormat, "format", "json", "Output format (json, human)")
 	rootCmd.AddCommand(refsCmd)
 }
@@ -57,8 +68,9 @@ func runRefs(cmd *cobra.Command, args []string) {
 	// If the input doesn't look like a SCIP ID, try searching for it first
 	if !looksLikeSCIPID(symbolID) {
 		searchOpts := query.SearchSymbolsOptions{
-			Query: symbolID,
-			Limit: 1,
+			Query:          symbolID,
+			Limit:          1,
+			IncludePrivate: true,
 		}
 		searchResult, searchErr := engine.SearchSymbols(ctx, searchOpts)
 		if searchErr == nil && len(searchResult.Symbols) > 0 {
@@ -77,6 +89,11 @@ func runRefs(cmd *cobra.Command, args []string) {
 		Scope:        refsScope,
 		IncludeTests: refsIncludeTest,
 		Limit:        refsLimit,
+
+		IncludeDynamicDispatch: refsDynamicDispatch,
+		ExternalOnly:           refsExternalOnly,
+		Cursor:                 refsCursor,
+		Kinds:                  refsKinds,
 	}
 	response, err := engine.FindReferences(ctx, opts)
 	if err != nil {
@@ -105,11 +122,14 @@ func runRefs(cmd *cobra.Command, args []string) {
 
 // ReferencesResponseCLI contains reference results for CLI output
 type ReferencesResponseCLI struct {
-	SymbolID        string                `json:"symbolId"`
-	TotalReferences int                   `json:"totalReferences"`
-	References      []ReferenceCLI        `json:"references"`
-	ByModule        []ModuleReferencesCLI `json:"byModule,omitempty"`
-	Provenance      *ProvenanceCLI        `json:"provenance,omitempty"`
+	SymbolID        string                       `json:"symbolId"`
+	TotalReferences int                          `json:"totalReferences"`
+	NextCursor      string                       `json:"nextCursor,omitempty"`
+	References      []ReferenceCLI               `json:"references"`
+	ByModule        []ModuleReferencesCLI        `json:"byModule,omitempty"`
+	DynamicDispatch *query.DynamicDispatchInfo   `json:"dynamicDispatch,omitempty"`
+	External        *query.ExternalReferenceInfo `json:"external,omitempty"`
+	Provenance      *ProvenanceCLI               `json:"provenance,omitempty"`
 }
 
 // ReferenceCLI represents a single reference to a symbol
@@ -119,6 +139,9 @@ type ReferenceCLI struct {
 	Context    string       `json:"context,omitempty"`
 	FromSymbol string       `json:"fromSymbol,omitempty"`
 	IsTest     bool         `json:"isTest"`
+	Dispatch   string       `json:"dispatch,omitempty"`
+	Confidence float64      `json:"confidence,omitempty"`
+	Via        string       `json:"via,omitempty"`
 }
 
 // ModuleReferencesCLI groups references by module
@@ -134,9 +157,12 @@ func convertRefsResponse(symbolID string, resp *query.FindReferencesResponse) *R
 
 	for _, r := range resp.References {
 		ref := ReferenceCLI{
-			Kind:    r.Kind,
-			Context: r.Context,
-			IsTest:  r.IsTest,
+			Kind:       r.Kind,
+			Context:    r.Context,
+			IsTest:     r.IsTest,
+			Dispatch:   r.Dispatch,
+			Confidence: r.Confidence,
+			Via:        r.Via,
 		}
 
 		if r.Location != nil {
@@ -170,8 +196,11 @@ func convertRefsResponse(symbolID string, resp *query.FindReferencesResponse) *R
 	result := &ReferencesResponseCLI{
 		SymbolID:        symbolID,
 		TotalReferences: resp.TotalCount,
+		NextCursor:      resp.NextCursor,
 		References:      refs,
 		ByModule:        byModule,
+		DynamicDispatch: resp.DynamicDispatch,
+		External:        resp.External,
 	}
 
 	if resp.Provenance != nil {
@@ -12,10 +12,11 @@ import (
 )
 
 var (
-	searchScope  string
-	searchKinds  string
-	searchLimit  int
-	searchFormat string
+	searchScope          string
+	searchKinds          string
+	searchLimit          int
+	searchFormat         string
+	searchIncludePrivate bool
 )
 
 var searchCmd = &cobra.Command{
@@ -31,7 +32,8 @@ Examples:
   ckb search handleRequest
   ckb search handleRequest --scope=api-module
   ckb search handleRequest --kinds=function,method
-  ckb search handleRequest --limit=10`,
+  ckb search handleRequest --limit=10
+  ckb search handleRequest --include-private=false`,
 	Args: cobra.ExactArgs(1),
 	Run:  runSearch,
 }
@@ -40,6 +42,7 @@ func init() {
 	searchCmd.Flags().StringVar(&searchScope, "scope", "", "Limit search to module ID")
 	searchCmd.Flags().StringVar(&searchKinds, "kinds", "", "Filter by kinds (comma-separated: class,function,method,etc)")
 	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Maximum number of results")
+	searchCmd.Flags().BoolVar(&searchIncludePrivate, "include-private", true, "Include private and internal symbols")
 	searchCmd.Flags().StringVar(&searchFormat, "format", "json", "Output format (json, human)")
 	rootCmd.AddCommand(searchCmd)
 }
@@ -65,6 +68,8 @@ func runSearch(cmd *cobra.Command, args []string) {
 		Scope: searchScope,
 		Kinds: kindsFilter,
 		Limit: searchLimit,
+
+		IncludePrivate: searchIncludePrivate,
 	}
 	response, err := engine.SearchSymbols(ctx, opts)
 	if err != nil {
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"ckb/internal/query"
+)
+
+var (
+	selftestDeterminism bool
+	selftestTools       string
+	selftestFormat      string
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run CKB self-checks against the current repository",
+	Long: `Run self-checks that verify CKB's output guarantees on the current repository.
+
+--determinism runs a representative set of tools twice and asserts that the
+responses are byte-identical (ignoring time-varying provenance fields). The
+first tool and field that differs is reported, and the command exits non-zero.
+
+Examples:
+  ckb selftest --determinism
+  ckb selftest --determinism --tools=getArchitecture,listKeyConcepts
+  ckb selftest --determinism --format=human`,
+	Run: runSelftest,
+}
+
+func init() {
+	selftestCmd.Flags().BoolVar(&selftestDeterminism, "determinism", false, "Verify that repeated tool runs produce identical output")
+	selftestCmd.Flags().StringVar(&selftestTools, "tools", "", fmt.Sprintf("Comma-separated tools to check (available: %s)", strings.Join(query.DeterminismToolNames(), ", ")))
+	selftestCmd.Flags().StringVar(&selftestFormat, "format", "json", "Output format (json, human)")
+	rootCmd.AddCommand(selftestCmd)
+}
+
+func runSelftest(cmd *cobra.Command, args []string) {
+	if !selftestDeterminism {
+		fmt.Fprintln(os.Stderr, "Error: no self-test selected (use --determinism)")
+		os.Exit(1)
+	}
+
+	start := time.Now()
+	logger := newLogger(selftestFormat)
+
+	repoRoot := mustGetRepoRoot()
+	engine := mustGetEngine(repoRoot, logger)
+	ctx := newContext()
+
+	var tools []string
+	for _, t := range strings.Split(selftestTools, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tools = append(tools, t)
+		}
+	}
+
+	response, err := engine.VerifyDeterminism(ctx, query.VerifyDeterminismOptions{Tools: tools})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running determinism self-test: %v\n", err)
+		os.Exit(1)
+	}
+
+	cliResponse := convertSelftestResponse(response)
+
+	output, err := FormatResponse(cliResponse, OutputFormat(selftestFormat))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(output)
+
+	logger.Debug("Determinism self-test completed", map[string]interface{}{
+		"tools":    len(response.Results),
+		"passed":   response.Passed,
+		"duration": time.Since(start).Milliseconds(),
+	})
+
+	if !response.Passed {
+		os.Exit(1)
+	}
+}
+
+// SelftestResponseCLI contains self-test results for CLI output
+type SelftestResponseCLI struct {
+	Passed    bool                    `json:"passed"`
+	Results   []SelftestToolResultCLI `json:"results"`
+	FirstDiff *SelftestToolResultCLI  `json:"firstDiff,omitempty"`
+}
+
+// SelftestToolResultCLI represents the determinism outcome for one tool
+type SelftestToolResultCLI struct {
+	Tool           string `json:"tool"`
+	Deterministic  bool   `json:"deterministic"`
+	FirstDiffField string `json:"firstDiffField,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+func convertSelftestResponse(resp *query.VerifyDeterminismResponse) *SelftestResponseCLI {
+	results := make([]SelftestToolResultCLI, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, SelftestToolResultCLI{
+			Tool:           r.Tool,
+			Deterministic:  r.Deterministic,
+			FirstDiffField: r.FirstDiffField,
+			Error:          r.Error,
+		})
+	}
+
+	cli := &SelftestResponseCLI{
+		Passed:  resp.Passed,
+		Results: results,
+	}
+	if resp.FirstDiff != nil {
+		cli.FirstDiff = &SelftestToolResultCLI{
+			Tool:           resp.FirstDiff.Tool,
+			Deterministic:  resp.FirstDiff.Deterministic,
+			FirstDiffField: resp.FirstDiff.FirstDiffField,
+			Error:          resp.FirstDiff.Error,
+		}
+	}
+	return cli
+}
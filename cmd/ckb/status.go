@@ -25,7 +25,7 @@ var statusCmd = &cobra.Command{
 }
 
 func init() {
-	statusCmd.Flags().StringVar(&statusFormat, "format", "human", "Output format (json, human)")
+	statusCmd.Flags().StringVar(&statusFormat, "format", "human", "Output format (json, human, prometheus)")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -44,6 +44,11 @@ func runStatus(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if statusFormat == query.StatusFormatPrometheus {
+		fmt.Print(response.Prometheus())
+		return
+	}
+
 	// Convert to CLI response format
 	cliResponse := convertStatusResponse(response)
 
@@ -13,6 +13,8 @@ import (
 var (
 	symbolRepoStateMode string
 	symbolFormat        string
+	symbolSource        bool
+	symbolContextLines  int
 )
 
 var symbolCmd = &cobra.Command{
@@ -30,6 +32,8 @@ RepoStateMode:
 func init() {
 	symbolCmd.Flags().StringVar(&symbolRepoStateMode, "repo-state-mode", "head", "Repo state mode (head, full)")
 	symbolCmd.Flags().StringVar(&symbolFormat, "format", "json", "Output format (json, human)")
+	symbolCmd.Flags().BoolVar(&symbolSource, "source", false, "Include the source around the definition")
+	symbolCmd.Flags().IntVar(&symbolContextLines, "context-lines", 5, "Lines of context on each side of the definition (max 50)")
 	rootCmd.AddCommand(symbolCmd)
 }
 
@@ -46,6 +50,8 @@ func runSymbol(cmd *cobra.Command, args []string) {
 	opts := query.GetSymbolOptions{
 		SymbolId:      symbolID,
 		RepoStateMode: symbolRepoStateMode,
+		IncludeSource: symbolSource,
+		ContextLines:  symbolContextLines,
 	}
 	response, err := engine.GetSymbol(ctx, opts)
 	if err != nil {
@@ -74,10 +80,12 @@ func runSymbol(cmd *cobra.Command, args []string) {
 
 // SymbolResponseCLI contains detailed symbol information for CLI output
 type SymbolResponseCLI struct {
-	Symbol     SymbolInfoCLI  `json:"symbol"`
-	Location   *LocationCLI   `json:"location,omitempty"`
-	Module     *ModuleInfoCLI `json:"module,omitempty"`
-	Provenance *ProvenanceCLI `json:"provenance,omitempty"`
+	Symbol              SymbolInfoCLI        `json:"symbol"`
+	Location            *LocationCLI         `json:"location,omitempty"`
+	AdditionalLocations []LocationCLI        `json:"additionalLocations,omitempty"`
+	Module              *ModuleInfoCLI       `json:"module,omitempty"`
+	SourceSnippet       *query.SourceContext `json:"sourceSnippet,omitempty"`
+	Provenance          *ProvenanceCLI       `json:"provenance,omitempty"`
 }
 
 // SymbolInfoCLI contains symbol details
@@ -153,6 +161,17 @@ func convertSymbolResponse(resp *query.GetSymbolResponse) *SymbolResponseCLI {
 			}
 		}
 
+		for _, loc := range resp.Symbol.AdditionalLocations {
+			result.AdditionalLocations = append(result.AdditionalLocations, LocationCLI{
+				FileID:      loc.FileId,
+				Path:        loc.FileId,
+				StartLine:   loc.StartLine,
+				StartColumn: loc.StartColumn,
+				EndLine:     loc.EndLine,
+				EndColumn:   loc.EndColumn,
+			})
+		}
+
 		if resp.Symbol.ModuleId != "" {
 			result.Module = &ModuleInfoCLI{
 				ModuleID: resp.Symbol.ModuleId,
@@ -160,6 +179,8 @@ func convertSymbolResponse(resp *query.GetSymbolResponse) *SymbolResponseCLI {
 		}
 	}
 
+	result.SourceSnippet = resp.SourceSnippet
+
 	if resp.Provenance != nil {
 		result.Provenance = &ProvenanceCLI{
 			RepoStateId:     resp.Provenance.RepoStateId,
@@ -259,8 +259,8 @@ func runTelemetryUsage(cmd *cobra.Command, args []string) {
 	// Compute period filter
 	periodFilter := computePeriodFilterCLI(usagePeriod)
 
-	// Get usage data
-	usages, err := storage.GetObservedUsage(symbolID, periodFilter)
+	// Sum the daily rollups, which outlive raw usage rows
+	rollup, err := storage.GetUsageRollup(symbolID, periodFilter)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting usage: %v\n", err)
 		os.Exit(1)
@@ -270,22 +270,14 @@ func runTelemetryUsage(cmd *cobra.Command, args []string) {
 		SymbolID: symbolID,
 	}
 
-	if len(usages) > 0 {
-		var totalCalls int64
-		var firstObserved, lastObserved time.Time
+	if len(rollup.Days) > 0 {
+		firstObserved, _ := time.Parse("2006-01-02", rollup.Days[len(rollup.Days)-1].Day)
+		lastObserved := rollup.LastIngestedAt
 
-		for i, u := range usages {
-			totalCalls += u.CallCount
-			if i == 0 {
-				lastObserved = u.IngestedAt
-			}
-			firstObserved = u.IngestedAt
-		}
-
-		response.TotalCalls = totalCalls
+		response.TotalCalls = rollup.TotalCalls
 		response.FirstObserved = &firstObserved
 		response.LastObserved = &lastObserved
-		response.MatchQuality = string(usages[0].MatchQuality)
+		response.MatchQuality = string(rollup.MatchQuality)
 	}
 
 	// Get callers if requested
@@ -11,9 +11,10 @@ import (
 )
 
 var (
-	traceFormat   string
-	traceMaxPaths int
-	traceMaxDepth int
+	traceFormat            string
+	traceMaxPaths          int
+	traceMaxDepth          int
+	traceMaxPathsPerSource int
 )
 
 var traceCmd = &cobra.Command{
@@ -27,6 +28,7 @@ Useful for understanding how code is actually used.
 Examples:
   ckb trace 'scip-go gomod myproject myproject/pkg/MyFunction'
   ckb trace --max-paths=20 --max-depth=3 'symbol-id'
+  ckb trace --paths-per-entrypoint=3 'symbol-id'
   ckb trace --format=human 'symbol-id'`,
 	Args: cobra.ExactArgs(1),
 	Run:  runTrace,
@@ -35,7 +37,8 @@ Examples:
 func init() {
 	traceCmd.Flags().StringVar(&traceFormat, "format", "json", "Output format (json, human)")
 	traceCmd.Flags().IntVar(&traceMaxPaths, "max-paths", 10, "Maximum paths to return")
-	traceCmd.Flags().IntVar(&traceMaxDepth, "max-depth", 5, "Maximum path depth (1-5)")
+	traceCmd.Flags().IntVar(&traceMaxDepth, "max-depth", 0, "Maximum path depth (0 = configured default, see traversal.traceUsage)")
+	traceCmd.Flags().IntVar(&traceMaxPathsPerSource, "paths-per-entrypoint", 1, "Distinct paths to collect from each entrypoint, shortest first (max 5)")
 	rootCmd.AddCommand(traceCmd)
 }
 
@@ -52,6 +55,8 @@ func runTrace(cmd *cobra.Command, args []string) {
 		SymbolId: symbolId,
 		MaxPaths: traceMaxPaths,
 		MaxDepth: traceMaxDepth,
+
+		MaxPathsPerSource: traceMaxPathsPerSource,
 	}
 	response, err := engine.TraceUsage(ctx, opts)
 	if err != nil {
@@ -99,6 +104,7 @@ type PathNodeCLI struct {
 	Kind     string       `json:"kind,omitempty"`
 	Location *LocationCLI `json:"location,omitempty"`
 	Role     string       `json:"role"`
+	Resolved bool         `json:"resolved"`
 }
 
 func convertTraceResponse(resp *query.TraceUsageResponse) *TraceResponseCLI {
@@ -111,6 +117,7 @@ func convertTraceResponse(resp *query.TraceUsageResponse) *TraceResponseCLI {
 				Name:     n.Name,
 				Kind:     n.Kind,
 				Role:     n.Role,
+				Resolved: n.Resolved,
 			}
 			if n.Location != nil {
 				node.Location = &LocationCLI{
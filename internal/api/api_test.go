@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"ckb/internal/config"
@@ -40,8 +41,8 @@ func newTestServer(t *testing.T) *Server {
 		Output: io.Discard,
 	})
 
-	// Create in-memory database
-	db, err := storage.Open(":memory:", logger)
+	// Create a database in a temp directory
+	db, err := storage.Open(t.TempDir(), logger)
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
@@ -246,6 +247,34 @@ func TestStatusEndpoint(t *testing.T) {
 	}
 }
 
+func TestStatusEndpointPrometheus(t *testing.T) {
+	server := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/status?format=prometheus", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected text/plain content type, got %q", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"# TYPE ckb_status_healthy gauge", `ckb_status_backend_available{backend="scip"}`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Response should contain %q:\n%s", want, body)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/status?format=xml", nil)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for unknown format, got %d", w.Code)
+	}
+}
+
 func TestDoctorEndpoint(t *testing.T) {
 	server := newTestServer(t)
 
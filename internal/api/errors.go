@@ -63,7 +63,7 @@ func MapCkbErrorToStatus(code errors.ErrorCode) int {
 		return http.StatusNotFound // 404
 	case errors.SymbolDeleted:
 		return http.StatusGone // 410
-	case errors.ScopeInvalid:
+	case errors.ScopeInvalid, errors.InvalidParameters:
 		return http.StatusBadRequest // 400
 	case errors.AliasCycle:
 		return http.StatusUnprocessableEntity // 422
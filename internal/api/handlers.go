@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"ckb/internal/impact"
 	"ckb/internal/query"
 )
 
@@ -37,6 +38,8 @@ type CacheInfo struct {
 	ViewsCached   int     `json:"viewsCached"`
 	HitRate       float64 `json:"hitRate"`
 	SizeBytes     int64   `json:"sizeBytes"`
+
+	CallGraph *query.CallGraphCacheStatus `json:"callGraph,omitempty"`
 }
 
 // DoctorAPIResponse represents the doctor diagnostic response
@@ -57,15 +60,16 @@ type DiagnosticCheck struct {
 
 // SymbolResponse represents a symbol lookup response
 type SymbolResponse struct {
-	ID            string          `json:"id"`
-	Name          string          `json:"name"`
-	Kind          string          `json:"kind"`
-	Location      *LocationInfo   `json:"location,omitempty"`
-	Module        string          `json:"module,omitempty"`
-	Signature     string          `json:"signature,omitempty"`
-	Visibility    string          `json:"visibility,omitempty"`
-	Documentation string          `json:"documentation,omitempty"`
-	Provenance    *ProvenanceInfo `json:"provenance,omitempty"`
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	Kind          string               `json:"kind"`
+	Location      *LocationInfo        `json:"location,omitempty"`
+	Module        string               `json:"module,omitempty"`
+	Signature     string               `json:"signature,omitempty"`
+	Visibility    string               `json:"visibility,omitempty"`
+	Documentation string               `json:"documentation,omitempty"`
+	SourceSnippet *query.SourceContext `json:"sourceSnippet,omitempty"`
+	Provenance    *ProvenanceInfo      `json:"provenance,omitempty"`
 }
 
 // LocationInfo represents location information
@@ -83,6 +87,8 @@ type ProvenanceInfo struct {
 	RepoStateID     string `json:"repoStateId"`
 	RepoStateDirty  bool   `json:"repoStateDirty"`
 	QueryDurationMs int64  `json:"queryDurationMs"`
+
+	Extra map[string]interface{} `json:"extra,omitempty"`
 }
 
 // SearchResponse represents a symbol search response
@@ -111,16 +117,23 @@ type ReferencesResponse struct {
 	SymbolID   string            `json:"symbolId"`
 	References []ReferenceResult `json:"references"`
 	Total      int               `json:"total"`
+	NextCursor string            `json:"nextCursor,omitempty"`
 	Timestamp  time.Time         `json:"timestamp"`
 	Provenance *ProvenanceInfo   `json:"provenance,omitempty"`
+
+	DynamicDispatch *query.DynamicDispatchInfo   `json:"dynamicDispatch,omitempty"`
+	External        *query.ExternalReferenceInfo `json:"external,omitempty"`
 }
 
 // ReferenceResult represents a single reference result
 type ReferenceResult struct {
-	Location *LocationInfo `json:"location"`
-	Kind     string        `json:"kind"`
-	Context  string        `json:"context,omitempty"`
-	IsTest   bool          `json:"isTest,omitempty"`
+	Location   *LocationInfo `json:"location"`
+	Kind       string        `json:"kind"`
+	Context    string        `json:"context,omitempty"`
+	IsTest     bool          `json:"isTest,omitempty"`
+	Dispatch   string        `json:"dispatch,omitempty"`
+	Confidence float64       `json:"confidence,omitempty"`
+	Via        string        `json:"via,omitempty"`
 }
 
 // ArchitectureResponse represents an architecture overview response
@@ -129,6 +142,9 @@ type ArchitectureResponse struct {
 	Modules      []ModuleInfo     `json:"modules"`
 	Dependencies []DependencyInfo `json:"dependencies"`
 	Entrypoints  []EntrypointInfo `json:"entrypoints"`
+	Cycles       [][]string       `json:"cycles,omitempty"`
+	Mermaid      string           `json:"mermaid,omitempty"`
+	Collapsed    int              `json:"collapsedModules,omitempty"`
 	Provenance   *ProvenanceInfo  `json:"provenance,omitempty"`
 }
 
@@ -142,14 +158,16 @@ type ModuleInfo struct {
 	FileCount     int    `json:"fileCount"`
 	IncomingEdges int    `json:"incomingEdges"`
 	OutgoingEdges int    `json:"outgoingEdges"`
+	Collapsed     int    `json:"collapsedCount,omitempty"`
 }
 
 // DependencyInfo represents a dependency relationship
 type DependencyInfo struct {
-	From     string `json:"from"`
-	To       string `json:"to"`
-	Kind     string `json:"kind"`
-	Strength int    `json:"strength"`
+	From          string         `json:"from"`
+	To            string         `json:"to"`
+	Kind          string         `json:"kind"`
+	Strength      int            `json:"strength"`
+	KindStrengths map[string]int `json:"kindStrengths,omitempty"`
 }
 
 // EntrypointInfo represents an entry point
@@ -162,13 +180,16 @@ type EntrypointInfo struct {
 
 // ImpactResponse represents an impact analysis response
 type ImpactResponse struct {
-	SymbolID         string          `json:"symbolId"`
-	Timestamp        time.Time       `json:"timestamp"`
-	RiskScore        *RiskScoreInfo  `json:"riskScore,omitempty"`
-	DirectImpact     []ImpactItem    `json:"directImpact"`
-	TransitiveImpact []ImpactItem    `json:"transitiveImpact,omitempty"`
-	ModulesAffected  []ModuleImpact  `json:"modulesAffected"`
-	Provenance       *ProvenanceInfo `json:"provenance,omitempty"`
+	SymbolID         string                 `json:"symbolId"`
+	Timestamp        time.Time              `json:"timestamp"`
+	RiskScore        *RiskScoreInfo         `json:"riskScore,omitempty"`
+	DirectImpact     []ImpactItem           `json:"directImpact"`
+	TransitiveImpact []ImpactItem           `json:"transitiveImpact,omitempty"`
+	ModulesAffected  []ModuleImpact         `json:"modulesAffected"`
+	ChangeKind       string                 `json:"changeKind,omitempty"`
+	NewName          string                 `json:"newName,omitempty"`
+	RiskThresholds   *impact.RiskThresholds `json:"riskThresholds,omitempty"`
+	Provenance       *ProvenanceInfo        `json:"provenance,omitempty"`
 }
 
 // RiskScoreInfo represents risk assessment
@@ -176,6 +197,7 @@ type RiskScoreInfo struct {
 	Level       string  `json:"level"`
 	Score       float64 `json:"score"`
 	Explanation string  `json:"explanation"`
+	Narrative   string  `json:"narrative,omitempty"`
 }
 
 // ImpactItem represents an affected item
@@ -187,6 +209,8 @@ type ImpactItem struct {
 	ModuleID   string        `json:"moduleId"`
 	Location   *LocationInfo `json:"location,omitempty"`
 	Confidence float64       `json:"confidence"`
+	Breaking   bool          `json:"breaking,omitempty"`
+	UpdateKind string        `json:"updateKind,omitempty"`
 }
 
 // ModuleImpact represents impact on a module
@@ -218,6 +242,12 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	format := r.URL.Query().Get("format")
+	if format != "" && format != query.StatusFormatJSON && format != query.StatusFormatPrometheus {
+		BadRequest(w, fmt.Sprintf("invalid format %q: must be %q or %q", format, query.StatusFormatJSON, query.StatusFormatPrometheus))
+		return
+	}
+
 	ctx := r.Context()
 	statusResp, err := s.engine.GetStatus(ctx)
 	if err != nil {
@@ -225,6 +255,13 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if format == query.StatusFormatPrometheus {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(statusResp.Prometheus()))
+		return
+	}
+
 	backends := make([]BackendInfo, 0, len(statusResp.Backends))
 	for _, b := range statusResp.Backends {
 		backends = append(backends, BackendInfo{
@@ -243,6 +280,7 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 			ViewsCached:   statusResp.Cache.ViewsCached,
 			HitRate:       statusResp.Cache.HitRate,
 			SizeBytes:     statusResp.Cache.SizeBytes,
+			CallGraph:     statusResp.Cache.CallGraph,
 		}
 	}
 
@@ -340,6 +378,8 @@ func (s *Server) handleGetSymbol(w http.ResponseWriter, r *http.Request) {
 	opts := query.GetSymbolOptions{
 		SymbolId:      symbolID,
 		RepoStateMode: repoStateMode,
+		IncludeSource: QueryParamBool(r, "includeSource", false),
+		ContextLines:  QueryParamInt(r, "contextLines", 0),
 	}
 
 	symbolResp, err := s.engine.GetSymbol(ctx, opts)
@@ -360,6 +400,7 @@ func (s *Server) handleGetSymbol(w http.ResponseWriter, r *http.Request) {
 		Module:        symbolResp.Symbol.ModuleId,
 		Signature:     symbolResp.Symbol.Signature,
 		Documentation: symbolResp.Symbol.Documentation,
+		SourceSnippet: symbolResp.SourceSnippet,
 	}
 
 	if symbolResp.Symbol.Visibility != nil {
@@ -382,6 +423,7 @@ func (s *Server) handleGetSymbol(w http.ResponseWriter, r *http.Request) {
 			RepoStateID:     symbolResp.Provenance.RepoStateId,
 			RepoStateDirty:  symbolResp.Provenance.RepoStateDirty,
 			QueryDurationMs: symbolResp.Provenance.QueryDurationMs,
+			Extra:           symbolResp.Provenance.Extra,
 		}
 	}
 
@@ -419,10 +461,11 @@ func (s *Server) handleSearchSymbols(w http.ResponseWriter, r *http.Request) {
 	}
 
 	opts := query.SearchSymbolsOptions{
-		Query: queryStr,
-		Scope: scope,
-		Kinds: kinds,
-		Limit: limit,
+		Query:          queryStr,
+		Scope:          scope,
+		Kinds:          kinds,
+		Limit:          limit,
+		IncludePrivate: QueryParamBool(r, "includePrivate", true),
 	}
 
 	searchResp, err := s.engine.SearchSymbols(ctx, opts)
@@ -470,6 +513,7 @@ func (s *Server) handleSearchSymbols(w http.ResponseWriter, r *http.Request) {
 			RepoStateID:     searchResp.Provenance.RepoStateId,
 			RepoStateDirty:  searchResp.Provenance.RepoStateDirty,
 			QueryDurationMs: searchResp.Provenance.QueryDurationMs,
+			Extra:           searchResp.Provenance.Extra,
 		}
 	}
 
@@ -492,6 +536,9 @@ func (s *Server) handleFindReferences(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	scope := r.URL.Query().Get("scope")
 	includeTests := r.URL.Query().Get("includeTests") == "true"
+	dynamicDispatch := r.URL.Query().Get("dynamicDispatch") == "true"
+	externalOnly := r.URL.Query().Get("externalOnly") == "true"
+	cursor := r.URL.Query().Get("cursor")
 	limitStr := r.URL.Query().Get("limit")
 
 	limit := 100
@@ -506,6 +553,13 @@ func (s *Server) handleFindReferences(w http.ResponseWriter, r *http.Request) {
 		Scope:        scope,
 		IncludeTests: includeTests,
 		Limit:        limit,
+
+		IncludeDynamicDispatch: dynamicDispatch,
+		ExternalOnly:           externalOnly,
+		Cursor:                 cursor,
+	}
+	if kinds := r.URL.Query().Get("kinds"); kinds != "" {
+		opts.Kinds = strings.Split(kinds, ",")
 	}
 
 	refsResp, err := s.engine.FindReferences(ctx, opts)
@@ -517,9 +571,12 @@ func (s *Server) handleFindReferences(w http.ResponseWriter, r *http.Request) {
 	refs := make([]ReferenceResult, 0, len(refsResp.References))
 	for _, ref := range refsResp.References {
 		result := ReferenceResult{
-			Kind:    ref.Kind,
-			Context: ref.Context,
-			IsTest:  ref.IsTest,
+			Kind:       ref.Kind,
+			Context:    ref.Context,
+			IsTest:     ref.IsTest,
+			Dispatch:   ref.Dispatch,
+			Confidence: ref.Confidence,
+			Via:        ref.Via,
 		}
 
 		if ref.Location != nil {
@@ -540,7 +597,11 @@ func (s *Server) handleFindReferences(w http.ResponseWriter, r *http.Request) {
 		SymbolID:   symbolID,
 		References: refs,
 		Total:      refsResp.TotalCount,
+		NextCursor: refsResp.NextCursor,
 		Timestamp:  time.Now().UTC(),
+
+		DynamicDispatch: refsResp.DynamicDispatch,
+		External:        refsResp.External,
 	}
 
 	if refsResp.Provenance != nil {
@@ -548,6 +609,7 @@ func (s *Server) handleFindReferences(w http.ResponseWriter, r *http.Request) {
 			RepoStateID:     refsResp.Provenance.RepoStateId,
 			RepoStateDirty:  refsResp.Provenance.RepoStateDirty,
 			QueryDurationMs: refsResp.Provenance.QueryDurationMs,
+			Extra:           refsResp.Provenance.Extra,
 		}
 	}
 
@@ -577,6 +639,12 @@ func (s *Server) handleGetArchitecture(w http.ResponseWriter, r *http.Request) {
 		Depth:               depth,
 		IncludeExternalDeps: includeExternal,
 		Refresh:             refresh,
+		Format:              r.URL.Query().Get("format"),
+	}
+	if v := r.URL.Query().Get("minSymbols"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			opts.MinSymbols = n
+		}
 	}
 
 	archResp, err := s.engine.GetArchitecture(ctx, opts)
@@ -596,16 +664,18 @@ func (s *Server) handleGetArchitecture(w http.ResponseWriter, r *http.Request) {
 			FileCount:     m.FileCount,
 			IncomingEdges: m.IncomingEdges,
 			OutgoingEdges: m.OutgoingEdges,
+			Collapsed:     m.CollapsedCount,
 		})
 	}
 
 	deps := make([]DependencyInfo, 0, len(archResp.DependencyGraph))
 	for _, d := range archResp.DependencyGraph {
 		deps = append(deps, DependencyInfo{
-			From:     d.From,
-			To:       d.To,
-			Kind:     d.Kind,
-			Strength: d.Strength,
+			From:          d.From,
+			To:            d.To,
+			Kind:          d.Kind,
+			Strength:      d.Strength,
+			KindStrengths: d.KindStrengths,
 		})
 	}
 
@@ -624,6 +694,9 @@ func (s *Server) handleGetArchitecture(w http.ResponseWriter, r *http.Request) {
 		Modules:      modules,
 		Dependencies: deps,
 		Entrypoints:  entrypoints,
+		Cycles:       archResp.Cycles,
+		Mermaid:      archResp.Mermaid,
+		Collapsed:    archResp.CollapsedModules,
 	}
 
 	if archResp.Provenance != nil {
@@ -631,6 +704,7 @@ func (s *Server) handleGetArchitecture(w http.ResponseWriter, r *http.Request) {
 			RepoStateID:     archResp.Provenance.RepoStateId,
 			RepoStateDirty:  archResp.Provenance.RepoStateDirty,
 			QueryDurationMs: archResp.Provenance.QueryDurationMs,
+			Extra:           archResp.Provenance.Extra,
 		}
 	}
 
@@ -654,7 +728,7 @@ func (s *Server) handleAnalyzeImpact(w http.ResponseWriter, r *http.Request) {
 	depthStr := r.URL.Query().Get("depth")
 	includeTests := r.URL.Query().Get("includeTests") == "true"
 
-	depth := 2
+	depth := 0 // 0 = configured default (traversal.impact)
 	if depthStr != "" {
 		if d, err := strconv.Atoi(depthStr); err == nil && d > 0 {
 			depth = d
@@ -665,6 +739,8 @@ func (s *Server) handleAnalyzeImpact(w http.ResponseWriter, r *http.Request) {
 		SymbolId:     symbolID,
 		Depth:        depth,
 		IncludeTests: includeTests,
+		ChangeKind:   r.URL.Query().Get("changeKind"),
+		NewName:      r.URL.Query().Get("newName"),
 	}
 
 	impactResp, err := s.engine.AnalyzeImpact(ctx, opts)
@@ -682,6 +758,8 @@ func (s *Server) handleAnalyzeImpact(w http.ResponseWriter, r *http.Request) {
 			Distance:   item.Distance,
 			ModuleID:   item.ModuleId,
 			Confidence: item.Confidence,
+			Breaking:   item.Breaking,
+			UpdateKind: item.UpdateKind,
 		}
 		if item.Location != nil {
 			impactItem.Location = &LocationInfo{
@@ -703,6 +781,8 @@ func (s *Server) handleAnalyzeImpact(w http.ResponseWriter, r *http.Request) {
 			Distance:   item.Distance,
 			ModuleID:   item.ModuleId,
 			Confidence: item.Confidence,
+			Breaking:   item.Breaking,
+			UpdateKind: item.UpdateKind,
 		}
 		if item.Location != nil {
 			impactItem.Location = &LocationInfo{
@@ -730,6 +810,9 @@ func (s *Server) handleAnalyzeImpact(w http.ResponseWriter, r *http.Request) {
 		DirectImpact:     directImpact,
 		TransitiveImpact: transitiveImpact,
 		ModulesAffected:  modulesAffected,
+		ChangeKind:       impactResp.ChangeKind,
+		NewName:          impactResp.NewName,
+		RiskThresholds:   impactResp.RiskThresholds,
 	}
 
 	if impactResp.RiskScore != nil {
@@ -737,6 +820,7 @@ func (s *Server) handleAnalyzeImpact(w http.ResponseWriter, r *http.Request) {
 			Level:       impactResp.RiskScore.Level,
 			Score:       impactResp.RiskScore.Score,
 			Explanation: impactResp.RiskScore.Explanation,
+			Narrative:   impactResp.RiskScore.Narrative,
 		}
 	}
 
@@ -745,6 +829,7 @@ func (s *Server) handleAnalyzeImpact(w http.ResponseWriter, r *http.Request) {
 			RepoStateID:     impactResp.Provenance.RepoStateId,
 			RepoStateDirty:  impactResp.Provenance.RepoStateDirty,
 			QueryDurationMs: impactResp.Provenance.QueryDurationMs,
+			Extra:           impactResp.Provenance.Extra,
 		}
 	}
 
@@ -810,7 +895,7 @@ func (s *Server) handleCacheWarm(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Warm by loading hotspots
-	if _, err := s.engine.GetHotspots(ctx, query.GetHotspotsOptions{Limit: 20}); err == nil {
+	if _, err := s.engine.GetHotspots(ctx, query.GetHotspotsOptions{Limit: 20, ExcludeGenerated: true}); err == nil {
 		warmed++
 	}
 
@@ -302,6 +302,10 @@ func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
 type DiffSummaryRequest struct {
 	From string `json:"from"` // Git ref (tag, branch, commit)
 	To   string `json:"to"`   // Git ref (tag, branch, commit)
+
+	MergeBase bool `json:"mergeBase,omitempty"` // Diff from the merge base of from and to (from...to)
+
+	CheckErrorHandling bool `json:"checkErrorHandling,omitempty"` // Flag ignored errors in added Go lines
 }
 
 // handleDiffSummary handles POST /diff/summary
@@ -327,9 +331,11 @@ func (s *Server) handleDiffSummary(w http.ResponseWriter, r *http.Request) {
 
 	opts := query.SummarizeDiffOptions{
 		CommitRange: &query.CommitRangeSelector{
-			Base: req.From,
-			Head: req.To,
+			Base:      req.From,
+			Head:      req.To,
+			MergeBase: req.MergeBase,
 		},
+		CheckErrorHandling: req.CheckErrorHandling,
 	}
 
 	result, err := s.engine.SummarizeDiff(ctx, opts)
@@ -166,6 +166,9 @@ func (s *Server) handleGetHotspots(w http.ResponseWriter, r *http.Request) {
 	if scope := r.URL.Query().Get("scope"); scope != "" {
 		opts.Scope = scope
 	}
+	opts.Format = r.URL.Query().Get("format")
+	opts.IncludeAuthors = QueryParamBool(r, "includeAuthors", false)
+	opts.ExcludeGenerated = QueryParamBool(r, "excludeGenerated", true)
 
 	resp, err := s.engine.GetHotspots(ctx, opts)
 	if err != nil {
@@ -220,6 +223,9 @@ func (s *Server) handleDecisions(w http.ResponseWriter, r *http.Request) {
 		if status := r.URL.Query().Get("status"); status != "" {
 			queryOpts.Status = status
 		}
+		if search := r.URL.Query().Get("search"); search != "" {
+			queryOpts.Search = search
+		}
 
 		resp, err := s.engine.GetDecisions(queryOpts)
 		if err != nil {
@@ -276,7 +282,9 @@ func (s *Server) handleModules(w http.ResponseWriter, r *http.Request) {
 	switch action {
 	case "overview":
 		opts := query.ModuleOverviewOptions{
-			Path: moduleID, // moduleID is typically the path
+			Path:             moduleID, // moduleID is typically the path
+			ExcludeGenerated: QueryParamBool(r, "excludeGenerated", true),
+			RespectGitignore: QueryParamBool(r, "respectGitignore", true),
 		}
 		resp, err := s.engine.GetModuleOverview(ctx, opts)
 		if err != nil {
@@ -317,9 +325,11 @@ func (s *Server) handleGetCallGraph(w http.ResponseWriter, r *http.Request) {
 	}
 
 	opts := query.CallGraphOptions{
-		SymbolId:  symbolID,
-		Direction: r.URL.Query().Get("direction"),
-		Depth:     QueryParamInt(r, "depth", 2),
+		SymbolId:       symbolID,
+		Direction:      r.URL.Query().Get("direction"),
+		Depth:          QueryParamInt(r, "depth", 2),
+		IncludeContext: QueryParamBool(r, "includeContext", false),
+		OutputFormat:   r.URL.Query().Get("outputFormat"),
 	}
 
 	resp, err := s.engine.GetCallGraph(ctx, opts)
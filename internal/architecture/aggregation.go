@@ -2,6 +2,7 @@ package architecture
 
 import (
 	"bufio"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,9 +13,15 @@ import (
 
 // AggregateModules collects statistics for each module
 func (g *ArchitectureGenerator) AggregateModules(mods []*modules.Module) ([]ModuleSummary, error) {
-	summaries := make([]ModuleSummary, 0, len(mods))
+	return g.aggregateModules(context.Background(), mods, 1)
+}
+
+// aggregateModules is AggregateModules over a pool of workers. Summaries
+// keep the order of mods.
+func (g *ArchitectureGenerator) aggregateModules(ctx context.Context, mods []*modules.Module, workers int) ([]ModuleSummary, error) {
+	summaries := make([]ModuleSummary, len(mods))
 
-	for _, mod := range mods {
+	err := forEachModule(ctx, mods, workers, func(i int, mod *modules.Module) {
 		summary := ModuleSummary{
 			ModuleId:    mod.ID,
 			Name:        mod.Name,
@@ -45,7 +52,10 @@ func (g *ArchitectureGenerator) AggregateModules(mods []*modules.Module) ([]Modu
 		}
 		summary.LOC = loc
 
-		summaries = append(summaries, summary)
+		summaries[i] = summary
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return summaries, nil
@@ -1,10 +1,16 @@
 package architecture
 
 import (
+	"container/list"
 	"sync"
 	"time"
 )
 
+// DefaultCacheEntries is the architecture cache size. Views are large and
+// only the current repo state is normally asked for, so a few entries cover
+// the views with and without external dependencies across a state change.
+const DefaultCacheEntries = 8
+
 // CachedArchitecture represents a cached architecture response
 type CachedArchitecture struct {
 	Response    *ArchitectureResponse
@@ -13,38 +19,64 @@ type CachedArchitecture struct {
 }
 
 // ArchitectureCache provides in-memory caching for architecture views
-// Architecture views are cached with full repoStateId
+// Architecture views are cached with full repoStateId. The cache is an LRU:
+// once full, the least recently used view is evicted, so views for repo
+// states that are no longer current don't accumulate.
 type ArchitectureCache struct {
-	mu    sync.RWMutex
-	cache map[string]*CachedArchitecture
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	cache    map[string]*list.Element
 }
 
-// NewArchitectureCache creates a new architecture cache
-func NewArchitectureCache() *ArchitectureCache {
+// NewArchitectureCache creates a new architecture cache holding at most
+// capacity views (DefaultCacheEntries if capacity <= 0)
+func NewArchitectureCache(capacity int) *ArchitectureCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheEntries
+	}
 	return &ArchitectureCache{
-		cache: make(map[string]*CachedArchitecture),
+		capacity: capacity,
+		order:    list.New(),
+		cache:    make(map[string]*list.Element),
 	}
 }
 
 // Get retrieves a cached architecture response
 func (c *ArchitectureCache) Get(repoStateId string) (*CachedArchitecture, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	cached, found := c.cache[repoStateId]
-	return cached, found
+	el, found := c.cache[repoStateId]
+	if !found {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*CachedArchitecture), true
 }
 
-// Set stores an architecture response in the cache
+// Set stores an architecture response in the cache, evicting the least
+// recently used entry when full
 func (c *ArchitectureCache) Set(repoStateId string, response *ArchitectureResponse) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.cache[repoStateId] = &CachedArchitecture{
+	cached := &CachedArchitecture{
 		Response:    response,
 		RepoStateId: repoStateId,
 		ComputedAt:  time.Now(),
 	}
+	if el, found := c.cache[repoStateId]; found {
+		el.Value = cached
+		c.order.MoveToFront(el)
+		return
+	}
+	c.cache[repoStateId] = c.order.PushFront(cached)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.cache, oldest.Value.(*CachedArchitecture).RepoStateId)
+	}
 }
 
 // Invalidate removes a specific cached entry
@@ -52,7 +84,10 @@ func (c *ArchitectureCache) Invalidate(repoStateId string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	delete(c.cache, repoStateId)
+	if el, found := c.cache[repoStateId]; found {
+		c.order.Remove(el)
+		delete(c.cache, repoStateId)
+	}
 }
 
 // Clear removes all cached entries
@@ -60,13 +95,14 @@ func (c *ArchitectureCache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.cache = make(map[string]*CachedArchitecture)
+	c.order.Init()
+	c.cache = make(map[string]*list.Element)
 }
 
 // Size returns the number of cached entries
 func (c *ArchitectureCache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return len(c.cache)
+	return c.order.Len()
 }
@@ -0,0 +1,69 @@
+package architecture
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"ckb/internal/modules"
+)
+
+// maxDefaultConcurrency caps the default worker count. Module work is
+// mostly file I/O, which stops scaling well before the core count does on
+// large machines.
+const maxDefaultConcurrency = 8
+
+// concurrency returns the number of modules to process in parallel: the
+// per-call option, else backendLimits.architectureConcurrency, else the
+// CPU count capped at maxDefaultConcurrency.
+func (g *ArchitectureGenerator) concurrency(opts *GeneratorOptions) int {
+	if opts != nil && opts.Concurrency > 0 {
+		return opts.Concurrency
+	}
+	if g.config != nil && g.config.BackendLimits.ArchitectureConcurrency > 0 {
+		return g.config.BackendLimits.ArchitectureConcurrency
+	}
+	n := runtime.NumCPU()
+	if n > maxDefaultConcurrency {
+		n = maxDefaultConcurrency
+	}
+	return n
+}
+
+// forEachModule calls fn for every module on a pool of workers goroutines.
+// fn receives the module's index so callers can store results by position
+// and keep the output order independent of scheduling. Modules not yet
+// started when ctx is cancelled are skipped and ctx's error is returned.
+func forEachModule(ctx context.Context, mods []*modules.Module, workers int, fn func(i int, mod *modules.Module)) error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(mods) {
+		workers = len(mods)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i, mods[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range mods {
+		select {
+		case <-ctx.Done():
+			break feed
+		case next <- i:
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	return ctx.Err()
+}
@@ -0,0 +1,91 @@
+package architecture
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"ckb/internal/modules"
+)
+
+func testModules(n int) []*modules.Module {
+	mods := make([]*modules.Module, n)
+	for i := range mods {
+		mods[i] = &modules.Module{ID: fmt.Sprintf("mod-%02d", i)}
+	}
+	return mods
+}
+
+// TestForEachModule checks every module is visited once at its own index
+func TestForEachModule(t *testing.T) {
+	mods := testModules(25)
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			got := make([]string, len(mods))
+			var calls int32
+			err := forEachModule(context.Background(), mods, workers, func(i int, mod *modules.Module) {
+				atomic.AddInt32(&calls, 1)
+				got[i] = mod.ID
+			})
+			if err != nil {
+				t.Fatalf("forEachModule() error = %v", err)
+			}
+			if int(calls) != len(mods) {
+				t.Errorf("fn called %d times, want %d", calls, len(mods))
+			}
+			for i, mod := range mods {
+				if got[i] != mod.ID {
+					t.Errorf("index %d got %q, want %q", i, got[i], mod.ID)
+				}
+			}
+		})
+	}
+}
+
+// TestForEachModuleCancelled checks cancellation stops scheduling new work
+func TestForEachModuleCancelled(t *testing.T) {
+	mods := testModules(50)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int32
+	err := forEachModule(ctx, mods, 1, func(i int, mod *modules.Module) {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Errorf("forEachModule() error = %v, want context.Canceled", err)
+	}
+	if calls >= int32(len(mods)) {
+		t.Errorf("fn called for all %d modules after cancellation", calls)
+	}
+}
+
+// TestBuildDependencyGraphOrder checks edges come out sorted by endpoints
+func TestBuildDependencyGraphOrder(t *testing.T) {
+	mods := []*modules.Module{
+		{ID: "b", RootPath: "b", Language: modules.LanguageGo},
+		{ID: "a", RootPath: "a", Language: modules.LanguageGo},
+	}
+	importsByModule := map[string][]*modules.ImportEdge{
+		"b": {{To: "z.io/pkg"}, {To: "y.io/pkg"}},
+		"a": {{To: "z.io/pkg"}, {To: "z.io/pkg"}},
+	}
+
+	g := &ArchitectureGenerator{repoRoot: t.TempDir()}
+	edges, err := g.BuildDependencyGraph(mods, importsByModule, &GeneratorOptions{IncludeExternalDeps: true})
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph() error = %v", err)
+	}
+
+	var got []string
+	for _, edge := range edges {
+		got = append(got, fmt.Sprintf("%s->%s:%d", edge.From, edge.To, edge.Strength))
+	}
+	want := []string{"a->external:z.io:2", "b->external:y.io:1", "b->external:z.io:1"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+}
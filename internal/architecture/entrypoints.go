@@ -1,6 +1,7 @@
 package architecture
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -19,10 +20,22 @@ import (
 // - package.json "main" field
 // - pubspec.yaml entry
 func (g *ArchitectureGenerator) DetectEntrypoints(mods []*modules.Module) ([]Entrypoint, error) {
-	var entrypoints []Entrypoint
+	return g.detectEntrypoints(context.Background(), mods, 1)
+}
+
+// detectEntrypoints is DetectEntrypoints over a pool of workers. Entrypoints
+// are grouped by module in the order of mods.
+func (g *ArchitectureGenerator) detectEntrypoints(ctx context.Context, mods []*modules.Module, workers int) ([]Entrypoint, error) {
+	perModule := make([][]Entrypoint, len(mods))
+	err := forEachModule(ctx, mods, workers, func(i int, mod *modules.Module) {
+		perModule[i] = g.detectModuleEntrypoints(mod)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	for _, mod := range mods {
-		moduleEntrypoints := g.detectModuleEntrypoints(mod)
+	var entrypoints []Entrypoint
+	for _, moduleEntrypoints := range perModule {
 		entrypoints = append(entrypoints, moduleEntrypoints...)
 	}
 
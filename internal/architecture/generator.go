@@ -26,6 +26,7 @@ type GeneratorOptions struct {
 	IncludeExternalDeps bool // Include external dependencies in graph (default false)
 	Refresh             bool // Force refresh, bypass cache
 	MaxFilesScanned     int  // Override default max files limit
+	Concurrency         int  // Modules processed in parallel (0 = configured or CPU-based default)
 }
 
 // DefaultGeneratorOptions returns the default generator options
@@ -61,11 +62,22 @@ func NewArchitectureGenerator(
 		importScanner: importScanner,
 		logger:        logger,
 		limits:        limits,
-		cache:         NewArchitectureCache(),
+		cache:         NewArchitectureCache(DefaultCacheEntries),
 	}
 }
 
-// Generate generates the complete architecture view
+// WithCache makes the generator share a cache with other generators, so a
+// view computed once is reused across requests. It returns g.
+func (g *ArchitectureGenerator) WithCache(cache *ArchitectureCache) *ArchitectureGenerator {
+	if cache != nil {
+		g.cache = cache
+	}
+	return g
+}
+
+// Generate generates the complete architecture view. Views are cached by
+// repoStateId; a view including external dependencies is cached separately
+// from one without.
 func (g *ArchitectureGenerator) Generate(ctx context.Context, repoStateId string, opts *GeneratorOptions) (*ArchitectureResponse, error) {
 	startTime := time.Now()
 
@@ -74,9 +86,14 @@ func (g *ArchitectureGenerator) Generate(ctx context.Context, repoStateId string
 		opts = DefaultGeneratorOptions()
 	}
 
+	cacheKey := repoStateId
+	if opts.IncludeExternalDeps {
+		cacheKey += "+external"
+	}
+
 	// Check cache first unless refresh is requested
 	if !opts.Refresh {
-		if cached, found := g.cache.Get(repoStateId); found {
+		if cached, found := g.cache.Get(cacheKey); found {
 			g.logger.Debug("Using cached architecture", map[string]interface{}{
 				"repoStateId": repoStateId,
 				"age":         time.Since(cached.ComputedAt).Seconds(),
@@ -90,6 +107,7 @@ func (g *ArchitectureGenerator) Generate(ctx context.Context, repoStateId string
 		"includeExternalDeps": opts.IncludeExternalDeps,
 		"depth":               opts.Depth,
 	})
+	workers := g.concurrency(opts)
 
 	// Step 1: Detect modules
 	detectionResult, err := modules.DetectModules(
@@ -118,13 +136,13 @@ func (g *ArchitectureGenerator) Generate(ctx context.Context, repoStateId string
 	}
 
 	// Step 2: Aggregate module statistics
-	moduleSummaries, err := g.AggregateModules(detectionResult.Modules)
+	moduleSummaries, err := g.aggregateModules(ctx, detectionResult.Modules, workers)
 	if err != nil {
 		return nil, fmt.Errorf("module aggregation failed: %w", err)
 	}
 
 	// Step 3: Scan imports and build dependency graph
-	importsByModule, err := g.scanImportsForModules(ctx, detectionResult.Modules)
+	importsByModule, err := g.scanImportsForModules(ctx, detectionResult.Modules, workers)
 	if err != nil {
 		return nil, fmt.Errorf("import scanning failed: %w", err)
 	}
@@ -135,7 +153,7 @@ func (g *ArchitectureGenerator) Generate(ctx context.Context, repoStateId string
 	}
 
 	// Step 4: Detect entrypoints
-	entrypoints, err := g.DetectEntrypoints(detectionResult.Modules)
+	entrypoints, err := g.detectEntrypoints(ctx, detectionResult.Modules, workers)
 	if err != nil {
 		return nil, fmt.Errorf("entrypoint detection failed: %w", err)
 	}
@@ -148,7 +166,7 @@ func (g *ArchitectureGenerator) Generate(ctx context.Context, repoStateId string
 	}
 
 	// Cache the response
-	g.cache.Set(repoStateId, response)
+	g.cache.Set(cacheKey, response)
 
 	duration := time.Since(startTime)
 	g.logger.Info("Architecture generation completed", map[string]interface{}{
@@ -156,25 +174,20 @@ func (g *ArchitectureGenerator) Generate(ctx context.Context, repoStateId string
 		"modules":      len(moduleSummaries),
 		"dependencies": len(dependencyGraph),
 		"entrypoints":  len(entrypoints),
+		"workers":      workers,
 	})
 
 	return response, nil
 }
 
-// scanImportsForModules scans all imports for all modules
-func (g *ArchitectureGenerator) scanImportsForModules(ctx context.Context, mods []*modules.Module) (map[string][]*modules.ImportEdge, error) {
-	result := make(map[string][]*modules.ImportEdge)
-
-	for _, mod := range mods {
-		// Check context for cancellation
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
+// scanImportsForModules scans the imports of all modules, workers at a time
+func (g *ArchitectureGenerator) scanImportsForModules(ctx context.Context, mods []*modules.Module, workers int) (map[string][]*modules.ImportEdge, error) {
+	scanned := make([][]*modules.ImportEdge, len(mods))
+	ok := make([]bool, len(mods))
 
-		// Scan imports for this module
-		imports, err := g.importScanner.ScanDirectory(
+	err := forEachModule(ctx, mods, workers, func(i int, mod *modules.Module) {
+		imports, err := g.importScanner.ScanDirectoryContext(
+			ctx,
 			g.repoRoot+"/"+mod.RootPath,
 			g.repoRoot,
 			g.config.Modules.Ignore,
@@ -184,12 +197,21 @@ func (g *ArchitectureGenerator) scanImportsForModules(ctx context.Context, mods
 				"moduleId": mod.ID,
 				"error":    err.Error(),
 			})
-			continue
+			return
 		}
-
-		result[mod.ID] = imports
+		scanned[i] = imports
+		ok[i] = true
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	result := make(map[string][]*modules.ImportEdge, len(mods))
+	for i, mod := range mods {
+		if ok[i] {
+			result[mod.ID] = scanned[i]
+		}
+	}
 	return result, nil
 }
 
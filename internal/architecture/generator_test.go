@@ -2,6 +2,7 @@ package architecture
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -104,7 +105,7 @@ func main() {
 
 // TestArchitectureCache tests the caching functionality
 func TestArchitectureCache(t *testing.T) {
-	cache := NewArchitectureCache()
+	cache := NewArchitectureCache(DefaultCacheEntries)
 
 	// Create a test response
 	response := &ArchitectureResponse{
@@ -158,6 +159,43 @@ func TestArchitectureCache(t *testing.T) {
 	}
 }
 
+// TestArchitectureCacheEvictsLeastRecentlyUsed tests that the cache stays
+// bounded as the repo state moves on
+func TestArchitectureCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewArchitectureCache(2)
+	response := &ArchitectureResponse{}
+
+	cache.Set("state-1", response)
+	cache.Set("state-2", response)
+	cache.Get("state-1") // state-2 is now least recently used
+	cache.Set("state-3", response)
+
+	if cache.Size() != 2 {
+		t.Errorf("Expected cache size 2, got %d", cache.Size())
+	}
+	if _, found := cache.Get("state-2"); found {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	for _, id := range []string{"state-1", "state-3"} {
+		if _, found := cache.Get(id); !found {
+			t.Errorf("Expected %s to stay cached", id)
+		}
+	}
+
+	// Re-setting an entry replaces it without growing the cache
+	cache.Set("state-3", response)
+	if cache.Size() != 2 {
+		t.Errorf("Expected cache size 2 after update, got %d", cache.Size())
+	}
+
+	for i := 0; i < 100; i++ {
+		cache.Set(fmt.Sprintf("state-%d", i+10), response)
+	}
+	if cache.Size() != 2 {
+		t.Errorf("Expected cache to stay at capacity, got %d", cache.Size())
+	}
+}
+
 // TestFilterExternalDeps tests external dependency filtering
 func TestFilterExternalDeps(t *testing.T) {
 	edges := []DependencyEdge{
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"ckb/internal/modules"
@@ -90,11 +91,17 @@ func (g *ArchitectureGenerator) BuildDependencyGraph(
 		}
 	}
 
-	// Convert map to slice
+	// Convert map to slice, sorted so the graph doesn't depend on map order
 	edges := make([]DependencyEdge, 0, len(edgeMap))
 	for _, edge := range edgeMap {
 		edges = append(edges, *edge)
 	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
 
 	return edges, nil
 }
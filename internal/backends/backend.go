@@ -16,6 +16,8 @@ const (
 	BackendLSP BackendID = "lsp"
 	// BackendGit represents the Git backend
 	BackendGit BackendID = "git"
+	// BackendTreesitter represents the on-demand tree-sitter parser backend
+	BackendTreesitter BackendID = "treesitter"
 )
 
 // Backend is the base interface that all backends must implement
@@ -31,7 +33,7 @@ type Backend interface {
 	Capabilities() []string
 
 	// Priority returns the priority of this backend (lower = higher priority)
-	// SCIP=1, Glean=2, LSP=3, Git=4
+	// SCIP=1, Glean=2, LSP=3, Git=4, Tree-sitter=5
 	Priority() int
 }
 
@@ -93,6 +95,9 @@ type SymbolResult struct {
 	// Location is the primary definition location
 	Location Location
 
+	// AdditionalLocations are further definition sites (partial classes, split types)
+	AdditionalLocations []Location
+
 	// Signature is the normalized signature
 	SignatureNormalized string
 
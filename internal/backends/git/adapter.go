@@ -122,7 +122,18 @@ func (g *GitAdapter) Capabilities() []string {
 }
 
 // executeGitCommand runs a git command with timeout and returns the output
+// with surrounding whitespace trimmed
 func (g *GitAdapter) executeGitCommand(args ...string) (string, error) {
+	output, err := g.executeGitCommandRaw(args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// executeGitCommandRaw runs a git command with timeout and returns the
+// output unmodified, for callers that need exact file content
+func (g *GitAdapter) executeGitCommandRaw(args ...string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), g.queryTimeout)
 	defer cancel()
 
@@ -170,7 +181,7 @@ func (g *GitAdapter) executeGitCommand(args ...string) (string, error) {
 		)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return string(output), nil
 }
 
 // executeGitCommandLines runs a git command and returns output as lines
@@ -18,6 +18,147 @@ type ChurnMetrics struct {
 	LastModified   string  `json:"lastModified"`   // ISO 8601 timestamp
 	AverageChanges float64 `json:"averageChanges"` // Lines changed per commit
 	HotspotScore   float64 `json:"hotspotScore"`   // Composite churn score
+	ChurnPattern   string  `json:"churnPattern,omitempty"`
+
+	// ChangeTimes holds the ISO 8601 author time of each commit counted in
+	// ChangeCount, newest first. Only GetHotspots fills it.
+	ChangeTimes []string `json:"changeTimes,omitempty"`
+}
+
+// Churn patterns describe the shape of a file's change sequence rather than
+// its size.
+const (
+	ChurnPatternOscillating = "oscillating" // Deleted and re-added, or content repeatedly added and removed
+	ChurnPatternGrowing     = "growing"
+	ChurnPatternShrinking   = "shrinking"
+	ChurnPatternSteady      = "steady"
+)
+
+// A commit counts as a swing when it changes at least this many lines and
+// nearly all of them in one direction.
+const (
+	minSwingLines    = 10
+	minSwingFraction = 0.8
+)
+
+// Net growth or shrinkage of at least this fraction of all changed lines
+// makes a file growing or shrinking rather than steady.
+const directionalChurnFraction = 0.25
+
+// churnEvent is one commit's change to a file.
+type churnEvent struct {
+	added   int
+	deleted int
+	created bool
+	removed bool
+}
+
+// classifyChurnPattern classifies a file's changes, given oldest first.
+// A file is oscillating when it was re-added after being deleted, or when
+// large one-directional commits keep reversing direction without moving
+// the file much overall.
+func classifyChurnPattern(events []churnEvent) string {
+	var net, gross int
+	seenRemoved := false
+	flips := 0
+	lastSwing := 0
+
+	for _, ev := range events {
+		if ev.removed {
+			seenRemoved = true
+		}
+		if ev.created && seenRemoved {
+			return ChurnPatternOscillating
+		}
+
+		delta := ev.added - ev.deleted
+		total := ev.added + ev.deleted
+		net += delta
+		gross += total
+
+		if absInt(delta) >= minSwingLines && float64(absInt(delta)) >= minSwingFraction*float64(total) {
+			sign := 1
+			if delta < 0 {
+				sign = -1
+			}
+			if lastSwing != 0 && sign != lastSwing {
+				flips++
+			}
+			lastSwing = sign
+		}
+	}
+
+	if gross == 0 {
+		return ChurnPatternSteady
+	}
+	directional := float64(absInt(net)) >= directionalChurnFraction*float64(gross)
+	switch {
+	case flips >= 2 && !directional:
+		return ChurnPatternOscillating
+	case directional && net > 0:
+		return ChurnPatternGrowing
+	case directional:
+		return ChurnPatternShrinking
+	default:
+		return ChurnPatternSteady
+	}
+}
+
+// HotspotScore is the composite churn score of a file:
+// sqrt(changeCount) * log(authorCount + 1) * log(averageChanges + 1).
+// It weighs files changed often by several authors highest.
+func HotspotScore(changeCount, authorCount int, averageChanges float64) float64 {
+	return math.Sqrt(float64(changeCount)) *
+		math.Log(float64(authorCount)+1) *
+		math.Log(averageChanges+1)
+}
+
+// Merge folds the churn of another path for the same logical file, such as
+// a symlink to it, into m and recomputes the hotspot score. Authors can't be
+// unioned from counts, so the larger set is kept as a lower bound.
+func (m *ChurnMetrics) Merge(other ChurnMetrics) {
+	totalChanges := m.ChangeCount + other.ChangeCount
+	if totalChanges > 0 {
+		m.AverageChanges = (m.AverageChanges*float64(m.ChangeCount) + other.AverageChanges*float64(other.ChangeCount)) / float64(totalChanges)
+	}
+	if other.AuthorCount > m.AuthorCount {
+		m.AuthorCount = other.AuthorCount
+	}
+	if other.LastModified > m.LastModified {
+		m.LastModified = other.LastModified
+	}
+	m.ChurnPattern = mergeChurnPatterns(m.ChurnPattern, m.ChangeCount, other.ChurnPattern, other.ChangeCount)
+	m.ChangeCount = totalChanges
+
+	if len(other.ChangeTimes) > 0 {
+		times := append(append(make([]string, 0, len(m.ChangeTimes)+len(other.ChangeTimes)), m.ChangeTimes...), other.ChangeTimes...)
+		sort.SliceStable(times, func(i, j int) bool { return times[i] > times[j] })
+		m.ChangeTimes = times
+	}
+	m.HotspotScore = HotspotScore(m.ChangeCount, m.AuthorCount, m.AverageChanges)
+}
+
+// mergeChurnPatterns combines the patterns of two change sequences: either
+// oscillating makes the file oscillating, and otherwise the pattern of the
+// busier sequence wins.
+func mergeChurnPatterns(a string, aChanges int, b string, bChanges int) string {
+	switch {
+	case a == ChurnPatternOscillating || b == ChurnPatternOscillating:
+		return ChurnPatternOscillating
+	case a == "":
+		return b
+	case b == "" || aChanges >= bChanges:
+		return a
+	default:
+		return b
+	}
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
 }
 
 // GetFileChurn returns churn metrics for a specific file
@@ -83,12 +224,7 @@ func (g *GitAdapter) GetFileChurn(filePath string, since string) (*ChurnMetrics,
 		avgChanges = 0
 	}
 
-	// Calculate hotspot score
-	// Score = sqrt(changeCount) * log(authorCount + 1) * log(avgChanges + 1)
-	// This gives higher weight to files with many changes by multiple authors
-	hotspotScore := math.Sqrt(float64(changeCount)) *
-		math.Log(float64(len(authors))+1) *
-		math.Log(avgChanges+1)
+	hotspotScore := HotspotScore(changeCount, len(authors), avgChanges)
 
 	return &ChurnMetrics{
 		FilePath:       filePath,
@@ -115,8 +251,9 @@ func (g *GitAdapter) GetHotspots(limit int, since string) ([]ChurnMetrics, error
 	})
 
 	// Use single git log command to get all data at once
-	// Format: commit hash, author, timestamp, then numstat for files
-	args := []string{"log", "--format=%H|%an|%aI", "--numstat"}
+	// Format: commit hash, author, timestamp, then numstat for files and a
+	// summary of created/deleted files
+	args := []string{"log", "--format=%H|%an|%aI", "--numstat", "--summary"}
 	if since != "" {
 		args = append(args, fmt.Sprintf("--since=%s", since))
 	}
@@ -138,6 +275,8 @@ func (g *GitAdapter) GetHotspots(limit int, since string) ([]ChurnMetrics, error
 		lastModified string
 		totalAdded   int
 		totalDeleted int
+		events       []churnEvent // Newest first
+		changeTimes  []string     // Newest first
 	}
 	fileMetrics := make(map[string]*fileStats)
 
@@ -160,6 +299,21 @@ func (g *GitAdapter) GetHotspots(limit int, since string) ([]ChurnMetrics, error
 			}
 		}
 
+		// Summary lines mark files created or deleted by the current commit;
+		// they follow the commit's numstat lines
+		if created, removed := strings.HasPrefix(line, "create mode "), strings.HasPrefix(line, "delete mode "); created || removed {
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				continue
+			}
+			if stats, ok := fileMetrics[strings.Join(fields[3:], " ")]; ok && len(stats.events) > 0 {
+				ev := &stats.events[len(stats.events)-1]
+				ev.created = ev.created || created
+				ev.removed = ev.removed || removed
+			}
+			continue
+		}
+
 		// This is a numstat line: "added<tab>deleted<tab>filename"
 		parts := strings.Fields(line)
 		if len(parts) < 3 {
@@ -189,6 +343,8 @@ func (g *GitAdapter) GetHotspots(limit int, since string) ([]ChurnMetrics, error
 		stats.authors[currentAuthor] = true
 		stats.totalAdded += added
 		stats.totalDeleted += deleted
+		stats.events = append(stats.events, churnEvent{added: added, deleted: deleted})
+		stats.changeTimes = append(stats.changeTimes, currentCommitTime)
 		// First commit seen is the most recent (git log is newest first)
 		if stats.lastModified == "" {
 			stats.lastModified = currentCommitTime
@@ -205,10 +361,13 @@ func (g *GitAdapter) GetHotspots(limit int, since string) ([]ChurnMetrics, error
 		avgChanges := float64(stats.totalAdded+stats.totalDeleted) / float64(stats.changeCount)
 		authorCount := len(stats.authors)
 
-		// Calculate hotspot score
-		hotspotScore := math.Sqrt(float64(stats.changeCount)) *
-			math.Log(float64(authorCount)+1) *
-			math.Log(avgChanges+1)
+		hotspotScore := HotspotScore(stats.changeCount, authorCount, avgChanges)
+
+		// Classify oldest first
+		events := make([]churnEvent, len(stats.events))
+		for i, ev := range stats.events {
+			events[len(events)-1-i] = ev
+		}
 
 		metrics = append(metrics, ChurnMetrics{
 			FilePath:       filePath,
@@ -217,6 +376,8 @@ func (g *GitAdapter) GetHotspots(limit int, since string) ([]ChurnMetrics, error
 			LastModified:   stats.lastModified,
 			AverageChanges: avgChanges,
 			HotspotScore:   hotspotScore,
+			ChurnPattern:   classifyChurnPattern(events),
+			ChangeTimes:    stats.changeTimes,
 		})
 	}
 
@@ -282,16 +443,65 @@ func (g *GitAdapter) getFileAuthorsSince(filePath string, since string) ([]strin
 	}
 
 	// Parse author names
-	authors := make([]string, 0, len(lines))
+	counts := parseShortlog(lines)
+	authors := make([]string, 0, len(counts))
+	for _, c := range counts {
+		authors = append(authors, c.Author)
+	}
+
+	return authors, nil
+}
+
+// AuthorCommitCount is the number of commits an author made to a file.
+type AuthorCommitCount struct {
+	Author  string `json:"author"`
+	Commits int    `json:"commits"`
+}
+
+// GetFileAuthorCommitCounts returns how many commits each author made to a
+// file since a given time, most commits first.
+func (g *GitAdapter) GetFileAuthorCommitCounts(filePath string, since string) ([]AuthorCommitCount, error) {
+	args := []string{"shortlog", "-sn"}
+
+	if since != "" {
+		args = append(args, fmt.Sprintf("--since=%s", since))
+	}
+
+	args = append(args, "HEAD", "--", filePath)
+
+	lines, err := g.executeGitCommandLines(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := parseShortlog(lines)
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Commits != counts[j].Commits {
+			return counts[i].Commits > counts[j].Commits
+		}
+		return counts[i].Author < counts[j].Author
+	})
+	return counts, nil
+}
+
+// parseShortlog parses "git shortlog -sn" lines ("  12\tJane Doe").
+func parseShortlog(lines []string) []AuthorCommitCount {
+	counts := make([]AuthorCommitCount, 0, len(lines))
 	for _, line := range lines {
 		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			author := strings.Join(parts[1:], " ")
-			authors = append(authors, author)
+		if len(parts) < 2 {
+			continue
 		}
+		commits, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		counts = append(counts, AuthorCommitCount{
+			Author:  strings.Join(parts[1:], " "),
+			Commits: commits,
+		})
 	}
-
-	return authors, nil
+	return counts
 }
 
 // getAverageChanges calculates the average number of lines changed per commit
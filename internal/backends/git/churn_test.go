@@ -0,0 +1,253 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ckb/internal/config"
+	"ckb/internal/logging"
+)
+
+func TestClassifyChurnPattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []churnEvent // oldest first
+		want   string
+	}{
+		{
+			name: "deleted then re-added",
+			events: []churnEvent{
+				{added: 40, created: true},
+				{added: 2, deleted: 1},
+				{deleted: 41, removed: true},
+				{added: 40, created: true},
+			},
+			want: ChurnPatternOscillating,
+		},
+		{
+			name: "content added and reverted repeatedly",
+			events: []churnEvent{
+				{added: 60, deleted: 2},
+				{added: 1, deleted: 58},
+				{added: 57, deleted: 1},
+				{added: 3, deleted: 55},
+			},
+			want: ChurnPatternOscillating,
+		},
+		{
+			name: "growing",
+			events: []churnEvent{
+				{added: 50, created: true},
+				{added: 30, deleted: 5},
+				{added: 20, deleted: 4},
+			},
+			want: ChurnPatternGrowing,
+		},
+		{
+			name: "shrinking",
+			events: []churnEvent{
+				{added: 2, deleted: 30},
+				{added: 1, deleted: 25},
+				{added: 5, deleted: 12},
+			},
+			want: ChurnPatternShrinking,
+		},
+		{
+			name: "steady edits",
+			events: []churnEvent{
+				{added: 5, deleted: 4},
+				{added: 3, deleted: 6},
+				{added: 8, deleted: 7},
+				{added: 4, deleted: 5},
+			},
+			want: ChurnPatternSteady,
+		},
+		{
+			name: "small back-and-forth edits are not swings",
+			events: []churnEvent{
+				{added: 4},
+				{deleted: 4},
+				{added: 4},
+				{deleted: 4},
+			},
+			want: ChurnPatternSteady,
+		},
+		{
+			name:   "no line changes",
+			events: []churnEvent{{}},
+			want:   ChurnPatternSteady,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyChurnPattern(tt.events); got != tt.want {
+				t.Errorf("classifyChurnPattern() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitAdapter_GetHotspots_DetectsReaddedFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name string, lines int) {
+		t.Helper()
+		content := strings.Repeat("line\n", lines)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("flaky.go", 20)
+	write("grow.go", 10)
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	run("rm", "-q", "flaky.go")
+	write("grow.go", 40)
+	run("add", ".")
+	run("commit", "-q", "-m", "drop flaky")
+	write("flaky.go", 20)
+	run("add", ".")
+	run("commit", "-q", "-m", "bring flaky back")
+
+	adapter, err := NewGitAdapter(&config.Config{
+		RepoRoot: dir,
+		Backends: config.BackendsConfig{Git: config.GitConfig{Enabled: true}},
+		QueryPolicy: config.QueryPolicyConfig{
+			TimeoutMs: map[string]int{"git": 5000},
+		},
+	}, logging.NewLogger(logging.Config{Format: logging.HumanFormat, Level: logging.ErrorLevel}))
+	if err != nil {
+		t.Fatalf("Failed to create adapter: %v", err)
+	}
+
+	hotspots, err := adapter.GetHotspots(10, "")
+	if err != nil {
+		t.Fatalf("Failed to get hotspots: %v", err)
+	}
+
+	patterns := map[string]string{}
+	for _, h := range hotspots {
+		patterns[h.FilePath] = h.ChurnPattern
+	}
+	if patterns["flaky.go"] != ChurnPatternOscillating {
+		t.Errorf("flaky.go pattern = %q, want oscillating", patterns["flaky.go"])
+	}
+	if patterns["grow.go"] != ChurnPatternGrowing {
+		t.Errorf("grow.go pattern = %q, want growing", patterns["grow.go"])
+	}
+}
+
+func TestChurnMetricsMerge(t *testing.T) {
+	m := ChurnMetrics{
+		FilePath:       "shared/util.go",
+		ChangeCount:    3,
+		AuthorCount:    2,
+		AverageChanges: 10,
+		LastModified:   "2024-05-01T00:00:00Z",
+		ChurnPattern:   ChurnPatternGrowing,
+		ChangeTimes:    []string{"2024-05-01T00:00:00Z", "2024-03-01T00:00:00Z", "2024-01-01T00:00:00Z"},
+	}
+	m.Merge(ChurnMetrics{
+		FilePath:       "svc/util.go",
+		ChangeCount:    1,
+		AuthorCount:    3,
+		AverageChanges: 2,
+		LastModified:   "2024-06-01T00:00:00Z",
+		ChurnPattern:   ChurnPatternShrinking,
+		ChangeTimes:    []string{"2024-04-01T00:00:00Z"},
+	})
+
+	if m.FilePath != "shared/util.go" || m.ChangeCount != 4 || m.AuthorCount != 3 || m.AverageChanges != 8 {
+		t.Errorf("unexpected merged churn: %+v", m)
+	}
+	if m.LastModified != "2024-06-01T00:00:00Z" {
+		t.Errorf("LastModified = %q, want the later of the two", m.LastModified)
+	}
+	if m.ChurnPattern != ChurnPatternGrowing {
+		t.Errorf("ChurnPattern = %q, want the busier sequence's growing", m.ChurnPattern)
+	}
+	wantTimes := []string{"2024-05-01T00:00:00Z", "2024-04-01T00:00:00Z", "2024-03-01T00:00:00Z", "2024-01-01T00:00:00Z"}
+	if strings.Join(m.ChangeTimes, ",") != strings.Join(wantTimes, ",") {
+		t.Errorf("ChangeTimes = %v, want %v", m.ChangeTimes, wantTimes)
+	}
+	if want := HotspotScore(4, 3, 8); m.HotspotScore != want {
+		t.Errorf("HotspotScore = %v, want %v", m.HotspotScore, want)
+	}
+
+	m.Merge(ChurnMetrics{ChangeCount: 1, ChurnPattern: ChurnPatternOscillating})
+	if m.ChurnPattern != ChurnPatternOscillating {
+		t.Errorf("ChurnPattern = %q, want oscillating to win", m.ChurnPattern)
+	}
+}
+
+func TestParseLineAuthorTimes(t *testing.T) {
+	output := strings.Join([]string{
+		"4e1f2a3b 1 1 2",
+		"author Alice",
+		"author-time 1600000000",
+		"author-tz +0000",
+		"filename main.go",
+		"\tpackage main",
+		"4e1f2a3b 2 2",
+		"author Alice",
+		"author-time 1600000000",
+		"filename main.go",
+		"\t",
+		"9c8d7e6f 3 3 1",
+		"author Bob",
+		"author-time 1700000000",
+		"filename main.go",
+		"\tfunc main() {}",
+		"",
+	}, "\n")
+
+	got := parseLineAuthorTimes(output)
+	want := []int64{1600000000, 1600000000, 1700000000}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Unix() != w {
+			t.Errorf("line %d: got %d, want %d", i+1, got[i].Unix(), w)
+		}
+	}
+}
+
+func TestParseShortlog(t *testing.T) {
+	lines := []string{
+		"    12\tJane Doe",
+		"     3\tbot",
+		"garbage",
+		"",
+	}
+	got := parseShortlog(lines)
+	want := []AuthorCommitCount{{Author: "Jane Doe", Commits: 12}, {Author: "bot", Commits: 3}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
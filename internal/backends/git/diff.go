@@ -1,6 +1,7 @@
 package git
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -80,6 +81,37 @@ func (g *GitAdapter) GetWorkingTreeDiff() ([]DiffStats, error) {
 	return stats, nil
 }
 
+// GetUncommittedDiff returns statistics for all uncommitted changes to
+// tracked files, staged and unstaged together, as one diff of the working
+// tree against HEAD. A file changed in both appears once, with the line
+// counts of its combined change.
+func (g *GitAdapter) GetUncommittedDiff() ([]DiffStats, error) {
+	g.logger.Debug("Getting uncommitted diff", nil)
+
+	lines, err := g.executeGitCommandLines("diff", "HEAD", "--numstat")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return []DiffStats{}, nil
+	}
+
+	stats, err := g.parseDiffStats(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.enrichDiffStatsStatus(stats, "diff", "HEAD", "--name-status"); err != nil {
+		// Non-fatal, log and continue
+		g.logger.Warn("Failed to enrich uncommitted diff stats", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	return stats, nil
+}
+
 // GetUntrackedFiles returns list of untracked files
 func (g *GitAdapter) GetUntrackedFiles() ([]string, error) {
 	g.logger.Debug("Getting untracked files", nil)
@@ -171,63 +203,23 @@ func (g *GitAdapter) parseDiffStats(lines []string) ([]DiffStats, error) {
 
 // enrichDiffStatsStaged adds IsNew and IsDeleted flags for staged changes
 func (g *GitAdapter) enrichDiffStatsStaged(stats []DiffStats) error {
-	// Get status for staged files
-	lines, err := g.executeGitCommandLines("diff", "--cached", "--name-status")
-	if err != nil {
-		return err
-	}
-
-	// Build status map: filepath -> status
-	statusMap := make(map[string]string)
-	for _, line := range lines {
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
-		}
-
-		status := parts[0]
-		filePath := strings.Join(parts[1:], " ")
-
-		// Handle rename syntax
-		if strings.Contains(filePath, " => ") {
-			renameParts := strings.SplitN(filePath, " => ", 2)
-			if len(renameParts) == 2 {
-				filePath = strings.TrimSpace(renameParts[1])
-			}
-		}
-
-		statusMap[filePath] = status
-	}
-
-	// Enrich stats
-	for i := range stats {
-		status, ok := statusMap[stats[i].FilePath]
-		if !ok {
-			continue
-		}
-
-		switch status[0] {
-		case 'A':
-			stats[i].IsNew = true
-		case 'D':
-			stats[i].IsDeleted = true
-		case 'R':
-			stats[i].IsRenamed = true
-		}
-	}
-
-	return nil
+	return g.enrichDiffStatsStatus(stats, "diff", "--cached", "--name-status")
 }
 
 // enrichDiffStatsWorking adds IsNew and IsDeleted flags for working tree changes
 func (g *GitAdapter) enrichDiffStatsWorking(stats []DiffStats) error {
-	// Get status for working tree files
-	lines, err := g.executeGitCommandLines("diff", "--name-status")
+	return g.enrichDiffStatsStatus(stats, "diff", "--name-status")
+}
+
+// enrichDiffStatsStatus adds IsNew, IsDeleted and IsRenamed flags from the
+// output of a "git diff --name-status" command run with args
+func (g *GitAdapter) enrichDiffStatsStatus(stats []DiffStats, args ...string) error {
+	lines, err := g.executeGitCommandLines(args...)
 	if err != nil {
 		return err
 	}
 
-	// Build status map
+	// Build status map: filepath -> status
 	statusMap := make(map[string]string)
 	for _, line := range lines {
 		parts := strings.Fields(line)
@@ -410,7 +402,7 @@ func (g *GitAdapter) GetCommitsSinceDate(since string, limit int) ([]CommitInfo,
 
 	args := []string{
 		"log",
-		"--format=%H|%an|%aI|%s",
+		commitInfoFormat,
 		"--since=" + since,
 		"-n", strconv.Itoa(limit),
 	}
@@ -420,11 +412,40 @@ func (g *GitAdapter) GetCommitsSinceDate(since string, limit int) ([]CommitInfo,
 		return nil, err
 	}
 
-	if len(lines) == 0 {
-		return []CommitInfo{}, nil
+	return parseCommitInfoLines(lines), nil
+}
+
+// GetCommitsInRange returns up to limit commits reachable from head but not
+// from base, newest first.
+func (g *GitAdapter) GetCommitsInRange(base, head string, limit int) ([]CommitInfo, error) {
+	if base == "" || head == "" {
+		return nil, errors.NewCkbError(
+			errors.InternalError,
+			"Both base and head commits are required",
+			nil,
+			nil,
+			nil,
+		)
 	}
 
-	// Parse commits
+	if limit <= 0 {
+		limit = 100 // Default cap
+	}
+
+	lines, err := g.executeGitCommandLines("log", commitInfoFormat, "-n", strconv.Itoa(limit), base+".."+head)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCommitInfoLines(lines), nil
+}
+
+// commitInfoFormat is the git log format parseCommitInfoLines reads.
+const commitInfoFormat = "--format=%H|%an|%aI|%s"
+
+// parseCommitInfoLines parses git log lines in commitInfoFormat, skipping
+// malformed ones.
+func parseCommitInfoLines(lines []string) []CommitInfo {
 	commits := make([]CommitInfo, 0, len(lines))
 	for _, line := range lines {
 		parts := strings.SplitN(line, "|", 4)
@@ -439,8 +460,7 @@ func (g *GitAdapter) GetCommitsSinceDate(since string, limit int) ([]CommitInfo,
 			Message:   parts[3],
 		})
 	}
-
-	return commits, nil
+	return commits
 }
 
 // GetFileDiffContent returns the actual diff content for a commit range
@@ -527,3 +547,189 @@ func (g *GitAdapter) GetCommitDiff(commitHash string) ([]DiffStats, error) {
 
 	return stats, nil
 }
+
+// DiffHunk is one changed region of a file, as in a unified diff header.
+// Line numbers are 1-based. OldLines is 0 for a pure addition and NewLines
+// is 0 for a pure deletion, in which case NewStart is the line the deleted
+// lines followed.
+type DiffHunk struct {
+	OldStart int `json:"oldStart"`
+	OldLines int `json:"oldLines"`
+	NewStart int `json:"newStart"`
+	NewLines int `json:"newLines"`
+}
+
+// GetFileHunks returns the hunks changing a file between base and the
+// working tree. isNew reports that the file doesn't exist at base, in which
+// case no hunks are returned.
+func (g *GitAdapter) GetFileHunks(base, filePath string) (hunks []DiffHunk, isNew bool, err error) {
+	if base == "" || filePath == "" {
+		return nil, false, errors.NewCkbError(
+			errors.InternalError,
+			"Both base ref and file path are required",
+			nil,
+			nil,
+			nil,
+		)
+	}
+
+	if _, err := g.executeGitCommand("rev-parse", "--verify", "--quiet", base+"^{commit}"); err != nil {
+		return nil, false, errors.NewCkbError(
+			errors.InternalError,
+			fmt.Sprintf("Unknown base ref %q", base),
+			err,
+			nil,
+			nil,
+		)
+	}
+
+	tracked, err := g.executeGitCommand("ls-tree", "--name-only", base, "--", filePath)
+	if err != nil {
+		return nil, false, err
+	}
+	if tracked == "" {
+		return nil, true, nil
+	}
+
+	lines, err := g.executeGitCommandLines("diff", "-U0", "--no-color", "--no-ext-diff", base, "--", filePath)
+	if err != nil {
+		return nil, false, err
+	}
+	return parseDiffHunks(lines), false, nil
+}
+
+// GetFileAtRef returns the content of a file as of ref.
+func (g *GitAdapter) GetFileAtRef(ref, filePath string) (string, error) {
+	if ref == "" || filePath == "" {
+		return "", errors.NewCkbError(
+			errors.InternalError,
+			"Both ref and file path are required",
+			nil,
+			nil,
+			nil,
+		)
+	}
+	return g.executeGitCommandRaw("show", ref+":"+filePath)
+}
+
+// GetRangeFileHunks returns the hunks changing a file between two refs.
+// A file missing at base shows as one hunk adding every line.
+func (g *GitAdapter) GetRangeFileHunks(base, head, filePath string) ([]DiffHunk, error) {
+	if base == "" || head == "" || filePath == "" {
+		return nil, errors.NewCkbError(
+			errors.InternalError,
+			"Base, head and file path are required",
+			nil,
+			nil,
+			nil,
+		)
+	}
+	lines, err := g.executeGitCommandLines("diff", "-U0", "--no-color", "--no-ext-diff", base, head, "--", filePath)
+	if err != nil {
+		return nil, err
+	}
+	return parseDiffHunks(lines), nil
+}
+
+// RangeCommit is a commit in a range and the files it changed.
+type RangeCommit struct {
+	Hash  string   `json:"hash"`
+	Files []string `json:"files,omitempty"`
+}
+
+// GetMergeBase returns the best common ancestor of two refs.
+func (g *GitAdapter) GetMergeBase(a, b string) (string, error) {
+	if a == "" || b == "" {
+		return "", errors.NewCkbError(
+			errors.InternalError,
+			"Both refs are required",
+			nil,
+			nil,
+			nil,
+		)
+	}
+	return g.executeGitCommand("merge-base", a, b)
+}
+
+// GetRangeCommits returns the commits reachable from head but not from
+// base, newest first, with the files each one changed. Merge commits list
+// no files.
+func (g *GitAdapter) GetRangeCommits(base, head string) ([]RangeCommit, error) {
+	if base == "" || head == "" {
+		return nil, errors.NewCkbError(
+			errors.InternalError,
+			"Both base and head commits are required",
+			nil,
+			nil,
+			nil,
+		)
+	}
+	lines, err := g.executeGitCommandLines("log", "--format="+rangeCommitPrefix+"%H", "--name-only", base+".."+head)
+	if err != nil {
+		return nil, err
+	}
+	return parseRangeCommits(lines), nil
+}
+
+// rangeCommitPrefix marks the header line of each commit in GetRangeCommits
+// output, so it can't be mistaken for a file name.
+const rangeCommitPrefix = "commit "
+
+func parseRangeCommits(lines []string) []RangeCommit {
+	var commits []RangeCommit
+	for _, line := range lines {
+		if hash, ok := strings.CutPrefix(line, rangeCommitPrefix); ok {
+			commits = append(commits, RangeCommit{Hash: hash})
+			continue
+		}
+		if len(commits) > 0 {
+			last := &commits[len(commits)-1]
+			last.Files = append(last.Files, line)
+		}
+	}
+	return commits
+}
+
+// parseDiffHunks extracts the hunk headers ("@@ -a,b +c,d @@") of a unified
+// diff. An omitted count means one line.
+func parseDiffHunks(lines []string) []DiffHunk {
+	var hunks []DiffHunk
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "@@ ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+			continue
+		}
+		oldStart, oldLines, ok1 := parseHunkRange(fields[1][1:])
+		newStart, newLines, ok2 := parseHunkRange(fields[2][1:])
+		if !ok1 || !ok2 {
+			continue
+		}
+		hunks = append(hunks, DiffHunk{
+			OldStart: oldStart,
+			OldLines: oldLines,
+			NewStart: newStart,
+			NewLines: newLines,
+		})
+	}
+	return hunks
+}
+
+func parseHunkRange(s string) (start, count int, ok bool) {
+	count = 1
+	if i := strings.IndexByte(s, ','); i >= 0 {
+		n, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return 0, 0, false
+		}
+		count = n
+		s = s[:i]
+	}
+	start, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, count, true
+}
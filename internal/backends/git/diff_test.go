@@ -0,0 +1,152 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"ckb/internal/config"
+	"ckb/internal/logging"
+)
+
+func TestParseDiffHunks(t *testing.T) {
+	lines := []string{
+		"diff --git a/main.go b/main.go",
+		"index 83db48f..bf269f4 100644",
+		"--- a/main.go",
+		"+++ b/main.go",
+		"@@ -3,2 +3,3 @@ func main() {",
+		"-\told()",
+		"+\tnew()",
+		"@@ -10 +11,0 @@",
+		"@@ -0,0 +20 @@ import \"fmt\"",
+		"@@ malformed",
+	}
+
+	want := []DiffHunk{
+		{OldStart: 3, OldLines: 2, NewStart: 3, NewLines: 3},
+		{OldStart: 10, OldLines: 1, NewStart: 11, NewLines: 0},
+		{OldStart: 0, OldLines: 0, NewStart: 20, NewLines: 1},
+	}
+	if got := parseDiffHunks(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDiffHunks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseRangeCommits(t *testing.T) {
+	lines := []string{
+		"commit 3333",
+		"b.go",
+		"commit 2222",
+		"commit 1111",
+		"a.go",
+		"dir/c.go",
+	}
+
+	want := []RangeCommit{
+		{Hash: "3333", Files: []string{"b.go"}},
+		{Hash: "2222"},
+		{Hash: "1111", Files: []string{"a.go", "dir/c.go"}},
+	}
+	if got := parseRangeCommits(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRangeCommits() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseCommitInfoLines(t *testing.T) {
+	lines := []string{
+		"aaaa|Ada|2024-03-02T10:00:00+01:00|Fix parser | lexer",
+		"malformed",
+		"bbbb|Bob|2024-03-01T09:00:00Z|Initial",
+	}
+
+	want := []CommitInfo{
+		{Hash: "aaaa", Author: "Ada", Timestamp: "2024-03-02T10:00:00+01:00", Message: "Fix parser | lexer"},
+		{Hash: "bbbb", Author: "Bob", Timestamp: "2024-03-01T09:00:00Z", Message: "Initial"},
+	}
+	if got := parseCommitInfoLines(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCommitInfoLines() = %+v, want %+v", got, want)
+	}
+	if got := parseCommitInfoLines(nil); got == nil || len(got) != 0 {
+		t.Errorf("parseCommitInfoLines(nil) = %#v, want an empty slice", got)
+	}
+}
+
+func TestGetUncommittedDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name string, lines int) {
+		t.Helper()
+		content := strings.Repeat("line\n", lines)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("both.go", 10)
+	write("unstaged.go", 5)
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+
+	// both.go gets a staged change and a further unstaged one
+	write("both.go", 13)
+	run("add", "both.go")
+	write("both.go", 15)
+	write("unstaged.go", 6)
+	write("new.go", 4)
+	run("add", "new.go")
+
+	adapter, err := NewGitAdapter(&config.Config{
+		RepoRoot: dir,
+		Backends: config.BackendsConfig{Git: config.GitConfig{Enabled: true}},
+		QueryPolicy: config.QueryPolicyConfig{
+			TimeoutMs: map[string]int{"git": 5000},
+		},
+	}, logging.NewLogger(logging.Config{Format: logging.HumanFormat, Level: logging.ErrorLevel}))
+	if err != nil {
+		t.Fatalf("Failed to create adapter: %v", err)
+	}
+
+	stats, err := adapter.GetUncommittedDiff()
+	if err != nil {
+		t.Fatalf("GetUncommittedDiff() error = %v", err)
+	}
+
+	byPath := make(map[string]DiffStats)
+	for _, s := range stats {
+		if _, dup := byPath[s.FilePath]; dup {
+			t.Errorf("%s reported more than once", s.FilePath)
+		}
+		byPath[s.FilePath] = s
+	}
+	if len(byPath) != 3 {
+		t.Fatalf("expected 3 files, got %+v", stats)
+	}
+	if got := byPath["both.go"]; got.Additions != 5 || got.IsNew {
+		t.Errorf("both.go = %+v, want 5 combined additions", got)
+	}
+	if got := byPath["unstaged.go"]; got.Additions != 1 {
+		t.Errorf("unstaged.go = %+v, want 1 addition", got)
+	}
+	if got := byPath["new.go"]; got.Additions != 4 || !got.IsNew {
+		t.Errorf("new.go = %+v, want new file with 4 additions", got)
+	}
+}
@@ -109,6 +109,41 @@ func TestGitAdapter_GetFileCommitCount_EmptyPath(t *testing.T) {
 	}
 }
 
+// TestGitAdapter_GetFilesCommitCount tests that commits shared by several
+// files are counted once
+func TestGitAdapter_GetFilesCommitCount(t *testing.T) {
+	adapter := setupTestAdapter(t)
+
+	readme, err := adapter.GetFileCommitCount("README.md")
+	if err != nil {
+		t.Fatalf("Failed to get commit count: %v", err)
+	}
+	goMod, err := adapter.GetFileCommitCount("go.mod")
+	if err != nil {
+		t.Fatalf("Failed to get commit count: %v", err)
+	}
+
+	count, err := adapter.GetFilesCommitCount([]string{"README.md"})
+	if err != nil {
+		t.Fatalf("Failed to get commit count: %v", err)
+	}
+	if count != readme {
+		t.Errorf("Expected %d commits for README.md alone, got %d", readme, count)
+	}
+
+	count, err = adapter.GetFilesCommitCount([]string{"README.md", "go.mod"})
+	if err != nil {
+		t.Fatalf("Failed to get commit count: %v", err)
+	}
+	if count < readme || count < goMod || count > readme+goMod {
+		t.Errorf("Expected between max(%d, %d) and %d commits, got %d", readme, goMod, readme+goMod, count)
+	}
+
+	if _, err := adapter.GetFilesCommitCount(nil); err == nil {
+		t.Error("Expected error for no file paths")
+	}
+}
+
 // TestGitAdapter_GetFileLastModified tests last modified timestamp
 func TestGitAdapter_GetFileLastModified(t *testing.T) {
 	adapter := setupTestAdapter(t)
@@ -242,6 +277,9 @@ func TestGitAdapter_GetHotspots(t *testing.T) {
 
 	for i, h := range hotspots {
 		t.Logf("Hotspot %d: %s (score=%.2f, changes=%d)", i, h.FilePath, h.HotspotScore, h.ChangeCount)
+		if len(h.ChangeTimes) != h.ChangeCount {
+			t.Errorf("%s: %d change times for %d changes", h.FilePath, len(h.ChangeTimes), h.ChangeCount)
+		}
 	}
 }
 
@@ -2,8 +2,10 @@ package git
 
 import (
 	"fmt"
+	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"ckb/internal/errors"
 )
@@ -189,6 +191,39 @@ func (g *GitAdapter) GetFileCommitCount(filePath string) (int, error) {
 	return count, nil
 }
 
+// GetFilesCommitCount returns the number of commits that touched any of the
+// files; a commit touching several of them is counted once
+func (g *GitAdapter) GetFilesCommitCount(filePaths []string) (int, error) {
+	if len(filePaths) == 0 {
+		return 0, errors.NewCkbError(
+			errors.InternalError,
+			"File path is required",
+			nil,
+			nil,
+			nil,
+		)
+	}
+
+	args := append([]string{"rev-list", "--count", "HEAD", "--"}, filePaths...)
+	output, err := g.executeGitCommand(args...)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := strconv.Atoi(output)
+	if err != nil {
+		return 0, errors.NewCkbError(
+			errors.InternalError,
+			"Failed to parse commit count",
+			err,
+			nil,
+			nil,
+		)
+	}
+
+	return count, nil
+}
+
 // GetFileLastModified returns the timestamp of the last commit that modified a file
 func (g *GitAdapter) GetFileLastModified(filePath string) (string, error) {
 	if filePath == "" {
@@ -258,3 +293,75 @@ func (g *GitAdapter) GetFileAuthors(filePath string) ([]string, error) {
 
 	return authors, nil
 }
+
+// GetLineAuthorTimes returns, for each line of a file at HEAD plus working
+// tree changes, when it was last modified according to git blame. Index i
+// holds line i+1. Uncommitted lines carry the time blame assigns them (now).
+func (g *GitAdapter) GetLineAuthorTimes(filePath string) ([]time.Time, error) {
+	if filePath == "" {
+		return nil, errors.NewCkbError(
+			errors.InternalError,
+			"File path is required",
+			nil,
+			nil,
+			nil,
+		)
+	}
+
+	output, err := g.executeGitCommand("blame", "--line-porcelain", "--", filePath)
+	if err != nil {
+		return nil, err
+	}
+	return parseLineAuthorTimes(output), nil
+}
+
+// parseLineAuthorTimes reads author times from git blame --line-porcelain
+// output, where every source line (prefixed with a tab) follows its own
+// header block.
+func parseLineAuthorTimes(output string) []time.Time {
+	var times []time.Time
+	var current time.Time
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			times = append(times, current)
+			current = time.Time{}
+		case strings.HasPrefix(line, "author-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				current = time.Unix(sec, 0)
+			}
+		}
+	}
+	return times
+}
+
+// GetDirectoryCommitCounts returns how many commits since the given time
+// (any git --since value) touched each directory, counting files directly
+// in it. The repository root is ".".
+func (g *GitAdapter) GetDirectoryCommitCounts(since string) (map[string]int, error) {
+	args := []string{"log", "--name-only", "--format=commit %H"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	args = append(args, "HEAD")
+
+	lines, err := g.executeGitCommandLines(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		if strings.HasPrefix(line, "commit ") {
+			seen = make(map[string]bool)
+			continue
+		}
+		dir := path.Dir(line)
+		if !seen[dir] {
+			seen[dir] = true
+			counts[dir]++
+		}
+	}
+	return counts, nil
+}
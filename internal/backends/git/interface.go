@@ -32,6 +32,9 @@ type GitBackend interface {
 	// GetWorkingTreeDiff returns statistics for working tree changes
 	GetWorkingTreeDiff() ([]DiffStats, error)
 
+	// GetUncommittedDiff returns statistics for staged and unstaged changes combined
+	GetUncommittedDiff() ([]DiffStats, error)
+
 	// GetUntrackedFiles returns list of untracked files
 	GetUntrackedFiles() ([]string, error)
 }
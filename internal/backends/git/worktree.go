@@ -0,0 +1,41 @@
+package git
+
+import (
+	"ckb/internal/errors"
+)
+
+// ResolveCommit resolves a revision (branch, tag, SHA, HEAD~n) to a full commit hash
+func (g *GitAdapter) ResolveCommit(rev string) (string, error) {
+	if rev == "" {
+		return "", errors.NewCkbError(
+			errors.InternalError,
+			"Revision is required",
+			nil,
+			nil,
+			nil,
+		)
+	}
+
+	return g.executeGitCommand("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+}
+
+// AddWorktree checks out rev into dir as a detached worktree.
+// The caller must call RemoveWorktree when done.
+func (g *GitAdapter) AddWorktree(dir, rev string) error {
+	g.logger.Debug("Adding detached worktree", map[string]interface{}{
+		"dir": dir,
+		"rev": rev,
+	})
+
+	_, err := g.executeGitCommand("worktree", "add", "--detach", "--force", dir, rev)
+	return err
+}
+
+// RemoveWorktree removes a worktree created by AddWorktree and prunes its metadata
+func (g *GitAdapter) RemoveWorktree(dir string) error {
+	if _, err := g.executeGitCommand("worktree", "remove", "--force", dir); err != nil {
+		return err
+	}
+	_, err := g.executeGitCommand("worktree", "prune")
+	return err
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitAdapter_ResolveCommit(t *testing.T) {
+	adapter := setupTestAdapter(t)
+
+	head, err := adapter.GetHeadCommit()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD commit: %v", err)
+	}
+
+	resolved, err := adapter.ResolveCommit("HEAD")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+	if resolved != head {
+		t.Errorf("ResolveCommit(HEAD) = %s, want %s", resolved, head)
+	}
+
+	if _, err := adapter.ResolveCommit("no-such-revision-ckb"); err == nil {
+		t.Error("Expected error for unknown revision")
+	}
+	if _, err := adapter.ResolveCommit(""); err == nil {
+		t.Error("Expected error for empty revision")
+	}
+}
+
+func TestGitAdapter_Worktree(t *testing.T) {
+	adapter := setupTestAdapter(t)
+
+	dir := filepath.Join(t.TempDir(), "tree")
+	if err := adapter.AddWorktree(dir, "HEAD"); err != nil {
+		t.Fatalf("Failed to add worktree: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		t.Errorf("Expected checked-out files in worktree: %v", err)
+	}
+
+	if err := adapter.RemoveWorktree(dir); err != nil {
+		t.Fatalf("Failed to remove worktree: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected worktree directory to be removed, stat err: %v", err)
+	}
+}
@@ -34,22 +34,24 @@ type QueryPolicy struct {
 // DefaultQueryPolicy returns the default query policy
 func DefaultQueryPolicy() *QueryPolicy {
 	return &QueryPolicy{
-		BackendPreferenceOrder: []BackendID{BackendSCIP, BackendGlean, BackendLSP},
+		BackendPreferenceOrder: []BackendID{BackendSCIP, BackendGlean, BackendLSP, BackendTreesitter},
 		AlwaysUse:              []BackendID{BackendGit},
 		MaxInFlightPerBackend: map[BackendID]int{
-			BackendSCIP:  10,
-			BackendGlean: 10,
-			BackendLSP:   3,
-			BackendGit:   5,
+			BackendSCIP:       10,
+			BackendGlean:      10,
+			BackendLSP:        3,
+			BackendGit:        5,
+			BackendTreesitter: 5,
 		},
 		CoalesceWindowMs:    50,
 		MergeMode:           MergeModePreferFirst,
 		SupplementThreshold: 0.8,
 		TimeoutMs: map[BackendID]int{
-			BackendSCIP:  5000,
-			BackendGlean: 5000,
-			BackendLSP:   15000,
-			BackendGit:   5000,
+			BackendSCIP:       5000,
+			BackendGlean:      5000,
+			BackendLSP:        15000,
+			BackendGit:        5000,
+			BackendTreesitter: 5000,
 		},
 	}
 }
@@ -305,6 +305,17 @@ func (s *SCIPAdapter) convertToSymbolResult(scipSym *SCIPSymbol) *backends.Symbo
 		}
 	}
 
+	var additionalLocations []backends.Location
+	for _, loc := range scipSym.AdditionalLocations {
+		additionalLocations = append(additionalLocations, backends.Location{
+			Path:      loc.FileId,
+			Line:      loc.StartLine + 1,
+			Column:    loc.StartColumn + 1,
+			EndLine:   loc.EndLine + 1,
+			EndColumn: loc.EndColumn + 1,
+		})
+	}
+
 	// Compute visibility confidence
 	visibilityConfidence := 0.9 // SCIP has good visibility inference
 	if scipSym.Visibility == "" {
@@ -316,6 +327,7 @@ func (s *SCIPAdapter) convertToSymbolResult(scipSym *SCIPSymbol) *backends.Symbo
 		Name:                 scipSym.Name,
 		Kind:                 string(scipSym.Kind),
 		Location:             location,
+		AdditionalLocations:  additionalLocations,
 		SignatureNormalized:  scipSym.SignatureNormalized,
 		SignatureFull:        "", // TODO: Extract full signature
 		Visibility:           scipSym.Visibility,
@@ -395,6 +407,7 @@ func (s *SCIPAdapter) GetIndexInfo() *IndexInfo {
 		IndexedCommit: s.index.IndexedCommit,
 		LoadedAt:      s.index.LoadedAt,
 		Freshness:     s.freshness,
+		ProjectRoot:   projectRoot(s.index),
 	}
 }
 
@@ -407,6 +420,14 @@ type IndexInfo struct {
 	IndexedCommit string
 	LoadedAt      time.Time
 	Freshness     *IndexFreshness
+	ProjectRoot   string // Directory the indexer ran in, as recorded by it (often a file:// URI)
+}
+
+func projectRoot(index *SCIPIndex) string {
+	if index.Metadata == nil {
+		return ""
+	}
+	return index.Metadata.ProjectRoot
 }
 
 // Reload reloads the SCIP index
@@ -480,6 +501,18 @@ func (s *SCIPAdapter) CountSymbolsByPath(pathPrefix string) int {
 	return s.index.CountSymbolsByPath(pathPrefix)
 }
 
+// ListDocuments summarizes the indexed documents inside dir
+func (s *SCIPAdapter) ListDocuments(dir string) []DocumentSummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.index == nil {
+		return nil
+	}
+
+	return s.index.ListDocuments(dir)
+}
+
 // AllSymbols returns all symbols in the index
 func (s *SCIPAdapter) AllSymbols() []*SymbolInformation {
 	s.mu.RLock()
@@ -504,6 +537,19 @@ func (s *SCIPAdapter) GetReferenceCount(symbolId string) int {
 	return s.index.GetReferenceCount(symbolId)
 }
 
+// ImplementedSymbols returns the symbols (e.g. interface methods) that a
+// symbol implements
+func (s *SCIPAdapter) ImplementedSymbols(symbolId string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.index == nil {
+		return nil
+	}
+
+	return s.index.FindImplementedSymbols(symbolId)
+}
+
 // GetIndex returns the underlying SCIP index for direct access.
 // This is used by FTS population and other systems that need raw symbol data.
 func (s *SCIPAdapter) GetIndex() *SCIPIndex {
@@ -512,3 +558,16 @@ func (s *SCIPAdapter) GetIndex() *SCIPIndex {
 
 	return s.index
 }
+
+// FileCoupling returns the structural coupling of a repo-relative file,
+// inspecting at most maxVisits occurrences
+func (s *SCIPAdapter) FileCoupling(relativePath string, maxVisits int) (FileCoupling, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.index == nil {
+		return FileCoupling{}, false
+	}
+
+	return s.index.FileCoupling(relativePath, maxVisits)
+}
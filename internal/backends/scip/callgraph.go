@@ -16,13 +16,15 @@ type CallGraphNode struct {
 	Name     string
 	Kind     SymbolKind
 	Location *Location
+	CallSite *Location // Where the call was found, for nodes returned by FindCallers/FindCallees
 }
 
 // CallGraphEdge represents an edge in the call graph
 type CallGraphEdge struct {
-	From string // caller symbol ID
-	To   string // callee symbol ID
-	Kind string // "call" or "reference"
+	From     string    // caller symbol ID
+	To       string    // callee symbol ID
+	Kind     string    // "call" or "reference"
+	CallSite *Location // First call of To inside From, when known
 }
 
 // CallGraph represents a call graph centered on a symbol
@@ -127,6 +129,7 @@ func (idx *SCIPIndex) FindCallees(symbolId string) ([]*CallGraphNode, error) {
 				Name:     extractSymbolName(occ.Symbol),
 				Kind:     kind,
 				Location: location,
+				CallSite: parseOccurrenceRange(occ, funcDoc.RelativePath),
 			})
 		}
 	}
@@ -176,6 +179,7 @@ func (idx *SCIPIndex) FindCallers(symbolId string) ([]*CallGraphNode, error) {
 						Name:     extractSymbolName(funcSymbol),
 						Kind:     kind,
 						Location: location,
+						CallSite: parseOccurrenceRange(occ, doc.RelativePath),
 					})
 					break
 				}
@@ -327,9 +331,10 @@ func (idx *SCIPIndex) BuildCallGraph(symbolId string, opts CallGraphOptions) (*C
 				if !seenEdges[edgeKey] {
 					seenEdges[edgeKey] = true
 					graph.Edges = append(graph.Edges, CallGraphEdge{
-						From: caller.SymbolID,
-						To:   current.id,
-						Kind: "call",
+						From:     caller.SymbolID,
+						To:       current.id,
+						Kind:     "call",
+						CallSite: caller.CallSite,
 					})
 				}
 
@@ -384,9 +389,10 @@ func (idx *SCIPIndex) BuildCallGraph(symbolId string, opts CallGraphOptions) (*C
 				if !seenEdges[edgeKey] {
 					seenEdges[edgeKey] = true
 					graph.Edges = append(graph.Edges, CallGraphEdge{
-						From: current.id,
-						To:   callee.SymbolID,
-						Kind: "call",
+						From:     current.id,
+						To:       callee.SymbolID,
+						Kind:     "call",
+						CallSite: callee.CallSite,
 					})
 				}
 
@@ -327,6 +327,7 @@ func convertSymbolInformation(sym *scippb.SymbolInformation) *SymbolInformation
 		Kind:                   int32(sym.Kind),
 		DisplayName:            sym.DisplayName,
 		SignatureDocumentation: nil, // Skip signature documentation for now
+		SignatureText:          sym.GetSignatureDocumentation().GetText(),
 		EnclosingSymbol:        sym.EnclosingSymbol,
 	}
 }
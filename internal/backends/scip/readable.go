@@ -0,0 +1,172 @@
+package scip
+
+import (
+	"path"
+	"strings"
+)
+
+// descriptorKind is the role a descriptor plays, given by its suffix.
+type descriptorKind int
+
+const (
+	descNamespace descriptorKind = iota // name/
+	descType                            // name#
+	descTerm                            // name.
+	descMethod                          // name(disambiguator).
+	descTypeParam                       // [name]
+	descParam                           // (name)
+	descMeta                            // name:
+	descMacro                           // name!
+)
+
+type descriptor struct {
+	name string
+	kind descriptorKind
+}
+
+// parseDescriptors splits a SCIP descriptor string into its parts, e.g.
+// "`ckb/internal/query`/Engine#GetSymbol()." into the namespace
+// "ckb/internal/query", the type "Engine" and the method "GetSymbol".
+func parseDescriptors(s string) ([]descriptor, bool) {
+	var parts []descriptor
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '[', '(':
+			closer := byte(']')
+			kind := descTypeParam
+			if s[i] == '(' {
+				closer, kind = ')', descParam
+			}
+			end := strings.IndexByte(s[i+1:], closer)
+			if end < 0 {
+				return nil, false
+			}
+			parts = append(parts, descriptor{name: s[i+1 : i+1+end], kind: kind})
+			i += end + 2
+			continue
+		}
+
+		name, next, ok := readDescriptorName(s, i)
+		if !ok || next >= len(s) {
+			return nil, false
+		}
+		i = next
+		switch s[i] {
+		case '/':
+			parts = append(parts, descriptor{name: name, kind: descNamespace})
+		case '#':
+			parts = append(parts, descriptor{name: name, kind: descType})
+		case '.':
+			parts = append(parts, descriptor{name: name, kind: descTerm})
+		case ':':
+			parts = append(parts, descriptor{name: name, kind: descMeta})
+		case '!':
+			parts = append(parts, descriptor{name: name, kind: descMacro})
+		case '(':
+			end := strings.IndexByte(s[i:], ')')
+			if end < 0 {
+				return nil, false
+			}
+			i += end
+			if i+1 < len(s) && s[i+1] == '.' {
+				i++
+			}
+			parts = append(parts, descriptor{name: name, kind: descMethod})
+		default:
+			return nil, false
+		}
+		i++
+	}
+	return parts, len(parts) > 0
+}
+
+// readDescriptorName reads a plain or backtick-escaped name starting at i
+// and returns it with the index of the suffix that follows.
+func readDescriptorName(s string, i int) (string, int, bool) {
+	if s[i] == '`' {
+		var b strings.Builder
+		for j := i + 1; j < len(s); j++ {
+			if s[j] != '`' {
+				b.WriteByte(s[j])
+				continue
+			}
+			if j+1 < len(s) && s[j+1] == '`' { // Escaped backtick
+				b.WriteByte('`')
+				j++
+				continue
+			}
+			return b.String(), j + 1, true
+		}
+		return "", 0, false
+	}
+
+	j := i
+	for j < len(s) && !strings.ContainsRune("/#.:!([", rune(s[j])) {
+		j++
+	}
+	if j == i {
+		return "", 0, false
+	}
+	return s[i:j], j, true
+}
+
+// ReadableSymbolID renders a SCIP symbol as a short qualified name in the
+// conventions of its language, e.g. "query.Engine.GetSymbol" for Go,
+// "com.google.common.ImmutableList.of" for Java or "crate::fs::read" for
+// Rust. Parameters, type parameters and overload disambiguators are dropped.
+// It reports false for IDs that aren't global SCIP symbols.
+func ReadableSymbolID(id string) (string, bool) {
+	if !strings.HasPrefix(id, "scip-") {
+		return "", false
+	}
+	parsed, err := ParseSCIPIdentifier(id)
+	if err != nil {
+		return "", false
+	}
+	parts, ok := parseDescriptors(parsed.Descriptor)
+	if !ok {
+		return "", false
+	}
+
+	language := parsed.GetLanguage()
+	var namespaces, names []string
+	for _, p := range parts {
+		switch p.kind {
+		case descNamespace:
+			namespaces = append(namespaces, p.name)
+		case descTypeParam, descParam:
+			// Not part of the symbol's name
+		default:
+			names = append(names, p.name)
+		}
+	}
+
+	var qualifier []string
+	switch language {
+	case "go":
+		// Go code refers to a package by its last path element
+		if len(namespaces) > 0 {
+			qualifier = []string{path.Base(namespaces[len(namespaces)-1])}
+		}
+	case "typescript", "javascript":
+		// Namespaces are source files; qualify by module name
+		if len(namespaces) > 0 {
+			file := path.Base(namespaces[len(namespaces)-1])
+			qualifier = []string{strings.TrimSuffix(file, path.Ext(file))}
+		}
+	default:
+		for _, ns := range namespaces {
+			qualifier = append(qualifier, strings.Split(strings.Trim(ns, "/"), "/")...)
+		}
+	}
+
+	all := append(qualifier, names...)
+	if len(all) == 0 {
+		return "", false
+	}
+	separator := "."
+	if language == "rust" {
+		separator = "::"
+	}
+	return strings.Join(all, separator), true
+}
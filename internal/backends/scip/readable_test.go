@@ -0,0 +1,32 @@
+package scip
+
+import "testing"
+
+func TestReadableSymbolID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+		ok   bool
+	}{
+		{"scip-go gomod ckb . `ckb/internal/query`/Engine#GetSymbol().", "query.Engine.GetSymbol", true},
+		{"scip-go gomod ckb a6af7cfb2eff `ckb/internal/api`/NewServer().", "api.NewServer", true},
+		{"scip-go gomod ckb . `ckb/internal/query`/Engine#GetSymbol().(opts)", "query.Engine.GetSymbol", true},
+		{"scip-typescript npm app 1.0.0 `src/utils.ts`/formatDate().", "utils.formatDate", true},
+		{"scip-java maven com.google.guava:guava 31.0 com/google/common/collect/ImmutableList#of(+1).", "com.google.common.collect.ImmutableList.of", true},
+		{"scip-python python app 0.1 `app.models`/User#save().", "app.models.User.save", true},
+		{"scip-rust cargo app 0.1.0 crate/fs/read_config().", "crate::fs::read_config", true},
+		{"scip-java maven lib 1.0 Box#[T]", "Box", true},
+		{"local 42", "", false},
+		{"ckb:repo:sym:abc", "", false},
+		{"scip-go gomod ckb . `unterminated", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, ok := ReadableSymbolID(tt.id)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ReadableSymbolID(%q) = (%q, %v), want (%q, %v)", tt.id, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -390,6 +391,27 @@ func (idx *SCIPIndex) FindImplementations(symbolId string) ([]*SCIPReference, er
 	return implementations, nil
 }
 
+// FindImplementedSymbols returns the symbols that symbolId implements, such
+// as the interface methods a concrete method satisfies
+func (idx *SCIPIndex) FindImplementedSymbols(symbolId string) []string {
+	symInfo, ok := idx.Symbols[symbolId]
+	if !ok {
+		return nil
+	}
+
+	var implemented []string
+	seen := make(map[string]bool)
+	for _, rel := range symInfo.Relationships {
+		if rel.IsImplementation && rel.Symbol != symbolId && !seen[rel.Symbol] {
+			seen[rel.Symbol] = true
+			implemented = append(implemented, rel.Symbol)
+		}
+	}
+	sort.Strings(implemented)
+
+	return implemented
+}
+
 // FindTypeReferences finds all type references to a symbol
 func (idx *SCIPIndex) FindTypeReferences(symbolId string) ([]*SCIPReference, error) {
 	typeRefs := make([]*SCIPReference, 0)
@@ -414,3 +436,75 @@ func (idx *SCIPIndex) FindTypeReferences(symbolId string) ([]*SCIPReference, err
 
 	return typeRefs, nil
 }
+
+// FileCoupling counts the files structurally coupled to one file.
+type FileCoupling struct {
+	Dependents   int  // Other files referencing symbols defined in the file
+	Dependencies int  // Other files defining symbols the file references
+	Truncated    bool // The visit budget ran out; counts are lower bounds
+}
+
+// FileCoupling computes the coupling of a document from the reference
+// index. maxVisits bounds the occurrences inspected (the file's own plus
+// those of each symbol it touches) so large files stay cheap; 0 means no
+// limit. ok is false when the file isn't indexed.
+func (idx *SCIPIndex) FileCoupling(relativePath string, maxVisits int) (coupling FileCoupling, ok bool) {
+	doc := idx.GetDocument(relativePath)
+	if doc == nil || idx.RefIndex == nil {
+		return FileCoupling{}, false
+	}
+
+	dependents := make(map[string]bool)
+	dependencies := make(map[string]bool)
+	visits := 0
+	budgetLeft := func() bool {
+		if maxVisits > 0 && visits >= maxVisits {
+			coupling.Truncated = true
+			return false
+		}
+		visits++
+		return true
+	}
+
+	// Symbols the file touches, in order of first use, and whether the
+	// file defines them
+	var symbols []string
+	definedHere := make(map[string]bool)
+	for _, occ := range doc.Occurrences {
+		if !budgetLeft() {
+			break
+		}
+		if occ.Symbol == "" || strings.HasPrefix(occ.Symbol, "local ") {
+			continue
+		}
+		if _, seen := definedHere[occ.Symbol]; !seen {
+			symbols = append(symbols, occ.Symbol)
+		}
+		definedHere[occ.Symbol] = definedHere[occ.Symbol] || occ.SymbolRoles&SymbolRoleDefinition != 0
+	}
+
+	for _, symbol := range symbols {
+		if coupling.Truncated {
+			break
+		}
+		for _, ref := range idx.RefIndex[symbol] {
+			if !budgetLeft() {
+				break
+			}
+			if ref.Doc == doc {
+				continue
+			}
+			isDefinition := ref.Occ.SymbolRoles&SymbolRoleDefinition != 0
+			switch {
+			case definedHere[symbol] && !isDefinition:
+				dependents[ref.Doc.RelativePath] = true
+			case !definedHere[symbol] && isDefinition:
+				dependencies[ref.Doc.RelativePath] = true
+			}
+		}
+	}
+
+	coupling.Dependents = len(dependents)
+	coupling.Dependencies = len(dependencies)
+	return coupling, true
+}
@@ -0,0 +1,85 @@
+package scip
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindImplementedSymbols(t *testing.T) {
+	const (
+		iface    = "scip-go gomod ex . Handler#Serve()."
+		other    = "scip-go gomod ex . Closer#Close()."
+		concrete = "scip-go gomod ex . fileHandler#Serve()."
+	)
+	idx := &SCIPIndex{
+		Symbols: map[string]*SymbolInformation{
+			concrete: {
+				Symbol: concrete,
+				Relationships: []*Relationship{
+					{Symbol: other, IsImplementation: true},
+					{Symbol: iface, IsImplementation: true},
+					{Symbol: iface, IsImplementation: true},
+					{Symbol: "scip-go gomod ex . File#", IsTypeDefinition: true},
+				},
+			},
+			iface: {Symbol: iface},
+		},
+	}
+
+	got := idx.FindImplementedSymbols(concrete)
+	want := []string{other, iface}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindImplementedSymbols() = %v, want %v", got, want)
+	}
+
+	if got := idx.FindImplementedSymbols(iface); len(got) != 0 {
+		t.Errorf("interface method implements nothing, got %v", got)
+	}
+	if got := idx.FindImplementedSymbols("missing"); got != nil {
+		t.Errorf("unknown symbol should return nil, got %v", got)
+	}
+}
+
+func TestFileCoupling(t *testing.T) {
+	const (
+		lib = "scip-go gomod ex . Lib()."
+		app = "scip-go gomod ex . App()."
+	)
+	def := func(sym string) *Occurrence { return &Occurrence{Symbol: sym, SymbolRoles: SymbolRoleDefinition} }
+	ref := func(sym string) *Occurrence { return &Occurrence{Symbol: sym} }
+
+	docs := []*Document{
+		{RelativePath: "lib.go", Occurrences: []*Occurrence{def(lib)}},
+		{RelativePath: "app.go", Occurrences: []*Occurrence{ref(app), def(app), ref(lib), ref(lib), {Symbol: "local 0"}}},
+		{RelativePath: "cli.go", Occurrences: []*Occurrence{ref(lib), ref(app)}},
+	}
+	idx := &SCIPIndex{Documents: docs, RefIndex: make(map[string][]*OccurrenceRef)}
+	for _, doc := range docs {
+		for _, occ := range doc.Occurrences {
+			idx.RefIndex[occ.Symbol] = append(idx.RefIndex[occ.Symbol], &OccurrenceRef{Doc: doc, Occ: occ})
+		}
+	}
+
+	tests := []struct {
+		path string
+		want FileCoupling
+	}{
+		{"lib.go", FileCoupling{Dependents: 2}},
+		// app.go uses App before defining it; it still counts as defined there
+		{"app.go", FileCoupling{Dependents: 1, Dependencies: 1}},
+		{"cli.go", FileCoupling{Dependencies: 2}},
+	}
+	for _, tt := range tests {
+		got, ok := idx.FileCoupling(tt.path, 0)
+		if !ok || got != tt.want {
+			t.Errorf("FileCoupling(%s) = %+v, %v; want %+v", tt.path, got, ok, tt.want)
+		}
+	}
+
+	if got, _ := idx.FileCoupling("lib.go", 2); !got.Truncated {
+		t.Errorf("expected truncation with a visit budget of 2, got %+v", got)
+	}
+	if _, ok := idx.FileCoupling("missing.go", 0); ok {
+		t.Error("unindexed file should not be ok")
+	}
+}
@@ -2,6 +2,7 @@ package scip
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -99,12 +100,18 @@ func convertToSCIPSymbolWithIndex(symInfo *SymbolInformation, idx *SCIPIndex) (*
 	// Extract documentation
 	documentation := strings.Join(symInfo.Documentation, "\n")
 
-	// Find location using RefIndex if available for O(1) lookup
-	var location *Location
+	// Find all definition locations; the first is the primary location
+	var locations []*Location
 	if idx.RefIndex != nil {
-		location = findSymbolLocationFast(symInfo.Symbol, idx)
+		locations = findSymbolDefinitionsFast(symInfo.Symbol, idx)
 	} else {
-		location = findSymbolLocation(symInfo.Symbol, idx)
+		locations = findSymbolDefinitions(symInfo.Symbol, idx)
+	}
+	var location *Location
+	var additionalLocations []*Location
+	if len(locations) > 0 {
+		location = locations[0]
+		additionalLocations = sortAdditionalLocations(locations[1:])
 	}
 
 	// Extract modifiers
@@ -131,6 +138,7 @@ func convertToSCIPSymbolWithIndex(symInfo *SymbolInformation, idx *SCIPIndex) (*
 		SignatureNormalized: "", // TODO: Extract signature
 		Modifiers:           modifiers,
 		Location:            location,
+		AdditionalLocations: additionalLocations,
 		ContainerName:       containerName,
 		Visibility:          visibility,
 	}, nil
@@ -225,6 +233,60 @@ func findSymbolLocationFast(symbolId string, idx *SCIPIndex) *Location {
 	return nil
 }
 
+// findSymbolDefinitions returns every definition location of a symbol, in
+// document order. Multiple definitions occur for partial classes and types
+// split across files.
+func findSymbolDefinitions(symbolId string, idx *SCIPIndex) []*Location {
+	var locations []*Location
+	for _, doc := range idx.Documents {
+		for _, occ := range doc.Occurrences {
+			if occ.Symbol == symbolId && occ.SymbolRoles&SymbolRoleDefinition != 0 {
+				if loc := parseOccurrenceRange(occ, doc.RelativePath); loc != nil {
+					locations = append(locations, loc)
+				}
+			}
+		}
+	}
+	return locations
+}
+
+// findSymbolDefinitionsFast is findSymbolDefinitions using the inverted index
+func findSymbolDefinitionsFast(symbolId string, idx *SCIPIndex) []*Location {
+	refs, ok := idx.RefIndex[symbolId]
+	if !ok {
+		return nil
+	}
+
+	var locations []*Location
+	for _, ref := range refs {
+		if ref.Occ.SymbolRoles&SymbolRoleDefinition != 0 {
+			if loc := parseOccurrenceRange(ref.Occ, ref.Doc.RelativePath); loc != nil {
+				locations = append(locations, loc)
+			}
+		}
+	}
+	return locations
+}
+
+// sortAdditionalLocations orders secondary definition sites by file, line and column
+func sortAdditionalLocations(locations []*Location) []*Location {
+	if len(locations) == 0 {
+		return nil
+	}
+	sorted := make([]*Location, len(locations))
+	copy(sorted, locations)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].FileId != sorted[j].FileId {
+			return sorted[i].FileId < sorted[j].FileId
+		}
+		if sorted[i].StartLine != sorted[j].StartLine {
+			return sorted[i].StartLine < sorted[j].StartLine
+		}
+		return sorted[i].StartColumn < sorted[j].StartColumn
+	})
+	return sorted
+}
+
 // parseOccurrenceRange converts a SCIP occurrence range to a Location
 func parseOccurrenceRange(occ *Occurrence, filePath string) *Location {
 	if len(occ.Range) < 3 {
@@ -403,14 +465,7 @@ func matchesQuery(sym *SCIPSymbol, queryLower string, options SearchOptions) boo
 		if sym.Location == nil {
 			return false
 		}
-		scopeMatch := false
-		for _, scope := range options.Scope {
-			if strings.HasPrefix(sym.Location.FileId, scope) {
-				scopeMatch = true
-				break
-			}
-		}
-		if !scopeMatch {
+		if !locationInScope(sym.Location, options.Scope) && !anyLocationInScope(sym.AdditionalLocations, options.Scope) {
 			return false
 		}
 	}
@@ -425,6 +480,26 @@ func matchesQuery(sym *SCIPSymbol, queryLower string, options SearchOptions) boo
 	return true
 }
 
+// locationInScope reports whether a location's file falls under any scope prefix
+func locationInScope(loc *Location, scopes []string) bool {
+	for _, scope := range scopes {
+		if strings.HasPrefix(loc.FileId, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyLocationInScope reports whether any of the locations falls under a scope prefix
+func anyLocationInScope(locs []*Location, scopes []string) bool {
+	for _, loc := range locs {
+		if locationInScope(loc, scopes) {
+			return true
+		}
+	}
+	return false
+}
+
 // isTestFile checks if a file path represents a test file
 func isTestFile(path string) bool {
 	pathLower := strings.ToLower(path)
@@ -448,3 +523,38 @@ func (idx *SCIPIndex) CountSymbolsByPath(pathPrefix string) int {
 	}
 	return count
 }
+
+// DocumentSummary describes one indexed document.
+type DocumentSummary struct {
+	Path        string
+	Language    string
+	SymbolCount int
+}
+
+// ListDocuments summarizes the indexed documents inside dir, sorted by path.
+// An empty dir or "." lists every document.
+func (idx *SCIPIndex) ListDocuments(dir string) []DocumentSummary {
+	dir = strings.TrimSuffix(dir, "/")
+	byPath := make(map[string]int)
+	var docs []DocumentSummary
+	for _, doc := range idx.Documents {
+		if dir != "" && dir != "." && doc.RelativePath != dir && !strings.HasPrefix(doc.RelativePath, dir+"/") {
+			continue
+		}
+		// Some indexers emit a file more than once; report it once
+		if i, ok := byPath[doc.RelativePath]; ok {
+			docs[i].SymbolCount += len(doc.Symbols)
+			continue
+		}
+		byPath[doc.RelativePath] = len(docs)
+		docs = append(docs, DocumentSummary{
+			Path:        doc.RelativePath,
+			Language:    doc.Language,
+			SymbolCount: len(doc.Symbols),
+		})
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Path < docs[j].Path
+	})
+	return docs
+}
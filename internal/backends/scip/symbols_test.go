@@ -0,0 +1,90 @@
+package scip
+
+import "testing"
+
+func TestConvertSymbolWithMultipleDefinitions(t *testing.T) {
+	const symbolId = "scip-dotnet nuget app 1.0 App/Widget#"
+	idx := &SCIPIndex{
+		Documents: []*Document{
+			{
+				RelativePath: "src/Widget.Render.cs",
+				Occurrences: []*Occurrence{
+					{Range: []int32{4, 21, 27}, Symbol: symbolId, SymbolRoles: SymbolRoleDefinition},
+				},
+			},
+			{
+				RelativePath: "src/Widget.cs",
+				Occurrences: []*Occurrence{
+					{Range: []int32{2, 21, 27}, Symbol: symbolId, SymbolRoles: SymbolRoleDefinition},
+					{Range: []int32{30, 8, 14}, Symbol: symbolId},
+				},
+			},
+			{
+				RelativePath: "src/Widget.Events.cs",
+				Occurrences: []*Occurrence{
+					{Range: []int32{7, 21, 27}, Symbol: symbolId, SymbolRoles: SymbolRoleDefinition},
+				},
+			},
+		},
+	}
+
+	sym, err := convertToSCIPSymbolWithIndex(&SymbolInformation{Symbol: symbolId, DisplayName: "Widget"}, idx)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+
+	if sym.Location == nil || sym.Location.FileId != "src/Widget.Render.cs" {
+		t.Fatalf("primary location = %+v, want first definition in document order", sym.Location)
+	}
+	if len(sym.AdditionalLocations) != 2 {
+		t.Fatalf("expected 2 additional locations, got %d", len(sym.AdditionalLocations))
+	}
+	// Additional locations are sorted by file path
+	if sym.AdditionalLocations[0].FileId != "src/Widget.Events.cs" || sym.AdditionalLocations[1].FileId != "src/Widget.cs" {
+		t.Errorf("additional locations not sorted: %s, %s",
+			sym.AdditionalLocations[0].FileId, sym.AdditionalLocations[1].FileId)
+	}
+}
+
+func TestMatchesQueryScopeUsesAdditionalLocations(t *testing.T) {
+	sym := &SCIPSymbol{
+		Name:     "Widget",
+		Location: &Location{FileId: "src/Widget.cs"},
+		AdditionalLocations: []*Location{
+			{FileId: "gen/Widget.Designer.cs"},
+		},
+	}
+
+	if !matchesQuery(sym, "widget", SearchOptions{Scope: []string{"gen/"}}) {
+		t.Error("expected scope match via additional location")
+	}
+	if matchesQuery(sym, "widget", SearchOptions{Scope: []string{"lib/"}}) {
+		t.Error("expected no scope match")
+	}
+}
+
+func TestListDocuments(t *testing.T) {
+	idx := &SCIPIndex{
+		Documents: []*Document{
+			{RelativePath: "pkg/b.go", Language: "go", Symbols: []*SymbolInformation{{Symbol: "b"}}},
+			{RelativePath: "pkgextra/c.go", Language: "go"},
+			{RelativePath: "pkg/a.go", Language: "go", Symbols: []*SymbolInformation{{Symbol: "a1"}, {Symbol: "a2"}}},
+			{RelativePath: "pkg/b.go", Language: "go", Symbols: []*SymbolInformation{{Symbol: "b2"}}},
+		},
+	}
+
+	docs := idx.ListDocuments("pkg/")
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 documents under pkg/, got %+v", docs)
+	}
+	if docs[0].Path != "pkg/a.go" || docs[0].SymbolCount != 2 {
+		t.Errorf("docs[0] = %+v, want pkg/a.go with 2 symbols", docs[0])
+	}
+	if docs[1].Path != "pkg/b.go" || docs[1].SymbolCount != 2 {
+		t.Errorf("docs[1] = %+v, want duplicate pkg/b.go merged to 2 symbols", docs[1])
+	}
+
+	if all := idx.ListDocuments(""); len(all) != 3 {
+		t.Errorf("expected 3 documents without a scope, got %d", len(all))
+	}
+}
@@ -77,6 +77,10 @@ type SCIPSymbol struct {
 	// Location is the definition location
 	Location *Location
 
+	// AdditionalLocations are further definition sites for symbols defined
+	// in multiple places (e.g., C# partial classes), sorted by file and line
+	AdditionalLocations []*Location
+
 	// ContainerName is the containing symbol (class, namespace, etc.)
 	ContainerName string
 
@@ -183,6 +187,10 @@ type SymbolInformation struct {
 	// SignatureDocumentation is the signature documentation
 	SignatureDocumentation *Document
 
+	// SignatureText is the declaration text from the indexer's signature
+	// documentation, e.g. "func Open(path string) (*DB, error)"
+	SignatureText string
+
 	// EnclosingSymbol is the containing symbol
 	EnclosingSymbol string
 }
@@ -0,0 +1,427 @@
+// Package treesitter provides an index-free symbol backend that parses source
+// files on demand. It is the last rung of the backend ladder, used when
+// neither a SCIP index nor a language server is available.
+package treesitter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ckb/internal/backends"
+	"ckb/internal/complexity"
+	"ckb/internal/errors"
+	"ckb/internal/logging"
+	"ckb/internal/paths"
+	"ckb/internal/symbols"
+)
+
+// IDPrefix marks symbol IDs produced by this backend.
+const IDPrefix = "ts-"
+
+const (
+	// symbolConfidence reflects single-file syntactic analysis: definitions
+	// are exact, but there is no type or cross-file resolution.
+	symbolConfidence = 0.6
+
+	// referenceConfidence is lower because references are matched by name
+	// and may include unrelated identifiers.
+	referenceConfidence = 0.5
+
+	// maxScanFiles caps how many files an unscoped query will parse.
+	maxScanFiles = 2000
+)
+
+// TreesitterAdapter implements the Backend and SymbolBackend interfaces by
+// parsing only the files a query touches.
+type TreesitterAdapter struct {
+	extractor *symbols.Extractor
+	repoRoot  string
+	logger    *logging.Logger
+}
+
+// NewTreesitterAdapter creates a new tree-sitter adapter. The adapter reports
+// itself unavailable when tree-sitter support is not compiled in.
+func NewTreesitterAdapter(repoRoot string, logger *logging.Logger) *TreesitterAdapter {
+	var extractor *symbols.Extractor
+	if symbols.IsAvailable() {
+		extractor = symbols.NewExtractor()
+	}
+	return &TreesitterAdapter{
+		extractor: extractor,
+		repoRoot:  repoRoot,
+		logger:    logger,
+	}
+}
+
+// ID returns the backend identifier
+func (t *TreesitterAdapter) ID() backends.BackendID {
+	return backends.BackendTreesitter
+}
+
+// IsAvailable checks if tree-sitter parsing is available
+func (t *TreesitterAdapter) IsAvailable() bool {
+	return t != nil && t.extractor != nil
+}
+
+// Capabilities returns the capabilities this backend supports
+func (t *TreesitterAdapter) Capabilities() []string {
+	return []string{
+		"symbol-search",
+		"goto-definition",
+		"find-references",
+	}
+}
+
+// Priority returns the priority of the tree-sitter backend (lowest of the
+// symbol backends)
+func (t *TreesitterAdapter) Priority() int {
+	return 5
+}
+
+// SymbolID builds the ID of a symbol defined at line of the repo-relative
+// path. The ID is decodable so lookups only need to parse that one file.
+func SymbolID(path, name, kind string, line int) string {
+	return fmt.Sprintf("%s%s:%d:%s:%s", IDPrefix, filepath.ToSlash(path), line, kind, name)
+}
+
+// ParseSymbolID decodes an ID produced by SymbolID.
+func ParseSymbolID(id string) (path, name, kind string, line int, err error) {
+	if !strings.HasPrefix(id, IDPrefix) {
+		return "", "", "", 0, fmt.Errorf("not a tree-sitter symbol ID: %s", id)
+	}
+	// Paths may contain colons, so split from the right
+	parts := strings.Split(strings.TrimPrefix(id, IDPrefix), ":")
+	if len(parts) < 4 {
+		return "", "", "", 0, fmt.Errorf("invalid tree-sitter symbol ID: %s", id)
+	}
+	n := len(parts)
+	line, err = strconv.Atoi(parts[n-3])
+	if err != nil {
+		return "", "", "", 0, fmt.Errorf("invalid line in tree-sitter symbol ID: %s", id)
+	}
+	path = strings.Join(parts[:n-3], ":")
+	if path == "" || parts[n-1] == "" {
+		return "", "", "", 0, fmt.Errorf("invalid tree-sitter symbol ID: %s", id)
+	}
+	return path, parts[n-1], parts[n-2], line, nil
+}
+
+// GetSymbol re-parses the file named in the ID and returns the matching
+// definition. If the file has been edited since the ID was issued, the
+// same-named definition nearest the original line is returned.
+func (t *TreesitterAdapter) GetSymbol(ctx context.Context, id string) (*backends.SymbolResult, error) {
+	path, name, kind, line, err := ParseSymbolID(id)
+	if err != nil {
+		return nil, errors.NewCkbError(errors.SymbolNotFound, err.Error(), nil, nil, nil)
+	}
+
+	syms, err := t.extractFile(ctx, path)
+	if err != nil {
+		return nil, errors.NewCkbError(errors.SymbolNotFound, fmt.Sprintf("Symbol not found: %s", id), err, nil, nil)
+	}
+
+	var best *symbols.Symbol
+	for i := range syms {
+		s := &syms[i]
+		if s.Name != name || s.Kind != kind {
+			continue
+		}
+		if best == nil || absInt(s.Line-line) < absInt(best.Line-line) {
+			best = s
+		}
+	}
+	if best == nil {
+		return nil, errors.NewCkbError(errors.SymbolNotFound, fmt.Sprintf("Symbol not found: %s", id), nil, nil, nil)
+	}
+
+	result := t.toSymbolResult(path, *best)
+	return &result, nil
+}
+
+// SearchSymbols parses the files under opts.Scope (or the whole repository
+// when no scope is given) and returns definitions whose name contains query.
+// An empty query matches every definition.
+func (t *TreesitterAdapter) SearchSymbols(ctx context.Context, query string, opts backends.SearchOptions) (*backends.SearchResult, error) {
+	if !t.IsAvailable() {
+		return nil, errors.NewCkbError(errors.BackendUnavailable, "tree-sitter backend is not available", nil, nil, nil)
+	}
+
+	files, truncated := t.scopeFiles(opts.Scope)
+	queryLower := strings.ToLower(query)
+
+	var results []backends.SymbolResult
+	for _, path := range files {
+		if ctx.Err() != nil {
+			truncated = true
+			break
+		}
+		if !opts.IncludeTests && isTestFile(path) {
+			continue
+		}
+		syms, err := t.extractFile(ctx, path)
+		if err != nil {
+			continue
+		}
+		for _, s := range syms {
+			if queryLower != "" && !strings.Contains(strings.ToLower(s.Name), queryLower) {
+				continue
+			}
+			if len(opts.Kind) > 0 && !matchesKind(opts.Kind, s.Kind) {
+				continue
+			}
+			results = append(results, t.toSymbolResult(path, s))
+		}
+	}
+
+	total := len(results)
+	if opts.MaxResults > 0 && len(results) > opts.MaxResults {
+		results = results[:opts.MaxResults]
+		truncated = true
+	}
+
+	return &backends.SearchResult{
+		Symbols:      results,
+		TotalMatches: total,
+		Completeness: completeness(symbolConfidence, truncated, "Definitions parsed on demand with tree-sitter"),
+	}, nil
+}
+
+// FindReferences finds identifiers matching the symbol's name in its defining
+// file and in any files under opts.Scope. Matching is by name only.
+func (t *TreesitterAdapter) FindReferences(ctx context.Context, symbolID string, opts backends.RefOptions) (*backends.ReferencesResult, error) {
+	path, name, _, line, err := ParseSymbolID(symbolID)
+	if err != nil {
+		return nil, errors.NewCkbError(errors.SymbolNotFound, err.Error(), nil, nil, nil)
+	}
+	if !t.IsAvailable() {
+		return nil, errors.NewCkbError(errors.BackendUnavailable, "tree-sitter backend is not available", nil, nil, nil)
+	}
+	if _, err := t.repoFile(path); err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	truncated := false
+	if len(opts.Scope) > 0 {
+		var scoped []string
+		scoped, truncated = t.scopeFiles(opts.Scope)
+		for _, f := range scoped {
+			if f != path {
+				files = append(files, f)
+			}
+		}
+	}
+
+	var refs []backends.Reference
+	for _, file := range files {
+		if ctx.Err() != nil {
+			truncated = true
+			break
+		}
+		if file != path && !opts.IncludeTests && isTestFile(file) {
+			continue
+		}
+		abs, err := t.repoFile(file)
+		if err != nil {
+			continue
+		}
+		occurrences, err := t.extractor.FindIdentifiers(ctx, abs, name)
+		if err != nil {
+			continue
+		}
+		for _, occ := range occurrences {
+			isDecl := file == path && occ.Line == line
+			if isDecl && !opts.IncludeDeclaration {
+				continue
+			}
+			kind := "reference"
+			if isDecl {
+				kind = "definition"
+			}
+			refs = append(refs, backends.Reference{
+				Location: backends.Location{
+					Path:      file,
+					Line:      occ.Line,
+					Column:    occ.Column,
+					EndLine:   occ.Line,
+					EndColumn: occ.EndColumn,
+				},
+				Kind:     kind,
+				SymbolID: symbolID,
+				Context:  occ.Context,
+			})
+		}
+	}
+
+	total := len(refs)
+	if opts.MaxResults > 0 && len(refs) > opts.MaxResults {
+		refs = refs[:opts.MaxResults]
+		truncated = true
+	}
+
+	return &backends.ReferencesResult{
+		References:      refs,
+		TotalReferences: total,
+		Completeness:    completeness(referenceConfidence, truncated, "Name-matched identifiers; not resolved across scopes"),
+	}, nil
+}
+
+// extractFile parses one repo-relative file.
+func (t *TreesitterAdapter) extractFile(ctx context.Context, path string) ([]symbols.Symbol, error) {
+	if !t.IsAvailable() {
+		return nil, errors.NewCkbError(errors.BackendUnavailable, "tree-sitter backend is not available", nil, nil, nil)
+	}
+
+	abs, err := t.repoFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return t.extractor.ExtractFile(ctx, abs)
+}
+
+// repoFile returns the absolute path of a repo-relative file. Paths come
+// from symbol IDs supplied by clients, so any that lead outside the
+// repository, directly or through a symlink, are rejected. Every file the
+// adapter reads goes through here.
+func (t *TreesitterAdapter) repoFile(path string) (string, error) {
+	root := filepath.Clean(t.repoRoot)
+	abs := filepath.Join(root, path)
+	if filepath.IsAbs(path) || !strings.HasPrefix(abs, root+string(filepath.Separator)) {
+		return "", errors.NewCkbError(errors.InvalidParameters, fmt.Sprintf("path outside repository: %s", path), nil, nil, nil)
+	}
+	if _, ok := paths.ResolveRepoPath(path, root); !ok {
+		return "", errors.NewCkbError(errors.InvalidParameters, fmt.Sprintf("path outside repository: %s", path), nil, nil, nil)
+	}
+	return abs, nil
+}
+
+// scopeFiles expands scope entries (files or directories, repo-relative) into
+// the supported source files they contain. An empty scope means the whole
+// repository. It reports whether the file cap was hit.
+func (t *TreesitterAdapter) scopeFiles(scope []string) ([]string, bool) {
+	if len(scope) == 0 {
+		scope = []string{"."}
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	truncated := false
+
+	add := func(abs string) bool {
+		rel, err := filepath.Rel(t.repoRoot, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return true
+		}
+		rel = filepath.ToSlash(rel)
+		if seen[rel] {
+			return true
+		}
+		if len(files) >= maxScanFiles {
+			truncated = true
+			return false
+		}
+		seen[rel] = true
+		files = append(files, rel)
+		return true
+	}
+
+	for _, s := range scope {
+		root := filepath.Join(t.repoRoot, filepath.FromSlash(s))
+		info, err := os.Stat(root)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			if isSupported(root) {
+				add(root)
+			}
+			continue
+		}
+		_ = filepath.Walk(root, func(path string, info os.FileInfo, walkErr error) error {
+			if walkErr != nil {
+				return nil //nolint:nilerr // Skip unreadable entries and keep walking
+			}
+			if info.IsDir() {
+				name := info.Name()
+				if path != root && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || name == "__pycache__") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !isSupported(path) {
+				return nil
+			}
+			if !add(path) {
+				return filepath.SkipAll
+			}
+			return nil
+		})
+	}
+
+	sort.Strings(files)
+	return files, truncated
+}
+
+// toSymbolResult converts an extracted symbol to a backend result.
+func (t *TreesitterAdapter) toSymbolResult(path string, s symbols.Symbol) backends.SymbolResult {
+	return backends.SymbolResult{
+		StableID: SymbolID(path, s.Name, s.Kind, s.Line),
+		Name:     s.Name,
+		Kind:     s.Kind,
+		Location: backends.Location{
+			Path:    path,
+			Line:    s.Line,
+			EndLine: s.EndLine,
+		},
+		SignatureNormalized: s.Signature,
+		SignatureFull:       s.Signature,
+		ContainerName:       s.Container,
+		ModuleID:            filepath.ToSlash(filepath.Dir(path)),
+		Completeness:        completeness(symbolConfidence, false, "Definition parsed on demand with tree-sitter"),
+	}
+}
+
+func completeness(score float64, truncated bool, details string) backends.CompletenessInfo {
+	if truncated {
+		return backends.NewCompletenessInfo(score, backends.Truncated, details)
+	}
+	return backends.NewCompletenessInfo(score, backends.SingleFileOnly, details)
+}
+
+func isSupported(path string) bool {
+	_, ok := complexity.LanguageFromExtension(strings.ToLower(filepath.Ext(path)))
+	return ok
+}
+
+func matchesKind(kinds []string, kind string) bool {
+	for _, k := range kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestFile checks if a file path represents a test file
+func isTestFile(path string) bool {
+	pathLower := strings.ToLower(path)
+	return strings.Contains(pathLower, "_test.") ||
+		strings.Contains(pathLower, ".test.") ||
+		strings.Contains(pathLower, ".spec.") ||
+		strings.HasPrefix(pathLower, "test/") ||
+		strings.HasPrefix(pathLower, "tests/") ||
+		strings.Contains(pathLower, "/test/") ||
+		strings.Contains(pathLower, "/tests/")
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
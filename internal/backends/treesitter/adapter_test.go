@@ -0,0 +1,170 @@
+package treesitter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ckb/internal/backends"
+	"ckb/internal/logging"
+)
+
+func TestSymbolIDRoundTrip(t *testing.T) {
+	tests := []struct {
+		path string
+		name string
+		kind string
+		line int
+	}{
+		{"internal/query/engine.go", "Engine", "type", 42},
+		{"cmd/ckb/main.go", "main", "function", 1},
+		{"odd:dir/file.py", "handler", "method", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			id := SymbolID(tt.path, tt.name, tt.kind, tt.line)
+			path, name, kind, line, err := ParseSymbolID(id)
+			if err != nil {
+				t.Fatalf("ParseSymbolID(%q) error: %v", id, err)
+			}
+			if path != tt.path || name != tt.name || kind != tt.kind || line != tt.line {
+				t.Errorf("ParseSymbolID(%q) = (%q, %q, %q, %d)", id, path, name, kind, line)
+			}
+		})
+	}
+}
+
+func TestParseSymbolIDInvalid(t *testing.T) {
+	for _, id := range []string{
+		"scip-go gomod ckb . Engine#",
+		"ts-",
+		"ts-a.go:x:function:Foo",
+		"ts-a.go:3:function:",
+	} {
+		if _, _, _, _, err := ParseSymbolID(id); err == nil {
+			t.Errorf("ParseSymbolID(%q) expected error", id)
+		}
+	}
+}
+
+func TestTreesitterAdapter(t *testing.T) {
+	repoRoot := t.TempDir()
+	writeFile(t, repoRoot, "pkg/handler.go", `package pkg
+
+type Handler struct{}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+`)
+	writeFile(t, repoRoot, "cmd/main.go", `package main
+
+func main() {
+	h := pkg.NewHandler()
+	_ = h
+}
+`)
+
+	adapter := NewTreesitterAdapter(repoRoot, logging.NewLogger(logging.Config{Level: logging.ErrorLevel}))
+	if !adapter.IsAvailable() {
+		t.Skip("tree-sitter not available")
+	}
+	ctx := context.Background()
+
+	result, err := adapter.SearchSymbols(ctx, "", backends.SearchOptions{Scope: []string{"pkg/handler.go"}})
+	if err != nil {
+		t.Fatalf("SearchSymbols error: %v", err)
+	}
+	if len(result.Symbols) != 2 {
+		t.Fatalf("expected 2 symbols in pkg/handler.go, got %d", len(result.Symbols))
+	}
+	if result.Completeness.Score >= 0.95 {
+		t.Errorf("completeness %.2f should reflect syntactic analysis", result.Completeness.Score)
+	}
+
+	var ctorID string
+	for _, sym := range result.Symbols {
+		if sym.Location.Path != "pkg/handler.go" {
+			t.Errorf("symbol %s has path %q, want repo-relative", sym.Name, sym.Location.Path)
+		}
+		if sym.Name == "NewHandler" {
+			ctorID = sym.StableID
+		}
+	}
+	if ctorID == "" {
+		t.Fatal("NewHandler not found")
+	}
+
+	sym, err := adapter.GetSymbol(ctx, ctorID)
+	if err != nil {
+		t.Fatalf("GetSymbol(%q) error: %v", ctorID, err)
+	}
+	if sym.Name != "NewHandler" || sym.Location.Line != 5 {
+		t.Errorf("GetSymbol = %s at line %d, want NewHandler at line 5", sym.Name, sym.Location.Line)
+	}
+
+	refs, err := adapter.FindReferences(ctx, ctorID, backends.RefOptions{Scope: []string{"cmd"}})
+	if err != nil {
+		t.Fatalf("FindReferences error: %v", err)
+	}
+	if len(refs.References) != 1 || refs.References[0].Location.Path != "cmd/main.go" {
+		t.Errorf("expected one reference in cmd/main.go, got %+v", refs.References)
+	}
+
+	refs, err = adapter.FindReferences(ctx, ctorID, backends.RefOptions{IncludeDeclaration: true})
+	if err != nil {
+		t.Fatalf("FindReferences error: %v", err)
+	}
+	if len(refs.References) != 1 || refs.References[0].Kind != "definition" {
+		t.Errorf("unscoped lookup should only search the defining file, got %+v", refs.References)
+	}
+
+	if _, err := adapter.GetSymbol(ctx, SymbolID("pkg/handler.go", "Missing", "function", 1)); err == nil {
+		t.Error("expected error for unknown symbol")
+	}
+
+	// IDs are client-supplied; their paths must stay inside the repo
+	outside := t.TempDir()
+	writeFile(t, outside, "secret.go", "package secret\n\nfunc Leak() {}\n")
+	escapes := []string{
+		SymbolID("../"+filepath.Base(outside)+"/secret.go", "Leak", "function", 3),
+		SymbolID(filepath.Join(outside, "secret.go"), "Leak", "function", 3),
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret.go"), filepath.Join(repoRoot, "pkg", "link.go")); err == nil {
+		escapes = append(escapes, SymbolID("pkg/link.go", "Leak", "function", 3))
+	}
+	for _, id := range escapes {
+		if sym, err := adapter.GetSymbol(ctx, id); err == nil {
+			t.Errorf("GetSymbol(%q) = %s, want an error for a path outside the repo", id, sym.Name)
+		}
+		if refs, err := adapter.FindReferences(ctx, id, backends.RefOptions{IncludeDeclaration: true}); err == nil {
+			t.Errorf("FindReferences(%q) = %d refs, want an error for a path outside the repo", id, len(refs.References))
+		}
+	}
+
+	// Scoped files reached through a symlink are skipped too
+	if _, err := os.Lstat(filepath.Join(repoRoot, "pkg", "link.go")); err == nil {
+		refs, err := adapter.FindReferences(ctx, SymbolID("pkg/handler.go", "Leak", "function", 1), backends.RefOptions{Scope: []string{"pkg"}})
+		if err != nil {
+			t.Fatalf("FindReferences failed: %v", err)
+		}
+		for _, ref := range refs.References {
+			if ref.Location.Path == "pkg/link.go" {
+				t.Errorf("reference read through a symlink out of the repo: %+v", ref)
+			}
+		}
+	}
+}
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
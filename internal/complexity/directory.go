@@ -0,0 +1,139 @@
+package complexity
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultMaxDirectoryFiles caps the files AnalyzeDirectory analyzes, so
+	// pointing it at a monorepo root can't run away.
+	DefaultMaxDirectoryFiles = 500
+
+	// defaultDirectoryWorkers is the number of files analyzed concurrently.
+	defaultDirectoryWorkers = 4
+)
+
+// DirectoryOptions controls AnalyzeDirectory.
+type DirectoryOptions struct {
+	MaxFiles int // Files analyzed at most (default: DefaultMaxDirectoryFiles)
+	Workers  int // Concurrent analyses (default: 4)
+}
+
+// SkippedFile is a file AnalyzeDirectory could not analyze.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// DirectoryComplexity holds the results of analyzing a directory tree.
+type DirectoryComplexity struct {
+	// Files contains the files analyzed successfully, in path order
+	Files []*FileComplexity `json:"files"`
+
+	// Skipped lists files whose analysis failed
+	Skipped []SkippedFile `json:"skipped,omitempty"`
+
+	// SourceFiles is the number of supported source files found
+	SourceFiles int `json:"sourceFiles"`
+
+	// Truncated is set when SourceFiles exceeded MaxFiles and only the
+	// first MaxFiles (in path order) were analyzed
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// AnalyzeDirectory analyzes every supported source file under dir with a
+// bounded pool of workers. Hidden directories, vendor and node_modules are
+// not descended into. A file that fails to analyze is listed in Skipped
+// rather than failing the whole directory.
+func AnalyzeDirectory(ctx context.Context, dir string, opts DirectoryOptions) (*DirectoryComplexity, error) {
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = DefaultMaxDirectoryFiles
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = defaultDirectoryWorkers
+	}
+
+	paths, err := sourceFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DirectoryComplexity{SourceFiles: len(paths)}
+	if len(paths) > opts.MaxFiles {
+		paths = paths[:opts.MaxFiles]
+		result.Truncated = true
+	}
+
+	files := make([]*FileComplexity, len(paths))
+	errs := make([]error, len(paths))
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Workers && w < len(paths); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Parsers aren't safe for concurrent use, so each worker has its own
+			analyzer := NewAnalyzer()
+			for i := range work {
+				files[i], errs[i] = analyzer.AnalyzeFile(ctx, paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, path := range paths {
+		switch {
+		case errs[i] != nil:
+			result.Skipped = append(result.Skipped, SkippedFile{Path: path, Reason: errs[i].Error()})
+		case files[i] == nil:
+			result.Skipped = append(result.Skipped, SkippedFile{Path: path, Reason: "no result"})
+		case files[i].Error != "":
+			result.Skipped = append(result.Skipped, SkippedFile{Path: path, Reason: files[i].Error})
+		default:
+			result.Files = append(result.Files, files[i])
+		}
+	}
+	return result, nil
+}
+
+// sourceFiles returns the supported source files under dir, sorted.
+func sourceFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := LanguageFromExtension(strings.ToLower(filepath.Ext(path))); ok {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
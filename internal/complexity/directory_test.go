@@ -0,0 +1,61 @@
+//go:build cgo
+
+package complexity
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzeDirectory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.go", "package a\n\nfunc A(x int) int {\n\tif x > 0 {\n\t\treturn 1\n\t}\n\treturn 0\n}\n")
+	write("sub/b.py", "def b():\n    return 1\n")
+	write("README.md", "# not source\n")
+	write("vendor/v.go", "package v\n\nfunc V() {}\n")
+	write(".hidden/h.go", "package h\n\nfunc H() {}\n")
+	if err := os.Symlink(filepath.Join(dir, "missing.go"), filepath.Join(dir, "broken.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := AnalyzeDirectory(context.Background(), dir, DirectoryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SourceFiles != 3 || result.Truncated {
+		t.Errorf("SourceFiles = %d, Truncated = %v; want 3 and false", result.SourceFiles, result.Truncated)
+	}
+	if len(result.Files) != 2 {
+		t.Fatalf("expected 2 analyzed files, got %d", len(result.Files))
+	}
+	if result.Files[0].Path != filepath.Join(dir, "a.go") || result.Files[0].MaxCyclomatic != 2 {
+		t.Errorf("first file = %s (max cyclomatic %d)", result.Files[0].Path, result.Files[0].MaxCyclomatic)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Path != filepath.Join(dir, "broken.go") {
+		t.Errorf("skipped = %+v, want the broken symlink", result.Skipped)
+	}
+
+	capped, err := AnalyzeDirectory(context.Background(), dir, DirectoryOptions{MaxFiles: 1, Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !capped.Truncated || capped.SourceFiles != 3 || len(capped.Files)+len(capped.Skipped) != 1 {
+		t.Errorf("capped = %+v", capped)
+	}
+
+	if _, err := AnalyzeDirectory(context.Background(), filepath.Join(dir, "nope"), DirectoryOptions{}); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
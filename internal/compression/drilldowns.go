@@ -91,6 +91,8 @@ func generateTruncationDrilldowns(ctx *DrilldownContext) []output.Drilldown {
 			drilldowns = append(drilldowns, output.Drilldown{
 				Label:          fmt.Sprintf("Explore top module: %s", ctx.TopModule.Name),
 				Query:          fmt.Sprintf("getModuleOverview %s", ctx.TopModule.ModuleId),
+				Tool:           "getModuleOverview",
+				Params:         map[string]interface{}{"path": ctx.TopModule.ModuleId},
 				RelevanceScore: 0.9,
 			})
 		}
@@ -101,6 +103,8 @@ func generateTruncationDrilldowns(ctx *DrilldownContext) []output.Drilldown {
 			drilldowns = append(drilldowns, output.Drilldown{
 				Label:          "Scope to specific module",
 				Query:          fmt.Sprintf("findReferences %s --scope=%s", ctx.SymbolId, ctx.TopModule.ModuleId),
+				Tool:           "findReferences",
+				Params:         map[string]interface{}{"symbolId": ctx.SymbolId, "scope": ctx.TopModule.ModuleId},
 				RelevanceScore: 0.85,
 			})
 		}
@@ -111,6 +115,8 @@ func generateTruncationDrilldowns(ctx *DrilldownContext) []output.Drilldown {
 			drilldowns = append(drilldowns, output.Drilldown{
 				Label:          "Get first page of references",
 				Query:          fmt.Sprintf("findReferences %s --limit=100", ctx.SymbolId),
+				Tool:           "findReferences",
+				Params:         map[string]interface{}{"symbolId": ctx.SymbolId, "limit": 100},
 				RelevanceScore: 0.8,
 			})
 		}
@@ -138,6 +144,7 @@ func generateCompletenessDrilldowns(ctx *DrilldownContext) []output.Drilldown {
 		drilldowns = append(drilldowns, output.Drilldown{
 			Label:          "Check workspace status",
 			Query:          "getStatus",
+			Tool:           "getStatus",
 			RelevanceScore: 0.7,
 		})
 	}
@@ -156,6 +163,8 @@ func generateCompletenessDrilldowns(ctx *DrilldownContext) []output.Drilldown {
 		drilldowns = append(drilldowns, output.Drilldown{
 			Label:          "Get maximum results (slower)",
 			Query:          fmt.Sprintf("findReferences %s --merge=union", ctx.SymbolId),
+			Tool:           "findReferences",
+			Params:         map[string]interface{}{"symbolId": ctx.SymbolId, "merge": "union"},
 			RelevanceScore: 0.65,
 		})
 	}
@@ -172,6 +181,7 @@ func generateFreshnessDrilldowns(ctx *DrilldownContext) []output.Drilldown {
 		drilldowns = append(drilldowns, output.Drilldown{
 			Label:          "Regenerate SCIP index",
 			Query:          "doctor --check=scip",
+			Tool:           "doctor",
 			RelevanceScore: 0.6,
 		})
 	}
@@ -4,11 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+
+	"ckb/internal/impact"
 )
 
 // EnvOverride records an environment variable override that was applied
@@ -53,6 +58,165 @@ type Config struct {
 	// v7.2 Analysis tier
 	// Values: "auto", "fast", "standard", "full"
 	Tier string `json:"tier,omitempty" mapstructure:"tier"`
+
+	// v7.4 Traversal depth limits
+	Traversal TraversalConfig `json:"traversal" mapstructure:"traversal"`
+
+	// v7.4 Deployment-specific metadata stamped on every response's provenance
+	// (e.g. environment, index build ID, tenant)
+	ProvenanceExtra map[string]interface{} `json:"provenanceExtra,omitempty" mapstructure:"provenanceExtra"`
+
+	// v7.4 Symbol identity resolution
+	Identity IdentityConfig `json:"identity" mapstructure:"identity"`
+
+	// v7.4 Response warning policy
+	Warnings WarningsConfig `json:"warnings" mapstructure:"warnings"`
+
+	// v7.4 Entrypoint detection hints
+	Entrypoints EntrypointsConfig `json:"entrypoints" mapstructure:"entrypoints"`
+
+	// v7.4 MCP tool execution limits
+	MCP MCPConfig `json:"mcp" mapstructure:"mcp"`
+
+	// v7.4 File role overrides: maps a role (see FileRoleNames) to path
+	// patterns that classify a file as that role ahead of the built-in
+	// heuristics, e.g. {"core": ["app/"], "test": ["e2e/"]}. A pattern is a
+	// case-insensitive path substring, a glob over the whole path when it
+	// contains * or ? (** spans directories), or a regular expression when
+	// it starts with "re:". Empty by default, which keeps the built-ins.
+	FileRoles map[string][]string `json:"fileRoles,omitempty" mapstructure:"fileRoles"`
+
+	// v7.4 Risk score history, read by getRiskTrend
+	RiskHistory RiskHistoryConfig `json:"riskHistory" mapstructure:"riskHistory"`
+
+	// v7.4 Score-to-level mapping for impact risk. Unset (zero) medium and
+	// high minimums use the impact defaults; an unset criticalMin leaves
+	// the critical level out.
+	RiskThresholds impact.RiskThresholds `json:"riskThresholds" mapstructure:"riskThresholds"`
+}
+
+// RiskHistoryConfig controls recording of analyzeImpact risk scores (v7.4)
+type RiskHistoryConfig struct {
+	// Enabled records the risk score of every analyzeImpact run. Off by
+	// default, so read-only deployments never write.
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+
+	// MaxPerSymbol bounds the scores kept per symbol; older ones are
+	// pruned. 0 uses DefaultRiskHistoryPerSymbol.
+	MaxPerSymbol int `json:"maxPerSymbol,omitempty" mapstructure:"maxPerSymbol"`
+}
+
+// DefaultRiskHistoryPerSymbol is how many risk scores are kept per symbol
+// when riskHistory.maxPerSymbol is unset
+const DefaultRiskHistoryPerSymbol = 100
+
+// FileRoleNames lists the roles fileRoles patterns can assign, in the order
+// they are tried; it mirrors the precedence of the built-in heuristics.
+var FileRoleNames = []string{"test", "config", "unknown", "entrypoint", "core"}
+
+// FileRoleRegexPrefix marks a fileRoles pattern as a regular expression
+const FileRoleRegexPrefix = "re:"
+
+// MCPConfig bounds MCP tool calls (v7.4)
+type MCPConfig struct {
+	// ToolTimeoutMs is how long a tool call may run before it is canceled
+	// and reported as a timeout. 0 uses DefaultToolTimeoutMs.
+	ToolTimeoutMs int `json:"toolTimeoutMs,omitempty" mapstructure:"toolTimeoutMs"`
+
+	// ToolTimeoutsMs overrides ToolTimeoutMs for individual tools by name,
+	// e.g. {"traceUsage": 60000}
+	ToolTimeoutsMs map[string]int `json:"toolTimeoutsMs,omitempty" mapstructure:"toolTimeoutsMs"`
+}
+
+// DefaultToolTimeoutMs is the MCP tool call timeout when none is configured
+const DefaultToolTimeoutMs = 30000
+
+// DefaultToolTimeoutsMs holds built-in timeouts for tools that are
+// expected to outlast DefaultToolTimeoutMs
+var DefaultToolTimeoutsMs = map[string]int{
+	"federationSyncRemote": 150000, // Bounds its own remote sync at two minutes
+}
+
+// ToolTimeout returns the timeout for a tool call: its configured
+// override, then its built-in one, then the configured or default timeout
+func (c MCPConfig) ToolTimeout(tool string) time.Duration {
+	if ms := c.ToolTimeoutsMs[tool]; ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	if ms := DefaultToolTimeoutsMs[tool]; ms > 0 && ms > c.ToolTimeoutMs {
+		return time.Duration(ms) * time.Millisecond
+	}
+	if c.ToolTimeoutMs > 0 {
+		return time.Duration(c.ToolTimeoutMs) * time.Millisecond
+	}
+	return DefaultToolTimeoutMs * time.Millisecond
+}
+
+// EntrypointsConfig tunes listEntrypoints detection (v7.4)
+type EntrypointsConfig struct {
+	// PathHints maps an entrypoint type (cli, api, job, event) to directory
+	// patterns where such entrypoints live, e.g. {"cli": ["apps/*"]}. A
+	// pattern is matched segment by segment against a consecutive run of
+	// directories anywhere in the path, with globs per segment. Hints add
+	// to the built-in ones.
+	PathHints map[string][]string `json:"pathHints,omitempty" mapstructure:"pathHints"`
+
+	// MaxFanOut is the fan-out above which an entrypoint is flagged as a
+	// potential orchestration hotspot. 0 uses DefaultEntrypointMaxFanOut.
+	MaxFanOut int `json:"maxFanOut,omitempty" mapstructure:"maxFanOut"`
+}
+
+// DefaultEntrypointMaxFanOut is the fan-out limit when none is configured
+const DefaultEntrypointMaxFanOut = 100
+
+// FanOutLimit returns the configured fan-out limit, or the default
+func (c EntrypointsConfig) FanOutLimit() int {
+	if c.MaxFanOut <= 0 {
+		return DefaultEntrypointMaxFanOut
+	}
+	return c.MaxFanOut
+}
+
+// DefaultEntrypointPathHints are always applied, whatever the config says
+var DefaultEntrypointPathHints = map[string][]string{
+	"cli": {"cmd"},
+	"job": {"*worker*", "*job*"},
+}
+
+// EntrypointTypes lists the entrypoint types path hints can be declared for
+var EntrypointTypes = []string{"cli", "api", "job", "event"}
+
+// PathHintsFor returns the built-in and configured hints for an entrypoint
+// type, without duplicates
+func (c EntrypointsConfig) PathHintsFor(entrypointType string) []string {
+	var hints []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{DefaultEntrypointPathHints[entrypointType], c.PathHints[entrypointType]} {
+		for _, h := range list {
+			h = strings.Trim(strings.TrimSpace(h), "/")
+			if h == "" || seen[h] {
+				continue
+			}
+			seen[h] = true
+			hints = append(hints, h)
+		}
+	}
+	return hints
+}
+
+// WarningsConfig controls which response warnings are reported (v7.4)
+type WarningsConfig struct {
+	// MinSeverity drops warnings less severe than this: "error", "warning"
+	// or "info". Empty reports everything.
+	MinSeverity string `json:"minSeverity,omitempty" mapstructure:"minSeverity"`
+}
+
+// IdentityConfig contains symbol identity resolution settings (v7.4)
+type IdentityConfig struct {
+	// FingerprintFallback treats stable IDs as unstable: an ID that no longer
+	// resolves is re-resolved by its symbol's name, container and kind, at
+	// reduced confidence. Useful for SCIP emitters whose IDs encode positions.
+	FingerprintFallback bool `json:"fingerprintFallback" mapstructure:"fingerprintFallback"`
 }
 
 // BackendsConfig contains backend-specific configuration
@@ -66,6 +230,18 @@ type BackendsConfig struct {
 type ScipConfig struct {
 	Enabled   bool   `json:"enabled" mapstructure:"enabled"`
 	IndexPath string `json:"indexPath" mapstructure:"indexPath"`
+	// AutoReindex starts a background reindex job when a SCIP-backed tool
+	// finds the index stale. Off by default: indexing can be slow and CPU
+	// heavy.
+	AutoReindex bool `json:"autoReindex,omitempty" mapstructure:"autoReindex"`
+	// ReindexCommand overrides the indexer command used by AutoReindex.
+	// Empty means the command `ckb index` runs for the detected language.
+	ReindexCommand string `json:"reindexCommand,omitempty" mapstructure:"reindexCommand"`
+	// RequireFreshIndex makes SCIP-backed tools fail with INDEX_STALE instead
+	// of serving results from an index more than MaxCommitsBehind commits
+	// behind HEAD. With MaxCommitsBehind 0, uncommitted changes also count.
+	RequireFreshIndex bool `json:"requireFreshIndex,omitempty" mapstructure:"requireFreshIndex"`
+	MaxCommitsBehind  int  `json:"maxCommitsBehind,omitempty" mapstructure:"maxCommitsBehind"`
 }
 
 // LspConfig contains LSP backend configuration
@@ -125,6 +301,8 @@ type CacheConfig struct {
 	QueryTtlSeconds    int `json:"queryTtlSeconds" mapstructure:"queryTtlSeconds"`
 	ViewTtlSeconds     int `json:"viewTtlSeconds" mapstructure:"viewTtlSeconds"`
 	NegativeTtlSeconds int `json:"negativeTtlSeconds" mapstructure:"negativeTtlSeconds"`
+	// CallGraphEntries caps the in-memory call graph cache (0 = default 256)
+	CallGraphEntries int `json:"callGraphEntries" mapstructure:"callGraphEntries"`
 }
 
 // BudgetConfig contains response budget configuration
@@ -134,6 +312,7 @@ type BudgetConfig struct {
 	MaxImpactItems      int `json:"maxImpactItems" mapstructure:"maxImpactItems"`
 	MaxDrilldowns       int `json:"maxDrilldowns" mapstructure:"maxDrilldowns"`
 	EstimatedMaxTokens  int `json:"estimatedMaxTokens" mapstructure:"estimatedMaxTokens"`
+	MaxSymbolTextLength int `json:"maxSymbolTextLength" mapstructure:"maxSymbolTextLength"` // v7.4: names/signatures in tool output
 }
 
 // BackendLimitsConfig contains backend limits
@@ -141,6 +320,14 @@ type BackendLimitsConfig struct {
 	MaxRefsPerQuery    int `json:"maxRefsPerQuery" mapstructure:"maxRefsPerQuery"`
 	MaxFilesScanned    int `json:"maxFilesScanned" mapstructure:"maxFilesScanned"`
 	MaxUnionModeTimeMs int `json:"maxUnionModeTimeMs" mapstructure:"maxUnionModeTimeMs"`
+
+	// Modules processed in parallel when building the architecture view;
+	// 0 uses the CPU count, capped at 8
+	ArchitectureConcurrency int `json:"architectureConcurrency,omitempty" mapstructure:"architectureConcurrency"`
+
+	// Symbols analyzed in parallel by analyzeImpactBatch and
+	// analyzeBranchImpact; 0 uses 4
+	ImpactBatchConcurrency int `json:"impactBatchConcurrency,omitempty" mapstructure:"impactBatchConcurrency"`
 }
 
 // PrivacyConfig contains privacy settings
@@ -218,6 +405,7 @@ type TelemetryServicePattern struct {
 type TelemetryAggregationConfig struct {
 	BucketSize          string `json:"bucketSize" mapstructure:"bucketSize"` // "daily" | "weekly" | "monthly"
 	RetentionDays       int    `json:"retentionDays" mapstructure:"retentionDays"`
+	RawRetentionDays    int    `json:"rawRetentionDays" mapstructure:"rawRetentionDays"` // v7.4: raw rows; daily rollups keep RetentionDays
 	MinCallsToStore     int    `json:"minCallsToStore" mapstructure:"minCallsToStore"`
 	StoreCallers        bool   `json:"storeCallers" mapstructure:"storeCallers"`
 	MaxCallersPerSymbol int    `json:"maxCallersPerSymbol" mapstructure:"maxCallersPerSymbol"`
@@ -245,6 +433,68 @@ type TelemetryAttributesConfig struct {
 	LineKeys      []string `json:"lineKeys" mapstructure:"lineKeys"`
 }
 
+// TraversalConfig contains per-tool graph traversal depth limits (v7.4)
+type TraversalConfig struct {
+	CallGraph  DepthLimits `json:"callGraph" mapstructure:"callGraph"`
+	TraceUsage DepthLimits `json:"traceUsage" mapstructure:"traceUsage"`
+	Impact     DepthLimits `json:"impact" mapstructure:"impact"`
+}
+
+// DepthLimits holds the default and maximum traversal depth for a tool
+type DepthLimits struct {
+	Default int `json:"default" mapstructure:"default"`
+	Max     int `json:"max" mapstructure:"max"`
+}
+
+// Built-in traversal limits, used when the config leaves a value unset
+var (
+	DefaultCallGraphDepth  = DepthLimits{Default: 1, Max: 4}
+	DefaultTraceUsageDepth = DepthLimits{Default: 5, Max: 5}
+	DefaultImpactDepth     = DepthLimits{Default: 2, Max: 5}
+)
+
+// withFallback fills zero fields from the built-in limits. Config files
+// written before the traversal block existed leave these fields unset.
+func (d DepthLimits) withFallback(fallback DepthLimits) DepthLimits {
+	if d.Default <= 0 {
+		d.Default = fallback.Default
+	}
+	if d.Max <= 0 {
+		d.Max = fallback.Max
+	}
+	if d.Default > d.Max {
+		d.Default = d.Max
+	}
+	return d
+}
+
+// Resolve applies the limits to a requested depth. A non-positive request
+// selects the default; requests above the maximum are clamped and reported.
+func (d DepthLimits) Resolve(requested int) (depth int, clamped bool) {
+	if requested <= 0 {
+		return d.Default, false
+	}
+	if requested > d.Max {
+		return d.Max, true
+	}
+	return requested, false
+}
+
+// CallGraphLimits returns the effective depth limits for getCallGraph
+func (t TraversalConfig) CallGraphLimits() DepthLimits {
+	return t.CallGraph.withFallback(DefaultCallGraphDepth)
+}
+
+// TraceUsageLimits returns the effective depth limits for traceUsage
+func (t TraversalConfig) TraceUsageLimits() DepthLimits {
+	return t.TraceUsage.withFallback(DefaultTraceUsageDepth)
+}
+
+// ImpactLimits returns the effective depth limits for analyzeImpact
+func (t TraversalConfig) ImpactLimits() DepthLimits {
+	return t.Impact.withFallback(DefaultImpactDepth)
+}
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -282,20 +532,22 @@ func DefaultConfig() *Config {
 			},
 		},
 		QueryPolicy: QueryPolicyConfig{
-			BackendPreferenceOrder: []string{"scip", "glean", "lsp"},
+			BackendPreferenceOrder: []string{"scip", "glean", "lsp", "treesitter"},
 			AlwaysUse:              []string{"git"},
 			MaxInFlightPerBackend: map[string]int{
-				"scip": 10,
-				"lsp":  3,
-				"git":  5,
+				"scip":       10,
+				"lsp":        3,
+				"git":        5,
+				"treesitter": 5,
 			},
 			CoalesceWindowMs:    50,
 			MergeMode:           "prefer-first",
 			SupplementThreshold: 0.8,
 			TimeoutMs: map[string]int{
-				"scip": 5000,
-				"lsp":  15000,
-				"git":  5000,
+				"scip":       5000,
+				"lsp":        15000,
+				"git":        5000,
+				"treesitter": 5000,
 			},
 		},
 		LspSupervisor: LspSupervisorConfig{
@@ -319,6 +571,7 @@ func DefaultConfig() *Config {
 			QueryTtlSeconds:    300,
 			ViewTtlSeconds:     3600,
 			NegativeTtlSeconds: 60,
+			CallGraphEntries:   256,
 		},
 		Budget: BudgetConfig{
 			MaxModules:          10,
@@ -326,6 +579,7 @@ func DefaultConfig() *Config {
 			MaxImpactItems:      20,
 			MaxDrilldowns:       5,
 			EstimatedMaxTokens:  4000,
+			MaxSymbolTextLength: 512,
 		},
 		BackendLimits: BackendLimitsConfig{
 			MaxRefsPerQuery:    10000,
@@ -369,6 +623,7 @@ func DefaultConfig() *Config {
 			Aggregation: TelemetryAggregationConfig{
 				BucketSize:          "weekly",
 				RetentionDays:       180,
+				RawRetentionDays:    30,
 				MinCallsToStore:     1,
 				StoreCallers:        false,
 				MaxCallersPerSymbol: 20,
@@ -404,6 +659,11 @@ func DefaultConfig() *Config {
 				LineKeys:      []string{"code.lineno", "code.line_number"},
 			},
 		},
+		Traversal: TraversalConfig{
+			CallGraph:  DefaultCallGraphDepth,
+			TraceUsage: DefaultTraceUsageDepth,
+			Impact:     DefaultImpactDepth,
+		},
 	}
 }
 
@@ -620,6 +880,11 @@ func applyOverride(cfg *Config, path string, value interface{}) bool {
 				cfg.Cache.NegativeTtlSeconds = v
 				return true
 			}
+		case "callGraphEntries":
+			if v, ok := value.(int); ok {
+				cfg.Cache.CallGraphEntries = v
+				return true
+			}
 		}
 	case "budget":
 		if len(parts) < 2 {
@@ -763,6 +1028,126 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Traversal limits: defaults must not exceed maximums
+	traversal := []struct {
+		field  string
+		limits DepthLimits
+	}{
+		{"traversal.callGraph", c.Traversal.CallGraph},
+		{"traversal.traceUsage", c.Traversal.TraceUsage},
+		{"traversal.impact", c.Traversal.Impact},
+	}
+	for _, t := range traversal {
+		if t.limits.Default < 0 || t.limits.Max < 0 {
+			return &ConfigError{Field: t.field, Message: "depth limits must not be negative"}
+		}
+		if t.limits.Max > 0 && t.limits.Default > t.limits.Max {
+			return &ConfigError{
+				Field:   t.field,
+				Message: fmt.Sprintf("default depth %d exceeds max depth %d", t.limits.Default, t.limits.Max),
+			}
+		}
+	}
+
+	if c.RiskHistory.MaxPerSymbol < 0 {
+		return &ConfigError{Field: "riskHistory.maxPerSymbol", Message: "must not be negative"}
+	}
+
+	// Risk thresholds: within [0, 1] and ascending once defaults apply
+	thresholds := c.RiskThresholds.Effective()
+	for _, t := range []struct {
+		field string
+		value float64
+	}{
+		{"riskThresholds.mediumMin", thresholds.MediumMin},
+		{"riskThresholds.highMin", thresholds.HighMin},
+		{"riskThresholds.criticalMin", thresholds.CriticalMin},
+	} {
+		if t.value < 0 || t.value > 1 {
+			return &ConfigError{Field: t.field, Message: fmt.Sprintf("must be between 0 and 1, got %v", t.value)}
+		}
+	}
+	if thresholds.MediumMin >= thresholds.HighMin {
+		return &ConfigError{
+			Field:   "riskThresholds.highMin",
+			Message: fmt.Sprintf("must be above mediumMin %v, got %v", thresholds.MediumMin, thresholds.HighMin),
+		}
+	}
+	if thresholds.CriticalMin != 0 && thresholds.CriticalMin <= thresholds.HighMin {
+		return &ConfigError{
+			Field:   "riskThresholds.criticalMin",
+			Message: fmt.Sprintf("must be above highMin %v, got %v", thresholds.HighMin, thresholds.CriticalMin),
+		}
+	}
+
+	switch c.Warnings.MinSeverity {
+	case "", "error", "warning", "info":
+	default:
+		return &ConfigError{
+			Field:   "warnings.minSeverity",
+			Message: fmt.Sprintf("unknown severity %q, expected error, warning or info", c.Warnings.MinSeverity),
+		}
+	}
+
+	if c.Entrypoints.MaxFanOut < 0 {
+		return &ConfigError{
+			Field:   "entrypoints.maxFanOut",
+			Message: "must not be negative",
+		}
+	}
+
+	if c.MCP.ToolTimeoutMs < 0 {
+		return &ConfigError{Field: "mcp.toolTimeoutMs", Message: "must not be negative"}
+	}
+	for tool, ms := range c.MCP.ToolTimeoutsMs {
+		if ms < 0 {
+			return &ConfigError{Field: "mcp.toolTimeoutsMs." + tool, Message: "must not be negative"}
+		}
+	}
+
+	for entrypointType, hints := range c.Entrypoints.PathHints {
+		field := "entrypoints.pathHints." + entrypointType
+		known := false
+		for _, t := range EntrypointTypes {
+			known = known || t == entrypointType
+		}
+		if !known {
+			return &ConfigError{
+				Field:   field,
+				Message: fmt.Sprintf("unknown entrypoint type %q, expected one of %v", entrypointType, EntrypointTypes),
+			}
+		}
+		for _, h := range hints {
+			if _, err := path.Match(h, ""); err != nil {
+				return &ConfigError{Field: field, Message: fmt.Sprintf("invalid pattern %q: %v", h, err)}
+			}
+		}
+	}
+
+	for role, patterns := range c.FileRoles {
+		field := "fileRoles." + role
+		known := false
+		for _, r := range FileRoleNames {
+			known = known || r == role
+		}
+		if !known {
+			return &ConfigError{
+				Field:   field,
+				Message: fmt.Sprintf("unknown file role %q, expected one of %v", role, FileRoleNames),
+			}
+		}
+		for _, p := range patterns {
+			if strings.TrimSpace(p) == "" {
+				return &ConfigError{Field: field, Message: "patterns must not be empty"}
+			}
+			if expr, ok := strings.CutPrefix(p, FileRoleRegexPrefix); ok {
+				if _, err := regexp.Compile(expr); err != nil {
+					return &ConfigError{Field: field, Message: fmt.Sprintf("invalid pattern %q: %v", p, err)}
+				}
+			}
+		}
+	}
+
 	// Add more validation as needed
 	return nil
 }
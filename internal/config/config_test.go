@@ -3,7 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	"ckb/internal/impact"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -612,6 +616,7 @@ func TestApplyOverride_AllPaths(t *testing.T) {
 		{"cache.queryTtlSeconds", "cache.queryTtlSeconds", 600, func(t *testing.T, cfg *Config) bool { return cfg.Cache.QueryTtlSeconds == 600 }},
 		{"cache.viewTtlSeconds", "cache.viewTtlSeconds", 7200, func(t *testing.T, cfg *Config) bool { return cfg.Cache.ViewTtlSeconds == 7200 }},
 		{"cache.negativeTtlSeconds", "cache.negativeTtlSeconds", 120, func(t *testing.T, cfg *Config) bool { return cfg.Cache.NegativeTtlSeconds == 120 }},
+		{"cache.callGraphEntries", "cache.callGraphEntries", 64, func(t *testing.T, cfg *Config) bool { return cfg.Cache.CallGraphEntries == 64 }},
 		// Budget
 		{"budget.maxModules", "budget.maxModules", 50, func(t *testing.T, cfg *Config) bool { return cfg.Budget.MaxModules == 50 }},
 		{"budget.maxSymbolsPerModule", "budget.maxSymbolsPerModule", 10, func(t *testing.T, cfg *Config) bool { return cfg.Budget.MaxSymbolsPerModule == 10 }},
@@ -939,3 +944,216 @@ func TestLoadConfigWithDetails_FromStandardLocation(t *testing.T) {
 		t.Errorf("Tier = %q, want %q", result.Config.Tier, "fast")
 	}
 }
+
+func TestTraversalConfig(t *testing.T) {
+	cfg := DefaultConfig()
+
+	limits := cfg.Traversal.CallGraphLimits()
+	if limits.Default != 1 || limits.Max != 4 {
+		t.Errorf("CallGraphLimits() = %+v, want {1 4}", limits)
+	}
+
+	// Unset blocks (older config files) fall back to built-in limits
+	var empty TraversalConfig
+	if got := empty.TraceUsageLimits(); got != DefaultTraceUsageDepth {
+		t.Errorf("TraceUsageLimits() on empty config = %+v, want %+v", got, DefaultTraceUsageDepth)
+	}
+	if got := empty.ImpactLimits(); got != DefaultImpactDepth {
+		t.Errorf("ImpactLimits() on empty config = %+v, want %+v", got, DefaultImpactDepth)
+	}
+
+	// A raised max without a default keeps the built-in default
+	custom := TraversalConfig{Impact: DepthLimits{Max: 8}}
+	if got := custom.ImpactLimits(); got.Default != 2 || got.Max != 8 {
+		t.Errorf("ImpactLimits() = %+v, want {2 8}", got)
+	}
+}
+
+func TestDepthLimits_Resolve(t *testing.T) {
+	limits := DepthLimits{Default: 2, Max: 4}
+
+	tests := []struct {
+		requested   int
+		wantDepth   int
+		wantClamped bool
+	}{
+		{0, 2, false},
+		{-1, 2, false},
+		{3, 3, false},
+		{4, 4, false},
+		{9, 4, true},
+	}
+
+	for _, tt := range tests {
+		depth, clamped := limits.Resolve(tt.requested)
+		if depth != tt.wantDepth || clamped != tt.wantClamped {
+			t.Errorf("Resolve(%d) = (%d, %v), want (%d, %v)",
+				tt.requested, depth, clamped, tt.wantDepth, tt.wantClamped)
+		}
+	}
+}
+
+func TestConfig_ValidateTraversal(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Traversal.CallGraph = DepthLimits{Default: 6, Max: 4}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected error when default depth exceeds max")
+	}
+	if cfgErr, ok := err.(*ConfigError); !ok || cfgErr.Field != "traversal.callGraph" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestConfig_ValidateWarningSeverity(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Warnings.MinSeverity = "warning"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Warnings.MinSeverity = "critical"
+	err := cfg.Validate()
+	if cfgErr, ok := err.(*ConfigError); !ok || cfgErr.Field != "warnings.minSeverity" {
+		t.Errorf("expected warnings.minSeverity error, got %v", err)
+	}
+}
+
+func TestEntrypointsConfig_PathHintsFor(t *testing.T) {
+	cfg := EntrypointsConfig{PathHints: map[string][]string{
+		"cli": {"apps/*", "/cmd/", ""},
+		"api": {"services/*/main"},
+	}}
+
+	if got := cfg.PathHintsFor("cli"); !reflect.DeepEqual(got, []string{"cmd", "apps/*"}) {
+		t.Errorf("cli hints = %v", got)
+	}
+	if got := cfg.PathHintsFor("api"); !reflect.DeepEqual(got, []string{"services/*/main"}) {
+		t.Errorf("api hints = %v", got)
+	}
+	if got := (EntrypointsConfig{}).PathHintsFor("job"); len(got) != 2 {
+		t.Errorf("expected built-in job hints, got %v", got)
+	}
+}
+
+func TestConfig_ValidateEntrypointHints(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Entrypoints.PathHints = map[string][]string{"event": {"functions"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Entrypoints.PathHints = map[string][]string{"daemon": {"bin"}}
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "entrypoints.pathHints.daemon" {
+		t.Errorf("expected unknown type error, got %v", err)
+	}
+
+	cfg.Entrypoints.PathHints = map[string][]string{"cli": {"apps/["}}
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "entrypoints.pathHints.cli" {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+
+	cfg.Entrypoints.PathHints = nil
+	cfg.Entrypoints.MaxFanOut = -1
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "entrypoints.maxFanOut" {
+		t.Errorf("expected maxFanOut error, got %v", err)
+	}
+}
+
+func TestConfig_ValidateFileRoles(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FileRoles = map[string][]string{"core": {"app/"}, "test": {"e2e/**", `re:_it\.go$`}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.FileRoles = map[string][]string{"glue": {"adapters/"}}
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "fileRoles.glue" {
+		t.Errorf("expected unknown role error, got %v", err)
+	}
+
+	cfg.FileRoles = map[string][]string{"test": {"re:(unclosed"}}
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "fileRoles.test" {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+
+	cfg.FileRoles = map[string][]string{"core": {" "}}
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "fileRoles.core" {
+		t.Errorf("expected empty pattern error, got %v", err)
+	}
+}
+
+func TestEntrypointsConfig_FanOutLimit(t *testing.T) {
+	if got := (EntrypointsConfig{}).FanOutLimit(); got != DefaultEntrypointMaxFanOut {
+		t.Errorf("default limit = %d, want %d", got, DefaultEntrypointMaxFanOut)
+	}
+	if got := (EntrypointsConfig{MaxFanOut: 40}).FanOutLimit(); got != 40 {
+		t.Errorf("configured limit = %d, want 40", got)
+	}
+}
+
+func TestMCPConfig_ToolTimeout(t *testing.T) {
+	var cfg MCPConfig
+	if got := cfg.ToolTimeout("traceUsage"); got != DefaultToolTimeoutMs*time.Millisecond {
+		t.Errorf("default timeout = %v, want %dms", got, DefaultToolTimeoutMs)
+	}
+	if got := cfg.ToolTimeout("federationSyncRemote"); got != 150*time.Second {
+		t.Errorf("built-in timeout = %v, want 150s", got)
+	}
+
+	cfg = MCPConfig{ToolTimeoutMs: 5000, ToolTimeoutsMs: map[string]int{"traceUsage": 60000}}
+	if got := cfg.ToolTimeout("traceUsage"); got != time.Minute {
+		t.Errorf("per-tool timeout = %v, want 1m", got)
+	}
+	if got := cfg.ToolTimeout("getSymbol"); got != 5*time.Second {
+		t.Errorf("configured timeout = %v, want 5s", got)
+	}
+}
+
+func TestValidate_MCPToolTimeouts(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MCP.ToolTimeoutsMs = map[string]int{"traceUsage": -1}
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "mcp.toolTimeoutsMs.traceUsage" {
+		t.Errorf("expected per-tool timeout error, got %v", err)
+	}
+
+	cfg.MCP.ToolTimeoutsMs = nil
+	cfg.MCP.ToolTimeoutMs = -1
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "mcp.toolTimeoutMs" {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestValidate_RiskThresholds(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds impact.RiskThresholds
+		wantField  string
+	}{
+		{"unset", impact.RiskThresholds{}, ""},
+		{"high only", impact.RiskThresholds{HighMin: 0.5}, ""},
+		{"with critical", impact.RiskThresholds{MediumMin: 0.3, HighMin: 0.5, CriticalMin: 0.8}, ""},
+		{"high below default medium", impact.RiskThresholds{HighMin: 0.3}, "riskThresholds.highMin"},
+		{"medium above one", impact.RiskThresholds{MediumMin: 1.5}, "riskThresholds.mediumMin"},
+		{"negative critical", impact.RiskThresholds{CriticalMin: -0.2}, "riskThresholds.criticalMin"},
+		{"critical below high", impact.RiskThresholds{CriticalMin: 0.6}, "riskThresholds.criticalMin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RiskThresholds = tt.thresholds
+			err := cfg.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if cfgErr, ok := err.(*ConfigError); !ok || cfgErr.Field != tt.wantField {
+				t.Errorf("Validate() error = %v, want one for %s", err, tt.wantField)
+			}
+		})
+	}
+
+}
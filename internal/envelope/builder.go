@@ -1,6 +1,7 @@
 package envelope
 
 import (
+	"sort"
 	"strings"
 
 	"ckb/internal/output"
@@ -48,6 +49,8 @@ func (b *Builder) FromProvenance(p *query.Provenance) *Builder {
 	b.resp.Meta.Provenance = &Provenance{
 		Backends:    backends,
 		RepoStateID: p.RepoStateId,
+		FromCache:   p.FromCache,
+		Extra:       p.Extra,
 	}
 
 	// Set confidence from completeness
@@ -64,7 +67,7 @@ func (b *Builder) FromProvenance(p *query.Provenance) *Builder {
 
 	// Add warnings from provenance
 	for _, w := range p.Warnings {
-		b.resp.Warnings = append(b.resp.Warnings, Warning{Message: w})
+		b.resp.Warnings = append(b.resp.Warnings, Warning{Severity: w.Severity, Code: w.Code, Message: w.Text})
 	}
 
 	return b
@@ -140,13 +143,17 @@ func (b *Builder) SuggestCalls(drilldowns []output.Drilldown) *Builder {
 
 // Warning adds a warning message.
 func (b *Builder) Warning(msg string) *Builder {
-	b.resp.Warnings = append(b.resp.Warnings, Warning{Message: msg})
-	return b
+	return b.TypedWarning(output.SeverityWarning, "", msg)
 }
 
 // WarningWithCode adds a warning with a code.
 func (b *Builder) WarningWithCode(code, msg string) *Builder {
-	b.resp.Warnings = append(b.resp.Warnings, Warning{Code: code, Message: msg})
+	return b.TypedWarning(output.SeverityWarning, code, msg)
+}
+
+// TypedWarning adds a warning with an explicit severity and code.
+func (b *Builder) TypedWarning(severity, code, msg string) *Builder {
+	b.resp.Warnings = append(b.resp.Warnings, Warning{Severity: severity, Code: code, Message: msg})
 	return b
 }
 
@@ -177,11 +184,47 @@ func (b *Builder) CrossRepo() *Builder {
 
 // Build returns the completed response envelope.
 func (b *Builder) Build() *Response {
+	SortWarnings(b.resp.Warnings)
 	return b.resp
 }
 
+// SortWarnings orders warnings most severe first, keeping insertion order
+// within a severity.
+func SortWarnings(warnings []Warning) {
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return output.GetWarningSeverity(warnings[i].Severity) < output.GetWarningSeverity(warnings[j].Severity)
+	})
+}
+
+// FilterWarnings drops warnings less severe than min. An empty min keeps all.
+func FilterWarnings(warnings []Warning, min string) []Warning {
+	if min == "" || len(warnings) == 0 {
+		return warnings
+	}
+	kept := warnings[:0:0]
+	for _, w := range warnings {
+		if output.MeetsSeverity(w.Severity, min) {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
+
 // ParseDrilldown converts a drilldown to a SuggestedCall.
+// Structured drilldowns (Tool set) are used as-is; otherwise Query is parsed.
 func ParseDrilldown(d output.Drilldown) *SuggestedCall {
+	if d.Tool != "" {
+		params := make(map[string]interface{}, len(d.Params))
+		for k, v := range d.Params {
+			params[k] = v
+		}
+		return &SuggestedCall{
+			Tool:   d.Tool,
+			Params: params,
+			Reason: d.Label,
+		}
+	}
+
 	// Drilldown.Query format: "toolName param1 --flag=value" or just "toolName symbolId"
 	parts := strings.Fields(d.Query)
 	if len(parts) == 0 {
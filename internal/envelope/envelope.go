@@ -28,12 +28,16 @@ type Confidence struct {
 type Provenance struct {
 	Backends    []string `json:"backends"`              // e.g., ["scip", "git"]
 	RepoStateID string   `json:"repoStateId,omitempty"` // commit hash or state ID
+	FromCache   bool     `json:"fromCache,omitempty"`   // served from a stored result
+
+	Extra map[string]interface{} `json:"extra,omitempty"` // operator-supplied metadata
 }
 
 // IndexAge describes SCIP index freshness.
 type IndexAge struct {
 	CommitsBehind int    `json:"commitsBehind,omitempty"` // commits behind HEAD
 	StaleReason   string `json:"staleReason,omitempty"`   // "uncommitted-changes", "behind-head"
+	RefreshJobID  string `json:"refreshJobId,omitempty"`  // background reindex replacing the stale index
 }
 
 // Freshness describes data currency.
@@ -55,6 +59,7 @@ type Meta struct {
 	Provenance *Provenance `json:"provenance,omitempty"`
 	Freshness  *Freshness  `json:"freshness,omitempty"`
 	Truncation *Truncation `json:"truncation,omitempty"`
+	ETag       string      `json:"etag,omitempty"` // Content hash; pass back as ifNoneMatch
 }
 
 // SuggestedCall represents a recommended follow-up tool call.
@@ -66,8 +71,9 @@ type SuggestedCall struct {
 
 // Warning represents a non-fatal issue.
 type Warning struct {
-	Code    string `json:"code,omitempty"` // machine-readable code
-	Message string `json:"message"`        // human-readable message
+	Severity string `json:"severity,omitempty"` // error, warning or info
+	Code     string `json:"code,omitempty"`     // machine-readable code
+	Message  string `json:"message"`            // human-readable message
 }
 
 // Response is the standard envelope for all MCP tool responses.
@@ -88,7 +88,8 @@ func TestBuilderFromProvenance(t *testing.T) {
 			Score:  0.85,
 			Reason: "SCIP primary",
 		},
-		Warnings: []string{"some warning"},
+		Warnings: []output.Warning{{Severity: output.SeverityWarning, Code: "SCIP_UNAVAILABLE", Text: "some warning"}},
+		Extra:    map[string]interface{}{"environment": "prod"},
 	}
 
 	resp := New().
@@ -110,6 +111,9 @@ func TestBuilderFromProvenance(t *testing.T) {
 	if resp.Meta.Provenance.RepoStateID != "abc123" {
 		t.Errorf("RepoStateID = %q, want %q", resp.Meta.Provenance.RepoStateID, "abc123")
 	}
+	if resp.Meta.Provenance.Extra["environment"] != "prod" {
+		t.Errorf("Extra = %v, want environment=prod", resp.Meta.Provenance.Extra)
+	}
 
 	// Check confidence
 	if resp.Meta.Confidence == nil {
@@ -128,6 +132,8 @@ func TestBuilderFromProvenance(t *testing.T) {
 	// Check warnings
 	if len(resp.Warnings) != 1 || resp.Warnings[0].Message != "some warning" {
 		t.Errorf("Warnings = %v, want [{Message: some warning}]", resp.Warnings)
+	} else if resp.Warnings[0].Severity != output.SeverityWarning || resp.Warnings[0].Code != "SCIP_UNAVAILABLE" {
+		t.Errorf("Warning severity/code not carried over: %+v", resp.Warnings[0])
 	}
 }
 
@@ -275,6 +281,32 @@ func TestBuilderWarning(t *testing.T) {
 	}
 }
 
+func TestBuilderTypedWarningOrderAndFilter(t *testing.T) {
+	resp := New().
+		Data(nil).
+		TypedWarning(output.SeverityInfo, "", "fyi").
+		TypedWarning(output.SeverityError, "E001", "broken").
+		Warning("careful").
+		Build()
+
+	want := []string{"broken", "careful", "fyi"}
+	if len(resp.Warnings) != len(want) {
+		t.Fatalf("Warnings count = %d, want %d", len(resp.Warnings), len(want))
+	}
+	for i, msg := range want {
+		if resp.Warnings[i].Message != msg {
+			t.Errorf("Warnings[%d].Message = %q, want %q", i, resp.Warnings[i].Message, msg)
+		}
+	}
+
+	if got := FilterWarnings(resp.Warnings, output.SeverityWarning); len(got) != 2 {
+		t.Errorf("FilterWarnings(warning) kept %d, want 2", len(got))
+	}
+	if got := FilterWarnings(resp.Warnings, ""); len(got) != 3 {
+		t.Errorf("FilterWarnings(\"\") kept %d, want 3", len(got))
+	}
+}
+
 func TestBuilderError(t *testing.T) {
 	resp := New().
 		Data(nil).
@@ -402,6 +434,32 @@ func TestParseDrilldown(t *testing.T) {
 	}
 }
 
+func TestParseDrilldownStructured(t *testing.T) {
+	d := output.Drilldown{
+		Label:  "Explain file",
+		Query:  "explainFile docs/My File.md",
+		Tool:   "explainFile",
+		Params: map[string]interface{}{"filePath": "docs/My File.md"},
+	}
+
+	got := ParseDrilldown(d)
+	if got == nil {
+		t.Fatal("ParseDrilldown returned nil")
+	}
+	if got.Tool != "explainFile" {
+		t.Errorf("Tool = %q, want %q", got.Tool, "explainFile")
+	}
+	if got.Params["filePath"] != "docs/My File.md" {
+		t.Errorf("Params[filePath] = %v, want %q", got.Params["filePath"], "docs/My File.md")
+	}
+	if len(got.Params) != 1 {
+		t.Errorf("expected 1 param, got %d: %v", len(got.Params), got.Params)
+	}
+	if got.Reason != "Explain file" {
+		t.Errorf("Reason = %q, want %q", got.Reason, "Explain file")
+	}
+}
+
 func TestOperational(t *testing.T) {
 	data := map[string]bool{"healthy": true}
 	resp := Operational(data)
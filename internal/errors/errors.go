@@ -26,6 +26,8 @@ const (
 	SymbolDeleted ErrorCode = "SYMBOL_DELETED"
 	// ScopeInvalid indicates invalid scope parameter
 	ScopeInvalid ErrorCode = "SCOPE_INVALID"
+	// InvalidParameters indicates tool parameters that don't match its schema
+	InvalidParameters ErrorCode = "INVALID_PARAMETERS"
 	// AliasCycle indicates circular alias chain
 	AliasCycle ErrorCode = "ALIAS_CYCLE"
 	// AliasChainTooDeep indicates alias chain exceeds max depth
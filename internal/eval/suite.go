@@ -241,6 +241,8 @@ func (s *Suite) runTestCase(ctx context.Context, tc TestCase) TestResult {
 		Scope: tc.Scope,
 		Kinds: tc.Kinds,
 		Limit: tc.TopK * 2, // Fetch extra for margin
+
+		IncludePrivate: true,
 	}
 
 	resp, err := s.engine.SearchSymbols(ctx, searchOpts)
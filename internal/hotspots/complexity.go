@@ -2,6 +2,8 @@ package hotspots
 
 import (
 	"context"
+	"path/filepath"
+	"strings"
 
 	"ckb/internal/complexity"
 )
@@ -47,6 +49,19 @@ func (ca *ComplexityAnalyzer) GetFileComplexityFull(ctx context.Context, path st
 	return ca.analyzer.AnalyzeFile(ctx, path)
 }
 
+// GetSourceComplexity analyzes source for a file that isn't on disk, such
+// as a version read from git. The language is taken from path's extension.
+func (ca *ComplexityAnalyzer) GetSourceComplexity(ctx context.Context, path string, source []byte) (*complexity.FileComplexity, error) {
+	if ca.analyzer == nil {
+		return &complexity.FileComplexity{Path: path, Error: "complexity analysis unavailable (requires CGO)"}, nil
+	}
+	lang, ok := complexity.LanguageFromExtension(strings.ToLower(filepath.Ext(path)))
+	if !ok {
+		return &complexity.FileComplexity{Path: path, Error: "unsupported file extension: " + filepath.Ext(path)}, nil
+	}
+	return ca.analyzer.AnalyzeSource(ctx, path, source, lang)
+}
+
 // IsAvailable returns whether complexity analysis is available.
 func (ca *ComplexityAnalyzer) IsAvailable() bool {
 	return ca.analyzer != nil
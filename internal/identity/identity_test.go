@@ -686,6 +686,77 @@ func TestDirectSymbolResolution(t *testing.T) {
 	}
 }
 
+func TestFingerprintFallbackResolution(t *testing.T) {
+	db, tmpDir := setupTestDB(t)
+	defer cleanupTestDB(db, tmpDir)
+
+	logger := logging.NewLogger(logging.Config{
+		Format: logging.JSONFormat,
+		Level:  logging.DebugLevel,
+	})
+
+	repo := NewSymbolRepository(db, logger)
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	// Same symbol before and after re-indexing; the emitter's ID changed
+	// because it encodes the definition line.
+	fp := &SymbolFingerprint{QualifiedContainer: "pkg.Server", Name: "Start", Kind: KindMethod}
+	for _, m := range []*SymbolMapping{
+		{StableId: "ckb:test:sym:start-l10", BackendStableId: "scip-go pkg/Server#Start@10", Fingerprint: fp, State: StateActive},
+		{StableId: "ckb:test:sym:start-l42", BackendStableId: "scip-go pkg/Server#Start@42", Fingerprint: fp, State: StateActive},
+		{StableId: "ckb:test:sym:stop", Fingerprint: &SymbolFingerprint{QualifiedContainer: "pkg.Server", Name: "Stop", Kind: KindMethod}, State: StateActive},
+	} {
+		m.Location = &Location{Path: "pkg/server.go", Line: 1}
+		m.LocationFreshness = Fresh
+		m.DefinitionVersionSemantics = UnknownSemantics
+		m.LastVerifiedAt = now
+		m.LastVerifiedStateId = "state-2"
+		if err := repo.Create(m); err != nil {
+			t.Fatalf("failed to create mapping: %v", err)
+		}
+	}
+	if err := repo.MarkDeleted("ckb:test:sym:start-l10", "state-2"); err != nil {
+		t.Fatalf("failed to delete symbol: %v", err)
+	}
+
+	resolver := NewIdentityResolver(db, logger)
+
+	// Disabled by default: the stale ID is a tombstone
+	resolved, err := resolver.ResolveSymbolId("ckb:test:sym:start-l10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resolved.Deleted {
+		t.Error("expected tombstone without fingerprint fallback")
+	}
+
+	resolver.SetFingerprintFallback(true)
+
+	for _, id := range []string{"ckb:test:sym:start-l10", "scip-go pkg/Server#Start@10"} {
+		resolved, err = resolver.ResolveSymbolId(id)
+		if err != nil {
+			t.Fatalf("%s: fallback resolution failed: %v", id, err)
+		}
+		if resolved.Symbol == nil || resolved.Symbol.StableId != "ckb:test:sym:start-l42" {
+			t.Fatalf("%s: expected ckb:test:sym:start-l42, got %+v", id, resolved.Symbol)
+		}
+		if resolved.ResolvedFrom != ResolvedFromFingerprint {
+			t.Errorf("%s: resolvedFrom = %q, want %q", id, resolved.ResolvedFrom, ResolvedFromFingerprint)
+		}
+		if resolved.RedirectConfidence != FingerprintFallbackConfidence {
+			t.Errorf("%s: confidence = %f, want %f", id, resolved.RedirectConfidence, FingerprintFallbackConfidence)
+		}
+		if resolved.RedirectedFrom != id {
+			t.Errorf("%s: redirectedFrom = %q", id, resolved.RedirectedFrom)
+		}
+	}
+
+	// IDs with no recorded fingerprint still fail
+	if _, err := resolver.ResolveSymbolId("ckb:test:sym:never-seen"); err == nil {
+		t.Error("expected error for unknown ID")
+	}
+}
+
 func TestAliasChainMaxDepthConstant(t *testing.T) {
 	// Verify the constant is set correctly per spec
 	if AliasChainMaxDepth != 3 {
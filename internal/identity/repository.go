@@ -15,6 +15,8 @@ type SymbolFilter struct {
 	State           *SymbolState // Filter by state (nil = all)
 	Kind            *SymbolKind  // Filter by kind (nil = all)
 	BackendStableId string       // Filter by backend ID (empty = all)
+	Name            string       // Filter by fingerprint name (empty = all)
+	Container       *string      // Filter by fingerprint qualified container (nil = all)
 	Limit           int          // Limit results (0 = no limit)
 	Offset          int          // Offset for pagination
 }
@@ -347,6 +349,21 @@ func (r *SymbolRepository) List(filter SymbolFilter) ([]*SymbolMapping, error) {
 		args = append(args, filter.BackendStableId)
 	}
 
+	if filter.Kind != nil {
+		query += " AND json_extract(fingerprint_json, '$.kind') = ?"
+		args = append(args, string(*filter.Kind))
+	}
+
+	if filter.Name != "" {
+		query += " AND json_extract(fingerprint_json, '$.name') = ?"
+		args = append(args, filter.Name)
+	}
+
+	if filter.Container != nil {
+		query += " AND COALESCE(json_extract(fingerprint_json, '$.qualifiedContainer'), '') = ?"
+		args = append(args, *filter.Container)
+	}
+
 	// Add ordering
 	query += " ORDER BY stable_id ASC"
 
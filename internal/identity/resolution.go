@@ -12,6 +12,13 @@ import (
 // Section 4.5: Alias resolution
 const AliasChainMaxDepth = 3
 
+// FingerprintFallbackConfidence is the confidence reported for symbols
+// re-resolved by fingerprint after their stable ID stopped resolving
+const FingerprintFallbackConfidence = 0.6
+
+// ResolvedFromFingerprint marks a symbol found by fingerprint fallback
+const ResolvedFromFingerprint = "fingerprint-fallback"
+
 // ResolvedSymbol represents the result of resolving a symbol ID through alias chains
 type ResolvedSymbol struct {
 	Symbol             *SymbolMapping `json:"symbol,omitempty"`             // The resolved symbol (nil if not found/deleted)
@@ -22,12 +29,16 @@ type ResolvedSymbol struct {
 	Deleted            bool           `json:"deleted,omitempty"`            // True if the symbol was deleted
 	DeletedAt          string         `json:"deletedAt,omitempty"`          // When it was deleted
 	Error              string         `json:"error,omitempty"`              // Error message if resolution failed
+	ResolvedFrom       string         `json:"resolvedFrom,omitempty"`       // How the symbol was found when not by ID or alias
 }
 
 // IdentityResolver handles symbol ID resolution with alias following
 type IdentityResolver struct {
 	db     *storage.DB
 	logger *logging.Logger
+
+	// fingerprintFallback re-resolves unresolvable IDs by fingerprint
+	fingerprintFallback bool
 }
 
 // NewIdentityResolver creates a new identity resolver
@@ -38,10 +49,106 @@ func NewIdentityResolver(db *storage.DB, logger *logging.Logger) *IdentityResolv
 	}
 }
 
+// SetFingerprintFallback enables re-resolution by fingerprint (name +
+// container + kind) when a stable ID no longer resolves. Some SCIP emitters
+// encode line numbers or hashes in their IDs, so IDs cached by clients go
+// stale after re-indexing even though the symbol still exists.
+func (r *IdentityResolver) SetFingerprintFallback(enabled bool) {
+	r.fingerprintFallback = enabled
+}
+
 // ResolveSymbolId follows alias chains to find the current symbol
 // Returns the resolved symbol or an error/tombstone response
 func (r *IdentityResolver) ResolveSymbolId(requestedId string) (*ResolvedSymbol, error) {
-	return r.resolveWithDepth(requestedId, 0, make(map[string]bool))
+	resolved, err := r.resolveWithDepth(requestedId, 0, make(map[string]bool))
+	if !r.fingerprintFallback {
+		return resolved, err
+	}
+
+	notFound := false
+	if ckbErr, ok := err.(*errors.CkbError); ok {
+		notFound = ckbErr.Code == errors.SymbolNotFound
+	}
+	tombstoned := err == nil && resolved != nil && resolved.Deleted && !resolved.Redirected
+	if !notFound && !tombstoned {
+		return resolved, err
+	}
+
+	if fallback := r.resolveByFingerprint(requestedId); fallback != nil {
+		return fallback, nil
+	}
+	return resolved, err
+}
+
+// resolveByFingerprint looks up the last known fingerprint of an ID and
+// returns the single active symbol with the same name, container and kind.
+// Ambiguous matches are narrowed by arity; if that leaves more than one
+// candidate the fallback gives up rather than guess.
+func (r *IdentityResolver) resolveByFingerprint(requestedId string) *ResolvedSymbol {
+	repo := NewSymbolRepository(r.db, r.logger)
+
+	known, err := repo.Get(requestedId)
+	if err != nil || known == nil {
+		// Clients may hold the backend's ID; its mapping may be tombstoned
+		byBackend, listErr := repo.List(SymbolFilter{BackendStableId: requestedId, Limit: 1})
+		if listErr != nil || len(byBackend) == 0 {
+			return nil
+		}
+		known = byBackend[0]
+	}
+	if known.IsActive() || known.Fingerprint == nil || known.Fingerprint.Name == "" {
+		return nil
+	}
+	fp := known.Fingerprint
+
+	active := StateActive
+	kind := fp.Kind
+	container := fp.QualifiedContainer
+	candidates, err := repo.List(SymbolFilter{
+		State:     &active,
+		Kind:      &kind,
+		Name:      fp.Name,
+		Container: &container,
+	})
+	if err != nil {
+		return nil
+	}
+
+	var matches []*SymbolMapping
+	for _, c := range candidates {
+		if c.StableId != known.StableId {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) > 1 {
+		var sameArity []*SymbolMapping
+		for _, c := range matches {
+			if c.Fingerprint != nil && c.Fingerprint.Arity == fp.Arity {
+				sameArity = append(sameArity, c)
+			}
+		}
+		matches = sameArity
+	}
+	if len(matches) != 1 {
+		r.logger.Debug("fingerprint fallback found no unique match", map[string]interface{}{
+			"requested_id": requestedId,
+			"candidates":   len(matches),
+		})
+		return nil
+	}
+
+	r.logger.Debug("resolved stale ID by fingerprint", map[string]interface{}{
+		"from": requestedId,
+		"to":   matches[0].StableId,
+	})
+	return &ResolvedSymbol{
+		Symbol:             matches[0],
+		Redirected:         true,
+		RedirectedFrom:     requestedId,
+		RedirectReason:     ReasonFuzzyMatch,
+		RedirectConfidence: FingerprintFallbackConfidence,
+		ResolvedFrom:       ResolvedFromFingerprint,
+	}
 }
 
 // resolveWithDepth is the internal recursive resolution function
@@ -7,7 +7,9 @@ import (
 
 // ImpactAnalyzer performs impact analysis on symbols
 type ImpactAnalyzer struct {
-	maxDepth int // Maximum depth for transitive analysis (default 2)
+	maxDepth       int               // Maximum depth for transitive analysis (default 2)
+	riskWeights    *RiskWeightConfig // Risk factor weights (nil = defaults)
+	riskThresholds *RiskThresholds   // Score-to-level mapping (nil = defaults)
 }
 
 // NewImpactAnalyzer creates a new ImpactAnalyzer with the specified max depth
@@ -29,6 +31,16 @@ type ImpactAnalysisResult struct {
 	TransitiveImpact []ImpactItem    // Transitive references (distance > 1)
 	ModulesAffected  []ModuleSummary // Summary by module
 	AnalysisLimits   *AnalysisLimits // Limitations of the analysis
+
+	// SuppressedNonBreaking counts impact items dropped by OnlyBreakingChanges
+	SuppressedNonBreaking int
+
+	// KindBreakdown counts impact items per ImpactKind across every
+	// reference, before any filtering or truncation
+	KindBreakdown map[string]int
+
+	// RiskThresholds mapped RiskScore.Score to its level
+	RiskThresholds RiskThresholds
 }
 
 // ModuleSummary provides a summary of impact for a single module
@@ -75,7 +87,13 @@ func (a *ImpactAnalyzer) Analyze(symbol *Symbol, refs []Reference) (*ImpactAnaly
 	allImpact = append(allImpact, result.TransitiveImpact...)
 
 	// Calculate risk score
-	result.RiskScore = ComputeRiskScore(symbol, allImpact)
+	weights := DefaultRiskWeights()
+	if a.riskWeights != nil {
+		weights = *a.riskWeights
+	}
+	result.RiskThresholds = a.thresholds()
+	result.RiskScore = computeRiskScore(symbol, allImpact, weights, result.RiskThresholds)
+	result.KindBreakdown = countImpactKinds(allImpact)
 
 	// Generate module summaries
 	result.ModulesAffected = a.generateModuleSummaries(allImpact)
@@ -83,6 +101,26 @@ func (a *ImpactAnalyzer) Analyze(symbol *Symbol, refs []Reference) (*ImpactAnaly
 	return result, nil
 }
 
+// thresholds returns the analyzer's risk thresholds or the defaults.
+func (a *ImpactAnalyzer) thresholds() RiskThresholds {
+	if a.riskThresholds != nil {
+		return *a.riskThresholds
+	}
+	return DefaultRiskThresholds()
+}
+
+// countImpactKinds tallies items by kind, returning nil when there are none.
+func countImpactKinds(items []ImpactItem) map[string]int {
+	if len(items) == 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[string(item.Kind)]++
+	}
+	return counts
+}
+
 // processDirectReferences converts references into impact items
 func (a *ImpactAnalyzer) processDirectReferences(symbol *Symbol, refs []Reference, symbolVisibility *VisibilityInfo) []ImpactItem {
 	items := make([]ImpactItem, 0, len(refs))
@@ -102,6 +140,7 @@ func (a *ImpactAnalyzer) processDirectReferences(symbol *Symbol, refs []Referenc
 			Location:   ref.Location,
 			Visibility: symbolVisibility, // Use the same visibility as the symbol
 			Distance:   1,                // Direct reference
+			Breaking:   IsBreakingImpact(kind),
 		}
 
 		items = append(items, item)
@@ -189,15 +228,15 @@ func extractModuleNameFromId(moduleId string) string {
 
 // estimateItemRisk estimates the risk level of an individual impact item
 func estimateItemRisk(item ImpactItem) RiskLevel {
-	// High risk: public direct callers, interface implementations
+	// High risk: public direct callers, interface implementations, annotation uses
 	if item.Visibility != nil && item.Visibility.Visibility == VisibilityPublic {
-		if item.Kind == DirectCaller || item.Kind == ImplementsInterface {
+		if IsBreakingImpact(item.Kind) {
 			return RiskHigh
 		}
 	}
 
-	// Medium risk: internal direct callers, transitive callers
-	if item.Kind == DirectCaller || item.Kind == TransitiveCaller {
+	// Medium risk: internal direct callers and annotation uses, transitive callers
+	if item.Kind == DirectCaller || item.Kind == AnnotationDependency || item.Kind == TransitiveCaller {
 		return RiskMedium
 	}
 
@@ -208,9 +247,10 @@ func estimateItemRisk(item ImpactItem) RiskLevel {
 // isHigherRisk compares two risk levels
 func isHigherRisk(a, b RiskLevel) bool {
 	riskOrder := map[RiskLevel]int{
-		RiskLow:    1,
-		RiskMedium: 2,
-		RiskHigh:   3,
+		RiskLow:      1,
+		RiskMedium:   2,
+		RiskHigh:     3,
+		RiskCritical: 4,
 	}
 	return riskOrder[a] > riskOrder[b]
 }
@@ -220,6 +260,18 @@ type AnalyzeOptions struct {
 	MaxDepth            int  // Override analyzer's default max depth
 	IncludeTests        bool // Include test dependencies in analysis
 	OnlyBreakingChanges bool // Only include potentially breaking changes
+
+	// ChangeKind is the change assumed; "" means ChangeModify. Deletes and
+	// renames break every reference. NewName names a rename's target.
+	ChangeKind ChangeKind
+	NewName    string
+
+	// RiskWeights replaces the default risk factor weights when set.
+	// They must sum to 1.0.
+	RiskWeights *RiskWeightConfig
+
+	// RiskThresholds replaces the default score-to-level mapping when set.
+	RiskThresholds *RiskThresholds
 }
 
 // AnalyzeWithOptions performs analysis with custom options
@@ -231,6 +283,23 @@ func (a *ImpactAnalyzer) AnalyzeWithOptions(symbol *Symbol, refs []Reference, op
 	}
 	defer func() { a.maxDepth = originalMaxDepth }()
 
+	if opts.RiskWeights != nil {
+		if err := opts.RiskWeights.Validate(); err != nil {
+			return nil, err
+		}
+		originalWeights := a.riskWeights
+		a.riskWeights = opts.RiskWeights
+		defer func() { a.riskWeights = originalWeights }()
+	}
+	if opts.RiskThresholds != nil {
+		if err := opts.RiskThresholds.Validate(); err != nil {
+			return nil, err
+		}
+		originalThresholds := a.riskThresholds
+		a.riskThresholds = opts.RiskThresholds
+		defer func() { a.riskThresholds = originalThresholds }()
+	}
+
 	// Filter references based on options
 	filteredRefs := refs
 	if !opts.IncludeTests {
@@ -243,5 +312,39 @@ func (a *ImpactAnalyzer) AnalyzeWithOptions(symbol *Symbol, refs []Reference, op
 	}
 
 	// Perform standard analysis
-	return a.Analyze(symbol, filteredRefs)
+	result, err := a.Analyze(symbol, filteredRefs)
+	if err != nil {
+		return nil, err
+	}
+	weights := DefaultRiskWeights()
+	if a.riskWeights != nil {
+		weights = *a.riskWeights
+	}
+	applyChangeKind(result, symbol, opts.ChangeKind, opts.NewName, weights, result.RiskThresholds)
+	if !opts.OnlyBreakingChanges {
+		return result, nil
+	}
+
+	// Keep only breaking impacts. The risk score still reflects every
+	// reference, so hiding the rest doesn't make the change look safer.
+	direct := filterBreakingImpact(result.DirectImpact)
+	transitive := filterBreakingImpact(result.TransitiveImpact)
+	result.SuppressedNonBreaking = len(result.DirectImpact) - len(direct) +
+		len(result.TransitiveImpact) - len(transitive)
+	result.DirectImpact = direct
+	result.TransitiveImpact = transitive
+	result.ModulesAffected = a.generateModuleSummaries(append(append([]ImpactItem{}, direct...), transitive...))
+	return result, nil
+}
+
+// filterBreakingImpact returns the items flagged breaking or whose kind
+// IsBreakingImpact.
+func filterBreakingImpact(items []ImpactItem) []ImpactItem {
+	filtered := make([]ImpactItem, 0, len(items))
+	for _, item := range items {
+		if item.Breaking || IsBreakingImpact(item.Kind) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
 }
@@ -1,6 +1,7 @@
 package impact
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -176,6 +177,157 @@ func TestAnalyzeWithOptions(t *testing.T) {
 	}
 }
 
+func TestAnalyzeWithOptionsOnlyBreaking(t *testing.T) {
+	analyzer := NewImpactAnalyzer(2)
+
+	symbol := &Symbol{
+		StableId:  "test.Symbol.myFunction",
+		Name:      "myFunction",
+		Kind:      KindFunction,
+		ModuleId:  "module1",
+		Modifiers: []string{"public"},
+	}
+
+	refs := []Reference{
+		{
+			Location:   &Location{FileId: "caller.go", StartLine: 10},
+			Kind:       RefCall,
+			FromSymbol: "caller",
+			FromModule: "module2",
+		},
+		{
+			Location:   &Location{FileId: "types.go", StartLine: 20},
+			Kind:       RefType,
+			FromSymbol: "holder",
+			FromModule: "module3",
+		},
+		{
+			Location:   &Location{FileId: "impl.go", StartLine: 30},
+			Kind:       RefImplements,
+			FromSymbol: "impl",
+			FromModule: "module2",
+		},
+	}
+
+	all, err := analyzer.AnalyzeWithOptions(symbol, refs, AnalyzeOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeWithOptions failed: %v", err)
+	}
+	breaking, err := analyzer.AnalyzeWithOptions(symbol, refs, AnalyzeOptions{OnlyBreakingChanges: true})
+	if err != nil {
+		t.Fatalf("AnalyzeWithOptions failed: %v", err)
+	}
+
+	if len(breaking.DirectImpact) != 2 {
+		t.Fatalf("expected 2 breaking impacts, got %d", len(breaking.DirectImpact))
+	}
+	for _, item := range breaking.DirectImpact {
+		if !IsBreakingImpact(item.Kind) {
+			t.Errorf("non-breaking impact %s (%s) was not filtered", item.Name, item.Kind)
+		}
+	}
+	if breaking.SuppressedNonBreaking != 1 {
+		t.Errorf("expected 1 suppressed impact, got %d", breaking.SuppressedNonBreaking)
+	}
+	if all.SuppressedNonBreaking != 0 {
+		t.Errorf("expected nothing suppressed without the option, got %d", all.SuppressedNonBreaking)
+	}
+
+	// module3 only had the type dependency
+	if len(breaking.ModulesAffected) != 1 || breaking.ModulesAffected[0].ModuleId != "module2" {
+		t.Errorf("expected only module2 affected, got %+v", breaking.ModulesAffected)
+	}
+
+	// Risk is computed over all references either way
+	if breaking.RiskScore.Score != all.RiskScore.Score {
+		t.Errorf("risk score changed with filtering: %v vs %v", breaking.RiskScore.Score, all.RiskScore.Score)
+	}
+
+	// So is the kind breakdown
+	wantKinds := map[string]int{
+		string(DirectCaller):        1,
+		string(TypeDependency):      1,
+		string(ImplementsInterface): 1,
+	}
+	for _, result := range []*ImpactAnalysisResult{all, breaking} {
+		if !reflect.DeepEqual(result.KindBreakdown, wantKinds) {
+			t.Errorf("KindBreakdown = %v, want %v", result.KindBreakdown, wantKinds)
+		}
+	}
+}
+
+func TestAnalyzeWithOptionsRiskWeights(t *testing.T) {
+	analyzer := NewImpactAnalyzer(2)
+	symbol := &Symbol{StableId: "s", Name: "Fn", ModuleId: "module1"}
+	refs := []Reference{
+		{Location: &Location{FileId: "a.go"}, Kind: RefCall, FromSymbol: "a", FromModule: "module2"},
+		{Location: &Location{FileId: "b.go"}, Kind: RefCall, FromSymbol: "b", FromModule: "module3"},
+	}
+
+	if _, err := analyzer.AnalyzeWithOptions(symbol, refs, AnalyzeOptions{
+		RiskWeights: &RiskWeightConfig{Visibility: 0.5, DirectCallers: 0.5, ModuleSpread: 0.5},
+	}); err == nil {
+		t.Error("expected an error for weights that don't sum to 1.0")
+	}
+
+	weights := &RiskWeightConfig{Visibility: 0.1, DirectCallers: 0.1, ModuleSpread: 0.7, ImpactKind: 0.1}
+	result, err := analyzer.AnalyzeWithOptions(symbol, refs, AnalyzeOptions{RiskWeights: weights})
+	if err != nil {
+		t.Fatalf("AnalyzeWithOptions failed: %v", err)
+	}
+	for _, f := range result.RiskScore.Factors {
+		if f.Name == "module-spread" && f.Weight != 0.7 {
+			t.Errorf("expected module-spread weight 0.7, got %v", f.Weight)
+		}
+	}
+
+	// The override applies to that call only
+	defaults, err := analyzer.Analyze(symbol, refs)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !reflect.DeepEqual(defaults.RiskScore, ComputeRiskScore(symbol, append(defaults.DirectImpact, defaults.TransitiveImpact...))) {
+		t.Error("expected default weights after an overridden call")
+	}
+}
+
+func TestAnalyzeWithOptionsRiskThresholds(t *testing.T) {
+	analyzer := NewImpactAnalyzer(2)
+	symbol := &Symbol{StableId: "s", Name: "Fn", ModuleId: "module1", Modifiers: []string{"public"}}
+	refs := []Reference{
+		{Location: &Location{FileId: "a.go"}, Kind: RefCall, FromSymbol: "a", FromModule: "module2"},
+		{Location: &Location{FileId: "b.go"}, Kind: RefCall, FromSymbol: "b", FromModule: "module3"},
+	}
+
+	if _, err := analyzer.AnalyzeWithOptions(symbol, refs, AnalyzeOptions{
+		RiskThresholds: &RiskThresholds{MediumMin: 0.7, HighMin: 0.4},
+	}); err == nil {
+		t.Error("expected an error for descending thresholds")
+	}
+
+	defaults, err := analyzer.Analyze(symbol, refs)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if defaults.RiskThresholds != DefaultRiskThresholds() {
+		t.Errorf("RiskThresholds = %+v, want defaults", defaults.RiskThresholds)
+	}
+
+	// Thresholds just under and at the score put it in the top level
+	score := defaults.RiskScore.Score
+	thresholds := RiskThresholds{MediumMin: score / 4, HighMin: score / 2, CriticalMin: score}
+	result, err := analyzer.AnalyzeWithOptions(symbol, refs, AnalyzeOptions{RiskThresholds: &thresholds})
+	if err != nil {
+		t.Fatalf("AnalyzeWithOptions failed: %v", err)
+	}
+	if result.RiskScore.Level != RiskCritical || result.RiskThresholds != thresholds {
+		t.Errorf("level = %s with thresholds %+v, want critical with %+v", result.RiskScore.Level, result.RiskThresholds, thresholds)
+	}
+	if result.RiskScore.Score != score {
+		t.Errorf("score = %v, want %v: thresholds must not change the score", result.RiskScore.Score, score)
+	}
+}
+
 func TestAnalyzeNilSymbol(t *testing.T) {
 	analyzer := NewImpactAnalyzer(2)
 	_, err := analyzer.Analyze(nil, []Reference{})
@@ -0,0 +1,151 @@
+package impact
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ChangeKind is the change an impact analysis assumes is made to the symbol
+type ChangeKind string
+
+const (
+	ChangeModify ChangeKind = "modify" // Signature or behavior changes (default)
+	ChangeDelete ChangeKind = "delete" // The symbol is removed
+	ChangeRename ChangeKind = "rename" // The symbol is renamed
+)
+
+// ChangeKinds lists the supported change kinds
+var ChangeKinds = []ChangeKind{ChangeModify, ChangeDelete, ChangeRename}
+
+// ParseChangeKind parses a change kind; the empty string is ChangeModify.
+func ParseChangeKind(s string) (ChangeKind, error) {
+	if s == "" {
+		return ChangeModify, nil
+	}
+	for _, kind := range ChangeKinds {
+		if ChangeKind(s) == kind {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("unknown change kind %q, expected modify, delete or rename", s)
+}
+
+// How a reference must be updated when the symbol is renamed
+const (
+	UpdateMechanical = "mechanical" // Rewriting the name at the site is enough
+	UpdateLogic      = "logic"      // The site depends on the name beyond spelling it
+)
+
+// RenameUpdateKind classifies how a site must be updated for a rename.
+// Calls, type uses and accesses spell the name and can be rewritten by a
+// refactoring tool. Interface implementations must be renamed in step with
+// the interface, including any outside the index, and annotation uses are
+// often wired up by name at runtime, so both need a closer look.
+func RenameUpdateKind(kind ImpactKind) string {
+	switch kind {
+	case ImplementsInterface, AnnotationDependency, Unknown:
+		return UpdateLogic
+	default:
+		return UpdateMechanical
+	}
+}
+
+// deleteRiskFloor is the lowest risk score of deleting a symbol that is
+// still referenced: every reference stops compiling.
+const deleteRiskFloor = 0.4
+
+// applyChangeKind adjusts a result computed for a modification to the given
+// change. Deleting or renaming breaks every reference, so all items are
+// flagged breaking; a rename also classifies each item's update, and a
+// delete rescores risk with every reference counted as breaking.
+func applyChangeKind(result *ImpactAnalysisResult, symbol *Symbol, change ChangeKind, newName string, weights RiskWeightConfig, thresholds RiskThresholds) {
+	if change != ChangeDelete && change != ChangeRename {
+		return
+	}
+
+	for _, items := range [][]ImpactItem{result.DirectImpact, result.TransitiveImpact} {
+		for i := range items {
+			items[i].Breaking = true
+			if change == ChangeRename {
+				items[i].UpdateKind = RenameUpdateKind(items[i].Kind)
+			}
+		}
+	}
+
+	allImpact := append(append([]ImpactItem{}, result.DirectImpact...), result.TransitiveImpact...)
+	name := symbol.Name
+	if name == "" {
+		name = symbol.StableId
+	}
+
+	if change == ChangeDelete {
+		score := computeRiskScore(symbol, allImpact, weights, thresholds)
+		total := 0.0
+		for i := range score.Factors {
+			if score.Factors[i].Name == "impact-kind" && len(allImpact) > 0 {
+				score.Factors[i].Value = 1.0
+				score.Factors[i].Detail = fmt.Sprintf("%s stop compiling once the symbol is gone", plural(len(allImpact), "reference"))
+			}
+			total += score.Factors[i].Weight * score.Factors[i].Value
+		}
+		if len(allImpact) > 0 {
+			total = math.Max(total, deleteRiskFloor)
+		}
+		score.Score = total
+		score.Level = thresholds.Level(total)
+		score.Explanation = deleteExplanation(score.Level, name, allImpact)
+		result.RiskScore = score
+		return
+	}
+
+	if result.RiskScore != nil {
+		result.RiskScore.Explanation = renameExplanation(result.RiskScore.Level, name, newName, allImpact)
+	}
+}
+
+// deleteExplanation describes the risk of deleting a symbol.
+func deleteExplanation(level RiskLevel, name string, impact []ImpactItem) string {
+	if len(impact) == 0 {
+		return fmt.Sprintf("%s: deleting %s orphans no references.", riskLabel(level), name)
+	}
+	return fmt.Sprintf("%s: deleting %s orphans %s across %s; each must be removed or rewritten before the change compiles.",
+		riskLabel(level), name, plural(len(impact), "reference"), plural(countModules(impact), "module"))
+}
+
+// renameExplanation describes the risk of renaming a symbol, splitting the
+// sites by how they must be updated.
+func renameExplanation(level RiskLevel, name, newName string, impact []ImpactItem) string {
+	target := "renaming " + name
+	if newName != "" {
+		target += " to " + newName
+	}
+	logic := 0
+	for _, item := range impact {
+		if item.UpdateKind == UpdateLogic {
+			logic++
+		}
+	}
+	return fmt.Sprintf("%s: %s touches %s across %s; %d can be updated mechanically, %d need logic changes.",
+		riskLabel(level), target, plural(len(impact), "reference"), plural(countModules(impact), "module"), len(impact)-logic, logic)
+}
+
+// riskLabel renders a risk level as the leading "High risk" of an
+// explanation.
+func riskLabel(level RiskLevel) string {
+	s := string(level)
+	if s == "" {
+		return "Unknown risk"
+	}
+	return strings.ToUpper(s[:1]) + s[1:] + " risk"
+}
+
+func countModules(impact []ImpactItem) int {
+	modules := make(map[string]bool)
+	for _, item := range impact {
+		if item.ModuleId != "" {
+			modules[item.ModuleId] = true
+		}
+	}
+	return len(modules)
+}
@@ -0,0 +1,108 @@
+package impact
+
+import (
+	"strings"
+	"testing"
+)
+
+func changeKindFixture() (*Symbol, []Reference) {
+	symbol := &Symbol{
+		StableId:  "test:Helper",
+		Name:      "Helper",
+		Kind:      KindFunction,
+		ModuleId:  "module1",
+		Modifiers: []string{"private"},
+	}
+	refs := []Reference{
+		{Location: &Location{FileId: "caller.go", StartLine: 10}, Kind: RefCall, FromSymbol: "caller", FromModule: "module1"},
+		{Location: &Location{FileId: "types.go", StartLine: 20}, Kind: RefType, FromSymbol: "holder", FromModule: "module2"},
+		{Location: &Location{FileId: "impl.go", StartLine: 30}, Kind: RefAnnotation, FromSymbol: "handler", FromModule: "module2"},
+	}
+	return symbol, refs
+}
+
+func TestParseChangeKind(t *testing.T) {
+	for in, want := range map[string]ChangeKind{"": ChangeModify, "modify": ChangeModify, "delete": ChangeDelete, "rename": ChangeRename} {
+		got, err := ParseChangeKind(in)
+		if err != nil || got != want {
+			t.Errorf("ParseChangeKind(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseChangeKind("remove"); err == nil {
+		t.Error("expected an error for an unknown change kind")
+	}
+}
+
+func TestAnalyzeDeleteFlagsEveryReference(t *testing.T) {
+	analyzer := NewImpactAnalyzer(1)
+	symbol, refs := changeKindFixture()
+
+	modify, err := analyzer.AnalyzeWithOptions(symbol, refs, AnalyzeOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeWithOptions failed: %v", err)
+	}
+	deleted, err := analyzer.AnalyzeWithOptions(symbol, refs, AnalyzeOptions{ChangeKind: ChangeDelete, OnlyBreakingChanges: true})
+	if err != nil {
+		t.Fatalf("AnalyzeWithOptions failed: %v", err)
+	}
+
+	// The type dependency breaks too, so onlyBreakingChanges keeps it
+	if len(deleted.DirectImpact) != len(refs) || deleted.SuppressedNonBreaking != 0 {
+		t.Errorf("expected all %d references kept, got %d (%d suppressed)", len(refs), len(deleted.DirectImpact), deleted.SuppressedNonBreaking)
+	}
+	for _, item := range deleted.DirectImpact {
+		if !item.Breaking {
+			t.Errorf("%s not flagged breaking", item.StableId)
+		}
+	}
+
+	if deleted.RiskScore.Score <= modify.RiskScore.Score {
+		t.Errorf("delete risk %.2f should exceed modify risk %.2f", deleted.RiskScore.Score, modify.RiskScore.Score)
+	}
+	if deleted.RiskScore.Score < deleteRiskFloor {
+		t.Errorf("delete risk %.2f below floor %.2f", deleted.RiskScore.Score, deleteRiskFloor)
+	}
+	if !strings.Contains(deleted.RiskScore.Explanation, "deleting Helper orphans 3 references") {
+		t.Errorf("explanation does not name the deletion: %q", deleted.RiskScore.Explanation)
+	}
+
+	// Modify keeps the kind-based flags
+	for _, item := range modify.DirectImpact {
+		if item.Breaking != IsBreakingImpact(item.Kind) {
+			t.Errorf("%s: breaking = %v for kind %s", item.StableId, item.Breaking, item.Kind)
+		}
+	}
+}
+
+func TestAnalyzeDeleteUnreferenced(t *testing.T) {
+	symbol, _ := changeKindFixture()
+	result, err := NewImpactAnalyzer(1).AnalyzeWithOptions(symbol, nil, AnalyzeOptions{ChangeKind: ChangeDelete})
+	if err != nil {
+		t.Fatalf("AnalyzeWithOptions failed: %v", err)
+	}
+	if result.RiskScore.Level != RiskLow {
+		t.Errorf("deleting an unreferenced private symbol should be low risk, got %s", result.RiskScore.Level)
+	}
+}
+
+func TestAnalyzeRenameClassifiesUpdates(t *testing.T) {
+	symbol, refs := changeKindFixture()
+	result, err := NewImpactAnalyzer(1).AnalyzeWithOptions(symbol, refs, AnalyzeOptions{ChangeKind: ChangeRename, NewName: "Assist"})
+	if err != nil {
+		t.Fatalf("AnalyzeWithOptions failed: %v", err)
+	}
+
+	want := map[string]string{"caller": UpdateMechanical, "holder": UpdateMechanical, "handler": UpdateLogic}
+	for _, item := range result.DirectImpact {
+		if item.UpdateKind != want[item.StableId] {
+			t.Errorf("%s: update kind %q, want %q", item.StableId, item.UpdateKind, want[item.StableId])
+		}
+		if !item.Breaking {
+			t.Errorf("%s not flagged breaking", item.StableId)
+		}
+	}
+	if !strings.Contains(result.RiskScore.Explanation, "renaming Helper to Assist") ||
+		!strings.Contains(result.RiskScore.Explanation, "2 can be updated mechanically, 1 need logic changes") {
+		t.Errorf("unexpected explanation: %q", result.RiskScore.Explanation)
+	}
+}
@@ -1,5 +1,7 @@
 package impact
 
+import "strings"
+
 // ImpactKind represents the type of impact a reference has
 type ImpactKind string
 
@@ -9,7 +11,13 @@ const (
 	TypeDependency      ImpactKind = "type-dependency"
 	TestDependency      ImpactKind = "test-dependency"
 	ImplementsInterface ImpactKind = "implements-interface"
-	Unknown             ImpactKind = "unknown"
+
+	// AnnotationDependency is a use of the symbol as an annotation or
+	// decorator. Frameworks wire such uses up at runtime, so removing or
+	// changing the symbol breaks them without a compile error at the site.
+	AnnotationDependency ImpactKind = "annotation-dependency"
+
+	Unknown ImpactKind = "unknown"
 )
 
 // ImpactItem represents a single item impacted by a symbol change
@@ -23,6 +31,8 @@ type ImpactItem struct {
 	Location   *Location       // Location of the impact
 	Visibility *VisibilityInfo // Visibility of the impacted symbol
 	Distance   int             // Distance from original symbol (1 = direct, 2+ = transitive)
+	Breaking   bool            // Whether the change is expected to break this item
+	UpdateKind string          // For renames: UpdateMechanical or UpdateLogic
 }
 
 // ClassifyImpact determines the impact kind based on reference type and context
@@ -50,6 +60,10 @@ func ClassifyImpact(ref *Reference, symbol *Symbol) ImpactKind {
 		// Type reference - could be parameter, return type, field type, etc.
 		return TypeDependency
 
+	case RefAnnotation:
+		// Decorator or annotation - framework wiring depends on the symbol
+		return AnnotationDependency
+
 	case RefRead, RefWrite:
 		// Property or variable access
 		if symbol.Kind == KindProperty || symbol.Kind == KindVariable || symbol.Kind == KindConstant {
@@ -93,6 +107,10 @@ func ClassifyImpactWithConfidence(ref *Reference, symbol *Symbol) (ImpactKind, f
 		// High confidence for interface implementations
 		confidence = 0.95
 
+	case AnnotationDependency:
+		// Detected from the source text around the reference
+		confidence = 0.85
+
 	default:
 		// Low confidence for unknown
 		confidence = 0.5
@@ -101,12 +119,38 @@ func ClassifyImpactWithConfidence(ref *Reference, symbol *Symbol) (ImpactKind, f
 	return kind, confidence
 }
 
+// IsBreakingImpact reports whether an impact kind is one a change to the
+// symbol is expected to break: direct callers, interface implementations
+// and annotation uses. Type, test and transitive dependencies are reported
+// but not flagged.
+func IsBreakingImpact(kind ImpactKind) bool {
+	return kind == DirectCaller || kind == ImplementsInterface || kind == AnnotationDependency
+}
+
+// IsAnnotationSite reports whether the reference at the 1-indexed column
+// of a source line is used as an annotation or decorator: the name, or a
+// qualified name ending in it, directly follows an "@" that starts a
+// token, as in Java's @Inject, Python's @app.route or a TypeScript
+// @Component.
+func IsAnnotationSite(line string, column int) bool {
+	if column < 2 || column-1 > len(line) {
+		return false
+	}
+	prefix := strings.TrimRight(line[:column-1], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_$.")
+	if !strings.HasSuffix(prefix, "@") {
+		return false
+	}
+	before := strings.TrimSuffix(prefix, "@")
+	return before == "" || strings.ContainsAny(before[len(before)-1:], " \t(,")
+}
+
 // IsBreakingChange determines if a change to the symbol would break the reference
 func IsBreakingChange(ref *Reference, symbol *Symbol, changeType string) bool {
 	switch changeType {
 	case "signature-change":
-		// Signature changes affect calls and type dependencies
-		return ref.Kind == RefCall || ref.Kind == RefType
+		// Signature changes affect calls, type dependencies and decorators
+		// (which are calls made by the framework)
+		return ref.Kind == RefCall || ref.Kind == RefType || ref.Kind == RefAnnotation
 
 	case "rename":
 		// Rename affects all references
@@ -123,7 +167,7 @@ func IsBreakingChange(ref *Reference, symbol *Symbol, changeType string) bool {
 
 	case "behavioral-change":
 		// Behavioral changes affect callers but not type dependencies
-		return ref.Kind == RefCall || ref.Kind == RefRead || ref.Kind == RefWrite
+		return ref.Kind == RefCall || ref.Kind == RefRead || ref.Kind == RefWrite || ref.Kind == RefAnnotation
 
 	default:
 		// Conservative: assume breaking for unknown change types
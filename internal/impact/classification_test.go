@@ -47,6 +47,15 @@ func TestClassifyImpact(t *testing.T) {
 			symbol:   &Symbol{Kind: KindClass},
 			expected: DirectCaller,
 		},
+		{
+			name: "decorator",
+			ref: &Reference{
+				Kind:   RefAnnotation,
+				IsTest: false,
+			},
+			symbol:   &Symbol{Kind: KindFunction},
+			expected: AnnotationDependency,
+		},
 		{
 			name: "type reference",
 			ref: &Reference{
@@ -301,3 +310,26 @@ func TestIsBreakingChange(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAnnotationSite(t *testing.T) {
+	tests := []struct {
+		line   string
+		column int
+		want   bool
+	}{
+		{"    @Inject", 6, true},
+		{"@app.route('/users')", 6, true},
+		{"@Component({selector: 'x'})", 2, true},
+		{"  @pytest.mark.parametrize('a', [1])", 15, true},
+		{"    route(app)", 5, false},
+		{"email = 'a@b.com'", 12, false},
+		{"x = a @ matmul", 9, false},
+		{"", 1, false},
+		{"@Inject", 20, false},
+	}
+	for _, tt := range tests {
+		if got := IsAnnotationSite(tt.line, tt.column); got != tt.want {
+			t.Errorf("IsAnnotationSite(%q, %d) = %v, want %v", tt.line, tt.column, got, tt.want)
+		}
+	}
+}
@@ -74,6 +74,7 @@
 //   - TypeDependency: Type references in signatures, parameters, etc.
 //   - TestDependency: References from test code
 //   - ImplementsInterface: Interface implementation relationships
+//   - AnnotationDependency: Uses as an annotation or decorator
 //
 // Risk Scoring:
 //
@@ -9,9 +9,10 @@ import (
 type RiskLevel string
 
 const (
-	RiskHigh   RiskLevel = "high"
-	RiskMedium RiskLevel = "medium"
-	RiskLow    RiskLevel = "low"
+	RiskCritical RiskLevel = "critical" // Only when RiskThresholds.CriticalMin is set
+	RiskHigh     RiskLevel = "high"
+	RiskMedium   RiskLevel = "medium"
+	RiskLow      RiskLevel = "low"
 )
 
 // RiskScore contains the calculated risk assessment
@@ -27,6 +28,105 @@ type RiskFactor struct {
 	Name   string  // Factor name
 	Weight float64 // Weight in the overall calculation
 	Value  float64 // Normalized value (0.0 - 1.0)
+	Detail string  // What drove the value, e.g. "47 direct callers across 6 modules"
+}
+
+// RiskWeightConfig sets how much each factor contributes to the risk score.
+// The four weights must sum to 1.0.
+type RiskWeightConfig struct {
+	Visibility    float64
+	DirectCallers float64
+	ModuleSpread  float64
+	ImpactKind    float64
+}
+
+// riskWeightTolerance is how far the weights may sum from 1.0
+const riskWeightTolerance = 0.01
+
+// DefaultRiskWeights returns the weights used when none are configured
+func DefaultRiskWeights() RiskWeightConfig {
+	return RiskWeightConfig{
+		Visibility:    0.3,
+		DirectCallers: 0.35,
+		ModuleSpread:  0.25,
+		ImpactKind:    0.1,
+	}
+}
+
+// Validate checks that no weight is negative and that they sum to 1.0
+func (w RiskWeightConfig) Validate() error {
+	weights := []float64{w.Visibility, w.DirectCallers, w.ModuleSpread, w.ImpactKind}
+	sum := 0.0
+	for _, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("risk weights must not be negative, got %+v", w)
+		}
+		sum += weight
+	}
+	if math.Abs(sum-1.0) > riskWeightTolerance {
+		return fmt.Errorf("risk weights must sum to 1.0, got %.3f", sum)
+	}
+	return nil
+}
+
+// RiskThresholds maps risk scores to levels: a score at or above a level's
+// minimum gets that level, and anything below MediumMin is low. A zero
+// CriticalMin leaves out the critical level, so scores top out at high.
+// The same type is read from the riskThresholds config section and
+// reported with impact results.
+type RiskThresholds struct {
+	MediumMin   float64 `json:"mediumMin,omitempty" mapstructure:"mediumMin"`
+	HighMin     float64 `json:"highMin,omitempty" mapstructure:"highMin"`
+	CriticalMin float64 `json:"criticalMin,omitempty" mapstructure:"criticalMin"` // Unset when there is no critical level
+}
+
+// DefaultRiskThresholds returns the thresholds used when none are configured
+func DefaultRiskThresholds() RiskThresholds {
+	return RiskThresholds{
+		MediumMin: 0.4,
+		HighMin:   0.7,
+	}
+}
+
+// Effective returns the thresholds with unset (zero) medium and high
+// minimums filled from DefaultRiskThresholds.
+func (t RiskThresholds) Effective() RiskThresholds {
+	defaults := DefaultRiskThresholds()
+	if t.MediumMin == 0 {
+		t.MediumMin = defaults.MediumMin
+	}
+	if t.HighMin == 0 {
+		t.HighMin = defaults.HighMin
+	}
+	return t
+}
+
+// Validate checks that the thresholds lie within [0, 1] and ascend
+func (t RiskThresholds) Validate() error {
+	if t.MediumMin < 0 || t.HighMin > 1 || t.CriticalMin < 0 || t.CriticalMin > 1 {
+		return fmt.Errorf("risk thresholds must lie within [0, 1], got %+v", t)
+	}
+	if t.MediumMin >= t.HighMin {
+		return fmt.Errorf("risk thresholds must ascend, got medium %.2f and high %.2f", t.MediumMin, t.HighMin)
+	}
+	if t.CriticalMin != 0 && t.CriticalMin <= t.HighMin {
+		return fmt.Errorf("risk thresholds must ascend, got high %.2f and critical %.2f", t.HighMin, t.CriticalMin)
+	}
+	return nil
+}
+
+// Level returns the risk level of a score
+func (t RiskThresholds) Level(score float64) RiskLevel {
+	switch {
+	case t.CriticalMin > 0 && score >= t.CriticalMin:
+		return RiskCritical
+	case score >= t.HighMin:
+		return RiskHigh
+	case score >= t.MediumMin:
+		return RiskMedium
+	default:
+		return RiskLow
+	}
 }
 
 // ComputeRiskScore calculates risk based on multiple factors:
@@ -35,13 +135,25 @@ type RiskFactor struct {
 // - Number of modules affected
 // - Presence of test coverage (future v2)
 func ComputeRiskScore(symbol *Symbol, impact []ImpactItem) *RiskScore {
+	return ComputeRiskScoreWithWeights(symbol, impact, DefaultRiskWeights())
+}
+
+// ComputeRiskScoreWithWeights calculates risk like ComputeRiskScore, with
+// the given factor weights. Each factor reports the weight applied to it.
+func ComputeRiskScoreWithWeights(symbol *Symbol, impact []ImpactItem, weights RiskWeightConfig) *RiskScore {
+	return computeRiskScore(symbol, impact, weights, DefaultRiskThresholds())
+}
+
+// computeRiskScore calculates risk with the given weights, leveling the
+// score with the given thresholds.
+func computeRiskScore(symbol *Symbol, impact []ImpactItem, weights RiskWeightConfig, thresholds RiskThresholds) *RiskScore {
 	factors := make([]RiskFactor, 0)
 
 	// Factor 1: Visibility risk
 	visibilityScore := calculateVisibilityRisk(symbol, impact)
 	factors = append(factors, RiskFactor{
 		Name:   "visibility",
-		Weight: 0.3,
+		Weight: weights.Visibility,
 		Value:  visibilityScore,
 	})
 
@@ -49,7 +161,7 @@ func ComputeRiskScore(symbol *Symbol, impact []ImpactItem) *RiskScore {
 	directCallerScore := calculateDirectCallerRisk(impact)
 	factors = append(factors, RiskFactor{
 		Name:   "direct-callers",
-		Weight: 0.35,
+		Weight: weights.DirectCallers,
 		Value:  directCallerScore,
 	})
 
@@ -57,7 +169,7 @@ func ComputeRiskScore(symbol *Symbol, impact []ImpactItem) *RiskScore {
 	moduleSpreadScore := calculateModuleSpreadRisk(impact)
 	factors = append(factors, RiskFactor{
 		Name:   "module-spread",
-		Weight: 0.25,
+		Weight: weights.ModuleSpread,
 		Value:  moduleSpreadScore,
 	})
 
@@ -65,10 +177,14 @@ func ComputeRiskScore(symbol *Symbol, impact []ImpactItem) *RiskScore {
 	impactKindScore := calculateImpactKindRisk(impact)
 	factors = append(factors, RiskFactor{
 		Name:   "impact-kind",
-		Weight: 0.1,
+		Weight: weights.ImpactKind,
 		Value:  impactKindScore,
 	})
 
+	for i := range factors {
+		factors[i].Detail = describeFactor(factors[i], impact)
+	}
+
 	// Calculate weighted score
 	totalScore := 0.0
 	for _, factor := range factors {
@@ -76,7 +192,7 @@ func ComputeRiskScore(symbol *Symbol, impact []ImpactItem) *RiskScore {
 	}
 
 	// Determine risk level
-	level := determineRiskLevel(totalScore)
+	level := thresholds.Level(totalScore)
 
 	// Generate explanation
 	explanation := generateExplanation(level, factors, impact)
@@ -178,12 +294,16 @@ func calculateModuleSpreadRisk(impact []ImpactItem) float64 {
 func calculateImpactKindRisk(impact []ImpactItem) float64 {
 	hasBreaking := false
 	hasImplements := false
+	hasAnnotations := false
 
 	for _, item := range impact {
 		if item.Kind == ImplementsInterface {
 			hasImplements = true
 		}
-		if item.Kind == DirectCaller || item.Kind == ImplementsInterface {
+		if item.Kind == AnnotationDependency {
+			hasAnnotations = true
+		}
+		if IsBreakingImpact(item.Kind) {
 			hasBreaking = true
 		}
 	}
@@ -193,6 +313,11 @@ func calculateImpactKindRisk(impact []ImpactItem) float64 {
 		return 0.9
 	}
 
+	// Annotation uses break at runtime rather than at compile time
+	if hasAnnotations {
+		return 0.8
+	}
+
 	// Direct callers are medium-high risk
 	if hasBreaking {
 		return 0.7
@@ -202,17 +327,6 @@ func calculateImpactKindRisk(impact []ImpactItem) float64 {
 	return 0.4
 }
 
-// determineRiskLevel converts numeric score to risk level
-func determineRiskLevel(score float64) RiskLevel {
-	if score >= 0.7 {
-		return RiskHigh
-	}
-	if score >= 0.4 {
-		return RiskMedium
-	}
-	return RiskLow
-}
-
 // generateExplanation creates a human-readable explanation
 func generateExplanation(level RiskLevel, factors []RiskFactor, impact []ImpactItem) string {
 	directCallers := 0
@@ -230,6 +344,8 @@ func generateExplanation(level RiskLevel, factors []RiskFactor, impact []ImpactI
 	moduleCount := len(modules)
 
 	switch level {
+	case RiskCritical:
+		return fmt.Sprintf("Critical risk: %d direct caller(s) across %d module(s). Changes may break multiple components.", directCallers, moduleCount)
 	case RiskHigh:
 		return fmt.Sprintf("High risk: %d direct caller(s) across %d module(s). Changes may break multiple components.", directCallers, moduleCount)
 	case RiskMedium:
@@ -240,3 +356,62 @@ func generateExplanation(level RiskLevel, factors []RiskFactor, impact []ImpactI
 		return "Unknown risk level."
 	}
 }
+
+// describeFactor states, in concrete terms, what a factor measured. The
+// result is used to rank factors into a narrative, so it names counts
+// rather than repeating the normalized value.
+func describeFactor(factor RiskFactor, impact []ImpactItem) string {
+	directCallers := 0
+	implementations := 0
+	annotations := 0
+	modules := make(map[string]bool)
+	for _, item := range impact {
+		if item.Kind == DirectCaller && item.Distance == 1 {
+			directCallers++
+		}
+		if item.Kind == ImplementsInterface {
+			implementations++
+		}
+		if item.Kind == AnnotationDependency {
+			annotations++
+		}
+		if item.ModuleId != "" {
+			modules[item.ModuleId] = true
+		}
+	}
+
+	switch factor.Name {
+	case "visibility":
+		switch {
+		case factor.Value >= 0.9:
+			return "public API with external consumers"
+		case factor.Value >= 0.5:
+			return "internal API shared within the codebase"
+		default:
+			return "private symbol"
+		}
+	case "direct-callers":
+		return fmt.Sprintf("%s across %s", plural(directCallers, "direct caller"), plural(len(modules), "module"))
+	case "module-spread":
+		return fmt.Sprintf("impact spans %s", plural(len(modules), "module"))
+	case "impact-kind":
+		switch {
+		case implementations > 0:
+			return fmt.Sprintf("%s must stay in sync", plural(implementations, "interface implementation"))
+		case annotations > 0:
+			return fmt.Sprintf("used as an annotation or decorator %s; framework wiring breaks at runtime", plural(annotations, "time"))
+		case directCallers > 0:
+			return "call sites break if the signature changes"
+		default:
+			return "referenced only as a type"
+		}
+	}
+	return ""
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
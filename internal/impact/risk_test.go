@@ -291,6 +291,15 @@ func TestCalculateImpactKindRisk(t *testing.T) {
 			minScore: 0.85,
 			maxScore: 0.95,
 		},
+		{
+			name: "annotation uses - high risk",
+			impact: []ImpactItem{
+				{Kind: DirectCaller},
+				{Kind: AnnotationDependency},
+			},
+			minScore: 0.75,
+			maxScore: 0.85,
+		},
 		{
 			name: "direct callers - medium-high risk",
 			impact: []ImpactItem{
@@ -320,6 +329,58 @@ func TestCalculateImpactKindRisk(t *testing.T) {
 	}
 }
 
+func TestRiskWeightConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights RiskWeightConfig
+		wantErr bool
+	}{
+		{"defaults", DefaultRiskWeights(), false},
+		{"spread heavy", RiskWeightConfig{Visibility: 0.2, DirectCallers: 0.2, ModuleSpread: 0.5, ImpactKind: 0.1}, false},
+		{"within tolerance", RiskWeightConfig{Visibility: 0.3, DirectCallers: 0.3, ModuleSpread: 0.3, ImpactKind: 0.105}, false},
+		{"sum too high", RiskWeightConfig{Visibility: 0.5, DirectCallers: 0.5, ModuleSpread: 0.5, ImpactKind: 0.1}, true},
+		{"sum too low", RiskWeightConfig{Visibility: 0.1, DirectCallers: 0.1}, true},
+		{"negative weight", RiskWeightConfig{Visibility: 0.6, DirectCallers: 0.6, ModuleSpread: -0.3, ImpactKind: 0.1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.weights.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestComputeRiskScoreWithWeights(t *testing.T) {
+	symbol := &Symbol{Name: "Fn"}
+	impact := []ImpactItem{
+		{Kind: DirectCaller, Distance: 1, ModuleId: "a"},
+		{Kind: DirectCaller, Distance: 1, ModuleId: "b"},
+		{Kind: TypeDependency, Distance: 1, ModuleId: "c"},
+	}
+	weights := RiskWeightConfig{Visibility: 0, DirectCallers: 0, ModuleSpread: 1, ImpactKind: 0}
+
+	score := ComputeRiskScoreWithWeights(symbol, impact, weights)
+	if want := calculateModuleSpreadRisk(impact); score.Score != want {
+		t.Errorf("expected score %v from module spread alone, got %v", want, score.Score)
+	}
+	for _, f := range score.Factors {
+		want := 0.0
+		if f.Name == "module-spread" {
+			want = 1
+		}
+		if f.Weight != want {
+			t.Errorf("factor %s: expected weight %v, got %v", f.Name, want, f.Weight)
+		}
+	}
+
+	if got, want := ComputeRiskScore(symbol, impact).Score, ComputeRiskScoreWithWeights(symbol, impact, DefaultRiskWeights()).Score; got != want {
+		t.Errorf("ComputeRiskScore = %v, want default-weighted %v", got, want)
+	}
+}
+
 func TestDetermineRiskLevel(t *testing.T) {
 	tests := []struct {
 		score    float64
@@ -338,7 +399,7 @@ func TestDetermineRiskLevel(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("score "+string(rune(tt.score*100)), func(t *testing.T) {
-			result := determineRiskLevel(tt.score)
+			result := DefaultRiskThresholds().Level(tt.score)
 			if result != tt.expected {
 				t.Errorf("score %f: expected %s, got %s", tt.score, tt.expected, result)
 			}
@@ -346,6 +407,62 @@ func TestDetermineRiskLevel(t *testing.T) {
 	}
 }
 
+func TestRiskThresholdsLevel(t *testing.T) {
+	strict := RiskThresholds{MediumMin: 0.3, HighMin: 0.5, CriticalMin: 0.8}
+	tests := []struct {
+		score    float64
+		expected RiskLevel
+	}{
+		{0.29, RiskLow},
+		{0.3, RiskMedium},
+		{0.49, RiskMedium},
+		{0.5, RiskHigh},
+		{0.79, RiskHigh},
+		{0.8, RiskCritical},
+		{1.0, RiskCritical},
+	}
+	for _, tt := range tests {
+		if got := strict.Level(tt.score); got != tt.expected {
+			t.Errorf("score %.2f: expected %s, got %s", tt.score, tt.expected, got)
+		}
+	}
+}
+
+func TestRiskThresholdsEffective(t *testing.T) {
+	if got := (RiskThresholds{HighMin: 0.5}).Effective(); got != (RiskThresholds{MediumMin: 0.4, HighMin: 0.5}) {
+		t.Errorf("Effective() = %+v, want the default mediumMin with highMin 0.5", got)
+	}
+	if got := (RiskThresholds{}).Effective(); got != DefaultRiskThresholds() {
+		t.Errorf("Effective() of unset thresholds = %+v, want the defaults", got)
+	}
+}
+
+func TestRiskThresholdsValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds RiskThresholds
+		wantErr    bool
+	}{
+		{"defaults", DefaultRiskThresholds(), false},
+		{"with critical", RiskThresholds{MediumMin: 0.3, HighMin: 0.5, CriticalMin: 0.8}, false},
+		{"medium at zero", RiskThresholds{MediumMin: 0, HighMin: 0.5}, false},
+		{"high above one", RiskThresholds{MediumMin: 0.4, HighMin: 1.2}, true},
+		{"negative medium", RiskThresholds{MediumMin: -0.1, HighMin: 0.5}, true},
+		{"medium not below high", RiskThresholds{MediumMin: 0.7, HighMin: 0.7}, true},
+		{"critical below high", RiskThresholds{MediumMin: 0.4, HighMin: 0.7, CriticalMin: 0.6}, true},
+		{"critical above one", RiskThresholds{MediumMin: 0.4, HighMin: 0.7, CriticalMin: 1.5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.thresholds.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestGenerateExplanation(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -400,3 +517,31 @@ func TestGenerateExplanation(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeFactor(t *testing.T) {
+	impact := []ImpactItem{
+		{Kind: DirectCaller, Distance: 1, ModuleId: "m1"},
+		{Kind: DirectCaller, Distance: 1, ModuleId: "m2"},
+		{Kind: TransitiveCaller, Distance: 2, ModuleId: "m2"},
+	}
+
+	tests := []struct {
+		factor RiskFactor
+		want   string
+	}{
+		{RiskFactor{Name: "visibility", Value: 0.9}, "public API with external consumers"},
+		{RiskFactor{Name: "visibility", Value: 0.2}, "private symbol"},
+		{RiskFactor{Name: "direct-callers"}, "2 direct callers across 2 modules"},
+		{RiskFactor{Name: "module-spread"}, "impact spans 2 modules"},
+		{RiskFactor{Name: "impact-kind"}, "call sites break if the signature changes"},
+		{RiskFactor{Name: "unknown"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.factor.Name, func(t *testing.T) {
+			if got := describeFactor(tt.factor, impact); got != tt.want {
+				t.Errorf("describeFactor(%s) = %q, want %q", tt.factor.Name, got, tt.want)
+			}
+		})
+	}
+}
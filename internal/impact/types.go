@@ -47,6 +47,7 @@ const (
 	RefType       ReferenceKind = "type"       // Type reference
 	RefImplements ReferenceKind = "implements" // Interface implementation
 	RefExtends    ReferenceKind = "extends"    // Class extension
+	RefAnnotation ReferenceKind = "annotation" // Annotation or decorator (@Inject, @app.route)
 )
 
 // Reference represents a reference to a symbol
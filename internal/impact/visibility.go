@@ -1,6 +1,7 @@
 package impact
 
 import (
+	"regexp"
 	"strings"
 )
 
@@ -131,6 +132,12 @@ func deriveFromNaming(symbol *Symbol) *VisibilityInfo {
 		return nil
 	}
 
+	// Rust doesn't follow the case conventions below; read its visibility
+	// keywords instead
+	if isRustSymbol(symbol) {
+		return deriveRustVisibility(symbol)
+	}
+
 	// Check for common private naming conventions
 	// Underscore prefix (Python, TypeScript)
 	if strings.HasPrefix(symbol.Name, "_") {
@@ -184,3 +191,94 @@ func deriveFromNaming(symbol *Symbol) *VisibilityInfo {
 
 	return nil
 }
+
+// rustItemDecl matches a Rust item declaration line, capturing its
+// visibility qualifier ("" when the item is private).
+var rustItemDecl = regexp.MustCompile(`^(pub(?:\s*\([^)]*\))?\s+)?(?:(?:async|const|unsafe|default|extern(?:\s+"[^"]*")?)\s+)*(?:fn|struct|enum|trait|type|const|static|mod|union|use|macro_rules!|macro)\b`)
+
+// isRustSymbol reports whether a symbol comes from Rust source, judged by
+// its file extension or the rust-analyzer SCIP scheme.
+func isRustSymbol(symbol *Symbol) bool {
+	if strings.HasPrefix(symbol.StableId, "rust-analyzer ") {
+		return true
+	}
+	return symbol.Location != nil && strings.HasSuffix(symbol.Location.FileId, ".rs")
+}
+
+// deriveRustVisibility applies Rust's rules when no modifiers were indexed.
+// Items in a tests module or integration test file and __-prefixed names
+// are private. Otherwise the symbol's own declaration line in the signature
+// decides: pub (including a pub use re-export) is public, pub(crate),
+// pub(super) and pub(in path) are internal, and no qualifier is private.
+// Only the item's own line counts, so a method without pub on a pub struct
+// stays private. Without a declaration to read, nothing is derived.
+func deriveRustVisibility(symbol *Symbol) *VisibilityInfo {
+	private := &VisibilityInfo{
+		Visibility: VisibilityPrivate,
+		Confidence: 0.6,
+		Source:     "naming-convention",
+	}
+	if strings.HasPrefix(symbol.Name, "__") || inRustTestModule(symbol) {
+		return private
+	}
+
+	qualifier, ok := rustDeclQualifier(symbol.Signature, symbol.Name)
+	if !ok {
+		return nil
+	}
+	qualifier = strings.Join(strings.Fields(qualifier), "")
+	switch {
+	case qualifier == "":
+		return private
+	case qualifier == "pub":
+		return &VisibilityInfo{
+			Visibility: VisibilityPublic,
+			Confidence: 0.6,
+			Source:     "naming-convention",
+		}
+	case qualifier == "pub(self)":
+		return private
+	default:
+		return &VisibilityInfo{
+			Visibility: VisibilityInternal,
+			Confidence: 0.6,
+			Source:     "naming-convention",
+		}
+	}
+}
+
+// inRustTestModule reports whether a Rust symbol lives in a tests module
+// (mod tests) or an integration test under tests/.
+func inRustTestModule(symbol *Symbol) bool {
+	if symbol.ContainerName == "tests" {
+		return true
+	}
+	if strings.Contains(symbol.StableId, " tests/") || strings.Contains(symbol.StableId, "/tests/") {
+		return true
+	}
+	if symbol.Location != nil {
+		file := symbol.Location.FileId
+		return strings.HasPrefix(file, "tests/") || strings.Contains(file, "/tests/")
+	}
+	return false
+}
+
+// rustDeclQualifier finds the line of signature declaring name and returns
+// the visibility qualifier in front of it. Signatures may carry context
+// lines (the enclosing impl or module path) before the declaration; those
+// are skipped unless they declare name themselves.
+func rustDeclQualifier(signature, name string) (string, bool) {
+	if signature == "" || name == "" {
+		return "", false
+	}
+	nameWord := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+	for _, line := range strings.Split(signature, "\n") {
+		line = strings.TrimSpace(line)
+		m := rustItemDecl.FindStringSubmatch(line)
+		if m == nil || !nameWord.MatchString(line) {
+			continue
+		}
+		return strings.TrimSpace(m[1]), true
+	}
+	return "", false
+}
@@ -268,6 +268,97 @@ func TestDeriveFromReferences(t *testing.T) {
 	}
 }
 
+func TestDeriveRustVisibility(t *testing.T) {
+	rs := &Location{FileId: "src/lib.rs"}
+	tests := []struct {
+		name        string
+		symbol      *Symbol
+		expectedVis *Visibility
+	}{
+		{
+			name:        "pub fn",
+			symbol:      &Symbol{Name: "parse", Signature: "pub fn parse(input: &str) -> Ast", Location: rs},
+			expectedVis: ptr(VisibilityPublic),
+		},
+		{
+			name:        "private fn",
+			symbol:      &Symbol{Name: "Parse", Signature: "fn Parse(input: &str)", Location: rs},
+			expectedVis: ptr(VisibilityPrivate),
+		},
+		{
+			name:        "pub(crate) struct",
+			symbol:      &Symbol{Name: "Cache", Signature: "pub(crate) struct Cache", Location: rs},
+			expectedVis: ptr(VisibilityInternal),
+		},
+		{
+			name:        "pub(super) async fn",
+			symbol:      &Symbol{Name: "fetch", Signature: "pub(super) async fn fetch()", Location: rs},
+			expectedVis: ptr(VisibilityInternal),
+		},
+		{
+			name:        "pub(in path) const",
+			symbol:      &Symbol{Name: "LIMIT", Signature: "pub(in crate::net) const LIMIT: usize = 4", Location: rs},
+			expectedVis: ptr(VisibilityInternal),
+		},
+		{
+			name:        "pub use re-export",
+			symbol:      &Symbol{Name: "Client", Signature: "pub use crate::http::Client;", Location: rs},
+			expectedVis: ptr(VisibilityPublic),
+		},
+		{
+			name: "private method on pub struct",
+			symbol: &Symbol{
+				Name:      "reset",
+				Signature: "pub struct Parser\nfn reset(&mut self)",
+				Location:  rs,
+			},
+			expectedVis: ptr(VisibilityPrivate),
+		},
+		{
+			name:        "tests module",
+			symbol:      &Symbol{Name: "Helper", ContainerName: "tests", Signature: "pub fn Helper()", Location: rs},
+			expectedVis: ptr(VisibilityPrivate),
+		},
+		{
+			name:        "integration test file",
+			symbol:      &Symbol{Name: "it_works", Signature: "pub fn it_works()", Location: &Location{FileId: "tests/smoke.rs"}},
+			expectedVis: ptr(VisibilityPrivate),
+		},
+		{
+			name:        "rust-analyzer scheme without file",
+			symbol:      &Symbol{StableId: "rust-analyzer cargo app 0.1.0 net/Conn#", Name: "Conn", Signature: "pub struct Conn"},
+			expectedVis: ptr(VisibilityPublic),
+		},
+		{
+			name:        "no signature",
+			symbol:      &Symbol{Name: "Parser", Location: rs},
+			expectedVis: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := deriveFromNaming(tt.symbol)
+
+			if tt.expectedVis == nil {
+				if result != nil {
+					t.Errorf("expected nil result, got %v", result)
+				}
+				return
+			}
+			if result == nil {
+				t.Fatalf("expected non-nil result")
+			}
+			if result.Visibility != *tt.expectedVis {
+				t.Errorf("expected visibility %s, got %s", *tt.expectedVis, result.Visibility)
+			}
+			if result.Source != "naming-convention" {
+				t.Errorf("expected source naming-convention, got %s", result.Source)
+			}
+		})
+	}
+}
+
 // Helper function to create pointer to Visibility
 func ptr(v Visibility) *Visibility {
 	return &v
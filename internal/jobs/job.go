@@ -29,6 +29,7 @@ const (
 	JobTypeFederationSync      JobType = "federation_sync"
 	JobTypeWebhookDispatch     JobType = "webhook_dispatch"
 	JobTypeScheduledTask       JobType = "scheduled_task"
+	JobTypeReindex             JobType = "reindex"
 )
 
 // Job represents a background task with its state and metadata.
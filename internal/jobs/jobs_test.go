@@ -483,6 +483,31 @@ func TestParseScheduledTaskScope(t *testing.T) {
 	})
 }
 
+func TestParseReindexScope(t *testing.T) {
+	t.Run("empty string", func(t *testing.T) {
+		scope, err := ParseReindexScope("")
+		if err != nil {
+			t.Fatalf("ParseReindexScope() error = %v", err)
+		}
+		if scope != nil {
+			t.Error("Expected nil for empty string")
+		}
+	})
+
+	t.Run("valid JSON", func(t *testing.T) {
+		scope, err := ParseReindexScope(`{"command":"scip-go","repoStateId":"abc","reason":"2 commit(s) behind HEAD"}`)
+		if err != nil {
+			t.Fatalf("ParseReindexScope() error = %v", err)
+		}
+		if scope.Command != "scip-go" {
+			t.Errorf("Command = %q, want 'scip-go'", scope.Command)
+		}
+		if scope.RepoStateID != "abc" {
+			t.Errorf("RepoStateID = %q, want 'abc'", scope.RepoStateID)
+		}
+	})
+}
+
 func TestJobStatusConstants(t *testing.T) {
 	statuses := []JobStatus{JobQueued, JobRunning, JobCompleted, JobFailed, JobCancelled}
 	for _, s := range statuses {
@@ -500,6 +525,7 @@ func TestJobTypeConstants(t *testing.T) {
 		JobTypeFederationSync,
 		JobTypeWebhookDispatch,
 		JobTypeScheduledTask,
+		JobTypeReindex,
 	}
 	for _, jt := range types {
 		if string(jt) == "" {
@@ -155,3 +155,31 @@ func ParseScheduledTaskScope(scopeJSON string) (*ScheduledTaskScope, error) {
 
 	return &scope, nil
 }
+
+// ReindexScope defines the scope for reindex jobs.
+type ReindexScope struct {
+	Command     string `json:"command"`               // Indexer command line
+	RepoStateID string `json:"repoStateId,omitempty"` // Repo state that was found stale
+	Reason      string `json:"reason,omitempty"`
+}
+
+// ParseReindexScope parses the scope JSON for reindex jobs.
+func ParseReindexScope(scopeJSON string) (*ReindexScope, error) {
+	if scopeJSON == "" {
+		return nil, nil
+	}
+
+	var scope ReindexScope
+	if err := json.Unmarshal([]byte(scopeJSON), &scope); err != nil {
+		return nil, err
+	}
+
+	return &scope, nil
+}
+
+// ReindexResult contains the result of a reindex job.
+type ReindexResult struct {
+	Duration      string `json:"duration"`
+	IndexedCommit string `json:"indexedCommit,omitempty"`
+	DocumentCount int    `json:"documentCount"`
+}
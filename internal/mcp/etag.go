@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"ckb/internal/envelope"
+	"ckb/internal/output"
+)
+
+// ifNoneMatchParam is the shared call option carrying the etag of a result
+// the client already holds. When it still matches, the tool returns a
+// notModified stub instead of the full response.
+const ifNoneMatchParam = "ifNoneMatch"
+
+// etagLength is the number of hex digits of the SHA-256 kept in an etag.
+const etagLength = 16
+
+// responseETag hashes a tool response for conditional calls. Time-varying
+// provenance fields in the data are left out as output.CompareSnapshots
+// does, so repeating a query over unchanged code yields the same tag. The
+// output styles are part of the hash since they change the bytes sent.
+func responseETag(resp *envelope.Response, styles ...string) (string, error) {
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return "", err
+	}
+	if normalized, err := output.NormalizeForSnapshot(data); err == nil {
+		data = normalized
+	}
+
+	rest := *resp
+	rest.Data = nil
+	if rest.Meta != nil {
+		meta := *rest.Meta
+		meta.ETag = ""
+		rest.Meta = &meta
+		if meta == (envelope.Meta{}) {
+			rest.Meta = nil
+		}
+	}
+	envelopeBytes, err := json.Marshal(rest)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write(data)
+	h.Write(envelopeBytes)
+	for _, style := range styles {
+		h.Write([]byte{0})
+		h.Write([]byte(style))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:etagLength], nil
+}
+
+// applyETag stamps resp with its etag, or replaces it with a notModified
+// stub when the client's ifNoneMatch already names that etag.
+func applyETag(resp *envelope.Response, ifNoneMatch string, styles ...string) (*envelope.Response, error) {
+	etag, err := responseETag(resp, styles...)
+	if err != nil {
+		return nil, err
+	}
+	if ifNoneMatch != "" && ifNoneMatch == etag {
+		return envelope.New().Data(map[string]interface{}{
+			"notModified": true,
+			"etag":        etag,
+		}).Build(), nil
+	}
+	if resp.Meta == nil {
+		resp.Meta = &envelope.Meta{}
+	}
+	resp.Meta.ETag = etag
+	return resp, nil
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"ckb/internal/envelope"
+)
+
+func TestResponseETag(t *testing.T) {
+	build := func(durationMs int, name string) *envelope.Response {
+		return NewToolResponse().Data(map[string]interface{}{
+			"name":       name,
+			"provenance": map[string]interface{}{"repoStateId": "abc", "queryDurationMs": durationMs},
+		}).Build()
+	}
+
+	first, err := responseETag(build(12, "Engine"))
+	if err != nil {
+		t.Fatalf("responseETag failed: %v", err)
+	}
+	if len(first) != etagLength {
+		t.Errorf("etag %q has length %d, want %d", first, len(first), etagLength)
+	}
+
+	// Query duration is excluded, as in snapshot comparisons
+	if again, _ := responseETag(build(97, "Engine")); again != first {
+		t.Errorf("etag changed with query duration: %s vs %s", first, again)
+	}
+	if changed, _ := responseETag(build(12, "Server")); changed == first {
+		t.Error("etag did not change with the data")
+	}
+	if styled, _ := responseETag(build(12, "Engine"), "module", ""); styled == first {
+		t.Error("etag did not change with the output style")
+	}
+
+	// A stamped etag doesn't feed back into the hash
+	resp := build(12, "Engine")
+	resp.Meta = &envelope.Meta{ETag: "stale"}
+	if stamped, _ := responseETag(resp); stamped != first {
+		t.Errorf("etag depends on the previous etag: %s vs %s", first, stamped)
+	}
+}
+
+func TestCallToolIfNoneMatch(t *testing.T) {
+	server := newTestMCPServer(t)
+	server.tools["getCallGraph"] = func(params map[string]interface{}) (*envelope.Response, error) {
+		return NewToolResponse().Data(map[string]interface{}{"nodes": []string{"a", "b"}}).Build(), nil
+	}
+
+	call := func(args map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		resp := callTool(t, server, "getCallGraph", args)
+		if hasToolError(t, resp) {
+			t.Fatalf("unexpected error: %s", getToolErrorMessage(t, resp))
+		}
+		content := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})
+		var env map[string]interface{}
+		if err := json.Unmarshal([]byte(content[0]["text"].(string)), &env); err != nil {
+			t.Fatalf("invalid envelope: %v", err)
+		}
+		return env
+	}
+
+	full := call(map[string]interface{}{"symbolId": "sym"})
+	etag, _ := full["meta"].(map[string]interface{})["etag"].(string)
+	if etag == "" {
+		t.Fatalf("expected meta.etag, got %v", full["meta"])
+	}
+
+	stub := call(map[string]interface{}{"symbolId": "sym", "ifNoneMatch": etag})
+	data, _ := stub["data"].(map[string]interface{})
+	if data["notModified"] != true || data["etag"] != etag || data["nodes"] != nil {
+		t.Errorf("expected a notModified stub, got %v", stub)
+	}
+
+	stale := call(map[string]interface{}{"symbolId": "sym", "ifNoneMatch": "0000000000000000"})
+	if data, _ := stale["data"].(map[string]interface{}); data["nodes"] == nil {
+		t.Errorf("expected the full response for a stale etag, got %v", stale)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"ckb/internal/backends/scip"
 	"ckb/internal/envelope"
+	"ckb/internal/output"
 )
 
 // handleMessage processes an incoming MCP message and returns a response
@@ -24,6 +26,52 @@ func (s *MCPServer) handleMessage(msg *MCPMessage) *MCPMessage {
 	return NewErrorMessage(msg.Id, InvalidRequest, "Invalid message: not a request or notification", nil)
 }
 
+// handleBatch processes a JSON-RPC batch. Each element is handled on its
+// own, in order, and requests get a response carrying their id; a malformed
+// or failing element only affects its own response. When the batch itself
+// is invalid (not an array, or empty) the single error response is returned
+// instead.
+func (s *MCPServer) handleBatch(data []byte) ([]*MCPMessage, *MCPMessage) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, NewErrorMessage(nil, ParseError, fmt.Sprintf("Failed to parse batch: %v", err), nil)
+	}
+	if len(elements) == 0 {
+		return nil, NewErrorMessage(nil, InvalidRequest, "Invalid request: empty batch", nil)
+	}
+
+	responses := make([]*MCPMessage, 0, len(elements))
+	for _, raw := range elements {
+		if response := s.handleBatchElement(raw); response != nil {
+			responses = append(responses, response)
+		}
+	}
+	return responses, nil
+}
+
+// handleBatchElement handles one batch element, turning a panic into an
+// error response so the rest of the batch still runs.
+func (s *MCPServer) handleBatchElement(raw json.RawMessage) (response *MCPMessage) {
+	msg, err := parseMessage(raw)
+	if err != nil {
+		return NewErrorMessage(nil, InvalidRequest, fmt.Sprintf("Invalid request: %v", err), nil)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Panic handling batch element", map[string]interface{}{
+				"method": msg.Method,
+				"panic":  fmt.Sprint(r),
+			})
+			response = nil
+			if msg.Id != nil {
+				response = NewErrorMessage(msg.Id, InternalError, fmt.Sprintf("Internal error: %v", r), nil)
+			}
+		}
+	}()
+	return s.handleMessage(msg)
+}
+
 // handleRequest handles a JSON-RPC request
 func (s *MCPServer) handleRequest(msg *MCPMessage) *MCPMessage {
 	s.logger.Debug("Handling request", map[string]interface{}{
@@ -180,6 +228,18 @@ func (s *MCPServer) handleListTools(params map[string]interface{}) (interface{},
 	return result, nil
 }
 
+// provenanceExtraFromParams extracts the provenance metadata a client may
+// attach to a tools/call request (params._meta.provenanceExtra). It is merged
+// over the configured provenanceExtra. Returns nil if absent.
+func provenanceExtraFromParams(params map[string]interface{}) map[string]interface{} {
+	meta, ok := params["_meta"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	extra, _ := meta["provenanceExtra"].(map[string]interface{})
+	return extra
+}
+
 // handleCallTool executes a tool
 func (s *MCPServer) handleCallTool(params map[string]interface{}) (interface{}, error) {
 	toolName, ok := params["name"].(string)
@@ -197,24 +257,43 @@ func (s *MCPServer) handleCallTool(params map[string]interface{}) (interface{},
 		return nil, fmt.Errorf("tool not found: %s", toolName)
 	}
 
+	if errs := validateToolParams(s.toolSchema(toolName), toolParams); len(errs) > 0 {
+		return invalidParamsResult(toolName, errs), nil
+	}
+
+	pathStyle, _ := toolParams["pathStyle"].(string)
+	if pathStyle != "" && pathStyle != output.PathStyleRepo && pathStyle != output.PathStyleModule {
+		return nil, fmt.Errorf("invalid 'pathStyle' parameter: %q (expected %q or %q)", pathStyle, output.PathStyleRepo, output.PathStyleModule)
+	}
+	idStyle, _ := toolParams["idStyle"].(string)
+	if idStyle != "" && idStyle != output.IDStyleRaw && idStyle != output.IDStyleReadable {
+		return nil, fmt.Errorf("invalid 'idStyle' parameter: %q (expected %q or %q)", idStyle, output.IDStyleRaw, output.IDStyleReadable)
+	}
+
 	s.logger.Info("Calling tool", map[string]interface{}{
 		"tool":   toolName,
 		"params": toolParams,
 	})
 
-	result, err := handler(toolParams)
+	refreshJobID, err := s.checkIndexFreshness(toolName, toolParams)
 	if err != nil {
-		// Wrap error in envelope format
-		errResp := envelope.New().Data(nil).Error(err).Build()
-		jsonBytes, _ := json.Marshal(errResp)
-		return map[string]interface{}{
-			"content": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": string(jsonBytes),
-				},
-			},
-		}, nil
+		return toolErrorResult(err), nil
+	}
+
+	result, err := s.runToolWithTimeout(toolName, handler, toolParams, progressTokenFromParams(params), provenanceExtraFromParams(params))
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	if result != nil {
+		withIndexRefresh(result, refreshJobID)
+		result.Warnings = envelope.FilterWarnings(result.Warnings, s.minWarningSeverity())
+
+		ifNoneMatch, _ := toolParams[ifNoneMatchParam].(string)
+		result, err = applyETag(result, ifNoneMatch, pathStyle, idStyle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal response: %w", err)
+		}
 	}
 
 	// Marshal the envelope response to JSON
@@ -223,6 +302,27 @@ func (s *MCPServer) handleCallTool(params map[string]interface{}) (interface{},
 		return nil, fmt.Errorf("failed to marshal response: %w", err)
 	}
 
+	// Generated code can produce names and signatures thousands of characters
+	// long; shorten them for the client only
+	jsonBytes, err = output.TruncateSymbolText(jsonBytes, s.maxSymbolTextLength())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	if pathStyle == output.PathStyleModule {
+		jsonBytes, err = output.ModuleRelativePaths(jsonBytes, s.modulePaths())
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal response: %w", err)
+		}
+	}
+
+	if idStyle == output.IDStyleReadable {
+		jsonBytes, err = output.ReadableSymbolIDs(jsonBytes, scip.ReadableSymbolID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal response: %w", err)
+		}
+	}
+
 	return map[string]interface{}{
 		"content": []map[string]interface{}{
 			{
@@ -233,6 +333,20 @@ func (s *MCPServer) handleCallTool(params map[string]interface{}) (interface{},
 	}, nil
 }
 
+// toolErrorResult wraps a tool error in the envelope format.
+func toolErrorResult(err error) map[string]interface{} {
+	errResp := envelope.New().Data(nil).Error(err).Build()
+	jsonBytes, _ := json.Marshal(errResp)
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": string(jsonBytes),
+			},
+		},
+	}
+}
+
 // handleListResources returns the list of available resources
 func (s *MCPServer) handleListResources(params map[string]interface{}) (interface{}, error) {
 	resources, templates := s.GetResourceDefinitions()
@@ -86,23 +86,23 @@ func TestExtractSymbolName(t *testing.T) {
 
 func TestComputeTrend(t *testing.T) {
 	tests := []struct {
-		name   string
-		usages []telemetry.ObservedUsage
-		want   telemetry.UsageTrend
+		name string
+		days []telemetry.DailyUsage
+		want telemetry.UsageTrend
 	}{
 		{
-			name:   "empty",
-			usages: []telemetry.ObservedUsage{},
-			want:   telemetry.TrendStable,
+			name: "empty",
+			days: []telemetry.DailyUsage{},
+			want: telemetry.TrendStable,
 		},
 		{
-			name:   "single element",
-			usages: []telemetry.ObservedUsage{{CallCount: 100}},
-			want:   telemetry.TrendStable,
+			name: "single element",
+			days: []telemetry.DailyUsage{{CallCount: 100}},
+			want: telemetry.TrendStable,
 		},
 		{
 			name: "stable usage",
-			usages: []telemetry.ObservedUsage{
+			days: []telemetry.DailyUsage{
 				{CallCount: 100},
 				{CallCount: 100},
 				{CallCount: 100},
@@ -112,7 +112,7 @@ func TestComputeTrend(t *testing.T) {
 		},
 		{
 			name: "increasing usage",
-			usages: []telemetry.ObservedUsage{
+			days: []telemetry.DailyUsage{
 				{CallCount: 200},
 				{CallCount: 200},
 				{CallCount: 50},
@@ -122,7 +122,7 @@ func TestComputeTrend(t *testing.T) {
 		},
 		{
 			name: "decreasing usage",
-			usages: []telemetry.ObservedUsage{
+			days: []telemetry.DailyUsage{
 				{CallCount: 50},
 				{CallCount: 50},
 				{CallCount: 200},
@@ -132,7 +132,7 @@ func TestComputeTrend(t *testing.T) {
 		},
 		{
 			name: "new calls from zero",
-			usages: []telemetry.ObservedUsage{
+			days: []telemetry.DailyUsage{
 				{CallCount: 100},
 				{CallCount: 0},
 			},
@@ -140,7 +140,7 @@ func TestComputeTrend(t *testing.T) {
 		},
 		{
 			name: "both halves zero",
-			usages: []telemetry.ObservedUsage{
+			days: []telemetry.DailyUsage{
 				{CallCount: 0},
 				{CallCount: 0},
 			},
@@ -150,7 +150,7 @@ func TestComputeTrend(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := computeTrend(tt.usages)
+			result := computeTrend(tt.days)
 			if result != tt.want {
 				t.Errorf("computeTrend() = %v, want %v", result, tt.want)
 			}
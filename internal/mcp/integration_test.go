@@ -145,7 +145,7 @@ func newTestMCPServerWithSCIP(t *testing.T, scipIndexPath string) *MCPServer {
 		Output: io.Discard,
 	})
 
-	db, err := storage.Open(":memory:", logger)
+	db, err := storage.Open(t.TempDir(), logger)
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
@@ -378,6 +378,90 @@ func TestGetCallGraph_WithSCIPFallback(t *testing.T) {
 	}
 }
 
+// TestBatchRequest tests that a JSON-RPC batch gets one id-matched response
+// per request, in order, and that a failing element doesn't stop the others.
+func TestBatchRequest(t *testing.T) {
+	server := newTestMCPServer(t)
+
+	batch := `[` +
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{}},` +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":"not-an-object"},` +
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"getStatus","arguments":{}}}` +
+		`]`
+	single := `{"jsonrpc":"2.0","id":4,"method":"tools/list","params":{}}`
+
+	stdout := &bytes.Buffer{}
+	server.SetStdin(strings.NewReader(batch + "\n" + single + "\n"))
+	server.SetStdout(stdout)
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 output lines (batch, single), got %d: %s", len(lines), stdout.String())
+	}
+
+	var responses []MCPMessage
+	if err := json.Unmarshal([]byte(lines[0]), &responses); err != nil {
+		t.Fatalf("batch response is not an array: %v", err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 batch responses, got %d", len(responses))
+	}
+	for i, resp := range responses {
+		if resp.Id != float64(i+1) {
+			t.Errorf("response %d: id = %v, want %d", i, resp.Id, i+1)
+		}
+	}
+	if responses[0].Error != nil || responses[0].Result == nil {
+		t.Errorf("tools/list should succeed, got error %v", responses[0].Error)
+	}
+	if responses[1].Error == nil || responses[1].Error.Code != InvalidParams {
+		t.Errorf("invalid call should fail with InvalidParams, got %+v", responses[1].Error)
+	}
+	if responses[2].Error != nil || responses[2].Result == nil {
+		t.Errorf("getStatus after a failed element should succeed, got error %v", responses[2].Error)
+	}
+
+	var after MCPMessage
+	if err := json.Unmarshal([]byte(lines[1]), &after); err != nil {
+		t.Fatalf("single request after a batch should get a single response: %v", err)
+	}
+	if after.Id != float64(4) || after.Error != nil {
+		t.Errorf("single response = id %v, error %v", after.Id, after.Error)
+	}
+}
+
+func TestHandleBatchInvalid(t *testing.T) {
+	server := newTestMCPServer(t)
+
+	tests := []struct {
+		name     string
+		input    string
+		wantCode int
+	}{
+		{"empty", `[]`, InvalidRequest},
+		{"malformed", `[{"jsonrpc":"2.0"`, ParseError},
+	}
+	for _, tt := range tests {
+		responses, errResponse := server.handleBatch([]byte(tt.input))
+		if responses != nil || errResponse == nil || errResponse.Error.Code != tt.wantCode {
+			t.Errorf("%s: got responses %v, error %+v; want single error %d", tt.name, responses, errResponse, tt.wantCode)
+		}
+	}
+
+	// A non-object element fails on its own; notifications get no response
+	responses, errResponse := server.handleBatch([]byte(`[1,{"jsonrpc":"2.0","method":"notifications/initialized"}]`))
+	if errResponse != nil {
+		t.Fatalf("unexpected batch error: %+v", errResponse)
+	}
+	if len(responses) != 1 || responses[0].Error == nil || responses[0].Error.Code != InvalidRequest {
+		t.Errorf("expected one InvalidRequest response, got %+v", responses)
+	}
+}
+
 // fileExists checks if a file exists
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
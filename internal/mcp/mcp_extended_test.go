@@ -573,7 +573,7 @@ func TestMCPServerVersion(t *testing.T) {
 		Output: io.Discard,
 	})
 
-	db, err := storage.Open(":memory:", logger)
+	db, err := storage.Open(t.TempDir(), logger)
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
@@ -628,7 +628,7 @@ func newTestMCPServerWithVersion(t *testing.T, ver string) *MCPServer {
 		Output: io.Discard,
 	})
 
-	db, err := storage.Open(":memory:", logger)
+	db, err := storage.Open(t.TempDir(), logger)
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
@@ -673,7 +673,7 @@ func BenchmarkToolsList(b *testing.B) {
 		Output: io.Discard,
 	})
 
-	db, _ := storage.Open(":memory:", logger)
+	db, _ := storage.Open(b.TempDir(), logger)
 	engine, _ := query.NewEngine(".", db, logger, cfg)
 	server := NewMCPServer(version.Version, engine, logger)
 
@@ -707,7 +707,7 @@ func BenchmarkResourcesList(b *testing.B) {
 		Output: io.Discard,
 	})
 
-	db, _ := storage.Open(":memory:", logger)
+	db, _ := storage.Open(b.TempDir(), logger)
 	engine, _ := query.NewEngine(".", db, logger, cfg)
 	server := NewMCPServer(version.Version, engine, logger)
 
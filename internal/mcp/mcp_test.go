@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"ckb/internal/config"
+	"ckb/internal/envelope"
 	"ckb/internal/logging"
 	"ckb/internal/query"
 	"ckb/internal/storage"
@@ -40,8 +41,8 @@ func newTestMCPServer(t *testing.T) *MCPServer {
 		Output: io.Discard,
 	})
 
-	// Create in-memory database
-	db, err := storage.Open(":memory:", logger)
+	// Create a database in a temp directory
+	db, err := storage.Open(t.TempDir(), logger)
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
@@ -399,3 +400,83 @@ func TestNewNotificationMessage(t *testing.T) {
 		t.Error("Should have params")
 	}
 }
+
+func TestWithIndexRefresh(t *testing.T) {
+	resp := envelope.New().Data(nil).Build()
+	withIndexRefresh(resp, "")
+	if resp.Meta != nil || len(resp.Warnings) != 0 {
+		t.Fatalf("expected no changes without a job, got %+v", resp)
+	}
+
+	withIndexRefresh(resp, "job-1")
+	if resp.Meta == nil || resp.Meta.Freshness == nil || resp.Meta.Freshness.IndexAge == nil ||
+		resp.Meta.Freshness.IndexAge.RefreshJobID != "job-1" {
+		t.Fatalf("expected refresh job in freshness metadata, got %+v", resp.Meta)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Code != query.WarnIndexRefreshing {
+		t.Errorf("expected one INDEX_REFRESHING warning, got %+v", resp.Warnings)
+	}
+	if len(resp.SuggestedNextCalls) != 1 || resp.SuggestedNextCalls[0].Tool != "getJobStatus" ||
+		resp.SuggestedNextCalls[0].Params["jobId"] != "job-1" {
+		t.Errorf("expected a getJobStatus suggestion, got %+v", resp.SuggestedNextCalls)
+	}
+
+	// A warning already raised by the engine isn't repeated
+	resp = envelope.New().Data(nil).Build()
+	resp.Warnings = []envelope.Warning{{Code: query.WarnIndexRefreshing, Message: "underway"}}
+	withIndexRefresh(resp, "job-1")
+	if len(resp.Warnings) != 1 {
+		t.Errorf("expected the existing warning only, got %+v", resp.Warnings)
+	}
+}
+
+func TestCheckIndexFreshnessAutoRefreshWithoutIndex(t *testing.T) {
+	server := newTestMCPServer(t)
+
+	jobID, err := server.checkIndexFreshness("findReferences", map[string]interface{}{
+		"autoRefresh":       true,
+		"requireFreshIndex": true,
+	})
+	if err != nil || jobID != "" {
+		t.Errorf("checkIndexFreshness() = %q, %v; want no job and no error without an index", jobID, err)
+	}
+}
+
+func TestCallToolProvenanceExtra(t *testing.T) {
+	server := newTestMCPServer(t)
+
+	resp := sendRequest(t, server, "tools/call", 1, map[string]interface{}{
+		"name":      "getArchitecture",
+		"arguments": map[string]interface{}{},
+		"_meta": map[string]interface{}{
+			"provenanceExtra": map[string]interface{}{"environment": "staging", "indexBuildId": "b-42"},
+		},
+	})
+	if hasToolError(t, resp) {
+		t.Fatalf("unexpected error: %s", getToolErrorMessage(t, resp))
+	}
+
+	content := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})
+	var env envelope.Response
+	if err := json.Unmarshal([]byte(content[0]["text"].(string)), &env); err != nil {
+		t.Fatalf("invalid envelope: %v", err)
+	}
+	if env.Meta == nil || env.Meta.Provenance == nil {
+		t.Fatalf("expected provenance metadata, got %+v", env.Meta)
+	}
+	extra := env.Meta.Provenance.Extra
+	if extra["environment"] != "staging" || extra["indexBuildId"] != "b-42" {
+		t.Errorf("expected provenance extras in the response, got %v", extra)
+	}
+
+	// Extras are scoped to the call that sent them
+	resp = callTool(t, server, "getArchitecture", map[string]interface{}{})
+	content = resp.Result.(map[string]interface{})["content"].([]map[string]interface{})
+	env = envelope.Response{}
+	if err := json.Unmarshal([]byte(content[0]["text"].(string)), &env); err != nil {
+		t.Fatalf("invalid envelope: %v", err)
+	}
+	if env.Meta != nil && env.Meta.Provenance != nil && env.Meta.Provenance.Extra != nil {
+		t.Errorf("expected no extras without _meta, got %v", env.Meta.Provenance.Extra)
+	}
+}
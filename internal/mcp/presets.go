@@ -64,9 +64,12 @@ var Presets = map[string][]string{
 		// Review-specific
 		"summarizeDiff",
 		"summarizePr",
+		"analyzeBranchImpact",
+		"generateReviewChecklist",
 		"getOwnership",
 		"getOwnershipDrift",
 		"recentlyRelevant",
+		"diffArchitecture",
 	},
 
 	// Refactor: core + refactoring analysis tools
@@ -78,9 +81,12 @@ var Presets = map[string][]string{
 		"analyzeImpact", "getHotspots", "getStatus", "expandToolset",
 		// Refactor-specific
 		"justifySymbol",
+		"analyzeFileDeletion",
 		"analyzeCoupling",
 		"findDeadCodeCandidates",
+		"getStaleSymbols",
 		"auditRisk",
+		"getRiskTrend",
 		"explainOrigin",
 	},
 
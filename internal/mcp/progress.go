@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+
+	"ckb/internal/query"
+)
+
+// progressTokenFromParams extracts the progress token a client may attach to
+// a tools/call request (params._meta.progressToken). Returns nil if absent.
+func progressTokenFromParams(params map[string]interface{}) interface{} {
+	meta, ok := params["_meta"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	switch token := meta["progressToken"].(type) {
+	case string, float64:
+		return token
+	}
+	return nil
+}
+
+// setProgressToken records the progress token of the tool call in flight.
+// A handler runs to completion before the next call starts, even after a
+// timeout, so there is at most one.
+func (s *MCPServer) setProgressToken(token interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.progressToken = token
+}
+
+// progressFunc returns a callback that reports engine progress to the client
+// as notifications/progress, or nil when the current call has no progress
+// token. Updates that would not advance progress are dropped, as are updates
+// once the call has returned or timed out, and send failures are only
+// logged.
+func (s *MCPServer) progressFunc() query.ProgressFunc {
+	s.mu.RLock()
+	token := s.progressToken
+	s.mu.RUnlock()
+	if token == nil {
+		return nil
+	}
+	ctx := s.callContext()
+
+	last := -1.0
+	return func(phase string, percent float64) {
+		if percent <= last {
+			return
+		}
+		last = percent
+		err := s.sendProgress(ctx, map[string]interface{}{
+			"progressToken": token,
+			"progress":      percent,
+			"total":         100,
+			"message":       phase,
+		})
+		if err != nil {
+			s.logger.Debug("Failed to send progress notification", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// sendProgress writes a progress notification unless the call's context is
+// done. The check is made under the write lock, so no progress follows the
+// call's response or timeout error.
+func (s *MCPServer) sendProgress(ctx context.Context, params map[string]interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return s.writeMessageLocked(&MCPMessage{
+		Jsonrpc: "2.0",
+		Method:  "notifications/progress",
+		Params:  params,
+	})
+}
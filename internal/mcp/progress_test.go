@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestProgressTokenFromParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   interface{}
+	}{
+		{"no meta", map[string]interface{}{"name": "traceUsage"}, nil},
+		{"string token", map[string]interface{}{"_meta": map[string]interface{}{"progressToken": "abc"}}, "abc"},
+		{"numeric token", map[string]interface{}{"_meta": map[string]interface{}{"progressToken": float64(7)}}, float64(7)},
+		{"invalid token", map[string]interface{}{"_meta": map[string]interface{}{"progressToken": true}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := progressTokenFromParams(tt.params); got != tt.want {
+				t.Errorf("progressTokenFromParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressFuncSendsMonotonicNotifications(t *testing.T) {
+	server := newTestMCPServer(t)
+	stdout := &bytes.Buffer{}
+	server.SetStdout(stdout)
+
+	if server.progressFunc() != nil {
+		t.Fatal("expected no progress callback without a token")
+	}
+
+	server.setProgressToken("tok-1")
+	progress := server.progressFunc()
+	server.setProgressToken(nil)
+
+	progress("resolving target", 0)
+	progress("tracing entrypoints", 50)
+	progress("tracing entrypoints", 40) // never goes backwards
+	progress("done", 100)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 notifications, got %d: %s", len(lines), stdout.String())
+	}
+
+	var last map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[2]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last["method"] != "notifications/progress" {
+		t.Errorf("method = %v, want notifications/progress", last["method"])
+	}
+	params, _ := last["params"].(map[string]interface{})
+	if params["progressToken"] != "tok-1" || params["progress"] != float64(100) || params["message"] != "done" {
+		t.Errorf("unexpected progress params: %v", params)
+	}
+}
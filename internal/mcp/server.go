@@ -2,13 +2,17 @@ package mcp
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"sync"
 	"time"
 
+	"ckb/internal/config"
+	"ckb/internal/envelope"
 	"ckb/internal/logging"
+	"ckb/internal/output"
 	"ckb/internal/query"
 	"ckb/internal/repos"
 )
@@ -49,6 +53,24 @@ type MCPServer struct {
 	activePreset string // current preset (core, review, refactor, etc.)
 	toolsetHash  string // hash of current tool definitions (for cursor invalidation)
 	expanded     bool   // true if expandToolset has been called this session
+
+	// Progress token of the tool call being handled, if the client sent one
+	progressToken interface{}
+
+	// Context of the tool call being handled; canceled when it times out
+	callCtx context.Context
+
+	// Closed when the last tool handler returns, which may be after its
+	// call timed out
+	handlerDone chan struct{}
+
+	// Serializes writes to stdout; progress can come from a handler
+	// while a response is being written
+	writeMu sync.Mutex
+
+	// Input schemas by tool name, built on first use for validation
+	toolSchemas map[string]map[string]interface{}
+	schemaOnce  sync.Once
 }
 
 // NewMCPServer creates a new MCP server in legacy single-engine mode
@@ -124,6 +146,128 @@ func (s *MCPServer) engine() *query.Engine {
 	return entry.engine
 }
 
+// maxSymbolTextLength returns the configured cap on symbol names and
+// signatures in tool output, or 0 for the default.
+func (s *MCPServer) maxSymbolTextLength() int {
+	engine := s.engine()
+	if engine == nil || engine.GetConfig() == nil {
+		return 0
+	}
+	return engine.GetConfig().Budget.MaxSymbolTextLength
+}
+
+// toolTimeout returns how long a call to the named tool may run.
+func (s *MCPServer) toolTimeout(tool string) time.Duration {
+	engine := s.engine()
+	if engine == nil || engine.GetConfig() == nil {
+		return config.MCPConfig{}.ToolTimeout(tool)
+	}
+	return engine.GetConfig().MCP.ToolTimeout(tool)
+}
+
+// minWarningSeverity returns the configured warnings.minSeverity policy.
+func (s *MCPServer) minWarningSeverity() string {
+	engine := s.engine()
+	if engine == nil || engine.GetConfig() == nil {
+		return ""
+	}
+	return engine.GetConfig().Warnings.MinSeverity
+}
+
+// modulePaths returns the module splitter for pathStyle=module output.
+func (s *MCPServer) modulePaths() *output.ModulePaths {
+	engine := s.engine()
+	if engine == nil {
+		return nil
+	}
+	return output.NewModulePaths(engine.ModuleRoots())
+}
+
+// checkIndexFreshness enforces the index freshness policy for SCIP-backed
+// tools. The requireFreshIndex and maxCommitsBehind arguments override the
+// configured policy for a single call.
+//
+// With autoRefresh, a stale index also starts a background reindex (or
+// joins the one underway), whose job ID is returned. The tool then answers
+// from the stale index even when the policy requires a fresh one. Without
+// it, the configured autoReindex may still start one; its job is reported
+// but the policy holds.
+func (s *MCPServer) checkIndexFreshness(toolName string, params map[string]interface{}) (string, error) {
+	if !scipBackedTools[toolName] {
+		return "", nil
+	}
+	engine := s.engine()
+	if engine == nil {
+		return "", nil
+	}
+
+	var refreshJobID string
+	autoRefresh, _ := params["autoRefresh"].(bool)
+	if autoRefresh {
+		jobID, err := engine.RefreshStaleIndex(s.callContext())
+		if err != nil {
+			s.logger.Warn("Could not start index refresh", map[string]interface{}{
+				"tool":  toolName,
+				"error": err.Error(),
+			})
+		}
+		refreshJobID = jobID
+	} else {
+		refreshJobID = engine.AutoReindex(s.callContext())
+	}
+
+	policy := engine.FreshnessPolicy()
+	if v, ok := params["requireFreshIndex"].(bool); ok {
+		policy.RequireFresh = v
+	}
+	if v, ok := params["maxCommitsBehind"].(float64); ok {
+		policy.MaxCommitsBehind = int(v)
+	}
+	if err := engine.CheckIndexFreshness(policy); err != nil && (!autoRefresh || refreshJobID == "") {
+		return "", err
+	}
+	return refreshJobID, nil
+}
+
+// withIndexRefresh points a response at the background reindex started for
+// it: the job ID goes into the freshness metadata, with a warning and a
+// suggested getJobStatus call.
+func withIndexRefresh(resp *envelope.Response, jobID string) {
+	if resp == nil || jobID == "" {
+		return
+	}
+	if resp.Meta == nil {
+		resp.Meta = &envelope.Meta{}
+	}
+	if resp.Meta.Freshness == nil {
+		resp.Meta.Freshness = &envelope.Freshness{}
+	}
+	if resp.Meta.Freshness.IndexAge == nil {
+		resp.Meta.Freshness.IndexAge = &envelope.IndexAge{}
+	}
+	resp.Meta.Freshness.IndexAge.RefreshJobID = jobID
+
+	hasWarning := false
+	for _, w := range resp.Warnings {
+		if w.Code == query.WarnIndexRefreshing {
+			hasWarning = true
+			break
+		}
+	}
+	if !hasWarning {
+		resp.Warnings = append(resp.Warnings, envelope.Warning{
+			Severity: output.SeverityInfo,
+			Code:     query.WarnIndexRefreshing,
+			Message:  fmt.Sprintf("SCIP index is stale; a background reindex is underway (job %s). Results use the current index until it completes.", jobID),
+		})
+	}
+	resp.SuggestedNextCalls = append(resp.SuggestedNextCalls, envelope.SuggestedCall{
+		Tool:   "getJobStatus",
+		Params: map[string]interface{}{"jobId": jobID},
+		Reason: "Check when the refreshed index is ready",
+	})
+}
+
 // GetEngine returns the current engine or an error if none is active
 func (s *MCPServer) GetEngine() (*query.Engine, error) {
 	engine := s.engine()
@@ -176,7 +320,16 @@ func (s *MCPServer) Start() error {
 
 	// Main message loop
 	for {
-		msg, err := s.readMessage()
+		line, err := s.readLine()
+		if err == nil && isBatch(line) {
+			s.serveBatch(line)
+			continue
+		}
+
+		var msg *MCPMessage
+		if err == nil {
+			msg, err = parseMessage(line)
+		}
 		if err != nil {
 			if err == io.EOF {
 				s.logger.Info("MCP server shutting down (EOF)", nil)
@@ -207,6 +360,25 @@ func (s *MCPServer) Start() error {
 	}
 }
 
+// serveBatch handles a JSON-RPC batch and writes its responses.
+func (s *MCPServer) serveBatch(line []byte) {
+	responses, errResponse := s.handleBatch(line)
+
+	var err error
+	switch {
+	case errResponse != nil:
+		err = s.writeMessage(errResponse)
+	case len(responses) > 0:
+		// A batch of notifications gets no response at all
+		err = s.writeBatch(responses)
+	}
+	if err != nil {
+		s.logger.Error("Error writing response", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
 // SetStdin sets the input stream (for testing)
 func (s *MCPServer) SetStdin(r io.Reader) {
 	s.stdin = r
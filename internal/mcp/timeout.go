@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ckb/internal/envelope"
+	"ckb/internal/errors"
+	"ckb/internal/query"
+)
+
+// setCallContext records the context of the tool call in flight. Like the
+// progress token, there is at most one, and it stays set until the handler
+// returns.
+func (s *MCPServer) setCallContext(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callCtx = ctx
+}
+
+// callContext returns the context tool handlers pass to the engine. It is
+// canceled when the call times out, so cooperative backends stop early.
+// Outside a tool call (e.g. handlers invoked directly) it never expires.
+func (s *MCPServer) callContext() context.Context {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.callCtx == nil {
+		return context.Background()
+	}
+	return s.callCtx
+}
+
+// toolResult is the outcome of a handler run by runToolWithTimeout.
+type toolResult struct {
+	resp *envelope.Response
+	err  error
+}
+
+// runToolWithTimeout runs a tool handler under the tool's timeout. When the
+// timeout expires first, the call's context is canceled and a Timeout
+// error is returned straight away; the handler is left to notice the
+// cancellation and finish in the background, and its result is discarded.
+// The next call waits for it, so handlers still run one at a time, and its
+// progress updates stop once the call has returned. Provenance extras, if
+// any, are carried on the call context into every response the call builds.
+func (s *MCPServer) runToolWithTimeout(name string, handler ToolHandler, params map[string]interface{}, progressToken interface{}, provenanceExtra map[string]interface{}) (*envelope.Response, error) {
+	s.waitForHandler()
+
+	timeout := s.toolTimeout(name)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if len(provenanceExtra) > 0 {
+		ctx = query.WithProvenanceExtra(ctx, provenanceExtra)
+	}
+
+	s.setCallContext(ctx)
+	s.setProgressToken(progressToken)
+	handlerDone := make(chan struct{})
+	s.mu.Lock()
+	s.handlerDone = handlerDone
+	s.mu.Unlock()
+
+	done := make(chan toolResult, 1)
+	go func() {
+		defer close(handlerDone)
+		defer s.setProgressToken(nil)
+		defer s.setCallContext(nil)
+		// A panic would otherwise take down the server from this goroutine
+		defer func() {
+			if r := recover(); r != nil {
+				done <- toolResult{err: fmt.Errorf("tool %s panicked: %v", name, r)}
+			}
+		}()
+		resp, err := handler(params)
+		done <- toolResult{resp: resp, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.resp, result.err
+	case <-ctx.Done():
+		s.logger.Warn("Tool call timed out", map[string]interface{}{
+			"tool":    name,
+			"timeout": timeout.String(),
+		})
+		return nil, toolTimeoutError(name, timeout)
+	}
+}
+
+// waitForHandler blocks until the handler of the previous tool call, if
+// any, has returned.
+func (s *MCPServer) waitForHandler() {
+	s.mu.RLock()
+	handlerDone := s.handlerDone
+	s.mu.RUnlock()
+	if handlerDone != nil {
+		<-handlerDone
+	}
+}
+
+// toolTimeoutError reports a tool call that exceeded its timeout.
+func toolTimeoutError(name string, timeout time.Duration) *errors.CkbError {
+	return errors.NewCkbError(
+		errors.Timeout,
+		fmt.Sprintf("%s did not finish within %s; narrow the query or raise mcp.toolTimeoutsMs.%s", name, timeout, name),
+		context.DeadlineExceeded,
+		nil,
+		nil,
+	)
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"ckb/internal/envelope"
+)
+
+func TestToolTimeout_SlowBackend(t *testing.T) {
+	server := newTestMCPServer(t)
+	server.engine().GetConfig().MCP.ToolTimeoutsMs = map[string]int{"slowTool": 20}
+
+	// Stands in for a handler stuck in a slow backend call that only
+	// returns once its context is canceled
+	canceled := make(chan struct{})
+	server.tools["slowTool"] = func(params map[string]interface{}) (*envelope.Response, error) {
+		ctx := server.callContext()
+		select {
+		case <-ctx.Done():
+			close(canceled)
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+			return OperationalResponse(map[string]interface{}{"done": true}), nil
+		}
+	}
+
+	start := time.Now()
+	resp := callTool(t, server, "slowTool", map[string]interface{}{})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("call took %v, timeout did not fire", elapsed)
+	}
+	if !hasToolError(t, resp) {
+		t.Fatal("expected a tool error for the timed-out call")
+	}
+	result := resp.Result.(map[string]interface{})
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "slowTool did not finish within 20ms") {
+		t.Errorf("error should name the tool and timeout: %s", text)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler context was not canceled")
+	}
+
+	server.waitForHandler()
+	if ctx := server.callContext(); ctx.Err() != nil {
+		t.Errorf("call context outside a call should not be canceled: %v", ctx.Err())
+	}
+}
+
+func TestToolTimeout_DetachedHandler(t *testing.T) {
+	server := newTestMCPServer(t)
+	stdout := &bytes.Buffer{}
+	server.SetStdout(stdout)
+	server.engine().GetConfig().MCP.ToolTimeoutsMs = map[string]int{"slowTool": 20}
+
+	// Keeps reporting progress after its call has timed out
+	finished := make(chan struct{})
+	server.tools["slowTool"] = func(params map[string]interface{}) (*envelope.Response, error) {
+		defer close(finished)
+		ctx := server.callContext()
+		progress := server.progressFunc()
+		progress("started", 10)
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		progress("still running", 50)
+		return nil, ctx.Err()
+	}
+	var overlapped bool
+	server.tools["nextTool"] = func(params map[string]interface{}) (*envelope.Response, error) {
+		select {
+		case <-finished:
+		default:
+			overlapped = true
+		}
+		return OperationalResponse(map[string]interface{}{"done": true}), nil
+	}
+
+	server.SetStdin(strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slowTool","arguments":{},"_meta":{"progressToken":"tok-1"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"nextTool","arguments":{}}}` + "\n"))
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if overlapped {
+		t.Error("the next call started while the timed-out handler was still running")
+	}
+
+	// Progress from before the timeout, then the two responses, each on
+	// its own line
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("malformed output line %q: %v", line, err)
+		}
+		lines = append(lines, msg)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("expected progress and two responses, got %q", stdout.String())
+	}
+	if params, _ := lines[0]["params"].(map[string]interface{}); params["message"] != "started" {
+		t.Errorf("first line should be the progress sent before the timeout, got %v", lines[0])
+	}
+	if lines[1]["id"] != float64(1) || lines[2]["id"] != float64(2) {
+		t.Errorf("expected the responses to follow in order, got %v and %v", lines[1], lines[2])
+	}
+}
+
+func TestToolTimeout_FastToolUnaffected(t *testing.T) {
+	server := newTestMCPServer(t)
+	resp := callTool(t, server, "getStatus", map[string]interface{}{})
+	if hasToolError(t, resp) {
+		t.Fatal("getStatus should complete within the default timeout")
+	}
+}
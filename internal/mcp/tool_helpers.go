@@ -54,6 +54,12 @@ func (t *ToolResponse) Warning(msg string) *ToolResponse {
 	return t
 }
 
+// TypedWarning adds a warning with an explicit severity and code.
+func (t *ToolResponse) TypedWarning(severity, code, msg string) *ToolResponse {
+	t.builder.TypedWarning(severity, code, msg)
+	return t
+}
+
 // CrossRepo marks this as a cross-repo query.
 func (t *ToolResponse) CrossRepo() *ToolResponse {
 	t.builder.CrossRepo()
@@ -281,7 +281,7 @@ func TestFullToolResponseFlow(t *testing.T) {
 			Score:  0.92,
 			Reason: "SCIP+git hybrid",
 		},
-		Warnings: []string{"index slightly stale"},
+		Warnings: []output.Warning{{Severity: output.SeverityInfo, Text: "index slightly stale"}},
 	}
 
 	drilldowns := []output.Drilldown{
@@ -6,10 +6,13 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"ckb/internal/complexity"
 	"ckb/internal/envelope"
 	"ckb/internal/jobs"
+	"ckb/internal/output"
 	"ckb/internal/query"
 )
 
@@ -19,12 +22,19 @@ func (s *MCPServer) toolGetStatus(params map[string]interface{}) (*envelope.Resp
 		"params": params,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	statusResp, err := s.engine().GetStatus(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status: %w", err)
 	}
 
+	if format, _ := params["format"].(string); format == query.StatusFormatPrometheus {
+		return OperationalResponse(map[string]interface{}{
+			"format":     query.StatusFormatPrometheus,
+			"prometheus": statusResp.Prometheus(),
+		}), nil
+	}
+
 	backends := make([]map[string]interface{}, 0, len(statusResp.Backends))
 	for _, b := range statusResp.Backends {
 		backends = append(backends, map[string]interface{}{
@@ -53,6 +63,7 @@ func (s *MCPServer) toolGetStatus(params map[string]interface{}) (*envelope.Resp
 			"queriesCached": statusResp.Cache.QueriesCached,
 			"viewsCached":   statusResp.Cache.ViewsCached,
 			"hitRate":       statusResp.Cache.HitRate,
+			"callGraph":     statusResp.Cache.CallGraph,
 		},
 		"repoState": map[string]interface{}{
 			"dirty":       statusResp.RepoState.Dirty,
@@ -76,7 +87,7 @@ func (s *MCPServer) toolDoctor(params map[string]interface{}) (*envelope.Respons
 		"params": params,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	doctorResp, err := s.engine().Doctor(ctx, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to run diagnostics: %w", err)
@@ -188,15 +199,24 @@ func (s *MCPServer) toolGetSymbol(params map[string]interface{}) (*envelope.Resp
 		repoStateMode = "head"
 	}
 
+	includeSource, _ := params["includeSource"].(bool)
+	contextLines := 0
+	if v, ok := params["contextLines"].(float64); ok {
+		contextLines = int(v)
+	}
+
 	s.logger.Debug("Executing getSymbol", map[string]interface{}{
 		"symbolId":      symbolId,
 		"repoStateMode": repoStateMode,
+		"includeSource": includeSource,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	opts := query.GetSymbolOptions{
 		SymbolId:      symbolId,
 		RepoStateMode: repoStateMode,
+		IncludeSource: includeSource,
+		ContextLines:  contextLines,
 	}
 
 	symbolResp, err := s.engine().GetSymbol(ctx, opts)
@@ -248,6 +268,9 @@ func (s *MCPServer) toolGetSymbol(params map[string]interface{}) (*envelope.Resp
 
 		data["symbol"] = symbolInfo
 	}
+	if symbolResp.SourceSnippet != nil {
+		data["sourceSnippet"] = symbolResp.SourceSnippet
+	}
 
 	return NewToolResponse().
 		Data(data).
@@ -280,19 +303,32 @@ func (s *MCPServer) toolSearchSymbols(params map[string]interface{}) (*envelope.
 		limit = int(limitVal)
 	}
 
+	minConfidence, err := minConfidenceParam(params)
+	if err != nil {
+		return nil, err
+	}
+
+	includePrivate := true
+	if v, ok := params["includePrivate"].(bool); ok {
+		includePrivate = v
+	}
+
 	s.logger.Debug("Executing searchSymbols", map[string]interface{}{
-		"query": queryStr,
-		"scope": scope,
-		"kinds": kinds,
-		"limit": limit,
+		"query":          queryStr,
+		"scope":          scope,
+		"kinds":          kinds,
+		"limit":          limit,
+		"includePrivate": includePrivate,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	opts := query.SearchSymbolsOptions{
-		Query: queryStr,
-		Scope: scope,
-		Kinds: kinds,
-		Limit: limit,
+		Query:          queryStr,
+		Scope:          scope,
+		Kinds:          kinds,
+		Limit:          limit,
+		MinConfidence:  minConfidence,
+		IncludePrivate: includePrivate,
 	}
 
 	searchResp, err := s.engine().SearchSymbols(ctx, opts)
@@ -308,6 +344,9 @@ func (s *MCPServer) toolSearchSymbols(params map[string]interface{}) (*envelope.
 			"kind":     sym.Kind,
 			"score":    sym.Score,
 		}
+		if sym.Confidence > 0 {
+			symbolInfo["confidence"] = sym.Confidence
+		}
 
 		if sym.ModuleId != "" {
 			symbolInfo["moduleId"] = sym.ModuleId
@@ -344,6 +383,12 @@ func (s *MCPServer) toolSearchSymbols(params map[string]interface{}) (*envelope.
 		"symbols":    symbols,
 		"totalCount": searchResp.TotalCount,
 	}
+	if searchResp.FilteredByConfidence > 0 {
+		data["filteredByConfidence"] = searchResp.FilteredByConfidence
+	}
+	if searchResp.FilteredPrivate > 0 {
+		data["filteredPrivate"] = searchResp.FilteredPrivate
+	}
 
 	// Record wide-result metrics
 	responseBytes := MeasureJSONSize(data)
@@ -364,6 +409,19 @@ func (s *MCPServer) toolSearchSymbols(params map[string]interface{}) (*envelope.
 		Build(), nil
 }
 
+// minConfidenceParam reads the optional minConfidence threshold shared by
+// the confidence-bearing tools. Absent means no filtering.
+func minConfidenceParam(params map[string]interface{}) (float64, error) {
+	v, ok := params["minConfidence"].(float64)
+	if !ok {
+		return 0, nil
+	}
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("'minConfidence' must be between 0 and 1, got %g", v)
+	}
+	return v, nil
+}
+
 // toolFindReferences implements the findReferences tool
 func (s *MCPServer) toolFindReferences(params map[string]interface{}) (*envelope.Response, error) {
 	timer := NewWideResultTimer()
@@ -385,19 +443,47 @@ func (s *MCPServer) toolFindReferences(params map[string]interface{}) (*envelope
 		includeTests = includeVal
 	}
 
+	includeDynamicDispatch := false
+	if v, ok := params["includeDynamicDispatch"].(bool); ok {
+		includeDynamicDispatch = v
+	}
+
+	externalOnly := false
+	if v, ok := params["externalOnly"].(bool); ok {
+		externalOnly = v
+	}
+
+	cursor, _ := params["cursor"].(string)
+
+	var kinds []string
+	if kindsVal, ok := params["kinds"].([]interface{}); ok {
+		for _, k := range kindsVal {
+			if kind, ok := k.(string); ok {
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+
 	s.logger.Debug("Executing findReferences", map[string]interface{}{
-		"symbolId":     symbolId,
-		"scope":        scope,
-		"limit":        limit,
-		"includeTests": includeTests,
+		"symbolId":               symbolId,
+		"scope":                  scope,
+		"limit":                  limit,
+		"includeTests":           includeTests,
+		"includeDynamicDispatch": includeDynamicDispatch,
+		"externalOnly":           externalOnly,
+		"kinds":                  kinds,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	opts := query.FindReferencesOptions{
-		SymbolId:     symbolId,
-		Scope:        scope,
-		IncludeTests: includeTests,
-		Limit:        limit,
+		SymbolId:               symbolId,
+		Scope:                  scope,
+		IncludeTests:           includeTests,
+		Limit:                  limit,
+		IncludeDynamicDispatch: includeDynamicDispatch,
+		ExternalOnly:           externalOnly,
+		Cursor:                 cursor,
+		Kinds:                  kinds,
 	}
 
 	refsResp, err := s.engine().FindReferences(ctx, opts)
@@ -416,6 +502,12 @@ func (s *MCPServer) toolFindReferences(params map[string]interface{}) (*envelope
 			ref["context"] = r.Context
 		}
 
+		if r.Dispatch != "" {
+			ref["dispatch"] = r.Dispatch
+			ref["confidence"] = r.Confidence
+			ref["via"] = r.Via
+		}
+
 		if r.Location != nil {
 			ref["location"] = map[string]interface{}{
 				"fileId":      r.Location.FileId,
@@ -433,6 +525,15 @@ func (s *MCPServer) toolFindReferences(params map[string]interface{}) (*envelope
 		"references": refs,
 		"totalCount": refsResp.TotalCount,
 	}
+	if refsResp.NextCursor != "" {
+		data["nextCursor"] = refsResp.NextCursor
+	}
+	if refsResp.DynamicDispatch != nil {
+		data["dynamicDispatch"] = refsResp.DynamicDispatch
+	}
+	if refsResp.External != nil {
+		data["external"] = refsResp.External
+	}
 
 	// Record wide-result metrics
 	responseBytes := MeasureJSONSize(data)
@@ -476,12 +577,18 @@ func (s *MCPServer) toolGetArchitecture(params map[string]interface{}) (*envelop
 		"refresh":             refresh,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	opts := query.GetArchitectureOptions{
 		Depth:               depth,
 		IncludeExternalDeps: includeExternalDeps,
 		Refresh:             refresh,
 	}
+	if format, ok := params["format"].(string); ok {
+		opts.Format = format
+	}
+	if minSymbols, ok := params["minSymbols"].(float64); ok {
+		opts.MinSymbols = int(minSymbols)
+	}
 
 	archResp, err := s.engine().GetArchitecture(ctx, opts)
 	if err != nil {
@@ -503,6 +610,9 @@ func (s *MCPServer) toolGetArchitecture(params map[string]interface{}) (*envelop
 		if m.Language != "" {
 			moduleInfo["language"] = m.Language
 		}
+		if m.CollapsedCount > 0 {
+			moduleInfo["collapsedCount"] = m.CollapsedCount
+		}
 
 		modules = append(modules, moduleInfo)
 	}
@@ -510,12 +620,16 @@ func (s *MCPServer) toolGetArchitecture(params map[string]interface{}) (*envelop
 	// Convert dependency graph edges
 	depEdges := make([]map[string]interface{}, 0, len(archResp.DependencyGraph))
 	for _, edge := range archResp.DependencyGraph {
-		depEdges = append(depEdges, map[string]interface{}{
+		edgeInfo := map[string]interface{}{
 			"from":     edge.From,
 			"to":       edge.To,
 			"kind":     edge.Kind,
 			"strength": edge.Strength,
-		})
+		}
+		if len(edge.KindStrengths) > 0 {
+			edgeInfo["kindStrengths"] = edge.KindStrengths
+		}
+		depEdges = append(depEdges, edgeInfo)
 	}
 
 	data := map[string]interface{}{
@@ -528,6 +642,16 @@ func (s *MCPServer) toolGetArchitecture(params map[string]interface{}) (*envelop
 	if len(archResp.Limitations) > 0 {
 		data["limitations"] = archResp.Limitations
 	}
+	if archResp.CycleCount > 0 {
+		data["cycles"] = archResp.Cycles
+		data["cycleCount"] = archResp.CycleCount
+	}
+	if archResp.Mermaid != "" {
+		data["mermaid"] = archResp.Mermaid
+	}
+	if archResp.CollapsedModules > 0 {
+		data["collapsedModules"] = archResp.CollapsedModules
+	}
 
 	resp := NewToolResponse().
 		Data(data).
@@ -538,6 +662,56 @@ func (s *MCPServer) toolGetArchitecture(params map[string]interface{}) (*envelop
 	return resp.Build(), nil
 }
 
+// toolDiffArchitecture implements the diffArchitecture tool
+func (s *MCPServer) toolDiffArchitecture(params map[string]interface{}) (*envelope.Response, error) {
+	base, ok := params["base"].(string)
+	if !ok || base == "" {
+		return nil, fmt.Errorf("missing or invalid 'base' parameter")
+	}
+
+	head := "HEAD"
+	if v, ok := params["head"].(string); ok && v != "" {
+		head = v
+	}
+
+	includeExternalDeps := false
+	if v, ok := params["includeExternalDeps"].(bool); ok {
+		includeExternalDeps = v
+	}
+
+	threshold := 0.0 // 0 = default (0.5)
+	if v, ok := params["strengthChangeThreshold"].(float64); ok {
+		threshold = v
+	}
+
+	s.logger.Debug("Executing diffArchitecture", map[string]interface{}{
+		"base":                base,
+		"head":                head,
+		"includeExternalDeps": includeExternalDeps,
+	})
+
+	ctx := s.callContext()
+	resp, err := s.engine().DiffArchitecture(ctx, query.DiffArchitectureOptions{
+		Base:                    base,
+		Head:                    head,
+		IncludeExternalDeps:     includeExternalDeps,
+		StrengthChangeThreshold: threshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("architecture diff failed: %w", err)
+	}
+
+	toolResp := NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance).
+		WithDrilldowns(resp.Drilldowns)
+	for _, limitation := range resp.Limitations {
+		toolResp.TypedWarning(output.SeverityWarning, query.WarnAnalysisLimited, limitation)
+	}
+
+	return toolResp.Build(), nil
+}
+
 // toolAnalyzeImpact implements the analyzeImpact tool
 func (s *MCPServer) toolAnalyzeImpact(params map[string]interface{}) (*envelope.Response, error) {
 	timer := NewWideResultTimer()
@@ -547,7 +721,7 @@ func (s *MCPServer) toolAnalyzeImpact(params map[string]interface{}) (*envelope.
 		return nil, fmt.Errorf("missing or invalid 'symbolId' parameter")
 	}
 
-	depth := 2
+	depth := 0 // 0 = configured default (traversal.impact)
 	if depthVal, ok := params["depth"].(float64); ok {
 		depth = int(depthVal)
 	}
@@ -563,18 +737,39 @@ func (s *MCPServer) toolAnalyzeImpact(params map[string]interface{}) (*envelope.
 		telemetryPeriod = v
 	}
 
+	onlyBreaking := false
+	if v, ok := params["onlyBreakingChanges"].(bool); ok {
+		onlyBreaking = v
+	}
+
+	minConfidence, err := minConfidenceParam(params)
+	if err != nil {
+		return nil, err
+	}
+
 	s.logger.Debug("Executing analyzeImpact", map[string]interface{}{
-		"symbolId":         symbolId,
-		"depth":            depth,
-		"includeTelemetry": includeTelemetry,
+		"symbolId":            symbolId,
+		"depth":               depth,
+		"includeTelemetry":    includeTelemetry,
+		"onlyBreakingChanges": onlyBreaking,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	opts := query.AnalyzeImpactOptions{
-		SymbolId:         symbolId,
-		Depth:            depth,
-		IncludeTelemetry: includeTelemetry,
-		TelemetryPeriod:  telemetryPeriod,
+		SymbolId:            symbolId,
+		Depth:               depth,
+		IncludeTelemetry:    includeTelemetry,
+		TelemetryPeriod:     telemetryPeriod,
+		OnlyBreakingChanges: onlyBreaking,
+		MinConfidence:       minConfidence,
+	}
+	opts.ChangeKind, _ = params["changeKind"].(string)
+	opts.NewName, _ = params["newName"].(string)
+	if prev, ok := params["previous"].(map[string]interface{}); ok {
+		opts.Previous = &query.ImpactDigest{}
+		opts.Previous.SymbolId, _ = prev["symbolId"].(string)
+		opts.Previous.RepoStateId, _ = prev["repoStateId"].(string)
+		opts.Previous.Fingerprint, _ = prev["fingerprint"].(string)
 	}
 
 	impactResp, err := s.engine().AnalyzeImpact(ctx, opts)
@@ -582,6 +777,29 @@ func (s *MCPServer) toolAnalyzeImpact(params map[string]interface{}) (*envelope.
 		return nil, fmt.Errorf("impact analysis failed: %w", err)
 	}
 
+	data := impactResultData(impactResp)
+
+	// Record wide-result metrics
+	totalImpact := len(impactResp.DirectImpact) + len(impactResp.TransitiveImpact)
+	responseBytes := MeasureJSONSize(data)
+	RecordWideResult(WideResultMetrics{
+		ToolName:        "analyzeImpact",
+		TotalResults:    totalImpact,
+		ReturnedResults: totalImpact,
+		TruncatedCount:  0, // analyzeImpact doesn't truncate currently
+		ResponseBytes:   responseBytes,
+		EstimatedTokens: EstimateTokens(responseBytes),
+		ExecutionMs:     timer.ElapsedMs(),
+	})
+
+	return NewToolResponse().
+		Data(data).
+		WithProvenance(impactResp.Provenance).
+		Build(), nil
+}
+
+// impactResultData shapes an analyzeImpact response for the tool result.
+func impactResultData(impactResp *query.AnalyzeImpactResponse) map[string]interface{} {
 	directImpact := make([]map[string]interface{}, 0, len(impactResp.DirectImpact))
 	for _, item := range impactResp.DirectImpact {
 		itemInfo := map[string]interface{}{
@@ -592,6 +810,12 @@ func (s *MCPServer) toolAnalyzeImpact(params map[string]interface{}) (*envelope.
 			"moduleId":   item.ModuleId,
 			"confidence": item.Confidence,
 		}
+		if item.Breaking {
+			itemInfo["breaking"] = true
+		}
+		if item.UpdateKind != "" {
+			itemInfo["updateKind"] = item.UpdateKind
+		}
 		if item.Location != nil {
 			itemInfo["location"] = map[string]interface{}{
 				"fileId":    item.Location.FileId,
@@ -611,6 +835,12 @@ func (s *MCPServer) toolAnalyzeImpact(params map[string]interface{}) (*envelope.
 			"moduleId":   item.ModuleId,
 			"confidence": item.Confidence,
 		}
+		if item.Breaking {
+			itemInfo["breaking"] = true
+		}
+		if item.UpdateKind != "" {
+			itemInfo["updateKind"] = item.UpdateKind
+		}
 		if item.Location != nil {
 			itemInfo["location"] = map[string]interface{}{
 				"fileId":    item.Location.FileId,
@@ -625,22 +855,55 @@ func (s *MCPServer) toolAnalyzeImpact(params map[string]interface{}) (*envelope.
 		"transitiveImpact":  transitiveImpact,
 		"blendedConfidence": impactResp.BlendedConfidence,
 	}
+	if len(impactResp.ModulesAffected) > 0 {
+		data["modulesAffected"] = impactResp.ModulesAffected
+	}
+	if impactResp.ChangeKind != "" {
+		data["changeKind"] = impactResp.ChangeKind
+	}
+	if impactResp.NewName != "" {
+		data["newName"] = impactResp.NewName
+	}
+	if impactResp.OnlyBreakingChanges {
+		data["onlyBreakingChanges"] = true
+		data["suppressedNonBreaking"] = impactResp.SuppressedNonBreaking
+	}
+	if impactResp.FilteredByConfidence > 0 {
+		data["filteredByConfidence"] = impactResp.FilteredByConfidence
+	}
+	if len(impactResp.KindBreakdown) > 0 {
+		data["kindBreakdown"] = impactResp.KindBreakdown
+	}
+	if impactResp.RiskThresholds != nil {
+		data["riskThresholds"] = impactResp.RiskThresholds
+	}
+	if impactResp.Digest != nil {
+		data["digest"] = impactResp.Digest
+	}
 
 	if impactResp.RiskScore != nil {
 		factors := make([]map[string]interface{}, 0, len(impactResp.RiskScore.Factors))
 		for _, f := range impactResp.RiskScore.Factors {
-			factors = append(factors, map[string]interface{}{
+			factor := map[string]interface{}{
 				"name":   f.Name,
 				"value":  f.Value,
 				"weight": f.Weight,
-			})
+			}
+			if f.Detail != "" {
+				factor["detail"] = f.Detail
+			}
+			factors = append(factors, factor)
 		}
-		data["riskScore"] = map[string]interface{}{
+		riskScore := map[string]interface{}{
 			"score":       impactResp.RiskScore.Score,
 			"level":       impactResp.RiskScore.Level,
 			"explanation": impactResp.RiskScore.Explanation,
 			"factors":     factors,
 		}
+		if impactResp.RiskScore.Narrative != "" {
+			riskScore["narrative"] = impactResp.RiskScore.Narrative
+		}
+		data["riskScore"] = riskScore
 	}
 
 	// Add observed usage if available
@@ -661,23 +924,11 @@ func (s *MCPServer) toolAnalyzeImpact(params map[string]interface{}) (*envelope.
 		data["observedUsage"] = observedUsage
 	}
 
-	// Record wide-result metrics
-	totalImpact := len(impactResp.DirectImpact) + len(impactResp.TransitiveImpact)
-	responseBytes := MeasureJSONSize(data)
-	RecordWideResult(WideResultMetrics{
-		ToolName:        "analyzeImpact",
-		TotalResults:    totalImpact,
-		ReturnedResults: totalImpact,
-		TruncatedCount:  0, // analyzeImpact doesn't truncate currently
-		ResponseBytes:   responseBytes,
-		EstimatedTokens: EstimateTokens(responseBytes),
-		ExecutionMs:     timer.ElapsedMs(),
-	})
+	if len(impactResp.RelatedDecisions) > 0 {
+		data["relatedDecisions"] = impactResp.RelatedDecisions
+	}
 
-	return NewToolResponse().
-		Data(data).
-		WithProvenance(impactResp.Provenance).
-		Build(), nil
+	return data
 }
 
 // toolExplainSymbol implements the explainSymbol tool
@@ -691,7 +942,7 @@ func (s *MCPServer) toolExplainSymbol(params map[string]interface{}) (*envelope.
 		"symbolId": symbolId,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	resp, err := s.engine().ExplainSymbol(ctx, query.ExplainSymbolOptions{SymbolId: symbolId})
 	if err != nil {
 		return nil, fmt.Errorf("explainSymbol failed: %w", err)
@@ -703,6 +954,135 @@ func (s *MCPServer) toolExplainSymbol(params map[string]interface{}) (*envelope.
 		Build(), nil
 }
 
+// toolAnalyzeImpactBatch implements the analyzeImpactBatch tool
+func (s *MCPServer) toolAnalyzeImpactBatch(params map[string]interface{}) (*envelope.Response, error) {
+	var symbolIds []string
+	if idsVal, ok := params["symbolIds"].([]interface{}); ok {
+		for _, id := range idsVal {
+			if idStr, ok := id.(string); ok {
+				symbolIds = append(symbolIds, idStr)
+			}
+		}
+	}
+	if len(symbolIds) == 0 {
+		return nil, fmt.Errorf("missing or invalid 'symbolIds' parameter")
+	}
+
+	depth := 0 // 0 = configured default (traversal.impact)
+	if depthVal, ok := params["depth"].(float64); ok {
+		depth = int(depthVal)
+	}
+	includeTests := false
+	if v, ok := params["includeTests"].(bool); ok {
+		includeTests = v
+	}
+
+	s.logger.Debug("Executing analyzeImpactBatch", map[string]interface{}{
+		"symbolCount": len(symbolIds),
+		"depth":       depth,
+	})
+
+	ctx := s.callContext()
+	resp, err := s.engine().AnalyzeImpactBatch(ctx, query.AnalyzeImpactBatchOptions{
+		SymbolIds:    symbolIds,
+		Depth:        depth,
+		IncludeTests: includeTests,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("batch impact analysis failed: %w", err)
+	}
+
+	return NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance).
+		WithDrilldowns(resp.Drilldowns).
+		Build(), nil
+}
+
+// toolAnalyzeBranchImpact implements the analyzeBranchImpact tool
+func (s *MCPServer) toolAnalyzeBranchImpact(params map[string]interface{}) (*envelope.Response, error) {
+	base, _ := params["base"].(string)
+	head, _ := params["head"].(string)
+
+	depth := 0 // 0 = configured default (traversal.impact)
+	if depthVal, ok := params["depth"].(float64); ok {
+		depth = int(depthVal)
+	}
+	includeTests := false
+	if v, ok := params["includeTests"].(bool); ok {
+		includeTests = v
+	}
+
+	s.logger.Debug("Executing analyzeBranchImpact", map[string]interface{}{
+		"base":  base,
+		"head":  head,
+		"depth": depth,
+	})
+
+	ctx := s.callContext()
+	resp, err := s.engine().AnalyzeBranchImpact(ctx, query.AnalyzeBranchImpactOptions{
+		Base:         base,
+		Head:         head,
+		Depth:        depth,
+		IncludeTests: includeTests,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("branch impact analysis failed: %w", err)
+	}
+
+	toolResp := NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance).
+		WithDrilldowns(resp.Drilldowns)
+	for _, l := range resp.Limitations {
+		toolResp.TypedWarning(output.SeverityWarning, query.WarnAnalysisLimited, l)
+	}
+	return toolResp.Build(), nil
+}
+
+// toolGetSymbolNeighborhood implements the getSymbolNeighborhood tool
+func (s *MCPServer) toolGetSymbolNeighborhood(params map[string]interface{}) (*envelope.Response, error) {
+	symbolId, ok := params["symbolId"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid 'symbolId' parameter")
+	}
+
+	var include []string
+	if includeVal, ok := params["include"].([]interface{}); ok {
+		for _, f := range includeVal {
+			if fStr, ok := f.(string); ok {
+				include = append(include, fStr)
+			}
+		}
+	}
+
+	var timeout time.Duration // 0 = default (10s)
+	if v, ok := params["timeoutMs"].(float64); ok && v > 0 {
+		timeout = time.Duration(v) * time.Millisecond
+	}
+
+	s.logger.Debug("Executing getSymbolNeighborhood", map[string]interface{}{
+		"symbolId": symbolId,
+		"include":  include,
+	})
+
+	ctx := s.callContext()
+	resp, err := s.engine().GetSymbolNeighborhood(ctx, query.GetSymbolNeighborhoodOptions{
+		SymbolId: symbolId,
+		Include:  include,
+		Timeout:  timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getSymbolNeighborhood failed: %w", err)
+	}
+
+	return NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance).
+		WithDrilldowns(resp.Drilldowns).
+		Build(), nil
+}
+
 // toolJustifySymbol implements the justifySymbol tool
 func (s *MCPServer) toolJustifySymbol(params map[string]interface{}) (*envelope.Response, error) {
 	symbolId, ok := params["symbolId"].(string)
@@ -714,7 +1094,7 @@ func (s *MCPServer) toolJustifySymbol(params map[string]interface{}) (*envelope.
 		"symbolId": symbolId,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	resp, err := s.engine().JustifySymbol(ctx, query.JustifySymbolOptions{SymbolId: symbolId})
 	if err != nil {
 		return nil, fmt.Errorf("justifySymbol failed: %w", err)
@@ -740,22 +1120,29 @@ func (s *MCPServer) toolGetCallGraph(params map[string]interface{}) (*envelope.R
 		direction = "both"
 	}
 
-	depth := 1
+	depth := 0 // 0 = configured default (traversal.callGraph)
 	if depthVal, ok := params["depth"].(float64); ok {
 		depth = int(depthVal)
 	}
 
+	includeContext, _ := params["includeContext"].(bool)
+	outputFormat, _ := params["outputFormat"].(string)
+
 	s.logger.Debug("Executing getCallGraph", map[string]interface{}{
-		"symbolId":  symbolId,
-		"direction": direction,
-		"depth":     depth,
+		"symbolId":       symbolId,
+		"direction":      direction,
+		"depth":          depth,
+		"includeContext": includeContext,
+		"outputFormat":   outputFormat,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	resp, err := s.engine().GetCallGraph(ctx, query.CallGraphOptions{
-		SymbolId:  symbolId,
-		Direction: direction,
-		Depth:     depth,
+		SymbolId:       symbolId,
+		Direction:      direction,
+		Depth:          depth,
+		IncludeContext: includeContext,
+		OutputFormat:   outputFormat,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("getCallGraph failed: %w", err)
@@ -789,10 +1176,21 @@ func (s *MCPServer) toolGetModuleOverview(params map[string]interface{}) (*envel
 		"name": name,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
+	excludeGenerated := true
+	if v, ok := params["excludeGenerated"].(bool); ok {
+		excludeGenerated = v
+	}
+	respectGitignore := true
+	if v, ok := params["respectGitignore"].(bool); ok {
+		respectGitignore = v
+	}
+
 	resp, err := s.engine().GetModuleOverview(ctx, query.ModuleOverviewOptions{
-		Path: path,
-		Name: name,
+		Path:             path,
+		Name:             name,
+		ExcludeGenerated: excludeGenerated,
+		RespectGitignore: respectGitignore,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("getModuleOverview failed: %w", err)
@@ -811,13 +1209,22 @@ func (s *MCPServer) toolExplainFile(params map[string]interface{}) (*envelope.Re
 		return nil, fmt.Errorf("missing or invalid 'filePath' parameter")
 	}
 
+	checkUnusedImports := false
+	if v, ok := params["checkUnusedImports"].(bool); ok {
+		checkUnusedImports = v
+	}
+	base, _ := params["base"].(string)
+
 	s.logger.Debug("Executing explainFile", map[string]interface{}{
 		"filePath": filePath,
+		"base":     base,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	resp, err := s.engine().ExplainFile(ctx, query.ExplainFileOptions{
-		FilePath: filePath,
+		FilePath:           filePath,
+		CheckUnusedImports: checkUnusedImports,
+		Base:               base,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("explainFile failed: %w", err)
@@ -830,6 +1237,114 @@ func (s *MCPServer) toolExplainFile(params map[string]interface{}) (*envelope.Re
 		Build(), nil
 }
 
+// toolAnalyzeFileDeletion implements the analyzeFileDeletion tool
+func (s *MCPServer) toolAnalyzeFileDeletion(params map[string]interface{}) (*envelope.Response, error) {
+	filePath, ok := params["filePath"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid 'filePath' parameter")
+	}
+
+	s.logger.Debug("Executing analyzeFileDeletion", map[string]interface{}{
+		"filePath": filePath,
+	})
+
+	ctx := s.callContext()
+	resp, err := s.engine().AnalyzeFileDeletion(ctx, query.AnalyzeFileDeletionOptions{
+		FilePath: filePath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("analyzeFileDeletion failed: %w", err)
+	}
+
+	toolResp := NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance).
+		WithDrilldowns(resp.Drilldowns)
+	if resp.Truncated {
+		toolResp.TypedWarning(output.SeverityWarning, query.WarnAnalysisLimited, "File defines more symbols than were checked; the verdict may be incomplete")
+	}
+
+	return toolResp.Build(), nil
+}
+
+// toolGetStaleSymbols implements the getStaleSymbols tool
+func (s *MCPServer) toolGetStaleSymbols(params map[string]interface{}) (*envelope.Response, error) {
+	scope, _ := params["scope"].(string)
+
+	staleDays := 0 // 0 = default (365)
+	if v, ok := params["staleDays"].(float64); ok {
+		staleDays = int(v)
+	}
+
+	limit := 0 // 0 = default (20)
+	if v, ok := params["limit"].(float64); ok {
+		limit = int(v)
+	}
+
+	s.logger.Debug("Executing getStaleSymbols", map[string]interface{}{
+		"scope":     scope,
+		"staleDays": staleDays,
+		"limit":     limit,
+	})
+
+	ctx := s.callContext()
+	resp, err := s.engine().GetStaleSymbols(ctx, query.GetStaleSymbolsOptions{
+		Scope:     scope,
+		StaleDays: staleDays,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getStaleSymbols failed: %w", err)
+	}
+
+	toolResp := NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance).
+		WithDrilldowns(resp.Drilldowns)
+	for _, limitation := range resp.Limitations {
+		toolResp.TypedWarning(output.SeverityWarning, query.WarnAnalysisLimited, limitation)
+	}
+
+	return toolResp.Build(), nil
+}
+
+// toolListFiles implements the listFiles tool
+func (s *MCPServer) toolListFiles(params map[string]interface{}) (*envelope.Response, error) {
+	scope, _ := params["scope"].(string)
+
+	limit := 0 // 0 = default (200)
+	if v, ok := params["limit"].(float64); ok {
+		limit = int(v)
+	}
+
+	offset := 0
+	if v, ok := params["offset"].(float64); ok {
+		offset = int(v)
+	}
+
+	s.logger.Debug("Executing listFiles", map[string]interface{}{
+		"scope":  scope,
+		"limit":  limit,
+		"offset": offset,
+	})
+
+	ctx := s.callContext()
+	resp, err := s.engine().ListFiles(ctx, query.ListFilesOptions{
+		Scope:  scope,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listFiles failed: %w", err)
+	}
+
+	return NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance).
+		WithDrilldowns(resp.Drilldowns).
+		Build(), nil
+}
+
 // toolListEntrypoints implements the listEntrypoints tool
 func (s *MCPServer) toolListEntrypoints(params map[string]interface{}) (*envelope.Response, error) {
 	moduleFilter, _ := params["moduleFilter"].(string)
@@ -839,15 +1354,21 @@ func (s *MCPServer) toolListEntrypoints(params map[string]interface{}) (*envelop
 		limit = int(limitVal)
 	}
 
+	maxFanOut := 0
+	if v, ok := params["maxFanOut"].(float64); ok {
+		maxFanOut = int(v)
+	}
+
 	s.logger.Debug("Executing listEntrypoints", map[string]interface{}{
 		"moduleFilter": moduleFilter,
 		"limit":        limit,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	resp, err := s.engine().ListEntrypoints(ctx, query.ListEntrypointsOptions{
 		ModuleFilter: moduleFilter,
 		Limit:        limit,
+		MaxFanOut:    maxFanOut,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("listEntrypoints failed: %w", err)
@@ -872,22 +1393,36 @@ func (s *MCPServer) toolTraceUsage(params map[string]interface{}) (*envelope.Res
 		maxPaths = int(maxPathsVal)
 	}
 
-	maxDepth := 5
+	maxDepth := 0 // 0 = configured default (traversal.traceUsage)
 	if maxDepthVal, ok := params["maxDepth"].(float64); ok {
 		maxDepth = int(maxDepthVal)
 	}
 
+	maxPathsPerSource := 0 // 0 = one path per entrypoint
+	if v, ok := params["maxPathsPerSource"].(float64); ok {
+		maxPathsPerSource = int(v)
+	}
+
+	minConfidence, err := minConfidenceParam(params)
+	if err != nil {
+		return nil, err
+	}
+
 	s.logger.Debug("Executing traceUsage", map[string]interface{}{
-		"symbolId": symbolId,
-		"maxPaths": maxPaths,
-		"maxDepth": maxDepth,
+		"symbolId":          symbolId,
+		"maxPaths":          maxPaths,
+		"maxDepth":          maxDepth,
+		"maxPathsPerSource": maxPathsPerSource,
 	})
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	resp, err := s.engine().TraceUsage(ctx, query.TraceUsageOptions{
-		SymbolId: symbolId,
-		MaxPaths: maxPaths,
-		MaxDepth: maxDepth,
+		SymbolId:          symbolId,
+		MaxPaths:          maxPaths,
+		MaxDepth:          maxDepth,
+		Progress:          s.progressFunc(),
+		MinConfidence:     minConfidence,
+		MaxPathsPerSource: maxPathsPerSource,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("traceUsage failed: %w", err)
@@ -902,7 +1437,7 @@ func (s *MCPServer) toolTraceUsage(params map[string]interface{}) (*envelope.Res
 
 // toolSummarizeDiff handles the summarizeDiff tool call
 func (s *MCPServer) toolSummarizeDiff(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	opts := query.SummarizeDiffOptions{}
 
@@ -910,10 +1445,12 @@ func (s *MCPServer) toolSummarizeDiff(params map[string]interface{}) (*envelope.
 	if commitRange, ok := params["commitRange"].(map[string]interface{}); ok {
 		base, _ := commitRange["base"].(string)
 		head, _ := commitRange["head"].(string)
+		mergeBase, _ := commitRange["mergeBase"].(bool)
 		if base != "" && head != "" {
 			opts.CommitRange = &query.CommitRangeSelector{
-				Base: base,
-				Head: head,
+				Base:      base,
+				Head:      head,
+				MergeBase: mergeBase,
 			}
 		}
 	}
@@ -935,6 +1472,29 @@ func (s *MCPServer) toolSummarizeDiff(params map[string]interface{}) (*envelope.
 		}
 	}
 
+	if v, ok := params["checkErrorHandling"].(bool); ok {
+		opts.CheckErrorHandling = v
+	}
+	if v, ok := params["baseIndexPath"].(string); ok {
+		opts.BaseIndexPath = v
+	}
+	if v, ok := params["includeComplexity"].(bool); ok {
+		opts.IncludeComplexity = v
+	}
+	if v, ok := params["cyclomaticThreshold"].(float64); ok {
+		opts.CyclomaticThreshold = int(v)
+	}
+	if v, ok := params["cognitiveThreshold"].(float64); ok {
+		opts.CognitiveThreshold = int(v)
+	}
+	if v, ok := params["excludePaths"].([]interface{}); ok {
+		for _, p := range v {
+			if s, ok := p.(string); ok {
+				opts.ExcludePaths = append(opts.ExcludePaths, s)
+			}
+		}
+	}
+
 	resp, err := s.engine().SummarizeDiff(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("summarizeDiff failed: %w", err)
@@ -950,9 +1510,9 @@ func (s *MCPServer) toolSummarizeDiff(params map[string]interface{}) (*envelope.
 // toolGetHotspots handles the getHotspots tool call
 func (s *MCPServer) toolGetHotspots(params map[string]interface{}) (*envelope.Response, error) {
 	timer := NewWideResultTimer()
-	ctx := context.Background()
+	ctx := s.callContext()
 
-	opts := query.GetHotspotsOptions{}
+	opts := query.GetHotspotsOptions{ExcludeGenerated: true}
 
 	// Parse timeWindow if provided
 	if timeWindow, ok := params["timeWindow"].(map[string]interface{}); ok {
@@ -976,6 +1536,18 @@ func (s *MCPServer) toolGetHotspots(params map[string]interface{}) (*envelope.Re
 		opts.Limit = int(limit)
 	}
 
+	if format, ok := params["format"].(string); ok {
+		opts.Format = format
+	}
+
+	if includeAuthors, ok := params["includeAuthors"].(bool); ok {
+		opts.IncludeAuthors = includeAuthors
+	}
+
+	if excludeGenerated, ok := params["excludeGenerated"].(bool); ok {
+		opts.ExcludeGenerated = excludeGenerated
+	}
+
 	resp, err := s.engine().GetHotspots(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("getHotspots failed: %w", err)
@@ -1002,7 +1574,7 @@ func (s *MCPServer) toolGetHotspots(params map[string]interface{}) (*envelope.Re
 
 // toolExplainPath handles the explainPath tool call
 func (s *MCPServer) toolExplainPath(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	filePath, ok := params["filePath"].(string)
 	if !ok || filePath == "" {
@@ -1031,14 +1603,30 @@ func (s *MCPServer) toolExplainPath(params map[string]interface{}) (*envelope.Re
 
 // toolListKeyConcepts handles the listKeyConcepts tool call
 func (s *MCPServer) toolListKeyConcepts(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	limit := 12
 	if limitVal, ok := params["limit"].(float64); ok {
 		limit = int(limitVal)
 	}
 
-	resp, err := s.engine().ListKeyConcepts(ctx, query.ListKeyConceptsOptions{Limit: limit})
+	excludeGenerated := true
+	if v, ok := params["excludeGenerated"].(bool); ok {
+		excludeGenerated = v
+	}
+
+	opts := query.ListKeyConceptsOptions{Limit: limit, ExcludeGenerated: excludeGenerated, RespectGitignore: true}
+	if v, ok := params["respectGitignore"].(bool); ok {
+		opts.RespectGitignore = v
+	}
+	if v, ok := params["minOccurrences"].(float64); ok {
+		opts.MinOccurrences = int(v)
+	}
+	if v, ok := params["minFiles"].(float64); ok {
+		opts.MinFiles = int(v)
+	}
+
+	resp, err := s.engine().ListKeyConcepts(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("listKeyConcepts failed: %w", err)
 	}
@@ -1052,10 +1640,20 @@ func (s *MCPServer) toolListKeyConcepts(params map[string]interface{}) (*envelop
 
 // toolRecentlyRelevant handles the recentlyRelevant tool call
 func (s *MCPServer) toolRecentlyRelevant(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	opts := query.RecentlyRelevantOptions{}
 
+	// Parse commitRange if provided
+	if commitRange, ok := params["commitRange"].(map[string]interface{}); ok {
+		base, _ := commitRange["base"].(string)
+		head, _ := commitRange["head"].(string)
+		opts.CommitRange = &query.CommitRangeSelector{
+			Base: base,
+			Head: head,
+		}
+	}
+
 	// Parse timeWindow if provided
 	if timeWindow, ok := params["timeWindow"].(map[string]interface{}); ok {
 		start, _ := timeWindow["start"].(string)
@@ -1092,7 +1690,7 @@ func (s *MCPServer) toolRecentlyRelevant(params map[string]interface{}) (*envelo
 
 // toolRefreshArchitecture handles the refreshArchitecture tool call (v6.0)
 func (s *MCPServer) toolRefreshArchitecture(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	// Parse scope (default: "all")
 	scope := "all"
@@ -1145,7 +1743,7 @@ func (s *MCPServer) toolRefreshArchitecture(params map[string]interface{}) (*env
 
 // toolGetOwnership handles the getOwnership tool call (v6.0)
 func (s *MCPServer) toolGetOwnership(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	// Parse path (required)
 	path, ok := params["path"].(string)
@@ -1191,7 +1789,7 @@ func (s *MCPServer) toolGetOwnership(params map[string]interface{}) (*envelope.R
 
 // toolGetModuleResponsibilities handles the getModuleResponsibilities tool call (v6.0)
 func (s *MCPServer) toolGetModuleResponsibilities(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	// Parse moduleId (optional)
 	moduleId, _ := params["moduleId"].(string)
@@ -1408,6 +2006,15 @@ func (s *MCPServer) toolAnnotateModule(params map[string]interface{}) (*envelope
 		}
 	}
 
+	var apiSymbols []string
+	if apiVal, ok := params["apiSymbols"].([]interface{}); ok {
+		for _, a := range apiVal {
+			if as, ok := a.(string); ok {
+				apiSymbols = append(apiSymbols, as)
+			}
+		}
+	}
+
 	s.logger.Debug("Executing annotateModule", map[string]interface{}{
 		"moduleId":       moduleId,
 		"responsibility": responsibility,
@@ -1422,6 +2029,7 @@ func (s *MCPServer) toolAnnotateModule(params map[string]interface{}) (*envelope
 		Tags:           tags,
 		PublicPaths:    publicPaths,
 		InternalPaths:  internalPaths,
+		APISymbols:     apiSymbols,
 	}
 
 	resp, err := s.engine().AnnotateModule(input)
@@ -1432,6 +2040,62 @@ func (s *MCPServer) toolAnnotateModule(params map[string]interface{}) (*envelope
 	return OperationalResponse(resp), nil
 }
 
+// toolGetRiskTrend handles the getRiskTrend tool call
+func (s *MCPServer) toolGetRiskTrend(params map[string]interface{}) (*envelope.Response, error) {
+	symbolId, ok := params["symbolId"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid 'symbolId' parameter")
+	}
+
+	limit := 0
+	if v, ok := params["limit"].(float64); ok {
+		limit = int(v)
+	}
+
+	s.logger.Debug("Executing getRiskTrend", map[string]interface{}{
+		"symbolId": symbolId,
+		"limit":    limit,
+	})
+
+	ctx := s.callContext()
+	resp, err := s.engine().GetRiskTrend(ctx, query.GetRiskTrendOptions{
+		SymbolId: symbolId,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getRiskTrend failed: %w", err)
+	}
+
+	toolResp := NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance)
+	for _, limitation := range resp.Limitations {
+		toolResp.TypedWarning(output.SeverityInfo, query.WarnAnalysisLimited, limitation)
+	}
+
+	return toolResp.Build(), nil
+}
+
+// toolValidateAnnotations handles the validateAnnotations tool call
+func (s *MCPServer) toolValidateAnnotations(params map[string]interface{}) (*envelope.Response, error) {
+	s.logger.Debug("Executing validateAnnotations", nil)
+
+	ctx := s.callContext()
+	resp, err := s.engine().ValidateAnnotations(ctx, query.ValidateAnnotationsOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("validateAnnotations failed: %w", err)
+	}
+
+	toolResp := NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance)
+	for _, limitation := range resp.Limitations {
+		toolResp.TypedWarning(output.SeverityWarning, query.WarnAnalysisLimited, limitation)
+	}
+
+	return toolResp.Build(), nil
+}
+
 // v6.1 Job management tools
 
 // toolGetJobStatus handles the getJobStatus tool call
@@ -1516,7 +2180,7 @@ func (s *MCPServer) toolCancelJob(params map[string]interface{}) (*envelope.Resp
 // toolSummarizePr handles the summarizePr tool call
 func (s *MCPServer) toolSummarizePr(params map[string]interface{}) (*envelope.Response, error) {
 	timer := NewWideResultTimer()
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	// Parse baseBranch (optional, default: "main")
 	baseBranch := "main"
@@ -1571,9 +2235,53 @@ func (s *MCPServer) toolSummarizePr(params map[string]interface{}) (*envelope.Re
 		Build(), nil
 }
 
+// toolGenerateReviewChecklist handles the generateReviewChecklist tool call
+func (s *MCPServer) toolGenerateReviewChecklist(params map[string]interface{}) (*envelope.Response, error) {
+	ctx := s.callContext()
+
+	base := "main"
+	if v, ok := params["base"].(string); ok && v != "" {
+		base = v
+	}
+
+	head := "HEAD"
+	if v, ok := params["head"].(string); ok && v != "" {
+		head = v
+	}
+
+	var timeout time.Duration // 0 = default (30s)
+	if v, ok := params["timeoutMs"].(float64); ok && v > 0 {
+		timeout = time.Duration(v) * time.Millisecond
+	}
+
+	s.logger.Debug("Executing generateReviewChecklist", map[string]interface{}{
+		"base": base,
+		"head": head,
+	})
+
+	resp, err := s.engine().GenerateReviewChecklist(ctx, query.ReviewChecklistOptions{
+		Base:     base,
+		Head:     head,
+		Timeout:  timeout,
+		Progress: s.progressFunc(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generateReviewChecklist failed: %w", err)
+	}
+
+	toolResp := NewToolResponse().
+		Data(resp).
+		WithProvenance(resp.Provenance)
+	for _, limitation := range resp.Limitations {
+		toolResp.TypedWarning(output.SeverityWarning, query.WarnAnalysisLimited, limitation)
+	}
+
+	return toolResp.Build(), nil
+}
+
 // toolGetOwnershipDrift handles the getOwnershipDrift tool call
 func (s *MCPServer) toolGetOwnershipDrift(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
 	// Parse scope (optional)
 	scope := ""
@@ -1618,11 +2326,15 @@ func (s *MCPServer) toolGetOwnershipDrift(params map[string]interface{}) (*envel
 
 // toolGetFileComplexity handles the getFileComplexity tool call
 func (s *MCPServer) toolGetFileComplexity(params map[string]interface{}) (*envelope.Response, error) {
-	ctx := context.Background()
+	ctx := s.callContext()
 
-	// Parse filePath (required)
-	filePath, ok := params["filePath"].(string)
-	if !ok || filePath == "" {
+	// Parse filePath, or dirPath for directory mode (one is required)
+	filePath, _ := params["filePath"].(string)
+	dirPath, _ := params["dirPath"].(string)
+	if dirPath != "" {
+		filePath = dirPath
+	}
+	if filePath == "" {
 		return nil, fmt.Errorf("filePath is required")
 	}
 
@@ -1658,9 +2370,14 @@ func (s *MCPServer) toolGetFileComplexity(params map[string]interface{}) (*envel
 	}
 
 	// Check if file exists
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+	info, err := os.Stat(absPath)
+	if os.IsNotExist(err) {
 		return nil, fmt.Errorf("file not found: %s", filePath)
 	}
+	isDir := err == nil && info.IsDir()
+	if dirPath != "" && !isDir {
+		return nil, fmt.Errorf("not a directory: %s", dirPath)
+	}
 
 	// Check if complexity analysis is available
 	if !complexity.IsAvailable() {
@@ -1673,6 +2390,10 @@ func (s *MCPServer) toolGetFileComplexity(params map[string]interface{}) (*envel
 			Build(), nil
 	}
 
+	if isDir {
+		return s.directoryComplexity(ctx, filePath, absPath, sortBy, limit)
+	}
+
 	// Analyze the file
 	analyzer := complexity.NewAnalyzer()
 	result, err := analyzer.AnalyzeFile(ctx, absPath)
@@ -1744,6 +2465,101 @@ func (s *MCPServer) toolGetFileComplexity(params map[string]interface{}) (*envel
 	return OperationalResponse(resp), nil
 }
 
+// directoryComplexity is getFileComplexity's directory mode: it analyzes
+// the source files under absPath and returns per-file summaries, most
+// complex first by sortBy (the file's most complex function for cyclomatic
+// and cognitive, total function lines for lines), up to limit files.
+func (s *MCPServer) directoryComplexity(ctx context.Context, dirPath, absPath, sortBy string, limit int) (*envelope.Response, error) {
+	result, err := complexity.AnalyzeDirectory(ctx, absPath, complexity.DirectoryOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	repoRoot := s.engine().GetRepoRoot()
+	relPath := func(path string) string {
+		if rel, err := filepath.Rel(repoRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+
+	functionLines := func(fc *complexity.FileComplexity) int {
+		total := 0
+		for _, fn := range fc.Functions {
+			total += fn.Lines
+		}
+		return total
+	}
+
+	files := result.Files
+	var functionCount, totalCyclomatic, totalCognitive, maxCyclomatic, maxCognitive int
+	for _, fc := range files {
+		functionCount += fc.FunctionCount
+		totalCyclomatic += fc.TotalCyclomatic
+		totalCognitive += fc.TotalCognitive
+		maxCyclomatic = max(maxCyclomatic, fc.MaxCyclomatic)
+		maxCognitive = max(maxCognitive, fc.MaxCognitive)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		switch sortBy {
+		case "cognitive":
+			return files[i].MaxCognitive > files[j].MaxCognitive
+		case "lines":
+			return functionLines(files[i]) > functionLines(files[j])
+		default: // cyclomatic
+			return files[i].MaxCyclomatic > files[j].MaxCyclomatic
+		}
+	})
+	if limit > 0 && len(files) > limit {
+		files = files[:limit]
+	}
+
+	fileList := make([]map[string]interface{}, len(files))
+	for i, fc := range files {
+		fileList[i] = map[string]interface{}{
+			"path":              relPath(fc.Path),
+			"language":          string(fc.Language),
+			"functionCount":     fc.FunctionCount,
+			"totalCyclomatic":   fc.TotalCyclomatic,
+			"totalCognitive":    fc.TotalCognitive,
+			"averageCyclomatic": fc.AverageCyclomatic,
+			"averageCognitive":  fc.AverageCognitive,
+			"maxCyclomatic":     fc.MaxCyclomatic,
+			"maxCognitive":      fc.MaxCognitive,
+			"lines":             functionLines(fc),
+		}
+	}
+
+	resp := map[string]interface{}{
+		"path":            dirPath,
+		"sourceFiles":     result.SourceFiles,
+		"analyzedFiles":   len(result.Files),
+		"functionCount":   functionCount,
+		"totalCyclomatic": totalCyclomatic,
+		"totalCognitive":  totalCognitive,
+		"maxCyclomatic":   maxCyclomatic,
+		"maxCognitive":    maxCognitive,
+		"files":           fileList,
+	}
+	if functionCount > 0 {
+		resp["averageCyclomatic"] = float64(totalCyclomatic) / float64(functionCount)
+		resp["averageCognitive"] = float64(totalCognitive) / float64(functionCount)
+	}
+	if len(result.Skipped) > 0 {
+		skipped := make([]map[string]interface{}, len(result.Skipped))
+		for i, sf := range result.Skipped {
+			skipped[i] = map[string]interface{}{"path": relPath(sf.Path), "reason": sf.Reason}
+		}
+		resp["skipped"] = skipped
+	}
+
+	return NewToolResponse().
+		Data(resp).
+		WithTruncation(result.Truncated, len(result.Files)+len(result.Skipped), result.SourceFiles, "max-files").
+		Build(), nil
+}
+
 // toolGetWideResultMetrics returns aggregated metrics for wide-result tools.
 // This is an internal/debug tool to inform the Frontier mode decision.
 func (s *MCPServer) toolGetWideResultMetrics(params map[string]interface{}) (*envelope.Response, error) {
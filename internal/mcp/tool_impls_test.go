@@ -2,8 +2,13 @@ package mcp
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"ckb/internal/complexity"
+	"ckb/internal/query"
 )
 
 // =============================================================================
@@ -892,6 +897,46 @@ func TestToolGetFileComplexity_ValidPath(t *testing.T) {
 	}
 }
 
+func TestToolGetFileComplexity_Directory(t *testing.T) {
+	if !complexity.IsAvailable() {
+		t.Skip("complexity analysis requires CGO")
+	}
+	t.Parallel()
+	server := newTestMCPServer(t)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"simple.go":  "package p\n\nfunc Simple() {}\n",
+		"branchy.go": "package p\n\nfunc Branchy(x int) int {\n\tif x > 0 {\n\t\treturn 1\n\t}\n\tif x < 0 {\n\t\treturn -1\n\t}\n\treturn 0\n}\n",
+		"notes.txt":  "not source",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := server.toolGetFileComplexity(map[string]interface{}{"dirPath": dir, "limit": float64(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, ok := resp.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected data %T", resp.Data)
+	}
+	if data["sourceFiles"] != 2 || data["analyzedFiles"] != 2 {
+		t.Errorf("sourceFiles = %v, analyzedFiles = %v; want 2 and 2", data["sourceFiles"], data["analyzedFiles"])
+	}
+	fileList, _ := data["files"].([]map[string]interface{})
+	if len(fileList) != 1 || !strings.HasSuffix(fileList[0]["path"].(string), "branchy.go") {
+		t.Errorf("files = %v, want only the most complex file", fileList)
+	}
+
+	if _, err := server.toolGetFileComplexity(map[string]interface{}{"dirPath": filepath.Join(dir, "simple.go")}); err == nil {
+		t.Error("expected an error when dirPath is a file")
+	}
+}
+
 // =============================================================================
 // JustifySymbol Tool Tests
 // =============================================================================
@@ -1003,3 +1048,32 @@ func TestToolAnnotateModule_ArrayParsing(t *testing.T) {
 		t.Error("expected result if no error")
 	}
 }
+
+func TestImpactResultDataModulesAffected(t *testing.T) {
+	resp := &query.AnalyzeImpactResponse{
+		ModulesAffected: []query.ModuleImpact{
+			{ModuleId: "internal/query", Name: "query", ImpactCount: 3, DirectCount: 2},
+		},
+	}
+
+	raw, err := json.Marshal(NewToolResponse().Data(impactResultData(resp)).Build())
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var env struct {
+		Data struct {
+			ModulesAffected []query.ModuleImpact `json:"modulesAffected"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	got := env.Data.ModulesAffected
+	if len(got) != 1 || got[0].ModuleId != "internal/query" || got[0].ImpactCount != 3 || got[0].DirectCount != 2 {
+		t.Errorf("modulesAffected = %+v, want the engine's module impact", got)
+	}
+
+	if _, ok := impactResultData(&query.AnalyzeImpactResponse{})["modulesAffected"]; ok {
+		t.Error("modulesAffected should be omitted when empty")
+	}
+}
@@ -124,8 +124,8 @@ func (s *MCPServer) toolGetObservedUsage(params map[string]interface{}) (*envelo
 	// Convert period to filter
 	periodFilter := computePeriodFilter(period)
 
-	// Get usage data
-	usages, err := storage.GetObservedUsage(symbolID, periodFilter)
+	// Sum the daily rollups, which outlive raw usage rows
+	rollup, err := storage.GetUsageRollup(symbolID, periodFilter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get observed usage: %w", err)
 	}
@@ -135,27 +135,15 @@ func (s *MCPServer) toolGetObservedUsage(params map[string]interface{}) (*envelo
 		SymbolName: extractSymbolName(symbolID),
 	}
 
-	if len(usages) > 0 {
-		var totalCalls int64
-		var periodCalls int64
-		var firstObserved, lastObserved time.Time
-
-		for i, u := range usages {
-			totalCalls += u.CallCount
-			periodCalls += u.CallCount
-			if i == 0 {
-				lastObserved = u.IngestedAt
-			}
-			firstObserved = u.IngestedAt
-		}
-
+	if len(rollup.Days) > 0 {
+		firstObserved, _ := time.Parse("2006-01-02", rollup.Days[len(rollup.Days)-1].Day)
 		response.Usage = &telemetry.UsageData{
-			TotalCalls:    totalCalls,
-			PeriodCalls:   periodCalls,
+			TotalCalls:    rollup.TotalCalls,
+			PeriodCalls:   rollup.TotalCalls,
 			FirstObserved: firstObserved,
-			LastObserved:  lastObserved,
-			MatchQuality:  usages[0].MatchQuality,
-			Trend:         computeTrend(usages),
+			LastObserved:  rollup.LastIngestedAt,
+			MatchQuality:  rollup.MatchQuality,
+			Trend:         computeTrend(rollup.Days),
 		}
 	}
 
@@ -369,15 +357,17 @@ func extractSymbolName(symbolID string) string {
 	return symbolID
 }
 
-func computeTrend(usages []telemetry.ObservedUsage) telemetry.UsageTrend {
-	if len(usages) < 2 {
+// computeTrend compares call volume in the newer and older halves of the
+// days, which are ordered newest first
+func computeTrend(days []telemetry.DailyUsage) telemetry.UsageTrend {
+	if len(days) < 2 {
 		return telemetry.TrendStable
 	}
 
 	// Compare first half vs second half
-	mid := len(usages) / 2
+	mid := len(days) / 2
 	var recentCalls, olderCalls int64
-	for i, u := range usages {
+	for i, u := range days {
 		if i < mid {
 			recentCalls += u.CallCount
 		} else {
@@ -1,7 +1,6 @@
 package mcp
 
 import (
-	"context"
 	"fmt"
 
 	"ckb/internal/audit"
@@ -38,7 +37,7 @@ func (s *MCPServer) toolExplainOrigin(params map[string]interface{}) (*envelope.
 	repoRoot := s.engine().GetRepoRoot()
 	explainer := explain.NewExplainer(repoRoot, s.logger)
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	result, err := explainer.Explain(ctx, explain.ExplainOptions{
 		Symbol:          symbol,
 		IncludeUsage:    includeUsage,
@@ -81,7 +80,7 @@ func (s *MCPServer) toolAnalyzeCoupling(params map[string]interface{}) (*envelop
 	repoRoot := s.engine().GetRepoRoot()
 	analyzer := coupling.NewAnalyzer(repoRoot, s.logger)
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	result, err := analyzer.Analyze(ctx, coupling.AnalyzeOptions{
 		Target:         target,
 		MinCorrelation: minCorrelation,
@@ -139,7 +138,7 @@ func (s *MCPServer) toolExportForLLM(params map[string]interface{}) (*envelope.R
 	repoRoot := s.engine().GetRepoRoot()
 	exporter := export.NewExporter(repoRoot, s.logger)
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	result, err := exporter.Export(ctx, export.ExportOptions{
 		RepoRoot:          repoRoot,
 		IncludeUsage:      includeUsage,
@@ -202,7 +201,7 @@ func (s *MCPServer) toolAuditRisk(params map[string]interface{}) (*envelope.Resp
 	repoRoot := s.engine().GetRepoRoot()
 	analyzer := audit.NewAnalyzer(repoRoot, s.logger)
 
-	ctx := context.Background()
+	ctx := s.callContext()
 	result, err := analyzer.Analyze(ctx, audit.AuditOptions{
 		RepoRoot:  repoRoot,
 		MinScore:  minScore,
@@ -6,6 +6,8 @@ import (
 
 	"ckb/internal/docs"
 	"ckb/internal/envelope"
+	"ckb/internal/output"
+	"ckb/internal/query"
 )
 
 // v7.3 Doc-Symbol Linking tool implementations
@@ -238,7 +240,34 @@ func (s *MCPServer) toolGetDocCoverage(params map[string]interface{}) (*envelope
 		topN = int(v)
 	}
 
-	report, err := s.engine().GetDocCoverage(exportedOnly, topN)
+	scope := ""
+	if v, ok := params["scope"].(string); ok {
+		scope = v
+	}
+
+	limit := 50
+	if v, ok := params["limit"].(float64); ok && v > 0 {
+		limit = int(v)
+	}
+
+	includeGenerated := false
+	if v, ok := params["includeGenerated"].(bool); ok {
+		includeGenerated = v
+	}
+
+	includeTestOnly := false
+	if v, ok := params["includeTestOnly"].(bool); ok {
+		includeTestOnly = v
+	}
+
+	report, err := s.engine().GetDocCoverage(s.callContext(), query.DocCoverageOptions{
+		ExportedOnly:     exportedOnly,
+		TopN:             topN,
+		Scope:            scope,
+		Limit:            limit,
+		IncludeGenerated: includeGenerated,
+		IncludeTestOnly:  includeTestOnly,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get doc coverage: %w", err)
 	}
@@ -253,15 +282,23 @@ func (s *MCPServer) toolGetDocCoverage(params map[string]interface{}) (*envelope
 		})
 	}
 
-	return NewToolResponse().
-		Data(map[string]interface{}{
-			"totalSymbols":    report.TotalSymbols,
-			"documented":      report.Documented,
-			"undocumented":    report.Undocumented,
-			"coveragePercent": report.CoveragePercent,
-			"topUndocumented": topUndocumented,
-		}).
-		Build(), nil
+	data := map[string]interface{}{
+		"totalSymbols":    report.TotalSymbols,
+		"documented":      report.Documented,
+		"undocumented":    report.Undocumented,
+		"coveragePercent": report.CoveragePercent,
+		"topUndocumented": topUndocumented,
+	}
+	if report.SymbolDocs != nil {
+		data["symbolDocs"] = report.SymbolDocs
+	}
+
+	resp := NewToolResponse().Data(data)
+	for _, limitation := range report.Limitations {
+		resp.TypedWarning(output.SeverityWarning, query.WarnAnalysisLimited, limitation)
+	}
+
+	return resp.Build(), nil
 }
 
 // truncateString truncates a string to max length, adding ellipsis if needed
@@ -210,7 +210,7 @@ func (s *MCPServer) toolFederationSyncRemote(params map[string]interface{}) (*en
 		return nil, fmt.Errorf("failed to initialize remote clients: %w", initErr)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := context.WithTimeout(s.callContext(), 2*time.Minute)
 	defer cancel()
 
 	var result map[string]interface{}
@@ -283,7 +283,7 @@ func (s *MCPServer) toolFederationStatusRemote(params map[string]interface{}) (*
 		return nil, fmt.Errorf("failed to initialize remote clients: %w", initErr)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(s.callContext(), 30*time.Second)
 	defer cancel()
 
 	status, statusErr := engine.GetRemoteStatus(ctx, serverName)
@@ -351,7 +351,7 @@ func (s *MCPServer) toolFederationSearchSymbolsHybrid(params map[string]interfac
 		return nil, fmt.Errorf("failed to initialize remote clients: %w", initErr)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(s.callContext(), 30*time.Second)
 	defer cancel()
 
 	opts := federation.HybridSearchOptions{
@@ -411,7 +411,7 @@ func (s *MCPServer) toolFederationListAllRepos(params map[string]interface{}) (*
 		return nil, fmt.Errorf("failed to initialize remote clients: %w", initErr)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(s.callContext(), 30*time.Second)
 	defer cancel()
 
 	result, listErr := engine.ListAllRepos(ctx)
@@ -14,13 +14,20 @@ type ToolHandler func(params map[string]interface{}) (*envelope.Response, error)
 
 // GetToolDefinitions returns all tool definitions
 func (s *MCPServer) GetToolDefinitions() []Tool {
-	return []Tool{
+	tools := []Tool{
 		{
 			Name:        "getStatus",
 			Description: "Get CKB system status including backend health, cache stats, and repository state",
 			InputSchema: map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
+				"type": "object",
+				"properties": map[string]interface{}{
+					"format": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"json", "prometheus"},
+						"default":     "json",
+						"description": "With prometheus, return the status metrics in the Prometheus text exposition format in the prometheus field",
+					},
+				},
 			},
 		},
 		{
@@ -75,6 +82,17 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     "head",
 						"description": "Whether to use HEAD commit only or full working tree state",
 					},
+					"includeSource": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Include the source lines around the definition",
+					},
+					"contextLines": map[string]interface{}{
+						"type":        "integer",
+						"default":     5,
+						"maximum":     50,
+						"description": "Lines of context on each side of the definition line when includeSource is set",
+					},
 				},
 				"required": []string{"symbolId"},
 			},
@@ -105,6 +123,17 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     20,
 						"description": "Maximum number of results to return",
 					},
+					"minConfidence": map[string]interface{}{
+						"type":        "number",
+						"minimum":     0,
+						"maximum":     1,
+						"description": "Drop results whose name-match confidence is below this (exact 1.0, prefix 0.8, substring 0.6, fuzzy 0.3)",
+					},
+					"includePrivate": map[string]interface{}{
+						"type":        "boolean",
+						"default":     true,
+						"description": "Set false to hide symbols known to be private or internal, e.g. when exploring a public API surface. Symbols of unknown visibility are kept",
+					},
 				},
 				"required": []string{"query"},
 			},
@@ -121,7 +150,7 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 					},
 					"scope": map[string]interface{}{
 						"type":        "string",
-						"description": "Optional module ID to limit search scope",
+						"description": "Optional path prefix to limit search scope; globs (*, ?, **) and 're:' regular expressions match against file paths",
 					},
 					"merge": map[string]interface{}{
 						"type":        "string",
@@ -134,6 +163,25 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     100,
 						"description": "Maximum number of references to return",
 					},
+					"includeDynamicDispatch": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Also include calls made through interface methods this symbol implements (marked dispatch: dynamic, lower confidence, capped)",
+					},
+					"externalOnly": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Only return references from outside the module that defines the symbol, to see who depends on it as an API",
+					},
+					"cursor": map[string]interface{}{
+						"type":        "string",
+						"description": "nextCursor from a previous truncated response, to fetch the following page with the same parameters",
+					},
+					"kinds": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string", "enum": []string{"definition", "reference", "call", "type", "read", "write", "import", "implements", "forward_declaration"}},
+						"description": "Only return references of these kinds, e.g. [\"call\"] to assess a rename; empty or absent returns all kinds",
+					},
 				},
 				"required": []string{"symbolId"},
 			},
@@ -159,7 +207,48 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     false,
 						"description": "Force refresh of cached architecture",
 					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"json", "mermaid"},
+						"default":     "json",
+						"description": "With mermaid, also return a mermaid 'graph LR' diagram of modules and dependencies in the mermaid field",
+					},
+					"minSymbols": map[string]interface{}{
+						"type":        "integer",
+						"minimum":     0,
+						"default":     0,
+						"description": "Fold modules with fewer symbols into one misc module per top-level directory, merging their dependency edges (0 keeps every module)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "diffArchitecture",
+			Description: "Compare the module dependency graph between two git revisions. Reports added/removed modules, added/removed dependency edges, and edges whose strength changed significantly. Use to spot new coupling introduced by a refactor.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"base": map[string]interface{}{
+						"type":        "string",
+						"description": "Base revision (branch, tag, or commit)",
+					},
+					"head": map[string]interface{}{
+						"type":        "string",
+						"default":     "HEAD",
+						"description": "Head revision (branch, tag, or commit)",
+					},
+					"includeExternalDeps": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Whether to include external dependency edges",
+					},
+					"strengthChangeThreshold": map[string]interface{}{
+						"type":        "number",
+						"default":     0.5,
+						"description": "Relative strength change for an edge to be reported as changed",
+					},
 				},
+				"required": []string{"base"},
 			},
 		},
 		{
@@ -188,10 +277,132 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"description": "Time period for telemetry data (7d, 30d, 90d, all)",
 						"enum":        []string{"7d", "30d", "90d", "all"},
 					},
+					"onlyBreakingChanges": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Return only impacts a change is expected to break (direct callers, interface implementations); the risk score still counts everything",
+					},
+					"minConfidence": map[string]interface{}{
+						"type":        "number",
+						"minimum":     0,
+						"maximum":     1,
+						"description": "Drop impact items whose confidence is below this; the risk score still counts everything",
+					},
+					"changeKind": map[string]interface{}{
+						"type":        "string",
+						"default":     "modify",
+						"enum":        []string{"modify", "delete", "rename"},
+						"description": "The change to analyze. delete and rename flag every reference as breaking; delete raises the risk score, rename marks each site's update as mechanical or logic",
+					},
+					"newName": map[string]interface{}{
+						"type":        "string",
+						"description": "New name of the symbol; only with changeKind rename",
+					},
+					"previous": map[string]interface{}{
+						"type":        "object",
+						"description": "The digest from an earlier analyzeImpact result with the same options. If the repo state and symbol are unchanged, that result is returned without re-running the analysis (meta.provenance.fromCache)",
+						"properties": map[string]interface{}{
+							"symbolId":    map[string]interface{}{"type": "string"},
+							"repoStateId": map[string]interface{}{"type": "string"},
+							"fingerprint": map[string]interface{}{"type": "string"},
+						},
+					},
 				},
 				"required": []string{"symbolId"},
 			},
 		},
+		{
+			Name:        "analyzeImpactBatch",
+			Description: "Analyze the combined impact of changing a set of symbols together, e.g. a deprecation batch. Returns the deduplicated union of affected items and modules, an aggregate risk score, and a per-symbol breakdown. Symbols that fail are reported without failing the batch.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"symbolIds": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"maxItems":    100,
+						"description": "Stable symbol IDs to analyze",
+					},
+					"depth": map[string]interface{}{
+						"type":        "number",
+						"default":     2,
+						"description": "Maximum depth for transitive impact analysis",
+					},
+					"includeTests": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Count test code as affected",
+					},
+				},
+				"required": []string{"symbolIds"},
+			},
+		},
+		{
+			Name:        "analyzeBranchImpact",
+			Description: "Analyze the net impact of merging a branch: diffs head against its merge base with base, maps the changed lines to symbols, and analyzes the modified symbols together. Changes a later commit undid are reported as netted out, not as changes.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"base": map[string]interface{}{
+						"type":        "string",
+						"default":     "main",
+						"description": "Branch the head would be merged into",
+					},
+					"head": map[string]interface{}{
+						"type":        "string",
+						"default":     "HEAD",
+						"description": "Branch to analyze",
+					},
+					"depth": map[string]interface{}{
+						"type":        "number",
+						"default":     2,
+						"description": "Maximum depth for transitive impact analysis",
+					},
+					"includeTests": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Count test code as affected",
+					},
+				},
+			},
+		},
+		{
+			Name:        "analyzeFileDeletion",
+			Description: "Check whether a file can be deleted. Finds every symbol the file defines and the references to them from other files, then returns a verdict: safe, test-only (only tests depend on it), entrypoint (a program main), or blocked, listing the blocking symbols and their callers.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"filePath": map[string]interface{}{
+						"type":        "string",
+						"description": "Path to the file (relative or absolute)",
+					},
+				},
+				"required": []string{"filePath"},
+			},
+		},
+		{
+			Name:        "getStaleSymbols",
+			Description: "Find symbols not modified (per git blame) in a long time, for maintenance planning. Ranked by concern: heavily used code left untouched in a directory that keeps changing ranks first; unused code in a quiet directory is marked stable. Each result has its last-modified date, caller/reference counts and the directory's recent commit count.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"scope": map[string]interface{}{
+						"type":        "string",
+						"description": "Path prefix to limit the search to (e.g. internal/api)",
+					},
+					"staleDays": map[string]interface{}{
+						"type":        "integer",
+						"default":     365,
+						"description": "Symbols whose lines are all older than this many days are stale",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"default":     20,
+						"description": "Maximum symbols to return",
+					},
+				},
+			},
+		},
 		{
 			Name:        "explainSymbol",
 			Description: "Get an AI-friendly explanation of a symbol including usage, history, and summary",
@@ -206,9 +417,36 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 				"required": []string{"symbolId"},
 			},
 		},
+		{
+			Name:        "getSymbolNeighborhood",
+			Description: "Get everything near a symbol in one call: definition, signature and docs, plus callers, callees, tests, owners and related decisions. Use include to fetch only the facets you need; each facet reports whether it was complete, degraded or timed out.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"symbolId": map[string]interface{}{
+						"type":        "string",
+						"description": "The stable symbol ID",
+					},
+					"include": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"callers", "callees", "tests", "owners", "decisions"},
+						},
+						"description": "Facets to fetch (default: all). The definition is always included.",
+					},
+					"timeoutMs": map[string]interface{}{
+						"type":        "integer",
+						"default":     10000,
+						"description": "Deadline for all facets; unfinished facets are reported as timed out",
+					},
+				},
+				"required": []string{"symbolId"},
+			},
+		},
 		{
 			Name:        "justifySymbol",
-			Description: "Get a keep/investigate/remove verdict for a symbol based on usage analysis",
+			Description: "Get a keep/investigate/remove verdict for a symbol based on usage analysis. With telemetry enabled, runtime calls confirm (no calls: remove) or overturn (observed calls: investigate) a verdict for symbols without static callers.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -241,6 +479,17 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     1,
 						"description": "Maximum depth to traverse (1-4)",
 					},
+					"includeContext": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Attach the source lines around each call site to edges, and each callee's definition line to its node, so the graph can be read without opening files. Enlarges the response",
+					},
+					"outputFormat": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"json", "dot"},
+						"default":     "json",
+						"description": "With dot, also return the graph as a Graphviz digraph in the dot field",
+					},
 				},
 				"required": []string{"symbolId"},
 			},
@@ -259,6 +508,16 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"type":        "string",
 						"description": "Optional friendly name for the module",
 					},
+					"excludeGenerated": map[string]interface{}{
+						"type":        "boolean",
+						"default":     true,
+						"description": "Leave generated files (*.pb.go, *_gen.go, mocks, 'Code generated' headers) out of the file count",
+					},
+					"respectGitignore": map[string]interface{}{
+						"type":        "boolean",
+						"default":     true,
+						"description": "Leave paths matched by the repo's .gitignore files (build output like dist/ or target/) out of the file count",
+					},
 				},
 			},
 		},
@@ -272,10 +531,42 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"type":        "string",
 						"description": "Path to the file (relative or absolute)",
 					},
+					"checkUnusedImports": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Report imports no symbol in the file references, using SCIP reference data (Go and TypeScript)",
+					},
+					"base": map[string]interface{}{
+						"type":        "string",
+						"description": "Git ref to diff against (e.g. main). Each symbol is marked added, modified or unchanged relative to it, for reviewing a changed file",
+					},
 				},
 				"required": []string{"filePath"},
 			},
 		},
+		{
+			Name:        "listFiles",
+			Description: "List the source files in the index with language, role, symbol count and last-modified time, ordered by path. Use it to check index coverage: a directory missing from the listing is not indexed.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"scope": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory to list (repo-relative); omit for the whole index",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"default":     200,
+						"description": "Maximum files per page (max 1000)",
+					},
+					"offset": map[string]interface{}{
+						"type":        "number",
+						"default":     0,
+						"description": "Resume position; pass nextOffset from the previous page",
+					},
+				},
+			},
+		},
 		{
 			Name:        "listEntrypoints",
 			Description: "List system entrypoints (API handlers, CLI mains, jobs) with ranking signals",
@@ -291,6 +582,10 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     30,
 						"description": "Maximum number of entrypoints to return",
 					},
+					"maxFanOut": map[string]interface{}{
+						"type":        "number",
+						"description": "Fan-out above which an entrypoint gets a highFanOut ranking signal and a warning (default: entrypoints.maxFanOut config, 100)",
+					},
 				},
 			},
 		},
@@ -314,6 +609,17 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     5,
 						"description": "Maximum path depth to traverse (1-5)",
 					},
+					"maxPathsPerSource": map[string]interface{}{
+						"type":        "number",
+						"default":     1,
+						"description": "Distinct paths to collect from each entrypoint, shortest first (1-5). Raise to see different call chains through the same entrypoint",
+					},
+					"minConfidence": map[string]interface{}{
+						"type":        "number",
+						"minimum":     0,
+						"maximum":     1,
+						"description": "Drop paths whose confidence is below this",
+					},
 				},
 				"required": []string{"symbolId"},
 			},
@@ -336,6 +642,11 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 								"type":        "string",
 								"description": "Head commit hash or ref",
 							},
+							"mergeBase": map[string]interface{}{
+								"type":        "boolean",
+								"default":     false,
+								"description": "Diff from the merge base of base and head (base...head), showing only head's changes as a PR review does",
+							},
 						},
 						"required": []string{"base", "head"},
 					},
@@ -358,6 +669,35 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						},
 						"required": []string{"start"},
 					},
+					"checkErrorHandling": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Flag ignored errors (_ = f(), unchecked Close, empty err checks) on added Go lines as error-handling risk signals",
+					},
+					"baseIndexPath": map[string]interface{}{
+						"type":        "string",
+						"description": "Path to a SCIP index built at the base. Modified public symbols then report signatureChange (old, new, breaking)",
+					},
+					"excludePaths": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Glob patterns (** spans directories) for files to leave out, e.g. vendor/** or **/*.pb.go. Applied before the 50-file cap",
+					},
+					"includeComplexity": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Compare function complexity at base and head for changed core and entrypoint files, emitting complexity-increase risk signals",
+					},
+					"cyclomaticThreshold": map[string]interface{}{
+						"type":        "integer",
+						"default":     15,
+						"description": "Per-function cyclomatic complexity limit for includeComplexity",
+					},
+					"cognitiveThreshold": map[string]interface{}{
+						"type":        "integer",
+						"default":     20,
+						"description": "Per-function cognitive complexity limit for includeComplexity",
+					},
 				},
 			},
 		},
@@ -390,6 +730,22 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     20,
 						"description": "Maximum number of hotspots to return (max 50)",
 					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"json", "csv"},
+						"default":     "json",
+						"description": "With csv, also return the hotspots as CSV rows in the csv field, for spreadsheets",
+					},
+					"includeAuthors": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Add the top 5 authors per hotspot with their commit counts and share of changes (one extra git call per hotspot)",
+					},
+					"excludeGenerated": map[string]interface{}{
+						"type":        "boolean",
+						"default":     true,
+						"description": "Drop generated files from the ranking",
+					},
 				},
 			},
 		},
@@ -422,6 +778,26 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     12,
 						"description": "Maximum number of concepts to return (max 12)",
 					},
+					"excludeGenerated": map[string]interface{}{
+						"type":        "boolean",
+						"default":     true,
+						"description": "Ignore symbols and files in generated code",
+					},
+					"respectGitignore": map[string]interface{}{
+						"type":        "boolean",
+						"default":     true,
+						"description": "Skip paths matched by the repo's .gitignore files when concepts come from file names (no SCIP index)",
+					},
+					"minOccurrences": map[string]interface{}{
+						"type":        "number",
+						"default":     2,
+						"description": "Drop concepts seen fewer times. Applied before the cap of 12, so higher values let less common concepts drop out and others take their place",
+					},
+					"minFiles": map[string]interface{}{
+						"type":        "number",
+						"default":     1,
+						"description": "Drop concepts found in fewer files. Applied before the cap of 12",
+					},
 				},
 			},
 		},
@@ -431,6 +807,21 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
+					"commitRange": map[string]interface{}{
+						"type":        "object",
+						"description": "Commits in base..head to analyze, e.g. between two release tags. Cannot be combined with timeWindow",
+						"properties": map[string]interface{}{
+							"base": map[string]interface{}{
+								"type":        "string",
+								"description": "Base commit hash or ref",
+							},
+							"head": map[string]interface{}{
+								"type":        "string",
+								"description": "Head commit hash or ref",
+							},
+						},
+						"required": []string{"base", "head"},
+					},
 					"timeWindow": map[string]interface{}{
 						"type":        "object",
 						"description": "Time period to analyze (default: 7 days)",
@@ -609,7 +1000,7 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 					},
 					"search": map[string]interface{}{
 						"type":        "string",
-						"description": "Search in title and ID",
+						"description": "Search terms; returns decisions containing every term, ranked by relevance (title matches weigh most), with scores. Combines with status and moduleId",
 					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
@@ -661,10 +1052,25 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						},
 						"description": "Paths intended to be internal/private",
 					},
+					"apiSymbols": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+						"description": "Curated public API: symbol names or glob patterns (e.g. 'Client', 'Client.*'). When set, only matching symbols are treated as public API; other exports count as internal",
+					},
 				},
 				"required": []string{"moduleId"},
 			},
 		},
+		{
+			Name:        "validateAnnotations",
+			Description: "Check module annotations against the current code. Reports declared public/internal paths that no longer exist, internal paths referenced from outside their module (boundary violations), and detected modules without annotations, each with a severity.",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
 		// v6.1 Job management tools
 		{
 			Name:        "getJobStatus",
@@ -740,6 +1146,30 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 				},
 			},
 		},
+		{
+			Name:        "generateReviewChecklist",
+			Description: "Generate an ordered PR review checklist: high-risk symbols to scrutinize, tests to run (with commands), public API changes to confirm, ownership gaps needing reviewers, and decisions affecting touched modules. Combines summarizeDiff, analyzeImpact, getOwnership and getDecisions under one deadline; each item links to a drilldown.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"base": map[string]interface{}{
+						"type":        "string",
+						"default":     "main",
+						"description": "Base revision (branch, tag, or commit)",
+					},
+					"head": map[string]interface{}{
+						"type":        "string",
+						"default":     "HEAD",
+						"description": "Head revision (branch, tag, or commit)",
+					},
+					"timeoutMs": map[string]interface{}{
+						"type":        "integer",
+						"default":     30000,
+						"description": "Overall deadline; phases not reached in time are reported as limitations",
+					},
+				},
+			},
+		},
 		{
 			Name:        "getOwnershipDrift",
 			Description: "Detect ownership drift by comparing CODEOWNERS declarations against actual git-blame ownership. Returns files where the declared owners differ significantly from who actually writes the code.",
@@ -1384,13 +1814,17 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 		// v6.2.2 Tree-sitter Complexity tools
 		{
 			Name:        "getFileComplexity",
-			Description: "Get code complexity metrics for a source file using tree-sitter parsing. Returns cyclomatic and cognitive complexity for each function, plus file-level aggregates. Supports Go, JavaScript, TypeScript, Python, Rust, Java, and Kotlin.",
+			Description: "Get code complexity metrics for a source file using tree-sitter parsing. Returns cyclomatic and cognitive complexity for each function, plus file-level aggregates. Given a directory, analyzes its source files and returns per-file summaries with directory totals. Supports Go, JavaScript, TypeScript, Python, Rust, Java, and Kotlin.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
 					"filePath": map[string]interface{}{
 						"type":        "string",
-						"description": "Path to the source file (relative or absolute)",
+						"description": "Path to the source file or directory (relative or absolute). Either filePath or dirPath is required",
+					},
+					"dirPath": map[string]interface{}{
+						"type":        "string",
+						"description": "Directory to analyze (relative or absolute). At most 500 source files are analyzed; hidden, vendor and node_modules directories are skipped",
 					},
 					"includeFunctions": map[string]interface{}{
 						"type":        "boolean",
@@ -1401,15 +1835,14 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"type":        "string",
 						"enum":        []string{"cyclomatic", "cognitive", "lines"},
 						"default":     "cyclomatic",
-						"description": "Sort functions by this metric (descending)",
+						"description": "Sort functions by this metric (descending). In directory mode, files are sorted by their most complex function, or by total function lines",
 					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
 						"default":     20,
-						"description": "Maximum number of functions to return (most complex first)",
+						"description": "Maximum number of functions to return (most complex first), or of files in directory mode",
 					},
 				},
-				"required": []string{"filePath"},
 			},
 		},
 		// v6.4 Telemetry tools
@@ -1602,6 +2035,26 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 				},
 			},
 		},
+		{
+			Name:        "getRiskTrend",
+			Description: "Show how a symbol's analyzeImpact risk score has changed across repo states. Returns the recorded scores oldest first with timestamps, and the change from oldest to newest. Scores are recorded only while riskHistory.enabled is set in config, and only for modifications (not changeKind delete or rename).",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"symbolId": map[string]interface{}{
+						"type":        "string",
+						"description": "The stable symbol ID",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"default":     20,
+						"minimum":     1,
+						"description": "Number of most recent scores to return",
+					},
+				},
+				"required": []string{"symbolId"},
+			},
+		},
 		// v7.3 Doc-Symbol Linking tools
 		{
 			Name:        "getDocsForSymbol",
@@ -1684,7 +2137,7 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 		},
 		{
 			Name:        "getDocCoverage",
-			Description: "Get documentation coverage statistics. Reports how many symbols are documented and which high-centrality symbols are missing documentation.",
+			Description: "Get documentation coverage statistics. Reports how many symbols are documented and which high-centrality symbols are missing documentation. Also scores doc comments of public symbols and returns a backlog of undocumented or poorly documented ones, most-used first.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -1698,6 +2151,25 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 						"default":     10,
 						"description": "Number of top undocumented symbols to return",
 					},
+					"scope": map[string]interface{}{
+						"type":        "string",
+						"description": "Path prefix to limit the doc-comment backlog (e.g. internal/api)",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"default":     50,
+						"description": "Maximum symbols in the doc-comment backlog",
+					},
+					"includeGenerated": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Include symbols from generated files",
+					},
+					"includeTestOnly": map[string]interface{}{
+						"type":        "boolean",
+						"default":     false,
+						"description": "Include public symbols only referenced from tests",
+					},
 				},
 			},
 		},
@@ -1733,6 +2205,143 @@ func (s *MCPServer) GetToolDefinitions() []Tool {
 			},
 		},
 	}
+
+	addPathStyleParam(tools)
+	addIDStyleParam(tools)
+	addFreshnessParams(tools)
+	return tools
+}
+
+// pathStyleTools are the tools whose results are mostly file paths.
+var pathStyleTools = map[string]bool{
+	"searchSymbols":         true,
+	"getSymbol":             true,
+	"getSymbolNeighborhood": true,
+	"findReferences":        true,
+	"getCallGraph":          true,
+	"traceUsage":            true,
+	"explainFile":           true,
+	"listFiles":             true,
+	"listEntrypoints":       true,
+	"analyzeImpact":         true,
+	"analyzeImpactBatch":    true,
+	"analyzeBranchImpact":   true,
+	"analyzeFileDeletion":   true,
+	"getStaleSymbols":       true,
+	"getHotspots":           true,
+	"summarizeDiff":         true,
+	"getOwnership":          true,
+	"recentlyRelevant":      true,
+}
+
+// addPathStyleParam documents the pathStyle option, which handleCallTool
+// applies to any tool's output, on the tools where it matters most.
+func addPathStyleParam(tools []Tool) {
+	for _, tool := range tools {
+		if !pathStyleTools[tool.Name] {
+			continue
+		}
+		props, ok := tool.InputSchema["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		props["pathStyle"] = map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{"repo", "module"},
+			"default":     "repo",
+			"description": "Path format: repo-relative, or relative to the owning module with the module reported as pathModule",
+		}
+	}
+}
+
+// idStyleTools are the tools whose results are built around symbol IDs.
+var idStyleTools = map[string]bool{
+	"searchSymbols":         true,
+	"getSymbol":             true,
+	"getSymbolNeighborhood": true,
+	"explainSymbol":         true,
+	"justifySymbol":         true,
+	"getCallGraph":          true,
+	"traceUsage":            true,
+	"explainFile":           true,
+	"analyzeImpact":         true,
+	"analyzeImpactBatch":    true,
+	"analyzeBranchImpact":   true,
+	"analyzeFileDeletion":   true,
+	"getStaleSymbols":       true,
+	"listEntrypoints":       true,
+}
+
+// addIDStyleParam documents the idStyle option, which handleCallTool
+// applies to any tool's output.
+func addIDStyleParam(tools []Tool) {
+	for _, tool := range tools {
+		if !idStyleTools[tool.Name] {
+			continue
+		}
+		props, ok := tool.InputSchema["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		props["idStyle"] = map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{"raw", "readable"},
+			"default":     "raw",
+			"description": "Symbol ID format: raw, or readable names (e.g. query.Engine.GetSymbol) with the raw ID in stableIdRaw/symbolIdRaw; always pass raw IDs back",
+		}
+	}
+}
+
+// scipBackedTools are the tools whose answers come from the SCIP index, and
+// so honor the index freshness policy. Pure git tools are not listed.
+var scipBackedTools = map[string]bool{
+	"searchSymbols":          true,
+	"getSymbol":              true,
+	"getSymbolNeighborhood":  true,
+	"explainSymbol":          true,
+	"justifySymbol":          true,
+	"findReferences":         true,
+	"getCallGraph":           true,
+	"traceUsage":             true,
+	"explainFile":            true,
+	"explainPath":            true,
+	"listFiles":              true,
+	"listEntrypoints":        true,
+	"getModuleOverview":      true,
+	"listKeyConcepts":        true,
+	"analyzeImpact":          true,
+	"analyzeImpactBatch":     true,
+	"analyzeBranchImpact":    true,
+	"analyzeFileDeletion":    true,
+	"getStaleSymbols":        true,
+	"findDeadCodeCandidates": true,
+	"exportForLLM":           true,
+}
+
+// addFreshnessParams documents the per-call freshness override, which
+// handleCallTool applies before running a SCIP-backed tool.
+func addFreshnessParams(tools []Tool) {
+	for _, tool := range tools {
+		if !scipBackedTools[tool.Name] {
+			continue
+		}
+		props, ok := tool.InputSchema["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		props["requireFreshIndex"] = map[string]interface{}{
+			"type":        "boolean",
+			"description": "Fail with INDEX_STALE instead of answering from a stale SCIP index (default: backends.scip.requireFreshIndex)",
+		}
+		props["maxCommitsBehind"] = map[string]interface{}{
+			"type":        "number",
+			"description": "With requireFreshIndex, commits the index may lag HEAD; 0 also rejects uncommitted changes",
+		}
+		props["autoRefresh"] = map[string]interface{}{
+			"type":        "boolean",
+			"description": "If the SCIP index is stale, start a background reindex (reusing one already running) and answer from the current index; the job ID is in meta.freshness.indexAge.refreshJobId for getJobStatus",
+		}
+	}
 }
 
 // RegisterTools registers all tool handlers
@@ -1745,12 +2354,19 @@ func (s *MCPServer) RegisterTools() {
 	s.tools["searchSymbols"] = s.toolSearchSymbols
 	s.tools["findReferences"] = s.toolFindReferences
 	s.tools["getArchitecture"] = s.toolGetArchitecture
+	s.tools["diffArchitecture"] = s.toolDiffArchitecture
 	s.tools["analyzeImpact"] = s.toolAnalyzeImpact
+	s.tools["analyzeImpactBatch"] = s.toolAnalyzeImpactBatch
+	s.tools["analyzeBranchImpact"] = s.toolAnalyzeBranchImpact
+	s.tools["analyzeFileDeletion"] = s.toolAnalyzeFileDeletion
+	s.tools["getStaleSymbols"] = s.toolGetStaleSymbols
 	s.tools["explainSymbol"] = s.toolExplainSymbol
+	s.tools["getSymbolNeighborhood"] = s.toolGetSymbolNeighborhood
 	s.tools["justifySymbol"] = s.toolJustifySymbol
 	s.tools["getCallGraph"] = s.toolGetCallGraph
 	s.tools["getModuleOverview"] = s.toolGetModuleOverview
 	s.tools["explainFile"] = s.toolExplainFile
+	s.tools["listFiles"] = s.toolListFiles
 	s.tools["listEntrypoints"] = s.toolListEntrypoints
 	s.tools["traceUsage"] = s.toolTraceUsage
 	s.tools["summarizeDiff"] = s.toolSummarizeDiff
@@ -1765,12 +2381,14 @@ func (s *MCPServer) RegisterTools() {
 	s.tools["recordDecision"] = s.toolRecordDecision
 	s.tools["getDecisions"] = s.toolGetDecisions
 	s.tools["annotateModule"] = s.toolAnnotateModule
+	s.tools["validateAnnotations"] = s.toolValidateAnnotations
 	// v6.1 Job management tools
 	s.tools["getJobStatus"] = s.toolGetJobStatus
 	s.tools["listJobs"] = s.toolListJobs
 	s.tools["cancelJob"] = s.toolCancelJob
 	// v6.1 CI/CD tools
 	s.tools["summarizePr"] = s.toolSummarizePr
+	s.tools["generateReviewChecklist"] = s.toolGenerateReviewChecklist
 	s.tools["getOwnershipDrift"] = s.toolGetOwnershipDrift
 	// v6.2 Federation tools
 	s.tools["listFederations"] = s.toolListFederations
@@ -1814,6 +2432,7 @@ func (s *MCPServer) RegisterTools() {
 	s.tools["analyzeCoupling"] = s.toolAnalyzeCoupling
 	s.tools["exportForLLM"] = s.toolExportForLLM
 	s.tools["auditRisk"] = s.toolAuditRisk
+	s.tools["getRiskTrend"] = s.toolGetRiskTrend
 	// v7.3 Doc-Symbol Linking tools
 	s.tools["getDocsForSymbol"] = s.toolGetDocsForSymbol
 	s.tools["getSymbolsInDoc"] = s.toolGetSymbolsInDoc
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +14,16 @@ const MaxMessageSize = 1024 * 1024
 
 // readMessage reads a JSON-RPC message from the input stream
 func (s *MCPServer) readMessage() (*MCPMessage, error) {
+	line, err := s.readLine()
+	if err != nil {
+		return nil, err
+	}
+	return parseMessage(line)
+}
+
+// readLine reads one raw line, which holds either a single JSON-RPC
+// message or a batch, from the input stream
+func (s *MCPServer) readLine() ([]byte, error) {
 	// Lazily initialize the scanner on first use
 	if s.scanner == nil {
 		s.scanner = bufio.NewScanner(s.stdin)
@@ -32,16 +43,34 @@ func (s *MCPServer) readMessage() (*MCPMessage, error) {
 		"raw": line,
 	})
 
+	return []byte(line), nil
+}
+
+// parseMessage parses a single JSON-RPC message
+func parseMessage(data []byte) (*MCPMessage, error) {
 	var msg MCPMessage
-	if err := json.Unmarshal([]byte(line), &msg); err != nil {
+	if err := json.Unmarshal(data, &msg); err != nil {
 		return nil, fmt.Errorf("error parsing JSON-RPC message: %w", err)
 	}
 
 	return &msg, nil
 }
 
+// isBatch reports whether a raw line is a JSON-RPC batch (a JSON array)
+func isBatch(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
 // writeMessage writes a JSON-RPC message to the output stream
 func (s *MCPServer) writeMessage(msg *MCPMessage) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.writeMessageLocked(msg)
+}
+
+// writeMessageLocked writes a JSON-RPC message; the caller holds writeMu
+func (s *MCPServer) writeMessageLocked(msg *MCPMessage) error {
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("error marshaling JSON-RPC message: %w", err)
@@ -58,6 +87,26 @@ func (s *MCPServer) writeMessage(msg *MCPMessage) error {
 	return nil
 }
 
+// writeBatch writes the responses to a batch as one JSON array
+func (s *MCPServer) writeBatch(msgs []*MCPMessage) error {
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON-RPC batch: %w", err)
+	}
+
+	s.logger.Debug("Sending batch", map[string]interface{}{
+		"raw": string(data),
+	})
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := fmt.Fprintf(s.stdout, "%s\n", data); err != nil {
+		return fmt.Errorf("error writing to stdout: %w", err)
+	}
+
+	return nil
+}
+
 // writeError writes an error response
 func (s *MCPServer) writeError(id interface{}, code int, message string) error {
 	return s.writeMessage(NewErrorMessage(id, code, message, nil))
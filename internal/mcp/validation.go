@@ -0,0 +1,243 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"ckb/internal/envelope"
+	"ckb/internal/errors"
+)
+
+// ParamError describes one tool parameter that doesn't match the tool's
+// input schema.
+type ParamError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// toolSchema returns the input schema of a tool, or nil for tools without
+// a definition (e.g. handlers registered only in tests).
+func (s *MCPServer) toolSchema(name string) map[string]interface{} {
+	s.schemaOnce.Do(func() {
+		s.toolSchemas = make(map[string]map[string]interface{})
+		for _, tool := range s.GetToolDefinitions() {
+			s.toolSchemas[tool.Name] = tool.InputSchema
+		}
+	})
+	return s.toolSchemas[name]
+}
+
+// validateToolParams checks params against a tool's input schema: required
+// fields, types, enums, numeric bounds and array lengths. Every offending
+// field is reported, in field order. Properties the schema doesn't declare
+// are left alone, since clients pass shared options such as pathStyle.
+//
+// Numbers are normalized in place to float64, the type JSON decoding
+// produces, so handlers can rely on a single numeric type.
+func validateToolParams(schema map[string]interface{}, params map[string]interface{}) []ParamError {
+	if schema == nil {
+		return nil
+	}
+	props, _ := schema["properties"].(map[string]interface{})
+
+	var errs []ParamError
+	for _, field := range schemaStrings(schema["required"]) {
+		if params[field] == nil {
+			errs = append(errs, ParamError{Field: field, Message: "required"})
+		}
+	}
+
+	fields := make([]string, 0, len(params))
+	for field := range params {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		prop, ok := props[field].(map[string]interface{})
+		if !ok || params[field] == nil {
+			continue
+		}
+		value, msg := checkParamValue(prop, params[field])
+		if msg != "" {
+			errs = append(errs, ParamError{Field: field, Message: msg})
+			continue
+		}
+		params[field] = value
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+// checkParamValue validates one value against its property schema and
+// returns the normalized value, or a message describing the mismatch.
+func checkParamValue(prop map[string]interface{}, value interface{}) (interface{}, string) {
+	typ, _ := prop["type"].(string)
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, "expected string, got " + paramTypeName(value)
+		}
+		if allowed := schemaStrings(prop["enum"]); len(allowed) > 0 && !containsString(allowed, s) {
+			return nil, fmt.Sprintf("must be one of %s, got %q", strings.Join(allowed, ", "), s)
+		}
+		return s, ""
+
+	case "integer", "number":
+		n, ok := paramNumber(value)
+		if !ok {
+			return nil, "expected " + typ + ", got " + paramTypeName(value)
+		}
+		if typ == "integer" && n != math.Trunc(n) {
+			return nil, fmt.Sprintf("expected integer, got %v", n)
+		}
+		if min, ok := paramNumber(prop["minimum"]); ok && n < min {
+			return nil, fmt.Sprintf("must be at least %v, got %v", min, n)
+		}
+		if max, ok := paramNumber(prop["maximum"]); ok && n > max {
+			return nil, fmt.Sprintf("must be at most %v, got %v", max, n)
+		}
+		return n, ""
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return nil, "expected boolean, got " + paramTypeName(value)
+		}
+		return value, ""
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			if strs, isStrings := value.([]string); isStrings {
+				items = make([]interface{}, len(strs))
+				for i, s := range strs {
+					items[i] = s
+				}
+			} else {
+				return nil, "expected array, got " + paramTypeName(value)
+			}
+		}
+		if max, ok := paramNumber(prop["maxItems"]); ok && float64(len(items)) > max {
+			return nil, fmt.Sprintf("must have at most %v items, got %d", max, len(items))
+		}
+		if itemSchema, ok := prop["items"].(map[string]interface{}); ok {
+			for i, item := range items {
+				normalized, msg := checkParamValue(itemSchema, item)
+				if msg != "" {
+					return nil, fmt.Sprintf("item %d: %s", i, msg)
+				}
+				items[i] = normalized
+			}
+		}
+		return items, ""
+
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return nil, "expected object, got " + paramTypeName(value)
+		}
+		return value, ""
+	}
+	return value, ""
+}
+
+// paramNumber converts any numeric value to float64.
+func paramNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// paramTypeName names a decoded JSON value's type for error messages.
+func paramTypeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}, []string:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := paramNumber(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// schemaStrings reads a schema list ("required", "enum") declared as
+// either []string or []interface{}.
+func schemaStrings(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		strs := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidParamsResult reports every schema violation of a tool call at
+// once. The message lists them for clients that only show the error text;
+// data carries them as fields for clients that act on them.
+func invalidParamsResult(tool string, errs []ParamError) map[string]interface{} {
+	parts := make([]string, len(errs))
+	for i, e := range errs {
+		parts[i] = e.Field + ": " + e.Message
+	}
+	err := errors.NewCkbError(
+		errors.InvalidParameters,
+		fmt.Sprintf("invalid parameters for %s: %s", tool, strings.Join(parts, "; ")),
+		nil,
+		nil,
+		nil,
+	).WithDetails(errs)
+
+	errResp := envelope.New().Data(map[string]interface{}{
+		"code":          err.Code,
+		"tool":          tool,
+		"invalidParams": errs,
+	}).Error(err).Build()
+	jsonBytes, _ := json.Marshal(errResp)
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": string(jsonBytes),
+			},
+		},
+	}
+}
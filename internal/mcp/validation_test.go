@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"ckb/internal/compression"
+	"ckb/internal/envelope"
+	"ckb/internal/output"
+)
+
+func TestValidateToolParams(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"symbolId":  map[string]interface{}{"type": "string"},
+			"direction": map[string]interface{}{"type": "string", "enum": []string{"callers", "callees", "both"}},
+			"depth":     map[string]interface{}{"type": "integer", "maximum": 4},
+			"score":     map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1},
+			"verbose":   map[string]interface{}{"type": "boolean"},
+			"kinds":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "maxItems": 2},
+		},
+		"required": []string{"symbolId"},
+	}
+
+	t.Run("valid params are normalized", func(t *testing.T) {
+		params := map[string]interface{}{
+			"symbolId":  "sym",
+			"direction": "callers",
+			"depth":     2,
+			"kinds":     []string{"function"},
+			"pathStyle": "module", // Undeclared properties pass through
+		}
+		if errs := validateToolParams(schema, params); len(errs) != 0 {
+			t.Fatalf("unexpected errors: %+v", errs)
+		}
+		if depth, ok := params["depth"].(float64); !ok || depth != 2 {
+			t.Errorf("depth = %#v, want float64 2", params["depth"])
+		}
+		if kinds, ok := params["kinds"].([]interface{}); !ok || len(kinds) != 1 {
+			t.Errorf("kinds = %#v, want []interface{} with one item", params["kinds"])
+		}
+	})
+
+	t.Run("every offending field is reported", func(t *testing.T) {
+		params := map[string]interface{}{
+			"direction": "up",
+			"depth":     2.5,
+			"score":     3.0,
+			"verbose":   "yes",
+			"kinds":     []interface{}{"a", 1.0},
+		}
+		errs := validateToolParams(schema, params)
+		got := make(map[string]string)
+		for _, e := range errs {
+			got[e.Field] = e.Message
+		}
+		want := map[string]string{
+			"symbolId":  "required",
+			"direction": "must be one of",
+			"depth":     "expected integer",
+			"score":     "must be at most",
+			"verbose":   "expected boolean",
+			"kinds":     "item 1: expected string",
+		}
+		if len(got) != len(want) {
+			t.Errorf("got %d errors, want %d: %+v", len(got), len(want), errs)
+		}
+		for field, prefix := range want {
+			if !strings.HasPrefix(got[field], prefix) {
+				t.Errorf("%s: message %q, want prefix %q", field, got[field], prefix)
+			}
+		}
+		for i := 1; i < len(errs); i++ {
+			if errs[i-1].Field > errs[i].Field {
+				t.Errorf("errors not in field order: %+v", errs)
+			}
+		}
+	})
+
+	t.Run("nil schema accepts anything", func(t *testing.T) {
+		if errs := validateToolParams(nil, map[string]interface{}{"x": 1}); len(errs) != 0 {
+			t.Errorf("unexpected errors: %+v", errs)
+		}
+	})
+}
+
+func TestCallToolRejectsInvalidParams(t *testing.T) {
+	server := newTestMCPServer(t)
+
+	called := false
+	server.tools["getCallGraph"] = func(params map[string]interface{}) (*envelope.Response, error) {
+		called = true
+		return NewToolResponse().Data(nil).Build(), nil
+	}
+
+	resp := callTool(t, server, "getCallGraph", map[string]interface{}{"direction": "up"})
+	if !hasToolError(t, resp) {
+		t.Fatal("expected a validation error")
+	}
+	if called {
+		t.Error("handler ran despite invalid params")
+	}
+	msg := getToolErrorMessage(t, resp)
+	for _, want := range []string{"INVALID_PARAMETERS", "symbolId: required", "direction: must be one of"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not mention %q", msg, want)
+		}
+	}
+}
+
+func TestDrilldownsUseDeclaredParams(t *testing.T) {
+	server := newTestMCPServer(t)
+	schemas := make(map[string]map[string]interface{})
+	for _, tool := range server.GetToolDefinitions() {
+		schemas[tool.Name] = tool.InputSchema
+	}
+
+	reasons := []compression.TruncationReason{
+		compression.TruncMaxModules, compression.TruncMaxItems, compression.TruncMaxRefs, compression.TruncTimeout,
+	}
+	for _, reason := range reasons {
+		drilldowns := compression.GenerateDrilldowns(&compression.DrilldownContext{
+			TruncationReason: reason,
+			Completeness:     compression.CompletenessInfo{Score: 0.5, IsBestEffort: true},
+			IndexFreshness:   &compression.IndexFreshness{StaleAgainstHead: true},
+			SymbolId:         "sym",
+			TopModule:        &output.Module{ModuleId: "internal/query", Name: "query"},
+			Budget:           &compression.ResponseBudget{MaxDrilldowns: 10},
+		})
+		for _, d := range drilldowns {
+			if d.Tool == "" {
+				continue
+			}
+			schema, ok := schemas[d.Tool]
+			if !ok {
+				t.Errorf("%s: drilldown %q names unknown tool %q", reason, d.Label, d.Tool)
+				continue
+			}
+			properties, _ := schema["properties"].(map[string]interface{})
+			for name := range d.Params {
+				if _, ok := properties[name]; !ok {
+					t.Errorf("%s: drilldown %q passes undeclared %s param %q", reason, d.Label, d.Tool, name)
+				}
+			}
+		}
+	}
+}
@@ -235,7 +235,7 @@ func newTestMCPServerWithIndex(t testing.TB) *MCPServer {
 		Output: io.Discard,
 	})
 
-	db, err := storage.Open(":memory:", logger)
+	db, err := storage.Open(t.TempDir(), logger)
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
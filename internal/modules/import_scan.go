@@ -194,7 +194,13 @@ func (s *ImportScanner) ScanFile(filePath string, repoRoot string) ([]*ImportEdg
 
 // ScanDirectory scans all files in a directory for imports
 func (s *ImportScanner) ScanDirectory(dirPath string, repoRoot string, ignoreDirs []string) ([]*ImportEdge, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.config.ScanTimeoutMs)*time.Millisecond)
+	return s.ScanDirectoryContext(context.Background(), dirPath, repoRoot, ignoreDirs)
+}
+
+// ScanDirectoryContext is ScanDirectory, stopping early when ctx is done.
+// The configured scan timeout still applies.
+func (s *ImportScanner) ScanDirectoryContext(ctx context.Context, dirPath string, repoRoot string, ignoreDirs []string) ([]*ImportEdge, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.ScanTimeoutMs)*time.Millisecond)
 	defer cancel()
 
 	return s.scanDirectoryWithContext(ctx, dirPath, repoRoot, ignoreDirs)
@@ -27,6 +27,9 @@
 //  3. Null handling: Nil/undefined fields are omitted entirely
 //  4. Timestamps: Only in provenance block, excluded from snapshot tests
 //
+// DeterministicEncodeTo writes the same bytes to an io.Writer incrementally,
+// for responses too large to buffer comfortably.
+//
 // # Snapshot Testing
 //
 // The package provides tools for comparing responses in tests while excluding
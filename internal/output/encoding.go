@@ -1,8 +1,10 @@
 package output
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"io"
 	"reflect"
 	"sort"
 )
@@ -33,6 +35,99 @@ func DeterministicEncode(v interface{}) ([]byte, error) {
 	return result, nil
 }
 
+// DeterministicEncodeTo writes the same bytes as DeterministicEncode to w,
+// encoding incrementally instead of building the whole document in memory.
+// The normalized value tree is still built, since it decides which fields
+// are omitted, but the encoded output is never held in full.
+func DeterministicEncodeTo(w io.Writer, v interface{}) error {
+	bw := bufio.NewWriter(w)
+	sw := &streamWriter{w: bw}
+	sw.leaf = json.NewEncoder(&sw.scratch)
+	sw.leaf.SetEscapeHTML(false)
+
+	if err := sw.write(normalizeValue(v)); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// streamWriter encodes a normalized value tree the way encoding/json
+// would, one element at a time.
+type streamWriter struct {
+	w       *bufio.Writer
+	leaf    *json.Encoder
+	scratch bytes.Buffer
+}
+
+func (s *streamWriter) write(v interface{}) error {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		// encoding/json emits nil maps as null
+		if val == nil {
+			_, err := s.w.WriteString("null")
+			return err
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		if err := s.w.WriteByte('{'); err != nil {
+			return err
+		}
+		for i, k := range keys {
+			if i > 0 {
+				if err := s.w.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			if err := s.writeLeaf(k); err != nil {
+				return err
+			}
+			if err := s.w.WriteByte(':'); err != nil {
+				return err
+			}
+			if err := s.write(val[k]); err != nil {
+				return err
+			}
+		}
+		return s.w.WriteByte('}')
+	case []interface{}:
+		if val == nil {
+			_, err := s.w.WriteString("null")
+			return err
+		}
+		if err := s.w.WriteByte('['); err != nil {
+			return err
+		}
+		for i, elem := range val {
+			if i > 0 {
+				if err := s.w.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			if err := s.write(elem); err != nil {
+				return err
+			}
+		}
+		return s.w.WriteByte(']')
+	default:
+		return s.writeLeaf(v)
+	}
+}
+
+// writeLeaf encodes a scalar (or any value normalizeValue passed through)
+// with the same encoder settings as DeterministicEncode.
+func (s *streamWriter) writeLeaf(v interface{}) error {
+	s.scratch.Reset()
+	if err := s.leaf.Encode(v); err != nil {
+		return err
+	}
+	_, err := s.w.Write(bytes.TrimSuffix(s.scratch.Bytes(), []byte{'\n'}))
+	return err
+}
+
 // DeterministicEncodeIndented produces indented byte-identical JSON output
 func DeterministicEncodeIndented(v interface{}, indent string) ([]byte, error) {
 	// Normalize the value first
@@ -3,6 +3,7 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -295,3 +296,70 @@ func TestComplexNestedStructure(t *testing.T) {
 		t.Error("metadata keys are not properly handled")
 	}
 }
+
+func TestDeterministicEncodeToMatchesDeterministicEncode(t *testing.T) {
+	type nested struct {
+		Label string                 `json:"label"`
+		Extra map[string]interface{} `json:"extra,omitempty"`
+	}
+	score := 0.987654321
+
+	inputs := map[string]interface{}{
+		"nil":    nil,
+		"empty":  []string{},
+		"scalar": "a <b> & c",
+		"struct": struct {
+			Name     string    `json:"name"`
+			Score    *float64  `json:"score,omitempty"`
+			Missing  *float64  `json:"missing,omitempty"`
+			Tags     []string  `json:"tags"`
+			Children []nested  `json:"children"`
+			Empty    nested    `json:"empty"`
+			Weights  []float64 `json:"weights"`
+		}{
+			Name:     "héllo \"quoted\"\n<tag>",
+			Score:    &score,
+			Tags:     []string{"z", "a"},
+			Children: []nested{{Label: "x", Extra: map[string]interface{}{"k": nil, "b": 2, "a": []int{}}}, {}},
+			Weights:  []float64{1.0000001, 2.5, 1e-9},
+		},
+		"map": map[string]interface{}{
+			"zebra":  "last",
+			"alpha":  map[string]interface{}{"inner": 0.1234567},
+			"&amp":   true,
+			"nested": []interface{}{nil, 1, "two", map[string]interface{}{}},
+		},
+		"modules": []Module{
+			{ModuleId: "mod2", Name: "second", ImpactCount: 5, SymbolCount: 10},
+			{ModuleId: "mod1", Name: "first", ImpactCount: 10, SymbolCount: 5},
+		},
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			want, err := DeterministicEncode(input)
+			if err != nil {
+				t.Fatalf("DeterministicEncode() error = %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := DeterministicEncodeTo(&buf, input); err != nil {
+				t.Fatalf("DeterministicEncodeTo() error = %v", err)
+			}
+
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("DeterministicEncodeTo() wrote\n%s\nwant\n%s", buf.String(), want)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestDeterministicEncodeToWriteError(t *testing.T) {
+	if err := DeterministicEncodeTo(failingWriter{}, map[string]string{"a": "b"}); err == nil {
+		t.Error("expected the writer's error to be returned")
+	}
+}
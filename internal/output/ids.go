@@ -0,0 +1,80 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ID styles for serialized symbol IDs
+const (
+	IDStyleRaw      = "raw"      // Stable IDs as stored (default)
+	IDStyleReadable = "readable" // Friendly qualified names, raw ID kept alongside
+)
+
+// rawIDSuffix names the field holding the raw ID in readable style, e.g.
+// stableIdRaw next to stableId.
+const rawIDSuffix = "Raw"
+
+// idFields are the JSON keys rewritten by ReadableSymbolIDs.
+var idFields = []string{"stableId", "symbolId"}
+
+// ReadableSymbolIDs rewrites symbol IDs in an encoded JSON document to the
+// form returned by readable, moving each raw ID to a sibling field with the
+// "Raw" suffix. IDs readable can't render are left as they are.
+//
+// Drilldown params are skipped: they are inputs for follow-up calls and the
+// raw ID is what tools accept. The input is returned unchanged when nothing
+// needs rewriting.
+func ReadableSymbolIDs(data []byte, readable func(id string) (string, bool)) ([]byte, error) {
+	if readable == nil {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if !readableIDValue(doc, readable) {
+		return data, nil
+	}
+	return json.Marshal(doc)
+}
+
+func readableIDValue(v interface{}, readable func(string) (string, bool)) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, key := range idFields {
+			id, ok := val[key].(string)
+			if !ok {
+				continue
+			}
+			if _, done := val[key+rawIDSuffix]; done {
+				continue
+			}
+			if name, ok := readable(id); ok {
+				val[key] = name
+				val[key+rawIDSuffix] = id
+				changed = true
+			}
+		}
+		for key, child := range val {
+			if key == "params" {
+				continue
+			}
+			if readableIDValue(child, readable) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if readableIDValue(child, readable) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,56 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReadableSymbolIDs(t *testing.T) {
+	readable := func(id string) (string, bool) {
+		if !strings.HasPrefix(id, "scip-") {
+			return "", false
+		}
+		return strings.ToUpper(strings.TrimPrefix(id, "scip-")), true
+	}
+
+	data := []byte(`{"data":{"symbol":{"stableId":"scip-a","name":"a"},` +
+		`"callers":[{"symbolId":"scip-b"},{"symbolId":"local 3"}],` +
+		`"drilldowns":[{"tool":"getSymbol","params":{"symbolId":"scip-c"}}]}}`)
+
+	out, err := ReadableSymbolIDs(data, readable)
+	if err != nil {
+		t.Fatalf("ReadableSymbolIDs: %v", err)
+	}
+
+	var got struct {
+		Data struct {
+			Symbol     map[string]interface{}   `json:"symbol"`
+			Callers    []map[string]interface{} `json:"callers"`
+			Drilldowns []struct {
+				Params map[string]interface{} `json:"params"`
+			} `json:"drilldowns"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Data.Symbol["stableId"] != "A" || got.Data.Symbol["stableIdRaw"] != "scip-a" {
+		t.Errorf("symbol = %v", got.Data.Symbol)
+	}
+	if got.Data.Callers[0]["symbolId"] != "B" || got.Data.Callers[0]["symbolIdRaw"] != "scip-b" {
+		t.Errorf("first caller = %v", got.Data.Callers[0])
+	}
+	if _, ok := got.Data.Callers[1]["symbolIdRaw"]; ok || got.Data.Callers[1]["symbolId"] != "local 3" {
+		t.Errorf("unrenderable ID should be left alone: %v", got.Data.Callers[1])
+	}
+	if got.Data.Drilldowns[0].Params["symbolId"] != "scip-c" {
+		t.Errorf("drilldown params must keep raw IDs: %v", got.Data.Drilldowns[0].Params)
+	}
+
+	unchanged := []byte(`{"symbolId":"local 1"}`)
+	if out, _ := ReadableSymbolIDs(unchanged, readable); string(out) != string(unchanged) {
+		t.Errorf("expected input unchanged, got %s", out)
+	}
+}
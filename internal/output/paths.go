@@ -0,0 +1,142 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Path styles for serialized file paths
+const (
+	PathStyleRepo   = "repo"   // Repo-relative (default)
+	PathStyleModule = "module" // Relative to the owning module
+)
+
+// PathModuleKey holds, on an object, the module its paths are relative to.
+const PathModuleKey = "pathModule"
+
+// pathFields are the JSON keys rewritten by ModuleRelativePaths.
+var pathFields = []string{"path", "filePath", "fileId", "file"}
+
+// callParamKeys hold follow-up calls whose params are passed straight back
+// to a tool, so their paths must stay repo-relative.
+var callParamKeys = map[string]bool{
+	"suggestedNextCalls": true,
+	"drilldowns":         true,
+	"drilldown":          true,
+}
+
+// ModulePaths splits repo-relative paths by the module that contains them.
+type ModulePaths struct {
+	roots []string // Longest first, so nested modules win
+}
+
+// NewModulePaths creates a splitter for the given module root paths. The
+// repository root itself is ignored: stripping it would change nothing.
+func NewModulePaths(roots []string) *ModulePaths {
+	m := &ModulePaths{}
+	for _, root := range roots {
+		root = strings.Trim(root, "/")
+		if root == "" || root == "." {
+			continue
+		}
+		m.roots = append(m.roots, root)
+	}
+	sort.Slice(m.roots, func(i, j int) bool {
+		if len(m.roots[i]) != len(m.roots[j]) {
+			return len(m.roots[i]) > len(m.roots[j])
+		}
+		return m.roots[i] < m.roots[j]
+	})
+	return m
+}
+
+// Split returns the module containing path and the path relative to it.
+func (m *ModulePaths) Split(path string) (module, rel string, ok bool) {
+	for _, root := range m.roots {
+		if strings.HasPrefix(path, root+"/") {
+			return root, path[len(root)+1:], true
+		}
+	}
+	return "", path, false
+}
+
+// ModuleRelativePaths rewrites file paths in an encoded JSON document to be
+// relative to their module, recording the module under PathModuleKey on the
+// same object. An object's paths are only rewritten when they share one
+// module, so every path in it stays unambiguous.
+//
+// This only affects what is sent to clients; analysis stays repo-relative.
+// Suggested calls and drilldowns are left untouched since their params are
+// tool inputs, which take repo-relative paths.
+// The input is returned unchanged when nothing needs rewriting.
+func ModuleRelativePaths(data []byte, modules *ModulePaths) ([]byte, error) {
+	if modules == nil || len(modules.roots) == 0 {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if !moduleRelativeValue(doc, modules) {
+		return data, nil
+	}
+	return json.Marshal(doc)
+}
+
+// moduleRelativeValue walks a decoded JSON value and reports whether
+// anything was rewritten.
+func moduleRelativeValue(v interface{}, modules *ModulePaths) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if _, done := val[PathModuleKey]; !done && rewriteObjectPaths(val, modules) {
+			changed = true
+		}
+		for key, child := range val {
+			if key == PathModuleKey || callParamKeys[key] {
+				continue
+			}
+			if moduleRelativeValue(child, modules) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if moduleRelativeValue(child, modules) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func rewriteObjectPaths(obj map[string]interface{}, modules *ModulePaths) bool {
+	module := ""
+	rel := make(map[string]string)
+	for _, key := range pathFields {
+		s, ok := obj[key].(string)
+		if !ok {
+			continue
+		}
+		m, r, ok := modules.Split(s)
+		if !ok || (module != "" && m != module) {
+			return false
+		}
+		module = m
+		rel[key] = r
+	}
+	if module == "" {
+		return false
+	}
+	for key, r := range rel {
+		obj[key] = r
+	}
+	obj[PathModuleKey] = module
+	return true
+}
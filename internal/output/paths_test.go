@@ -0,0 +1,131 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestModulePathsSplit(t *testing.T) {
+	m := NewModulePaths([]string{".", "services/payments", "services/payments/sdk", "services/ledger/"})
+
+	tests := []struct {
+		path   string
+		module string
+		rel    string
+		ok     bool
+	}{
+		{"services/payments/internal/handlers/charge.go", "services/payments", "internal/handlers/charge.go", true},
+		{"services/payments/sdk/client.go", "services/payments/sdk", "client.go", true},
+		{"services/ledger/book.go", "services/ledger", "book.go", true},
+		{"services/paymentsx/main.go", "", "services/paymentsx/main.go", false},
+		{"README.md", "", "README.md", false},
+	}
+
+	for _, tt := range tests {
+		module, rel, ok := m.Split(tt.path)
+		if module != tt.module || rel != tt.rel || ok != tt.ok {
+			t.Errorf("Split(%q) = (%q, %q, %v), want (%q, %q, %v)", tt.path, module, rel, ok, tt.module, tt.rel, tt.ok)
+		}
+	}
+}
+
+func TestModuleRelativePaths(t *testing.T) {
+	doc := map[string]interface{}{
+		"data": map[string]interface{}{
+			"files": []interface{}{
+				map[string]interface{}{"path": "services/payments/handlers/charge.go", "symbolCount": 3},
+				map[string]interface{}{"path": "tools/gen.go"},
+			},
+			"location": map[string]interface{}{"fileId": "services/ledger/book.go", "startLine": 4},
+			"mixed": map[string]interface{}{
+				"path":     "services/payments/a.go",
+				"filePath": "services/ledger/b.go",
+			},
+		},
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := ModuleRelativePaths(data, NewModulePaths([]string{"services/payments", "services/ledger"}))
+	if err != nil {
+		t.Fatalf("ModuleRelativePaths: %v", err)
+	}
+
+	var got struct {
+		Data struct {
+			Files    []map[string]interface{} `json:"files"`
+			Location map[string]interface{}   `json:"location"`
+			Mixed    map[string]interface{}   `json:"mixed"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Data.Files[0]["path"] != "handlers/charge.go" || got.Data.Files[0][PathModuleKey] != "services/payments" {
+		t.Errorf("files[0] = %v", got.Data.Files[0])
+	}
+	if got.Data.Files[1]["path"] != "tools/gen.go" || got.Data.Files[1][PathModuleKey] != nil {
+		t.Errorf("path outside any module should be untouched, got %v", got.Data.Files[1])
+	}
+	if got.Data.Location["fileId"] != "book.go" || got.Data.Location[PathModuleKey] != "services/ledger" {
+		t.Errorf("location = %v", got.Data.Location)
+	}
+	if got.Data.Mixed["path"] != "services/payments/a.go" || got.Data.Mixed[PathModuleKey] != nil {
+		t.Errorf("paths from different modules should stay repo-relative, got %v", got.Data.Mixed)
+	}
+
+	unchanged, err := ModuleRelativePaths(data, NewModulePaths([]string{"."}))
+	if err != nil || string(unchanged) != string(data) {
+		t.Error("expected input returned unchanged with only a root module")
+	}
+}
+
+func TestModuleRelativePathsKeepsCallParams(t *testing.T) {
+	doc := map[string]interface{}{
+		"data": map[string]interface{}{
+			"path": "services/payments/a.go",
+			"drilldowns": []interface{}{
+				map[string]interface{}{"tool": "explainFile", "params": map[string]interface{}{"filePath": "services/payments/a.go"}},
+			},
+		},
+		"suggestedNextCalls": []interface{}{
+			map[string]interface{}{"tool": "getModuleOverview", "params": map[string]interface{}{"path": "services/payments/a.go"}},
+		},
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := ModuleRelativePaths(data, NewModulePaths([]string{"services/payments"}))
+	if err != nil {
+		t.Fatalf("ModuleRelativePaths: %v", err)
+	}
+
+	type call struct {
+		Params map[string]interface{} `json:"params"`
+	}
+	var got struct {
+		Data struct {
+			Path       string `json:"path"`
+			Drilldowns []call `json:"drilldowns"`
+		} `json:"data"`
+		SuggestedNextCalls []call `json:"suggestedNextCalls"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Data.Path != "a.go" {
+		t.Errorf("data path = %q, want a.go", got.Data.Path)
+	}
+	if p := got.SuggestedNextCalls[0].Params; p["path"] != "services/payments/a.go" || p[PathModuleKey] != nil {
+		t.Errorf("suggested call params should stay repo-relative, got %v", p)
+	}
+	if p := got.Data.Drilldowns[0].Params; p["filePath"] != "services/payments/a.go" || p[PathModuleKey] != nil {
+		t.Errorf("drilldown params should stay repo-relative, got %v", p)
+	}
+}
@@ -3,7 +3,9 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"sort"
 )
 
 // SnapshotExcludeFields lists fields to exclude when comparing responses for tests
@@ -11,6 +13,7 @@ var SnapshotExcludeFields = []string{
 	"provenance.cachedAt",
 	"provenance.queryDurationMs",
 	"provenance.computedAt",
+	"provenance.extra",
 }
 
 // NormalizeForSnapshot removes time-varying fields for comparison
@@ -52,6 +55,98 @@ func CompareSnapshots(a, b []byte) (bool, string) {
 	return true, ""
 }
 
+// FirstDifference returns the path of the first field that differs between
+// two responses (ignoring time-varying fields), or "" if they are identical.
+// Paths use dot notation for objects and [i] for array elements, e.g.
+// "modules[2].name". Keys are visited in sorted order so the result is stable.
+func FirstDifference(a, b []byte) (string, error) {
+	normalizedA, err := NormalizeForSnapshot(a)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize snapshot A: %w", err)
+	}
+	normalizedB, err := NormalizeForSnapshot(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize snapshot B: %w", err)
+	}
+	if bytes.Equal(normalizedA, normalizedB) {
+		return "", nil
+	}
+
+	var parsedA, parsedB interface{}
+	if err := json.Unmarshal(normalizedA, &parsedA); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(normalizedB, &parsedB); err != nil {
+		return "", err
+	}
+
+	path, _ := firstDiffPath(parsedA, parsedB, "")
+	if path == "" {
+		path = "$"
+	}
+	return path, nil
+}
+
+// firstDiffPath walks two decoded JSON values in parallel and returns the
+// path of the first mismatch.
+func firstDiffPath(a, b interface{}, path string) (string, bool) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			return path, true
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, seen := av[k]; !seen {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			aChild, aOk := av[k]
+			bChild, bOk := bv[k]
+			if aOk != bOk {
+				return childPath, true
+			}
+			if p, differs := firstDiffPath(aChild, bChild, childPath); differs {
+				return p, true
+			}
+		}
+		return "", false
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			return path, true
+		}
+		n := len(av)
+		if len(bv) < n {
+			n = len(bv)
+		}
+		for i := 0; i < n; i++ {
+			if p, differs := firstDiffPath(av[i], bv[i], fmt.Sprintf("%s[%d]", path, i)); differs {
+				return p, true
+			}
+		}
+		if len(av) != len(bv) {
+			return fmt.Sprintf("%s[%d]", path, n), true
+		}
+		return "", false
+	default:
+		if !reflect.DeepEqual(a, b) {
+			return path, true
+		}
+		return "", false
+	}
+}
+
 // removeNestedField removes a nested field from a map using dot notation
 // e.g., "provenance.cachedAt" removes the "cachedAt" field from the "provenance" object
 func removeNestedField(data map[string]interface{}, path string) {
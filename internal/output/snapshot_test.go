@@ -44,6 +44,17 @@ func TestNormalizeForSnapshot(t *testing.T) {
 			}`,
 			want: `{"data":"test","provenance":{"backend":"scip"}}`,
 		},
+		{
+			name: "remove operator extras",
+			input: `{
+				"data": "test",
+				"provenance": {
+					"extra": {"environment": "prod", "tenant": "acme"},
+					"backend": "scip"
+				}
+			}`,
+			want: `{"data":"test","provenance":{"backend":"scip"}}`,
+		},
 		{
 			name: "remove all time-varying fields",
 			input: `{
@@ -513,3 +524,61 @@ func TestDeepEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestFirstDifference(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{
+			name: "identical",
+			a:    `{"modules":["a","b"]}`,
+			b:    `{"modules":["a","b"]}`,
+			want: "",
+		},
+		{
+			name: "only time fields differ",
+			a:    `{"data":1,"provenance":{"cachedAt":"2024-01-01T00:00:00Z"}}`,
+			b:    `{"data":1,"provenance":{"cachedAt":"2024-02-02T00:00:00Z"}}`,
+			want: "",
+		},
+		{
+			name: "nested value differs",
+			a:    `{"modules":[{"name":"a"},{"name":"b"}]}`,
+			b:    `{"modules":[{"name":"a"},{"name":"c"}]}`,
+			want: "modules[1].name",
+		},
+		{
+			name: "array length differs",
+			a:    `{"items":[1,2]}`,
+			b:    `{"items":[1,2,3]}`,
+			want: "items[2]",
+		},
+		{
+			name: "missing key",
+			a:    `{"a":1,"b":2}`,
+			b:    `{"a":1}`,
+			want: "b",
+		},
+		{
+			name: "first key in sorted order wins",
+			a:    `{"z":1,"m":1}`,
+			b:    `{"z":2,"m":2}`,
+			want: "m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FirstDifference([]byte(tt.a), []byte(tt.b))
+			if err != nil {
+				t.Fatalf("FirstDifference() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FirstDifference() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// DefaultMaxSymbolTextLength is the longest symbol name or signature, in
+// characters, emitted in serialized responses.
+const DefaultMaxSymbolTextLength = 512
+
+// symbolTextFields are the JSON keys shortened by TruncateSymbolText.
+var symbolTextFields = []string{"name", "signature", "signatureNormalized"}
+
+// TruncatedFieldsKey lists, on an object, which of its fields were shortened.
+const TruncatedFieldsKey = "truncatedFields"
+
+// TruncateSymbolText shortens over-long symbol names and signatures in an
+// encoded JSON document, ending them with an ellipsis and recording the
+// shortened keys under TruncatedFieldsKey on the same object.
+//
+// This only affects what is sent to clients; analysis keeps the full values.
+// The input is returned unchanged when nothing needs shortening.
+func TruncateSymbolText(data []byte, maxLen int) ([]byte, error) {
+	if maxLen <= 0 {
+		maxLen = DefaultMaxSymbolTextLength
+	}
+	if len(data) <= maxLen {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if !truncateSymbolTextValue(doc, maxLen) {
+		return data, nil
+	}
+	return json.Marshal(doc)
+}
+
+// truncateSymbolTextValue walks a decoded JSON value and reports whether
+// anything was shortened.
+func truncateSymbolTextValue(v interface{}, maxLen int) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]interface{}:
+		var truncated []string
+		for _, key := range symbolTextFields {
+			s, ok := val[key].(string)
+			if !ok {
+				continue
+			}
+			if short, cut := TruncateText(s, maxLen); cut {
+				val[key] = short
+				truncated = append(truncated, key)
+			}
+		}
+		if len(truncated) > 0 {
+			val[TruncatedFieldsKey] = truncated
+			changed = true
+		}
+		for key, child := range val {
+			if key == TruncatedFieldsKey {
+				continue
+			}
+			if truncateSymbolTextValue(child, maxLen) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if truncateSymbolTextValue(child, maxLen) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// TruncateText shortens s to at most maxLen characters, replacing the tail
+// with an ellipsis. It reports whether s was shortened.
+func TruncateText(s string, maxLen int) (string, bool) {
+	if maxLen <= 0 || len(s) <= maxLen {
+		return s, false
+	}
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s, false
+	}
+	if maxLen == 1 {
+		return "…", true
+	}
+	return string(runes[:maxLen-1]) + "…", true
+}
@@ -0,0 +1,108 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		in      string
+		max     int
+		want    string
+		wantCut bool
+	}{
+		{"short", 10, "short", false},
+		{"exactly10!", 10, "exactly10!", false},
+		{"much too long", 5, "much…", true},
+		{"ünïcödé-näme", 4, "ünï…", true},
+		{"abc", 0, "abc", false},
+	}
+
+	for _, tt := range tests {
+		got, cut := TruncateText(tt.in, tt.max)
+		if got != tt.want || cut != tt.wantCut {
+			t.Errorf("TruncateText(%q, %d) = (%q, %v), want (%q, %v)", tt.in, tt.max, got, cut, tt.want, tt.wantCut)
+		}
+	}
+}
+
+func TestTruncateSymbolText(t *testing.T) {
+	long := strings.Repeat("x", 40)
+	doc := map[string]interface{}{
+		"data": map[string]interface{}{
+			"symbol": map[string]interface{}{
+				"name":                long,
+				"signature":           "func " + long,
+				"signatureNormalized": "short",
+				"documentation":       long,
+				"referenceCount":      int64(9007199254740993),
+			},
+			"items": []interface{}{
+				map[string]interface{}{"name": "ok"},
+				map[string]interface{}{"name": long},
+			},
+		},
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := TruncateSymbolText(data, 16)
+	if err != nil {
+		t.Fatalf("TruncateSymbolText: %v", err)
+	}
+
+	var got struct {
+		Data struct {
+			Symbol struct {
+				Name                string   `json:"name"`
+				Signature           string   `json:"signature"`
+				SignatureNormalized string   `json:"signatureNormalized"`
+				Documentation       string   `json:"documentation"`
+				ReferenceCount      int64    `json:"referenceCount"`
+				TruncatedFields     []string `json:"truncatedFields"`
+			} `json:"symbol"`
+			Items []struct {
+				Name            string   `json:"name"`
+				TruncatedFields []string `json:"truncatedFields"`
+			} `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	sym := got.Data.Symbol
+	if len([]rune(sym.Name)) != 16 || !strings.HasSuffix(sym.Name, "…") {
+		t.Errorf("name not truncated to 16 chars: %q", sym.Name)
+	}
+	if !strings.HasPrefix(sym.Signature, "func x") || len([]rune(sym.Signature)) != 16 {
+		t.Errorf("signature not truncated: %q", sym.Signature)
+	}
+	if sym.SignatureNormalized != "short" || sym.Documentation != long {
+		t.Errorf("unrelated or short fields must be untouched: %+v", sym)
+	}
+	if sym.ReferenceCount != 9007199254740993 {
+		t.Errorf("numbers must survive re-encoding exactly, got %d", sym.ReferenceCount)
+	}
+	if strings.Join(sym.TruncatedFields, ",") != "name,signature" {
+		t.Errorf("truncatedFields = %v", sym.TruncatedFields)
+	}
+	if got.Data.Items[0].TruncatedFields != nil || len(got.Data.Items[1].TruncatedFields) != 1 {
+		t.Errorf("expected only the long item marked, got %+v", got.Data.Items)
+	}
+}
+
+func TestTruncateSymbolTextUnchanged(t *testing.T) {
+	data := []byte(`{"z":1,"a":{"name":"fine","signature":"func fine()"}}`)
+	out, err := TruncateSymbolText(data, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(data) {
+		t.Errorf("expected input returned as-is, got %s", out)
+	}
+}
@@ -35,11 +35,17 @@ type ImpactItem struct {
 	Confidence float64 `json:"confidence"`
 }
 
-// Drilldown represents a suggested follow-up query
+// Drilldown represents a suggested follow-up query.
+// Query is a human-readable display string; Tool and Params carry the same
+// call in structured form so clients can dispatch it without parsing Query.
+// Params only holds the tool's declared inputs; a query with options no tool
+// accepts has no Tool.
 type Drilldown struct {
-	Label          string  `json:"label"`
-	Query          string  `json:"query"`
-	RelevanceScore float64 `json:"relevanceScore"`
+	Label          string                 `json:"label"`
+	Query          string                 `json:"query"`
+	Tool           string                 `json:"tool,omitempty"`
+	Params         map[string]interface{} `json:"params,omitempty"`
+	RelevanceScore float64                `json:"relevanceScore"`
 }
 
 // Warning represents a warning message
@@ -0,0 +1,32 @@
+package output
+
+// Warning severities, as ranked by WarningSeverity
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// MeetsSeverity reports whether severity is at least as severe as min.
+// An empty or unknown min admits everything.
+func MeetsSeverity(severity, min string) bool {
+	if _, ok := WarningSeverity[min]; !ok {
+		return true
+	}
+	return GetWarningSeverity(severity) <= GetWarningSeverity(min)
+}
+
+// FilterWarnings returns the warnings at or above min severity, preserving
+// order. The input slice is not modified.
+func FilterWarnings(warnings []Warning, min string) []Warning {
+	if _, ok := WarningSeverity[min]; !ok || len(warnings) == 0 {
+		return warnings
+	}
+	kept := make([]Warning, 0, len(warnings))
+	for _, w := range warnings {
+		if MeetsSeverity(w.Severity, min) {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,53 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMeetsSeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		min      string
+		want     bool
+	}{
+		{SeverityError, SeverityWarning, true},
+		{SeverityWarning, SeverityWarning, true},
+		{SeverityInfo, SeverityWarning, false},
+		{SeverityInfo, SeverityInfo, true},
+		{SeverityWarning, SeverityError, false},
+		{"", SeverityWarning, false}, // Unknown severities rank as info
+		{SeverityInfo, "", true},
+		{SeverityInfo, "bogus", true},
+	}
+
+	for _, tt := range tests {
+		if got := MeetsSeverity(tt.severity, tt.min); got != tt.want {
+			t.Errorf("MeetsSeverity(%q, %q) = %v, want %v", tt.severity, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestFilterWarnings(t *testing.T) {
+	warnings := []Warning{
+		{Severity: SeverityInfo, Text: "note"},
+		{Severity: SeverityError, Text: "broken"},
+		{Severity: SeverityWarning, Text: "heuristic"},
+	}
+
+	got := FilterWarnings(warnings, SeverityWarning)
+	want := []Warning{
+		{Severity: SeverityError, Text: "broken"},
+		{Severity: SeverityWarning, Text: "heuristic"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterWarnings(warning) = %v, want %v", got, want)
+	}
+
+	if got := FilterWarnings(warnings, ""); len(got) != 3 {
+		t.Errorf("FilterWarnings with no threshold dropped warnings: %v", got)
+	}
+	if warnings[0].Text != "note" {
+		t.Error("FilterWarnings modified its input")
+	}
+}
@@ -0,0 +1,168 @@
+package paths
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreMatcher matches repo-relative paths against a repository's
+// .gitignore files: the root one and any nested ones, each applying to its
+// own directory. Later rules win over earlier ones and deeper files over
+// shallower ones, and "!" negates, as in git.
+//
+// Like git, a path inside an ignored directory can't be re-included;
+// Ignored only looks at the path itself, so callers walking a tree must
+// skip ignored directories rather than descend into them. Nested files are
+// read on first use, so a matcher is not safe for concurrent use.
+type IgnoreMatcher struct {
+	root  string
+	rules map[string][]ignoreRule // By repo-relative directory, "" for the root; loaded lazily
+}
+
+// ignoreRule is one parsed .gitignore pattern.
+type ignoreRule struct {
+	segments []string // Slash-separated pattern, "**" matching any number of segments
+	negate   bool
+	dirOnly  bool
+}
+
+// LoadIgnoreMatcher returns a matcher for the repository at root, or nil
+// when root has no .gitignore. All methods are safe to call on nil, which
+// ignores nothing.
+func LoadIgnoreMatcher(root string) *IgnoreMatcher {
+	rules, ok := readIgnoreFile(filepath.Join(root, ".gitignore"))
+	if !ok {
+		return nil
+	}
+	return &IgnoreMatcher{
+		root:  root,
+		rules: map[string][]ignoreRule{"": rules},
+	}
+}
+
+// Ignored reports whether rel, a slash-separated path relative to the
+// repository root, is ignored. isDir selects directory-only patterns.
+func (m *IgnoreMatcher) Ignored(rel string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+	rel = strings.Trim(path.Clean(filepath.ToSlash(rel)), "/")
+	if rel == "" || rel == "." || strings.HasPrefix(rel, "../") {
+		return false
+	}
+
+	ignored := false
+	dir := ""
+	for {
+		base := strings.TrimPrefix(strings.TrimPrefix(rel, dir), "/")
+		for _, rule := range m.rulesFor(dir) {
+			if rule.dirOnly && !isDir {
+				continue
+			}
+			if matchIgnoreSegments(rule.segments, strings.Split(base, "/")) {
+				ignored = !rule.negate
+			}
+		}
+
+		// Descend to the next directory on the way to rel
+		i := strings.Index(base, "/")
+		if i < 0 {
+			return ignored
+		}
+		if dir == "" {
+			dir = base[:i]
+		} else {
+			dir = dir + "/" + base[:i]
+		}
+	}
+}
+
+// rulesFor returns the rules of the .gitignore in the repo-relative dir.
+func (m *IgnoreMatcher) rulesFor(dir string) []ignoreRule {
+	if rules, ok := m.rules[dir]; ok {
+		return rules
+	}
+	rules, _ := readIgnoreFile(filepath.Join(m.root, filepath.FromSlash(dir), ".gitignore"))
+	m.rules[dir] = rules
+	return rules
+}
+
+// readIgnoreFile parses a .gitignore file; ok is false when it can't be read.
+func readIgnoreFile(file string) (rules []ignoreRule, ok bool) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, true
+}
+
+// parseIgnoreRule parses one .gitignore line; ok is false for blank lines
+// and comments.
+func parseIgnoreRule(line string) (rule ignoreRule, ok bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		// \# and \! escape a literal leading character
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	// A pattern with a slash other than a trailing one is relative to the
+	// .gitignore's directory; otherwise it matches a name at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	rule.segments = strings.Split(line, "/")
+	if n := len(rule.segments); n > 1 && rule.segments[n-1] == "**" {
+		// "dir/**" matches what is inside dir, not dir itself
+		rule.segments = append(rule.segments[:n-1], "*", "**")
+	}
+	if !anchored {
+		rule.segments = append([]string{"**"}, rule.segments...)
+	}
+	return rule, true
+}
+
+// matchIgnoreSegments matches path segments against pattern segments,
+// where "**" matches zero or more whole segments.
+func matchIgnoreSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchIgnoreSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchIgnoreSegments(pattern[1:], name[1:])
+}
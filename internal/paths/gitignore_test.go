@@ -0,0 +1,68 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".gitignore", "# build output\ndist/\n/target\n*.log\n!keep.log\n__pycache__/\ndocs/**/*.html\ncache/**\n")
+	write("web/.gitignore", "generated\n!important.log\n")
+
+	m := LoadIgnoreMatcher(root)
+	if m == nil {
+		t.Fatal("expected a matcher for a repo with .gitignore")
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"dist", true, true},
+		{"web/dist", true, true},
+		{"dist", false, false}, // dist/ only matches directories
+		{"target", true, true},
+		{"src/target", true, false}, // /target is anchored to the root
+		{"app.log", false, true},
+		{"src/deep/app.log", false, true},
+		{"keep.log", false, false},
+		{"src/__pycache__", true, true},
+		{"docs/a/b/page.html", false, true},
+		{"docs/page.html", false, true},
+		{"page.html", false, false},
+		{"cache", true, false}, // cache/** matches the contents only
+		{"cache/x", false, true},
+		{"web/generated", true, true},
+		{"generated", true, false}, // web/.gitignore applies below web only
+		{"web/important.log", false, false},
+		{"src/main.go", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Ignored(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Ignored(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestLoadIgnoreMatcher_NoGitignore(t *testing.T) {
+	m := LoadIgnoreMatcher(t.TempDir())
+	if m != nil {
+		t.Fatal("expected nil matcher without .gitignore")
+	}
+	if m.Ignored("dist", true) {
+		t.Error("nil matcher should ignore nothing")
+	}
+}
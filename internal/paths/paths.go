@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
@@ -57,12 +58,67 @@ func IsWithinRepo(path string, repoRoot string) bool {
 	return !strings.HasPrefix(canonical, "..")
 }
 
+// ResolveRepoPath resolves symlinks in a repo-relative path and returns the
+// repo-relative path of the file it points to. Paths that do not exist are
+// returned as-is. ok is false when the target lies outside the repository.
+func ResolveRepoPath(relPath string, repoRoot string) (resolved string, ok bool) {
+	normalized := NormalizePath(relPath)
+	absPath := JoinRepoPath(repoRoot, normalized)
+	if _, err := os.Lstat(absPath); err != nil {
+		return normalized, true
+	}
+
+	canonical, err := CanonicalizePath(absPath, repoRoot)
+	if err != nil {
+		return normalized, true
+	}
+	if canonical == ".." || strings.HasPrefix(canonical, "../") {
+		return normalized, false
+	}
+	return canonical, true
+}
+
 // NormalizePath normalizes a path by converting backslashes to forward slashes
 // This is useful for paths that are already relative but need normalization
 func NormalizePath(path string) string {
 	return filepath.ToSlash(path)
 }
 
+// ToRepoRelative converts a path as reported by a tool (git, an indexer) to
+// repo-relative forward-slash form. It accepts Windows separators, file://
+// URIs, leading "./" and absolute paths under repoRoot. Absolute paths
+// outside the repository are only cleaned. Symlinks are not resolved; see
+// ResolveRepoPath for that.
+func ToRepoRelative(p string, repoRoot string) string {
+	if p == "" {
+		return ""
+	}
+	p = strings.ReplaceAll(strings.TrimPrefix(p, "file://"), "\\", "/")
+	if !IsAbsSlashPath(p) {
+		return path.Clean(p)
+	}
+
+	root := strings.TrimSuffix(strings.ReplaceAll(repoRoot, "\\", "/"), "/")
+	switch {
+	case root == "":
+	case p == root:
+		return "."
+	case strings.HasPrefix(p, root+"/"):
+		return path.Clean(p[len(root)+1:])
+	}
+	return path.Clean(p)
+}
+
+// IsAbsSlashPath reports whether a forward-slash path is absolute on any
+// platform, so Windows paths are recognized when running elsewhere.
+func IsAbsSlashPath(p string) bool {
+	if strings.HasPrefix(p, "/") {
+		return true
+	}
+	return len(p) >= 3 && p[1] == ':' && p[2] == '/' &&
+		((p[0] >= 'a' && p[0] <= 'z') || (p[0] >= 'A' && p[0] <= 'Z'))
+}
+
 // JoinRepoPath joins a repo root with a canonical path
 func JoinRepoPath(repoRoot string, canonicalPath string) string {
 	// Ensure we use forward slashes in the canonical path
@@ -330,6 +330,32 @@ func TestNormalizePath(t *testing.T) {
 	// On Windows, backslashes would be converted to forward slashes
 }
 
+func TestToRepoRelative(t *testing.T) {
+	tests := []struct {
+		path     string
+		repoRoot string
+		want     string
+	}{
+		{"internal/query/engine.go", "/repo", "internal/query/engine.go"},
+		{"./internal/query/engine.go", "/repo", "internal/query/engine.go"},
+		{"internal\\query\\engine.go", "/repo", "internal/query/engine.go"},
+		{"/repo/internal/query/engine.go", "/repo", "internal/query/engine.go"},
+		{"/repo/internal/query/engine.go", "/repo/", "internal/query/engine.go"},
+		{"file:///repo/internal/query/engine.go", "/repo", "internal/query/engine.go"},
+		{"C:\\src\\repo\\main.go", "C:\\src\\repo", "main.go"},
+		{"/repo", "/repo", "."},
+		{"/repository/main.go", "/repo", "/repository/main.go"},
+		{"/other//main.go", "/repo", "/other/main.go"},
+		{"", "/repo", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ToRepoRelative(tt.path, tt.repoRoot); got != tt.want {
+			t.Errorf("ToRepoRelative(%q, %q) = %q, want %q", tt.path, tt.repoRoot, got, tt.want)
+		}
+	}
+}
+
 func TestJoinRepoPath(t *testing.T) {
 	result := JoinRepoPath("/repo/root", "path/to/file.go")
 	expected := filepath.Join("/repo/root", "path", "to", "file.go")
@@ -758,3 +784,47 @@ func TestPathConstants(t *testing.T) {
 		t.Errorf("DaemonSubdir = %q, want %q", DaemonSubdir, "daemon")
 	}
 }
+
+func TestResolveRepoPath(t *testing.T) {
+	repoRoot := t.TempDir()
+	outside := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(repoRoot, "shared"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoRoot, "svc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(repoRoot, "shared", "util.go")
+	if err := os.WriteFile(target, []byte("package shared\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, filepath.Join(repoRoot, "svc", "util.go")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	external := filepath.Join(outside, "secret.go")
+	if err := os.WriteFile(external, []byte("package secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(external, filepath.Join(repoRoot, "svc", "escape.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"shared/util.go", "shared/util.go", true},
+		{"svc/util.go", "shared/util.go", true},
+		{"svc/escape.go", "svc/escape.go", false},
+		{"missing/file.go", "missing/file.go", true},
+	}
+
+	for _, tt := range tests {
+		got, ok := ResolveRepoPath(tt.path, repoRoot)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ResolveRepoPath(%q) = (%q, %v), want (%q, %v)", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package project
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"os/exec"
@@ -449,6 +451,40 @@ func BuildCppCommand(root, overrideCompdb string) (string, error) {
 	return "scip-clang --compdb-path=" + compdbPath, nil
 }
 
+// ErrNoCompileCommands is returned by BuildIndexCommand for a C/C++ project
+// without a compile_commands.json.
+var ErrNoCompileCommands = errors.New("compile_commands.json not found")
+
+// BuildIndexCommand returns the indexer command line that indexes a project
+// in lang, writing Go indexes to indexPath. compdb overrides where C/C++
+// projects look for compile_commands.json. Both `ckb index` and automatic
+// reindexing run this command.
+func BuildIndexCommand(root string, lang Language, indexPath, compdb string) (string, error) {
+	indexer := GetIndexerInfo(lang)
+	if indexer == nil {
+		return "", fmt.Errorf("no SCIP indexer available for %s", LanguageDisplayName(lang))
+	}
+
+	switch lang {
+	case LangCpp:
+		command, err := BuildCppCommand(root, compdb)
+		if err != nil {
+			return "", err
+		}
+		if command == "" {
+			return "", ErrNoCompileCommands
+		}
+		return command, nil
+	case LangRuby:
+		return BuildRubyCommand(root)
+	case LangGo:
+		// scip-go writes to the working directory unless told otherwise
+		return fmt.Sprintf("%s --output %s", indexer.Command, indexPath), nil
+	default:
+		return indexer.Command, nil
+	}
+}
+
 // BuildRubyCommand builds the appropriate scip-ruby command based on project setup.
 func BuildRubyCommand(root string) (string, error) {
 	hasGemfile := fileExists(filepath.Join(root, "Gemfile"))
@@ -425,6 +425,41 @@ func TestBuildRubyCommand(t *testing.T) {
 	}
 }
 
+func TestBuildIndexCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    Language
+		files   []string
+		compdb  string
+		wantCmd string
+		wantErr error
+	}{
+		{name: "Go writes to the index path", lang: LangGo, wantCmd: "scip-go --output .scip/index.scip"},
+		{name: "Python uses the indexer command", lang: LangPython, wantCmd: "scip-python index ."},
+		{name: "C++ finds the compdb", lang: LangCpp, files: []string{"build/compile_commands.json"}, wantCmd: "scip-clang --compdb-path=build/compile_commands.json"},
+		{name: "C++ compdb override", lang: LangCpp, compdb: "out/cc.json", wantCmd: "scip-clang --compdb-path=out/cc.json"},
+		{name: "C++ without compdb", lang: LangCpp, wantErr: ErrNoCompileCommands},
+		{name: "Ruby without Gemfile", lang: LangRuby, files: []string{"lib/app.rb"}, wantCmd: "scip-ruby ."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupTestDir(t, tt.files)
+			got, err := BuildIndexCommand(dir, tt.lang, ".scip/index.scip", tt.compdb)
+			if err != tt.wantErr {
+				t.Fatalf("BuildIndexCommand() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.wantCmd {
+				t.Errorf("BuildIndexCommand() = %q, want %q", got, tt.wantCmd)
+			}
+		})
+	}
+
+	if _, err := BuildIndexCommand(t.TempDir(), LangUnknown, "", ""); err == nil {
+		t.Error("expected an error for a language without an indexer")
+	}
+}
+
 func TestValidatePHPSetup(t *testing.T) {
 	tests := []struct {
 		name        string
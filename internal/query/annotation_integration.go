@@ -2,6 +2,9 @@ package query
 
 import (
 	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
 	"strings"
 
 	"ckb/internal/decisions"
@@ -15,19 +18,26 @@ type RelatedDecision struct {
 	Status          string   `json:"status"`
 	AffectedModules []string `json:"affectedModules,omitempty"`
 	FilePath        string   `json:"filePath,omitempty"`
+
+	// Set by analyzeImpact: which impacted symbols the decision names
+	Scope           string   `json:"scope,omitempty"`           // "symbol" or "module"
+	AffectedSymbols []string `json:"affectedSymbols,omitempty"` // Stable IDs, sorted
 }
 
+// Decision scopes within an impact analysis
+const (
+	DecisionScopeSymbol = "symbol" // The decision names some of the impacted symbols or their files
+	DecisionScopeModule = "module" // The decision only applies through a shared module
+)
+
 // ModuleAnnotations contains declared metadata for a module
 type ModuleAnnotations struct {
-	Responsibility string   `json:"responsibility,omitempty"`
-	Capabilities   []string `json:"capabilities,omitempty"`
-	Tags           []string `json:"tags,omitempty"`
-	Boundaries     *struct {
-		Public   []string `json:"public,omitempty"`
-		Internal []string `json:"internal,omitempty"`
-	} `json:"boundaries,omitempty"`
-	Source     string  `json:"source"` // "declared" or "inferred"
-	Confidence float64 `json:"confidence"`
+	Responsibility string      `json:"responsibility,omitempty"`
+	Capabilities   []string    `json:"capabilities,omitempty"`
+	Tags           []string    `json:"tags,omitempty"`
+	Boundaries     *Boundaries `json:"boundaries,omitempty"`
+	Source         string      `json:"source"` // "declared" or "inferred"
+	Confidence     float64     `json:"confidence"`
 }
 
 // AnnotationContext provides annotation data for enriching tool responses
@@ -149,19 +159,10 @@ func (e *Engine) getModuleAnnotations(moduleID string) *ModuleAnnotations {
 
 		// Boundaries
 		if module.Boundaries != nil && *module.Boundaries != "" && *module.Boundaries != "{}" {
-			var boundaries struct {
-				Public   []string `json:"public"`
-				Internal []string `json:"internal"`
-			}
+			var boundaries Boundaries
 			if err := json.Unmarshal([]byte(*module.Boundaries), &boundaries); err == nil {
-				if len(boundaries.Public) > 0 || len(boundaries.Internal) > 0 {
-					annotations.Boundaries = &struct {
-						Public   []string `json:"public,omitempty"`
-						Internal []string `json:"internal,omitempty"`
-					}{
-						Public:   boundaries.Public,
-						Internal: boundaries.Internal,
-					}
+				if len(boundaries.Public) > 0 || len(boundaries.Internal) > 0 || len(boundaries.API) > 0 {
+					annotations.Boundaries = &boundaries
 				}
 			}
 		}
@@ -191,6 +192,67 @@ func (e *Engine) getAnnotationContext(moduleID string) *AnnotationContext {
 	}
 }
 
+// linkDecisionsToImpact records, on each decision, the impacted symbols its
+// text names directly or through their file. Decisions naming none remain
+// module-scoped. Symbol-scoped decisions are ordered first, by how many
+// symbols they cover.
+func (e *Engine) linkDecisionsToImpact(related []RelatedDecision, impacted []ImpactItem) {
+	for i := range related {
+		related[i].Scope = DecisionScopeModule
+		adr := e.parseDecisionFromFile(related[i].FilePath)
+		if adr == nil {
+			continue
+		}
+		text := strings.Join(append([]string{adr.Title, adr.Context, adr.Decision},
+			append(adr.Consequences, adr.Alternatives...)...), "\n")
+		if ids := symbolsMentioned(text, impacted); len(ids) > 0 {
+			related[i].Scope = DecisionScopeSymbol
+			related[i].AffectedSymbols = ids
+		}
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		if len(related[i].AffectedSymbols) != len(related[j].AffectedSymbols) {
+			return len(related[i].AffectedSymbols) > len(related[j].AffectedSymbols)
+		}
+		return related[i].ID < related[j].ID
+	})
+}
+
+// symbolsMentioned returns the sorted stable IDs of the items that text
+// names, either by symbol name as a whole word or by file path or base name.
+func symbolsMentioned(text string, items []ImpactItem) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, item := range items {
+		if seen[item.StableId] {
+			continue
+		}
+		if mentionsName(text, item.Name) || (item.Location != nil && mentionsFile(text, item.Location.FileId)) {
+			seen[item.StableId] = true
+			ids = append(ids, item.StableId)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func mentionsName(text, name string) bool {
+	// Very short names match too much prose to be meaningful
+	if len(name) < 3 || !strings.Contains(text, name) {
+		return false
+	}
+	matched, err := regexp.MatchString(`\b`+regexp.QuoteMeta(name)+`\b`, text)
+	return err == nil && matched
+}
+
+func mentionsFile(text, fileId string) bool {
+	if fileId == "" {
+		return false
+	}
+	return strings.Contains(text, fileId) || strings.Contains(text, path.Base(fileId))
+}
+
 // getDecisionForSymbol checks if there's an ADR that mentions this symbol should be kept
 // Returns (hasDecision, decisionID, decisionTitle)
 //
@@ -213,8 +275,6 @@ func (e *Engine) getDecisionForSymbol(moduleID string, symbolName string) (bool,
 }
 
 // parseDecisionFromFile reads full decision content from file
-//
-//nolint:unused // reserved for future enhancement
 func (e *Engine) parseDecisionFromFile(filePath string) *decisions.ArchitecturalDecision {
 	if filePath == "" {
 		return nil
@@ -1,6 +1,8 @@
 package query
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -199,3 +201,58 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestSymbolsMentioned(t *testing.T) {
+	items := []ImpactItem{
+		{StableId: "sym:Charge", Name: "Charge", Location: &LocationInfo{FileId: "payments/charge.go"}},
+		{StableId: "sym:Refund", Name: "Refund", Location: &LocationInfo{FileId: "payments/refund.go"}},
+		{StableId: "sym:ChargeAll", Name: "ChargeAll", Location: &LocationInfo{FileId: "payments/batch.go"}},
+		{StableId: "sym:Do", Name: "Do", Location: &LocationInfo{FileId: "payments/do.go"}},
+	}
+
+	text := "Charge must stay idempotent. Do not call it from the batch path; see refund.go."
+	got := symbolsMentioned(text, items)
+	want := []string{"sym:Charge", "sym:Refund"}
+	if len(got) != len(want) {
+		t.Fatalf("symbolsMentioned() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("symbolsMentioned() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestLinkDecisionsToImpact(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	adrDir := filepath.Join(engine.repoRoot, "docs", "decisions")
+	if err := os.MkdirAll(adrDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	adr := "# ADR-012: Idempotent charges\n\n**Status:** accepted\n\n## Context\n\nRetries can double-bill.\n\n## Decision\n\nCharge takes an idempotency key.\n"
+	if err := os.WriteFile(filepath.Join(adrDir, "adr-012.md"), []byte(adr), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	related := []RelatedDecision{
+		{ID: "ADR-003", Title: "Module layout", FilePath: "docs/decisions/missing.md"},
+		{ID: "ADR-012", Title: "Idempotent charges", FilePath: "docs/decisions/adr-012.md"},
+	}
+	engine.linkDecisionsToImpact(related, []ImpactItem{
+		{StableId: "sym:Charge", Name: "Charge"},
+		{StableId: "sym:Refund", Name: "Refund"},
+	})
+
+	if related[0].ID != "ADR-012" || related[0].Scope != DecisionScopeSymbol {
+		t.Fatalf("expected symbol-scoped ADR-012 first, got %+v", related[0])
+	}
+	if len(related[0].AffectedSymbols) != 1 || related[0].AffectedSymbols[0] != "sym:Charge" {
+		t.Errorf("AffectedSymbols = %v, want [sym:Charge]", related[0].AffectedSymbols)
+	}
+	if related[1].Scope != DecisionScopeModule || len(related[1].AffectedSymbols) != 0 {
+		t.Errorf("decision without symbol mentions should be module-scoped, got %+v", related[1])
+	}
+}
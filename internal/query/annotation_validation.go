@@ -0,0 +1,324 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ckb/internal/backends/scip"
+	"ckb/internal/errors"
+	"ckb/internal/output"
+	"ckb/internal/storage"
+)
+
+// Annotation drift finding kinds.
+const (
+	DriftMissingPath       = "missing-path"       // A declared public/internal path no longer exists
+	DriftBoundaryViolation = "boundary-violation" // An internal path is referenced from outside its module
+	DriftUnannotated       = "unannotated"        // A detected module has no annotation
+)
+
+// maxBoundaryEvidence caps the referencing files listed per violation.
+const maxBoundaryEvidence = 5
+
+// ValidateAnnotationsOptions contains options for validateAnnotations.
+type ValidateAnnotationsOptions struct{}
+
+// ValidateAnnotationsResponse is the response for validateAnnotations.
+type ValidateAnnotationsResponse struct {
+	Findings         []AnnotationFinding `json:"findings"`
+	ModulesChecked   int                 `json:"modulesChecked"`
+	AnnotatedModules int                 `json:"annotatedModules"`
+	Summary          map[string]int      `json:"summary"` // Findings by kind
+	Limitations      []string            `json:"limitations,omitempty"`
+	Provenance       *Provenance         `json:"provenance"`
+}
+
+// AnnotationFinding is one way a module annotation has drifted from the code.
+type AnnotationFinding struct {
+	ModuleId string   `json:"moduleId"`
+	Kind     string   `json:"kind"`     // missing-path, boundary-violation, unannotated
+	Severity string   `json:"severity"` // error, warning, info
+	Path     string   `json:"path,omitempty"`
+	Message  string   `json:"message"`
+	Evidence []string `json:"evidence,omitempty"` // Referencing files, for boundary violations
+}
+
+// annotatedModule is a module with declared boundaries, as validated.
+type annotatedModule struct {
+	id       string
+	root     string // Repo-relative; "" for the repo root
+	public   []string
+	internal []string
+}
+
+// ValidateAnnotations checks module annotations against the current tree
+// and index: declared paths that no longer exist, internal paths referenced
+// from other modules, and detected modules nobody has annotated.
+func (e *Engine) ValidateAnnotations(ctx context.Context, opts ValidateAnnotationsOptions) (*ValidateAnnotationsResponse, error) {
+	startTime := time.Now()
+
+	if e.db == nil {
+		return nil, errors.NewCkbError(errors.InternalError, "validateAnnotations needs the CKB database", nil, nil, nil)
+	}
+
+	repoState, err := e.GetRepoState(ctx, "head")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	records, err := storage.NewModuleRepository(e.db).ListAll()
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	var annotated []annotatedModule
+	annotatedRoots := make(map[string]bool)
+	for _, rec := range records {
+		ann := e.getModuleAnnotations(rec.ModuleID)
+		if ann == nil {
+			continue
+		}
+		m := annotatedModule{id: rec.ModuleID, root: cleanModuleRoot(rec.RootPath)}
+		if ann.Boundaries != nil {
+			m.public = ann.Boundaries.Public
+			m.internal = ann.Boundaries.Internal
+		}
+		annotated = append(annotated, m)
+		annotatedRoots[m.root] = true
+		annotatedRoots[cleanModuleRoot(rec.ModuleID)] = true
+	}
+
+	var findings []AnnotationFinding
+	var limitations []string
+
+	var idx *scip.SCIPIndex
+	var scipPaths *scipPathIndex
+	if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
+		idx = e.scipAdapter.GetIndex()
+		scipPaths = e.scipPaths()
+	}
+	checkedBoundaries := false
+
+	for _, m := range annotated {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		for _, p := range m.public {
+			if _, ok := e.resolveAnnotationPath(m.root, p); !ok {
+				findings = append(findings, AnnotationFinding{
+					ModuleId: m.id,
+					Kind:     DriftMissingPath,
+					Severity: output.SeverityWarning,
+					Path:     p,
+					Message:  fmt.Sprintf("Declared public path %s no longer exists", p),
+				})
+			}
+		}
+		for _, p := range m.internal {
+			resolved, ok := e.resolveAnnotationPath(m.root, p)
+			if !ok {
+				findings = append(findings, AnnotationFinding{
+					ModuleId: m.id,
+					Kind:     DriftMissingPath,
+					Severity: output.SeverityWarning,
+					Path:     p,
+					Message:  fmt.Sprintf("Declared internal path %s no longer exists", p),
+				})
+				continue
+			}
+			if idx == nil {
+				continue
+			}
+			checkedBoundaries = true
+			if f := boundaryViolation(idx, scipPaths, m, p, resolved); f != nil {
+				findings = append(findings, *f)
+			}
+		}
+	}
+	if idx == nil && len(annotated) > 0 {
+		limitations = append(limitations, "SCIP index unavailable; internal paths were not checked for external references")
+	}
+
+	moduleRoots := e.ModuleRoots()
+	for _, root := range moduleRoots {
+		root = cleanModuleRoot(root)
+		if root == "" || annotatedRoots[root] {
+			continue
+		}
+		findings = append(findings, AnnotationFinding{
+			ModuleId: root,
+			Kind:     DriftUnannotated,
+			Severity: output.SeverityInfo,
+			Path:     root,
+			Message:  fmt.Sprintf("Module %s has no annotation", root),
+		})
+	}
+
+	sortAnnotationFindings(findings)
+	summary := make(map[string]int)
+	for _, f := range findings {
+		summary[f.Kind]++
+	}
+	if findings == nil {
+		findings = []AnnotationFinding{}
+	}
+
+	completeness := CompletenessInfo{Score: 1.0, Reason: "full-backend"}
+	if idx == nil {
+		completeness = CompletenessInfo{Score: 0.7, Reason: "scip-unavailable"}
+	}
+	provenance := e.buildProvenance(ctx, repoState, "head", startTime, []BackendContribution{
+		{BackendId: "scip", Available: idx != nil, Used: checkedBoundaries, Completeness: completeness.Score},
+	}, completeness)
+
+	return &ValidateAnnotationsResponse{
+		Findings:         findings,
+		ModulesChecked:   len(annotated) + summary[DriftUnannotated],
+		AnnotatedModules: len(annotated),
+		Summary:          summary,
+		Limitations:      limitations,
+		Provenance:       provenance,
+	}, nil
+}
+
+// resolveAnnotationPath finds a declared path in the tree, first as given
+// (repo-relative) and then relative to the module root. Glob patterns match
+// if anything matches them. Paths escaping the repository never resolve.
+func (e *Engine) resolveAnnotationPath(moduleRoot, declared string) (string, bool) {
+	p := strings.Trim(strings.TrimPrefix(filepath.ToSlash(declared), "./"), "/")
+	if p == "" {
+		return "", false
+	}
+	candidates := []string{path.Clean(p)}
+	if moduleRoot != "" && !strings.HasPrefix(p, moduleRoot+"/") && p != moduleRoot {
+		candidates = append(candidates, path.Join(moduleRoot, p))
+	}
+	for _, c := range candidates {
+		if c == ".." || strings.HasPrefix(c, "../") {
+			continue
+		}
+		abs := filepath.Join(e.repoRoot, filepath.FromSlash(c))
+		if strings.ContainsAny(c, "*?[") {
+			if matches, err := filepath.Glob(abs); err == nil && len(matches) > 0 {
+				return c, true
+			}
+			continue
+		}
+		if _, err := os.Stat(abs); err == nil {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// boundaryViolation reports references to symbols defined under an internal
+// path that come from files outside the module. Returns nil if there are none.
+func boundaryViolation(idx *scip.SCIPIndex, scipPaths *scipPathIndex, m annotatedModule, declared, internalPath string) *AnnotationFinding {
+	files := make(map[string]bool)
+	symbols := make(map[string]bool)
+	refCount := 0
+	for _, doc := range idx.Documents {
+		docPath := scipPaths.canonical(doc.RelativePath)
+		if !pathMatchesAnnotation(docPath, internalPath) {
+			continue
+		}
+		for _, sym := range doc.Symbols {
+			if strings.HasPrefix(sym.Symbol, "local ") {
+				continue
+			}
+			refs, err := idx.FindReferences(sym.Symbol, scip.ReferenceOptions{})
+			if err != nil {
+				continue
+			}
+			for _, ref := range refs {
+				if ref.Location == nil {
+					continue
+				}
+				refPath := scipPaths.canonical(ref.Location.FileId)
+				if withinModule(refPath, m.root) || pathMatchesAnnotation(refPath, internalPath) {
+					continue
+				}
+				refCount++
+				files[refPath] = true
+				name := sym.DisplayName
+				if name == "" {
+					name = sym.Symbol
+				}
+				symbols[name] = true
+			}
+		}
+	}
+	if refCount == 0 {
+		return nil
+	}
+
+	evidence := make([]string, 0, len(files))
+	for f := range files {
+		evidence = append(evidence, f)
+	}
+	sort.Strings(evidence)
+	if len(evidence) > maxBoundaryEvidence {
+		evidence = evidence[:maxBoundaryEvidence]
+	}
+	return &AnnotationFinding{
+		ModuleId: m.id,
+		Kind:     DriftBoundaryViolation,
+		Severity: output.SeverityError,
+		Path:     declared,
+		Message: fmt.Sprintf("Internal path %s has %d references to %d symbols from %d files outside the module",
+			declared, refCount, len(symbols), len(files)),
+		Evidence: evidence,
+	}
+}
+
+// pathMatchesAnnotation reports whether file is the declared path, inside
+// it, or matches it as a glob.
+func pathMatchesAnnotation(file, declared string) bool {
+	if strings.ContainsAny(declared, "*?[") {
+		if ok, _ := path.Match(declared, file); ok {
+			return true
+		}
+		ok, _ := path.Match(declared, path.Dir(file))
+		return ok
+	}
+	return file == declared || strings.HasPrefix(file, declared+"/")
+}
+
+// withinModule reports whether file is under the module root; everything is
+// within the repo-root module.
+func withinModule(file, root string) bool {
+	return root == "" || file == root || strings.HasPrefix(file, root+"/")
+}
+
+// cleanModuleRoot normalizes a module root to repo-relative form, with ""
+// for the repository root.
+func cleanModuleRoot(root string) string {
+	root = strings.Trim(strings.TrimPrefix(filepath.ToSlash(root), "./"), "/")
+	if root == "." {
+		return ""
+	}
+	return root
+}
+
+// sortAnnotationFindings orders by severity, then module, kind and path.
+func sortAnnotationFindings(findings []AnnotationFinding) {
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if sa, sb := output.GetWarningSeverity(a.Severity), output.GetWarningSeverity(b.Severity); sa != sb {
+			return sa < sb
+		}
+		if a.ModuleId != b.ModuleId {
+			return a.ModuleId < b.ModuleId
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Path < b.Path
+	})
+}
@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateAnnotationsMissingPaths(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if err := os.MkdirAll(filepath.Join(engine.repoRoot, "pkg", "client", "internal"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.AnnotateModule(&AnnotateModuleInput{
+		ModuleId:      "pkg/client",
+		PublicPaths:   []string{"pkg/client/api"},
+		InternalPaths: []string{"internal", "pkg/client/cache"},
+	}); err != nil {
+		t.Fatalf("AnnotateModule: %v", err)
+	}
+
+	resp, err := engine.ValidateAnnotations(context.Background(), ValidateAnnotationsOptions{})
+	if err != nil {
+		t.Fatalf("ValidateAnnotations: %v", err)
+	}
+
+	if resp.AnnotatedModules != 1 {
+		t.Errorf("AnnotatedModules = %d, want 1", resp.AnnotatedModules)
+	}
+	var missing []string
+	for _, f := range resp.Findings {
+		if f.Kind == DriftMissingPath {
+			missing = append(missing, f.Path)
+		}
+	}
+	// "internal" resolves relative to the module root
+	if len(missing) != 2 || missing[0] != "pkg/client/api" || missing[1] != "pkg/client/cache" {
+		t.Errorf("missing paths = %v, want [pkg/client/api pkg/client/cache]", missing)
+	}
+	if resp.Summary[DriftMissingPath] != 2 {
+		t.Errorf("summary = %v", resp.Summary)
+	}
+	if len(resp.Limitations) == 0 {
+		t.Error("expected a limitation without a SCIP index")
+	}
+}
+
+func TestPathMatchesAnnotation(t *testing.T) {
+	tests := []struct {
+		file, declared string
+		want           bool
+	}{
+		{"pkg/client/internal/pool.go", "pkg/client/internal", true},
+		{"pkg/client/internal", "pkg/client/internal", true},
+		{"pkg/client/internalx/pool.go", "pkg/client/internal", false},
+		{"pkg/client/cache.go", "pkg/client/*.go", true},
+		{"pkg/client/x/cache.go", "pkg/client/*", true},
+		{"pkg/server/cache.go", "pkg/client/*", false},
+	}
+	for _, tt := range tests {
+		if got := pathMatchesAnnotation(tt.file, tt.declared); got != tt.want {
+			t.Errorf("pathMatchesAnnotation(%q, %q) = %v, want %v", tt.file, tt.declared, got, tt.want)
+		}
+	}
+}
+
+func TestSortAnnotationFindings(t *testing.T) {
+	findings := []AnnotationFinding{
+		{ModuleId: "b", Kind: DriftUnannotated, Severity: "info"},
+		{ModuleId: "b", Kind: DriftMissingPath, Severity: "warning", Path: "b/x"},
+		{ModuleId: "a", Kind: DriftBoundaryViolation, Severity: "error"},
+		{ModuleId: "a", Kind: DriftMissingPath, Severity: "warning", Path: "a/y"},
+	}
+	sortAnnotationFindings(findings)
+
+	want := []string{"a/" + DriftBoundaryViolation, "a/" + DriftMissingPath, "b/" + DriftMissingPath, "b/" + DriftUnannotated}
+	for i, w := range want {
+		if got := findings[i].ModuleId + "/" + findings[i].Kind; got != w {
+			t.Errorf("position %d: got %s, want %s", i, got, w)
+		}
+	}
+}
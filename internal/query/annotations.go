@@ -15,6 +15,7 @@ type AnnotateModuleInput struct {
 	Tags           []string `json:"tags,omitempty"`
 	PublicPaths    []string `json:"publicPaths,omitempty"`
 	InternalPaths  []string `json:"internalPaths,omitempty"`
+	APISymbols     []string `json:"apiSymbols,omitempty"` // Curated public API: symbol names or glob patterns
 }
 
 // AnnotateModuleResult represents the result of annotating a module
@@ -32,6 +33,7 @@ type AnnotateModuleResult struct {
 type Boundaries struct {
 	Public   []string `json:"public,omitempty"`
 	Internal []string `json:"internal,omitempty"`
+	API      []string `json:"api,omitempty"`
 }
 
 // AnnotateModule adds or updates module metadata
@@ -96,11 +98,15 @@ func (e *Engine) AnnotateModule(input *AnnotateModuleInput) (*AnnotateModuleResu
 
 	// Also update the modules table v2 columns (boundaries, responsibility, tags, owner_ref)
 	var boundariesJSON *string
-	if len(input.PublicPaths) > 0 || len(input.InternalPaths) > 0 {
+	hasBoundaries := len(input.PublicPaths) > 0 || len(input.InternalPaths) > 0 || len(input.APISymbols) > 0
+	if hasBoundaries {
 		boundaries := map[string][]string{
 			"public":   input.PublicPaths,
 			"internal": input.InternalPaths,
 		}
+		if len(input.APISymbols) > 0 {
+			boundaries["api"] = input.APISymbols
+		}
 		if bytes, err := json.Marshal(boundaries); err == nil {
 			s := string(bytes)
 			boundariesJSON = &s
@@ -137,6 +143,7 @@ func (e *Engine) AnnotateModule(input *AnnotateModuleInput) (*AnnotateModuleResu
 			"error":    err.Error(),
 		})
 	}
+	e.invalidateDeclaredAPI()
 
 	// Build result
 	result := &AnnotateModuleResult{
@@ -148,10 +155,11 @@ func (e *Engine) AnnotateModule(input *AnnotateModuleInput) (*AnnotateModuleResu
 		Created:        created,
 	}
 
-	if len(input.PublicPaths) > 0 || len(input.InternalPaths) > 0 {
+	if hasBoundaries {
 		result.Boundaries = &Boundaries{
 			Public:   input.PublicPaths,
 			Internal: input.InternalPaths,
+			API:      input.APISymbols,
 		}
 	}
 
@@ -0,0 +1,148 @@
+package query
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"ckb/internal/storage"
+)
+
+// declaredAPI is the curated public API declared with the apiSymbols module
+// annotation. Modules without a declaration fall back to visibility.
+type declaredAPI struct {
+	modules []declaredAPIModule // longest root first
+}
+
+type declaredAPIModule struct {
+	root     string
+	patterns []string
+}
+
+// loadDeclaredAPI returns the apiSymbols declarations from module
+// annotations, reading them once and again after invalidateDeclaredAPI.
+// Returns nil when no module declares a curated API.
+func (e *Engine) loadDeclaredAPI() *declaredAPI {
+	if e.db == nil {
+		return nil
+	}
+
+	e.declaredAPIMu.Lock()
+	defer e.declaredAPIMu.Unlock()
+	if e.declaredAPILoaded {
+		return e.declaredAPI
+	}
+
+	mods, err := storage.NewModuleRepository(e.db).ListWithBoundaries()
+	if err != nil {
+		e.logger.Debug("Failed to load declared API", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil // Not cached; the next call retries
+	}
+	e.declaredAPI = parseDeclaredAPI(mods)
+	e.declaredAPILoaded = true
+	return e.declaredAPI
+}
+
+// invalidateDeclaredAPI drops the cached declarations after annotations
+// change.
+func (e *Engine) invalidateDeclaredAPI() {
+	e.declaredAPIMu.Lock()
+	e.declaredAPI = nil
+	e.declaredAPILoaded = false
+	e.declaredAPIMu.Unlock()
+}
+
+// parseDeclaredAPI builds the declarations from modules with boundaries.
+func parseDeclaredAPI(mods []*storage.Module) *declaredAPI {
+
+	api := &declaredAPI{}
+	for _, m := range mods {
+		var b Boundaries
+		if m.Boundaries == nil || json.Unmarshal([]byte(*m.Boundaries), &b) != nil || len(b.API) == 0 {
+			continue
+		}
+		root := strings.Trim(strings.TrimPrefix(m.RootPath, "./"), "/")
+		if root == "." {
+			root = ""
+		}
+		api.modules = append(api.modules, declaredAPIModule{root: root, patterns: b.API})
+	}
+	if len(api.modules) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(api.modules, func(i, j int) bool {
+		return len(api.modules[i].root) > len(api.modules[j].root)
+	})
+	return api
+}
+
+// patternsFor returns the API patterns of the innermost module containing
+// filePath that declares any.
+func (d *declaredAPI) patternsFor(filePath string) ([]string, bool) {
+	if d == nil {
+		return nil, false
+	}
+	for _, m := range d.modules {
+		if m.root == "" || filePath == m.root || strings.HasPrefix(filePath, m.root+"/") {
+			return m.patterns, true
+		}
+	}
+	return nil, false
+}
+
+// isPublicAPI reports whether a symbol is part of the public API. When its
+// module declares apiSymbols only matching symbols qualify; otherwise the
+// visibility-based answer is returned. declared reports which rule applied.
+func (d *declaredAPI) isPublicAPI(filePath, name, container string, visible bool) (public bool, declared bool) {
+	patterns, ok := d.patternsFor(filePath)
+	if !ok {
+		return visible, false
+	}
+	return matchesAPIPattern(patterns, name, container), true
+}
+
+// matchesAPIPattern matches a symbol against name globs. A pattern matches
+// either the bare name or the container-qualified name, so "Client" and
+// "Client.*" both work.
+func matchesAPIPattern(patterns []string, name, container string) bool {
+	qualified := ""
+	if container != "" {
+		qualified = container + "." + name
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+		if qualified != "" {
+			if ok, _ := path.Match(p, qualified); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyDeclaredAPI overrides a symbol's visibility with its module's curated
+// API declaration, if there is one.
+func (e *Engine) applyDeclaredAPI(sym *SymbolInfo) {
+	if sym == nil || sym.Location == nil {
+		return
+	}
+	public, declared := e.loadDeclaredAPI().isPublicAPI(sym.Location.FileId, sym.Name, sym.ContainerName, false)
+	if !declared {
+		return
+	}
+	visibility := "internal"
+	if public {
+		visibility = "public"
+	}
+	sym.Visibility = &VisibilityInfo{
+		Visibility: visibility,
+		Confidence: 1.0,
+		Source:     "declared-api",
+	}
+}
@@ -0,0 +1,89 @@
+package query
+
+import "testing"
+
+func TestMatchesAPIPattern(t *testing.T) {
+	tests := []struct {
+		name      string
+		patterns  []string
+		symbol    string
+		container string
+		want      bool
+	}{
+		{"exact name", []string{"Client"}, "Client", "", true},
+		{"glob on name", []string{"New*"}, "NewClient", "", true},
+		{"qualified glob", []string{"Client.*"}, "Do", "Client", true},
+		{"qualified glob other type", []string{"Client.*"}, "Do", "Server", false},
+		{"not listed", []string{"Client", "New*"}, "Helper", "", false},
+		{"malformed pattern ignored", []string{"["}, "Client", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAPIPattern(tt.patterns, tt.symbol, tt.container); got != tt.want {
+				t.Errorf("matchesAPIPattern(%v, %q, %q) = %v, want %v", tt.patterns, tt.symbol, tt.container, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeclaredAPIFromAnnotations(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if api := engine.loadDeclaredAPI(); api != nil {
+		t.Fatalf("expected no declared API before annotating, got %+v", api)
+	}
+
+	if _, err := engine.AnnotateModule(&AnnotateModuleInput{
+		ModuleId:   "pkg/client",
+		APISymbols: []string{"Client", "Client.*", "New*"},
+	}); err != nil {
+		t.Fatalf("AnnotateModule: %v", err)
+	}
+	if _, err := engine.AnnotateModule(&AnnotateModuleInput{
+		ModuleId:    "pkg/server",
+		PublicPaths: []string{"pkg/server/api"},
+	}); err != nil {
+		t.Fatalf("AnnotateModule: %v", err)
+	}
+
+	api := engine.loadDeclaredAPI()
+	if again := engine.loadDeclaredAPI(); again != api {
+		t.Error("expected declarations to be cached until the next annotation")
+	}
+	tests := []struct {
+		path, name, container string
+		visible               bool
+		wantPublic            bool
+		wantDeclared          bool
+	}{
+		{"pkg/client/client.go", "NewClient", "", true, true, true},
+		{"pkg/client/client.go", "Do", "Client", true, true, true},
+		{"pkg/client/pool.go", "Pool", "", true, false, true},
+		{"pkg/server/server.go", "Serve", "", true, true, false},
+		{"pkg/clientutil/util.go", "Helper", "", false, false, false},
+	}
+	for _, tt := range tests {
+		public, declared := api.isPublicAPI(tt.path, tt.name, tt.container, tt.visible)
+		if public != tt.wantPublic || declared != tt.wantDeclared {
+			t.Errorf("isPublicAPI(%q, %q) = (%v, %v), want (%v, %v)",
+				tt.path, tt.name, public, declared, tt.wantPublic, tt.wantDeclared)
+		}
+	}
+
+	annotations := engine.getModuleAnnotations("pkg/client")
+	if annotations == nil || annotations.Boundaries == nil || len(annotations.Boundaries.API) != 3 {
+		t.Errorf("expected apiSymbols in module annotations, got %+v", annotations)
+	}
+
+	sym := &SymbolInfo{
+		Name:       "Pool",
+		Visibility: &VisibilityInfo{Visibility: "public", Confidence: 0.9, Source: "scip"},
+		Location:   &LocationInfo{FileId: "pkg/client/pool.go"},
+	}
+	engine.applyDeclaredAPI(sym)
+	if sym.Visibility.Visibility != "internal" || sym.Visibility.Source != "declared-api" {
+		t.Errorf("exported symbol outside curated API should be internal, got %+v", sym.Visibility)
+	}
+}
@@ -19,42 +19,50 @@ type GetArchitectureOptions struct {
 	Depth               int
 	IncludeExternalDeps bool
 	Refresh             bool
+	Format              string // "json" (default) or "mermaid" to also fill Mermaid
+	MinSymbols          int    // Fold modules with fewer symbols into a misc module per top-level directory (0 = keep all)
 }
 
 // GetArchitectureResponse is the response for getArchitecture.
 type GetArchitectureResponse struct {
-	Modules         []ModuleSummary       `json:"modules"`
-	DependencyGraph []DependencyEdge      `json:"dependencyGraph"`
-	Entrypoints     []Entrypoint          `json:"entrypoints"`
-	Truncated       bool                  `json:"truncated,omitempty"`
-	TruncationInfo  *TruncationInfo       `json:"truncationInfo,omitempty"`
-	Provenance      *Provenance           `json:"provenance"`
-	Drilldowns      []output.Drilldown    `json:"drilldowns,omitempty"`
-	Confidence      float64               `json:"confidence"`
-	ConfidenceBasis []ConfidenceBasisItem `json:"confidenceBasis"`
-	Limitations     []string              `json:"limitations,omitempty"`
+	Modules          []ModuleSummary       `json:"modules"`
+	DependencyGraph  []DependencyEdge      `json:"dependencyGraph"`
+	Entrypoints      []Entrypoint          `json:"entrypoints"`
+	Truncated        bool                  `json:"truncated,omitempty"`
+	TruncationInfo   *TruncationInfo       `json:"truncationInfo,omitempty"`
+	Provenance       *Provenance           `json:"provenance"`
+	Drilldowns       []output.Drilldown    `json:"drilldowns,omitempty"`
+	Confidence       float64               `json:"confidence"`
+	ConfidenceBasis  []ConfidenceBasisItem `json:"confidenceBasis"`
+	Limitations      []string              `json:"limitations,omitempty"`
+	Cycles           [][]string            `json:"cycles,omitempty"`           // Module IDs in each dependency cycle, largest first
+	CycleCount       int                   `json:"cycleCount,omitempty"`       // Number of dependency cycles
+	Mermaid          string                `json:"mermaid,omitempty"`          // With Format "mermaid": graph LR block of modules and edges
+	CollapsedModules int                   `json:"collapsedModules,omitempty"` // Modules folded into misc modules by MinSymbols
 }
 
 // ModuleSummary describes a module in the architecture.
 type ModuleSummary struct {
-	ModuleId      string `json:"moduleId"`
-	Name          string `json:"name"`
-	Path          string `json:"path"`
-	Language      string `json:"language,omitempty"`
-	SymbolCount   int    `json:"symbolCount"`
-	FileCount     int    `json:"fileCount"`
-	ExportedCount int    `json:"exportedCount,omitempty"`
-	IncomingEdges int    `json:"incomingEdges"`
-	OutgoingEdges int    `json:"outgoingEdges"`
-	IsEntrypoint  bool   `json:"isEntrypoint,omitempty"`
+	ModuleId       string `json:"moduleId"`
+	Name           string `json:"name"`
+	Path           string `json:"path"`
+	Language       string `json:"language,omitempty"`
+	SymbolCount    int    `json:"symbolCount"`
+	FileCount      int    `json:"fileCount"`
+	ExportedCount  int    `json:"exportedCount,omitempty"`
+	IncomingEdges  int    `json:"incomingEdges"`
+	OutgoingEdges  int    `json:"outgoingEdges"`
+	IsEntrypoint   bool   `json:"isEntrypoint,omitempty"`
+	CollapsedCount int    `json:"collapsedCount,omitempty"` // For misc modules: how many modules were folded in
 }
 
 // DependencyEdge represents a dependency between modules.
 type DependencyEdge struct {
-	From     string `json:"from"`
-	To       string `json:"to"`
-	Kind     string `json:"kind"` // local-file, local-module, workspace-package, external-dependency, stdlib
-	Strength int    `json:"strength"`
+	From          string         `json:"from"`
+	To            string         `json:"to"`
+	Kind          string         `json:"kind"` // local-file, local-module, workspace-package, external-dependency, stdlib, or mixed
+	Strength      int            `json:"strength"`
+	KindStrengths map[string]int `json:"kindStrengths,omitempty"` // For mixed edges: strength per original kind
 }
 
 // Entrypoint represents an entry point in the codebase.
@@ -79,6 +87,14 @@ func (e *Engine) GetArchitecture(ctx context.Context, opts GetArchitectureOption
 	if opts.Depth <= 0 {
 		opts.Depth = 2
 	}
+	if opts.MinSymbols < 0 {
+		opts.MinSymbols = 0
+	}
+	switch opts.Format {
+	case "", ArchitectureFormatJSON, ArchitectureFormatMermaid:
+	default:
+		return nil, fmt.Errorf("invalid format %q: must be %q or %q", opts.Format, ArchitectureFormatJSON, ArchitectureFormatMermaid)
+	}
 
 	var confidenceBasis []ConfidenceBasisItem
 	var limitations []string
@@ -89,11 +105,7 @@ func (e *Engine) GetArchitecture(ctx context.Context, opts GetArchitectureOption
 		return nil, e.wrapError(err, errors.InternalError)
 	}
 
-	// Create import scanner for the architecture generator
-	importScanner := modules.NewImportScanner(&e.config.ImportScan, e.logger)
-
-	// Create architecture generator
-	generator := architecture.NewArchitectureGenerator(e.repoRoot, e.config, importScanner, e.logger)
+	generator := e.architectureGenerator()
 
 	// Build generator options
 	genOpts := &architecture.GeneratorOptions{
@@ -103,7 +115,7 @@ func (e *Engine) GetArchitecture(ctx context.Context, opts GetArchitectureOption
 	}
 
 	// Generate architecture
-	arch, err := generator.Generate(ctx, repoState.RepoStateId, genOpts)
+	arch, err := generator.Generate(ctx, e.architectureCacheKey(repoState), genOpts)
 	if err != nil {
 		return nil, e.wrapError(err, errors.InternalError)
 	}
@@ -119,7 +131,8 @@ func (e *Engine) GetArchitecture(ctx context.Context, opts GetArchitectureOption
 	entrypoints := convertArchEntrypoints(arch.Entrypoints)
 
 	// Enrich module summaries with symbol counts from SCIP
-	if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
+	hasSymbolCounts := e.scipAdapter != nil && e.scipAdapter.IsAvailable()
+	if hasSymbolCounts {
 		for i := range moduleSummaries {
 			// Count symbols for this module's path prefix
 			symbolCount := e.scipAdapter.CountSymbolsByPath(moduleSummaries[i].Path)
@@ -127,6 +140,19 @@ func (e *Engine) GetArchitecture(ctx context.Context, opts GetArchitectureOption
 		}
 	}
 
+	// Fold small modules; without symbol counts every module would qualify
+	collapsedModules := 0
+	if opts.MinSymbols > 0 {
+		if hasSymbolCounts {
+			moduleSummaries, edges, entrypoints, collapsedModules = collapseSmallModules(moduleSummaries, edges, entrypoints, opts.MinSymbols)
+		} else {
+			limitations = append(limitations, "minSymbols ignored: module symbol counts need a SCIP index")
+		}
+	}
+
+	// One edge per module pair, so edge counts and caps see real dependencies
+	edges = mergeParallelEdges(edges)
+
 	// Compute edge counts for modules
 	computeEdgeCounts(moduleSummaries, edges)
 
@@ -162,14 +188,25 @@ func (e *Engine) GetArchitecture(ctx context.Context, opts GetArchitectureOption
 		return edges[i].To < edges[j].To
 	})
 
+	// Find cycles before the caps so they can't hide one
+	cycles := findDependencyCycles(edges)
+	moduleByID := make(map[string]ModuleSummary, len(moduleSummaries))
+	for _, m := range moduleSummaries {
+		moduleByID[m.ModuleId] = m
+	}
+
 	// v5.2: Apply edge cap
 	var truncationInfo *TruncationInfo
 	if len(edges) > maxEdges {
 		limitations = append(limitations, "Edge count exceeded; showing top 50 by strength")
+		if len(cycles) > 0 {
+			limitations = append(limitations, "Cycles were detected on the full dependency graph; some of their edges fall outside the top 50 shown")
+		}
 		edges = edges[:maxEdges]
 	}
 
 	// v5.2: Apply module cap
+	originalModuleCount := len(moduleSummaries)
 	if len(moduleSummaries) > maxModules {
 		truncationInfo = &TruncationInfo{
 			Reason:        "max-modules",
@@ -198,7 +235,7 @@ func (e *Engine) GetArchitecture(ctx context.Context, opts GetArchitectureOption
 	}
 
 	// Build provenance
-	provenance := e.buildProvenance(repoState, "full", startTime, nil, completeness)
+	provenance := e.buildProvenance(ctx, repoState, "full", startTime, nil, completeness)
 
 	// Generate drilldowns
 	var compTrunc *compression.TruncationInfo
@@ -219,21 +256,64 @@ func (e *Engine) GetArchitecture(ctx context.Context, opts GetArchitectureOption
 	}
 
 	drilldowns := e.generateDrilldowns(compTrunc, completeness, "", topModule)
+	if len(cycles) > 0 {
+		drilldowns = append(drilldowns, cycleDrilldown(cycles[0], moduleByID))
+	}
+
+	var mermaid string
+	if opts.Format == ArchitectureFormatMermaid {
+		var omissions []string
+		if collapsedModules > 0 {
+			omissions = append(omissions, fmt.Sprintf("collapsed: %d modules with fewer than %d symbols folded into misc modules", collapsedModules, opts.MinSymbols))
+		}
+		if originalModuleCount > len(moduleSummaries) {
+			omissions = append(omissions, fmt.Sprintf("truncated: showing %d of %d modules", len(moduleSummaries), originalModuleCount))
+		}
+		if originalEdgeCount > len(edges) {
+			omissions = append(omissions, fmt.Sprintf("truncated: showing %d of %d dependency edges", len(edges), originalEdgeCount))
+		}
+		mermaid = architectureMermaid(moduleSummaries, edges, moduleByID, omissions)
+	}
 
 	return &GetArchitectureResponse{
-		Modules:         moduleSummaries,
-		DependencyGraph: edges,
-		Entrypoints:     entrypoints,
-		Truncated:       truncationInfo != nil,
-		TruncationInfo:  truncationInfo,
-		Provenance:      provenance,
-		Drilldowns:      drilldowns,
-		Confidence:      confidence,
-		ConfidenceBasis: confidenceBasis,
-		Limitations:     limitations,
+		Modules:          moduleSummaries,
+		DependencyGraph:  edges,
+		Entrypoints:      entrypoints,
+		Truncated:        truncationInfo != nil,
+		TruncationInfo:   truncationInfo,
+		Provenance:       provenance,
+		Drilldowns:       drilldowns,
+		Confidence:       confidence,
+		ConfidenceBasis:  confidenceBasis,
+		Limitations:      limitations,
+		Cycles:           cycles,
+		CycleCount:       len(cycles),
+		Mermaid:          mermaid,
+		CollapsedModules: collapsedModules,
 	}, nil
 }
 
+// cycleDrilldown suggests a module overview of the first module in a cycle.
+func cycleDrilldown(cycle []string, modules map[string]ModuleSummary) output.Drilldown {
+	first := cycle[0]
+	params := map[string]interface{}{"name": first}
+	if m, ok := modules[first]; ok {
+		if m.Name != "" {
+			params["name"] = m.Name
+		}
+		if m.Path != "" {
+			params["path"] = m.Path
+		}
+	}
+	return output.Drilldown{
+		Label:          fmt.Sprintf("Inspect %s, in a cycle of %d modules", params["name"], len(cycle)),
+		Query:          fmt.Sprintf("getModuleOverview %s", first),
+		Tool:           "getModuleOverview",
+		Params:         params,
+		RelevanceScore: 0.85,
+	}
+}
+
 // convertModuleSummaries converts architecture module summaries to response format.
 func convertModuleSummaries(archModules []architecture.ModuleSummary) []ModuleSummary {
 	result := make([]ModuleSummary, 0, len(archModules))
@@ -400,6 +480,7 @@ func (e *Engine) RefreshArchitecture(ctx context.Context, opts RefreshArchitectu
 				RepoStateId:     repoState.RepoStateId,
 				RepoStateDirty:  repoState.Dirty,
 				QueryDurationMs: time.Since(startTime).Milliseconds(),
+				Extra:           e.provenanceExtra(ctx),
 			},
 		}, nil
 	}
@@ -407,14 +488,13 @@ func (e *Engine) RefreshArchitecture(ctx context.Context, opts RefreshArchitectu
 	// Refresh modules if requested
 	if opts.Scope == "all" || opts.Scope == "modules" {
 		// Re-detect modules
-		importScanner := modules.NewImportScanner(&e.config.ImportScan, e.logger)
-		generator := architecture.NewArchitectureGenerator(e.repoRoot, e.config, importScanner, e.logger)
+		generator := e.architectureGenerator()
 
 		genOpts := &architecture.GeneratorOptions{
 			Refresh: true,
 		}
 
-		_, genErr := generator.Generate(ctx, repoState.RepoStateId, genOpts)
+		_, genErr := generator.Generate(ctx, e.architectureCacheKey(repoState), genOpts)
 		if genErr != nil {
 			warnings = append(warnings, "Module refresh had errors: "+genErr.Error())
 		} else {
@@ -458,6 +538,7 @@ func (e *Engine) RefreshArchitecture(ctx context.Context, opts RefreshArchitectu
 			RepoStateId:     repoState.RepoStateId,
 			RepoStateDirty:  repoState.Dirty,
 			QueryDurationMs: durationMs,
+			Extra:           e.provenanceExtra(ctx),
 		},
 	}, nil
 }
@@ -497,3 +578,23 @@ func (e *Engine) queueRefreshJob(opts RefreshArchitectureOptions) (*RefreshArchi
 		Warnings:      []string{"Job queued for async processing"},
 	}, nil
 }
+
+// architectureGenerator returns a generator backed by the engine's shared
+// architecture cache.
+func (e *Engine) architectureGenerator() *architecture.ArchitectureGenerator {
+	importScanner := modules.NewImportScanner(&e.config.ImportScan, e.logger)
+	return architecture.NewArchitectureGenerator(e.repoRoot, e.config, importScanner, e.logger).
+		WithCache(e.archCache)
+}
+
+// architectureCacheKey keys cached architecture views by repo state, since
+// modules, imports and entrypoints are read from the working tree, and by
+// the commit the SCIP index was built at, so a reindex also rebuilds them.
+func (e *Engine) architectureCacheKey(repoState *RepoState) string {
+	if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
+		if info := e.scipAdapter.GetIndexInfo(); info != nil && info.IndexedCommit != "" {
+			return repoState.RepoStateId + "@index:" + info.IndexedCommit
+		}
+	}
+	return repoState.RepoStateId
+}
@@ -0,0 +1,150 @@
+package query
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// miscModulePrefix starts the IDs of the synthetic modules that small
+// modules are collapsed into.
+const miscModulePrefix = "misc:"
+
+// collapseSmallModules folds every module with fewer than minSymbols symbols
+// into a synthetic "misc" module for its top-level directory. Edges to and
+// from folded modules are rewritten to the misc module; edges that end up
+// with the same endpoints and kind are merged and their strengths summed,
+// and edges between modules folded into the same misc module are dropped.
+// Entrypoints are moved to the misc module as well.
+//
+// It returns the new modules, edges and entrypoints and the number of
+// modules folded; with minSymbols <= 0 its input is returned unchanged.
+func collapseSmallModules(modules []ModuleSummary, edges []DependencyEdge, entrypoints []Entrypoint, minSymbols int) ([]ModuleSummary, []DependencyEdge, []Entrypoint, int) {
+	if minSymbols <= 0 {
+		return modules, edges, entrypoints, 0
+	}
+
+	kept := make([]ModuleSummary, 0, len(modules))
+	miscByID := make(map[string]*ModuleSummary)
+	var miscIDs []string
+	target := make(map[string]string) // Folded module ID -> misc module ID
+	for _, m := range modules {
+		if m.SymbolCount >= minSymbols {
+			kept = append(kept, m)
+			continue
+		}
+		top := topLevelDir(m.Path)
+		id := miscModulePrefix + top
+		misc, ok := miscByID[id]
+		if !ok {
+			misc = &ModuleSummary{ModuleId: id, Name: path.Join(top, "misc"), Language: m.Language}
+			if top != "." {
+				misc.Path = top
+			}
+			miscByID[id] = misc
+			miscIDs = append(miscIDs, id)
+		}
+		if misc.Language != m.Language {
+			misc.Language = ""
+		}
+		misc.SymbolCount += m.SymbolCount
+		misc.FileCount += m.FileCount
+		misc.ExportedCount += m.ExportedCount
+		misc.IsEntrypoint = misc.IsEntrypoint || m.IsEntrypoint
+		misc.CollapsedCount++
+		target[m.ModuleId] = id
+	}
+	if len(target) == 0 {
+		return modules, edges, entrypoints, 0
+	}
+
+	sort.Strings(miscIDs)
+	for _, id := range miscIDs {
+		kept = append(kept, *miscByID[id])
+	}
+
+	type edgeKey struct{ from, to, kind string }
+	merged := make(map[edgeKey]int)
+	var order []edgeKey
+	for _, edge := range edges {
+		from, to := edge.From, edge.To
+		if id, ok := target[from]; ok {
+			from = id
+		}
+		if id, ok := target[to]; ok {
+			to = id
+		}
+		if from == to && from != edge.From {
+			continue // Internal to a misc module
+		}
+		key := edgeKey{from, to, edge.Kind}
+		if _, ok := merged[key]; !ok {
+			order = append(order, key)
+		}
+		merged[key] += edge.Strength
+	}
+	collapsedEdges := make([]DependencyEdge, 0, len(order))
+	for _, key := range order {
+		collapsedEdges = append(collapsedEdges, DependencyEdge{From: key.from, To: key.to, Kind: key.kind, Strength: merged[key]})
+	}
+
+	collapsedEntrypoints := make([]Entrypoint, len(entrypoints))
+	for i, ep := range entrypoints {
+		if id, ok := target[ep.ModuleId]; ok {
+			ep.ModuleId = id
+		}
+		collapsedEntrypoints[i] = ep
+	}
+
+	return kept, collapsedEdges, collapsedEntrypoints, len(target)
+}
+
+// topLevelDir returns the first segment of a repo-relative path, or "." for
+// the repo root.
+func topLevelDir(p string) string {
+	p = strings.Trim(path.Clean(strings.ReplaceAll(p, "\\", "/")), "/")
+	if p == "" || p == "." {
+		return "."
+	}
+	if i := strings.Index(p, "/"); i >= 0 {
+		return p[:i]
+	}
+	return p
+}
+
+// DependencyEdgeKindMixed is the kind of an edge merged from parallel edges
+// of different kinds.
+const DependencyEdgeKindMixed = "mixed"
+
+// mergeParallelEdges merges edges with the same endpoints into one whose
+// strength is their sum. The merged edge keeps the kind when all agree;
+// otherwise its kind is DependencyEdgeKindMixed and KindStrengths holds the
+// strength per original kind. Edges stay in order of first appearance.
+func mergeParallelEdges(edges []DependencyEdge) []DependencyEdge {
+	type pairKey struct{ from, to string }
+	index := make(map[pairKey]int, len(edges))
+	breakdown := make(map[pairKey]map[string]int)
+	merged := make([]DependencyEdge, 0, len(edges))
+	for _, edge := range edges {
+		key := pairKey{edge.From, edge.To}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, edge)
+			breakdown[key] = map[string]int{edge.Kind: edge.Strength}
+			continue
+		}
+		merged[i].Strength += edge.Strength
+		breakdown[key][edge.Kind] += edge.Strength
+		if merged[i].Kind != edge.Kind {
+			merged[i].Kind = DependencyEdgeKindMixed
+		}
+	}
+
+	for i := range merged {
+		if merged[i].Kind == DependencyEdgeKindMixed {
+			merged[i].KindStrengths = breakdown[pairKey{merged[i].From, merged[i].To}]
+		}
+	}
+	return merged
+}
@@ -0,0 +1,115 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollapseSmallModules(t *testing.T) {
+	modules := []ModuleSummary{
+		{ModuleId: "mod:query", Name: "query", Path: "internal/query", Language: "go", SymbolCount: 120, FileCount: 30},
+		{ModuleId: "mod:tiny", Name: "tiny", Path: "internal/tiny", Language: "go", SymbolCount: 2, FileCount: 1},
+		{ModuleId: "mod:small", Name: "small", Path: "internal/small", Language: "go", SymbolCount: 4, FileCount: 2},
+		{ModuleId: "mod:tool", Name: "tool", Path: "cmd/tool", Language: "go", SymbolCount: 3, FileCount: 1},
+		{ModuleId: "mod:root", Name: "root", Path: "", Language: "go", SymbolCount: 1, FileCount: 1},
+	}
+	edges := []DependencyEdge{
+		{From: "mod:tiny", To: "mod:query", Kind: "local-module", Strength: 2},
+		{From: "mod:small", To: "mod:query", Kind: "local-module", Strength: 3},
+		{From: "mod:small", To: "mod:query", Kind: "local-file", Strength: 1},
+		{From: "mod:tiny", To: "mod:small", Kind: "local-module", Strength: 5},
+		{From: "mod:tool", To: "mod:tiny", Kind: "local-module", Strength: 1},
+		{From: "mod:query", To: "mod:query", Kind: "local-file", Strength: 4},
+	}
+	entrypoints := []Entrypoint{{ModuleId: "mod:tool", FileId: "cmd/tool/main.go", Kind: "main"}}
+
+	t.Run("zero keeps full detail", func(t *testing.T) {
+		gotModules, gotEdges, gotEntrypoints, collapsed := collapseSmallModules(modules, edges, entrypoints, 0)
+		if collapsed != 0 || len(gotModules) != len(modules) || len(gotEdges) != len(edges) || len(gotEntrypoints) != 1 {
+			t.Errorf("expected input unchanged, got %d modules, %d edges, %d collapsed", len(gotModules), len(gotEdges), collapsed)
+		}
+	})
+
+	gotModules, gotEdges, gotEntrypoints, collapsed := collapseSmallModules(modules, edges, entrypoints, 5)
+	if collapsed != 4 {
+		t.Errorf("collapsed = %d, want 4", collapsed)
+	}
+
+	wantModules := []ModuleSummary{
+		{ModuleId: "mod:query", Name: "query", Path: "internal/query", Language: "go", SymbolCount: 120, FileCount: 30},
+		{ModuleId: "misc:.", Name: "misc", Language: "go", SymbolCount: 1, FileCount: 1, CollapsedCount: 1},
+		{ModuleId: "misc:cmd", Name: "cmd/misc", Path: "cmd", Language: "go", SymbolCount: 3, FileCount: 1, CollapsedCount: 1},
+		{ModuleId: "misc:internal", Name: "internal/misc", Path: "internal", Language: "go", SymbolCount: 6, FileCount: 3, CollapsedCount: 2},
+	}
+	if !reflect.DeepEqual(gotModules, wantModules) {
+		t.Errorf("modules =\n%+v\nwant\n%+v", gotModules, wantModules)
+	}
+
+	// Kinds stay separate, strengths add up, and the edge inside
+	// misc:internal disappears
+	wantEdges := []DependencyEdge{
+		{From: "misc:internal", To: "mod:query", Kind: "local-module", Strength: 5},
+		{From: "misc:internal", To: "mod:query", Kind: "local-file", Strength: 1},
+		{From: "misc:cmd", To: "misc:internal", Kind: "local-module", Strength: 1},
+		{From: "mod:query", To: "mod:query", Kind: "local-file", Strength: 4},
+	}
+	if !reflect.DeepEqual(gotEdges, wantEdges) {
+		t.Errorf("edges =\n%+v\nwant\n%+v", gotEdges, wantEdges)
+	}
+
+	if gotEntrypoints[0].ModuleId != "misc:cmd" {
+		t.Errorf("entrypoint module = %q, want misc:cmd", gotEntrypoints[0].ModuleId)
+	}
+	if entrypoints[0].ModuleId != "mod:tool" {
+		t.Error("input entrypoints were modified")
+	}
+}
+
+func TestTopLevelDir(t *testing.T) {
+	tests := map[string]string{
+		"":               ".",
+		".":              ".",
+		"internal/query": "internal",
+		"cmd":            "cmd",
+		"./web/src/":     "web",
+	}
+	for in, want := range tests {
+		if got := topLevelDir(in); got != want {
+			t.Errorf("topLevelDir(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMergeParallelEdges(t *testing.T) {
+	edges := []DependencyEdge{
+		{From: "api", To: "query", Kind: "local-module", Strength: 3},
+		{From: "query", To: "storage", Kind: "local-module", Strength: 2},
+		{From: "api", To: "query", Kind: "local-file", Strength: 1},
+		{From: "query", To: "storage", Kind: "local-module", Strength: 4},
+		{From: "api", To: "query", Kind: "local-module", Strength: 2},
+		{From: "query", To: "api", Kind: "local-file", Strength: 1},
+	}
+
+	got := mergeParallelEdges(edges)
+	want := []DependencyEdge{
+		{From: "api", To: "query", Kind: DependencyEdgeKindMixed, Strength: 6, KindStrengths: map[string]int{"local-module": 5, "local-file": 1}},
+		{From: "query", To: "storage", Kind: "local-module", Strength: 6},
+		{From: "query", To: "api", Kind: "local-file", Strength: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeParallelEdges() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestArchitectureCacheKeyFollowsRepoState(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	// The graph is read from the working tree, so any change to it must
+	// miss the cache even when the index hasn't moved
+	clean := engine.architectureCacheKey(&RepoState{RepoStateId: "head-abc"})
+	edited := engine.architectureCacheKey(&RepoState{RepoStateId: "head-abc-dirty-123"})
+	if clean == edited {
+		t.Errorf("cache key %q unchanged after the working tree changed", clean)
+	}
+}
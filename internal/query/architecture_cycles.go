@@ -0,0 +1,90 @@
+package query
+
+import "sort"
+
+// findDependencyCycles returns the groups of modules that depend on each
+// other in a cycle: the strongly connected components of the dependency
+// graph with more than one module. Self-loops alone don't count. Each cycle
+// lists its module IDs sorted; cycles are ordered largest first, then by
+// their first module.
+func findDependencyCycles(edges []DependencyEdge) [][]string {
+	adjacency := make(map[string][]string)
+	var nodes []string
+	seen := make(map[string]bool)
+	addNode := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			nodes = append(nodes, id)
+		}
+	}
+	for _, edge := range edges {
+		addNode(edge.From)
+		addNode(edge.To)
+		if edge.From != edge.To {
+			adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		}
+	}
+	// Fixed visiting order keeps the result independent of edge order
+	sort.Strings(nodes)
+	for id := range adjacency {
+		sort.Strings(adjacency[id])
+	}
+
+	// Tarjan's strongly connected components
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+	next := 0
+
+	var strongConnect func(v string)
+	strongConnect = func(v string) {
+		index[v] = next
+		lowlink[v] = next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range adjacency[v] {
+			if _, visited := index[w]; !visited {
+				strongConnect(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+
+		if lowlink[v] != index[v] {
+			return
+		}
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, v := range nodes {
+		if _, visited := index[v]; !visited {
+			strongConnect(v)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		if len(cycles[i]) != len(cycles[j]) {
+			return len(cycles[i]) > len(cycles[j])
+		}
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
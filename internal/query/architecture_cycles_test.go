@@ -0,0 +1,81 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindDependencyCycles(t *testing.T) {
+	edge := func(from, to string) DependencyEdge {
+		return DependencyEdge{From: from, To: to, Kind: "local-module", Strength: 1}
+	}
+
+	tests := []struct {
+		name  string
+		edges []DependencyEdge
+		want  [][]string
+	}{
+		{
+			name:  "acyclic",
+			edges: []DependencyEdge{edge("api", "query"), edge("query", "storage"), edge("api", "storage")},
+			want:  nil,
+		},
+		{
+			name:  "self-loop is not a cycle",
+			edges: []DependencyEdge{edge("api", "api"), edge("api", "query")},
+			want:  nil,
+		},
+		{
+			name:  "two-module cycle",
+			edges: []DependencyEdge{edge("query", "storage"), edge("storage", "query")},
+			want:  [][]string{{"query", "storage"}},
+		},
+		{
+			name: "separate cycles, largest first",
+			edges: []DependencyEdge{
+				edge("x", "y"), edge("y", "x"),
+				edge("a", "b"), edge("b", "c"), edge("c", "a"),
+				edge("c", "x"), // joins the two one way only
+				edge("a", "a"),
+			},
+			want: [][]string{{"a", "b", "c"}, {"x", "y"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findDependencyCycles(tt.edges)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findDependencyCycles() = %v, want %v", got, tt.want)
+			}
+
+			// Edge order must not matter
+			reversed := make([]DependencyEdge, len(tt.edges))
+			for i, e := range tt.edges {
+				reversed[len(tt.edges)-1-i] = e
+			}
+			if again := findDependencyCycles(reversed); !reflect.DeepEqual(again, got) {
+				t.Errorf("result depends on edge order: %v vs %v", again, got)
+			}
+		})
+	}
+}
+
+func TestCycleDrilldown(t *testing.T) {
+	modules := map[string]ModuleSummary{
+		"mod-a": {ModuleId: "mod-a", Name: "a", Path: "internal/a"},
+	}
+
+	got := cycleDrilldown([]string{"mod-a", "mod-b"}, modules)
+	if got.Tool != "getModuleOverview" {
+		t.Errorf("Tool = %q, want getModuleOverview", got.Tool)
+	}
+	if got.Params["path"] != "internal/a" || got.Params["name"] != "a" {
+		t.Errorf("Params = %v, want path internal/a and name a", got.Params)
+	}
+
+	// Modules outside the summary still get a usable drilldown
+	if got := cycleDrilldown([]string{"mod-z", "mod-a"}, modules); got.Params["name"] != "mod-z" {
+		t.Errorf("Params = %v, want name mod-z", got.Params)
+	}
+}
@@ -0,0 +1,316 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"ckb/internal/architecture"
+	"ckb/internal/errors"
+	"ckb/internal/modules"
+	"ckb/internal/output"
+)
+
+// Default relative strength change for an edge to be reported as changed.
+const defaultStrengthChangeThreshold = 0.5
+
+// Edges whose strength moves by less than this are never reported as changed,
+// so 1 -> 2 import churn does not drown out real coupling shifts.
+const minStrengthDelta = 2
+
+// DiffArchitectureOptions contains options for diffArchitecture.
+type DiffArchitectureOptions struct {
+	Base                    string  // Base revision (required)
+	Head                    string  // Head revision (default: HEAD)
+	IncludeExternalDeps     bool    // Include external-dependency edges
+	StrengthChangeThreshold float64 // Relative strength change to report (default: 0.5)
+}
+
+// DiffArchitectureResponse is the response for diffArchitecture.
+type DiffArchitectureResponse struct {
+	Base           string                 `json:"base"`
+	Head           string                 `json:"head"`
+	AddedModules   []ModuleSummary        `json:"addedModules"`
+	RemovedModules []ModuleSummary        `json:"removedModules"`
+	AddedEdges     []DependencyEdge       `json:"addedEdges"`
+	RemovedEdges   []DependencyEdge       `json:"removedEdges"`
+	ChangedEdges   []DependencyEdgeChange `json:"changedEdges"`
+	Limitations    []string               `json:"limitations,omitempty"`
+	Provenance     *Provenance            `json:"provenance"`
+	Drilldowns     []output.Drilldown     `json:"drilldowns,omitempty"`
+}
+
+// DependencyEdgeChange describes a dependency edge whose strength changed.
+type DependencyEdgeChange struct {
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Kind         string `json:"kind"`
+	BaseStrength int    `json:"baseStrength"`
+	HeadStrength int    `json:"headStrength"`
+	Delta        int    `json:"delta"`
+}
+
+// architectureSnapshot is the module graph at one revision.
+type architectureSnapshot struct {
+	Modules []ModuleSummary
+	Edges   []DependencyEdge
+}
+
+// DiffArchitecture compares the module dependency graph at two revisions.
+// Each revision is checked out into a temporary git worktree and analyzed
+// with the architecture generator, without the response caps getArchitecture
+// applies. When a revision cannot be materialized the diff is returned empty
+// with a limitation.
+func (e *Engine) DiffArchitecture(ctx context.Context, opts DiffArchitectureOptions) (*DiffArchitectureResponse, error) {
+	startTime := time.Now()
+
+	if opts.Base == "" {
+		return nil, fmt.Errorf("base revision is required")
+	}
+	if opts.Head == "" {
+		opts.Head = "HEAD"
+	}
+	if opts.StrengthChangeThreshold <= 0 {
+		opts.StrengthChangeThreshold = defaultStrengthChangeThreshold
+	}
+
+	repoState, err := e.GetRepoState(ctx, "head")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	resp := &DiffArchitectureResponse{
+		Base:           opts.Base,
+		Head:           opts.Head,
+		AddedModules:   []ModuleSummary{},
+		RemovedModules: []ModuleSummary{},
+		AddedEdges:     []DependencyEdge{},
+		RemovedEdges:   []DependencyEdge{},
+		ChangedEdges:   []DependencyEdgeChange{},
+	}
+
+	completeness := CompletenessInfo{Score: 1.0, Reason: "full-backend"}
+	var backendContribs []BackendContribution
+
+	if e.gitAdapter == nil || !e.gitAdapter.IsAvailable() {
+		resp.Limitations = append(resp.Limitations, "Git backend unavailable; cannot compute architecture at historical revisions")
+		completeness = CompletenessInfo{Score: 0, Reason: "no-backend"}
+		resp.Provenance = e.buildProvenance(ctx, repoState, "head", startTime, nil, completeness)
+		return resp, nil
+	}
+	backendContribs = append(backendContribs, BackendContribution{BackendId: "git", Available: true, Used: true})
+
+	baseCommit, err := e.gitAdapter.ResolveCommit(opts.Base)
+	if err != nil {
+		return nil, fmt.Errorf("unknown base revision %q", opts.Base)
+	}
+	headCommit, err := e.gitAdapter.ResolveCommit(opts.Head)
+	if err != nil {
+		return nil, fmt.Errorf("unknown head revision %q", opts.Head)
+	}
+	resp.Base = baseCommit
+	resp.Head = headCommit
+
+	base, baseErr := e.architectureAtRevision(ctx, baseCommit, opts.IncludeExternalDeps)
+	head, headErr := e.architectureAtRevision(ctx, headCommit, opts.IncludeExternalDeps)
+	if baseErr != nil || headErr != nil {
+		for _, err := range []error{baseErr, headErr} {
+			if err != nil {
+				resp.Limitations = append(resp.Limitations, err.Error())
+			}
+		}
+		completeness = CompletenessInfo{Score: 0, Reason: "revision-unavailable"}
+		resp.Provenance = e.buildProvenance(ctx, repoState, "head", startTime, backendContribs, completeness)
+		return resp, nil
+	}
+
+	diffArchitectureSnapshots(resp, base, head, opts.StrengthChangeThreshold)
+
+	resp.Provenance = e.buildProvenance(ctx, repoState, "head", startTime, backendContribs, completeness)
+	resp.Drilldowns = architectureDiffDrilldowns(resp)
+
+	return resp, nil
+}
+
+// architectureAtRevision generates the module graph for a commit in a
+// temporary worktree.
+func (e *Engine) architectureAtRevision(ctx context.Context, commit string, includeExternal bool) (*architectureSnapshot, error) {
+	tmpDir, err := os.MkdirTemp("", "ckb-arch-*")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create worktree directory for %s: %v", shortCommit(commit), err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	worktree := filepath.Join(tmpDir, "tree")
+	if err := e.gitAdapter.AddWorktree(worktree, commit); err != nil {
+		return nil, fmt.Errorf("cannot check out %s: %v", shortCommit(commit), err)
+	}
+	defer func() {
+		if err := e.gitAdapter.RemoveWorktree(worktree); err != nil {
+			e.logger.Warn("Failed to remove architecture worktree", map[string]interface{}{
+				"worktree": worktree,
+				"error":    err.Error(),
+			})
+		}
+	}()
+
+	importScanner := modules.NewImportScanner(&e.config.ImportScan, e.logger)
+	generator := architecture.NewArchitectureGenerator(worktree, e.config, importScanner, e.logger)
+	arch, err := generator.Generate(ctx, commit, &architecture.GeneratorOptions{
+		Depth:               2,
+		IncludeExternalDeps: includeExternal,
+		Refresh:             true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("architecture generation failed at %s: %v", shortCommit(commit), err)
+	}
+
+	return &architectureSnapshot{
+		Modules: convertModuleSummaries(arch.Modules),
+		Edges:   convertArchEdges(arch.DependencyGraph, includeExternal),
+	}, nil
+}
+
+// diffArchitectureSnapshots fills resp with the module and edge differences
+// between base and head. All result lists are sorted deterministically.
+func diffArchitectureSnapshots(resp *DiffArchitectureResponse, base, head *architectureSnapshot, threshold float64) {
+	baseModules := make(map[string]ModuleSummary, len(base.Modules))
+	for _, m := range base.Modules {
+		baseModules[m.ModuleId] = m
+	}
+	headModules := make(map[string]ModuleSummary, len(head.Modules))
+	for _, m := range head.Modules {
+		headModules[m.ModuleId] = m
+		if _, ok := baseModules[m.ModuleId]; !ok {
+			resp.AddedModules = append(resp.AddedModules, m)
+		}
+	}
+	for _, m := range base.Modules {
+		if _, ok := headModules[m.ModuleId]; !ok {
+			resp.RemovedModules = append(resp.RemovedModules, m)
+		}
+	}
+
+	edgeKey := func(e DependencyEdge) string { return e.From + "\x00" + e.To + "\x00" + e.Kind }
+	baseEdges := make(map[string]DependencyEdge, len(base.Edges))
+	for _, edge := range base.Edges {
+		baseEdges[edgeKey(edge)] = edge
+	}
+	headEdges := make(map[string]DependencyEdge, len(head.Edges))
+	for _, edge := range head.Edges {
+		headEdges[edgeKey(edge)] = edge
+		baseEdge, ok := baseEdges[edgeKey(edge)]
+		if !ok {
+			resp.AddedEdges = append(resp.AddedEdges, edge)
+			continue
+		}
+		if isSignificantStrengthChange(baseEdge.Strength, edge.Strength, threshold) {
+			resp.ChangedEdges = append(resp.ChangedEdges, DependencyEdgeChange{
+				From:         edge.From,
+				To:           edge.To,
+				Kind:         edge.Kind,
+				BaseStrength: baseEdge.Strength,
+				HeadStrength: edge.Strength,
+				Delta:        edge.Strength - baseEdge.Strength,
+			})
+		}
+	}
+	for _, edge := range base.Edges {
+		if _, ok := headEdges[edgeKey(edge)]; !ok {
+			resp.RemovedEdges = append(resp.RemovedEdges, edge)
+		}
+	}
+
+	sortModulesById(resp.AddedModules)
+	sortModulesById(resp.RemovedModules)
+	sortEdgesLexically(resp.AddedEdges)
+	sortEdgesLexically(resp.RemovedEdges)
+
+	// Largest shifts first, lexical tie-breaker
+	sort.Slice(resp.ChangedEdges, func(i, j int) bool {
+		di, dj := absInt(resp.ChangedEdges[i].Delta), absInt(resp.ChangedEdges[j].Delta)
+		if di != dj {
+			return di > dj
+		}
+		if resp.ChangedEdges[i].From != resp.ChangedEdges[j].From {
+			return resp.ChangedEdges[i].From < resp.ChangedEdges[j].From
+		}
+		if resp.ChangedEdges[i].To != resp.ChangedEdges[j].To {
+			return resp.ChangedEdges[i].To < resp.ChangedEdges[j].To
+		}
+		return resp.ChangedEdges[i].Kind < resp.ChangedEdges[j].Kind
+	})
+}
+
+// isSignificantStrengthChange reports whether an edge strength moved by at
+// least minStrengthDelta and by at least threshold relative to the base.
+func isSignificantStrengthChange(base, head int, threshold float64) bool {
+	delta := absInt(head - base)
+	if delta < minStrengthDelta {
+		return false
+	}
+	if base <= 0 {
+		return true
+	}
+	return float64(delta)/float64(base) >= threshold
+}
+
+func sortModulesById(mods []ModuleSummary) {
+	sort.Slice(mods, func(i, j int) bool {
+		return mods[i].ModuleId < mods[j].ModuleId
+	})
+}
+
+func sortEdgesLexically(edges []DependencyEdge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		if edges[i].To != edges[j].To {
+			return edges[i].To < edges[j].To
+		}
+		return edges[i].Kind < edges[j].Kind
+	})
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// shortCommit abbreviates a commit hash for messages.
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+// architectureDiffDrilldowns suggests follow-ups for newly introduced structure.
+func architectureDiffDrilldowns(resp *DiffArchitectureResponse) []output.Drilldown {
+	var drilldowns []output.Drilldown
+	if len(resp.AddedModules) > 0 {
+		m := resp.AddedModules[0]
+		drilldowns = append(drilldowns, output.Drilldown{
+			Label:          fmt.Sprintf("Explore new module %s", m.Name),
+			Query:          fmt.Sprintf("getModuleOverview --path=%s", m.Path),
+			Tool:           "getModuleOverview",
+			Params:         map[string]interface{}{"path": m.Path},
+			RelevanceScore: 0.8,
+		})
+	}
+	if len(resp.AddedEdges) > 0 || len(resp.ChangedEdges) > 0 {
+		drilldowns = append(drilldowns, output.Drilldown{
+			Label:          "Inspect the current dependency graph",
+			Query:          "getArchitecture",
+			Tool:           "getArchitecture",
+			RelevanceScore: 0.6,
+		})
+	}
+	return drilldowns
+}
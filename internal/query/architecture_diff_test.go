@@ -0,0 +1,106 @@
+package query
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDiffArchitectureSnapshots(t *testing.T) {
+	base := &architectureSnapshot{
+		Modules: []ModuleSummary{
+			{ModuleId: "mod:api", Name: "api"},
+			{ModuleId: "mod:legacy", Name: "legacy"},
+			{ModuleId: "mod:query", Name: "query"},
+		},
+		Edges: []DependencyEdge{
+			{From: "mod:api", To: "mod:query", Kind: "local-module", Strength: 4},
+			{From: "mod:api", To: "mod:legacy", Kind: "local-module", Strength: 2},
+			{From: "mod:query", To: "mod:storage", Kind: "local-module", Strength: 10},
+		},
+	}
+	head := &architectureSnapshot{
+		Modules: []ModuleSummary{
+			{ModuleId: "mod:query", Name: "query"},
+			{ModuleId: "mod:api", Name: "api"},
+			{ModuleId: "mod:cache", Name: "cache"},
+		},
+		Edges: []DependencyEdge{
+			{From: "mod:api", To: "mod:query", Kind: "local-module", Strength: 12},
+			{From: "mod:query", To: "mod:storage", Kind: "local-module", Strength: 11},
+			{From: "mod:query", To: "mod:cache", Kind: "local-module", Strength: 3},
+			{From: "mod:api", To: "mod:cache", Kind: "local-module", Strength: 1},
+		},
+	}
+
+	resp := &DiffArchitectureResponse{}
+	diffArchitectureSnapshots(resp, base, head, 0.5)
+
+	if len(resp.AddedModules) != 1 || resp.AddedModules[0].ModuleId != "mod:cache" {
+		t.Errorf("expected mod:cache added, got %+v", resp.AddedModules)
+	}
+	if len(resp.RemovedModules) != 1 || resp.RemovedModules[0].ModuleId != "mod:legacy" {
+		t.Errorf("expected mod:legacy removed, got %+v", resp.RemovedModules)
+	}
+
+	// Added edges are ordered lexically, regardless of input order
+	if len(resp.AddedEdges) != 2 {
+		t.Fatalf("expected 2 added edges, got %+v", resp.AddedEdges)
+	}
+	if resp.AddedEdges[0].From != "mod:api" || resp.AddedEdges[1].From != "mod:query" {
+		t.Errorf("added edges not sorted: %+v", resp.AddedEdges)
+	}
+	if len(resp.RemovedEdges) != 1 || resp.RemovedEdges[0].To != "mod:legacy" {
+		t.Errorf("expected api->legacy removed, got %+v", resp.RemovedEdges)
+	}
+
+	// 10 -> 11 is below the threshold; 4 -> 12 is reported
+	if len(resp.ChangedEdges) != 1 {
+		t.Fatalf("expected 1 changed edge, got %+v", resp.ChangedEdges)
+	}
+	change := resp.ChangedEdges[0]
+	if change.To != "mod:query" || change.BaseStrength != 4 || change.HeadStrength != 12 || change.Delta != 8 {
+		t.Errorf("unexpected changed edge: %+v", change)
+	}
+}
+
+func TestIsSignificantStrengthChange(t *testing.T) {
+	tests := []struct {
+		base, head int
+		threshold  float64
+		expected   bool
+	}{
+		{4, 12, 0.5, true},
+		{12, 4, 0.5, true},
+		{10, 11, 0.5, false},
+		{1, 2, 0.5, false}, // below minimum absolute delta
+		{10, 14, 0.5, false},
+		{10, 14, 0.3, true},
+		{0, 3, 0.5, true},
+	}
+
+	for _, tt := range tests {
+		if got := isSignificantStrengthChange(tt.base, tt.head, tt.threshold); got != tt.expected {
+			t.Errorf("isSignificantStrengthChange(%d, %d, %v) = %v, want %v", tt.base, tt.head, tt.threshold, got, tt.expected)
+		}
+	}
+}
+
+func TestDiffArchitectureWithoutGit(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if _, err := engine.DiffArchitecture(context.Background(), DiffArchitectureOptions{}); err == nil {
+		t.Error("expected error when base is missing")
+	}
+
+	resp, err := engine.DiffArchitecture(context.Background(), DiffArchitectureOptions{Base: "main"})
+	if err != nil {
+		t.Fatalf("DiffArchitecture failed: %v", err)
+	}
+	if len(resp.Limitations) == 0 {
+		t.Error("expected a limitation when git is unavailable")
+	}
+	if resp.AddedModules == nil || resp.ChangedEdges == nil {
+		t.Error("expected empty, non-nil result lists")
+	}
+}
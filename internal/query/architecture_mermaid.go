@@ -0,0 +1,110 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Architecture output formats.
+const (
+	ArchitectureFormatJSON    = "json"
+	ArchitectureFormatMermaid = "mermaid"
+)
+
+// architectureMermaid renders modules and dependency edges as a mermaid
+// "graph LR" block. Every module and edge endpoint becomes a node labeled
+// with the module name (looked up in known for endpoints outside modules);
+// edges are labeled "kind: strength". Nodes and edges are sorted so
+// identical graphs render identically. Each omission note becomes a %%
+// comment so readers know the diagram is partial.
+func architectureMermaid(modules []ModuleSummary, edges []DependencyEdge, known map[string]ModuleSummary, omissions []string) string {
+	labels := make(map[string]string)
+	for _, m := range modules {
+		labels[m.ModuleId] = m.Name
+	}
+	for _, e := range edges {
+		for _, id := range []string{e.From, e.To} {
+			if _, ok := labels[id]; !ok {
+				labels[id] = known[id].Name
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(labels))
+	for id := range labels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// Sanitizing can map distinct IDs to the same node ID; number the
+	// later ones so each module keeps its own node
+	nodeIDs := make(map[string]string, len(ids))
+	used := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		base := mermaidNodeID(id)
+		node := base
+		for n := 2; used[node]; n++ {
+			node = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[node] = true
+		nodeIDs[id] = node
+	}
+
+	sortedEdges := append([]DependencyEdge(nil), edges...)
+	sort.Slice(sortedEdges, func(i, j int) bool {
+		if sortedEdges[i].From != sortedEdges[j].From {
+			return sortedEdges[i].From < sortedEdges[j].From
+		}
+		if sortedEdges[i].To != sortedEdges[j].To {
+			return sortedEdges[i].To < sortedEdges[j].To
+		}
+		return sortedEdges[i].Kind < sortedEdges[j].Kind
+	})
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, note := range omissions {
+		fmt.Fprintf(&b, "  %%%% %s\n", note)
+	}
+	for _, id := range ids {
+		label := labels[id]
+		if label == "" {
+			label = id
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", nodeIDs[id], mermaidEscape(label))
+	}
+	for _, e := range sortedEdges {
+		edgeLabel := fmt.Sprintf("%d", e.Strength)
+		if e.Kind != "" {
+			edgeLabel = e.Kind + ": " + edgeLabel
+		}
+		fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", nodeIDs[e.From], mermaidEscape(edgeLabel), nodeIDs[e.To])
+	}
+	return b.String()
+}
+
+// mermaidNodeID turns a module ID into a mermaid node identifier: anything
+// other than letters, digits and underscores becomes an underscore.
+func mermaidNodeID(id string) string {
+	var b strings.Builder
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	node := b.String()
+	// "end" closes subgraphs in mermaid and can't name a node
+	if node == "" || strings.EqualFold(node, "end") {
+		node = "m_" + node
+	}
+	return node
+}
+
+// mermaidEscape makes text safe inside a quoted mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
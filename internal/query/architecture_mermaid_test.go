@@ -0,0 +1,61 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArchitectureMermaid(t *testing.T) {
+	modules := []ModuleSummary{
+		{ModuleId: "internal/query", Name: "query"},
+		{ModuleId: "internal/api", Name: "api"},
+	}
+	known := map[string]ModuleSummary{
+		"internal/storage": {ModuleId: "internal/storage", Name: "storage"},
+	}
+	edges := []DependencyEdge{
+		{From: "internal/query", To: "internal/storage", Kind: "local-module", Strength: 3},
+		{From: "internal/api", To: "internal/query", Kind: "local-module", Strength: 7},
+	}
+
+	got := architectureMermaid(modules, edges, known, []string{"truncated: showing 2 of 5 modules"})
+	want := `graph LR
+  %% truncated: showing 2 of 5 modules
+  internal_api["api"]
+  internal_query["query"]
+  internal_storage["storage"]
+  internal_api -->|"local-module: 7"| internal_query
+  internal_query -->|"local-module: 3"| internal_storage
+`
+	if got != want {
+		t.Errorf("architectureMermaid() =\n%s\nwant:\n%s", got, want)
+	}
+
+	// Order of the input doesn't change the output
+	reversed := []DependencyEdge{edges[1], edges[0]}
+	if again := architectureMermaid([]ModuleSummary{modules[1], modules[0]}, reversed, known, []string{"truncated: showing 2 of 5 modules"}); again != got {
+		t.Errorf("output depends on input order:\n%s", again)
+	}
+}
+
+func TestArchitectureMermaidNodeIDs(t *testing.T) {
+	modules := []ModuleSummary{
+		{ModuleId: "a.b", Name: `say "hi"`},
+		{ModuleId: "a/b"},
+		{ModuleId: "end"},
+	}
+	got := architectureMermaid(modules, nil, nil, nil)
+
+	for _, line := range []string{
+		`a_b["say #quot;hi#quot;"]`,
+		`a_b_2["a/b"]`,
+		`m_end["end"]`,
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("missing %q in:\n%s", line, got)
+		}
+	}
+	if strings.Contains(got, "%%") {
+		t.Errorf("unexpected truncation comment in:\n%s", got)
+	}
+}
@@ -0,0 +1,376 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"ckb/internal/backends"
+	"ckb/internal/backends/git"
+	"ckb/internal/errors"
+	"ckb/internal/output"
+)
+
+// symbolNettedOut marks a symbol a branch commit touched that ends up
+// unchanged relative to the merge base.
+const symbolNettedOut = "netted-out"
+
+const (
+	// maxBranchFiles bounds how many files are mapped to symbols.
+	maxBranchFiles = 200
+
+	// maxBranchFileSymbols bounds the symbols looked up per file.
+	maxBranchFileSymbols = 500
+)
+
+// AnalyzeBranchImpactOptions contains options for analyzeBranchImpact.
+type AnalyzeBranchImpactOptions struct {
+	Base         string // Branch the head would be merged into (default "main")
+	Head         string // Branch to analyze (default HEAD)
+	Depth        int
+	IncludeTests bool
+}
+
+// AnalyzeBranchImpactResponse is the net impact of merging a branch.
+type AnalyzeBranchImpactResponse struct {
+	Base           string                      `json:"base"`
+	Head           string                      `json:"head"`
+	MergeBase      string                      `json:"mergeBase"`
+	CommitCount    int                         `json:"commitCount"`
+	ChangedFiles   []BranchFileChange          `json:"changedFiles"`
+	ChangedSymbols []BranchSymbolChange        `json:"changedSymbols"`
+	NettedOut      []BranchSymbolChange        `json:"nettedOut,omitempty"`      // Touched by a commit, unchanged overall
+	NettedOutFiles []string                    `json:"nettedOutFiles,omitempty"` // Touched by a commit, identical to the merge base
+	Impact         *AnalyzeImpactBatchResponse `json:"impact,omitempty"`         // Combined impact of the modified symbols
+	Limitations    []string                    `json:"limitations,omitempty"`
+	Provenance     *Provenance                 `json:"provenance"`
+	Drilldowns     []output.Drilldown          `json:"drilldowns,omitempty"`
+}
+
+// BranchFileChange is a file that differs between the merge base and head.
+type BranchFileChange struct {
+	Path      string `json:"path"`
+	Status    string `json:"status"` // added, modified, deleted, renamed
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// BranchSymbolChange is a symbol the branch changed, or touched and then
+// changed back.
+type BranchSymbolChange struct {
+	StableId string `json:"stableId"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	FileId   string `json:"fileId"`
+	Line     int    `json:"line"`
+	Change   string `json:"change"` // added, modified, netted-out
+}
+
+// AnalyzeBranchImpact analyzes what merging head into base would change.
+// The net diff from the merge base to head is mapped to symbols the same
+// way explainFile marks changes, and the modified symbols are analyzed
+// together with AnalyzeImpactBatch. Intermediate churn doesn't count: a
+// symbol a commit changed and a later one restored is reported as netted
+// out rather than changed.
+func (e *Engine) AnalyzeBranchImpact(ctx context.Context, opts AnalyzeBranchImpactOptions) (*AnalyzeBranchImpactResponse, error) {
+	startTime := time.Now()
+
+	if opts.Base == "" {
+		opts.Base = "main"
+	}
+	if opts.Head == "" {
+		opts.Head = "HEAD"
+	}
+
+	if e.gitAdapter == nil || !e.gitAdapter.IsAvailable() {
+		return nil, errors.NewCkbError(errors.BackendUnavailable, "analyzeBranchImpact needs git history", nil, nil, nil)
+	}
+	if e.scipAdapter == nil || !e.scipAdapter.IsAvailable() {
+		return nil, errors.NewCkbError(
+			errors.IndexMissing,
+			"analyzeBranchImpact needs a SCIP index to map changed lines to symbols",
+			nil,
+			[]errors.FixAction{{Type: errors.RunCommand, Command: "ckb index", Safe: true, Description: "Build the SCIP index"}},
+			nil,
+		)
+	}
+
+	repoState, err := e.GetRepoState(ctx, "head")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	mergeBase, err := e.gitAdapter.GetMergeBase(opts.Base, opts.Head)
+	if err != nil {
+		return nil, errors.NewCkbError(
+			errors.InternalError,
+			fmt.Sprintf("No common ancestor between %s and %s", opts.Base, opts.Head),
+			err, nil, nil,
+		)
+	}
+	commits, err := e.gitAdapter.GetRangeCommits(mergeBase, opts.Head)
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+	diffStats, err := e.gitAdapter.GetCommitRangeDiff(mergeBase, opts.Head)
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	var limitations []string
+	if headCommit, err := e.gitAdapter.ResolveCommit(opts.Head); err == nil && headCommit != repoState.HeadCommit {
+		limitations = append(limitations, fmt.Sprintf("%s is not checked out; symbol positions come from the index of the checked-out tree and may not match", opts.Head))
+	}
+
+	changedFiles := make([]BranchFileChange, 0, len(diffStats))
+	netChanged := make(map[string]bool, len(diffStats))
+	isNew := make(map[string]bool)
+	for _, df := range diffStats {
+		status := "modified"
+		switch {
+		case df.IsNew:
+			status = "added"
+			isNew[df.FilePath] = true
+		case df.IsDeleted:
+			status = "deleted"
+		case df.IsRenamed:
+			status = "renamed"
+		}
+		changedFiles = append(changedFiles, BranchFileChange{
+			Path:      df.FilePath,
+			Status:    status,
+			Additions: df.Additions,
+			Deletions: df.Deletions,
+		})
+		netChanged[df.FilePath] = true
+	}
+	sort.Slice(changedFiles, func(i, j int) bool { return changedFiles[i].Path < changedFiles[j].Path })
+
+	lastTouch := lastTouchingCommits(commits)
+	var nettedOutFiles []string
+	for file := range lastTouch {
+		if !netChanged[file] {
+			nettedOutFiles = append(nettedOutFiles, file)
+		}
+	}
+	sort.Strings(nettedOutFiles)
+
+	files := make([]string, 0, len(netChanged)+len(nettedOutFiles))
+	for _, f := range changedFiles {
+		if f.Status != "deleted" {
+			files = append(files, f.Path)
+		}
+	}
+	files = append(files, nettedOutFiles...)
+	filesTruncated := len(files) > maxBranchFiles
+	if filesTruncated {
+		limitations = append(limitations, fmt.Sprintf("Only %d of %d files were mapped to symbols", maxBranchFiles, len(files)))
+		files = files[:maxBranchFiles]
+	}
+
+	var changed, netted []BranchSymbolChange
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		symbols := e.fileDefinitions(ctx, file)
+		if len(symbols) == 0 {
+			continue
+		}
+		headContent, err := e.gitAdapter.GetFileAtRef(opts.Head, file)
+		if err != nil {
+			continue
+		}
+		headLines := splitSourceLines(headContent)
+
+		net := append([]ExplainFileSymbol(nil), symbols...)
+		if netChanged[file] {
+			e.markRangeChanges(net, file, mergeBase, opts.Head, headLines, isNew[file])
+		}
+		changed = append(changed, branchSymbolChanges(net, file)...)
+
+		if hash, ok := lastTouch[file]; ok {
+			touched := append([]ExplainFileSymbol(nil), symbols...)
+			e.markRangeChanges(touched, file, hash+"^", hash, headLines, false)
+			netted = append(netted, branchSymbolChanges(nettedOutSymbols(net, touched), file)...)
+		}
+	}
+	touches := 0
+	for _, c := range commits {
+		touches += len(c.Files)
+	}
+	if touches > len(lastTouch) {
+		// Some file was changed by several commits
+		limitations = append(limitations, "Netted-out symbols are found from the last commit touching each file; earlier reverted edits are not reported")
+	}
+	if changed == nil {
+		changed = []BranchSymbolChange{}
+	}
+
+	var impactIds []string
+	for _, c := range changed {
+		if c.Change == symbolModified {
+			impactIds = append(impactIds, c.StableId)
+		}
+	}
+	impactIds = dedupeSymbolIds(impactIds)
+	if len(impactIds) > maxImpactBatchSymbols {
+		limitations = append(limitations, fmt.Sprintf("Impact covers the first %d of %d modified symbols", maxImpactBatchSymbols, len(impactIds)))
+		impactIds = impactIds[:maxImpactBatchSymbols]
+	}
+	var batch *AnalyzeImpactBatchResponse
+	if len(impactIds) > 0 {
+		batch, err = e.AnalyzeImpactBatch(ctx, AnalyzeImpactBatchOptions{
+			SymbolIds:    impactIds,
+			Depth:        opts.Depth,
+			IncludeTests: opts.IncludeTests,
+		})
+		if err != nil {
+			limitations = append(limitations, fmt.Sprintf("Impact analysis failed: %v", err))
+			batch = nil
+		}
+	}
+
+	completeness := CompletenessInfo{Score: 1.0, Reason: "full-backend"}
+	if filesTruncated {
+		completeness = CompletenessInfo{Score: 0.8, Reason: "max-files"}
+	}
+	provenance := e.buildProvenance(ctx, repoState, "head", startTime, []BackendContribution{
+		{BackendId: "git", Available: true, Used: true, ResultCount: len(changedFiles), Completeness: completeness.Score},
+		{BackendId: "scip", Available: true, Used: len(files) > 0, ResultCount: len(changed), Completeness: completeness.Score},
+	}, completeness)
+
+	var drilldowns []output.Drilldown
+	for i, id := range impactIds {
+		if i >= 3 {
+			break
+		}
+		drilldowns = append(drilldowns, output.Drilldown{
+			Label:          fmt.Sprintf("Impact of %s", id),
+			Query:          fmt.Sprintf("analyzeImpact %s", id),
+			Tool:           "analyzeImpact",
+			Params:         map[string]interface{}{"symbolId": id},
+			RelevanceScore: 0.9 - float64(i)*0.05,
+		})
+	}
+
+	return &AnalyzeBranchImpactResponse{
+		Base:           opts.Base,
+		Head:           opts.Head,
+		MergeBase:      mergeBase,
+		CommitCount:    len(commits),
+		ChangedFiles:   changedFiles,
+		ChangedSymbols: changed,
+		NettedOut:      netted,
+		NettedOutFiles: nettedOutFiles,
+		Impact:         batch,
+		Limitations:    limitations,
+		Provenance:     provenance,
+		Drilldowns:     drilldowns,
+	}, nil
+}
+
+// fileDefinitions returns the indexed symbols defined in a file, sorted by
+// line.
+func (e *Engine) fileDefinitions(ctx context.Context, relPath string) []ExplainFileSymbol {
+	result, err := e.scipAdapter.SearchSymbols(ctx, "", backends.SearchOptions{
+		MaxResults:   maxBranchFileSymbols,
+		Scope:        []string{relPath},
+		IncludeTests: true,
+	})
+	if err != nil || result == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var symbols []ExplainFileSymbol
+	for _, sym := range result.Symbols {
+		if seen[sym.StableID] {
+			continue
+		}
+		if line, ok := definitionLineInFile(sym, relPath); ok {
+			seen[sym.StableID] = true
+			symbols = append(symbols, ExplainFileSymbol{
+				StableId: sym.StableID,
+				Name:     sym.Name,
+				Kind:     sym.Kind,
+				Line:     line,
+			})
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Line < symbols[j].Line })
+	return symbols
+}
+
+// markRangeChanges is markSymbolChanges between two refs: head must be the
+// revision headLines (and the symbol lines) belong to.
+func (e *Engine) markRangeChanges(symbols []ExplainFileSymbol, file, base, head string, headLines []string, isNew bool) {
+	if isNew {
+		classifySymbolChanges(symbols, nil, len(headLines), true)
+		return
+	}
+	hunks, err := e.gitAdapter.GetRangeFileHunks(base, head, file)
+	if err != nil {
+		return
+	}
+	classifySymbolChanges(symbols, hunks, len(headLines), false)
+	if !anySymbolChange(symbols, symbolModified) {
+		return
+	}
+	if oldContent, err := e.gitAdapter.GetFileAtRef(base, file); err == nil {
+		refineChangesByContent(symbols, hunks, splitSourceLines(oldContent), headLines)
+	}
+}
+
+// lastTouchingCommits maps each file to the newest commit changing it.
+// Commits must be newest first.
+func lastTouchingCommits(commits []git.RangeCommit) map[string]string {
+	last := make(map[string]string)
+	for _, c := range commits {
+		for _, f := range c.Files {
+			if _, ok := last[f]; !ok {
+				last[f] = c.Hash
+			}
+		}
+	}
+	return last
+}
+
+// nettedOutSymbols returns the symbols a commit touched that are unchanged
+// in the net diff. net and touched are the same symbols, in the same order,
+// marked against the merge base and the commit's parent respectively.
+func nettedOutSymbols(net, touched []ExplainFileSymbol) []ExplainFileSymbol {
+	var result []ExplainFileSymbol
+	for i := range touched {
+		if i >= len(net) || !isSymbolChanged(touched[i].Change) || isSymbolChanged(net[i].Change) {
+			continue
+		}
+		sym := touched[i]
+		sym.Change = symbolNettedOut
+		result = append(result, sym)
+	}
+	return result
+}
+
+func isSymbolChanged(change string) bool {
+	return change == symbolAdded || change == symbolModified
+}
+
+// branchSymbolChanges converts the changed symbols of a file for the response.
+func branchSymbolChanges(symbols []ExplainFileSymbol, file string) []BranchSymbolChange {
+	var result []BranchSymbolChange
+	for _, sym := range symbols {
+		if sym.Change != symbolNettedOut && !isSymbolChanged(sym.Change) {
+			continue
+		}
+		result = append(result, BranchSymbolChange{
+			StableId: sym.StableId,
+			Name:     sym.Name,
+			Kind:     sym.Kind,
+			FileId:   file,
+			Line:     sym.Line,
+			Change:   sym.Change,
+		})
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"ckb/internal/backends/git"
+)
+
+func TestLastTouchingCommits(t *testing.T) {
+	commits := []git.RangeCommit{
+		{Hash: "c3", Files: []string{"a.go"}},
+		{Hash: "c2"}, // merge
+		{Hash: "c1", Files: []string{"a.go", "b.go"}},
+	}
+
+	want := map[string]string{"a.go": "c3", "b.go": "c1"}
+	if got := lastTouchingCommits(commits); !reflect.DeepEqual(got, want) {
+		t.Errorf("lastTouchingCommits() = %v, want %v", got, want)
+	}
+}
+
+func TestNettedOutSymbols(t *testing.T) {
+	net := []ExplainFileSymbol{
+		{StableId: "kept", Line: 1, Change: symbolModified},
+		{StableId: "reverted", Line: 10, Change: symbolUnchanged},
+		{StableId: "restored", Line: 20, Change: symbolUnchanged},
+		{StableId: "untouched", Line: 30, Change: ""},
+	}
+	touched := []ExplainFileSymbol{
+		{StableId: "kept", Line: 1, Change: symbolModified},
+		{StableId: "reverted", Line: 10, Change: symbolModified},
+		{StableId: "restored", Line: 20, Change: symbolAdded},
+		{StableId: "untouched", Line: 30, Change: symbolUnchanged},
+	}
+
+	got := nettedOutSymbols(net, touched)
+	var ids []string
+	for _, sym := range got {
+		ids = append(ids, sym.StableId)
+		if sym.Change != symbolNettedOut {
+			t.Errorf("%s: change = %q, want %q", sym.StableId, sym.Change, symbolNettedOut)
+		}
+	}
+	if want := []string{"reverted", "restored"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("nettedOutSymbols() = %v, want %v", ids, want)
+	}
+}
+
+func TestBranchSymbolChanges(t *testing.T) {
+	symbols := []ExplainFileSymbol{
+		{StableId: "a", Name: "A", Kind: "function", Line: 3, Change: symbolAdded},
+		{StableId: "b", Name: "B", Kind: "function", Line: 9, Change: symbolUnchanged},
+		{StableId: "c", Name: "C", Kind: "type", Line: 15, Change: symbolModified},
+	}
+
+	want := []BranchSymbolChange{
+		{StableId: "a", Name: "A", Kind: "function", FileId: "pkg/x.go", Line: 3, Change: symbolAdded},
+		{StableId: "c", Name: "C", Kind: "type", FileId: "pkg/x.go", Line: 15, Change: symbolModified},
+	}
+	if got := branchSymbolChanges(symbols, "pkg/x.go"); !reflect.DeepEqual(got, want) {
+		t.Errorf("branchSymbolChanges() = %+v, want %+v", got, want)
+	}
+}
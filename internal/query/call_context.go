@@ -0,0 +1,129 @@
+package query
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Bounds on the source attached by getCallGraph's includeContext option
+const (
+	callContextRadius     = 2       // Lines shown either side of a call
+	maxCallContexts       = 50      // Edges and nodes that get source attached
+	maxContextLineLength  = 200     // Longer lines are cut
+	maxContextSourceBytes = 1 << 20 // Larger files are skipped
+)
+
+// SourceContext is a short excerpt of source around a line.
+type SourceContext struct {
+	Line      int      `json:"line"`      // 1-based line of interest
+	StartLine int      `json:"startLine"` // 1-based line of Lines[0]
+	Lines     []string `json:"lines"`
+}
+
+// sourceLineReader reads lines of repo files for excerpts, caching each file
+// for the life of one request. Paths outside the repository, unreadable
+// files and files over maxContextSourceBytes yield nothing.
+type sourceLineReader struct {
+	engine *Engine
+	files  map[string][]string
+}
+
+func (e *Engine) newSourceLineReader() *sourceLineReader {
+	return &sourceLineReader{engine: e, files: make(map[string][]string)}
+}
+
+func (r *sourceLineReader) lines(path string) []string {
+	if lines, ok := r.files[path]; ok {
+		return lines
+	}
+	r.files[path] = nil
+
+	absPath, _, _, err := r.engine.resolveRepoFile(path)
+	if err != nil {
+		return nil
+	}
+	info, err := os.Stat(absPath)
+	if err != nil || info.Size() > maxContextSourceBytes {
+		return nil
+	}
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxContextSourceBytes)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	r.files[path] = lines
+	return lines
+}
+
+// excerpt returns line of path (1-based) with radius lines either side.
+func (r *sourceLineReader) excerpt(path string, line, radius int) *SourceContext {
+	return excerptLines(r.lines(path), line, radius)
+}
+
+func excerptLines(lines []string, line, radius int) *SourceContext {
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+	start := line - radius
+	if start < 1 {
+		start = 1
+	}
+	end := line + radius
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	excerpt := make([]string, 0, end-start+1)
+	for _, l := range lines[start-1 : end] {
+		l = strings.TrimRight(l, " \t\r")
+		if len(l) > maxContextLineLength {
+			l = l[:maxContextLineLength] + "…"
+		}
+		excerpt = append(excerpt, l)
+	}
+	return &SourceContext{Line: line, StartLine: start, Lines: excerpt}
+}
+
+// attachCallContext adds the source around each known call site to edges
+// and the first line of each callee's definition to its node, up to
+// maxCallContexts of each. callSites is keyed by "from->to".
+func (e *Engine) attachCallContext(nodes []CallGraphNode, edges []CallGraphEdge, callSites map[string]*LocationInfo) {
+	reader := e.newSourceLineReader()
+
+	attached := 0
+	for i := range edges {
+		if attached >= maxCallContexts {
+			break
+		}
+		site := callSites[edges[i].From+"->"+edges[i].To]
+		if site == nil {
+			continue
+		}
+		if ctx := reader.excerpt(site.FileId, site.StartLine, callContextRadius); ctx != nil {
+			edges[i].CallSite = ctx
+			attached++
+		}
+	}
+
+	attached = 0
+	for i := range nodes {
+		if attached >= maxCallContexts {
+			break
+		}
+		if nodes[i].Role != "callee" || nodes[i].Location == nil {
+			continue
+		}
+		if ctx := reader.excerpt(nodes[i].Location.FileId, nodes[i].Location.StartLine, 0); ctx != nil {
+			nodes[i].Definition = ctx
+			attached++
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExcerptLines(t *testing.T) {
+	lines := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name   string
+		line   int
+		radius int
+		want   *SourceContext
+	}{
+		{"middle", 3, 1, &SourceContext{Line: 3, StartLine: 2, Lines: []string{"b", "c", "d"}}},
+		{"clamped at start", 1, 2, &SourceContext{Line: 1, StartLine: 1, Lines: []string{"a", "b", "c"}}},
+		{"clamped at end", 5, 2, &SourceContext{Line: 5, StartLine: 3, Lines: []string{"c", "d", "e"}}},
+		{"single line", 4, 0, &SourceContext{Line: 4, StartLine: 4, Lines: []string{"d"}}},
+		{"out of range", 6, 1, nil},
+		{"zero line", 0, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := excerptLines(lines, tt.line, tt.radius); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("excerptLines(%d, %d) = %+v, want %+v", tt.line, tt.radius, got, tt.want)
+			}
+		})
+	}
+
+	long := strings.Repeat("x", maxContextLineLength+50)
+	got := excerptLines([]string{long}, 1, 0)
+	if n := len(got.Lines[0]); n > maxContextLineLength+len("…") {
+		t.Errorf("long line kept %d bytes, want at most %d", n, maxContextLineLength+len("…"))
+	}
+}
+
+func TestAttachCallContext(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	src := "package main\n\nfunc helper() int {\n\treturn 1\n}\n\nfunc main() {\n\tx := helper()\n\t_ = x\n}\n"
+	if err := os.WriteFile(filepath.Join(engine.repoRoot, "main.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := []CallGraphNode{
+		{ID: "main", Role: "root"},
+		{ID: "helper", Role: "callee", Location: &LocationInfo{FileId: "main.go", StartLine: 3}},
+		{ID: "outside", Role: "callee", Location: &LocationInfo{FileId: "../etc/passwd", StartLine: 1}},
+	}
+	edges := []CallGraphEdge{
+		{From: "main", To: "helper"},
+		{From: "main", To: "outside"},
+	}
+	callSites := map[string]*LocationInfo{
+		"main->helper":  {FileId: "main.go", StartLine: 8},
+		"main->outside": {FileId: "../etc/passwd", StartLine: 1},
+	}
+
+	engine.attachCallContext(nodes, edges, callSites)
+
+	want := &SourceContext{Line: 8, StartLine: 6, Lines: []string{"", "func main() {", "\tx := helper()", "\t_ = x", "}"}}
+	if !reflect.DeepEqual(edges[0].CallSite, want) {
+		t.Errorf("call site = %+v, want %+v", edges[0].CallSite, want)
+	}
+	if def := nodes[1].Definition; def == nil || def.Lines[0] != "func helper() int {" {
+		t.Errorf("callee definition = %+v, want its first line", def)
+	}
+	if edges[1].CallSite != nil || nodes[2].Definition != nil {
+		t.Error("source outside the repository was attached")
+	}
+	if nodes[0].Definition != nil {
+		t.Error("root node got a definition excerpt")
+	}
+}
+
+func TestAttachSymbolSource(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	var src strings.Builder
+	for i := 1; i <= 120; i++ {
+		src.WriteString("line\n")
+	}
+	if err := os.WriteFile(filepath.Join(engine.repoRoot, "big.go"), []byte(src.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	symbolAt := func(file string, line int) *GetSymbolResponse {
+		return &GetSymbolResponse{Symbol: &SymbolInfo{Location: &LocationInfo{FileId: file, StartLine: line}}}
+	}
+
+	tests := []struct {
+		name      string
+		resp      *GetSymbolResponse
+		opts      GetSymbolOptions
+		wantStart int
+		wantLines int
+	}{
+		{"not requested", symbolAt("big.go", 60), GetSymbolOptions{}, 0, 0},
+		{"default context", symbolAt("big.go", 60), GetSymbolOptions{IncludeSource: true}, 55, 11},
+		{"explicit context", symbolAt("big.go", 60), GetSymbolOptions{IncludeSource: true, ContextLines: 2}, 58, 5},
+		{"capped context", symbolAt("big.go", 60), GetSymbolOptions{IncludeSource: true, ContextLines: 500}, 10, 101},
+		{"missing location", &GetSymbolResponse{Symbol: &SymbolInfo{}}, GetSymbolOptions{IncludeSource: true}, 0, 0},
+		{"missing file", symbolAt("gone.go", 1), GetSymbolOptions{IncludeSource: true}, 0, 0},
+		{"outside repo", symbolAt("../etc/passwd", 1), GetSymbolOptions{IncludeSource: true}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine.attachSymbolSource(tt.resp, tt.opts)
+			got := tt.resp.SourceSnippet
+			if tt.wantLines == 0 {
+				if got != nil {
+					t.Fatalf("expected no snippet, got %+v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("expected a snippet")
+			}
+			if got.StartLine != tt.wantStart || len(got.Lines) != tt.wantLines {
+				t.Errorf("snippet starts at %d with %d lines, want %d and %d", got.StartLine, len(got.Lines), tt.wantStart, tt.wantLines)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package query
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"ckb/internal/backends/scip"
+)
+
+// defaultCallGraphCacheEntries is the call graph cache size when the config
+// doesn't set one.
+const defaultCallGraphCacheEntries = 256
+
+// callGraphCacheKey identifies a call graph build.
+type callGraphCacheKey struct {
+	symbolId string
+	opts     scip.CallGraphOptions
+}
+
+type callGraphCacheEntry struct {
+	key   callGraphCacheKey
+	graph *scip.CallGraph
+}
+
+// callGraphCache is an LRU cache of SCIP call graphs shared by traceUsage,
+// getCallGraph and explainSymbol. Entries belong to one repo state; the
+// cache empties itself when a lookup arrives for a different state.
+type callGraphCache struct {
+	mu       sync.Mutex
+	capacity int
+	stateId  string
+	order    *list.List // front is most recently used
+	entries  map[callGraphCacheKey]*list.Element
+	hits     int64
+	misses   int64
+}
+
+func newCallGraphCache(capacity int) *callGraphCache {
+	if capacity <= 0 {
+		capacity = defaultCallGraphCacheEntries
+	}
+	return &callGraphCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[callGraphCacheKey]*list.Element),
+	}
+}
+
+// get returns the cached graph for key under the given repo state.
+func (c *callGraphCache) get(stateId string, key callGraphCacheKey) (*scip.CallGraph, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resetIfStale(stateId)
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		return el.Value.(*callGraphCacheEntry).graph, true
+	}
+	c.misses++
+	return nil, false
+}
+
+// put stores a graph, evicting the least recently used entry when full.
+func (c *callGraphCache) put(stateId string, key callGraphCacheKey, graph *scip.CallGraph) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resetIfStale(stateId)
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*callGraphCacheEntry).graph = graph
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&callGraphCacheEntry{key: key, graph: graph})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*callGraphCacheEntry).key)
+	}
+}
+
+// resetIfStale drops every entry when the repo state has moved on.
+// Callers hold c.mu.
+func (c *callGraphCache) resetIfStale(stateId string) {
+	if stateId == c.stateId {
+		return
+	}
+	c.stateId = stateId
+	c.order.Init()
+	c.entries = make(map[callGraphCacheKey]*list.Element)
+}
+
+// clear drops every entry, for when the index changes under an unchanged
+// repo state. Hit and miss counts are kept.
+func (c *callGraphCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stateId = ""
+	c.order.Init()
+	c.entries = make(map[callGraphCacheKey]*list.Element)
+}
+
+// status reports the cache size and hit rate.
+func (c *callGraphCache) status() *CallGraphCacheStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := &CallGraphCacheStatus{
+		Entries:  c.order.Len(),
+		Capacity: c.capacity,
+		Hits:     c.hits,
+		Misses:   c.misses,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		s.HitRate = float64(c.hits) / float64(total)
+	}
+	return s
+}
+
+// buildCallGraph returns the SCIP call graph for symbolId, served from the
+// call graph cache when the repo state is unchanged. Cached graphs are
+// shared and must not be modified.
+func (e *Engine) buildCallGraph(ctx context.Context, symbolId string, opts scip.CallGraphOptions) (*scip.CallGraph, error) {
+	if e.callGraphCache == nil {
+		return e.scipAdapter.BuildCallGraph(symbolId, opts)
+	}
+	repoState, err := e.GetRepoState(ctx, "full")
+	if err != nil {
+		return e.scipAdapter.BuildCallGraph(symbolId, opts)
+	}
+
+	key := callGraphCacheKey{symbolId: symbolId, opts: opts}
+	if graph, ok := e.callGraphCache.get(repoState.RepoStateId, key); ok {
+		return graph, nil
+	}
+	graph, err := e.scipAdapter.BuildCallGraph(symbolId, opts)
+	if err != nil {
+		return nil, err
+	}
+	e.callGraphCache.put(repoState.RepoStateId, key, graph)
+	return graph, nil
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"testing"
+
+	"ckb/internal/backends/scip"
+)
+
+func TestCallGraphCache(t *testing.T) {
+	key := func(id string) callGraphCacheKey {
+		return callGraphCacheKey{symbolId: id, opts: scip.CallGraphOptions{Direction: scip.DirectionCallees, MaxDepth: 1}}
+	}
+	graph := func(id string) *scip.CallGraph {
+		return &scip.CallGraph{Root: &scip.CallGraphNode{SymbolID: id}}
+	}
+
+	t.Run("lru eviction", func(t *testing.T) {
+		c := newCallGraphCache(2)
+		c.put("s1", key("a"), graph("a"))
+		c.put("s1", key("b"), graph("b"))
+		if _, ok := c.get("s1", key("a")); !ok {
+			t.Fatal("expected hit for a")
+		}
+		// b is now least recently used
+		c.put("s1", key("c"), graph("c"))
+		if _, ok := c.get("s1", key("b")); ok {
+			t.Error("expected b to be evicted")
+		}
+		if g, ok := c.get("s1", key("a")); !ok || g.Root.SymbolID != "a" {
+			t.Error("expected a to survive eviction")
+		}
+	})
+
+	t.Run("options are part of the key", func(t *testing.T) {
+		c := newCallGraphCache(4)
+		c.put("s1", key("a"), graph("a"))
+		deeper := key("a")
+		deeper.opts.MaxDepth = 3
+		if _, ok := c.get("s1", deeper); ok {
+			t.Error("expected miss for a different depth")
+		}
+	})
+
+	t.Run("repo state change invalidates", func(t *testing.T) {
+		c := newCallGraphCache(4)
+		c.put("s1", key("a"), graph("a"))
+		if _, ok := c.get("s2", key("a")); ok {
+			t.Error("expected miss after repo state change")
+		}
+		if _, ok := c.get("s1", key("a")); ok {
+			t.Error("entries from the old state should be gone")
+		}
+	})
+
+	t.Run("clear all cache drops entries", func(t *testing.T) {
+		engine, cleanup := testEngine(t)
+		defer cleanup()
+
+		engine.callGraphCache.put("s1", key("a"), graph("a"))
+		if err := engine.ClearAllCache(); err != nil {
+			t.Fatalf("ClearAllCache: %v", err)
+		}
+		if _, ok := engine.callGraphCache.get("s1", key("a")); ok {
+			t.Error("expected miss after ClearAllCache at the same repo state")
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		c := newCallGraphCache(0)
+		c.put("s1", key("a"), graph("a"))
+		c.get("s1", key("a"))
+		c.get("s1", key("a"))
+		c.get("s1", key("b"))
+
+		s := c.status()
+		if s.Capacity != defaultCallGraphCacheEntries {
+			t.Errorf("capacity = %d, want default %d", s.Capacity, defaultCallGraphCacheEntries)
+		}
+		if s.Entries != 1 || s.Hits != 2 || s.Misses != 1 {
+			t.Errorf("status = %+v, want 1 entry, 2 hits, 1 miss", s)
+		}
+		if !floatEqual(s.HitRate, 2.0/3.0) {
+			t.Errorf("hit rate = %v, want 2/3", s.HitRate)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Call graph output formats.
+const (
+	CallGraphFormatJSON = "json"
+	CallGraphFormatDOT  = "dot"
+)
+
+// callGraphDOT renders a call graph as a Graphviz digraph. Edges run from
+// caller to callee, so callers point at the root and callees away from it.
+// Nodes and edges are emitted sorted by ID so identical graphs produce
+// identical output.
+func callGraphDOT(root string, nodes []CallGraphNode, edges []CallGraphEdge) string {
+	sortedNodes := append([]CallGraphNode(nil), nodes...)
+	sort.Slice(sortedNodes, func(i, j int) bool { return sortedNodes[i].ID < sortedNodes[j].ID })
+
+	sortedEdges := append([]CallGraphEdge(nil), edges...)
+	sort.Slice(sortedEdges, func(i, j int) bool {
+		if sortedEdges[i].From != sortedEdges[j].From {
+			return sortedEdges[i].From < sortedEdges[j].From
+		}
+		return sortedEdges[i].To < sortedEdges[j].To
+	})
+
+	var b strings.Builder
+	b.WriteString("digraph callgraph {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+
+	for _, n := range sortedNodes {
+		name := n.Name
+		if name == "" {
+			name = n.ID
+		}
+		attrs := []string{"label=" + dotQuote(name)}
+		if n.Location != nil && n.Location.FileId != "" {
+			attrs = append(attrs, "tooltip="+dotQuote(fmt.Sprintf("%s:%d", n.Location.FileId, n.Location.StartLine)))
+		}
+		if n.ID == root || n.Role == "root" {
+			attrs = append(attrs, "style=bold", "penwidth=2")
+		} else if n.Role == "transitive" {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+
+	for _, e := range sortedEdges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote returns s as a double-quoted DOT ID.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,57 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCallGraphDOT(t *testing.T) {
+	nodes := []CallGraphNode{
+		{ID: "root", Name: "Handle", Role: "root", Location: &LocationInfo{FileId: "api/handler.go", StartLine: 42}},
+		{ID: "callee", Name: "store", Role: "callee"},
+		{ID: "caller", Name: `Serve"HTTP"`, Role: "caller", Location: &LocationInfo{FileId: "api/server.go", StartLine: 7}},
+		{ID: "deep", Name: "flush", Role: "transitive"},
+	}
+	edges := []CallGraphEdge{
+		{From: "root", To: "callee"},
+		{From: "caller", To: "root"},
+		{From: "callee", To: "deep"},
+	}
+
+	got := callGraphDOT("root", nodes, edges)
+	want := `digraph callgraph {
+  rankdir=LR;
+  node [shape=box, fontname="Helvetica"];
+  "callee" [label="store"];
+  "caller" [label="Serve\"HTTP\"", tooltip="api/server.go:7"];
+  "deep" [label="flush", style=dashed];
+  "root" [label="Handle", tooltip="api/handler.go:42", style=bold, penwidth=2];
+  "callee" -> "deep";
+  "caller" -> "root";
+  "root" -> "callee";
+}
+`
+	if got != want {
+		t.Errorf("callGraphDOT() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Input order must not matter
+	reversed := []CallGraphNode{nodes[3], nodes[2], nodes[1], nodes[0]}
+	if again := callGraphDOT("root", reversed, []CallGraphEdge{edges[2], edges[1], edges[0]}); again != got {
+		t.Errorf("output depends on input order:\n%s", again)
+	}
+	if nodes[0].ID != "root" {
+		t.Error("callGraphDOT reordered its input")
+	}
+}
+
+func TestGetCallGraphRejectsUnknownFormat(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	_, err := engine.GetCallGraph(context.Background(), CallGraphOptions{SymbolId: "x", OutputFormat: "svg"})
+	if err == nil || !strings.Contains(err.Error(), "invalid output format") {
+		t.Errorf("expected an invalid output format error, got %v", err)
+	}
+}
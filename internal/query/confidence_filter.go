@@ -0,0 +1,41 @@
+package query
+
+import "strings"
+
+// belowMinConfidence reports whether confidence falls short of a
+// minConfidence option. A minimum of zero or less disables filtering.
+func belowMinConfidence(confidence, min float64) bool {
+	return min > 0 && confidence < min && !floatEqual(confidence, min)
+}
+
+// filterByConfidence drops the items below min and returns how many were
+// dropped. Order is preserved.
+func filterByConfidence[T any](items []T, min float64, confidence func(T) float64) ([]T, int) {
+	if min <= 0 {
+		return items, 0
+	}
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if !belowMinConfidence(confidence(item), min) {
+			kept = append(kept, item)
+		}
+	}
+	return kept, len(items) - len(kept)
+}
+
+// searchMatchConfidence is how sure a search result is to be what the query
+// asked for, judged by how the name matches: exactly, as a prefix, as a
+// substring, or only fuzzily.
+func searchMatchConfidence(name, query string) float64 {
+	nameLower, queryLower := strings.ToLower(name), strings.ToLower(query)
+	switch {
+	case nameLower == queryLower:
+		return 1.0
+	case strings.HasPrefix(nameLower, queryLower):
+		return 0.8
+	case strings.Contains(nameLower, queryLower):
+		return 0.6
+	default:
+		return 0.3
+	}
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterByConfidence(t *testing.T) {
+	items := []ImpactItem{
+		{StableId: "a", Confidence: 0.95},
+		{StableId: "b", Confidence: 0.5},
+		{StableId: "c", Confidence: 0.8},
+		{StableId: "d", Confidence: 0.1 + 0.7}, // 0.7999999999999999
+	}
+	confidence := func(item ImpactItem) float64 { return item.Confidence }
+
+	tests := []struct {
+		name         string
+		min          float64
+		wantIds      []string
+		wantFiltered int
+	}{
+		{"disabled", 0, []string{"a", "b", "c", "d"}, 0},
+		{"threshold keeps equal scores", 0.8, []string{"a", "c", "d"}, 1},
+		{"everything filtered", 0.99, []string{}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, filtered := filterByConfidence(items, tt.min, confidence)
+			ids := []string{}
+			for _, item := range kept {
+				ids = append(ids, item.StableId)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIds) {
+				t.Errorf("kept = %v, want %v", ids, tt.wantIds)
+			}
+			if filtered != tt.wantFiltered {
+				t.Errorf("filtered = %d, want %d", filtered, tt.wantFiltered)
+			}
+		})
+	}
+}
+
+func TestSearchMatchConfidence(t *testing.T) {
+	tests := []struct {
+		name, query string
+		want        float64
+	}{
+		{"Engine", "engine", 1.0},
+		{"EngineConfig", "engine", 0.8},
+		{"NewEngine", "engine", 0.6},
+		{"Runner", "engine", 0.3},
+	}
+
+	for _, tt := range tests {
+		if got := searchMatchConfidence(tt.name, tt.query); got != tt.want {
+			t.Errorf("searchMatchConfidence(%q, %q) = %v, want %v", tt.name, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRankSearchResultsSetsConfidence(t *testing.T) {
+	results := []SearchResultItem{
+		{Name: "Engine", Kind: "class"},
+		{Name: "NewEngine", Kind: "function"},
+	}
+	rankSearchResults(results, "Engine")
+
+	if results[0].Confidence != 1.0 || results[1].Confidence != 0.6 {
+		t.Errorf("confidences = %v, %v; want 1.0, 0.6", results[0].Confidence, results[1].Confidence)
+	}
+}
@@ -0,0 +1,95 @@
+package query
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"ckb/internal/decisions"
+)
+
+// Field weights for ranking decisions against a search. A term in the title
+// (or ID) says more about what a decision is about than one in its body.
+const (
+	decisionTitleWeight       = 3.0
+	decisionDecisionWeight    = 2.0
+	decisionContextWeight     = 1.0
+	decisionConsequenceWeight = 1.0
+)
+
+// decisionSearchTerms splits a search into distinct lowercased terms.
+func decisionSearchTerms(search string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, term := range strings.Fields(strings.ToLower(search)) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// rankDecisions keeps the decisions matching every term and sorts them by
+// relevance, highest first, with ties broken by ID. Decisions listed from
+// the database carry only their summary, so their ADR files are parsed for
+// the body; if a file can't be read the decision is ranked on its title and
+// ID alone. It returns the ranked decisions and their scores by ID.
+func (e *Engine) rankDecisions(adrs []*decisions.ArchitecturalDecision, terms []string) ([]*decisions.ArchitecturalDecision, map[string]float64) {
+	ranked := make([]*decisions.ArchitecturalDecision, 0, len(adrs))
+	scores := make(map[string]float64)
+	for _, adr := range adrs {
+		content := adr
+		if adr.Context == "" && adr.Decision == "" && adr.FilePath != "" {
+			if full, err := e.parseDecisionFile(adr.FilePath); err == nil {
+				content = full
+			}
+		}
+		score, ok := scoreDecision(content, terms)
+		if !ok {
+			continue
+		}
+		ranked = append(ranked, adr)
+		scores[adr.ID] = score
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		si, sj := scores[ranked[i].ID], scores[ranked[j].ID]
+		if si != sj {
+			return si > sj
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	return ranked, scores
+}
+
+// scoreDecision scores a decision against lowercased search terms. Each
+// field a term occurs in adds the field's weight, growing logarithmically
+// with repeat occurrences. It reports false unless every term occurs
+// somewhere.
+func scoreDecision(adr *decisions.ArchitecturalDecision, terms []string) (float64, bool) {
+	fields := []struct {
+		text   string
+		weight float64
+	}{
+		{strings.ToLower(adr.ID + " " + adr.Title), decisionTitleWeight},
+		{strings.ToLower(adr.Decision), decisionDecisionWeight},
+		{strings.ToLower(adr.Context), decisionContextWeight},
+		{strings.ToLower(strings.Join(adr.Consequences, "\n")), decisionConsequenceWeight},
+	}
+
+	total := 0.0
+	for _, term := range terms {
+		matched := false
+		for _, f := range fields {
+			if n := strings.Count(f.text, term); n > 0 {
+				total += f.weight * (1 + math.Log(float64(n)))
+				matched = true
+			}
+		}
+		if !matched {
+			return 0, false
+		}
+	}
+	return math.Round(total*100) / 100, true
+}
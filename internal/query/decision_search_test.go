@@ -0,0 +1,142 @@
+package query
+
+import (
+	"testing"
+
+	"ckb/internal/decisions"
+	"ckb/internal/storage"
+)
+
+func TestGetDecisionsSearch(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	writer := decisions.NewWriter(engine.repoRoot, "docs/decisions")
+	write := func(num int, title, status, context, decision string, consequences []string, modules ...string) {
+		adr := decisions.NewADR(num, title)
+		adr.Status = status
+		adr.Context = context
+		adr.Decision = decision
+		adr.Consequences = consequences
+		adr.AffectedModules = modules
+		if _, err := writer.CreateADR(adr); err != nil {
+			t.Fatalf("CreateADR(%d) failed: %v", num, err)
+		}
+	}
+	write(1, "Use an LRU for query caching", "accepted",
+		"Queries repeat across tool calls.", "Cache query results in an LRU.",
+		[]string{"Stale results until invalidation"}, "internal/query")
+	write(2, "Adopt SQLite for storage", "accepted",
+		"We need an embedded store; caching in memory alone loses data.", "Use SQLite.",
+		[]string{"Single writer"}, "internal/storage")
+	write(3, "Invalidate caches on reindex", "proposed",
+		"Query caching serves stale data after a reindex.", "Drop the query cache on every reindex.",
+		nil, "internal/query")
+
+	ids := func(result *DecisionsResult) []string {
+		var out []string
+		for _, adr := range result.Decisions {
+			out = append(out, adr.ID)
+		}
+		return out
+	}
+
+	check := func(t *testing.T, q *DecisionsQuery, want ...string) *DecisionsResult {
+		t.Helper()
+		result, err := engine.GetDecisions(q)
+		if err != nil {
+			t.Fatalf("GetDecisions failed: %v", err)
+		}
+		got := ids(result)
+		if len(got) != len(want) {
+			t.Fatalf("decisions = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("decisions = %v, want %v", got, want)
+			}
+		}
+		return result
+	}
+
+	t.Run("files", func(t *testing.T) {
+		// Title matches outrank body matches
+		result := check(t, &DecisionsQuery{Search: "caching"}, "ADR-001", "ADR-002", "ADR-003")
+		if result.Scores["ADR-001"] <= result.Scores["ADR-003"] {
+			t.Errorf("scores = %v, want the title match highest", result.Scores)
+		}
+
+		// Every term must match
+		check(t, &DecisionsQuery{Search: "query reindex"}, "ADR-003")
+		check(t, &DecisionsQuery{Search: "caching postgres"})
+
+		// Filters compose with search
+		check(t, &DecisionsQuery{Search: "caching", Status: "accepted", ModuleID: "internal/query"}, "ADR-001")
+		check(t, &DecisionsQuery{Status: "accepted", ModuleID: "internal/storage"}, "ADR-002")
+
+		result = check(t, &DecisionsQuery{Search: "caching", Limit: 1}, "ADR-001")
+		if len(result.Scores) != 1 {
+			t.Errorf("scores = %v, want only the returned decision", result.Scores)
+		}
+
+		if result := check(t, &DecisionsQuery{}, "ADR-001", "ADR-002", "ADR-003"); result.Scores != nil {
+			t.Errorf("scores = %v, want none without a search", result.Scores)
+		}
+	})
+
+	t.Run("database", func(t *testing.T) {
+		// Database records carry only summaries; the body comes from the file
+		repo := storage.NewDecisionRepository(engine.db)
+		parser := decisions.NewParser(engine.repoRoot)
+		adrs, err := parser.ParseDirectory("docs/decisions")
+		if err != nil {
+			t.Fatalf("ParseDirectory failed: %v", err)
+		}
+		for _, adr := range adrs {
+			if err := repo.Create(&storage.DecisionRecord{
+				ID:              adr.ID,
+				Title:           adr.Title,
+				Status:          adr.Status,
+				AffectedModules: `["` + adr.AffectedModules[0] + `"]`,
+				FilePath:        adr.FilePath,
+				CreatedAt:       adr.Date,
+				UpdatedAt:       adr.Date,
+			}); err != nil {
+				t.Fatalf("Create(%s) failed: %v", adr.ID, err)
+			}
+		}
+
+		check(t, &DecisionsQuery{Search: "stale"}, "ADR-001", "ADR-003")
+		check(t, &DecisionsQuery{Search: "stale", Status: "proposed"}, "ADR-003")
+	})
+}
+
+func TestScoreDecision(t *testing.T) {
+	adr := &decisions.ArchitecturalDecision{
+		ID:           "ADR-007",
+		Title:        "Cache call graphs",
+		Context:      "Call graphs are rebuilt per request.",
+		Decision:     "Keep call graphs in a cache.",
+		Consequences: []string{"Memory grows with the cache"},
+	}
+
+	if _, ok := scoreDecision(adr, []string{"cache", "sqlite"}); ok {
+		t.Error("expected no match when a term is missing")
+	}
+	if _, ok := scoreDecision(adr, []string{"adr-007"}); !ok {
+		t.Error("expected the ID to match")
+	}
+
+	title, _ := scoreDecision(adr, []string{"cache"})
+	body, _ := scoreDecision(adr, []string{"memory"})
+	if title <= body {
+		t.Errorf("title match scored %v, consequence match %v; want title higher", title, body)
+	}
+}
+
+func TestDecisionSearchTerms(t *testing.T) {
+	got := decisionSearchTerms("  Query  caching query ")
+	if len(got) != 2 || got[0] != "query" || got[1] != "caching" {
+		t.Errorf("decisionSearchTerms() = %v, want [query caching]", got)
+	}
+}
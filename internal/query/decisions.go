@@ -21,6 +21,7 @@ type DecisionResult struct {
 type DecisionsResult struct {
 	Decisions []*decisions.ArchitecturalDecision `json:"decisions"`
 	Total     int                                `json:"total"`
+	Scores    map[string]float64                 `json:"scores,omitempty"` // Relevance by decision ID, set for searches
 	Query     *DecisionsQuery                    `json:"query,omitempty"`
 }
 
@@ -129,15 +130,7 @@ func (e *Engine) GetDecision(id string) (*DecisionResult, error) {
 
 	if record != nil {
 		// Parse the file to get full content
-		// Check if path is absolute (v6.0 style) or repo-relative
-		var adr *decisions.ArchitecturalDecision
-		var parseErr error
-		if filepath.IsAbs(record.FilePath) {
-			adr, parseErr = decisions.ParseFileAbsolute(record.FilePath)
-		} else {
-			parser := decisions.NewParser(e.repoRoot)
-			adr, parseErr = parser.ParseFile(record.FilePath)
-		}
+		adr, parseErr := e.parseDecisionFile(record.FilePath)
 		if parseErr == nil {
 			return &DecisionResult{
 				Decision: adr,
@@ -193,7 +186,9 @@ func (e *Engine) GetDecision(id string) (*DecisionResult, error) {
 	return nil, fmt.Errorf("decision not found: %s", id)
 }
 
-// GetDecisions retrieves decisions matching the query
+// GetDecisions retrieves decisions matching the query. The status and
+// module filters narrow the candidates; a search then keeps those that
+// contain every search term and orders them by relevance.
 func (e *Engine) GetDecisions(query *DecisionsQuery) (*DecisionsResult, error) {
 	if query == nil {
 		query = &DecisionsQuery{}
@@ -202,27 +197,66 @@ func (e *Engine) GetDecisions(query *DecisionsQuery) (*DecisionsResult, error) {
 		query.Limit = 50
 	}
 
-	decisionRepo := storage.NewDecisionRepository(e.db)
-	var records []*storage.DecisionRecord
-	var err error
-
-	// Query based on filters
-	switch {
-	case query.Status != "":
-		records, err = decisionRepo.GetByStatus(query.Status, query.Limit)
-	case query.ModuleID != "":
-		records, err = decisionRepo.GetByModule(query.ModuleID, query.Limit)
-	case query.Search != "":
-		records, err = decisionRepo.Search(query.Search, query.Limit)
-	default:
-		records, err = decisionRepo.ListAll(query.Limit)
+	adrs, err := e.listDecisions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply filters
+	filtered := make([]*decisions.ArchitecturalDecision, 0, len(adrs))
+	for _, adr := range adrs {
+		if query.Status != "" && adr.Status != query.Status {
+			continue
+		}
+		if query.ModuleID != "" {
+			found := false
+			for _, mod := range adr.AffectedModules {
+				if mod == query.ModuleID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		filtered = append(filtered, adr)
+	}
+
+	var scores map[string]float64
+	if terms := decisionSearchTerms(query.Search); len(terms) > 0 {
+		filtered, scores = e.rankDecisions(filtered, terms)
 	}
 
+	if query.Limit > 0 && len(filtered) > query.Limit {
+		filtered = filtered[:query.Limit]
+	}
+	if scores != nil {
+		returned := make(map[string]float64, len(filtered))
+		for _, adr := range filtered {
+			returned[adr.ID] = scores[adr.ID]
+		}
+		scores = returned
+	}
+
+	return &DecisionsResult{
+		Decisions: filtered,
+		Total:     len(filtered),
+		Scores:    scores,
+		Query:     query,
+	}, nil
+}
+
+// listDecisions returns every known decision, newest first, from the
+// database, or from a scan of the ADR directories (both repo-local and
+// global v6.0 paths) when the database has none.
+func (e *Engine) listDecisions() ([]*decisions.ArchitecturalDecision, error) {
+	decisionRepo := storage.NewDecisionRepository(e.db)
+	records, err := decisionRepo.ListAll(-1) // SQLite treats a negative LIMIT as none
 	if err != nil {
 		return nil, fmt.Errorf("failed to query decisions: %w", err)
 	}
 
-	// If we have database results, use them
 	if len(records) > 0 {
 		adrs := make([]*decisions.ArchitecturalDecision, 0, len(records))
 		for _, record := range records {
@@ -239,20 +273,12 @@ func (e *Engine) GetDecisions(query *DecisionsQuery) (*DecisionsResult, error) {
 			}
 			adrs = append(adrs, adr)
 		}
-
-		return &DecisionsResult{
-			Decisions: adrs,
-			Total:     len(adrs),
-			Query:     query,
-		}, nil
+		return adrs, nil
 	}
 
-	// Fall back to file system scan (both repo-local and global v6.0 paths)
 	parser := decisions.NewParser(e.repoRoot)
-	allDirs := parser.FindAllADRDirectories()
-
 	var allADRs []*decisions.ArchitecturalDecision
-	for _, dir := range allDirs {
+	for _, dir := range parser.FindAllADRDirectories() {
 		var adrs []*decisions.ArchitecturalDecision
 		var err error
 		if dir.IsAbsolute {
@@ -265,36 +291,16 @@ func (e *Engine) GetDecisions(query *DecisionsQuery) (*DecisionsResult, error) {
 		}
 		allADRs = append(allADRs, adrs...)
 	}
+	return allADRs, nil
+}
 
-	// Apply filters
-	filtered := make([]*decisions.ArchitecturalDecision, 0)
-	for _, adr := range allADRs {
-		if query.Status != "" && adr.Status != query.Status {
-			continue
-		}
-		if query.ModuleID != "" {
-			found := false
-			for _, mod := range adr.AffectedModules {
-				if mod == query.ModuleID {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-		filtered = append(filtered, adr)
-		if len(filtered) >= query.Limit {
-			break
-		}
+// parseDecisionFile parses the ADR file at a database record's path, which
+// is absolute (v6.0 style) or relative to the repo root.
+func (e *Engine) parseDecisionFile(path string) (*decisions.ArchitecturalDecision, error) {
+	if filepath.IsAbs(path) {
+		return decisions.ParseFileAbsolute(path)
 	}
-
-	return &DecisionsResult{
-		Decisions: filtered,
-		Total:     len(filtered),
-		Query:     query,
-	}, nil
+	return decisions.NewParser(e.repoRoot).ParseFile(path)
 }
 
 // UpdateDecisionStatus updates the status of an existing decision
@@ -0,0 +1,174 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ckb/internal/complexity"
+	"ckb/internal/hotspots"
+)
+
+// RiskComplexityIncrease is the risk signal type for functions whose
+// complexity crossed a threshold or grew sharply in a diff.
+const RiskComplexityIncrease = "complexity-increase"
+
+const (
+	defaultCyclomaticThreshold = 15
+	defaultCognitiveThreshold  = 20
+
+	// A function's complexity has grown significantly when it rose by at
+	// least complexityGrowthMin and by half or more of its old value.
+	complexityGrowthMin    = 5
+	complexityGrowthFactor = 1.5
+
+	// maxComplexityDiffFiles bounds the files parsed per diff; each is
+	// parsed twice.
+	maxComplexityDiffFiles = 20
+)
+
+// complexityChange is one function's complexity on both sides of a diff.
+// Old is nil for a function that didn't exist at the base.
+type complexityChange struct {
+	Name string
+	Old  *complexity.ComplexityResult
+	New  complexity.ComplexityResult
+}
+
+// complexitySignals compares function complexity at base and head for the
+// modified and added core and entrypoint files, and returns a
+// complexity-increase signal for each function that crossed a threshold
+// or grew significantly.
+func (e *Engine) complexitySignals(ctx context.Context, base, head string, files []DiffFileChange, cyclomaticThreshold, cognitiveThreshold int) ([]DiffRiskSignal, []string) {
+	if e.complexityAnalyzer == nil || !e.complexityAnalyzer.IsAvailable() {
+		return nil, []string{"Complexity analysis unavailable (requires CGO); complexity changes not checked"}
+	}
+	if cyclomaticThreshold <= 0 {
+		cyclomaticThreshold = defaultCyclomaticThreshold
+	}
+	if cognitiveThreshold <= 0 {
+		cognitiveThreshold = defaultCognitiveThreshold
+	}
+
+	var candidates []DiffFileChange
+	for _, file := range files {
+		if file.ChangeType == "deleted" || (file.Role != "core" && file.Role != "entrypoint") {
+			continue
+		}
+		if hotspots.IsSupported(file.FilePath) {
+			candidates = append(candidates, file)
+		}
+	}
+	var limitations []string
+	if len(candidates) > maxComplexityDiffFiles {
+		limitations = append(limitations, fmt.Sprintf("Complexity checked for %d of %d changed core files", maxComplexityDiffFiles, len(candidates)))
+		candidates = candidates[:maxComplexityDiffFiles]
+	}
+
+	var signals []DiffRiskSignal
+	for _, file := range candidates {
+		after := e.complexityAtRef(ctx, head, file.FilePath)
+		if after == nil {
+			continue
+		}
+		var before *complexity.FileComplexity
+		if file.ChangeType != "added" {
+			oldPath := file.FilePath
+			if file.OldPath != "" {
+				oldPath = file.OldPath
+			}
+			before = e.complexityAtRef(ctx, base, oldPath)
+		}
+
+		for _, change := range complexityChanges(before, after) {
+			signal, ok := complexitySignal(change, cyclomaticThreshold, cognitiveThreshold)
+			if !ok {
+				continue
+			}
+			signal.FilePath = file.FilePath
+			signals = append(signals, signal)
+		}
+	}
+	return signals, limitations
+}
+
+// complexityAtRef analyzes a file as of ref, or returns nil when it can't
+// be read or parsed.
+func (e *Engine) complexityAtRef(ctx context.Context, ref, path string) *complexity.FileComplexity {
+	source, err := e.gitAdapter.GetFileAtRef(ref, path)
+	if err != nil {
+		return nil
+	}
+	fc, err := e.complexityAnalyzer.GetSourceComplexity(ctx, path, []byte(source))
+	if err != nil || fc == nil || fc.Error != "" {
+		return nil
+	}
+	return fc
+}
+
+// complexityChanges pairs each function at head with the function of the
+// same name at base. Functions sharing a name (methods on different types)
+// are paired in order of appearance.
+func complexityChanges(before, after *complexity.FileComplexity) []complexityChange {
+	old := make(map[string][]complexity.ComplexityResult)
+	if before != nil {
+		for _, fn := range before.Functions {
+			old[fn.Name] = append(old[fn.Name], fn)
+		}
+	}
+
+	changes := make([]complexityChange, 0, len(after.Functions))
+	for _, fn := range after.Functions {
+		change := complexityChange{Name: fn.Name, New: fn}
+		if prev := old[fn.Name]; len(prev) > 0 {
+			change.Old = &prev[0]
+			old[fn.Name] = prev[1:]
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// complexitySignal returns a signal when a function's cyclomatic or
+// cognitive complexity crossed its threshold, which is high severity, or
+// grew significantly while staying under it, which is medium.
+func complexitySignal(c complexityChange, cyclomaticThreshold, cognitiveThreshold int) (DiffRiskSignal, bool) {
+	oldCyclomatic, oldCognitive := 0, 0
+	if c.Old != nil {
+		oldCyclomatic, oldCognitive = c.Old.Cyclomatic, c.Old.Cognitive
+	}
+	crossed := (c.New.Cyclomatic > cyclomaticThreshold && oldCyclomatic <= cyclomaticThreshold) ||
+		(c.New.Cognitive > cognitiveThreshold && oldCognitive <= cognitiveThreshold)
+	grew := c.Old != nil &&
+		(grewSignificantly(oldCyclomatic, c.New.Cyclomatic) || grewSignificantly(oldCognitive, c.New.Cognitive))
+	if !crossed && !grew {
+		return DiffRiskSignal{}, false
+	}
+
+	var desc strings.Builder
+	if c.Old == nil {
+		fmt.Fprintf(&desc, "New function %s has cyclomatic %d, cognitive %d", c.Name, c.New.Cyclomatic, c.New.Cognitive)
+	} else {
+		fmt.Fprintf(&desc, "Complexity of %s rose: cyclomatic %d -> %d, cognitive %d -> %d",
+			c.Name, oldCyclomatic, c.New.Cyclomatic, oldCognitive, c.New.Cognitive)
+	}
+	if crossed {
+		fmt.Fprintf(&desc, " (thresholds %d/%d)", cyclomaticThreshold, cognitiveThreshold)
+	}
+
+	severity := "medium"
+	if crossed {
+		severity = "high"
+	}
+	return DiffRiskSignal{
+		Type:        RiskComplexityIncrease,
+		Severity:    severity,
+		Line:        c.New.StartLine,
+		Description: desc.String(),
+		Confidence:  0.8,
+	}, true
+}
+
+func grewSignificantly(old, new int) bool {
+	return new-old >= complexityGrowthMin && float64(new) >= float64(old)*complexityGrowthFactor
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"ckb/internal/complexity"
+)
+
+func TestComplexityChanges(t *testing.T) {
+	before := &complexity.FileComplexity{Functions: []complexity.ComplexityResult{
+		{Name: "Parse", Cyclomatic: 4},
+		{Name: "String", Cyclomatic: 1},
+		{Name: "String", Cyclomatic: 2},
+		{Name: "Removed", Cyclomatic: 9},
+	}}
+	after := &complexity.FileComplexity{Functions: []complexity.ComplexityResult{
+		{Name: "Parse", Cyclomatic: 12},
+		{Name: "String", Cyclomatic: 1},
+		{Name: "String", Cyclomatic: 3},
+		{Name: "Added", Cyclomatic: 2},
+	}}
+
+	changes := complexityChanges(before, after)
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %d", len(changes))
+	}
+	if changes[0].Old == nil || changes[0].Old.Cyclomatic != 4 {
+		t.Errorf("Parse paired with %+v", changes[0].Old)
+	}
+	if changes[2].Old == nil || changes[2].Old.Cyclomatic != 2 {
+		t.Errorf("second String paired with %+v, want the second String at base", changes[2].Old)
+	}
+	if changes[3].Old != nil {
+		t.Errorf("Added paired with %+v, want nil", changes[3].Old)
+	}
+
+	if got := complexityChanges(nil, after); len(got) != 4 || got[0].Old != nil {
+		t.Errorf("added file: %+v", got)
+	}
+}
+
+func TestComplexitySignal(t *testing.T) {
+	result := func(cyclomatic, cognitive int) complexity.ComplexityResult {
+		return complexity.ComplexityResult{StartLine: 10, Cyclomatic: cyclomatic, Cognitive: cognitive}
+	}
+	old := func(cyclomatic, cognitive int) *complexity.ComplexityResult {
+		r := result(cyclomatic, cognitive)
+		return &r
+	}
+
+	tests := []struct {
+		name         string
+		change       complexityChange
+		wantSignal   bool
+		wantSeverity string
+		wantDesc     string
+	}{
+		{"unchanged", complexityChange{Name: "A", Old: old(5, 5), New: result(5, 5)}, false, "", ""},
+		{"small growth", complexityChange{Name: "A", Old: old(5, 5), New: result(8, 8)}, false, "", ""},
+		{"crossed cyclomatic", complexityChange{Name: "A", Old: old(14, 5), New: result(16, 6)}, true, "high", "cyclomatic 14 -> 16"},
+		{"crossed cognitive", complexityChange{Name: "A", Old: old(5, 18), New: result(6, 22)}, true, "high", "cognitive 18 -> 22"},
+		{"already over", complexityChange{Name: "A", Old: old(30, 5), New: result(31, 5)}, false, "", ""},
+		{"significant growth", complexityChange{Name: "A", Old: old(4, 4), New: result(10, 9)}, true, "medium", "cyclomatic 4 -> 10"},
+		{"growth above threshold", complexityChange{Name: "A", Old: old(20, 5), New: result(30, 5)}, true, "medium", "cyclomatic 20 -> 30"},
+		{"new complex function", complexityChange{Name: "B", New: result(18, 25)}, true, "high", "New function B"},
+		{"new simple function", complexityChange{Name: "B", New: result(3, 2)}, false, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signal, ok := complexitySignal(tt.change, 15, 20)
+			if ok != tt.wantSignal {
+				t.Fatalf("signal = %v, want %v (%+v)", ok, tt.wantSignal, signal)
+			}
+			if !ok {
+				return
+			}
+			if signal.Type != RiskComplexityIncrease || signal.Severity != tt.wantSeverity || signal.Line != 10 {
+				t.Errorf("signal = %+v, want severity %s", signal, tt.wantSeverity)
+			}
+			if !strings.Contains(signal.Description, tt.wantDesc) {
+				t.Errorf("description %q does not mention %q", signal.Description, tt.wantDesc)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"ckb/internal/backends"
+	"ckb/internal/backends/scip"
+	"ckb/internal/identity"
+	"ckb/internal/impact"
+)
+
+// SignatureDelta is a change to a modified symbol's signature between the
+// diff base and head.
+type SignatureDelta struct {
+	Old      string `json:"old"`
+	New      string `json:"new"`
+	Breaking bool   `json:"breaking"` // Some reference at head would break
+}
+
+// symbolSignature returns the signature an index records for a symbol, or
+// "" when the index or the indexer doesn't provide one.
+func symbolSignature(idx *scip.SCIPIndex, symbolId string) string {
+	if idx == nil {
+		return ""
+	}
+	if sym := idx.GetSymbol(symbolId); sym != nil {
+		return sym.SignatureText
+	}
+	return ""
+}
+
+// signatureDelta compares base and head signatures, returning nil when they
+// are equal once normalized. Breaking follows impact.IsBreakingChange for a
+// signature change over the symbol's references at head.
+func signatureDelta(oldSig, newSig string, symbol *impact.Symbol, refs []impact.Reference) *SignatureDelta {
+	if identity.NormalizeSignature(oldSig) == identity.NormalizeSignature(newSig) {
+		return nil
+	}
+	delta := &SignatureDelta{Old: oldSig, New: newSig}
+	for i := range refs {
+		if impact.IsBreakingChange(&refs[i], symbol, "signature-change") {
+			delta.Breaking = true
+			break
+		}
+	}
+	return delta
+}
+
+// addSignatureChanges sets SignatureChange on modified public symbols whose
+// signature differs between a SCIP index built at the diff base and the
+// current index, and returns risk signals for them. Symbols whose signature
+// isn't recorded on both sides are left alone and reported as limitations.
+func (e *Engine) addSignatureChanges(ctx context.Context, baseIndexPath string, symbols []DiffSymbolAffected) ([]DiffRiskSignal, []string) {
+	var candidates []int
+	for i, sym := range symbols {
+		if sym.ChangeType == "modified" && sym.IsPublicAPI && sym.SymbolId != "" {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	if baseIndexPath == "" {
+		return nil, []string{"No SCIP index for the diff base; signature changes not compared"}
+	}
+	baseIndex, err := scip.LoadSCIPIndex(baseIndexPath)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("Base SCIP index unavailable (%v); signature changes not compared", err)}
+	}
+	headIndex := e.scipAdapter.GetIndex()
+
+	var signals []DiffRiskSignal
+	uncompared := 0
+	for _, i := range candidates {
+		sym := &symbols[i]
+		oldSig := symbolSignature(baseIndex, sym.SymbolId)
+		newSig := symbolSignature(headIndex, sym.SymbolId)
+		if oldSig == "" || newSig == "" {
+			uncompared++
+			continue
+		}
+
+		symbol := &impact.Symbol{
+			StableId:            sym.SymbolId,
+			Name:                sym.Name,
+			Kind:                impact.SymbolKind(sym.Kind),
+			Signature:           newSig,
+			SignatureNormalized: identity.NormalizeSignature(newSig),
+		}
+		// References are only needed once the signature is known to differ
+		var refs []impact.Reference
+		if identity.NormalizeSignature(oldSig) != symbol.SignatureNormalized {
+			refs = e.headReferences(ctx, sym.SymbolId)
+		}
+		delta := signatureDelta(oldSig, newSig, symbol, refs)
+		if delta == nil {
+			continue
+		}
+		sym.SignatureChange = delta
+
+		signal := DiffRiskSignal{
+			Type:        "signature-change",
+			Severity:    "medium",
+			FilePath:    sym.FilePath,
+			Description: fmt.Sprintf("Signature of %s changed", sym.Name),
+			Confidence:  0.9,
+		}
+		if delta.Breaking {
+			signal.Type = "breaking-change"
+			signal.Severity = "high"
+			signal.Description = fmt.Sprintf("Signature of %s changed in a way that breaks its callers", sym.Name)
+		}
+		signals = append(signals, signal)
+	}
+
+	var limitations []string
+	if uncompared > 0 {
+		limitations = append(limitations, fmt.Sprintf("Signature not recorded at base or head for %d modified public symbols; not compared", uncompared))
+	}
+	return signals, limitations
+}
+
+// headReferences returns a symbol's references in the current index.
+func (e *Engine) headReferences(ctx context.Context, symbolId string) []impact.Reference {
+	result, err := e.scipAdapter.FindReferences(ctx, symbolId, backends.RefOptions{
+		MaxResults:   500,
+		IncludeTests: true,
+	})
+	if err != nil || result == nil {
+		return nil
+	}
+	refs := make([]impact.Reference, 0, len(result.References))
+	source := e.newSourceLineReader()
+	for _, ref := range result.References {
+		refs = append(refs, impact.Reference{
+			Kind: impactReferenceKind(source, ref),
+			Location: &impact.Location{
+				FileId:    ref.Location.Path,
+				StartLine: ref.Location.Line,
+			},
+			IsTest: isTestFilePath(ref.Location.Path),
+		})
+	}
+	return refs
+}
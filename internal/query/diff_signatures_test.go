@@ -0,0 +1,69 @@
+package query
+
+import (
+	"testing"
+
+	"ckb/internal/backends/scip"
+	"ckb/internal/impact"
+)
+
+func TestSignatureDelta(t *testing.T) {
+	symbol := &impact.Symbol{StableId: "pkg/Open().", Name: "Open"}
+	callers := []impact.Reference{{Kind: impact.RefCall}}
+	readers := []impact.Reference{{Kind: impact.RefRead}}
+
+	tests := []struct {
+		name         string
+		oldSig       string
+		newSig       string
+		refs         []impact.Reference
+		wantNil      bool
+		wantBreaking bool
+	}{
+		{"unchanged", "func Open(path string) error", "func Open(path string) error", callers, true, false},
+		{"whitespace only", "func Open(path string) error", "func Open(path  string)  error", callers, true, false},
+		{"changed with callers", "func Open(path string) error", "func Open(path string, mode int) error", callers, false, true},
+		{"changed without callers", "func Open(path string) error", "func Open(path string, mode int) error", readers, false, false},
+		{"changed without references", "func Open(path string) error", "func Open() error", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := signatureDelta(tt.oldSig, tt.newSig, symbol, tt.refs)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("expected nil delta, got %+v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("expected a delta")
+			}
+			if got.Old != tt.oldSig || got.New != tt.newSig {
+				t.Errorf("delta = %q -> %q, want %q -> %q", got.Old, got.New, tt.oldSig, tt.newSig)
+			}
+			if got.Breaking != tt.wantBreaking {
+				t.Errorf("Breaking = %v, want %v", got.Breaking, tt.wantBreaking)
+			}
+		})
+	}
+}
+
+func TestSymbolSignature(t *testing.T) {
+	idx := &scip.SCIPIndex{Symbols: map[string]*scip.SymbolInformation{
+		"pkg/Open().":  {Symbol: "pkg/Open().", SignatureText: "func Open() error"},
+		"pkg/Close().": {Symbol: "pkg/Close()."},
+	}}
+
+	if got := symbolSignature(idx, "pkg/Open()."); got != "func Open() error" {
+		t.Errorf("symbolSignature(Open) = %q", got)
+	}
+	if got := symbolSignature(idx, "pkg/Close()."); got != "" {
+		t.Errorf("symbolSignature(Close) = %q, want empty", got)
+	}
+	if got := symbolSignature(idx, "pkg/Missing()."); got != "" {
+		t.Errorf("symbolSignature(Missing) = %q, want empty", got)
+	}
+	if got := symbolSignature(nil, "pkg/Open()."); got != "" {
+		t.Errorf("symbolSignature(nil index) = %q, want empty", got)
+	}
+}
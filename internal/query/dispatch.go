@@ -0,0 +1,102 @@
+package query
+
+import (
+	"context"
+
+	"ckb/internal/backends"
+)
+
+const (
+	// DispatchDynamic marks a reference inferred through an interface method
+	// the target implements; the call may resolve to another implementation.
+	DispatchDynamic = "dynamic"
+
+	// dynamicDispatchConfidence is the confidence of an inferred reference.
+	dynamicDispatchConfidence = 0.5
+
+	// maxDynamicDispatchRefs caps inferred references per query so a widely
+	// implemented interface doesn't drown out the direct references.
+	maxDynamicDispatchRefs = 50
+)
+
+// DynamicDispatchInfo summarizes references inferred through interfaces.
+type DynamicDispatchInfo struct {
+	Via           []string `json:"via"` // Interface methods the symbol implements
+	InferredCount int      `json:"inferredCount"`
+	Capped        bool     `json:"capped"`
+	Note          string   `json:"note"`
+}
+
+// dynamicDispatchReferences returns call sites that target the interface
+// methods symbolId implements. SCIP records calls through an interface
+// against the interface method, so these are invisible to a direct lookup
+// on the concrete method.
+func (e *Engine) dynamicDispatchReferences(ctx context.Context, symbolId string, opts FindReferencesOptions) ([]ReferenceInfo, *DynamicDispatchInfo) {
+	if e.scipAdapter == nil || !e.scipAdapter.IsAvailable() {
+		return nil, nil
+	}
+
+	via := e.scipAdapter.ImplementedSymbols(symbolId)
+	if len(via) == 0 {
+		return nil, nil
+	}
+
+	canon := newPathCanonicalizer(e.repoRoot)
+	var inferred []ReferenceInfo
+	for _, iface := range via {
+		result, err := e.scipAdapter.FindReferences(ctx, iface, backends.RefOptions{
+			MaxResults:   maxDynamicDispatchRefs + 1,
+			IncludeTests: opts.IncludeTests,
+			Scope:        backendScope(opts.Scope),
+		})
+		if err != nil || result == nil {
+			continue
+		}
+		inferred = append(inferred, labelDynamicReferences(result.References, iface, canon)...)
+	}
+
+	inferred, capped := capDynamicReferences(inferred, maxDynamicDispatchRefs)
+	return inferred, &DynamicDispatchInfo{
+		Via:           via,
+		InferredCount: len(inferred),
+		Capped:        capped,
+		Note:          "Inferred references call an interface method this symbol implements; the runtime target may be another implementation",
+	}
+}
+
+// labelDynamicReferences converts references to an interface method into
+// inferred references to one of its implementations.
+func labelDynamicReferences(refs []backends.Reference, via string, canon *pathCanonicalizer) []ReferenceInfo {
+	labeled := make([]ReferenceInfo, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Kind == "definition" {
+			continue
+		}
+		labeled = append(labeled, ReferenceInfo{
+			Location: &LocationInfo{
+				FileId:      canon.resolve(ref.Location.Path),
+				StartLine:   ref.Location.Line,
+				StartColumn: ref.Location.Column,
+				EndLine:     ref.Location.EndLine,
+				EndColumn:   ref.Location.EndColumn,
+			},
+			Kind:       ref.Kind,
+			Context:    ref.Context,
+			Dispatch:   DispatchDynamic,
+			Confidence: dynamicDispatchConfidence,
+			Via:        via,
+		})
+	}
+	return labeled
+}
+
+// capDynamicReferences keeps the first max inferred references in a
+// deterministic order and reports whether any were dropped.
+func capDynamicReferences(refs []ReferenceInfo, max int) ([]ReferenceInfo, bool) {
+	refs = deduplicateReferences(refs)
+	sortReferences(refs)
+	if len(refs) <= max {
+		return refs, false
+	}
+	return refs[:max], true
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	"ckb/internal/backends"
+)
+
+func TestLabelDynamicReferences(t *testing.T) {
+	const via = "scip-go gomod ex . Handler#Serve()."
+	refs := []backends.Reference{
+		{Location: backends.Location{Path: "api/handler.go", Line: 4, Column: 2}, Kind: "definition"},
+		{Location: backends.Location{Path: "server/server.go", Line: 20, Column: 5}, Kind: "call", Context: "h.Serve(w, r)"},
+	}
+
+	labeled := labelDynamicReferences(refs, via, newPathCanonicalizer(""))
+	if len(labeled) != 1 {
+		t.Fatalf("expected interface definition to be skipped, got %d refs", len(labeled))
+	}
+	ref := labeled[0]
+	if ref.Dispatch != DispatchDynamic || ref.Via != via {
+		t.Errorf("reference not labeled: dispatch=%q via=%q", ref.Dispatch, ref.Via)
+	}
+	if ref.Confidence <= 0 || ref.Confidence >= 1 {
+		t.Errorf("confidence = %v, want a reduced value", ref.Confidence)
+	}
+	if ref.Location.FileId != "server/server.go" || ref.Location.StartLine != 20 || ref.Context != "h.Serve(w, r)" {
+		t.Errorf("unexpected reference %+v", ref)
+	}
+}
+
+func TestCapDynamicReferences(t *testing.T) {
+	var refs []ReferenceInfo
+	for i := 5; i > 0; i-- {
+		refs = append(refs, ReferenceInfo{
+			Location: &LocationInfo{FileId: fmt.Sprintf("f%d.go", i), StartLine: 1},
+			Dispatch: DispatchDynamic,
+		})
+	}
+	// Two interfaces may share a call site
+	refs = append(refs, ReferenceInfo{
+		Location: &LocationInfo{FileId: "f1.go", StartLine: 1},
+		Dispatch: DispatchDynamic,
+	})
+
+	got, capped := capDynamicReferences(refs, 3)
+	if !capped || len(got) != 3 {
+		t.Fatalf("capDynamicReferences() returned %d refs, capped=%v; want 3, true", len(got), capped)
+	}
+	if got[0].Location.FileId != "f1.go" || got[2].Location.FileId != "f3.go" {
+		t.Errorf("capped refs not in deterministic order: %s..%s", got[0].Location.FileId, got[2].Location.FileId)
+	}
+
+	got, capped = capDynamicReferences(refs[:2], 3)
+	if capped || len(got) != 2 {
+		t.Errorf("under the cap: got %d refs, capped=%v", len(got), capped)
+	}
+}
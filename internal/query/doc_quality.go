@@ -0,0 +1,211 @@
+package query
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"ckb/internal/backends"
+)
+
+// Doc quality levels
+const (
+	DocQualityMissing = "missing"
+	DocQualityPoor    = "poor"
+	DocQualityGood    = "good"
+)
+
+// Scores below this are reported as poorly documented.
+const poorDocScoreThreshold = 0.6
+
+// A doc comment with at least this many words gets full length credit.
+const fullDocWordCount = 12
+
+// SymbolDocCoverage summarizes doc-comment quality for public symbols.
+type SymbolDocCoverage struct {
+	PublicSymbols    int              `json:"publicSymbols"`
+	Documented       int              `json:"documented"`
+	PoorlyDocumented int              `json:"poorlyDocumented"`
+	Undocumented     int              `json:"undocumented"`
+	Backlog          []SymbolDocScore `json:"backlog"`
+	Truncated        bool             `json:"truncated,omitempty"`
+}
+
+// SymbolDocScore is the documentation assessment of a single symbol.
+type SymbolDocScore struct {
+	SymbolId       string   `json:"symbolId"`
+	Name           string   `json:"name"`
+	Kind           string   `json:"kind"`
+	FileId         string   `json:"fileId"`
+	Line           int      `json:"line,omitempty"`
+	Score          float64  `json:"score"`   // 0 (missing) - 1 (complete)
+	Quality        string   `json:"quality"` // missing, poor, good
+	Issues         []string `json:"issues,omitempty"`
+	ReferenceCount int      `json:"referenceCount"`
+}
+
+// codeFencePattern matches fenced code blocks (SCIP hover text often starts
+// with the symbol's signature in one).
+var codeFencePattern = regexp.MustCompile("(?s)```.*?```")
+
+var (
+	paramDocPattern  = regexp.MustCompile(`(?i)(@param\b|\bparams?\b|\bparameters?\b|\bargs?:|\barguments?\b|:param\b)`)
+	returnDocPattern = regexp.MustCompile(`(?i)(@returns?\b|\breturns?\b|\byields?\b|:rtype\b)`)
+)
+
+// scoreDocumentation rates a doc comment between 0 and 1. Every documented
+// symbol gets base credit, plus credit for length; for callables part of
+// the length credit is replaced by describing parameters and returns.
+func scoreDocumentation(doc, kind string) (float64, []string) {
+	text := strings.TrimSpace(codeFencePattern.ReplaceAllString(doc, ""))
+	if text == "" {
+		return 0, []string{"no-doc"}
+	}
+
+	var issues []string
+	words := len(strings.Fields(text))
+	lengthScore := float64(words) / fullDocWordCount
+	if lengthScore > 1 {
+		lengthScore = 1
+	}
+	if lengthScore < 0.5 {
+		issues = append(issues, "too-short")
+	}
+
+	if !isCallableKind(kind) {
+		return 0.4 + 0.6*lengthScore, issues
+	}
+
+	score := 0.4 + 0.3*lengthScore
+	if paramDocPattern.MatchString(text) {
+		score += 0.15
+	} else {
+		issues = append(issues, "no-params")
+	}
+	if returnDocPattern.MatchString(text) {
+		score += 0.15
+	} else {
+		issues = append(issues, "no-returns")
+	}
+	return score, issues
+}
+
+func isCallableKind(kind string) bool {
+	switch strings.ToLower(kind) {
+	case "function", "method", "constructor":
+		return true
+	}
+	return false
+}
+
+// classifyDocQuality maps a documentation score to a quality level.
+func classifyDocQuality(score float64) string {
+	switch {
+	case score == 0:
+		return DocQualityMissing
+	case score < poorDocScoreThreshold:
+		return DocQualityPoor
+	default:
+		return DocQualityGood
+	}
+}
+
+// symbolDocCoverage scores doc comments of public SCIP symbols in scope and
+// returns the undocumented and poorly documented ones, most-referenced first.
+// Symbols defined in test or generated files are skipped unless requested,
+// as are test-only exports; skipped symbols count toward neither the totals
+// nor the backlog.
+func (e *Engine) symbolDocCoverage(ctx context.Context, opts DocCoverageOptions) *SymbolDocCoverage {
+	coverage := &SymbolDocCoverage{Backlog: []SymbolDocScore{}}
+	scope := strings.Trim(strings.TrimPrefix(opts.Scope, "./"), "/")
+
+	var candidates []SymbolDocScore
+	for _, info := range e.scipAdapter.AllSymbols() {
+		sym, err := e.scipAdapter.GetSymbol(ctx, info.Symbol)
+		if err != nil || sym == nil || sym.Location.Path == "" {
+			continue
+		}
+		if !strings.EqualFold(sym.Visibility, "public") {
+			continue
+		}
+		switch strings.ToLower(sym.Kind) {
+		case "parameter", "local", "unknown":
+			continue
+		}
+		path := sym.Location.Path
+		if scope != "" && path != scope && !strings.HasPrefix(path, scope+"/") {
+			continue
+		}
+		if isTestFilePath(path) {
+			continue
+		}
+		if !opts.IncludeGenerated && isGeneratedFilePath(path) {
+			continue
+		}
+		if !opts.IncludeTestOnly && e.isTestOnlySymbol(ctx, sym.StableID) {
+			continue
+		}
+
+		coverage.PublicSymbols++
+		score, issues := scoreDocumentation(sym.Documentation, sym.Kind)
+		quality := classifyDocQuality(score)
+		switch quality {
+		case DocQualityMissing:
+			coverage.Undocumented++
+		case DocQualityPoor:
+			coverage.PoorlyDocumented++
+		default:
+			coverage.Documented++
+			continue
+		}
+
+		candidates = append(candidates, SymbolDocScore{
+			SymbolId:       sym.StableID,
+			Name:           sym.Name,
+			Kind:           sym.Kind,
+			FileId:         path,
+			Line:           sym.Location.Line,
+			Score:          score,
+			Quality:        quality,
+			Issues:         issues,
+			ReferenceCount: e.scipAdapter.GetReferenceCount(sym.StableID),
+		})
+	}
+
+	// Most-used first; worst docs first among equals
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].ReferenceCount != candidates[j].ReferenceCount {
+			return candidates[i].ReferenceCount > candidates[j].ReferenceCount
+		}
+		if !floatEqual(candidates[i].Score, candidates[j].Score) {
+			return candidates[i].Score < candidates[j].Score
+		}
+		return candidates[i].SymbolId < candidates[j].SymbolId
+	})
+
+	if len(candidates) > opts.Limit {
+		candidates = candidates[:opts.Limit]
+		coverage.Truncated = true
+	}
+	coverage.Backlog = append(coverage.Backlog, candidates...)
+
+	return coverage
+}
+
+// isTestOnlySymbol reports whether all references to a public symbol come
+// from test files.
+func (e *Engine) isTestOnlySymbol(ctx context.Context, symbolId string) bool {
+	refs, err := e.scipAdapter.FindReferences(ctx, symbolId, backends.RefOptions{
+		IncludeTests: true,
+		MaxResults:   200,
+	})
+	if err != nil || refs == nil {
+		return false
+	}
+	paths := make([]string, 0, len(refs.References))
+	for _, ref := range refs.References {
+		paths = append(paths, ref.Location.Path)
+	}
+	return isTestOnlyExport(&VisibilityInfo{Visibility: "public"}, paths)
+}
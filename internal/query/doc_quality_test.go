@@ -0,0 +1,58 @@
+package query
+
+import (
+	"testing"
+)
+
+func TestScoreDocumentation(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         string
+		kind        string
+		wantQuality string
+		wantIssue   string
+	}{
+		{"empty", "", "function", DocQualityMissing, "no-doc"},
+		{"signature only", "```go\nfunc Foo(a int) error\n```", "function", DocQualityMissing, "no-doc"},
+		{"terse function", "Foo does foo.", "function", DocQualityPoor, "too-short"},
+		{
+			"complete function",
+			"Foo resolves the symbol identified by id against the loaded index. The id param must be a stable ID. Returns an error when the index is missing.",
+			"function", DocQualityGood, "",
+		},
+		{
+			"long function without returns",
+			"Foo resolves the symbol identified by id against the loaded index and caches the result for later lookups by other callers.",
+			"function", DocQualityGood, "no-returns",
+		},
+		{"terse type", "Config holds settings.", "class", DocQualityPoor, "too-short"},
+		{
+			"documented type",
+			"Config holds the repository-level settings loaded from .ckb/config.json, with defaults applied for missing fields.",
+			"class", DocQualityGood, "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, issues := scoreDocumentation(tt.doc, tt.kind)
+			if got := classifyDocQuality(score); got != tt.wantQuality {
+				t.Errorf("quality = %q (score %.2f), want %q", got, score, tt.wantQuality)
+			}
+			if score < 0 || score > 1 {
+				t.Errorf("score %.2f out of range", score)
+			}
+			if tt.wantIssue != "" {
+				found := false
+				for _, issue := range issues {
+					if issue == tt.wantIssue {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("issues = %v, want %q", issues, tt.wantIssue)
+				}
+			}
+		})
+	}
+}
@@ -91,8 +91,34 @@ func (e *Engine) CheckAllDocsStaleness() ([]docs.StalenessReport, error) {
 	return checker.CheckAllDocuments()
 }
 
-// GetDocCoverage returns documentation coverage statistics.
-func (e *Engine) GetDocCoverage(exportedOnly bool, topN int) (*docs.CoverageReport, error) {
+// DocCoverageOptions controls getDocCoverage.
+type DocCoverageOptions struct {
+	ExportedOnly     bool   // Only count exported symbols in indexed-doc coverage
+	TopN             int    // Top undocumented symbols from indexed docs (default: 10)
+	Scope            string // Path prefix for the doc-comment backlog
+	Limit            int    // Max symbols in the doc-comment backlog (default: 50)
+	IncludeGenerated bool   // Include symbols from generated files in the backlog
+	IncludeTestOnly  bool   // Count test-only exports and include them in the backlog
+}
+
+// DocCoverageResponse combines indexed-doc coverage with doc-comment quality.
+type DocCoverageResponse struct {
+	*docs.CoverageReport
+	SymbolDocs  *SymbolDocCoverage `json:"symbolDocs,omitempty"`
+	Limitations []string           `json:"limitations,omitempty"`
+}
+
+// GetDocCoverage returns documentation coverage statistics: how many symbols
+// indexed docs reference, and a prioritized backlog of public symbols whose
+// doc comments are missing or poor.
+func (e *Engine) GetDocCoverage(ctx context.Context, opts DocCoverageOptions) (*DocCoverageResponse, error) {
+	if opts.TopN <= 0 {
+		opts.TopN = 10
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 50
+	}
+
 	store := docs.NewStore(e.db)
 	symbolIndex := &scipSymbolIndex{engine: e}
 
@@ -100,7 +126,19 @@ func (e *Engine) GetDocCoverage(exportedOnly bool, topN int) (*docs.CoverageRepo
 
 	// For now, pass nil lister (basic stats only)
 	// Full coverage analysis requires symbol listing which is heavy
-	return analyzer.Analyze(nil, exportedOnly, topN)
+	report, err := analyzer.Analyze(nil, opts.ExportedOnly, opts.TopN)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DocCoverageResponse{CoverageReport: report}
+	if e.scipAdapter == nil || !e.scipAdapter.IsAvailable() {
+		resp.Limitations = append(resp.Limitations, "SCIP index unavailable; doc-comment quality not analyzed")
+		return resp, nil
+	}
+	resp.SymbolDocs = e.symbolDocCoverage(ctx, opts)
+
+	return resp, nil
 }
 
 // rebuildSuffixIndex rebuilds the suffix index from SCIP symbols.
@@ -6,13 +6,16 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
+	"ckb/internal/architecture"
 	"ckb/internal/backends"
 	"ckb/internal/backends/git"
 	"ckb/internal/backends/lsp"
 	"ckb/internal/backends/scip"
+	"ckb/internal/backends/treesitter"
 	"ckb/internal/compression"
 	"ckb/internal/config"
 	"ckb/internal/errors"
@@ -20,9 +23,9 @@ import (
 	"ckb/internal/identity"
 	"ckb/internal/jobs"
 	"ckb/internal/logging"
+	"ckb/internal/modules"
 	"ckb/internal/output"
 	"ckb/internal/storage"
-	"ckb/internal/symbols"
 	"ckb/internal/tier"
 )
 
@@ -37,10 +40,11 @@ type Engine struct {
 	cache      *storage.Cache
 
 	// Backend references
-	orchestrator  *backends.Orchestrator
-	scipAdapter   *scip.SCIPAdapter
-	gitAdapter    *git.GitAdapter
-	lspSupervisor *lsp.LspSupervisor
+	orchestrator      *backends.Orchestrator
+	scipAdapter       *scip.SCIPAdapter
+	gitAdapter        *git.GitAdapter
+	lspSupervisor     *lsp.LspSupervisor
+	treesitterAdapter *treesitter.TreesitterAdapter
 
 	// Job runner for async operations
 	jobStore  *jobs.Store
@@ -49,9 +53,6 @@ type Engine struct {
 	// Complexity analyzer for hotspots
 	complexityAnalyzer *hotspots.ComplexityAnalyzer
 
-	// Tree-sitter symbol extractor for fallback search
-	treesitterExtractor *symbols.Extractor
-
 	// Tier detector for capability gating
 	tierDetector *tier.Detector
 
@@ -64,6 +65,30 @@ type Engine struct {
 	cacheStatsMu sync.RWMutex
 	cacheHits    int64
 	cacheMisses  int64
+
+	// Detected module roots, loaded on first use
+	moduleRootsMu sync.Mutex
+	moduleRoots   []string
+
+	// Declared apiSymbols, loaded on first use and dropped by AnnotateModule
+	declaredAPIMu     sync.Mutex
+	declaredAPI       *declaredAPI
+	declaredAPILoaded bool
+
+	// Architecture views, shared by getArchitecture and the tools built on it
+	archCache *architecture.ArchitectureCache
+
+	// SCIP call graphs, shared by traceUsage, getCallGraph and explainSymbol
+	callGraphCache *callGraphCache
+
+	// analyzeImpact results, reused when a caller passes back their digest
+	impactCache *impactResultCache
+
+	// Automatic reindexing: the running job and the repo state it was
+	// started for
+	reindexMu    sync.Mutex
+	reindexJobID string
+	reindexState string
 }
 
 // RepoState represents the current state of the repository.
@@ -86,6 +111,7 @@ func NewEngine(repoRoot string, db *storage.DB, logger *logging.Logger, cfg *con
 
 	// Create identity resolver
 	resolver := identity.NewIdentityResolver(db, logger)
+	resolver.SetFingerprintFallback(cfg.Identity.FingerprintFallback)
 
 	// Create orchestrator
 	policy := backends.LoadQueryPolicy(cfg)
@@ -94,24 +120,20 @@ func NewEngine(repoRoot string, db *storage.DB, logger *logging.Logger, cfg *con
 	// Create cache
 	cache := storage.NewCache(db)
 
-	// Initialize tree-sitter extractor if available
-	var tsExtractor *symbols.Extractor
-	if symbols.IsAvailable() {
-		tsExtractor = symbols.NewExtractor()
-	}
-
 	engine := &Engine{
-		db:                  db,
-		logger:              logger,
-		config:              cfg,
-		compressor:          compressor,
-		resolver:            resolver,
-		repoRoot:            repoRoot,
-		orchestrator:        orchestrator,
-		cache:               cache,
-		complexityAnalyzer:  hotspots.NewComplexityAnalyzer(),
-		treesitterExtractor: tsExtractor,
-		tierDetector:        tier.NewDetector(),
+		db:                 db,
+		logger:             logger,
+		config:             cfg,
+		compressor:         compressor,
+		resolver:           resolver,
+		repoRoot:           repoRoot,
+		orchestrator:       orchestrator,
+		cache:              cache,
+		complexityAnalyzer: hotspots.NewComplexityAnalyzer(),
+		tierDetector:       tier.NewDetector(),
+		archCache:          architecture.NewArchitectureCache(architecture.DefaultCacheEntries),
+		callGraphCache:     newCallGraphCache(cfg.Cache.CallGraphEntries),
+		impactCache:        newImpactResultCache(defaultImpactCacheEntries),
 	}
 
 	// Initialize backends
@@ -196,6 +218,18 @@ func (e *Engine) registerJobHandlers() {
 
 		return result, nil
 	})
+
+	// Reindex handler, submitted by AutoReindex and RefreshStaleIndex
+	e.jobRunner.RegisterHandler(jobs.JobTypeReindex, func(ctx context.Context, job *jobs.Job, progress func(int)) (interface{}, error) {
+		scope, err := jobs.ParseReindexScope(job.Scope)
+		if err != nil {
+			return nil, err
+		}
+		if scope == nil {
+			return nil, fmt.Errorf("reindex job has no scope")
+		}
+		return e.runReindex(ctx, scope, progress)
+	})
 }
 
 // initializeBackends initializes all configured backends.
@@ -245,6 +279,13 @@ func (e *Engine) initializeBackends(cfg *config.Config) error {
 		e.lspSupervisor = lsp.NewLspSupervisor(cfg, e.logger)
 	}
 
+	// Tree-sitter parses files on demand, so symbol queries still work
+	// without any index
+	if tsAdapter := treesitter.NewTreesitterAdapter(e.repoRoot, e.logger); tsAdapter.IsAvailable() {
+		e.treesitterAdapter = tsAdapter
+		e.orchestrator.RegisterBackend(tsAdapter)
+	}
+
 	return lastErr
 }
 
@@ -348,10 +389,15 @@ type Provenance struct {
 	Backends        []BackendContribution `json:"backends"`
 	Completeness    CompletenessInfo      `json:"completeness"`
 	CachedAt        string                `json:"cachedAt,omitempty"`
+	FromCache       bool                  `json:"fromCache,omitempty"` // Served from a stored result without re-running the query
 	QueryDurationMs int64                 `json:"queryDurationMs"`
-	Warnings        []string              `json:"warnings,omitempty"`
+	Warnings        []output.Warning      `json:"warnings,omitempty"`
 	Timeouts        []string              `json:"timeouts,omitempty"`
 	Truncations     []string              `json:"truncations,omitempty"`
+
+	// Extra holds operator-supplied metadata from config or context. It is
+	// kept last and excluded from snapshot comparisons.
+	Extra map[string]interface{} `json:"extra,omitempty"`
 }
 
 // BackendContribution describes a backend's contribution to a response.
@@ -373,13 +419,14 @@ type CompletenessInfo struct {
 
 // buildProvenance creates provenance metadata for a response.
 func (e *Engine) buildProvenance(
+	ctx context.Context,
 	repoState *RepoState,
 	mode string,
 	startTime time.Time,
 	contributions []BackendContribution,
 	completeness CompletenessInfo,
 ) *Provenance {
-	var warnings []string
+	var warnings []output.Warning
 	var timeouts []string
 
 	return &Provenance{
@@ -391,6 +438,7 @@ func (e *Engine) buildProvenance(
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
 		Warnings:        warnings,
 		Timeouts:        timeouts,
+		Extra:           e.provenanceExtra(ctx),
 	}
 }
 
@@ -428,6 +476,35 @@ func (e *Engine) generateDrilldowns(
 	return compression.GenerateDrilldowns(ctx)
 }
 
+// traversalConfig returns the configured traversal depth limits.
+func (e *Engine) traversalConfig() config.TraversalConfig {
+	if e.config == nil {
+		return config.TraversalConfig{}
+	}
+	return e.config.Traversal
+}
+
+// withDepthClamp records that a requested depth was clamped to the configured
+// maximum. If the response is already truncated for another reason, the
+// clamp is added as a note; otherwise a "max-depth" truncation is returned.
+func withDepthClamp(truncation *TruncationInfo, requested, max int) *TruncationInfo {
+	note := fmt.Sprintf("requested depth %d exceeds configured max %d; clamped", requested, max)
+	if truncation != nil {
+		if truncation.Note != "" {
+			truncation.Note += "; " + note
+		} else {
+			truncation.Note = note
+		}
+		return truncation
+	}
+	return &TruncationInfo{
+		Reason:        "max-depth",
+		OriginalCount: requested,
+		ReturnedCount: max,
+		Note:          note,
+	}
+}
+
 // wrapError converts an error to a CKB error with suggestions.
 func (e *Engine) wrapError(err error, code errors.ErrorCode) *errors.CkbError {
 	if ckbErr, ok := err.(*errors.CkbError); ok {
@@ -548,8 +625,54 @@ func (e *Engine) GetDB() *storage.DB {
 	return e.db
 }
 
-// ClearAllCache clears all cache entries (query, view, and negative caches).
+// ModuleRoots returns the repo-relative root paths of the detected modules.
+// Detection runs once per engine; ClearAllCache forces it to run again.
+func (e *Engine) ModuleRoots() []string {
+	e.moduleRootsMu.Lock()
+	defer e.moduleRootsMu.Unlock()
+
+	if e.moduleRoots != nil {
+		return e.moduleRoots
+	}
+
+	var roots, ignore []string
+	if e.config != nil {
+		roots, ignore = e.config.Modules.Roots, e.config.Modules.Ignore
+	}
+	result, err := modules.DetectModules(e.repoRoot, roots, ignore, "", e.logger)
+	if err != nil {
+		e.logger.Warn("Module detection failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	e.moduleRoots = make([]string, 0, len(result.Modules))
+	for _, m := range result.Modules {
+		e.moduleRoots = append(e.moduleRoots, m.RootPath)
+	}
+	sort.Strings(e.moduleRoots)
+	return e.moduleRoots
+}
+
+// ClearAllCache clears all cache entries (query, view, and negative caches,
+// and the in-memory caches built from the index).
 func (e *Engine) ClearAllCache() error {
+	e.moduleRootsMu.Lock()
+	e.moduleRoots = nil
+	e.moduleRootsMu.Unlock()
+
+	e.invalidateDeclaredAPI()
+
+	// Call graphs and impact results are keyed by repo state alone; after
+	// a reindex at the same state they would still come from the old index
+	if e.callGraphCache != nil {
+		e.callGraphCache.clear()
+	}
+	if e.impactCache != nil {
+		e.impactCache.clear()
+	}
+
 	if e.cache == nil {
 		return nil
 	}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"ckb/internal/config"
+	"ckb/internal/errors"
+	"ckb/internal/impact"
 	"ckb/internal/logging"
 	"ckb/internal/storage"
 )
@@ -337,6 +341,151 @@ func TestAnalyzeImpact(t *testing.T) {
 	})
 }
 
+func TestAnalyzeImpactFromRefs(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	t.Run("nil symbol", func(t *testing.T) {
+		if _, err := engine.AnalyzeImpactFromRefs(ctx, nil, nil, AnalyzeImpactOptions{}); err == nil {
+			t.Error("expected error for nil symbol")
+		}
+	})
+
+	t.Run("external references", func(t *testing.T) {
+		symbol := &SymbolInfo{
+			StableId: "ckb:repo:sym:target",
+			Name:     "Target",
+			Kind:     "function",
+			ModuleId: "internal/core",
+			Visibility: &VisibilityInfo{
+				Visibility: "public",
+				Confidence: 0.9,
+				Source:     "grep",
+			},
+		}
+		refs := []impact.Reference{
+			{
+				Kind:       impact.RefCall,
+				Location:   &impact.Location{FileId: "internal/api/handler.go", StartLine: 10},
+				FromSymbol: "ckb:repo:sym:handler",
+				FromModule: "internal/api",
+			},
+			{
+				Kind:       impact.RefCall,
+				Location:   &impact.Location{FileId: "internal/core/target_test.go", StartLine: 5},
+				FromSymbol: "ckb:repo:sym:testTarget",
+				FromModule: "internal/core",
+			},
+		}
+
+		result, err := engine.AnalyzeImpactFromRefs(ctx, symbol, refs, AnalyzeImpactOptions{Depth: 2})
+		if err != nil {
+			t.Fatalf("AnalyzeImpactFromRefs: %v", err)
+		}
+
+		// The test reference is dropped since tests weren't requested
+		if len(result.DirectImpact) != 1 || result.DirectImpact[0].StableId != "ckb:repo:sym:handler" {
+			t.Errorf("direct impact = %+v, want only the handler", result.DirectImpact)
+		}
+		if result.RiskScore == nil {
+			t.Error("expected a risk score")
+		}
+		if len(result.ModulesAffected) == 0 {
+			t.Error("expected affected modules")
+		}
+
+		p := result.Provenance
+		if p.Completeness.Reason != "external-references" || p.Completeness.Score >= 1 {
+			t.Errorf("completeness = %+v, want lowered external-references", p.Completeness)
+		}
+		var warned bool
+		for _, w := range p.Warnings {
+			warned = warned || w.Code == WarnAnalysisLimited
+		}
+		if !warned {
+			t.Error("expected an external-source warning")
+		}
+		if refs[1].IsTest {
+			t.Error("caller references were modified")
+		}
+	})
+
+	t.Run("change kinds", func(t *testing.T) {
+		symbol := &SymbolInfo{StableId: "ckb:repo:sym:helper", Name: "Helper", Kind: "function", ModuleId: "internal/core"}
+		refs := []impact.Reference{
+			{
+				Kind:       impact.RefType,
+				Location:   &impact.Location{FileId: "internal/api/types.go", StartLine: 3},
+				FromSymbol: "ckb:repo:sym:holder",
+				FromModule: "internal/api",
+			},
+		}
+
+		deleted, err := engine.AnalyzeImpactFromRefs(ctx, symbol, refs, AnalyzeImpactOptions{ChangeKind: "delete", OnlyBreakingChanges: true})
+		if err != nil {
+			t.Fatalf("AnalyzeImpactFromRefs: %v", err)
+		}
+		if deleted.ChangeKind != "delete" || len(deleted.DirectImpact) != 1 || !deleted.DirectImpact[0].Breaking {
+			t.Errorf("expected the type dependency as breaking impact of a delete, got %+v", deleted.DirectImpact)
+		}
+
+		renamed, err := engine.AnalyzeImpactFromRefs(ctx, symbol, refs, AnalyzeImpactOptions{ChangeKind: "rename", NewName: "Assist"})
+		if err != nil {
+			t.Fatalf("AnalyzeImpactFromRefs: %v", err)
+		}
+		if renamed.NewName != "Assist" || len(renamed.DirectImpact) != 1 || renamed.DirectImpact[0].UpdateKind != impact.UpdateMechanical {
+			t.Errorf("expected a mechanical rename update, got %+v", renamed.DirectImpact)
+		}
+
+		for _, opts := range []AnalyzeImpactOptions{{ChangeKind: "remove"}, {NewName: "Assist"}} {
+			_, err := engine.AnalyzeImpactFromRefs(ctx, symbol, refs, opts)
+			ckbErr, ok := err.(*errors.CkbError)
+			if !ok || ckbErr.Code != errors.InvalidParameters {
+				t.Errorf("%+v: expected InvalidParameters, got %v", opts, err)
+			}
+		}
+	})
+
+	t.Run("risk thresholds", func(t *testing.T) {
+		symbol := &SymbolInfo{StableId: "ckb:repo:sym:util", Name: "Util", Kind: "function", ModuleId: "internal/core"}
+		refs := []impact.Reference{
+			{
+				Kind:       impact.RefCall,
+				Location:   &impact.Location{FileId: "internal/api/handler.go", StartLine: 7},
+				FromSymbol: "ckb:repo:sym:handler",
+				FromModule: "internal/api",
+			},
+		}
+
+		defaults, err := engine.AnalyzeImpactFromRefs(ctx, symbol, refs, AnalyzeImpactOptions{})
+		if err != nil {
+			t.Fatalf("AnalyzeImpactFromRefs: %v", err)
+		}
+		if want := (impact.RiskThresholds{MediumMin: 0.4, HighMin: 0.7}); defaults.RiskThresholds == nil || *defaults.RiskThresholds != want {
+			t.Errorf("thresholds = %+v, want %+v", defaults.RiskThresholds, want)
+		}
+
+		// Configured thresholds relevel the same score and are echoed
+		score := defaults.RiskScore.Score
+		original := engine.config.RiskThresholds
+		defer func() { engine.config.RiskThresholds = original }()
+		engine.config.RiskThresholds = impact.RiskThresholds{MediumMin: score / 4, HighMin: score / 2, CriticalMin: score}
+
+		strict, err := engine.AnalyzeImpactFromRefs(ctx, symbol, refs, AnalyzeImpactOptions{})
+		if err != nil {
+			t.Fatalf("AnalyzeImpactFromRefs: %v", err)
+		}
+		if strict.RiskScore.Level != "critical" || strict.RiskScore.Score != score {
+			t.Errorf("risk = %s (%v), want critical at the unchanged score %v", strict.RiskScore.Level, strict.RiskScore.Score, score)
+		}
+		if strict.RiskThresholds == nil || strict.RiskThresholds.CriticalMin != score {
+			t.Errorf("thresholds = %+v, want the configured ones", strict.RiskThresholds)
+		}
+	})
+}
+
 func TestGenerateFixScript(t *testing.T) {
 	engine, cleanup := testEngine(t)
 	defer cleanup()
@@ -718,6 +867,23 @@ func TestRecentlyRelevant(t *testing.T) {
 		// Should not panic
 		_, _ = engine.RecentlyRelevant(ctx, opts)
 	})
+
+	t.Run("commit range validation", func(t *testing.T) {
+		_, err := engine.RecentlyRelevant(ctx, RecentlyRelevantOptions{
+			CommitRange: &CommitRangeSelector{Base: "v1.2", Head: "v1.3"},
+			TimeWindow:  &TimeWindowSelector{Start: "2024-01-01"},
+		})
+		if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+			t.Errorf("expected a mutually exclusive error, got %v", err)
+		}
+
+		_, err = engine.RecentlyRelevant(ctx, RecentlyRelevantOptions{
+			CommitRange: &CommitRangeSelector{Base: "v1.2"},
+		})
+		if err == nil || !strings.Contains(err.Error(), "base and head") {
+			t.Errorf("expected a missing head error, got %v", err)
+		}
+	})
 }
 
 // =============================================================================
@@ -848,3 +1014,81 @@ func TestGetModuleOverview(t *testing.T) {
 		_, _ = engine.GetModuleOverview(ctx, opts)
 	})
 }
+
+func TestWithDepthClamp(t *testing.T) {
+	t.Run("creates max-depth truncation", func(t *testing.T) {
+		got := withDepthClamp(nil, 9, 4)
+		if got.Reason != "max-depth" {
+			t.Errorf("Reason = %q, want %q", got.Reason, "max-depth")
+		}
+		if got.OriginalCount != 9 || got.ReturnedCount != 4 {
+			t.Errorf("counts = (%d, %d), want (9, 4)", got.OriginalCount, got.ReturnedCount)
+		}
+		if got.Note == "" {
+			t.Error("expected a note describing the clamp")
+		}
+	})
+
+	t.Run("keeps existing truncation reason", func(t *testing.T) {
+		existing := &TruncationInfo{Reason: "max-nodes", OriginalCount: 120, ReturnedCount: 100}
+		got := withDepthClamp(existing, 9, 4)
+		if got.Reason != "max-nodes" {
+			t.Errorf("Reason = %q, want %q", got.Reason, "max-nodes")
+		}
+		if got.Note == "" {
+			t.Error("expected clamp note on existing truncation")
+		}
+	})
+}
+
+func TestTraversalConfigFromEngine(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	engine.config.Traversal.CallGraph = config.DepthLimits{Default: 2, Max: 3}
+	limits := engine.traversalConfig().CallGraphLimits()
+	if depth, clamped := limits.Resolve(10); depth != 3 || !clamped {
+		t.Errorf("Resolve(10) = (%d, %v), want (3, true)", depth, clamped)
+	}
+}
+
+func TestProvenanceExtra(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if extra := engine.provenanceExtra(context.Background()); extra != nil {
+		t.Errorf("expected no extras by default, got %v", extra)
+	}
+
+	engine.config.ProvenanceExtra = map[string]interface{}{
+		"environment": "staging",
+		"tenant":      "acme",
+	}
+	ctx := WithProvenanceExtra(context.Background(), map[string]interface{}{"indexBuildId": "b-42"})
+	ctx = WithProvenanceExtra(ctx, map[string]interface{}{"environment": "prod"})
+
+	extra := engine.provenanceExtra(ctx)
+	want := map[string]interface{}{
+		"environment":  "prod",
+		"tenant":       "acme",
+		"indexBuildId": "b-42",
+	}
+	if len(extra) != len(want) {
+		t.Fatalf("expected %d extras, got %v", len(want), extra)
+	}
+	for k, v := range want {
+		if extra[k] != v {
+			t.Errorf("extra[%q] = %v, want %v", k, extra[k], v)
+		}
+	}
+
+	// Merging must not mutate the configured map
+	if engine.config.ProvenanceExtra["environment"] != "staging" {
+		t.Error("config extras were modified")
+	}
+
+	prov := engine.buildProvenance(ctx, &RepoState{RepoStateId: "abc"}, "head", time.Now(), nil, CompletenessInfo{})
+	if prov.Extra["tenant"] != "acme" {
+		t.Errorf("buildProvenance did not attach extras: %v", prov.Extra)
+	}
+}
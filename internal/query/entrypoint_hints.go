@@ -0,0 +1,89 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"ckb/internal/config"
+)
+
+// entrypointHintBoost is added to the ranking score of an entrypoint found
+// in a directory declared for its type.
+const entrypointHintBoost = 25
+
+// entrypointPathHints returns the built-in and configured directory hints
+// for an entrypoint type.
+func (e *Engine) entrypointPathHints(entrypointType string) []string {
+	if e.config == nil {
+		return config.EntrypointsConfig{}.PathHintsFor(entrypointType)
+	}
+	return e.config.Entrypoints.PathHintsFor(entrypointType)
+}
+
+// entrypointFanOutLimit returns the fan-out above which entrypoints are
+// flagged: the per-call override when set, else the configured limit.
+func (e *Engine) entrypointFanOutLimit(override int) int {
+	if override > 0 {
+		return override
+	}
+	if e.config == nil {
+		return config.EntrypointsConfig{}.FanOutLimit()
+	}
+	return e.config.Entrypoints.FanOutLimit()
+}
+
+// highFanOutWarning describes the entrypoints flagged highFanOut, naming
+// up to five of them, or returns "" when none are.
+func highFanOutWarning(entrypoints []EntrypointV52, limit int) string {
+	var names []string
+	for _, ep := range entrypoints {
+		if ep.Ranking != nil && ep.Ranking.Signals["highFanOut"] == true {
+			names = append(names, fmt.Sprintf("%s (%d)", ep.Name, ep.FanOut))
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	count := len(names)
+	if count > 5 {
+		names = append(names[:5], "...")
+	}
+	return fmt.Sprintf("%d entrypoints call more than %d functions and may be orchestration hotspots: %s",
+		count, limit, strings.Join(names, ", "))
+}
+
+// matchEntrypointHint returns the first hint matching filePath. A hint's
+// segments must match a consecutive run of the path's segments, each as a
+// glob; the file name is compared without its extension, so "services/*/main"
+// matches both services/billing/main.go and services/billing/main/app.go.
+func matchEntrypointHint(filePath string, hints []string) (string, bool) {
+	segments := strings.Split(strings.Trim(filePath, "/"), "/")
+	last := segments[len(segments)-1]
+	segments[len(segments)-1] = strings.TrimSuffix(last, path.Ext(last))
+
+	for _, hint := range hints {
+		parts := strings.Split(hint, "/")
+		for i := 0; i+len(parts) <= len(segments); i++ {
+			matched := true
+			for k, part := range parts {
+				if ok, _ := path.Match(part, segments[i+k]); !ok {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return hint, true
+			}
+		}
+	}
+	return "", false
+}
+
+// withPathHint records a matched hint in a detection basis.
+func withPathHint(basis, hint string) string {
+	if basis == "" {
+		return "path-hint:" + hint
+	}
+	return basis + "+path-hint:" + hint
+}
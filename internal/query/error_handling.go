@@ -0,0 +1,152 @@
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RiskErrorHandling is the risk signal type for ignored errors in added code.
+const RiskErrorHandling = "error-handling"
+
+// maxErrorHandlingSignals caps error-handling signals per diff so they
+// don't crowd out the structural ones.
+const maxErrorHandlingSignals = 10
+
+// errorHandlingRule flags an added line that drops an error.
+type errorHandlingRule struct {
+	pattern     *regexp.Regexp
+	severity    string
+	description string
+}
+
+// errorHandlingRules holds the heuristics for each language. Add a
+// language by adding its rules here; files in other languages are skipped.
+var errorHandlingRules = map[string][]errorHandlingRule{
+	"go": {
+		{
+			pattern:     regexp.MustCompile(`^\s*_\s*=\s*[\w.]*\w\(`),
+			severity:    "medium",
+			description: "Result of call discarded with _ =",
+		},
+		{
+			pattern:     regexp.MustCompile(`,\s*_\s*:?=\s*[\w.]*\w\(`),
+			severity:    "medium",
+			description: "Trailing return value (usually the error) discarded with _",
+		},
+		{
+			pattern:     regexp.MustCompile(`^\s*[\w.]+\.(Close|Write|WriteString|Flush|Sync|Encode|Rollback|Commit|Remove|RemoveAll|Rename|Chmod|Setenv)\(.*\)\s*$`),
+			severity:    "low",
+			description: "Return value of a call that can fail is not checked",
+		},
+		{
+			pattern:     regexp.MustCompile(`if\s+err\s*!=\s*nil\s*\{\s*\}`),
+			severity:    "medium",
+			description: "Error check with an empty body swallows the error",
+		},
+	},
+}
+
+// errorHandlingFinding is an added line matching an error-handling rule.
+type errorHandlingFinding struct {
+	Line        int
+	Severity    string
+	Description string
+}
+
+// addedLine is a line added by a diff, numbered in the new file.
+type addedLine struct {
+	Line int
+	Text string
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// parseAddedLines extracts the added lines from a unified diff of one file.
+func parseAddedLines(diff string) []addedLine {
+	var added []addedLine
+	newLine := 0
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		if m := hunkHeader.FindStringSubmatch(line); m != nil {
+			newLine, _ = strconv.Atoi(m[1])
+			inHunk = true
+			continue
+		}
+		if !inHunk {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			added = append(added, addedLine{Line: newLine, Text: line[1:]})
+			newLine++
+		case strings.HasPrefix(line, "-"):
+			// Removed lines don't advance the new file
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		default:
+			newLine++
+		}
+	}
+	return added
+}
+
+// scanErrorHandling applies the language's rules to added lines. Each line
+// is reported at most once, for the first rule it matches.
+func scanErrorHandling(language string, lines []addedLine) []errorHandlingFinding {
+	rules := errorHandlingRules[language]
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var findings []errorHandlingFinding
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l.Text), "//") {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.pattern.MatchString(l.Text) {
+				findings = append(findings, errorHandlingFinding{
+					Line:        l.Line,
+					Severity:    rule.severity,
+					Description: rule.description,
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// errorHandlingSignals scans the added lines of changed non-test files for
+// ignored errors. Only added lines are considered, so existing code is
+// never flagged.
+func (e *Engine) errorHandlingSignals(base, head string, files []DiffFileChange) []DiffRiskSignal {
+	var signals []DiffRiskSignal
+	for _, file := range files {
+		if file.ChangeType == "deleted" || file.Role == "test" {
+			continue
+		}
+		if _, ok := errorHandlingRules[file.Language]; !ok {
+			continue
+		}
+		diff, err := e.gitAdapter.GetFileDiffContent(base, head, file.FilePath)
+		if err != nil {
+			continue
+		}
+		for _, f := range scanErrorHandling(file.Language, parseAddedLines(diff)) {
+			if len(signals) >= maxErrorHandlingSignals {
+				return signals
+			}
+			signals = append(signals, DiffRiskSignal{
+				Type:        RiskErrorHandling,
+				Severity:    f.Severity,
+				FilePath:    file.FilePath,
+				Line:        f.Line,
+				Description: f.Description,
+				Confidence:  0.6,
+			})
+		}
+	}
+	return signals
+}
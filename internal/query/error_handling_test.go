@@ -0,0 +1,68 @@
+package query
+
+import "testing"
+
+func TestParseAddedLines(t *testing.T) {
+	diff := `diff --git a/store.go b/store.go
+index 1111111..2222222 100644
+--- a/store.go
++++ b/store.go
+@@ -10,4 +10,5 @@ func (s *Store) Save() error {
+ 	f, err := os.Create(s.path)
+-	if err != nil { return err }
++	if err != nil {
++		return err
++	}
+ 	defer f.Close()
+@@ -40,2 +41,3 @@ func (s *Store) Flush() {
+ 	s.mu.Lock()
++	_ = s.w.Flush()
+ 	s.mu.Unlock()
+\ No newline at end of file
+`
+	added := parseAddedLines(diff)
+
+	want := []addedLine{
+		{11, "\tif err != nil {"},
+		{12, "\t\treturn err"},
+		{13, "\t}"},
+		{42, "\t_ = s.w.Flush()"},
+	}
+	if len(added) != len(want) {
+		t.Fatalf("parseAddedLines() = %+v, want %+v", added, want)
+	}
+	for i := range want {
+		if added[i] != want[i] {
+			t.Errorf("added[%d] = %+v, want %+v", i, added[i], want[i])
+		}
+	}
+}
+
+func TestScanErrorHandlingGo(t *testing.T) {
+	tests := []struct {
+		line    string
+		flagged bool
+	}{
+		{"\t_ = os.Remove(tmp)", true},
+		{"\tdata, _ := io.ReadAll(r)", true},
+		{"\tf.Close()", true},
+		{"\tif err != nil {}", true},
+		{"\tdefer f.Close()", false},
+		{"\tif err := f.Close(); err != nil {", false},
+		{"\tv, _ := cache[key]", false},
+		{"\ts, _ := v.(string)", false},
+		{"\t// _ = legacy()", false},
+		{"\tfor _, x := range items {", false},
+	}
+
+	for _, tt := range tests {
+		findings := scanErrorHandling("go", []addedLine{{Line: 1, Text: tt.line}})
+		if got := len(findings) > 0; got != tt.flagged {
+			t.Errorf("scanErrorHandling(%q) flagged=%v, want %v", tt.line, got, tt.flagged)
+		}
+	}
+
+	if findings := scanErrorHandling("python", []addedLine{{Line: 1, Text: "_ = f()"}}); findings != nil {
+		t.Errorf("languages without rules should not be scanned, got %+v", findings)
+	}
+}
@@ -0,0 +1,283 @@
+package query
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ckb/internal/backends/git"
+)
+
+// Symbol change states reported by explainFile when a base ref is given
+const (
+	symbolAdded     = "added"
+	symbolModified  = "modified"
+	symbolUnchanged = "unchanged"
+)
+
+// markSymbolChanges sets the Change of each symbol by diffing the file
+// against base. Symbols must be sorted by line. Symbols the hunks mark as
+// modified are then compared by content, so one whose body only moved is
+// unchanged; if either revision can't be read the line-based result stands.
+func (e *Engine) markSymbolChanges(symbols []ExplainFileSymbol, relPath, base string, lineCount int) error {
+	if e.gitAdapter == nil || !e.gitAdapter.IsAvailable() {
+		return fmt.Errorf("git is not available")
+	}
+	hunks, isNew, err := e.gitAdapter.GetFileHunks(base, relPath)
+	if err != nil {
+		return err
+	}
+	classifySymbolChanges(symbols, hunks, lineCount, isNew)
+	if isNew || !anySymbolChange(symbols, symbolModified) {
+		return nil
+	}
+
+	oldContent, err := e.gitAdapter.GetFileAtRef(base, relPath)
+	if err != nil {
+		e.logger.Debug("Content comparison skipped", map[string]interface{}{
+			"file":  relPath,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	newContent, err := os.ReadFile(filepath.Join(e.repoRoot, filepath.FromSlash(relPath)))
+	if err != nil {
+		return nil
+	}
+	refineChangesByContent(symbols, hunks, splitSourceLines(oldContent), splitSourceLines(string(newContent)))
+	return nil
+}
+
+// classifySymbolChanges marks symbols as added, modified or unchanged given
+// the hunks changing their file. Symbol bodies aren't known, so each symbol
+// is taken to extend to the line before the next one (the last to the end of
+// the file). A symbol is added when its definition line falls in a hunk that
+// only adds lines, and modified when any hunk touches its extent.
+func classifySymbolChanges(symbols []ExplainFileSymbol, hunks []git.DiffHunk, lineCount int, isNew bool) {
+	starts := make([]int, len(symbols))
+	for i, sym := range symbols {
+		starts[i] = sym.Line
+	}
+
+	for i := range symbols {
+		if isNew {
+			symbols[i].Change = symbolAdded
+			continue
+		}
+
+		start := symbols[i].Line
+		end := symbolExtentEnd(starts, i, lineCount)
+
+		change := symbolUnchanged
+		for _, h := range hunks {
+			if h.NewLines == 0 {
+				// Pure deletion between NewStart and the line after it
+				if h.NewStart >= start && h.NewStart <= end {
+					change = symbolModified
+				}
+				continue
+			}
+			hunkEnd := h.NewStart + h.NewLines - 1
+			if h.OldLines == 0 && start >= h.NewStart && start <= hunkEnd {
+				change = symbolAdded
+				break
+			}
+			if h.NewStart <= end && hunkEnd >= start {
+				change = symbolModified
+			}
+		}
+		symbols[i].Change = change
+	}
+}
+
+// refineChangesByContent re-checks symbols marked modified by comparing
+// their bodies before and after. A symbol's old start is found by undoing
+// the line shifts of the hunks above it; its body runs to the closing brace
+// matching its first opening brace, or to the end of its extent without
+// one. Bodies equal after whitespace normalization are unchanged: the hunk
+// only touched the gap after the body, or moved lines without editing them.
+func refineChangesByContent(symbols []ExplainFileSymbol, hunks []git.DiffHunk, oldLines, newLines []string) {
+	starts := make([]int, len(symbols))
+	for i, sym := range symbols {
+		starts[i] = sym.Line
+	}
+
+	for i := range symbols {
+		if symbols[i].Change != symbolModified {
+			continue
+		}
+		start := starts[i]
+		oldStart, ok := oldLineFor(start, hunks)
+		if !ok || oldStart > len(oldLines) || start > len(newLines) {
+			continue
+		}
+
+		newEnd := bodyEnd(newLines, start, symbolExtentEnd(starts, i, len(newLines)))
+		oldLimit := len(oldLines)
+		if next := nextSymbolStart(starts, i); next > 0 {
+			if oldNext, ok := oldLineFor(next, hunks); ok {
+				oldLimit = oldNext - 1
+			}
+		}
+		oldEnd := bodyEnd(oldLines, oldStart, oldLimit)
+
+		if sameNormalizedContent(oldLines[oldStart-1:oldEnd], newLines[start-1:newEnd]) {
+			symbols[i].Change = symbolUnchanged
+		}
+	}
+}
+
+// oldLineFor maps a line of the new file to the old one by undoing the
+// shifts of the hunks before it. Lines inside a hunk have no counterpart.
+func oldLineFor(newLine int, hunks []git.DiffHunk) (int, bool) {
+	shift := 0
+	for _, h := range hunks {
+		if h.NewLines == 0 {
+			// Pure deletion after line NewStart
+			if h.NewStart < newLine {
+				shift += h.OldLines
+			}
+			continue
+		}
+		hunkEnd := h.NewStart + h.NewLines - 1
+		if newLine >= h.NewStart && newLine <= hunkEnd {
+			return 0, false
+		}
+		if hunkEnd < newLine {
+			shift += h.OldLines - h.NewLines
+		}
+	}
+	return newLine + shift, newLine+shift >= 1
+}
+
+// bodyEnd returns the line closing the brace block opened at or after
+// start, searching no further than limit. Without a balanced block the body
+// is taken to run to limit, less trailing blank lines.
+func bodyEnd(lines []string, start, limit int) int {
+	if limit > len(lines) {
+		limit = len(lines)
+	}
+	depth := 0
+	opened := false
+	for n := start; n <= limit; n++ {
+		for _, r := range lines[n-1] {
+			switch r {
+			case '{':
+				depth++
+				opened = true
+			case '}':
+				depth--
+			}
+		}
+		if opened && depth <= 0 {
+			return n
+		}
+	}
+	end := limit
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	if end < start {
+		end = start
+	}
+	return end
+}
+
+// sameNormalizedContent compares two line ranges ignoring indentation,
+// spacing within lines and blank lines.
+func sameNormalizedContent(a, b []string) bool {
+	na, nb := normalizeSourceLines(a), normalizeSourceLines(b)
+	if len(na) != len(nb) {
+		return false
+	}
+	for i := range na {
+		if na[i] != nb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeSourceLines(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			out = append(out, strings.Join(fields, " "))
+		}
+	}
+	return out
+}
+
+// splitSourceLines splits file content into lines, dropping the empty
+// element after a trailing newline.
+func splitSourceLines(content string) []string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	return lines
+}
+
+func anySymbolChange(symbols []ExplainFileSymbol, change string) bool {
+	for _, sym := range symbols {
+		if sym.Change == change {
+			return true
+		}
+	}
+	return false
+}
+
+// nextSymbolStart returns the first start after starts[i], or 0.
+func nextSymbolStart(starts []int, i int) int {
+	for j := i + 1; j < len(starts); j++ {
+		if starts[j] > starts[i] {
+			return starts[j]
+		}
+	}
+	return 0
+}
+
+// symbolExtentEnd estimates the last line of the symbol starting at
+// starts[i]: the line before the next symbol starting after it, or
+// lineCount for the last one. starts must be sorted.
+func symbolExtentEnd(starts []int, i, lineCount int) int {
+	end := lineCount
+	if next := nextSymbolStart(starts, i); next > 0 {
+		end = next - 1
+	}
+	if end < starts[i] {
+		end = starts[i]
+	}
+	return end
+}
+
+// keepChangedSymbols trims symbols to limit, preferring changed ones so a
+// review of a large file still sees everything the diff touched. Line order
+// is preserved.
+func keepChangedSymbols(symbols []ExplainFileSymbol, limit int) []ExplainFileSymbol {
+	if len(symbols) <= limit {
+		return symbols
+	}
+	keep := make([]bool, len(symbols))
+	kept := 0
+	for i, sym := range symbols {
+		if kept < limit && sym.Change != "" && sym.Change != symbolUnchanged {
+			keep[i] = true
+			kept++
+		}
+	}
+	for i := range symbols {
+		if kept < limit && !keep[i] {
+			keep[i] = true
+			kept++
+		}
+	}
+	result := make([]ExplainFileSymbol, 0, limit)
+	for i, sym := range symbols {
+		if keep[i] {
+			result = append(result, sym)
+		}
+	}
+	return result
+}
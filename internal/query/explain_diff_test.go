@@ -0,0 +1,225 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"ckb/internal/backends/git"
+)
+
+func TestClassifySymbolChanges(t *testing.T) {
+	// Symbols at lines 3, 10 and 20 of a 30-line file
+	lines := []int{3, 10, 20}
+
+	tests := []struct {
+		name  string
+		hunks []git.DiffHunk
+		isNew bool
+		want  []string
+	}{
+		{
+			name:  "no hunks",
+			hunks: nil,
+			want:  []string{symbolUnchanged, symbolUnchanged, symbolUnchanged},
+		},
+		{
+			name:  "new file",
+			isNew: true,
+			want:  []string{symbolAdded, symbolAdded, symbolAdded},
+		},
+		{
+			name:  "edit inside a body",
+			hunks: []git.DiffHunk{{OldStart: 12, OldLines: 1, NewStart: 12, NewLines: 2}},
+			want:  []string{symbolUnchanged, symbolModified, symbolUnchanged},
+		},
+		{
+			name:  "pure addition covering a definition",
+			hunks: []git.DiffHunk{{OldStart: 18, OldLines: 0, NewStart: 19, NewLines: 8}},
+			want:  []string{symbolUnchanged, symbolModified, symbolAdded},
+		},
+		{
+			name:  "deletion inside a body",
+			hunks: []git.DiffHunk{{OldStart: 6, OldLines: 2, NewStart: 5, NewLines: 0}},
+			want:  []string{symbolModified, symbolUnchanged, symbolUnchanged},
+		},
+		{
+			name:  "hunk spanning two symbols",
+			hunks: []git.DiffHunk{{OldStart: 8, OldLines: 4, NewStart: 8, NewLines: 4}},
+			want:  []string{symbolModified, symbolModified, symbolUnchanged},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbols := make([]ExplainFileSymbol, len(lines))
+			for i, line := range lines {
+				symbols[i] = ExplainFileSymbol{Line: line}
+			}
+			classifySymbolChanges(symbols, tt.hunks, 30, tt.isNew)
+
+			got := make([]string, len(symbols))
+			for i, sym := range symbols {
+				got[i] = sym.Change
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepChangedSymbols(t *testing.T) {
+	symbols := []ExplainFileSymbol{
+		{Name: "a", Line: 1, Change: symbolUnchanged},
+		{Name: "b", Line: 2, Change: symbolUnchanged},
+		{Name: "c", Line: 3, Change: symbolModified},
+		{Name: "d", Line: 4, Change: symbolUnchanged},
+		{Name: "e", Line: 5, Change: symbolAdded},
+	}
+
+	got := keepChangedSymbols(symbols, 3)
+	var names []string
+	for _, sym := range got {
+		names = append(names, sym.Name)
+	}
+	if want := []string{"a", "c", "e"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("kept %v, want %v", names, want)
+	}
+
+	if got := keepChangedSymbols(symbols, 10); len(got) != len(symbols) {
+		t.Errorf("kept %d symbols under the limit, want all %d", len(got), len(symbols))
+	}
+}
+
+func TestRefineChangesByContent(t *testing.T) {
+	oldLines := []string{
+		"package x",
+		"",
+		"func A() {",
+		"\treturn",
+		"}",
+		"",
+		"func B() {",
+		"\tb()",
+		"}",
+	}
+
+	tests := []struct {
+		name     string
+		newLines []string
+		hunks    []git.DiffHunk
+		want     []string
+	}{
+		{
+			name: "comment added in the gap after A",
+			newLines: []string{
+				"package x",
+				"",
+				"func A() {",
+				"\treturn",
+				"}",
+				"",
+				"// B does b",
+				"func B() {",
+				"\tb()",
+				"}",
+			},
+			hunks: []git.DiffHunk{{OldStart: 6, OldLines: 0, NewStart: 7, NewLines: 1}},
+			want:  []string{symbolUnchanged, symbolUnchanged},
+		},
+		{
+			name: "body reindented",
+			newLines: []string{
+				"package x",
+				"",
+				"func A() {",
+				"    return",
+				"}",
+				"",
+				"func B() {",
+				"\tb()",
+				"}",
+			},
+			hunks: []git.DiffHunk{{OldStart: 4, OldLines: 1, NewStart: 4, NewLines: 1}},
+			want:  []string{symbolUnchanged, symbolUnchanged},
+		},
+		{
+			name: "body edited",
+			newLines: []string{
+				"package x",
+				"",
+				"func A() {",
+				"\treturn",
+				"}",
+				"",
+				"func B() {",
+				"\tc()",
+				"}",
+			},
+			hunks: []git.DiffHunk{{OldStart: 8, OldLines: 1, NewStart: 8, NewLines: 1}},
+			want:  []string{symbolUnchanged, symbolModified},
+		},
+		{
+			name: "signature edited",
+			newLines: []string{
+				"package x",
+				"",
+				"func A(n int) {",
+				"\treturn",
+				"}",
+				"",
+				"func B() {",
+				"\tb()",
+				"}",
+			},
+			hunks: []git.DiffHunk{{OldStart: 3, OldLines: 1, NewStart: 3, NewLines: 1}},
+			want:  []string{symbolModified, symbolUnchanged},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var symbols []ExplainFileSymbol
+			for i, line := range tt.newLines {
+				if len(line) > 5 && line[:5] == "func " {
+					symbols = append(symbols, ExplainFileSymbol{Name: line[5:6], Line: i + 1})
+				}
+			}
+			classifySymbolChanges(symbols, tt.hunks, len(tt.newLines), false)
+			refineChangesByContent(symbols, tt.hunks, oldLines, tt.newLines)
+
+			var got []string
+			for _, sym := range symbols {
+				got = append(got, sym.Change)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOldLineFor(t *testing.T) {
+	hunks := []git.DiffHunk{
+		{OldStart: 2, OldLines: 0, NewStart: 3, NewLines: 2},   // two lines inserted after old line 2
+		{OldStart: 10, OldLines: 3, NewStart: 11, NewLines: 0}, // three lines deleted after new line 11
+	}
+	tests := []struct {
+		newLine int
+		want    int
+		wantOK  bool
+	}{
+		{1, 1, true},
+		{3, 0, false},
+		{4, 0, false},
+		{5, 3, true},
+		{11, 9, true},
+		{12, 13, true},
+	}
+	for _, tt := range tests {
+		got, ok := oldLineFor(tt.newLine, hunks)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("oldLineFor(%d) = %d, %v; want %d, %v", tt.newLine, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package query
+
+import (
+	"context"
+
+	"ckb/internal/output"
+)
+
+// maxReferenceScan caps how many references findReferences fetches before
+// filtering, sorting and paging; fetching only the page size would spend it
+// on internal references and on whichever references the index lists first.
+const maxReferenceScan = 5000
+
+// rootModule names the repository root when a path is in no nested module.
+const rootModule = "."
+
+// ExternalReferenceInfo summarizes an externalOnly findReferences query.
+type ExternalReferenceInfo struct {
+	SymbolModule  string `json:"symbolModule"` // Module defining the symbol; "." for the repo root
+	ExternalCount int    `json:"externalCount"`
+	InternalCount int    `json:"internalCount"` // References dropped as same-module
+	Note          string `json:"note,omitempty"`
+}
+
+// externalReferences keeps the references from files outside the module
+// that defines the symbol. The definition is taken from the references
+// themselves, falling back to the SCIP symbol location.
+func (e *Engine) externalReferences(ctx context.Context, symbolId string, refs []ReferenceInfo) ([]ReferenceInfo, *ExternalReferenceInfo) {
+	defPath := definitionPath(refs)
+	if defPath == "" && e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
+		if sym, err := e.scipAdapter.GetSymbol(ctx, symbolId); err == nil && sym != nil && sym.Location.Path != "" {
+			defPath = newPathCanonicalizer(e.repoRoot).resolve(sym.Location.Path)
+		}
+	}
+	if defPath == "" {
+		return refs, &ExternalReferenceInfo{
+			ExternalCount: len(refs),
+			Note:          "Symbol definition not found; references were not filtered by module",
+		}
+	}
+
+	modules := output.NewModulePaths(e.ModuleRoots())
+	symbolModule := moduleOf(modules, defPath)
+	external, internal := filterExternalReferences(refs, modules, symbolModule)
+	return external, &ExternalReferenceInfo{
+		SymbolModule:  symbolModule,
+		ExternalCount: len(external),
+		InternalCount: internal,
+	}
+}
+
+// definitionPath returns the file of the first definition reference.
+func definitionPath(refs []ReferenceInfo) string {
+	for _, ref := range refs {
+		if ref.Kind == "definition" && ref.Location != nil {
+			return ref.Location.FileId
+		}
+	}
+	return ""
+}
+
+// filterExternalReferences drops references whose file is in symbolModule
+// and reports how many were dropped.
+func filterExternalReferences(refs []ReferenceInfo, modules *output.ModulePaths, symbolModule string) ([]ReferenceInfo, int) {
+	external := make([]ReferenceInfo, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Location == nil || moduleOf(modules, ref.Location.FileId) == symbolModule {
+			continue
+		}
+		external = append(external, ref)
+	}
+	return external, len(refs) - len(external)
+}
+
+// moduleOf returns the module root containing path, or rootModule.
+func moduleOf(modules *output.ModulePaths, path string) string {
+	if module, _, ok := modules.Split(path); ok {
+		return module
+	}
+	return rootModule
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"testing"
+
+	"ckb/internal/output"
+)
+
+func TestFilterExternalReferences(t *testing.T) {
+	modules := output.NewModulePaths([]string{"services/api", "services/api/client", "libs/core"})
+	ref := func(kind, file string) ReferenceInfo {
+		return ReferenceInfo{Kind: kind, Location: &LocationInfo{FileId: file}}
+	}
+	refs := []ReferenceInfo{
+		ref("definition", "libs/core/types.go"),
+		ref("reference", "libs/core/util.go"),
+		ref("reference", "services/api/handler.go"),
+		ref("reference", "services/api/client/client.go"),
+		ref("reference", "main.go"),
+		{Kind: "reference"},
+	}
+
+	symbolModule := moduleOf(modules, definitionPath(refs))
+	if symbolModule != "libs/core" {
+		t.Fatalf("symbol module = %q, want libs/core", symbolModule)
+	}
+
+	external, dropped := filterExternalReferences(refs, modules, symbolModule)
+	want := []string{"services/api/handler.go", "services/api/client/client.go", "main.go"}
+	if len(external) != len(want) {
+		t.Fatalf("got %d external references, want %d", len(external), len(want))
+	}
+	for i, w := range want {
+		if external[i].Location.FileId != w {
+			t.Errorf("external[%d] = %s, want %s", i, external[i].Location.FileId, w)
+		}
+	}
+	if dropped != 3 {
+		t.Errorf("dropped = %d, want 3", dropped)
+	}
+}
+
+func TestModuleOfNested(t *testing.T) {
+	modules := output.NewModulePaths([]string{"services/api", "services/api/client"})
+	tests := map[string]string{
+		"services/api/client/x.go": "services/api/client",
+		"services/api/x.go":        "services/api",
+		"services/apix/x.go":       rootModule,
+		"main.go":                  rootModule,
+	}
+	for path, want := range tests {
+		if got := moduleOf(modules, path); got != want {
+			t.Errorf("moduleOf(%s) = %s, want %s", path, got, want)
+		}
+	}
+}
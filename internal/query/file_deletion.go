@@ -0,0 +1,252 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"ckb/internal/backends"
+	"ckb/internal/errors"
+	"ckb/internal/output"
+)
+
+// File deletion verdicts, from least to most disruptive.
+const (
+	DeletionSafe       = "safe"       // Nothing outside the file references its symbols
+	DeletionTestOnly   = "test-only"  // Only tests reference the file's symbols
+	DeletionEntrypoint = "entrypoint" // The file is a program entrypoint
+	DeletionBlocked    = "blocked"    // Production code references the file's symbols
+)
+
+const (
+	// maxDeletionSymbols bounds how many definitions are checked per file.
+	maxDeletionSymbols = 500
+
+	// maxDeletionRefsPerSymbol bounds the reference lookup per symbol.
+	maxDeletionRefsPerSymbol = 200
+)
+
+// AnalyzeFileDeletionOptions contains options for analyzeFileDeletion.
+type AnalyzeFileDeletionOptions struct {
+	FilePath string
+}
+
+// AnalyzeFileDeletionResponse is the response for analyzeFileDeletion.
+type AnalyzeFileDeletionResponse struct {
+	FilePath        string             `json:"filePath"`
+	Verdict         string             `json:"verdict"` // safe, test-only, entrypoint, blocked
+	Safe            bool               `json:"safe"`
+	Explanation     string             `json:"explanation"`
+	Entrypoint      bool               `json:"entrypoint,omitempty"`
+	SymbolCount     int                `json:"symbolCount"`
+	BlockingSymbols []DeletionBlocker  `json:"blockingSymbols,omitempty"`
+	TestOnlySymbols []DeletionBlocker  `json:"testOnlySymbols,omitempty"`
+	Truncated       bool               `json:"truncated,omitempty"`
+	Provenance      *Provenance        `json:"provenance"`
+	Drilldowns      []output.Drilldown `json:"drilldowns,omitempty"`
+}
+
+// DeletionBlocker is a symbol in the file that is referenced from elsewhere.
+type DeletionBlocker struct {
+	StableId       string          `json:"stableId"`
+	Name           string          `json:"name"`
+	Kind           string          `json:"kind"`
+	Line           int             `json:"line"`
+	ReferenceCount int             `json:"referenceCount"`
+	References     []ReferenceInfo `json:"references"`
+}
+
+// AnalyzeFileDeletion reports whether a file can be deleted without breaking
+// code elsewhere, by checking every symbol it defines for references from
+// other files.
+func (e *Engine) AnalyzeFileDeletion(ctx context.Context, opts AnalyzeFileDeletionOptions) (*AnalyzeFileDeletionResponse, error) {
+	startTime := time.Now()
+
+	_, relPath, filePaths, err := e.resolveRepoFile(opts.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.scipAdapter == nil || !e.scipAdapter.IsAvailable() {
+		return nil, errors.NewCkbError(
+			errors.IndexMissing,
+			"analyzeFileDeletion needs a SCIP index to find references from other files",
+			nil,
+			[]errors.FixAction{{Type: errors.RunCommand, Command: "ckb index", Safe: true, Description: "Build the SCIP index"}},
+			nil,
+		)
+	}
+
+	repoState, err := e.GetRepoState(ctx, "full")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	searchResult, err := e.scipAdapter.SearchSymbols(ctx, "", backends.SearchOptions{
+		MaxResults:   maxDeletionSymbols,
+		IncludeTests: true,
+		Scope:        filePaths,
+	})
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	inFile := make(map[string]bool, len(filePaths))
+	for _, p := range filePaths {
+		inFile[p] = true
+	}
+
+	canon := newPathCanonicalizer(e.repoRoot)
+	seen := make(map[string]bool)
+	var blocking, testOnly []DeletionBlocker
+	symbolCount := 0
+	definesMain := false
+	for _, sym := range searchResult.Symbols {
+		if seen[sym.StableID] {
+			continue
+		}
+		line, ok := definitionLineInFiles(sym, filePaths)
+		if !ok {
+			continue
+		}
+		seen[sym.StableID] = true
+		symbolCount++
+		if sym.Name == "main" && sym.Kind == "function" {
+			definesMain = true
+		}
+
+		refs, err := e.scipAdapter.FindReferences(ctx, sym.StableID, backends.RefOptions{
+			MaxResults:   maxDeletionRefsPerSymbol,
+			IncludeTests: true,
+		})
+		if err != nil || refs == nil {
+			continue
+		}
+		external := externalReferences(refs.References, inFile, canon)
+		if len(external) == 0 {
+			continue
+		}
+
+		blocker := DeletionBlocker{
+			StableId:       sym.StableID,
+			Name:           sym.Name,
+			Kind:           sym.Kind,
+			Line:           line,
+			ReferenceCount: len(external),
+			References:     external,
+		}
+		if allTestReferences(external) {
+			testOnly = append(testOnly, blocker)
+		} else {
+			blocking = append(blocking, blocker)
+		}
+	}
+	sortDeletionBlockers(blocking)
+	sortDeletionBlockers(testOnly)
+
+	entrypoint := definesMain || e.newFileRoleClassifier().classify(relPath) == "entrypoint"
+	verdict, explanation := fileDeletionVerdict(len(blocking), len(testOnly), entrypoint)
+
+	completeness := CompletenessInfo{Score: searchResult.Completeness.Score, Reason: string(searchResult.Completeness.Reason)}
+	provenance := e.buildProvenance(ctx, repoState, "full", startTime, []BackendContribution{{
+		BackendId:    "scip",
+		Available:    true,
+		Used:         true,
+		ResultCount:  symbolCount,
+		Completeness: completeness.Score,
+	}}, completeness)
+
+	var drilldowns []output.Drilldown
+	for i, b := range blocking {
+		if i >= 3 {
+			break
+		}
+		drilldowns = append(drilldowns, output.Drilldown{
+			Label:          fmt.Sprintf("Analyze impact of %s", b.Name),
+			Query:          fmt.Sprintf("analyzeImpact %s", b.StableId),
+			Tool:           "analyzeImpact",
+			Params:         map[string]interface{}{"symbolId": b.StableId},
+			RelevanceScore: 0.9 - float64(i)*0.05,
+		})
+	}
+
+	return &AnalyzeFileDeletionResponse{
+		FilePath:        relPath,
+		Verdict:         verdict,
+		Safe:            verdict == DeletionSafe,
+		Explanation:     explanation,
+		Entrypoint:      entrypoint,
+		SymbolCount:     symbolCount,
+		BlockingSymbols: blocking,
+		TestOnlySymbols: testOnly,
+		Truncated:       searchResult.TotalMatches > len(searchResult.Symbols),
+		Provenance:      provenance,
+		Drilldowns:      drilldowns,
+	}, nil
+}
+
+// externalReferences returns the non-definition references outside the
+// file being deleted, in a deterministic order.
+func externalReferences(refs []backends.Reference, inFile map[string]bool, canon *pathCanonicalizer) []ReferenceInfo {
+	var external []ReferenceInfo
+	for _, ref := range refs {
+		if ref.Kind == "definition" {
+			continue
+		}
+		path := canon.resolve(ref.Location.Path)
+		if inFile[ref.Location.Path] || inFile[path] {
+			continue
+		}
+		external = append(external, ReferenceInfo{
+			Location: &LocationInfo{
+				FileId:      path,
+				StartLine:   ref.Location.Line,
+				StartColumn: ref.Location.Column,
+				EndLine:     ref.Location.EndLine,
+				EndColumn:   ref.Location.EndColumn,
+			},
+			Kind:    ref.Kind,
+			Context: ref.Context,
+			IsTest:  isTestFilePath(path),
+		})
+	}
+	external = deduplicateReferences(external)
+	sortReferences(external)
+	return external
+}
+
+func allTestReferences(refs []ReferenceInfo) bool {
+	for _, ref := range refs {
+		if !ref.IsTest {
+			return false
+		}
+	}
+	return true
+}
+
+// sortDeletionBlockers puts the most referenced symbols first.
+func sortDeletionBlockers(blockers []DeletionBlocker) {
+	sort.Slice(blockers, func(i, j int) bool {
+		if blockers[i].ReferenceCount != blockers[j].ReferenceCount {
+			return blockers[i].ReferenceCount > blockers[j].ReferenceCount
+		}
+		return blockers[i].StableId < blockers[j].StableId
+	})
+}
+
+// fileDeletionVerdict picks the verdict for a file. Production references
+// block deletion outright; an entrypoint has no callers but removing it
+// removes a program, so it is never reported as safe.
+func fileDeletionVerdict(blocking, testOnly int, entrypoint bool) (string, string) {
+	switch {
+	case blocking > 0:
+		return DeletionBlocked, fmt.Sprintf("%d symbol(s) are referenced from production code in other files", blocking)
+	case entrypoint:
+		return DeletionEntrypoint, "File is a program entrypoint; nothing calls it, but deleting it removes the program"
+	case testOnly > 0:
+		return DeletionTestOnly, fmt.Sprintf("%d symbol(s) are referenced only from tests; delete or update those tests too", testOnly)
+	default:
+		return DeletionSafe, "No symbol in this file is referenced from another file"
+	}
+}
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ckb/internal/backends"
+	"ckb/internal/errors"
+)
+
+func TestFileDeletionVerdict(t *testing.T) {
+	tests := []struct {
+		name       string
+		blocking   int
+		testOnly   int
+		entrypoint bool
+		want       string
+	}{
+		{"no references", 0, 0, false, DeletionSafe},
+		{"tests only", 0, 2, false, DeletionTestOnly},
+		{"entrypoint", 0, 0, true, DeletionEntrypoint},
+		{"entrypoint used by tests", 0, 1, true, DeletionEntrypoint},
+		{"production references", 1, 3, true, DeletionBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, explanation := fileDeletionVerdict(tt.blocking, tt.testOnly, tt.entrypoint)
+			if got != tt.want {
+				t.Errorf("fileDeletionVerdict(%d, %d, %v) = %q, want %q", tt.blocking, tt.testOnly, tt.entrypoint, got, tt.want)
+			}
+			if explanation == "" {
+				t.Error("expected an explanation")
+			}
+		})
+	}
+}
+
+func TestExternalReferences(t *testing.T) {
+	refs := []backends.Reference{
+		{Location: backends.Location{Path: "pkg/util.go", Line: 3}, Kind: "definition"},
+		{Location: backends.Location{Path: "pkg/util.go", Line: 10}, Kind: "call"},
+		{Location: backends.Location{Path: "pkg/util_test.go", Line: 7}, Kind: "call"},
+		{Location: backends.Location{Path: "cmd/app/main.go", Line: 12}, Kind: "call"},
+	}
+
+	external := externalReferences(refs, map[string]bool{"pkg/util.go": true}, newPathCanonicalizer(""))
+	if len(external) != 2 {
+		t.Fatalf("expected 2 external references, got %d: %+v", len(external), external)
+	}
+	if external[0].Location.FileId != "cmd/app/main.go" || external[0].IsTest {
+		t.Errorf("unexpected first reference %+v", external[0].Location)
+	}
+	if !external[1].IsTest {
+		t.Error("pkg/util_test.go reference should be marked as a test")
+	}
+	if allTestReferences(external) {
+		t.Error("allTestReferences should be false with a production caller")
+	}
+	if !allTestReferences(external[1:]) {
+		t.Error("allTestReferences should be true for test callers only")
+	}
+}
+
+func TestAnalyzeFileDeletionRequiresIndex(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if err := os.WriteFile(filepath.Join(engine.repoRoot, "util.go"), []byte("package util\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := engine.AnalyzeFileDeletion(context.Background(), AnalyzeFileDeletionOptions{FilePath: "util.go"})
+	ckbErr, ok := err.(*errors.CkbError)
+	if !ok || ckbErr.Code != errors.IndexMissing {
+		t.Errorf("expected INDEX_MISSING without a SCIP index, got %v", err)
+	}
+
+	if _, err := engine.AnalyzeFileDeletion(context.Background(), AnalyzeFileDeletionOptions{FilePath: "missing.go"}); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
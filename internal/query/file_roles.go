@@ -0,0 +1,115 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ckb/internal/config"
+)
+
+// fileRoleClassifier classifies files by role, trying the configured
+// fileRoles patterns before the built-in heuristics of classifyFileRole.
+// A nil classifier uses the built-ins alone.
+type fileRoleClassifier struct {
+	rules []fileRoleRule
+}
+
+// fileRoleRule is one compiled fileRoles pattern.
+type fileRoleRule struct {
+	role      string
+	pattern   string         // As configured, for classification bases
+	substring string         // Lowercased; set unless re is
+	re        *regexp.Regexp // Globs and "re:" patterns
+}
+
+// newFileRoleClassifier returns a classifier for the engine's configured
+// file roles.
+func (e *Engine) newFileRoleClassifier() *fileRoleClassifier {
+	if e.config == nil {
+		return nil
+	}
+	return compileFileRoles(e.config.FileRoles)
+}
+
+// compileFileRoles compiles fileRoles patterns in config.FileRoleNames
+// order. Config validation rejects patterns that don't compile; any that
+// slip through are skipped.
+func compileFileRoles(roles map[string][]string) *fileRoleClassifier {
+	var rules []fileRoleRule
+	for _, role := range config.FileRoleNames {
+		for _, p := range roles[role] {
+			pattern := strings.TrimSpace(p)
+			rule := fileRoleRule{role: role, pattern: pattern}
+			switch {
+			case pattern == "":
+				continue
+			case strings.HasPrefix(pattern, config.FileRoleRegexPrefix):
+				re, err := regexp.Compile(strings.TrimPrefix(pattern, config.FileRoleRegexPrefix))
+				if err != nil {
+					continue
+				}
+				rule.re = re
+			case isScopeGlob(pattern):
+				rule.re = regexp.MustCompile("(?i)^" + scopeGlobToRegex(pattern) + "$")
+			default:
+				rule.substring = strings.ToLower(pattern)
+			}
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	return &fileRoleClassifier{rules: rules}
+}
+
+// match returns the first configured rule matching path.
+func (c *fileRoleClassifier) match(path string) (fileRoleRule, bool) {
+	if c == nil {
+		return fileRoleRule{}, false
+	}
+	path = strings.TrimPrefix(strings.ReplaceAll(path, "\\", "/"), "./")
+	lower := strings.ToLower(path)
+	for _, rule := range c.rules {
+		if rule.re != nil && rule.re.MatchString(path) {
+			return rule, true
+		}
+		if rule.re == nil && strings.Contains(lower, rule.substring) {
+			return rule, true
+		}
+	}
+	return fileRoleRule{}, false
+}
+
+// classify returns the role of the file at path (core, entrypoint, test,
+// config or unknown).
+func (c *fileRoleClassifier) classify(path string) string {
+	if rule, ok := c.match(path); ok {
+		return rule.role
+	}
+	return classifyFileRole(path)
+}
+
+// classifyPath is classifyPathRole with configured roles taking precedence,
+// mapped onto explainPath's vocabulary: tests are test-only and entrypoints
+// core, as in the built-in rules.
+func (c *fileRoleClassifier) classifyPath(path string) (string, string, []ClassificationBasis) {
+	rule, ok := c.match(path)
+	if !ok {
+		return classifyPathRole(path)
+	}
+	basis := []ClassificationBasis{{
+		Type:       "config",
+		Signal:     fmt.Sprintf("fileRoles.%s pattern: %s", rule.role, rule.pattern),
+		Confidence: 0.95,
+	}}
+	switch rule.role {
+	case "test":
+		return "test-only", "Test file per configured fileRoles pattern", basis
+	case "entrypoint":
+		return "core", "Entry point per configured fileRoles pattern", basis
+	default:
+		return rule.role, fmt.Sprintf("Classified as %s by configured fileRoles pattern", rule.role), basis
+	}
+}
@@ -0,0 +1,100 @@
+package query
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileRoleClassifier(t *testing.T) {
+	roles := compileFileRoles(map[string][]string{
+		"core":    {"app/"},
+		"test":    {"e2e/**"},
+		"unknown": {`re:^generated/.*\.go$`},
+		"config":  {""}, // Ignored
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"app/billing/service.go", "core"},
+		{"App/Billing/Service.go", "core"}, // Substrings ignore case
+		{"e2e/checkout/flow.go", "test"},
+		{"generated/models.go", "unknown"},
+		{"e2e/app/fixture.go", "test"},       // Roles are tried in config.FileRoleNames order
+		{"web/src/engine.go", "core"},        // Built-in fallback
+		{"cmd/ckb/main.go", "entrypoint"},    // Built-in fallback
+		{"internal/query/x_test.go", "test"}, // Built-in fallback
+		{"scripts/release.sh", "unknown"},    // Built-in fallback
+	}
+	for _, tt := range tests {
+		if got := roles.classify(tt.path); got != tt.want {
+			t.Errorf("classify(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	var none *fileRoleClassifier
+	if got := none.classify("internal/query/engine.go"); got != classifyFileRole("internal/query/engine.go") {
+		t.Errorf("nil classifier = %q, want built-in role", got)
+	}
+	if compileFileRoles(nil) != nil {
+		t.Error("expected no classifier without configured roles")
+	}
+}
+
+func TestFileRoleClassifier_ClassifyPath(t *testing.T) {
+	roles := compileFileRoles(map[string][]string{"core": {"app/"}, "test": {"e2e/"}})
+
+	role, _, basis := roles.classifyPath("app/handler.go")
+	if role != "core" || len(basis) != 1 || basis[0].Type != "config" {
+		t.Errorf("classifyPath(app/handler.go) = %q, %+v; want core from config", role, basis)
+	}
+	if role, _, _ := roles.classifyPath("e2e/login.go"); role != "test-only" {
+		t.Errorf("classifyPath(e2e/login.go) = %q, want test-only", role)
+	}
+	if role, _, _ := roles.classifyPath("internal/api/handler.go"); role != "glue" {
+		t.Errorf("classifyPath(internal/api/handler.go) = %q, want built-in glue", role)
+	}
+}
+
+func TestConfiguredFileRolesEndToEnd(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	file := filepath.Join(engine.repoRoot, "app", "orders.go")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("package app\n\nfunc Place() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := engine.ExplainFile(ctx, ExplainFileOptions{FilePath: "app/orders.go"})
+	if err != nil {
+		t.Fatalf("ExplainFile() error = %v", err)
+	}
+	if before.Facts.Role == "core" {
+		t.Fatal("app/ is core without configuration; the test proves nothing")
+	}
+
+	engine.config.FileRoles = map[string][]string{"core": {"app/"}}
+
+	explained, err := engine.ExplainFile(ctx, ExplainFileOptions{FilePath: "app/orders.go"})
+	if err != nil {
+		t.Fatalf("ExplainFile() error = %v", err)
+	}
+	if explained.Facts.Role != "core" {
+		t.Errorf("explainFile role = %q, want core", explained.Facts.Role)
+	}
+
+	path, err := engine.ExplainPath(ctx, ExplainPathOptions{FilePath: "app/orders.go"})
+	if err != nil {
+		t.Fatalf("ExplainPath() error = %v", err)
+	}
+	if path.Role != "core" {
+		t.Errorf("explainPath role = %q, want core", path.Role)
+	}
+}
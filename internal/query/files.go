@@ -0,0 +1,159 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ckb/internal/backends/scip"
+	"ckb/internal/errors"
+	"ckb/internal/output"
+)
+
+const (
+	defaultListFilesLimit = 200
+	maxListFilesLimit     = 1000
+)
+
+// ListFilesOptions contains options for listFiles.
+type ListFilesOptions struct {
+	Scope  string // Directory to list, repo-relative; empty lists the whole index
+	Limit  int
+	Offset int // Position to resume from, taken from a previous NextOffset
+}
+
+// ListFilesResponse is the response for listFiles.
+type ListFilesResponse struct {
+	Files      []IndexedFile      `json:"files"`
+	TotalCount int                `json:"totalCount"`
+	NextOffset int                `json:"nextOffset,omitempty"` // Set when more files follow
+	Missing    int                `json:"missing,omitempty"`    // Indexed files no longer on disk (this page)
+	Provenance *Provenance        `json:"provenance"`
+	Drilldowns []output.Drilldown `json:"drilldowns,omitempty"`
+}
+
+// IndexedFile describes a source file present in the index.
+type IndexedFile struct {
+	Path         string `json:"path"`
+	Language     string `json:"language"`
+	Role         string `json:"role"`
+	SymbolCount  int    `json:"symbolCount"`
+	LastModified string `json:"lastModified,omitempty"`
+	Missing      bool   `json:"missing,omitempty"` // Deleted or moved since indexing
+}
+
+// ListFiles lists the files in the SCIP index, ordered by path. A directory
+// that holds source but is absent from the listing is not covered by the index.
+func (e *Engine) ListFiles(ctx context.Context, opts ListFilesOptions) (*ListFilesResponse, error) {
+	startTime := time.Now()
+
+	if opts.Limit <= 0 {
+		opts.Limit = defaultListFilesLimit
+	}
+	if opts.Limit > maxListFilesLimit {
+		opts.Limit = maxListFilesLimit
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	if e.scipAdapter == nil || !e.scipAdapter.IsAvailable() {
+		return nil, errors.NewCkbError(
+			errors.IndexMissing,
+			"listFiles needs a SCIP index",
+			nil,
+			[]errors.FixAction{{Type: errors.RunCommand, Command: "ckb index", Safe: true, Description: "Build the SCIP index"}},
+			nil,
+		)
+	}
+
+	repoState, err := e.GetRepoState(ctx, "head")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	scope := ""
+	if opts.Scope != "" {
+		scope = filepath.ToSlash(filepath.Clean(opts.Scope))
+	}
+	docs := e.scipAdapter.ListDocuments(scope)
+
+	page, nextOffset := pageDocuments(docs, opts.Offset, opts.Limit)
+	files := make([]IndexedFile, 0, len(page))
+	missing := 0
+	roles := e.newFileRoleClassifier()
+	for _, doc := range page {
+		file := indexedFile(doc, roles)
+		if info, statErr := os.Stat(filepath.Join(e.repoRoot, doc.Path)); statErr == nil {
+			file.LastModified = info.ModTime().UTC().Format(time.RFC3339)
+		} else {
+			file.Missing = true
+			missing++
+		}
+		files = append(files, file)
+	}
+
+	completeness := CompletenessInfo{Score: 1.0, Reason: "full-backend"}
+	provenance := e.buildProvenance(ctx, repoState, "head", startTime, []BackendContribution{{
+		BackendId:    "scip",
+		Available:    true,
+		Used:         true,
+		ResultCount:  len(files),
+		Completeness: completeness.Score,
+	}}, completeness)
+	if missing > 0 {
+		e.addWarning(provenance, output.SeverityWarning, WarnIndexStale,
+			fmt.Sprintf("%d indexed file(s) no longer exist on disk; reindex to refresh", missing))
+	}
+
+	var drilldowns []output.Drilldown
+	if nextOffset > 0 {
+		drilldowns = append(drilldowns, output.Drilldown{
+			Label:          "Next page of files",
+			Query:          fmt.Sprintf("listFiles --scope=%s --offset=%d", scope, nextOffset),
+			Tool:           "listFiles",
+			Params:         map[string]interface{}{"scope": scope, "offset": nextOffset, "limit": opts.Limit},
+			RelevanceScore: 0.8,
+		})
+	}
+
+	return &ListFilesResponse{
+		Files:      files,
+		TotalCount: len(docs),
+		NextOffset: nextOffset,
+		Missing:    missing,
+		Provenance: provenance,
+		Drilldowns: drilldowns,
+	}, nil
+}
+
+// pageDocuments returns the documents in [offset, offset+limit) and the
+// offset of the next page, or 0 when this is the last page.
+func pageDocuments(docs []scip.DocumentSummary, offset, limit int) ([]scip.DocumentSummary, int) {
+	if offset >= len(docs) {
+		return nil, 0
+	}
+	end := offset + limit
+	if end >= len(docs) {
+		return docs[offset:], 0
+	}
+	return docs[offset:end], end
+}
+
+// indexedFile classifies an indexed document. Extension-based detection
+// wins over the indexer's language so results match explainFile.
+func indexedFile(doc scip.DocumentSummary, roles *fileRoleClassifier) IndexedFile {
+	language := detectLanguage(doc.Path)
+	if language == "" {
+		language = strings.ToLower(doc.Language)
+	}
+	return IndexedFile{
+		Path:        doc.Path,
+		Language:    language,
+		Role:        roles.classify(doc.Path),
+		SymbolCount: doc.SymbolCount,
+	}
+}
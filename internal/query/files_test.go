@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"ckb/internal/backends/scip"
+	"ckb/internal/errors"
+)
+
+func TestPageDocuments(t *testing.T) {
+	docs := []scip.DocumentSummary{{Path: "a.go"}, {Path: "b.go"}, {Path: "c.go"}}
+
+	tests := []struct {
+		offset, limit int
+		wantPaths     []string
+		wantNext      int
+	}{
+		{0, 2, []string{"a.go", "b.go"}, 2},
+		{2, 2, []string{"c.go"}, 0},
+		{0, 3, []string{"a.go", "b.go", "c.go"}, 0},
+		{5, 2, nil, 0},
+	}
+
+	for _, tt := range tests {
+		page, next := pageDocuments(docs, tt.offset, tt.limit)
+		var paths []string
+		for _, d := range page {
+			paths = append(paths, d.Path)
+		}
+		if len(paths) != len(tt.wantPaths) || next != tt.wantNext {
+			t.Errorf("pageDocuments(offset=%d, limit=%d) = %v, next %d; want %v, next %d",
+				tt.offset, tt.limit, paths, next, tt.wantPaths, tt.wantNext)
+			continue
+		}
+		for i := range paths {
+			if paths[i] != tt.wantPaths[i] {
+				t.Errorf("pageDocuments(offset=%d, limit=%d) = %v, want %v", tt.offset, tt.limit, paths, tt.wantPaths)
+				break
+			}
+		}
+	}
+}
+
+func TestIndexedFile(t *testing.T) {
+	tests := []struct {
+		doc      scip.DocumentSummary
+		language string
+		role     string
+	}{
+		{scip.DocumentSummary{Path: "cmd/app/main.go", Language: "Go"}, "go", "entrypoint"},
+		{scip.DocumentSummary{Path: "internal/query/engine_test.go"}, "go", "test"},
+		{scip.DocumentSummary{Path: "app/lib/util.ex", Language: "Elixir"}, "elixir", "core"},
+	}
+
+	for _, tt := range tests {
+		got := indexedFile(tt.doc, nil)
+		if got.Language != tt.language || got.Role != tt.role {
+			t.Errorf("indexedFile(%s) = language %q role %q, want %q %q", tt.doc.Path, got.Language, got.Role, tt.language, tt.role)
+		}
+	}
+}
+
+func TestListFilesRequiresIndex(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	_, err := engine.ListFiles(context.Background(), ListFilesOptions{})
+	ckbErr, ok := err.(*errors.CkbError)
+	if !ok || ckbErr.Code != errors.IndexMissing {
+		t.Errorf("expected INDEX_MISSING without a SCIP index, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"fmt"
+
+	"ckb/internal/backends/scip"
+	"ckb/internal/errors"
+)
+
+// FreshnessPolicy is how stale an index SCIP-backed queries will accept.
+type FreshnessPolicy struct {
+	RequireFresh     bool
+	MaxCommitsBehind int // With 0, uncommitted changes also count as stale
+}
+
+// FreshnessPolicy returns the configured policy (backends.scip.requireFreshIndex
+// and maxCommitsBehind). Callers may override it per request.
+func (e *Engine) FreshnessPolicy() FreshnessPolicy {
+	if e.config == nil {
+		return FreshnessPolicy{}
+	}
+	return FreshnessPolicy{
+		RequireFresh:     e.config.Backends.Scip.RequireFreshIndex,
+		MaxCommitsBehind: e.config.Backends.Scip.MaxCommitsBehind,
+	}
+}
+
+// CheckIndexFreshness returns an IndexStale error when the policy requires
+// a fresh index and the loaded one is too far behind. A missing index
+// passes: tools report that themselves, and may have non-SCIP fallbacks.
+func (e *Engine) CheckIndexFreshness(policy FreshnessPolicy) error {
+	if !policy.RequireFresh || e.scipAdapter == nil || !e.scipAdapter.IsAvailable() {
+		return nil
+	}
+	info := e.scipAdapter.GetIndexInfo()
+	if info == nil || info.Freshness == nil {
+		return nil
+	}
+
+	reason := freshnessViolation(info.Freshness, policy.MaxCommitsBehind)
+	if reason == "" {
+		return nil
+	}
+	return errors.NewCkbError(
+		errors.IndexStale,
+		fmt.Sprintf("SCIP index is stale (%s) and a fresh index is required; run 'ckb index' to rebuild it", reason),
+		nil,
+		[]errors.FixAction{{Type: errors.RunCommand, Command: "ckb index", Safe: true, Description: "Rebuild the SCIP index"}},
+		nil,
+	)
+}
+
+// freshnessViolation explains why an index exceeds maxBehind, or returns ""
+// if it doesn't. An index off HEAD by an unknown distance is treated as too
+// far behind, since freshness can't be shown.
+func freshnessViolation(f *scip.IndexFreshness, maxBehind int) string {
+	if maxBehind < 0 {
+		maxBehind = 0
+	}
+	if f.StaleAgainstHead {
+		if f.CommitsBehindHead == 0 {
+			return "index is not at HEAD and its distance from HEAD is unknown"
+		}
+		if f.CommitsBehindHead > maxBehind {
+			return fmt.Sprintf("index is %d commit(s) behind HEAD (max %d)", f.CommitsBehindHead, maxBehind)
+		}
+	}
+	if f.StaleAgainstRepoState && maxBehind == 0 {
+		return "uncommitted changes are not reflected in the index"
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"ckb/internal/backends/scip"
+)
+
+func TestFreshnessViolation(t *testing.T) {
+	tests := []struct {
+		name      string
+		freshness scip.IndexFreshness
+		maxBehind int
+		want      string // Substring of the reason; "" means fresh enough
+	}{
+		{"at HEAD", scip.IndexFreshness{}, 0, ""},
+		{"behind, strict", scip.IndexFreshness{StaleAgainstHead: true, CommitsBehindHead: 1}, 0, "1 commit(s) behind HEAD (max 0)"},
+		{"behind, within threshold", scip.IndexFreshness{StaleAgainstHead: true, CommitsBehindHead: 3}, 5, ""},
+		{"behind, over threshold", scip.IndexFreshness{StaleAgainstHead: true, CommitsBehindHead: 6}, 5, "6 commit(s) behind HEAD (max 5)"},
+		{"unknown distance", scip.IndexFreshness{StaleAgainstHead: true}, 5, "distance from HEAD is unknown"},
+		{"dirty, strict", scip.IndexFreshness{StaleAgainstRepoState: true}, 0, "uncommitted changes"},
+		{"dirty, with threshold", scip.IndexFreshness{StaleAgainstRepoState: true}, 2, ""},
+		{"negative threshold is strict", scip.IndexFreshness{StaleAgainstHead: true, CommitsBehindHead: 1}, -1, "max 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := freshnessViolation(&tt.freshness, tt.maxBehind)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("expected no violation, got %q", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("violation = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckIndexFreshnessWithoutIndex(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	// No SCIP index loaded: nothing to be stale
+	if err := engine.CheckIndexFreshness(FreshnessPolicy{RequireFresh: true}); err != nil {
+		t.Errorf("CheckIndexFreshness without an index = %v, want nil", err)
+	}
+}
@@ -0,0 +1,82 @@
+package query
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// generatedHeaderRe matches the standard Go generated-code marker
+// (https://go.dev/s/generatedcode), which other generators imitate.
+var generatedHeaderRe = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// maxHeaderLineBytes bounds how much of a file is read to find its header.
+const maxHeaderLineBytes = 512
+
+// isGeneratedFile reports whether a file is generated code, judged by its
+// path and its first line.
+func isGeneratedFile(path string, firstLine string) bool {
+	if isGeneratedFilePath(path) || isMockFilePath(path) {
+		return true
+	}
+	return generatedHeaderRe.MatchString(strings.TrimRight(firstLine, "\r\n"))
+}
+
+// isMockFilePath checks for mockgen/mockery style mock files.
+func isMockFilePath(path string) bool {
+	pathLower := strings.ToLower(filepath.ToSlash(path))
+	base := pathLower
+	if idx := strings.LastIndex(pathLower, "/"); idx >= 0 {
+		base = pathLower[idx+1:]
+	}
+	return strings.HasPrefix(base, "mock_") ||
+		strings.HasSuffix(base, "_mock.go") ||
+		strings.HasPrefix(pathLower, "mocks/") ||
+		strings.Contains(pathLower, "/mocks/")
+}
+
+// readFirstLine returns the first line of a file, or "" if it can't be read.
+// At most maxHeaderLineBytes are read.
+func readFirstLine(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	line, _ := bufio.NewReaderSize(f, maxHeaderLineBytes).ReadSlice('\n')
+	return string(line)
+}
+
+// generatedFileFilter answers isGeneratedFile for repo files, reading each
+// file's header at most once.
+type generatedFileFilter struct {
+	repoRoot string
+	checked  map[string]bool
+}
+
+func newGeneratedFileFilter(repoRoot string) *generatedFileFilter {
+	return &generatedFileFilter{repoRoot: repoRoot, checked: make(map[string]bool)}
+}
+
+// isGenerated reports whether a repo-relative path is generated code.
+func (f *generatedFileFilter) isGenerated(relPath string) bool {
+	return f.isGeneratedAt(relPath, filepath.Join(f.repoRoot, relPath))
+}
+
+// isGeneratedAt is isGenerated for a file whose path patterns are judged on
+// relPath but whose header is read from fullPath. The header is only read
+// when the path alone doesn't decide it.
+func (f *generatedFileFilter) isGeneratedAt(relPath, fullPath string) bool {
+	if generated, ok := f.checked[fullPath]; ok {
+		return generated
+	}
+	generated := isGeneratedFilePath(relPath) || isMockFilePath(relPath)
+	if !generated {
+		generated = isGeneratedFile(relPath, readFirstLine(fullPath))
+	}
+	f.checked[fullPath] = generated
+	return generated
+}
@@ -0,0 +1,98 @@
+package query
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsGeneratedFile(t *testing.T) {
+	tests := []struct {
+		path      string
+		firstLine string
+		want      bool
+	}{
+		{"api/v1/service.pb.go", "", true},
+		{"internal/store/store_gen.go", "", true},
+		{"internal/mocks/store.go", "", true},
+		{"internal/store/mock_store.go", "", true},
+		{"internal/store/store_mock.go", "", true},
+		{"internal/store/store.go", "// Code generated by stringer, edit freely\n", false},
+		{"internal/store/kind_string.go", "// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.\n", true},
+		{"internal/store/models.go", "// Code generated by sqlc. DO NOT EDIT.\r\n", true},
+		{"internal/store/store.go", "package store\n", false},
+		{"internal/store/store.go", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := isGeneratedFile(tt.path, tt.firstLine); got != tt.want {
+			t.Errorf("isGeneratedFile(%q, %q) = %v, want %v", tt.path, tt.firstLine, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratedFileFilter(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("kind_string.go", "// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.\n\npackage x\n")
+	write("kind.go", "package x\n\n// Code generated by hand. DO NOT EDIT.\n")
+
+	f := newGeneratedFileFilter(root)
+	if !f.isGenerated("kind_string.go") {
+		t.Error("expected header-marked file to be generated")
+	}
+	// Only the first line counts
+	if f.isGenerated("kind.go") {
+		t.Error("expected marker past the first line to be ignored")
+	}
+	if f.isGenerated("missing.go") {
+		t.Error("expected unreadable file not to be generated")
+	}
+}
+
+func TestGetModuleOverviewExcludeGenerated(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"store.go":        "package store\n",
+		"store.pb.go":     "package store\n",
+		"kind_string.go":  "// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.\n",
+		"mocks/store.go":  "package mocks\n",
+		"store_test.go":   "package store\n",
+		"doc/overview.md": "# Store\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	for _, tt := range []struct {
+		exclude bool
+		want    int
+	}{
+		{exclude: true, want: 3},
+		{exclude: false, want: 6},
+	} {
+		resp, err := engine.GetModuleOverview(ctx, ModuleOverviewOptions{Path: dir, ExcludeGenerated: tt.exclude})
+		if err != nil {
+			t.Fatalf("GetModuleOverview: %v", err)
+		}
+		if resp.Size.FileCount != tt.want {
+			t.Errorf("ExcludeGenerated=%v: file count = %d, want %d", tt.exclude, resp.Size.FileCount, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package query
+
+import "ckb/internal/backends/scip"
+
+const (
+	// maxCouplingVisits bounds the SCIP occurrences inspected per hotspot
+	// file, so a huge generated or vendored file can't stall the query.
+	maxCouplingVisits = 20000
+
+	// couplingSaturation is the number of coupled files at which a
+	// hotspot's coupling score reaches 1.
+	couplingSaturation = 20
+)
+
+// hotspotCoupling converts SCIP coupling counts into hotspot metrics. The
+// score grows linearly with the number of coupled files and saturates at
+// couplingSaturation.
+func hotspotCoupling(c scip.FileCoupling) *HotspotCoupling {
+	score := float64(c.Dependents+c.Dependencies) / couplingSaturation
+	if score > 1.0 {
+		score = 1.0
+	}
+	return &HotspotCoupling{
+		DependentCount:  c.Dependents,
+		DependencyCount: c.Dependencies,
+		Score:           score,
+	}
+}
+
+// couplingMultiplier scales a hotspot's ranking by its coupling: from 1.0
+// for an isolated (or unindexed) file up to 2.0 for a heavily coupled one,
+// so churn in files many others depend on ranks higher.
+func couplingMultiplier(c *HotspotCoupling) float64 {
+	if c == nil {
+		return 1.0
+	}
+	return 1.0 + c.Score
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+)
+
+// Hotspot output formats.
+const (
+	HotspotsFormatJSON = "json"
+	HotspotsFormatCSV  = "csv"
+)
+
+// hotspotsCSVHeader names the columns of the getHotspots CSV export.
+var hotspotsCSVHeader = []string{
+	"filePath", "role", "language", "changeCount", "authorCount", "recency", "riskLevel", "rankingScore",
+}
+
+// hotspotsCSV renders hotspots as CSV, one row per hotspot in the given
+// order, so the rows line up with the JSON hotspot list.
+func hotspotsCSV(hotspots []HotspotV52) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(hotspotsCSVHeader); err != nil {
+		return "", err
+	}
+
+	for _, h := range hotspots {
+		score := ""
+		if h.Ranking != nil {
+			score = strconv.FormatFloat(h.Ranking.Score, 'f', -1, 64)
+		}
+		row := []string{
+			h.FilePath,
+			h.Role,
+			h.Language,
+			strconv.Itoa(h.Churn.ChangeCount),
+			strconv.Itoa(h.Churn.AuthorCount),
+			h.Recency,
+			h.RiskLevel,
+			score,
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+
+	w.Flush()
+	return b.String(), w.Error()
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestHotspotsCSV(t *testing.T) {
+	hotspots := []HotspotV52{
+		{
+			FilePath:  "internal/query/navigation.go",
+			Role:      "core",
+			Language:  "go",
+			Churn:     HotspotChurn{ChangeCount: 42, AuthorCount: 5},
+			Recency:   "recent",
+			RiskLevel: "high",
+			Ranking:   &RankingV52{Score: 87.5},
+		},
+		{
+			FilePath:  `docs/a, "quoted" name.md`,
+			Churn:     HotspotChurn{ChangeCount: 3, AuthorCount: 1},
+			Recency:   "stale",
+			RiskLevel: "low",
+		},
+	}
+
+	got, err := hotspotsCSV(hotspots)
+	if err != nil {
+		t.Fatalf("hotspotsCSV() error = %v", err)
+	}
+	want := strings.Join([]string{
+		"filePath,role,language,changeCount,authorCount,recency,riskLevel,rankingScore",
+		"internal/query/navigation.go,core,go,42,5,recent,high,87.5",
+		`"docs/a, ""quoted"" name.md",,,3,1,stale,low,`,
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("hotspotsCSV() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGetHotspotsRejectsUnknownFormat(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	_, err := engine.GetHotspots(context.Background(), GetHotspotsOptions{Format: "xlsx"})
+	if err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Errorf("expected an invalid format error, got %v", err)
+	}
+}
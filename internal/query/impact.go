@@ -21,6 +21,27 @@ type AnalyzeImpactOptions struct {
 	IncludeTests     bool
 	IncludeTelemetry bool   // Include observed telemetry data
 	TelemetryPeriod  string // Time period for telemetry ("7d", "30d", "90d")
+
+	// OnlyBreakingChanges drops impacts a change isn't expected to break
+	// (type, test and unknown dependencies). The risk score is unaffected.
+	OnlyBreakingChanges bool
+
+	// MinConfidence drops impact items whose confidence is lower (0 = keep
+	// all). Risk and module summaries still count every item.
+	MinConfidence float64
+
+	// ChangeKind is the change to analyze: "modify" (default), "delete" or
+	// "rename". Deletes and renames break every reference; deletes raise
+	// the risk score and renames classify each site's update. NewName is
+	// the rename target and is only accepted with "rename".
+	ChangeKind string
+	NewName    string
+
+	// Previous is the digest of an earlier result for the same symbol and
+	// options. While the repo state and symbol are unchanged the stored
+	// result is returned with provenance fromCache set; otherwise the
+	// analysis runs in full.
+	Previous *ImpactDigest
 }
 
 // AnalyzeImpactResponse is the response for analyzeImpact.
@@ -39,6 +60,19 @@ type AnalyzeImpactResponse struct {
 	TruncationInfo    *TruncationInfo       `json:"truncationInfo,omitempty"`
 	Provenance        *Provenance           `json:"provenance"`
 	Drilldowns        []output.Drilldown    `json:"drilldowns,omitempty"`
+
+	ChangeKind string `json:"changeKind,omitempty"` // Set for delete and rename
+	NewName    string `json:"newName,omitempty"`
+
+	OnlyBreakingChanges   bool `json:"onlyBreakingChanges,omitempty"`
+	SuppressedNonBreaking int  `json:"suppressedNonBreaking,omitempty"` // Impacts hidden by onlyBreakingChanges
+	FilteredByConfidence  int  `json:"filteredByConfidence,omitempty"`  // Impacts dropped by minConfidence
+
+	KindBreakdown map[string]int `json:"kindBreakdown,omitempty"` // Impacts per kind, before filtering and truncation
+
+	RiskThresholds *impact.RiskThresholds `json:"riskThresholds,omitempty"` // Mapping that produced RiskScore.Level
+
+	Digest *ImpactDigest `json:"digest,omitempty"` // Pass back as Previous to reuse this result
 }
 
 // DocToUpdate represents documentation that may need updating when a symbol changes.
@@ -62,17 +96,28 @@ type ObservedUsageSummary struct {
 
 // RiskScore describes the risk of changing a symbol.
 type RiskScore struct {
-	Level       string       `json:"level"` // high, medium, low
+	Level       string       `json:"level"` // critical, high, medium, low
 	Score       float64      `json:"score"`
 	Explanation string       `json:"explanation"`
+	Narrative   string       `json:"narrative,omitempty"` // Factors ranked by contribution, see riskNarrative
 	Factors     []RiskFactor `json:"factors"`
 }
 
+// riskThresholds returns the configured riskThresholds, with the built-in
+// defaults for any left unset.
+func (e *Engine) riskThresholds() impact.RiskThresholds {
+	if e.config == nil {
+		return impact.DefaultRiskThresholds()
+	}
+	return e.config.RiskThresholds.Effective()
+}
+
 // RiskFactor describes a factor in the risk score.
 type RiskFactor struct {
 	Name   string  `json:"name"`
 	Value  float64 `json:"value"`
 	Weight float64 `json:"weight"`
+	Detail string  `json:"detail,omitempty"`
 }
 
 // ImpactItem describes an impact from changing a symbol.
@@ -85,6 +130,8 @@ type ImpactItem struct {
 	Location   *LocationInfo   `json:"location,omitempty"`
 	Confidence float64         `json:"confidence"`
 	Visibility *VisibilityInfo `json:"visibility,omitempty"`
+	Breaking   bool            `json:"breaking,omitempty"`
+	UpdateKind string          `json:"updateKind,omitempty"` // Renames: mechanical or logic
 }
 
 // ModuleImpact describes the impact on a module.
@@ -100,11 +147,16 @@ type ModuleImpact struct {
 func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (*AnalyzeImpactResponse, error) {
 	startTime := time.Now()
 
-	// Default options
-	if opts.Depth <= 0 {
-		opts.Depth = 2
+	if err := validateChangeKind(opts); err != nil {
+		return nil, err
 	}
 
+	// Resolve depth against configured traversal limits
+	depthLimits := e.traversalConfig().ImpactLimits()
+	requestedDepth := opts.Depth
+	var depthClamped bool
+	opts.Depth, depthClamped = depthLimits.Resolve(opts.Depth)
+
 	// Get repo state (full mode for impact analysis)
 	repoState, err := e.GetRepoState(ctx, "full")
 	if err != nil {
@@ -183,28 +235,78 @@ func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (
 		)
 	}
 
+	// A curated API declaration takes precedence over language visibility
+	e.applyDeclaredAPI(symbolInfo)
+
+	fingerprint := impactSymbolFingerprint(symbolInfo)
+	cacheKey := newImpactCacheKey(symbolInfo.StableId, repoState.RepoStateId, opts)
+	if cached := e.cachedImpact(opts.Previous, cacheKey, fingerprint, startTime); cached != nil {
+		return cached, nil
+	}
+
 	// Find references for impact analysis
 	var refs []impact.Reference
 	if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
+		// Always fetch test references so test-only exports can be detected;
+		// they are filtered below when not requested.
 		refOpts := backends.RefOptions{
 			MaxResults:   500,
-			IncludeTests: opts.IncludeTests,
+			IncludeTests: true,
 		}
 		refsResult, refsErr := e.scipAdapter.FindReferences(ctx, symbolIdForLookup, refOpts)
 		if refsErr == nil && refsResult != nil {
+			refPaths := make([]string, 0, len(refsResult.References))
+			source := e.newSourceLineReader()
 			for _, ref := range refsResult.References {
 				impactRef := impact.Reference{
-					Kind: impact.ReferenceKind(ref.Kind),
+					Kind: impactReferenceKind(source, ref),
 					Location: &impact.Location{
 						FileId:    ref.Location.Path,
 						StartLine: ref.Location.Line,
 					},
+					IsTest: isTestFilePath(ref.Location.Path),
 				}
 				refs = append(refs, impactRef)
+				refPaths = append(refPaths, ref.Location.Path)
 			}
+			symbolInfo.TestOnlyExport = isTestOnlyExport(symbolInfo.Visibility, refPaths)
 		}
 	}
 
+	resp, err := e.analyzeImpactRefs(ctx, impactRun{
+		startTime:       startTime,
+		repoState:       repoState,
+		requestedDepth:  requestedDepth,
+		maxDepth:        depthLimits.Max,
+		depthClamped:    depthClamped,
+		backendContribs: backendContribs,
+		completeness:    completeness,
+		lookupId:        symbolIdForLookup,
+	}, symbolInfo, refs, opts)
+	if err != nil {
+		return nil, err
+	}
+	e.recordRiskScore(resp, repoState.RepoStateId)
+	e.storeImpact(resp, cacheKey, fingerprint)
+	return resp, nil
+}
+
+// impactRun carries the per-call state that AnalyzeImpact and
+// AnalyzeImpactFromRefs hand to analyzeImpactRefs.
+type impactRun struct {
+	startTime       time.Time
+	repoState       *RepoState
+	requestedDepth  int
+	maxDepth        int
+	depthClamped    bool
+	backendContribs []BackendContribution
+	completeness    CompletenessInfo
+	lookupId        string // symbol ID used for telemetry lookups
+}
+
+// analyzeImpactRefs runs impact analysis, risk scoring and module
+// aggregation for a resolved symbol over the given references.
+func (e *Engine) analyzeImpactRefs(ctx context.Context, run impactRun, symbolInfo *SymbolInfo, refs []impact.Reference, opts AnalyzeImpactOptions) (*AnalyzeImpactResponse, error) {
 	// Filter test references if not included
 	if !opts.IncludeTests {
 		refs = filterTestReferences(refs)
@@ -219,11 +321,21 @@ func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (
 		Kind:     impact.SymbolKind(symbolInfo.Kind),
 		ModuleId: symbolInfo.ModuleId,
 	}
-	if symbolInfo.Visibility != nil {
+	if symbolInfo.TestOnlyExport {
+		// Test-only exports carry internal risk: no production code depends on them
+		impactSymbol.Modifiers = []string{string(impact.VisibilityInternal)}
+	} else if symbolInfo.Visibility != nil {
 		impactSymbol.Modifiers = []string{symbolInfo.Visibility.Visibility}
 	}
 
-	result, err := analyzer.Analyze(impactSymbol, refs)
+	thresholds := e.riskThresholds()
+	result, err := analyzer.AnalyzeWithOptions(impactSymbol, refs, impact.AnalyzeOptions{
+		IncludeTests:        opts.IncludeTests,
+		OnlyBreakingChanges: opts.OnlyBreakingChanges,
+		ChangeKind:          impact.ChangeKind(opts.ChangeKind),
+		NewName:             opts.NewName,
+		RiskThresholds:      &thresholds,
+	})
 	if err != nil {
 		return nil, e.wrapError(err, errors.InternalError)
 	}
@@ -233,6 +345,10 @@ func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (
 	transitiveImpact := convertImpactItems(result.TransitiveImpact)
 	modulesAffected := convertModuleImpacts(result.ModulesAffected)
 
+	itemConfidence := func(item ImpactItem) float64 { return item.Confidence }
+	directImpact, filteredDirect := filterByConfidence(directImpact, opts.MinConfidence, itemConfidence)
+	transitiveImpact, filteredTransitive := filterByConfidence(transitiveImpact, opts.MinConfidence, itemConfidence)
+
 	// Apply budget
 	budget := e.compressor.GetBudget()
 	var truncationInfo *TruncationInfo
@@ -255,6 +371,10 @@ func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (
 		}
 	}
 
+	if run.depthClamped {
+		truncationInfo = withDepthClamp(truncationInfo, run.requestedDepth, run.maxDepth)
+	}
+
 	// Sort by impact priority
 	sortImpactItems(directImpact)
 	sortImpactItems(transitiveImpact)
@@ -272,11 +392,14 @@ func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (
 	// Convert visibility and risk score
 	visibility := symbolInfo.Visibility
 	riskScore := convertRiskScore(result.RiskScore)
+	riskThresholds := result.RiskThresholds
 
 	// Build provenance
-	provenance := e.buildProvenance(repoState, "full", startTime, backendContribs, completeness)
+	provenance := e.buildProvenance(ctx, run.repoState, "full", run.startTime, run.backendContribs, run.completeness)
 	if result.AnalysisLimits != nil && result.AnalysisLimits.HasLimitations() {
-		provenance.Warnings = append(provenance.Warnings, result.AnalysisLimits.Notes...)
+		for _, note := range result.AnalysisLimits.Notes {
+			e.addWarning(provenance, output.SeverityWarning, WarnAnalysisLimited, note)
+		}
 	}
 
 	// Generate drilldowns
@@ -297,22 +420,22 @@ func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (
 		}
 	}
 
-	drilldowns := e.generateDrilldowns(compTrunc, completeness, opts.SymbolId, topModule)
+	drilldowns := e.generateDrilldowns(compTrunc, run.completeness, opts.SymbolId, topModule)
 
 	// Get telemetry data if enabled
 	var observedUsage *ObservedUsageSummary
 	var blendedConfidence float64
 
 	if opts.IncludeTelemetry && e.config != nil && e.config.Telemetry.Enabled && e.db != nil {
-		observedUsage, blendedConfidence = e.getObservedUsageForImpact(symbolIdForLookup, opts.TelemetryPeriod)
+		observedUsage, blendedConfidence = e.getObservedUsageForImpact(run.lookupId, opts.TelemetryPeriod)
 
 		// Add telemetry factors to risk score if we have observed data
 		if observedUsage != nil && observedUsage.HasTelemetry && riskScore != nil {
-			riskScore = e.enhanceRiskScoreWithTelemetry(riskScore, observedUsage)
+			riskScore = e.enhanceRiskScoreWithTelemetry(riskScore, observedUsage, &riskThresholds)
 		}
 	} else {
 		// Static-only confidence
-		blendedConfidence = completeness.Score * 0.79
+		blendedConfidence = run.completeness.Score * 0.79
 	}
 
 	// v6.5: Gather related decisions for all affected modules
@@ -342,6 +465,19 @@ func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (
 		}
 	}
 
+	// Narrow each decision to the impacted symbols it actually names
+	if len(relatedDecisions) > 0 {
+		impacted := make([]ImpactItem, 0, 1+len(directImpact)+len(transitiveImpact))
+		impacted = append(impacted, ImpactItem{
+			StableId: symbolInfo.StableId,
+			Name:     symbolInfo.Name,
+			Location: symbolInfo.Location,
+		})
+		impacted = append(impacted, directImpact...)
+		impacted = append(impacted, transitiveImpact...)
+		e.linkDecisionsToImpact(relatedDecisions, impacted)
+	}
+
 	// v7.3: Get documentation that may need updating (top 5 docs mentioning this symbol)
 	var docsToUpdate []DocToUpdate
 	if symbolInfo.StableId != "" {
@@ -363,9 +499,92 @@ func (e *Engine) AnalyzeImpact(ctx context.Context, opts AnalyzeImpactOptions) (
 		TruncationInfo:    truncationInfo,
 		Provenance:        provenance,
 		Drilldowns:        drilldowns,
+
+		ChangeKind: responseChangeKind(opts.ChangeKind),
+		NewName:    opts.NewName,
+
+		OnlyBreakingChanges:   opts.OnlyBreakingChanges,
+		SuppressedNonBreaking: result.SuppressedNonBreaking,
+		FilteredByConfidence:  filteredDirect + filteredTransitive,
+		KindBreakdown:         result.KindBreakdown,
+		RiskThresholds:        &riskThresholds,
 	}, nil
 }
 
+// AnalyzeImpactFromRefs analyzes the impact of changing a symbol using
+// references supplied by the caller (grep, LSP, a saved file) instead of the
+// SCIP index. Analysis, risk scoring and module aggregation are the same as
+// AnalyzeImpact; completeness is reported lower and a warning notes that the
+// references were not verified against the index. opts.SymbolId defaults to
+// the symbol's stable ID.
+func (e *Engine) AnalyzeImpactFromRefs(ctx context.Context, symbol *SymbolInfo, refs []impact.Reference, opts AnalyzeImpactOptions) (*AnalyzeImpactResponse, error) {
+	startTime := time.Now()
+
+	if symbol == nil || symbol.StableId == "" {
+		return nil, fmt.Errorf("symbol with a stable ID is required")
+	}
+	if err := validateChangeKind(opts); err != nil {
+		return nil, err
+	}
+	if opts.SymbolId == "" {
+		opts.SymbolId = symbol.StableId
+	}
+
+	depthLimits := e.traversalConfig().ImpactLimits()
+	requestedDepth := opts.Depth
+	var depthClamped bool
+	opts.Depth, depthClamped = depthLimits.Resolve(opts.Depth)
+
+	repoState, err := e.GetRepoState(ctx, "full")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	// Work on copies; the caller's symbol and references stay untouched
+	symbolInfo := *symbol
+	e.applyDeclaredAPI(&symbolInfo)
+
+	externalRefs := make([]impact.Reference, len(refs))
+	refPaths := make([]string, 0, len(refs))
+	for i, ref := range refs {
+		if ref.Location != nil && ref.Location.FileId != "" {
+			ref.IsTest = ref.IsTest || isTestFilePath(ref.Location.FileId)
+			refPaths = append(refPaths, ref.Location.FileId)
+		}
+		externalRefs[i] = ref
+	}
+	symbolInfo.TestOnlyExport = isTestOnlyExport(symbolInfo.Visibility, refPaths)
+
+	completeness := CompletenessInfo{
+		Score:   0.5,
+		Reason:  "external-references",
+		Details: fmt.Sprintf("%d references supplied by the caller", len(refs)),
+	}
+	resp, err := e.analyzeImpactRefs(ctx, impactRun{
+		startTime:      startTime,
+		repoState:      repoState,
+		requestedDepth: requestedDepth,
+		maxDepth:       depthLimits.Max,
+		depthClamped:   depthClamped,
+		backendContribs: []BackendContribution{{
+			BackendId:    "external",
+			Available:    true,
+			Used:         true,
+			ResultCount:  len(refs),
+			Completeness: completeness.Score,
+		}},
+		completeness: completeness,
+		lookupId:     symbolInfo.StableId,
+	}, &symbolInfo, externalRefs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	e.addWarning(resp.Provenance, output.SeverityWarning, WarnAnalysisLimited,
+		"references were supplied externally and not verified against the SCIP index; impact may be incomplete")
+	return resp, nil
+}
+
 // getObservedUsageForImpact fetches telemetry data for impact analysis
 func (e *Engine) getObservedUsageForImpact(symbolID string, period string) (*ObservedUsageSummary, float64) {
 	storage := telemetry.NewStorage(e.db.Conn())
@@ -373,24 +592,15 @@ func (e *Engine) getObservedUsageForImpact(symbolID string, period string) (*Obs
 	// Compute period filter
 	periodFilter := computeTelemetryPeriodFilter(period)
 
-	// Get usage data
-	usages, err := storage.GetObservedUsage(symbolID, periodFilter)
-	if err != nil || len(usages) == 0 {
+	// Sum daily rollups rather than scanning raw usage rows
+	rollup, err := storage.GetUsageRollup(symbolID, periodFilter)
+	if err != nil || rollup == nil || len(rollup.Days) == 0 {
 		return &ObservedUsageSummary{HasTelemetry: false}, 0.79 // Static-only confidence
 	}
 
-	// Calculate totals
-	var totalCalls int64
-	var lastObserved time.Time
-	var matchQuality telemetry.MatchQuality
-
-	for i, u := range usages {
-		totalCalls += u.CallCount
-		if i == 0 {
-			lastObserved = u.IngestedAt
-			matchQuality = u.MatchQuality
-		}
-	}
+	totalCalls := rollup.TotalCalls
+	lastObserved := rollup.LastIngestedAt
+	matchQuality := rollup.MatchQuality
 
 	// Get callers
 	var callerServices []string
@@ -404,7 +614,11 @@ func (e *Engine) getObservedUsageForImpact(symbolID string, period string) (*Obs
 	}
 
 	// Compute trend
-	trend := computeUsageTrend(usages)
+	dailyCalls := make([]int64, len(rollup.Days))
+	for i, d := range rollup.Days {
+		dailyCalls[i] = d.CallCount
+	}
+	trend := computeUsageTrend(dailyCalls)
 
 	// Build summary
 	summary := &ObservedUsageSummary{
@@ -426,7 +640,7 @@ func (e *Engine) getObservedUsageForImpact(symbolID string, period string) (*Obs
 }
 
 // enhanceRiskScoreWithTelemetry adds telemetry factors to risk assessment
-func (e *Engine) enhanceRiskScoreWithTelemetry(riskScore *RiskScore, usage *ObservedUsageSummary) *RiskScore {
+func (e *Engine) enhanceRiskScoreWithTelemetry(riskScore *RiskScore, usage *ObservedUsageSummary, thresholds *impact.RiskThresholds) *RiskScore {
 	// Copy existing factors
 	enhanced := &RiskScore{
 		Level:       riskScore.Level,
@@ -440,6 +654,7 @@ func (e *Engine) enhanceRiskScoreWithTelemetry(riskScore *RiskScore, usage *Obse
 	usageFactor := RiskFactor{
 		Name:   "observed_usage",
 		Weight: 0.2,
+		Detail: fmt.Sprintf("%d calls observed in production", usage.TotalCalls),
 	}
 
 	if usage.TotalCalls == 0 {
@@ -458,6 +673,7 @@ func (e *Engine) enhanceRiskScoreWithTelemetry(riskScore *RiskScore, usage *Obse
 		diversityFactor := RiskFactor{
 			Name:   "caller_diversity",
 			Weight: 0.15,
+			Detail: fmt.Sprintf("called by %d services", len(usage.CallerServices)),
 		}
 		if len(usage.CallerServices) >= 5 {
 			diversityFactor.Value = 1.0 // Many callers - higher risk
@@ -474,6 +690,7 @@ func (e *Engine) enhanceRiskScoreWithTelemetry(riskScore *RiskScore, usage *Obse
 		trendFactor := RiskFactor{
 			Name:   "usage_trend",
 			Weight: 0.1,
+			Detail: fmt.Sprintf("usage is %s", usage.Trend),
 		}
 		switch usage.Trend {
 		case "increasing":
@@ -498,13 +715,7 @@ func (e *Engine) enhanceRiskScoreWithTelemetry(riskScore *RiskScore, usage *Obse
 	}
 
 	// Update level
-	if enhanced.Score >= 0.7 {
-		enhanced.Level = "high"
-	} else if enhanced.Score >= 0.4 {
-		enhanced.Level = "medium"
-	} else {
-		enhanced.Level = "low"
-	}
+	enhanced.Level = string(thresholds.Level(enhanced.Score))
 
 	// Update explanation with telemetry info
 	if usage.TotalCalls > 0 {
@@ -514,6 +725,7 @@ func (e *Engine) enhanceRiskScoreWithTelemetry(riskScore *RiskScore, usage *Obse
 		enhanced.Explanation = fmt.Sprintf("%s No runtime calls observed in telemetry window.",
 			enhanced.Explanation)
 	}
+	enhanced.Narrative = riskNarrative(enhanced.Factors)
 
 	return enhanced
 }
@@ -535,19 +747,20 @@ func computeTelemetryPeriodFilter(period string) string {
 	}
 }
 
-// computeUsageTrend calculates the usage trend from observed data
-func computeUsageTrend(usages []telemetry.ObservedUsage) string {
-	if len(usages) < 2 {
+// computeUsageTrend calculates the usage trend from call counts ordered
+// newest first
+func computeUsageTrend(calls []int64) string {
+	if len(calls) < 2 {
 		return "stable"
 	}
 
-	mid := len(usages) / 2
+	mid := len(calls) / 2
 	var recentCalls, olderCalls int64
-	for i, u := range usages {
+	for i, c := range calls {
 		if i < mid {
-			recentCalls += u.CallCount
+			recentCalls += c
 		} else {
-			olderCalls += u.CallCount
+			olderCalls += c
 		}
 	}
 
@@ -610,6 +823,8 @@ func convertImpactItems(items []impact.ImpactItem) []ImpactItem {
 			Distance:   item.Distance,
 			ModuleId:   item.ModuleId,
 			Confidence: item.Confidence,
+			Breaking:   item.Breaking,
+			UpdateKind: item.UpdateKind,
 		}
 		if item.Location != nil {
 			ri.Location = &LocationInfo{
@@ -631,6 +846,28 @@ func convertImpactItems(items []impact.ImpactItem) []ImpactItem {
 	return result
 }
 
+// validateChangeKind checks an analyzeImpact change kind and that NewName
+// comes only with a rename.
+func validateChangeKind(opts AnalyzeImpactOptions) error {
+	kind, err := impact.ParseChangeKind(opts.ChangeKind)
+	if err != nil {
+		return errors.NewCkbError(errors.InvalidParameters, err.Error(), nil, nil, nil)
+	}
+	if opts.NewName != "" && kind != impact.ChangeRename {
+		return errors.NewCkbError(errors.InvalidParameters, "newName is only valid with changeKind rename", nil, nil, nil)
+	}
+	return nil
+}
+
+// responseChangeKind returns the change kind to report: modifications are
+// the default and left implicit.
+func responseChangeKind(kind string) string {
+	if kind == string(impact.ChangeModify) {
+		return ""
+	}
+	return kind
+}
+
 // convertModuleImpacts converts module impacts to response format.
 func convertModuleImpacts(modules []impact.ModuleSummary) []ModuleImpact {
 	result := make([]ModuleImpact, 0, len(modules))
@@ -662,6 +899,7 @@ func convertRiskScore(r *impact.RiskScore) *RiskScore {
 			Name:   f.Name,
 			Value:  f.Value,
 			Weight: f.Weight,
+			Detail: f.Detail,
 		})
 	}
 
@@ -669,6 +907,7 @@ func convertRiskScore(r *impact.RiskScore) *RiskScore {
 		Level:       string(r.Level),
 		Score:       r.Score,
 		Explanation: r.Explanation,
+		Narrative:   riskNarrative(factors),
 		Factors:     factors,
 	}
 }
@@ -676,11 +915,12 @@ func convertRiskScore(r *impact.RiskScore) *RiskScore {
 // sortImpactItems sorts impact items by priority.
 func sortImpactItems(items []ImpactItem) {
 	kindPriority := map[string]int{
-		"direct-caller":     1,
-		"transitive-caller": 2,
-		"type-dependency":   3,
-		"test-dependency":   4,
-		"unknown":           5,
+		"direct-caller":         1,
+		"annotation-dependency": 2,
+		"transitive-caller":     3,
+		"type-dependency":       4,
+		"test-dependency":       5,
+		"unknown":               6,
 	}
 
 	sort.Slice(items, func(i, j int) bool {
@@ -689,13 +929,33 @@ func sortImpactItems(items []ImpactItem) {
 		if pi != pj {
 			return pi < pj
 		}
-		if items[i].Confidence != items[j].Confidence {
+		if !floatEqual(items[i].Confidence, items[j].Confidence) {
 			return items[i].Confidence > items[j].Confidence
 		}
 		return items[i].StableId < items[j].StableId
 	})
 }
 
+// impactReferenceKind maps a backend reference to its impact reference
+// kind. Backends don't mark annotation and decorator uses, so plain
+// references are checked against their source line for a leading "@".
+func impactReferenceKind(source *sourceLineReader, ref backends.Reference) impact.ReferenceKind {
+	kind := impact.ReferenceKind(ref.Kind)
+	switch ref.Kind {
+	case "reference", "read", "call":
+	default:
+		return kind
+	}
+	lines := source.lines(ref.Location.Path)
+	if ref.Location.Line < 1 || ref.Location.Line > len(lines) {
+		return kind
+	}
+	if impact.IsAnnotationSite(lines[ref.Location.Line-1], ref.Location.Column) {
+		return impact.RefAnnotation
+	}
+	return kind
+}
+
 // getDocsToUpdate returns documentation that may need updating when a symbol changes (v7.3).
 func (e *Engine) getDocsToUpdate(symbolID string, limit int) []DocToUpdate {
 	refs, err := e.GetDocsForSymbol(symbolID, limit)
@@ -0,0 +1,433 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ckb/internal/impact"
+	"ckb/internal/output"
+)
+
+const (
+	// maxImpactBatchSymbols bounds a single analyzeImpactBatch call.
+	maxImpactBatchSymbols = 100
+
+	// defaultImpactBatchWorkers is how many symbols are analyzed
+	// concurrently unless configured otherwise.
+	defaultImpactBatchWorkers = 4
+)
+
+// AnalyzeImpactBatchOptions contains options for analyzeImpactBatch.
+type AnalyzeImpactBatchOptions struct {
+	SymbolIds    []string
+	Depth        int
+	IncludeTests bool
+	Concurrency  int // Symbols analyzed in parallel; 0 uses the configured default
+}
+
+// AnalyzeImpactBatchResponse is the combined impact of changing a set of
+// symbols together.
+type AnalyzeImpactBatchResponse struct {
+	RiskScore        *RiskScore             `json:"riskScore"`
+	AffectedItems    []ImpactItem           `json:"affectedItems"`
+	ModulesAffected  []ModuleImpact         `json:"modulesAffected"`
+	Symbols          []SymbolImpactEntry    `json:"symbols"`
+	AnalyzedCount    int                    `json:"analyzedCount"`
+	FailedCount      int                    `json:"failedCount,omitempty"`
+	RelatedDecisions []RelatedDecision      `json:"relatedDecisions,omitempty"`
+	Truncated        bool                   `json:"truncated,omitempty"`
+	TruncationInfo   *TruncationInfo        `json:"truncationInfo,omitempty"`
+	RiskThresholds   *impact.RiskThresholds `json:"riskThresholds,omitempty"` // Mapping that produced RiskScore.Level
+	Provenance       *Provenance            `json:"provenance"`
+	Drilldowns       []output.Drilldown     `json:"drilldowns,omitempty"`
+}
+
+// SymbolImpactEntry is one symbol's share of a batch.
+type SymbolImpactEntry struct {
+	SymbolId        string      `json:"symbolId"` // As requested
+	Symbol          *SymbolInfo `json:"symbol,omitempty"`
+	RiskScore       *RiskScore  `json:"riskScore,omitempty"`
+	DirectCount     int         `json:"directCount"`
+	TransitiveCount int         `json:"transitiveCount"`
+	ModuleCount     int         `json:"moduleCount"`
+	Error           string      `json:"error,omitempty"`
+}
+
+// AnalyzeImpactBatch analyzes the impact of changing several symbols at
+// once. Each symbol is analyzed with AnalyzeImpact, a few at a time, and the
+// results are merged: an item affected through several symbols is reported
+// once at its closest distance, and symbols in the batch are not counted as
+// impact of each other. Symbols that fail are reported in the breakdown
+// without failing the batch. Every list is sorted deterministically so the
+// output is stable across runs.
+func (e *Engine) AnalyzeImpactBatch(ctx context.Context, opts AnalyzeImpactBatchOptions) (*AnalyzeImpactBatchResponse, error) {
+	startTime := time.Now()
+
+	symbolIds := dedupeSymbolIds(opts.SymbolIds)
+	if len(symbolIds) == 0 {
+		return nil, fmt.Errorf("symbolIds must contain at least one symbol")
+	}
+	if len(symbolIds) > maxImpactBatchSymbols {
+		return nil, fmt.Errorf("too many symbols: %d (max %d)", len(symbolIds), maxImpactBatchSymbols)
+	}
+
+	results, errs := runImpactBatch(ctx, symbolIds, e.impactBatchConcurrency(opts), func(ctx context.Context, id string) (*AnalyzeImpactResponse, error) {
+		return e.AnalyzeImpact(ctx, AnalyzeImpactOptions{
+			SymbolId:     id,
+			Depth:        opts.Depth,
+			IncludeTests: opts.IncludeTests,
+		})
+	})
+
+	resp := aggregateImpactBatch(symbolIds, results, errs)
+	if resp.AnalyzedCount == 0 {
+		return nil, errs[0]
+	}
+
+	budget := e.compressor.GetBudget()
+	if len(resp.AffectedItems) > budget.MaxImpactItems {
+		resp.TruncationInfo = &TruncationInfo{
+			Reason:        "max-items",
+			OriginalCount: len(resp.AffectedItems),
+			ReturnedCount: budget.MaxImpactItems,
+		}
+		resp.Truncated = true
+		resp.AffectedItems = resp.AffectedItems[:budget.MaxImpactItems]
+	}
+	if len(resp.ModulesAffected) > budget.MaxModules {
+		resp.ModulesAffected = resp.ModulesAffected[:budget.MaxModules]
+	}
+
+	for _, r := range results {
+		if r != nil && r.Provenance != nil {
+			resp.Provenance = r.Provenance
+			break
+		}
+	}
+	if resp.Provenance != nil {
+		resp.Provenance.QueryDurationMs = time.Since(startTime).Milliseconds()
+		if resp.FailedCount > 0 {
+			e.addWarning(resp.Provenance, output.SeverityWarning, WarnAnalysisLimited,
+				fmt.Sprintf("%d of %d symbols could not be analyzed", resp.FailedCount, len(symbolIds)))
+		}
+	}
+	resp.Drilldowns = impactBatchDrilldowns(resp.Symbols)
+
+	return resp, nil
+}
+
+// impactBatchConcurrency returns the number of symbols to analyze in
+// parallel: the per-call option, else backendLimits.impactBatchConcurrency,
+// else defaultImpactBatchWorkers.
+func (e *Engine) impactBatchConcurrency(opts AnalyzeImpactBatchOptions) int {
+	if opts.Concurrency > 0 {
+		return opts.Concurrency
+	}
+	if e.config != nil && e.config.BackendLimits.ImpactBatchConcurrency > 0 {
+		return e.config.BackendLimits.ImpactBatchConcurrency
+	}
+	return defaultImpactBatchWorkers
+}
+
+// runImpactBatch calls analyze for every symbol on a pool of workers
+// goroutines. Results and errors are stored by position, so aggregation sees
+// the same input whatever the scheduling. Symbols not yet started when ctx
+// is cancelled get ctx's error.
+func runImpactBatch(ctx context.Context, symbolIds []string, workers int, analyze func(ctx context.Context, id string) (*AnalyzeImpactResponse, error)) ([]*AnalyzeImpactResponse, []error) {
+	results := make([]*AnalyzeImpactResponse, len(symbolIds))
+	errs := make([]error, len(symbolIds))
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(symbolIds) {
+		workers = len(symbolIds)
+	}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				results[i], errs[i] = analyze(ctx, symbolIds[i])
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(symbolIds); next++ {
+		select {
+		case <-ctx.Done():
+			break feed
+		case work <- next:
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	for i := next; i < len(symbolIds); i++ {
+		errs[i] = ctx.Err()
+	}
+	return results, errs
+}
+
+// dedupeSymbolIds trims the requested IDs and drops blanks and repeats,
+// keeping the first occurrence.
+func dedupeSymbolIds(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+// aggregateImpactBatch merges per-symbol results. results and errs are
+// indexed like symbolIds.
+func aggregateImpactBatch(symbolIds []string, results []*AnalyzeImpactResponse, errs []error) *AnalyzeImpactBatchResponse {
+	resp := &AnalyzeImpactBatchResponse{
+		Symbols: make([]SymbolImpactEntry, 0, len(symbolIds)),
+	}
+
+	inBatch := make(map[string]bool, len(symbolIds))
+	for i, r := range results {
+		inBatch[symbolIds[i]] = true
+		if r != nil && r.Symbol != nil {
+			inBatch[r.Symbol.StableId] = true
+		}
+	}
+
+	items := make(map[string]ImpactItem)
+	moduleNames := make(map[string]string)
+	seenDecisions := make(map[string]bool)
+	var symbolFactors []RiskFactor
+
+	for i, id := range symbolIds {
+		entry := SymbolImpactEntry{SymbolId: id}
+		r := results[i]
+		if errs[i] != nil || r == nil {
+			entry.Error = "not analyzed"
+			if errs[i] != nil {
+				entry.Error = errs[i].Error()
+			}
+			resp.FailedCount++
+			resp.Symbols = append(resp.Symbols, entry)
+			continue
+		}
+
+		resp.AnalyzedCount++
+		entry.Symbol = r.Symbol
+		entry.RiskScore = r.RiskScore
+		entry.DirectCount = len(r.DirectImpact)
+		entry.TransitiveCount = len(r.TransitiveImpact)
+		entry.ModuleCount = len(r.ModulesAffected)
+		resp.Symbols = append(resp.Symbols, entry)
+
+		if r.Truncated {
+			resp.Truncated = true
+		}
+		for _, m := range r.ModulesAffected {
+			if m.Name != "" {
+				moduleNames[m.ModuleId] = m.Name
+			}
+		}
+		for _, list := range [][]ImpactItem{r.DirectImpact, r.TransitiveImpact} {
+			for _, item := range list {
+				if inBatch[item.StableId] {
+					continue
+				}
+				if existing, ok := items[item.StableId]; ok {
+					item = mergeImpactItem(existing, item)
+				}
+				items[item.StableId] = item
+			}
+		}
+		for _, d := range r.RelatedDecisions {
+			if !seenDecisions[d.ID] {
+				seenDecisions[d.ID] = true
+				resp.RelatedDecisions = append(resp.RelatedDecisions, d)
+			}
+		}
+
+		if r.RiskThresholds != nil {
+			resp.RiskThresholds = r.RiskThresholds
+		}
+		if r.RiskScore != nil {
+			name := id
+			if r.Symbol != nil && r.Symbol.Name != "" {
+				name = r.Symbol.Name
+			}
+			symbolFactors = append(symbolFactors, RiskFactor{
+				Name:   id,
+				Value:  r.RiskScore.Score,
+				Weight: 1,
+				Detail: fmt.Sprintf("%s (%s risk)", name, r.RiskScore.Level),
+			})
+		}
+	}
+
+	resp.AffectedItems = make([]ImpactItem, 0, len(items))
+	for _, item := range items {
+		resp.AffectedItems = append(resp.AffectedItems, item)
+	}
+	sortBatchImpactItems(resp.AffectedItems)
+	resp.ModulesAffected = batchModuleImpacts(resp.AffectedItems, moduleNames)
+	sort.SliceStable(resp.RelatedDecisions, func(i, j int) bool {
+		return resp.RelatedDecisions[i].ID < resp.RelatedDecisions[j].ID
+	})
+	if resp.RiskThresholds == nil {
+		defaults := impact.DefaultRiskThresholds()
+		resp.RiskThresholds = &defaults
+	}
+	resp.RiskScore = batchRiskScore(symbolFactors, resp.RiskThresholds, len(resp.AffectedItems), len(resp.ModulesAffected))
+
+	return resp
+}
+
+// mergeImpactItem combines two sightings of the same affected item, keeping
+// the closest distance (and its kind) and the highest confidence.
+func mergeImpactItem(a, b ImpactItem) ImpactItem {
+	merged := a
+	if b.Distance < a.Distance || (b.Distance == a.Distance && impactKindPriority(b.Kind) < impactKindPriority(a.Kind)) {
+		merged = b
+	}
+	if a.Confidence > merged.Confidence {
+		merged.Confidence = a.Confidence
+	}
+	if b.Confidence > merged.Confidence {
+		merged.Confidence = b.Confidence
+	}
+	return merged
+}
+
+func impactKindPriority(kind string) int {
+	switch kind {
+	case "direct-caller":
+		return 1
+	case "transitive-caller":
+		return 2
+	case "type-dependency":
+		return 3
+	case "test-dependency":
+		return 4
+	}
+	return 5
+}
+
+// sortBatchImpactItems orders items by distance, then as sortImpactItems does.
+func sortBatchImpactItems(items []ImpactItem) {
+	sortImpactItems(items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Distance < items[j].Distance
+	})
+}
+
+// batchModuleImpacts counts the merged items per module, so a module reached
+// through several symbols isn't counted twice.
+func batchModuleImpacts(items []ImpactItem, names map[string]string) []ModuleImpact {
+	byModule := make(map[string]*ModuleImpact)
+	for _, item := range items {
+		if item.ModuleId == "" {
+			continue
+		}
+		m, ok := byModule[item.ModuleId]
+		if !ok {
+			m = &ModuleImpact{ModuleId: item.ModuleId, Name: names[item.ModuleId]}
+			byModule[item.ModuleId] = m
+		}
+		m.ImpactCount++
+		if item.Distance <= 1 {
+			m.DirectCount++
+		}
+	}
+
+	modules := make([]ModuleImpact, 0, len(byModule))
+	for _, m := range byModule {
+		modules = append(modules, *m)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].ImpactCount != modules[j].ImpactCount {
+			return modules[i].ImpactCount > modules[j].ImpactCount
+		}
+		return modules[i].ModuleId < modules[j].ModuleId
+	})
+	return modules
+}
+
+// batchRiskScore rates a batch by its riskiest symbol: changing several
+// symbols together is at least as risky as changing the worst of them. The
+// factors are the per-symbol scores, so the narrative names the symbols
+// driving the risk. The level comes from the thresholds the symbols were
+// rated with.
+func batchRiskScore(symbolFactors []RiskFactor, thresholds *impact.RiskThresholds, itemCount, moduleCount int) *RiskScore {
+	sort.SliceStable(symbolFactors, func(i, j int) bool {
+		if symbolFactors[i].Value != symbolFactors[j].Value {
+			return symbolFactors[i].Value > symbolFactors[j].Value
+		}
+		return symbolFactors[i].Name < symbolFactors[j].Name
+	})
+
+	score := 0.0
+	high := 0
+	for _, f := range symbolFactors {
+		if f.Value > score {
+			score = f.Value
+		}
+		if f.Value >= thresholds.HighMin {
+			high++
+		}
+	}
+
+	return &RiskScore{
+		Level: string(thresholds.Level(score)),
+		Score: score,
+		Explanation: fmt.Sprintf("%d of %d symbols are high risk; %d unique items affected across %d modules.",
+			high, len(symbolFactors), itemCount, moduleCount),
+		Narrative: riskNarrative(symbolFactors),
+		Factors:   symbolFactors,
+	}
+}
+
+// impactBatchDrilldowns suggests per-symbol impact analysis for the riskiest
+// symbols in the batch.
+func impactBatchDrilldowns(entries []SymbolImpactEntry) []output.Drilldown {
+	ranked := make([]SymbolImpactEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.RiskScore != nil {
+			ranked = append(ranked, entry)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].RiskScore.Score > ranked[j].RiskScore.Score
+	})
+
+	var drilldowns []output.Drilldown
+	for i, entry := range ranked {
+		if i >= 3 {
+			break
+		}
+		name := entry.SymbolId
+		if entry.Symbol != nil && entry.Symbol.Name != "" {
+			name = entry.Symbol.Name
+		}
+		drilldowns = append(drilldowns, output.Drilldown{
+			Label:          "Impact of changing " + name,
+			Query:          "analyzeImpact " + entry.SymbolId,
+			Tool:           "analyzeImpact",
+			Params:         map[string]interface{}{"symbolId": entry.SymbolId},
+			RelevanceScore: 0.8 - float64(i)*0.1,
+		})
+	}
+	return drilldowns
+}
@@ -0,0 +1,246 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"ckb/internal/impact"
+)
+
+func TestDedupeSymbolIds(t *testing.T) {
+	got := dedupeSymbolIds([]string{"a", " b ", "", "a", "c", "b"})
+	want := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupeSymbolIds = %v, want %v", got, want)
+	}
+}
+
+func TestAggregateImpactBatch(t *testing.T) {
+	results := []*AnalyzeImpactResponse{
+		{
+			Symbol:    &SymbolInfo{StableId: "sym-a", Name: "A"},
+			RiskScore: &RiskScore{Level: "medium", Score: 0.5},
+			DirectImpact: []ImpactItem{
+				{StableId: "caller-1", Kind: "direct-caller", Distance: 1, ModuleId: "mod/x", Confidence: 0.9},
+				{StableId: "sym-b", Kind: "direct-caller", Distance: 1, ModuleId: "mod/y", Confidence: 0.9},
+			},
+			TransitiveImpact: []ImpactItem{
+				{StableId: "caller-2", Kind: "transitive-caller", Distance: 2, ModuleId: "mod/y", Confidence: 0.6},
+			},
+			ModulesAffected:  []ModuleImpact{{ModuleId: "mod/x", Name: "x"}},
+			RelatedDecisions: []RelatedDecision{{ID: "ADR-2"}},
+		},
+		nil,
+		{
+			Symbol:    &SymbolInfo{StableId: "sym-b", Name: "B"},
+			RiskScore: &RiskScore{Level: "high", Score: 0.8},
+			DirectImpact: []ImpactItem{
+				{StableId: "caller-2", Kind: "direct-caller", Distance: 1, ModuleId: "mod/y", Confidence: 0.7},
+				{StableId: "caller-3", Kind: "direct-caller", Distance: 1, ModuleId: "mod/x", Confidence: 0.8},
+			},
+			RelatedDecisions: []RelatedDecision{{ID: "ADR-1"}, {ID: "ADR-2"}},
+		},
+	}
+	errs := []error{nil, errors.New("symbol not found"), nil}
+
+	resp := aggregateImpactBatch([]string{"sym-a", "missing", "sym-b"}, results, errs)
+
+	if resp.AnalyzedCount != 2 || resp.FailedCount != 1 {
+		t.Errorf("analyzed/failed = %d/%d, want 2/1", resp.AnalyzedCount, resp.FailedCount)
+	}
+
+	// sym-b is in the batch, so it isn't impact; caller-2 is merged at its
+	// closest distance with the higher confidence.
+	var ids []string
+	for _, item := range resp.AffectedItems {
+		ids = append(ids, item.StableId)
+	}
+	if want := []string{"caller-1", "caller-3", "caller-2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("affected items = %v, want %v", ids, want)
+	}
+	for _, item := range resp.AffectedItems {
+		if item.StableId == "caller-2" && (item.Distance != 1 || item.Kind != "direct-caller" || item.Confidence != 0.7) {
+			t.Errorf("caller-2 merged as %+v", item)
+		}
+	}
+
+	if len(resp.ModulesAffected) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(resp.ModulesAffected))
+	}
+	if m := resp.ModulesAffected[0]; m.ModuleId != "mod/x" || m.Name != "x" || m.ImpactCount != 2 || m.DirectCount != 2 {
+		t.Errorf("first module = %+v", m)
+	}
+
+	if resp.RiskScore.Level != "high" || resp.RiskScore.Score != 0.8 {
+		t.Errorf("aggregate risk = %s/%.2f, want high/0.80", resp.RiskScore.Level, resp.RiskScore.Score)
+	}
+	if resp.RiskScore.Narrative != "Primary risk: B (high risk); secondary: A (medium risk)" {
+		t.Errorf("narrative = %q", resp.RiskScore.Narrative)
+	}
+
+	if got := resp.Symbols[1]; got.SymbolId != "missing" || got.Error != "symbol not found" {
+		t.Errorf("failed entry = %+v", got)
+	}
+	if got := resp.Symbols[2]; got.DirectCount != 2 || got.TransitiveCount != 0 {
+		t.Errorf("sym-b entry = %+v", got)
+	}
+
+	if len(resp.RelatedDecisions) != 2 || resp.RelatedDecisions[0].ID != "ADR-1" {
+		t.Errorf("decisions = %+v", resp.RelatedDecisions)
+	}
+}
+
+func TestAggregateImpactBatchDeterministic(t *testing.T) {
+	results := []*AnalyzeImpactResponse{
+		{
+			Symbol:       &SymbolInfo{StableId: "a"},
+			DirectImpact: []ImpactItem{{StableId: "z", Kind: "direct-caller", Distance: 1, ModuleId: "m2"}, {StableId: "y", Kind: "direct-caller", Distance: 1, ModuleId: "m1"}},
+		},
+		{
+			Symbol:       &SymbolInfo{StableId: "b"},
+			DirectImpact: []ImpactItem{{StableId: "x", Kind: "direct-caller", Distance: 1, ModuleId: "m3"}},
+		},
+	}
+	first := aggregateImpactBatch([]string{"a", "b"}, results, make([]error, 2))
+	for i := 0; i < 20; i++ {
+		again := aggregateImpactBatch([]string{"a", "b"}, results, make([]error, 2))
+		if !reflect.DeepEqual(first.AffectedItems, again.AffectedItems) || !reflect.DeepEqual(first.ModulesAffected, again.ModulesAffected) {
+			t.Fatal("aggregation order is not deterministic")
+		}
+	}
+	if first.ModulesAffected[0].ModuleId != "m1" {
+		t.Errorf("ties should order by module ID, got %s first", first.ModulesAffected[0].ModuleId)
+	}
+}
+
+// fakeBatchAnalyze stands in for AnalyzeImpact: each symbol gets a fixed
+// result whose impact overlaps its neighbours', after a delay that varies
+// per symbol so workers finish out of order.
+func fakeBatchAnalyze(delay time.Duration) func(ctx context.Context, id string) (*AnalyzeImpactResponse, error) {
+	return func(ctx context.Context, id string) (*AnalyzeImpactResponse, error) {
+		var n int
+		_, _ = fmt.Sscanf(id, "sym-%d", &n)
+		time.Sleep(delay * time.Duration(1+(n*7)%5))
+		if n%11 == 10 {
+			return nil, fmt.Errorf("symbol not found: %s", id)
+		}
+		return &AnalyzeImpactResponse{
+			Symbol:    &SymbolInfo{StableId: id, Name: id},
+			RiskScore: &RiskScore{Level: "medium", Score: float64(n%10) / 10},
+			DirectImpact: []ImpactItem{
+				{StableId: fmt.Sprintf("caller-%d", n), Kind: "direct-caller", Distance: 1, ModuleId: fmt.Sprintf("mod/%d", n%3), Confidence: 0.9},
+				{StableId: fmt.Sprintf("caller-%d", n+1), Kind: "direct-caller", Distance: 1, ModuleId: fmt.Sprintf("mod/%d", (n+1)%3), Confidence: 0.8},
+			},
+			TransitiveImpact: []ImpactItem{
+				{StableId: fmt.Sprintf("caller-%d", n+2), Kind: "transitive-caller", Distance: 2, ModuleId: fmt.Sprintf("mod/%d", (n+2)%3), Confidence: 0.6},
+			},
+			ModulesAffected:  []ModuleImpact{{ModuleId: fmt.Sprintf("mod/%d", n%3), Name: fmt.Sprintf("m%d", n%3)}},
+			RelatedDecisions: []RelatedDecision{{ID: fmt.Sprintf("ADR-%d", n%4)}},
+		}, nil
+	}
+}
+
+func batchSymbolIds(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("sym-%d", i)
+	}
+	return ids
+}
+
+func TestAggregateImpactBatchRiskThresholds(t *testing.T) {
+	thresholds := &impact.RiskThresholds{MediumMin: 0.3, HighMin: 0.6, CriticalMin: 0.85}
+	results := []*AnalyzeImpactResponse{
+		{Symbol: &SymbolInfo{StableId: "a", Name: "A"}, RiskScore: &RiskScore{Level: "high", Score: 0.65}, RiskThresholds: thresholds},
+		{Symbol: &SymbolInfo{StableId: "b", Name: "B"}, RiskScore: &RiskScore{Level: "critical", Score: 0.9}, RiskThresholds: thresholds},
+	}
+
+	resp := aggregateImpactBatch([]string{"a", "b"}, results, []error{nil, nil})
+	if resp.RiskThresholds != thresholds {
+		t.Errorf("thresholds = %+v, want %+v", resp.RiskThresholds, thresholds)
+	}
+	if resp.RiskScore.Level != "critical" {
+		t.Errorf("level = %q, want critical", resp.RiskScore.Level)
+	}
+	if want := "2 of 2 symbols are high risk; 0 unique items affected across 0 modules."; resp.RiskScore.Explanation != want {
+		t.Errorf("explanation = %q, want %q", resp.RiskScore.Explanation, want)
+	}
+
+	// Without per-symbol thresholds the defaults apply
+	plain := *results[0]
+	plain.RiskThresholds = nil
+	resp = aggregateImpactBatch([]string{"a"}, []*AnalyzeImpactResponse{&plain}, []error{nil})
+	if resp.RiskScore.Level != "medium" || resp.RiskThresholds.HighMin != 0.7 {
+		t.Errorf("default level = %q with %+v", resp.RiskScore.Level, resp.RiskThresholds)
+	}
+}
+
+func TestRunImpactBatchMatchesSequential(t *testing.T) {
+	ids := batchSymbolIds(30)
+	analyze := fakeBatchAnalyze(100 * time.Microsecond)
+
+	seqResults, seqErrs := runImpactBatch(context.Background(), ids, 1, analyze)
+	want := aggregateImpactBatch(ids, seqResults, seqErrs)
+	if want.FailedCount == 0 || want.AnalyzedCount == 0 {
+		t.Fatalf("fixture should mix failures and successes, got %d/%d", want.AnalyzedCount, want.FailedCount)
+	}
+
+	for _, workers := range []int{2, 4, 8, 64} {
+		results, errs := runImpactBatch(context.Background(), ids, workers, analyze)
+		got := aggregateImpactBatch(ids, results, errs)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%d workers: result differs from sequential run", workers)
+		}
+	}
+}
+
+func TestRunImpactBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Every symbol is either analyzed or reported as cancelled
+	results, errs := runImpactBatch(ctx, batchSymbolIds(5), 2, func(ctx context.Context, id string) (*AnalyzeImpactResponse, error) {
+		return &AnalyzeImpactResponse{}, nil
+	})
+	for i := range results {
+		if results[i] == nil && !errors.Is(errs[i], context.Canceled) {
+			t.Errorf("symbol %d: error %v, want context.Canceled", i, errs[i])
+		}
+	}
+}
+
+func TestImpactBatchConcurrency(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if got := engine.impactBatchConcurrency(AnalyzeImpactBatchOptions{}); got != defaultImpactBatchWorkers {
+		t.Errorf("default = %d, want %d", got, defaultImpactBatchWorkers)
+	}
+	engine.config.BackendLimits.ImpactBatchConcurrency = 2
+	if got := engine.impactBatchConcurrency(AnalyzeImpactBatchOptions{}); got != 2 {
+		t.Errorf("configured = %d, want 2", got)
+	}
+	if got := engine.impactBatchConcurrency(AnalyzeImpactBatchOptions{Concurrency: 6}); got != 6 {
+		t.Errorf("per-call = %d, want 6", got)
+	}
+}
+
+// BenchmarkRunImpactBatch compares a 30-symbol batch analyzed sequentially
+// and on the default pool, with each symbol taking about a millisecond.
+func BenchmarkRunImpactBatch(b *testing.B) {
+	ids := batchSymbolIds(30)
+	analyze := fakeBatchAnalyze(time.Millisecond / 3)
+
+	for _, workers := range []int{1, defaultImpactBatchWorkers} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				results, errs := runImpactBatch(context.Background(), ids, workers, analyze)
+				aggregateImpactBatch(ids, results, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package query
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ckb/internal/output"
+)
+
+// defaultImpactCacheEntries bounds the number of analyzeImpact results kept
+// for reuse.
+const defaultImpactCacheEntries = 128
+
+// ImpactDigest identifies an analyzeImpact result. Passing it back as
+// AnalyzeImpactOptions.Previous returns the stored result instead of
+// re-running the analysis, as long as the repo state and the symbol are
+// unchanged.
+type ImpactDigest struct {
+	SymbolId    string `json:"symbolId"`
+	RepoStateId string `json:"repoStateId"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// impactCacheKey identifies a stored result. Options are part of the key
+// because depth and filters change the result.
+type impactCacheKey struct {
+	symbolId    string
+	repoStateId string
+	opts        AnalyzeImpactOptions
+}
+
+func newImpactCacheKey(symbolId, repoStateId string, opts AnalyzeImpactOptions) impactCacheKey {
+	opts.SymbolId = ""
+	opts.Previous = nil
+	return impactCacheKey{symbolId: symbolId, repoStateId: repoStateId, opts: opts}
+}
+
+type impactCacheEntry struct {
+	key         impactCacheKey
+	fingerprint string
+	resp        *AnalyzeImpactResponse
+}
+
+// impactResultCache is an LRU store of analyzeImpact results. Entries for
+// older repo states are never served, since the state is part of the key,
+// and age out as newer results push them to the back.
+type impactResultCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	entries  map[impactCacheKey]*list.Element
+}
+
+func newImpactResultCache(capacity int) *impactResultCache {
+	if capacity <= 0 {
+		capacity = defaultImpactCacheEntries
+	}
+	return &impactResultCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[impactCacheKey]*list.Element),
+	}
+}
+
+// get returns the stored result for key if it was computed for a symbol
+// with the same fingerprint.
+func (c *impactResultCache) get(key impactCacheKey, fingerprint string) *AnalyzeImpactResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := el.Value.(*impactCacheEntry)
+	if entry.fingerprint != fingerprint {
+		return nil
+	}
+	c.order.MoveToFront(el)
+	return entry.resp
+}
+
+// put stores a result, evicting the least recently used one when full.
+func (c *impactResultCache) put(key impactCacheKey, fingerprint string, resp *AnalyzeImpactResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*impactCacheEntry)
+		entry.fingerprint = fingerprint
+		entry.resp = resp
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&impactCacheEntry{key: key, fingerprint: fingerprint, resp: resp})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*impactCacheEntry).key)
+	}
+}
+
+// clear drops every stored result, for when the index is reloaded under an
+// unchanged repo state.
+func (c *impactResultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[impactCacheKey]*list.Element)
+}
+
+// impactSymbolFingerprint hashes the symbol facts impact analysis starts
+// from, so a result isn't reused for a symbol that resolved differently.
+func impactSymbolFingerprint(s *SymbolInfo) string {
+	parts := []string{s.StableId, s.Name, s.Kind, s.ContainerName, s.ModuleId}
+	if s.Visibility != nil {
+		parts = append(parts, s.Visibility.Visibility)
+	}
+	if s.Location != nil {
+		parts = append(parts, s.Location.FileId, strconv.Itoa(s.Location.StartLine))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cachedImpact returns the stored result a previous digest points at,
+// marked as served from cache, or nil when the digest no longer matches
+// the repo state or symbol or the result has been evicted. Results for an
+// unknown repo state are never reused: without git, changes can't be
+// detected.
+func (e *Engine) cachedImpact(prev *ImpactDigest, key impactCacheKey, fingerprint string, startTime time.Time) *AnalyzeImpactResponse {
+	if prev == nil || e.impactCache == nil || key.repoStateId == "unknown" {
+		return nil
+	}
+	if prev.SymbolId != key.symbolId || prev.RepoStateId != key.repoStateId || prev.Fingerprint != fingerprint {
+		return nil
+	}
+	cached := e.impactCache.get(key, fingerprint)
+	if cached == nil {
+		return nil
+	}
+
+	resp := copyImpactProvenance(cached)
+	if resp.Provenance != nil {
+		resp.Provenance.FromCache = true
+		resp.Provenance.QueryDurationMs = time.Since(startTime).Milliseconds()
+	}
+	return resp
+}
+
+// storeImpact records a fresh result and its digest for later reuse.
+func (e *Engine) storeImpact(resp *AnalyzeImpactResponse, key impactCacheKey, fingerprint string) {
+	resp.Digest = &ImpactDigest{
+		SymbolId:    key.symbolId,
+		RepoStateId: key.repoStateId,
+		Fingerprint: fingerprint,
+	}
+	if e.impactCache != nil && key.repoStateId != "unknown" {
+		e.impactCache.put(key, fingerprint, copyImpactProvenance(resp))
+	}
+}
+
+// copyImpactProvenance returns a shallow copy of resp with its own
+// provenance, so callers that adjust provenance (durations, warnings)
+// don't reach into a stored result.
+func copyImpactProvenance(resp *AnalyzeImpactResponse) *AnalyzeImpactResponse {
+	c := *resp
+	if resp.Provenance != nil {
+		prov := *resp.Provenance
+		prov.Warnings = append([]output.Warning(nil), resp.Provenance.Warnings...)
+		c.Provenance = &prov
+	}
+	return &c
+}
@@ -0,0 +1,172 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"ckb/internal/impact"
+)
+
+func TestImpactResultCache(t *testing.T) {
+	c := newImpactResultCache(2)
+	opts := AnalyzeImpactOptions{Depth: 2}
+	keyA := newImpactCacheKey("a", "state1", opts)
+	keyB := newImpactCacheKey("b", "state1", opts)
+	keyC := newImpactCacheKey("c", "state1", opts)
+
+	c.put(keyA, "fpA", &AnalyzeImpactResponse{})
+	c.put(keyB, "fpB", &AnalyzeImpactResponse{})
+	if c.get(keyA, "fpA") == nil {
+		t.Fatal("expected hit for a")
+	}
+	if c.get(keyA, "other") != nil {
+		t.Error("a changed fingerprint must miss")
+	}
+
+	// a was used last, so adding c evicts b
+	c.put(keyC, "fpC", &AnalyzeImpactResponse{})
+	if c.get(keyB, "fpB") != nil {
+		t.Error("b should have been evicted")
+	}
+	if c.get(keyA, "fpA") == nil || c.get(keyC, "fpC") == nil {
+		t.Error("a and c should remain cached")
+	}
+
+	// Options and repo state are part of the key; the symbol ID and digest are not
+	if c.get(newImpactCacheKey("a", "state2", opts), "fpA") != nil {
+		t.Error("a different repo state must miss")
+	}
+	if c.get(newImpactCacheKey("a", "state1", AnalyzeImpactOptions{Depth: 3}), "fpA") != nil {
+		t.Error("different options must miss")
+	}
+	withPrev := AnalyzeImpactOptions{SymbolId: "alias", Depth: 2, Previous: &ImpactDigest{}}
+	if c.get(newImpactCacheKey("a", "state1", withPrev), "fpA") == nil {
+		t.Error("the requested ID and digest should not affect the key")
+	}
+
+	c.clear()
+	if c.get(keyA, "fpA") != nil || c.get(keyC, "fpC") != nil {
+		t.Error("clear should drop every result")
+	}
+}
+
+func TestClearAllCacheDropsImpactResults(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	key := newImpactCacheKey("a", "state1", AnalyzeImpactOptions{Depth: 2})
+	engine.impactCache.put(key, "fpA", &AnalyzeImpactResponse{})
+	if err := engine.ClearAllCache(); err != nil {
+		t.Fatalf("ClearAllCache: %v", err)
+	}
+	if engine.impactCache.get(key, "fpA") != nil {
+		t.Error("impact results should not survive ClearAllCache, which runs on index reload")
+	}
+}
+
+func TestCachedImpact(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	key := newImpactCacheKey("sym", "state1", AnalyzeImpactOptions{Depth: 2})
+	stored := &AnalyzeImpactResponse{Provenance: &Provenance{RepoStateId: "state1", QueryDurationMs: 500}}
+	engine.storeImpact(stored, key, "fp")
+
+	digest := stored.Digest
+	if digest == nil || digest.SymbolId != "sym" || digest.RepoStateId != "state1" || digest.Fingerprint != "fp" {
+		t.Fatalf("unexpected digest %+v", digest)
+	}
+
+	got := engine.cachedImpact(digest, key, "fp", time.Now())
+	if got == nil {
+		t.Fatal("expected a cached result")
+	}
+	if !got.Provenance.FromCache || got.Provenance.QueryDurationMs >= 500 {
+		t.Errorf("cached provenance = %+v, want fromCache with a fresh duration", got.Provenance)
+	}
+	got.Provenance.QueryDurationMs = 999
+	if again := engine.cachedImpact(digest, key, "fp", time.Now()); again.Provenance.QueryDurationMs == 999 {
+		t.Error("changing a returned result's provenance leaked into the cache")
+	}
+	if stored.Provenance.FromCache {
+		t.Error("the fresh result must not be marked as cached")
+	}
+
+	tests := []struct {
+		name        string
+		prev        *ImpactDigest
+		key         impactCacheKey
+		fingerprint string
+	}{
+		{"no digest", nil, key, "fp"},
+		{"repo state changed", digest, newImpactCacheKey("sym", "state2", AnalyzeImpactOptions{Depth: 2}), "fp"},
+		{"symbol changed", digest, key, "fp2"},
+		{"other symbol", &ImpactDigest{SymbolId: "other", RepoStateId: "state1", Fingerprint: "fp"}, key, "fp"},
+	}
+	for _, tt := range tests {
+		if got := engine.cachedImpact(tt.prev, tt.key, tt.fingerprint, time.Now()); got != nil {
+			t.Errorf("%s: expected a full analysis, got a cached result", tt.name)
+		}
+	}
+
+	// Without git the repo state can't be tracked, so nothing is reused
+	unknownKey := newImpactCacheKey("sym", "unknown", AnalyzeImpactOptions{Depth: 2})
+	unknown := &AnalyzeImpactResponse{}
+	engine.storeImpact(unknown, unknownKey, "fp")
+	if got := engine.cachedImpact(unknown.Digest, unknownKey, "fp", time.Now()); got != nil {
+		t.Error("results for an unknown repo state must not be reused")
+	}
+}
+
+// BenchmarkAnalyzeImpactPrevious compares a full impact analysis with
+// reusing its result through the digest.
+func BenchmarkAnalyzeImpactPrevious(b *testing.B) {
+	engine, cleanup := testEngine(&testing.T{})
+	defer cleanup()
+	ctx := context.Background()
+
+	symbol := &SymbolInfo{
+		StableId:   "ckb:repo:sym:target",
+		Name:       "Target",
+		Kind:       "function",
+		ModuleId:   "internal/core",
+		Visibility: &VisibilityInfo{Visibility: "public", Confidence: 0.9, Source: "scip"},
+	}
+	refs := make([]impact.Reference, 0, 500)
+	for i := 0; i < 500; i++ {
+		refs = append(refs, impact.Reference{
+			Kind:       impact.RefCall,
+			Location:   &impact.Location{FileId: fmt.Sprintf("internal/mod%d/file%d.go", i%20, i), StartLine: i + 1},
+			FromSymbol: fmt.Sprintf("ckb:repo:sym:caller%d", i),
+			FromModule: fmt.Sprintf("internal/mod%d", i%20),
+		})
+	}
+	opts := AnalyzeImpactOptions{Depth: 2}
+
+	b.Run("full", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := engine.AnalyzeImpactFromRefs(ctx, symbol, refs, opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("previous", func(b *testing.B) {
+		resp, err := engine.AnalyzeImpactFromRefs(ctx, symbol, refs, opts)
+		if err != nil {
+			b.Fatal(err)
+		}
+		fingerprint := impactSymbolFingerprint(symbol)
+		key := newImpactCacheKey(symbol.StableId, "bench-state", opts)
+		engine.storeImpact(resp, key, fingerprint)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if engine.cachedImpact(resp.Digest, key, fingerprint, time.Now()) == nil {
+				b.Fatal("expected a cached result")
+			}
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"ckb/internal/telemetry"
+)
+
+// JustifyTelemetry is the runtime evidence justifySymbol weighed against
+// its static verdict.
+type JustifyTelemetry struct {
+	ObservedCalls   int64  `json:"observedCalls"`
+	ObservationDays int    `json:"observationDays"`
+	MatchQuality    string `json:"matchQuality,omitempty"` // Empty when the symbol was never observed
+	LastObserved    string `json:"lastObserved,omitempty"`
+}
+
+// justifyTelemetry returns the observed calls to a symbol over the whole
+// telemetry window, or nil when telemetry can't settle a dead-code question:
+// it's disabled, matches too few symbols to trust an absence of calls, the
+// window is shorter than telemetry.deadCode.minObservationDays, or the
+// symbol's own calls were only weakly matched.
+func (e *Engine) justifyTelemetry(symbolID string) *JustifyTelemetry {
+	if e.config == nil || !e.config.Telemetry.Enabled || e.db == nil || symbolID == "" {
+		return nil
+	}
+	storage := telemetry.NewStorage(e.db.Conn())
+
+	exact, strong, weak, unmatched, err := storage.GetMatchStats()
+	total := exact + strong + weak + unmatched
+	if err != nil || total == 0 {
+		return nil
+	}
+	coverage := telemetry.TelemetryCoverage{
+		Overall: telemetry.OverallCoverage{Level: coverageLevelFor(float64(exact+strong) / float64(total))},
+	}
+	if !coverage.CanUseDeadCode() {
+		return nil
+	}
+
+	days, err := storage.GetObservationWindowDays()
+	if err != nil || days < e.config.Telemetry.DeadCode.MinObservationDays {
+		return nil
+	}
+
+	rollup, err := storage.GetUsageRollup(symbolID, "")
+	if err != nil {
+		return nil
+	}
+	evidence := &JustifyTelemetry{ObservationDays: days}
+	if rollup != nil && len(rollup.Days) > 0 {
+		if rollup.MatchQuality == telemetry.MatchWeak || rollup.MatchQuality == telemetry.MatchUnmatched {
+			return nil
+		}
+		evidence.ObservedCalls = rollup.TotalCalls
+		evidence.MatchQuality = string(rollup.MatchQuality)
+		if !rollup.LastIngestedAt.IsZero() {
+			evidence.LastObserved = rollup.LastIngestedAt.Format(time.RFC3339)
+		}
+	}
+	return evidence
+}
+
+// coverageLevelFor maps the share of exactly or strongly matched telemetry
+// events to a coverage level.
+func coverageLevelFor(effectiveRate float64) telemetry.CoverageLevel {
+	switch {
+	case effectiveRate >= 0.8:
+		return telemetry.CoverageHigh
+	case effectiveRate >= 0.6:
+		return telemetry.CoverageMedium
+	case effectiveRate >= 0.4:
+		return telemetry.CoverageLow
+	default:
+		return telemetry.CoverageInsufficient
+	}
+}
+
+// blendJustifyTelemetry revisits a verdict for a symbol without static
+// callers in light of runtime calls. Observed calls mean something reaches
+// the symbol dynamically (reflection, registries, interface dispatch the
+// index missed), so a removal candidate becomes investigate. A full window
+// without calls corroborates the static finding and upgrades it to remove.
+// Other verdicts keep their outcome; a silent window is only noted.
+func blendJustifyTelemetry(facts ExplainSymbolFacts, verdict string, confidence float64, reasoning string, t *JustifyTelemetry) (string, float64, string) {
+	if t == nil || (facts.Usage != nil && facts.Usage.CallerCount > 0) {
+		return verdict, confidence, reasoning
+	}
+	if facts.Symbol != nil && facts.Symbol.TestOnlyExport {
+		return verdict, confidence, reasoning
+	}
+
+	if t.ObservedCalls > 0 {
+		return "investigate", 0.8, fmt.Sprintf("No static callers, but %d runtime calls observed in %d days; likely reached dynamically", t.ObservedCalls, t.ObservationDays)
+	}
+	if verdict == "remove-candidate" {
+		return "remove", 0.95, fmt.Sprintf("No static callers and no runtime calls observed in %d days", t.ObservationDays)
+	}
+	return verdict, confidence, fmt.Sprintf("%s; no runtime calls observed in %d days", reasoning, t.ObservationDays)
+}
@@ -0,0 +1,97 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlendJustifyTelemetry(t *testing.T) {
+	noCallers := ExplainSymbolFacts{Usage: &ExplainUsage{CallerCount: 0}}
+	silent := &JustifyTelemetry{ObservedCalls: 0, ObservationDays: 120}
+	called := &JustifyTelemetry{ObservedCalls: 42, ObservationDays: 120, MatchQuality: "exact"}
+
+	tests := []struct {
+		name           string
+		facts          ExplainSymbolFacts
+		verdict        string
+		telemetry      *JustifyTelemetry
+		wantVerdict    string
+		wantConfidence float64
+		wantReasoning  string
+	}{
+		{
+			name:           "no telemetry leaves verdict alone",
+			facts:          noCallers,
+			verdict:        "remove-candidate",
+			wantVerdict:    "remove-candidate",
+			wantConfidence: 0.7,
+		},
+		{
+			name:           "silent window upgrades removal candidate",
+			facts:          noCallers,
+			verdict:        "remove-candidate",
+			telemetry:      silent,
+			wantVerdict:    "remove",
+			wantConfidence: 0.95,
+			wantReasoning:  "no runtime calls observed in 120 days",
+		},
+		{
+			name:           "runtime calls downgrade removal candidate",
+			facts:          noCallers,
+			verdict:        "remove-candidate",
+			telemetry:      called,
+			wantVerdict:    "investigate",
+			wantConfidence: 0.8,
+			wantReasoning:  "42 runtime calls",
+		},
+		{
+			name:           "public API stays investigate when silent",
+			facts:          ExplainSymbolFacts{Usage: &ExplainUsage{}, Flags: &ExplainSymbolFlags{IsPublicApi: true}},
+			verdict:        "investigate",
+			telemetry:      silent,
+			wantVerdict:    "investigate",
+			wantConfidence: 0.7,
+			wantReasoning:  "no runtime calls observed",
+		},
+		{
+			name:           "static callers win",
+			facts:          ExplainSymbolFacts{Usage: &ExplainUsage{CallerCount: 3}},
+			verdict:        "keep",
+			telemetry:      silent,
+			wantVerdict:    "keep",
+			wantConfidence: 0.7,
+		},
+		{
+			name:           "test-only export is unaffected",
+			facts:          ExplainSymbolFacts{Symbol: &SymbolInfo{TestOnlyExport: true}},
+			verdict:        "investigate",
+			telemetry:      called,
+			wantVerdict:    "investigate",
+			wantConfidence: 0.7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, confidence, reasoning := blendJustifyTelemetry(tt.facts, tt.verdict, 0.7, "static", tt.telemetry)
+			if verdict != tt.wantVerdict {
+				t.Errorf("verdict = %q, want %q", verdict, tt.wantVerdict)
+			}
+			if !floatEqual(confidence, tt.wantConfidence) {
+				t.Errorf("confidence = %v, want %v", confidence, tt.wantConfidence)
+			}
+			if tt.wantReasoning != "" && !strings.Contains(reasoning, tt.wantReasoning) {
+				t.Errorf("reasoning = %q, want it to mention %q", reasoning, tt.wantReasoning)
+			}
+		})
+	}
+}
+
+func TestJustifyTelemetryDisabled(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if got := engine.justifyTelemetry("some-symbol"); got != nil {
+		t.Errorf("expected no telemetry evidence with telemetry disabled, got %+v", got)
+	}
+}
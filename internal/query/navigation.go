@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -15,6 +16,7 @@ import (
 	"ckb/internal/backends/git"
 	"ckb/internal/backends/scip"
 	"ckb/internal/output"
+	"ckb/internal/paths"
 	"ckb/internal/version"
 )
 
@@ -103,11 +105,24 @@ type ExplainCaller struct {
 	Context string `json:"context,omitempty"`
 }
 
-// ExplainCallee is a placeholder for future expansion.
+// ExplainCallee is a symbol the explained symbol calls.
 type ExplainCallee struct {
 	SymbolId string `json:"symbolId"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind,omitempty"`
+
+	// Resolved is false when the callee wasn't looked up, or the lookup
+	// failed, and Name was derived from the ID
+	Resolved bool `json:"resolved"`
 }
 
+// maxExplainCalleeLookups caps the symbol lookups explainSymbol makes to
+// name callees; the rest are named from their IDs.
+const maxExplainCalleeLookups = 10
+
+// maxSummaryCallees is how many callee names the explainSymbol summary lists.
+const maxSummaryCallees = 3
+
 // ExplainSymbolSummary provides condensed text.
 type ExplainSymbolSummary struct {
 	Tldr     string `json:"tldr"`
@@ -129,13 +144,16 @@ type JustifySymbolResponse struct {
 	Confidence       float64             `json:"confidence"`
 	Reasoning        string              `json:"reasoning"`
 	RelatedDecisions []RelatedDecision   `json:"relatedDecisions,omitempty"` // v6.5: ADRs that may justify this symbol
+	Telemetry        *JustifyTelemetry   `json:"telemetry,omitempty"`        // Runtime evidence, when telemetry can support a verdict
 }
 
 // CallGraphOptions configures call graph retrieval.
 type CallGraphOptions struct {
-	SymbolId  string
-	Direction string // "callers", "callees", or "both"
-	Depth     int
+	SymbolId       string
+	Direction      string // "callers", "callees", or "both"
+	Depth          int
+	IncludeContext bool   // Attach call-site source to edges and definition lines to callees
+	OutputFormat   string // "json" (default) or "dot" to also render Graphviz
 }
 
 // CallGraphResponse contains a lightweight call graph.
@@ -144,29 +162,40 @@ type CallGraphResponse struct {
 	Root  string          `json:"root"`
 	Nodes []CallGraphNode `json:"nodes"`
 	Edges []CallGraphEdge `json:"edges"`
+	Dot   string          `json:"dot,omitempty"` // Graphviz digraph, with OutputFormat "dot"
 }
 
 // CallGraphNode captures a node in the call graph.
 type CallGraphNode struct {
-	ID       string        `json:"id"`
-	SymbolId string        `json:"symbolId,omitempty"`
-	Name     string        `json:"name"`
-	Location *LocationInfo `json:"location,omitempty"`
-	Depth    int           `json:"depth"`
-	Role     string        `json:"role"` // "root", "caller", "callee"
-	Score    float64       `json:"score"`
+	ID         string         `json:"id"`
+	SymbolId   string         `json:"symbolId,omitempty"`
+	Name       string         `json:"name"`
+	Location   *LocationInfo  `json:"location,omitempty"`
+	Depth      int            `json:"depth"`
+	Role       string         `json:"role"` // "root", "caller", "callee"
+	Score      float64        `json:"score"`
+	Definition *SourceContext `json:"definition,omitempty"` // Callees only, with includeContext
 }
 
 // CallGraphEdge encodes a caller->callee relationship.
 type CallGraphEdge struct {
-	From string `json:"from"`
-	To   string `json:"to"`
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	CallSite *SourceContext `json:"callSite,omitempty"` // With includeContext, when the call site is known
 }
 
 // ModuleOverviewOptions controls module overview behavior.
 type ModuleOverviewOptions struct {
 	Path string
 	Name string
+
+	// ExcludeGenerated leaves generated files out of the file count
+	// (default: true)
+	ExcludeGenerated bool
+
+	// RespectGitignore leaves paths matched by the repo's .gitignore files
+	// out of the file count (default: true)
+	RespectGitignore bool
 }
 
 // ModuleOverviewResponse returns coarse module facts.
@@ -222,17 +251,13 @@ func (e *Engine) ExplainSymbol(ctx context.Context, opts ExplainSymbolOptions) (
 		calleeCount = e.scipAdapter.GetCalleeCount(symbolId)
 		if calleeCount > 0 {
 			// Populate callees list with symbol IDs
-			graph, graphErr := e.scipAdapter.BuildCallGraph(symbolId, scip.CallGraphOptions{
+			graph, graphErr := e.buildCallGraph(ctx, symbolId, scip.CallGraphOptions{
 				Direction: scip.DirectionCallees,
 				MaxDepth:  1,
 				MaxNodes:  20,
 			})
 			if graphErr == nil && graph != nil {
-				for _, callee := range graph.Callees {
-					facts.Callees = append(facts.Callees, ExplainCallee{
-						SymbolId: callee.SymbolID,
-					})
-				}
+				facts.Callees = e.explainCallees(ctx, graph.Callees)
 			}
 		}
 	}
@@ -242,9 +267,11 @@ func (e *Engine) ExplainSymbol(ctx context.Context, opts ExplainSymbolOptions) (
 	if err == nil && refResp != nil {
 		callers := make([]ExplainCaller, 0, len(refResp.References))
 		moduleSet := map[string]struct{}{}
+		refPaths := make([]string, 0, len(refResp.References))
 		hasTests := false
 
 		for _, ref := range refResp.References {
+			refPaths = append(refPaths, ref.Location.FileId)
 			moduleName := topLevelModule(ref.Location.FileId)
 			moduleSet[moduleName] = struct{}{}
 			isCall := strings.Contains(strings.ToLower(ref.Kind), "call")
@@ -273,19 +300,29 @@ func (e *Engine) ExplainSymbol(ctx context.Context, opts ExplainSymbolOptions) (
 		if facts.Flags != nil {
 			facts.Flags.HasTests = hasTests
 		}
+
+		// Exported only so tests can reach it: not part of the public API
+		if facts.Symbol != nil && isTestOnlyExport(facts.Symbol.Visibility, refPaths) {
+			facts.Symbol.TestOnlyExport = true
+			if facts.Flags != nil {
+				facts.Flags.IsPublicApi = false
+			}
+		}
 	}
 
-	// Compute history from git using definition path when available
+	// Compute history from git using every definition path when available
+	// (partial classes and split types have more than one)
 	if facts.Symbol != nil && facts.Symbol.Location != nil && e.gitAdapter != nil && e.gitAdapter.IsAvailable() {
-		history, err := e.gitAdapter.GetFileHistory(facts.Symbol.Location.FileId, 20)
-		if err == nil {
-			facts.History = &ExplainHistory{
-				CreatedAt:       tailTimestamp(history.Commits),
-				LastModifiedAt:  history.LastModified,
-				CommitCount:     history.CommitCount,
-				CommitFrequency: classifyCommitFrequency(history.CommitCount),
+		files := facts.Symbol.definitionFiles()
+		var histories []*git.FileHistory
+		for _, file := range files {
+			history, err := e.gitAdapter.GetFileHistory(file, 20)
+			if err == nil {
+				histories = append(histories, history)
 			}
 		}
+		commitCount, _ := e.gitAdapter.GetFilesCommitCount(files) // 0 on error
+		facts.History = mergeFileHistories(histories, commitCount)
 	}
 
 	// v6.5: Add annotation context (related ADRs and module metadata)
@@ -313,6 +350,10 @@ func (e *Engine) ExplainSymbol(ctx context.Context, opts ExplainSymbolOptions) (
 		resolved.SymbolId = facts.Symbol.StableId
 		resolved.ResolvedFrom = "id"
 		resolved.Confidence = 1.0
+		if symbolResp.ResolvedFrom != "" {
+			resolved.ResolvedFrom = symbolResp.ResolvedFrom
+			resolved.Confidence = symbolResp.Confidence
+		}
 	}
 
 	var truncation *TruncationInfo
@@ -342,6 +383,63 @@ func (e *Engine) ExplainSymbol(ctx context.Context, opts ExplainSymbolOptions) (
 	}, nil
 }
 
+// explainCallees names call graph callees, looking up at most
+// maxExplainCalleeLookups of them; like traceUsage, lookups are cached per
+// request so a callee reached twice costs one. A callee that isn't looked
+// up, or whose lookup fails, keeps the call graph's name, or one derived
+// from its ID.
+func (e *Engine) explainCallees(ctx context.Context, nodes []*scip.CallGraphNode) []ExplainCallee {
+	cache := make(map[string]*symbolCacheEntry)
+	lookups := 0
+	callees := make([]ExplainCallee, 0, len(nodes))
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		cached, ok := cache[node.SymbolID]
+		if !ok {
+			name := node.Name
+			if name == "" || name == node.SymbolID {
+				name = symbolDisplayName(node.SymbolID)
+			}
+			cached = &symbolCacheEntry{name: name, kind: string(node.Kind)}
+			if lookups < maxExplainCalleeLookups {
+				lookups++
+				if symResp, err := e.GetSymbol(ctx, GetSymbolOptions{SymbolId: node.SymbolID, RepoStateMode: "head"}); err == nil && symResp.Symbol != nil {
+					cached = &symbolCacheEntry{name: symResp.Symbol.Name, kind: symResp.Symbol.Kind, location: symResp.Symbol.Location, resolved: true}
+				}
+			}
+			cache[node.SymbolID] = cached
+		}
+		callees = append(callees, ExplainCallee{
+			SymbolId: node.SymbolID,
+			Name:     cached.name,
+			Kind:     cached.kind,
+			Resolved: cached.resolved,
+		})
+	}
+	return callees
+}
+
+// calleeSummary lists the first few callee names, e.g. "calls validateInput,
+// persist and notify" or "calls a, b, c and 4 more".
+func calleeSummary(callees []ExplainCallee) string {
+	if len(callees) == 0 {
+		return ""
+	}
+	names := make([]string, 0, maxSummaryCallees)
+	for i := 0; i < len(callees) && i < maxSummaryCallees; i++ {
+		names = append(names, callees[i].Name)
+	}
+	if rest := len(callees) - len(names); rest > 0 {
+		return fmt.Sprintf("calls %s and %d more", strings.Join(names, ", "), rest)
+	}
+	if len(names) == 1 {
+		return "calls " + names[0]
+	}
+	return fmt.Sprintf("calls %s and %s", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
 // buildExplainSummary constructs summary text from facts.
 func buildExplainSummary(facts ExplainSymbolFacts) ExplainSymbolSummary {
 	summary := ExplainSymbolSummary{}
@@ -352,6 +450,13 @@ func buildExplainSummary(facts ExplainSymbolFacts) ExplainSymbolSummary {
 	if facts.Usage != nil {
 		summary.Usage = fmt.Sprintf("%d callers, %d references across %d modules", facts.Usage.CallerCount, facts.Usage.ReferenceCount, facts.Usage.ModuleCount)
 	}
+	if calls := calleeSummary(facts.Callees); calls != "" {
+		if summary.Usage != "" {
+			summary.Usage += "; " + calls
+		} else {
+			summary.Usage = calls
+		}
+	}
 	if facts.History != nil {
 		summary.History = fmt.Sprintf("%d commits, last modified %s", facts.History.CommitCount, facts.History.LastModifiedAt)
 	}
@@ -377,6 +482,13 @@ func (e *Engine) JustifySymbol(ctx context.Context, opts JustifySymbolOptions) (
 
 	verdict, confidence, reasoning := computeJustifyVerdict(explain.Facts)
 
+	// Corroborate or overturn a no-callers verdict with runtime calls
+	var observed *JustifyTelemetry
+	if explain.Facts.Symbol != nil {
+		observed = e.justifyTelemetry(explain.Facts.Symbol.StableId)
+		verdict, confidence, reasoning = blendJustifyTelemetry(explain.Facts, verdict, confidence, reasoning, observed)
+	}
+
 	// v6.5: Extract related decisions from annotations for response
 	var relatedDecisions []RelatedDecision
 	if explain.Facts.Annotations != nil && len(explain.Facts.Annotations.RelatedDecisions) > 0 {
@@ -398,12 +510,22 @@ func (e *Engine) JustifySymbol(ctx context.Context, opts JustifySymbolOptions) (
 		Confidence:       confidence,
 		Reasoning:        reasoning,
 		RelatedDecisions: relatedDecisions,
+		Telemetry:        observed,
 	}, nil
 }
 
 // computeJustifyVerdict encapsulates verdict selection logic for unit testing.
 // v6.5: Now considers ADRs - if a module has an accepted ADR, symbols are less likely to be removal candidates.
 func computeJustifyVerdict(facts ExplainSymbolFacts) (verdict string, confidence float64, reasoning string) {
+	// Exported but only used by tests -> investigate (candidate for unexporting)
+	if facts.Symbol != nil && facts.Symbol.TestOnlyExport {
+		callerCount := 0
+		if facts.Usage != nil {
+			callerCount = facts.Usage.CallerCount
+		}
+		return "investigate", 0.7, fmt.Sprintf("Exported but only referenced from tests (%d test callers)", callerCount)
+	}
+
 	// Has active callers -> keep
 	if facts.Usage != nil && facts.Usage.CallerCount > 0 {
 		return "keep", 0.9, fmt.Sprintf("Active callers detected (%d)", facts.Usage.CallerCount)
@@ -432,15 +554,18 @@ func computeJustifyVerdict(facts ExplainSymbolFacts) (verdict string, confidence
 func (e *Engine) GetCallGraph(ctx context.Context, opts CallGraphOptions) (*CallGraphResponse, error) {
 	startTime := time.Now()
 
-	if opts.Depth == 0 {
-		opts.Depth = 1
-	}
-	if opts.Depth > 4 {
-		opts.Depth = 4 // Hard limit
-	}
+	depthLimits := e.traversalConfig().CallGraphLimits()
+	requestedDepth := opts.Depth
+	var depthClamped bool
+	opts.Depth, depthClamped = depthLimits.Resolve(opts.Depth)
 	if opts.Direction == "" {
 		opts.Direction = "both"
 	}
+	switch opts.OutputFormat {
+	case "", CallGraphFormatJSON, CallGraphFormatDOT:
+	default:
+		return nil, fmt.Errorf("invalid output format %q: must be %q or %q", opts.OutputFormat, CallGraphFormatJSON, CallGraphFormatDOT)
+	}
 
 	symbolResp, err := e.GetSymbol(ctx, GetSymbolOptions{SymbolId: opts.SymbolId, RepoStateMode: "full"})
 	if err != nil {
@@ -449,7 +574,8 @@ func (e *Engine) GetCallGraph(ctx context.Context, opts CallGraphOptions) (*Call
 
 	nodes := []CallGraphNode{}
 	edges := []CallGraphEdge{}
-	var warnings []string
+	callSites := make(map[string]*LocationInfo) // "from->to" -> call location
+	var warnings []output.Warning
 
 	// Add root node
 	rootId := opts.SymbolId
@@ -483,7 +609,7 @@ func (e *Engine) GetCallGraph(ctx context.Context, opts CallGraphOptions) (*Call
 			}
 
 			// Build call graph from SCIP using resolved symbol ID
-			graph, err := e.scipAdapter.BuildCallGraph(rootId, scip.CallGraphOptions{
+			graph, err := e.buildCallGraph(ctx, rootId, scip.CallGraphOptions{
 				Direction: scipDirection,
 				MaxDepth:  opts.Depth,
 				MaxNodes:  100,
@@ -536,6 +662,13 @@ func (e *Engine) GetCallGraph(ctx context.Context, opts CallGraphOptions) (*Call
 
 				// Add edges from BFS traversal (for deeper levels)
 				for _, edge := range graph.Edges {
+					if edge.CallSite != nil {
+						callSites[edge.From+"->"+edge.To] = &LocationInfo{
+							FileId:      edge.CallSite.FileId,
+							StartLine:   edge.CallSite.StartLine + 1,
+							StartColumn: edge.CallSite.StartColumn + 1,
+						}
+					}
 					// Skip edges already added (depth 1)
 					alreadyAdded := false
 					for _, e := range edges {
@@ -585,7 +718,11 @@ func (e *Engine) GetCallGraph(ctx context.Context, opts CallGraphOptions) (*Call
 			}
 		}
 	} else {
-		warnings = append(warnings, "SCIP backend not available; call graph may be incomplete")
+		warnings = append(warnings, output.Warning{
+			Severity: output.SeverityWarning,
+			Code:     WarnScipUnavailable,
+			Text:     "SCIP backend not available; call graph may be incomplete",
+		})
 
 		// Fallback: use reference-based approach for callers only
 		if opts.Direction == "both" || opts.Direction == "callers" {
@@ -605,22 +742,33 @@ func (e *Engine) GetCallGraph(ctx context.Context, opts CallGraphOptions) (*Call
 						Score:    1.0,
 					})
 					edges = append(edges, CallGraphEdge{From: key, To: rootId})
+					callSites[key+"->"+rootId] = ref.Location
 				}
 			}
 		}
 
 		if opts.Direction == "both" || opts.Direction == "callees" {
-			warnings = append(warnings, "Callee analysis requires SCIP index")
+			warnings = append(warnings, output.Warning{
+				Severity: output.SeverityInfo,
+				Code:     WarnScipUnavailable,
+				Text:     "Callee analysis requires SCIP index",
+			})
 		}
 	}
 
 	// Sort nodes by score for deterministic output
 	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Score > nodes[j].Score })
 
+	if opts.IncludeContext {
+		e.attachCallContext(nodes, edges, callSites)
+	}
+
 	prov := symbolResp.Provenance
 	if prov != nil {
 		prov.QueryDurationMs = time.Since(startTime).Milliseconds()
-		prov.Warnings = append(prov.Warnings, warnings...)
+		for _, w := range warnings {
+			e.addWarning(prov, w.Severity, w.Code, w.Text)
+		}
 	}
 
 	var truncation *TruncationInfo
@@ -631,6 +779,14 @@ func (e *Engine) GetCallGraph(ctx context.Context, opts CallGraphOptions) (*Call
 			ReturnedCount: len(nodes),
 		}
 	}
+	if depthClamped {
+		truncation = withDepthClamp(truncation, requestedDepth, depthLimits.Max)
+	}
+
+	var dot string
+	if opts.OutputFormat == CallGraphFormatDOT {
+		dot = callGraphDOT(rootId, nodes, edges)
+	}
 
 	return &CallGraphResponse{
 		AINavigationMeta: AINavigationMeta{
@@ -644,6 +800,7 @@ func (e *Engine) GetCallGraph(ctx context.Context, opts CallGraphOptions) (*Call
 		Root:  rootId,
 		Nodes: nodes,
 		Edges: edges,
+		Dot:   dot,
 	}, nil
 }
 
@@ -657,21 +814,30 @@ func (e *Engine) GetModuleOverview(ctx context.Context, opts ModuleOverviewOptio
 	}
 
 	fileCount := 0
+	generated := newGeneratedFileFilter(e.repoRoot)
+	walkFilter := e.newTreeWalkFilter(opts.RespectGitignore)
 	_ = filepath.Walk(modulePath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil //nolint:nilerr // continue walking on individual file errors
 		}
 
 		if info.IsDir() {
-			base := filepath.Base(path)
-			// Skip hidden directories and common non-source directories
-			if strings.HasPrefix(base, ".") || base == "node_modules" || base == "vendor" {
+			if walkFilter.skipDir(path, path == modulePath) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
 		if info.Mode().IsRegular() {
+			if walkFilter.skipFile(path) {
+				return nil
+			}
+			if opts.ExcludeGenerated {
+				relPath, relErr := filepath.Rel(modulePath, path)
+				if relErr == nil && generated.isGeneratedAt(filepath.ToSlash(relPath), path) {
+					return nil
+				}
+			}
 			fileCount++
 		}
 		return nil
@@ -687,7 +853,7 @@ func (e *Engine) GetModuleOverview(ctx context.Context, opts ModuleOverviewOptio
 		}
 	}
 
-	prov := &Provenance{QueryDurationMs: time.Since(startTime).Milliseconds()}
+	prov := &Provenance{QueryDurationMs: time.Since(startTime).Milliseconds(), Extra: e.provenanceExtra(ctx)}
 
 	moduleName := opts.Name
 	if moduleName == "" {
@@ -749,6 +915,86 @@ func tailTimestamp(commits []git.CommitInfo) string {
 	return commits[len(commits)-1].Timestamp
 }
 
+// definitionLineInFile returns the line where a symbol is defined in the given
+// file, checking secondary definition sites when the primary one is elsewhere.
+func definitionLineInFile(sym backends.SymbolResult, relPath string) (int, bool) {
+	if sym.Location.Path == relPath {
+		return sym.Location.Line, true
+	}
+	for _, loc := range sym.AdditionalLocations {
+		if loc.Path == relPath {
+			return loc.Line, true
+		}
+	}
+	return 0, false
+}
+
+// definitionLineInFiles is definitionLineInFile for any of several paths
+// naming the same file.
+func definitionLineInFiles(sym backends.SymbolResult, relPaths []string) (int, bool) {
+	for _, p := range relPaths {
+		if line, ok := definitionLineInFile(sym, p); ok {
+			return line, true
+		}
+	}
+	return 0, false
+}
+
+// mergeFileHistories combines the histories of all files defining a symbol.
+// commitCount is the number of commits touching any of the files, counted
+// once each; the histories themselves are capped, so when it is unknown (0)
+// the distinct commits they list are counted instead.
+func mergeFileHistories(histories []*git.FileHistory, commitCount int) *ExplainHistory {
+	if len(histories) == 0 {
+		return nil
+	}
+	if len(histories) == 1 {
+		h := histories[0]
+		if commitCount <= 0 {
+			commitCount = h.CommitCount
+		}
+		return &ExplainHistory{
+			CreatedAt:       tailTimestamp(h.Commits),
+			LastModifiedAt:  h.LastModified,
+			CommitCount:     commitCount,
+			CommitFrequency: classifyCommitFrequency(commitCount),
+		}
+	}
+
+	seen := make(map[string]bool)
+	var createdAt, lastModified string
+	var createdTime, lastTime time.Time
+	for _, h := range histories {
+		for _, c := range h.Commits {
+			if seen[c.Hash] {
+				continue
+			}
+			seen[c.Hash] = true
+
+			ts, err := time.Parse(time.RFC3339, c.Timestamp)
+			if err != nil {
+				continue
+			}
+			if createdAt == "" || ts.Before(createdTime) {
+				createdAt, createdTime = c.Timestamp, ts
+			}
+			if lastModified == "" || ts.After(lastTime) {
+				lastModified, lastTime = c.Timestamp, ts
+			}
+		}
+	}
+
+	if commitCount <= 0 {
+		commitCount = len(seen)
+	}
+	return &ExplainHistory{
+		CreatedAt:       createdAt,
+		LastModifiedAt:  lastModified,
+		CommitCount:     commitCount,
+		CommitFrequency: classifyCommitFrequency(commitCount),
+	}
+}
+
 // classifyCommitFrequency categorizes commit frequency.
 func classifyCommitFrequency(count int) string {
 	switch {
@@ -765,7 +1011,9 @@ func classifyCommitFrequency(count int) string {
 
 // ExplainFileOptions controls explainFile behavior.
 type ExplainFileOptions struct {
-	FilePath string
+	FilePath           string
+	CheckUnusedImports bool   // Report imports nothing in the file references; needs SCIP
+	Base               string // Mark symbols changed since this ref; needs git
 }
 
 // ExplainFileResponse provides lightweight file-level orientation.
@@ -778,16 +1026,19 @@ type ExplainFileResponse struct {
 
 // ExplainFileFacts contains the factual information about a file.
 type ExplainFileFacts struct {
-	Path       string                `json:"path"`
-	Role       string                `json:"role"` // core, glue, test, config, unknown
-	Language   string                `json:"language,omitempty"`
-	LineCount  int                   `json:"lineCount"`
-	Symbols    []ExplainFileSymbol   `json:"symbols"`  // Top defined symbols (max 15)
-	Imports    []string              `json:"imports"`  // Key imports
-	Exports    []string              `json:"exports"`  // Key exports/public symbols
-	Hotspots   []ExplainFileHotspot  `json:"hotspots"` // Local hotspots
-	Confidence float64               `json:"confidence"`
-	Basis      []ConfidenceBasisItem `json:"confidenceBasis"`
+	Path          string                `json:"path"`
+	Base          string                `json:"base,omitempty"`          // Ref symbol changes are relative to
+	CanonicalPath string                `json:"canonicalPath,omitempty"` // Symlink target, when path is a symlink
+	Role          string                `json:"role"`                    // core, glue, test, config, unknown
+	Language      string                `json:"language,omitempty"`
+	LineCount     int                   `json:"lineCount"`
+	Symbols       []ExplainFileSymbol   `json:"symbols"`                 // Top defined symbols (max 15)
+	Imports       []string              `json:"imports"`                 // Key imports
+	UnusedImports []string              `json:"unusedImports,omitempty"` // Only when requested
+	Exports       []string              `json:"exports"`                 // Key exports/public symbols
+	Hotspots      []ExplainFileHotspot  `json:"hotspots"`                // Local hotspots
+	Confidence    float64               `json:"confidence"`
+	Basis         []ConfidenceBasisItem `json:"confidenceBasis"`
 }
 
 // ExplainFileSymbol represents a symbol defined in the file.
@@ -797,6 +1048,7 @@ type ExplainFileSymbol struct {
 	Kind       string `json:"kind"`
 	Line       int    `json:"line"`
 	Visibility string `json:"visibility,omitempty"`
+	Change     string `json:"change,omitempty"` // added, modified, unchanged; only with a base ref
 }
 
 // ExplainFileHotspot represents a hotspot in the file.
@@ -809,9 +1061,10 @@ type ExplainFileHotspot struct {
 
 // ExplainFileSummary provides a natural language summary.
 type ExplainFileSummary struct {
-	OneLiner    string   `json:"oneLiner"`
-	KeySymbols  []string `json:"keySymbols"`
-	Suggestions []string `json:"suggestions,omitempty"`
+	OneLiner       string   `json:"oneLiner"`
+	KeySymbols     []string `json:"keySymbols"`
+	ChangedSymbols []string `json:"changedSymbols,omitempty"` // Added or modified since the base ref
+	Suggestions    []string `json:"suggestions,omitempty"`
 }
 
 // ConfidenceBasisItem describes a component of confidence.
@@ -825,40 +1078,17 @@ type ConfidenceBasisItem struct {
 func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*ExplainFileResponse, error) {
 	startTime := time.Now()
 
-	// Normalize file path
-	filePath := opts.FilePath
-	if !filepath.IsAbs(filePath) {
-		filePath = filepath.Join(e.repoRoot, filePath)
-	}
-
-	// Clean the path to resolve .. and other traversals
-	filePath = filepath.Clean(filePath)
-
-	// Security: verify path is within repo root
-	repoRootClean := filepath.Clean(e.repoRoot)
-	if !strings.HasPrefix(filePath, repoRootClean+string(filepath.Separator)) && filePath != repoRootClean {
-		return nil, fmt.Errorf("path outside repository: %s", opts.FilePath)
-	}
-
-	// Check if file exists
-	fileInfo, err := os.Stat(filePath)
-	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("file not found: %s", opts.FilePath)
-	}
-	if fileInfo.IsDir() {
-		return nil, fmt.Errorf("path is a directory, not a file: %s", opts.FilePath)
+	filePath, relPath, filePaths, err := e.resolveRepoFile(opts.FilePath)
+	if err != nil {
+		return nil, err
 	}
-
-	// Get relative path for display
-	relPath := opts.FilePath
-	if filepath.IsAbs(opts.FilePath) {
-		if rel, err := filepath.Rel(e.repoRoot, opts.FilePath); err == nil {
-			relPath = rel
-		}
+	canonicalPath := ""
+	if len(filePaths) > 1 {
+		canonicalPath = filePaths[1]
 	}
 
 	// Determine file role
-	role := classifyFileRole(relPath)
+	role := e.newFileRoleClassifier().classify(relPath)
 
 	// Detect language from extension
 	language := detectLanguage(relPath)
@@ -881,17 +1111,22 @@ func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*Exp
 		// Search for symbols in this file
 		searchResult, err := e.scipAdapter.SearchSymbols(ctx, "", backends.SearchOptions{
 			MaxResults: 50,
-			Scope:      []string{relPath},
+			Scope:      filePaths,
 		})
 
 		if err == nil && searchResult != nil {
+			seen := make(map[string]bool)
 			for _, sym := range searchResult.Symbols {
-				if sym.Location.Path == relPath {
+				if seen[sym.StableID] {
+					continue
+				}
+				if line, ok := definitionLineInFiles(sym, filePaths); ok {
+					seen[sym.StableID] = true
 					symbols = append(symbols, ExplainFileSymbol{
 						StableId:   sym.StableID,
 						Name:       sym.Name,
 						Kind:       sym.Kind,
-						Line:       sym.Location.Line,
+						Line:       line,
 						Visibility: sym.Visibility,
 					})
 
@@ -907,12 +1142,52 @@ func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*Exp
 			Backend: "scip",
 			Status:  "missing",
 		})
+
+		// Without an index, parse just this file for its definitions
+		if e.treesitterAdapter != nil {
+			searchResult, err := e.treesitterAdapter.SearchSymbols(ctx, "", backends.SearchOptions{
+				IncludeTests: true,
+				Scope:        filePaths[len(filePaths)-1:],
+			})
+			if err == nil && searchResult != nil {
+				confidenceBasis = append(confidenceBasis, ConfidenceBasisItem{
+					Backend:   "treesitter",
+					Status:    "partial",
+					Heuristic: "definitions parsed on demand",
+				})
+				for _, sym := range searchResult.Symbols {
+					symbols = append(symbols, ExplainFileSymbol{
+						StableId: sym.StableID,
+						Name:     sym.Name,
+						Kind:     sym.Kind,
+						Line:     sym.Location.Line,
+					})
+					if isExportedSymbol(sym.Name, "", language) {
+						exports = append(exports, sym.Name)
+					}
+				}
+			}
+		}
 	}
 
 	// Sort symbols by line number and limit to 15
 	sort.Slice(symbols, func(i, j int) bool {
 		return symbols[i].Line < symbols[j].Line
 	})
+
+	var warnings []string
+	var changedSymbols []string
+	if opts.Base != "" {
+		if err := e.markSymbolChanges(symbols, relPath, opts.Base, lineCount); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Changes since %s not marked: %v", opts.Base, err))
+		}
+		symbols = keepChangedSymbols(symbols, 15)
+		for _, sym := range symbols {
+			if sym.Change == symbolAdded || sym.Change == symbolModified {
+				changedSymbols = append(changedSymbols, sym.Name)
+			}
+		}
+	}
 	if len(symbols) > 15 {
 		symbols = symbols[:15]
 	}
@@ -922,6 +1197,18 @@ func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*Exp
 		exports = exports[:10]
 	}
 
+	var unusedImports []string
+	if opts.CheckUnusedImports {
+		docPaths := filePaths
+		if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
+			docPaths = append([]string{e.scipPaths().scipPath(relPath)}, filePaths...)
+		}
+		unusedImports, err = e.findUnusedImports(filePath, docPaths, language)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Unused imports not checked: %v", err))
+		}
+	}
+
 	// Get git history for hotspots
 	hotspots := []ExplainFileHotspot{}
 	if e.gitAdapter != nil && e.gitAdapter.IsAvailable() {
@@ -962,21 +1249,26 @@ func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*Exp
 			Tool:          "explainFile",
 		},
 		Facts: ExplainFileFacts{
-			Path:       relPath,
-			Role:       role,
-			Language:   language,
-			LineCount:  lineCount,
-			Symbols:    symbols,
-			Imports:    imports,
-			Exports:    exports,
-			Hotspots:   hotspots,
-			Confidence: confidence,
-			Basis:      confidenceBasis,
+			Path:          relPath,
+			Base:          opts.Base,
+			CanonicalPath: canonicalPath,
+			Role:          role,
+			Language:      language,
+			LineCount:     lineCount,
+			Symbols:       symbols,
+			Imports:       imports,
+			UnusedImports: unusedImports,
+			Exports:       exports,
+			Hotspots:      hotspots,
+			Confidence:    confidence,
+			Basis:         confidenceBasis,
 		},
 		Summary: ExplainFileSummary{
-			OneLiner:   oneLiner,
-			KeySymbols: keySymbols,
+			OneLiner:       oneLiner,
+			KeySymbols:     keySymbols,
+			ChangedSymbols: changedSymbols,
 		},
+		Warnings: warnings,
 	}
 
 	// Add provenance
@@ -985,6 +1277,7 @@ func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*Exp
 		RepoStateId:     repoState.RepoStateId,
 		RepoStateDirty:  repoState.Dirty,
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
+		Extra:           e.provenanceExtra(ctx),
 	}
 
 	// Add drilldowns
@@ -992,6 +1285,8 @@ func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*Exp
 		{
 			Label:          "Get module overview",
 			Query:          fmt.Sprintf("getModuleOverview --path=%s", filepath.Dir(relPath)),
+			Tool:           "getModuleOverview",
+			Params:         map[string]interface{}{"path": filepath.Dir(relPath)},
 			RelevanceScore: 0.9,
 		},
 	}
@@ -999,6 +1294,8 @@ func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*Exp
 		response.Drilldowns = append(response.Drilldowns, output.Drilldown{
 			Label:          fmt.Sprintf("Explore %s", symbols[0].Name),
 			Query:          fmt.Sprintf("explainSymbol %s", symbols[0].StableId),
+			Tool:           "explainSymbol",
+			Params:         map[string]interface{}{"symbolId": symbols[0].StableId},
 			RelevanceScore: 0.85,
 		})
 	}
@@ -1006,6 +1303,56 @@ func (e *Engine) ExplainFile(ctx context.Context, opts ExplainFileOptions) (*Exp
 	return response, nil
 }
 
+// resolveRepoFile validates that path names a regular file inside the
+// repository. It returns the absolute path, the repo-relative path for
+// display, and the paths the file's symbols may be indexed under: the
+// relative path, followed by the symlink target when path is a symlink.
+func (e *Engine) resolveRepoFile(path string) (string, string, []string, error) {
+	// Normalize file path
+	filePath := path
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(e.repoRoot, filePath)
+	}
+
+	// Clean the path to resolve .. and other traversals
+	filePath = filepath.Clean(filePath)
+
+	// Security: verify path is within repo root
+	repoRootClean := filepath.Clean(e.repoRoot)
+	if !strings.HasPrefix(filePath, repoRootClean+string(filepath.Separator)) && filePath != repoRootClean {
+		return "", "", nil, fmt.Errorf("path outside repository: %s", path)
+	}
+
+	// Check if file exists
+	fileInfo, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return "", "", nil, fmt.Errorf("file not found: %s", path)
+	}
+	if fileInfo.IsDir() {
+		return "", "", nil, fmt.Errorf("path is a directory, not a file: %s", path)
+	}
+
+	// Get relative path for display
+	relPath := path
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(e.repoRoot, path); err == nil {
+			relPath = rel
+		}
+	}
+
+	// A symlink is analyzed as the file it points to, which must also be
+	// inside the repo
+	canonicalPath, inRepo := paths.ResolveRepoPath(relPath, e.repoRoot)
+	if !inRepo {
+		return "", "", nil, fmt.Errorf("path outside repository: %s", path)
+	}
+	filePaths := []string{relPath}
+	if canonicalPath != paths.NormalizePath(relPath) {
+		filePaths = append(filePaths, canonicalPath)
+	}
+	return filePath, relPath, filePaths, nil
+}
+
 // classifyFileRole determines the role of a file based on its path.
 func classifyFileRole(path string) string {
 	pathLower := strings.ToLower(path)
@@ -1082,6 +1429,12 @@ func detectLanguage(path string) string {
 		return "csharp"
 	case ".php":
 		return "php"
+	case ".scala", ".sc":
+		return "scala"
+	case ".ex", ".exs":
+		return "elixir"
+	case ".zig":
+		return "zig"
 	default:
 		return ""
 	}
@@ -1182,6 +1535,7 @@ func isExportedSymbol(name, visibility, language string) bool {
 type ListEntrypointsOptions struct {
 	ModuleFilter string // Optional filter to specific module
 	Limit        int    // Max results (default 30)
+	MaxFanOut    int    // Fan-out above which an entrypoint is flagged (default: entrypoints.maxFanOut config)
 }
 
 // ListEntrypointsResponse provides the list of system entrypoints.
@@ -1200,8 +1554,9 @@ type EntrypointV52 struct {
 	Name           string        `json:"name"`
 	Type           string        `json:"type"` // api, cli, job, event
 	Location       *LocationInfo `json:"location"`
-	DetectionBasis string        `json:"detectionBasis"` // naming, framework-config, static-call
-	FanOut         int           `json:"fanOut"`         // Number of functions called
+	DetectionBasis string        `json:"detectionBasis"`     // naming, framework-config, static-call; "+path-hint:<hint>" when a path hint matched
+	PathHint       string        `json:"pathHint,omitempty"` // Entrypoint directory hint the location matched
+	FanOut         int           `json:"fanOut"`             // Number of functions called
 	Ranking        *RankingV52   `json:"ranking"`
 }
 
@@ -1223,7 +1578,12 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 			Status:  "available",
 		})
 
-		// Search for main functions
+		cliHints := e.entrypointPathHints("cli")
+		apiHints := e.entrypointPathHints("api")
+		jobHints := e.entrypointPathHints("job")
+		eventHints := e.entrypointPathHints("event")
+
+		// Search for main functions; only those in a CLI directory count
 		mainResults, _ := e.scipAdapter.SearchSymbols(ctx, "main", backends.SearchOptions{
 			MaxResults: 50,
 			Kind:       []string{"function"},
@@ -1231,20 +1591,26 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 
 		if mainResults != nil {
 			for _, sym := range mainResults.Symbols {
-				if sym.Name == "main" && strings.Contains(sym.Location.Path, "/cmd/") {
-					fanOut := e.scipAdapter.GetCalleeCount(sym.StableID)
-					entrypoints = append(entrypoints, EntrypointV52{
-						SymbolId: sym.StableID,
-						Name:     sym.Name,
-						Type:     "cli",
-						Location: &LocationInfo{
-							FileId:    sym.Location.Path,
-							StartLine: sym.Location.Line,
-						},
-						DetectionBasis: "naming",
-						FanOut:         fanOut,
-					})
+				if sym.Name != "main" {
+					continue
 				}
+				hint, ok := matchEntrypointHint(sym.Location.Path, cliHints)
+				if !ok {
+					continue
+				}
+				fanOut := e.scipAdapter.GetCalleeCount(sym.StableID)
+				entrypoints = append(entrypoints, EntrypointV52{
+					SymbolId: sym.StableID,
+					Name:     sym.Name,
+					Type:     "cli",
+					Location: &LocationInfo{
+						FileId:    sym.Location.Path,
+						StartLine: sym.Location.Line,
+					},
+					DetectionBasis: withPathHint("naming", hint),
+					PathHint:       hint,
+					FanOut:         fanOut,
+				})
 			}
 		}
 
@@ -1265,16 +1631,29 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 					if strings.HasPrefix(sym.Name, "Handle") ||
 						strings.HasSuffix(sym.Name, "Handler") ||
 						strings.HasPrefix(sym.Name, "Serve") {
+						// Handlers in an event directory (e.g. serverless
+						// functions) are event entrypoints
+						epType, basis := "api", "naming"
+						hint, ok := matchEntrypointHint(sym.Location.Path, apiHints)
+						if !ok {
+							if hint, ok = matchEntrypointHint(sym.Location.Path, eventHints); ok {
+								epType = "event"
+							}
+						}
+						if ok {
+							basis = withPathHint(basis, hint)
+						}
 						fanOut := e.scipAdapter.GetCalleeCount(sym.StableID)
 						entrypoints = append(entrypoints, EntrypointV52{
 							SymbolId: sym.StableID,
 							Name:     sym.Name,
-							Type:     "api",
+							Type:     epType,
 							Location: &LocationInfo{
 								FileId:    sym.Location.Path,
 								StartLine: sym.Location.Line,
 							},
-							DetectionBasis: "naming",
+							DetectionBasis: basis,
+							PathHint:       hint,
 							FanOut:         fanOut,
 						})
 					}
@@ -1294,10 +1673,16 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 					if strings.Contains(sym.Location.Path, "_test.") {
 						continue
 					}
-					if strings.HasPrefix(sym.Name, "Run") ||
-						strings.HasPrefix(sym.Name, "Execute") ||
-						strings.Contains(sym.Location.Path, "worker") ||
-						strings.Contains(sym.Location.Path, "job") {
+					named := strings.HasPrefix(sym.Name, "Run") || strings.HasPrefix(sym.Name, "Execute")
+					hint, hinted := matchEntrypointHint(sym.Location.Path, jobHints)
+					if named || hinted {
+						basis := ""
+						if named {
+							basis = "naming"
+						}
+						if hinted {
+							basis = withPathHint(basis, hint)
+						}
 						fanOut := e.scipAdapter.GetCalleeCount(sym.StableID)
 						entrypoints = append(entrypoints, EntrypointV52{
 							SymbolId: sym.StableID,
@@ -1307,7 +1692,8 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 								FileId:    sym.Location.Path,
 								StartLine: sym.Location.Line,
 							},
-							DetectionBasis: "naming",
+							DetectionBasis: basis,
+							PathHint:       hint,
 							FanOut:         fanOut,
 						})
 					}
@@ -1345,6 +1731,7 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 	}
 
 	// Apply ranking signals
+	maxFanOut := e.entrypointFanOutLimit(opts.MaxFanOut)
 	for i := range entrypoints {
 		score := 0.0
 		ep := &entrypoints[i]
@@ -1361,29 +1748,34 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 			score += 40
 		}
 
-		// Score by fan-out (higher fan-out = more important)
+		// Declared entrypoint directories raise confidence
+		if ep.PathHint != "" {
+			score += entrypointHintBoost
+		}
+
+		// Score by fan-out (higher fan-out = more important). The cap keeps
+		// rankings stable; excessive fan-out is flagged separately below.
 		score += float64(ep.FanOut) * 2
 		if score > 200 {
 			score = 200 // Cap
 		}
 
-		ep.Ranking = NewRankingV52(score, map[string]interface{}{
+		signals := map[string]interface{}{
 			"type":           ep.Type,
 			"detectionBasis": ep.DetectionBasis,
 			"fanOut":         ep.FanOut,
-		})
+		}
+		if ep.PathHint != "" {
+			signals["pathHint"] = ep.PathHint
+		}
+		if ep.FanOut > maxFanOut {
+			signals["highFanOut"] = true
+		}
+		ep.Ranking = NewRankingV52(score, signals)
 	}
 
 	// Sort by ranking score with deterministic tie-breaker (name, then symbolId)
-	sort.Slice(entrypoints, func(i, j int) bool {
-		if entrypoints[i].Ranking.Score != entrypoints[j].Ranking.Score {
-			return entrypoints[i].Ranking.Score > entrypoints[j].Ranking.Score
-		}
-		if entrypoints[i].Name != entrypoints[j].Name {
-			return entrypoints[i].Name < entrypoints[j].Name
-		}
-		return entrypoints[i].SymbolId < entrypoints[j].SymbolId
-	})
+	sortEntrypoints(entrypoints)
 
 	// Track total count before limiting
 	totalFound := len(entrypoints)
@@ -1393,6 +1785,10 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 		entrypoints = entrypoints[:opts.Limit]
 	}
 
+	if w := highFanOutWarning(entrypoints, maxFanOut); w != "" {
+		warnings = append(warnings, w)
+	}
+
 	// Compute confidence
 	confidence := 0.79 // Heuristics-based
 	for _, b := range confidenceBasis {
@@ -1421,6 +1817,7 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 		RepoStateId:     repoState.RepoStateId,
 		RepoStateDirty:  repoState.Dirty,
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
+		Extra:           e.provenanceExtra(ctx),
 	}
 
 	// Add drilldowns
@@ -1429,11 +1826,15 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 			{
 				Label:          fmt.Sprintf("Explore %s", entrypoints[0].Name),
 				Query:          fmt.Sprintf("explainSymbol %s", entrypoints[0].SymbolId),
+				Tool:           "explainSymbol",
+				Params:         map[string]interface{}{"symbolId": entrypoints[0].SymbolId},
 				RelevanceScore: 0.9,
 			},
 			{
 				Label:          fmt.Sprintf("Call graph for %s", entrypoints[0].Name),
 				Query:          fmt.Sprintf("getCallGraph %s", entrypoints[0].SymbolId),
+				Tool:           "getCallGraph",
+				Params:         map[string]interface{}{"symbolId": entrypoints[0].SymbolId},
 				RelevanceScore: 0.85,
 			},
 		}
@@ -1444,9 +1845,17 @@ func (e *Engine) ListEntrypoints(ctx context.Context, opts ListEntrypointsOption
 
 // TraceUsageOptions controls traceUsage behavior.
 type TraceUsageOptions struct {
-	SymbolId string // Target symbol to trace to
-	MaxPaths int    // Maximum paths to return (default 10)
-	MaxDepth int    // Maximum path depth (default 5)
+	SymbolId string       // Target symbol to trace to
+	MaxPaths int          // Maximum paths to return (default 10)
+	MaxDepth int          // Maximum path depth (default 5)
+	Progress ProgressFunc // Optional progress callback
+
+	// MaxPathsPerSource is how many distinct paths to collect from each
+	// entrypoint, shortest first (default 1, max maxTracePathsPerSource)
+	MaxPathsPerSource int
+
+	// MinConfidence drops paths whose confidence is lower (0 = keep all)
+	MinConfidence float64
 }
 
 // TraceUsageResponse provides paths from entrypoints to a target symbol.
@@ -1458,6 +1867,8 @@ type TraceUsageResponse struct {
 	Confidence      float64               `json:"confidence"`
 	ConfidenceBasis []ConfidenceBasisItem `json:"confidenceBasis"`
 	Limitations     []string              `json:"limitations,omitempty"`
+
+	FilteredByConfidence int `json:"filteredByConfidence,omitempty"` // Paths dropped by minConfidence
 }
 
 // UsagePath represents a path from an entrypoint to the target.
@@ -1475,6 +1886,10 @@ type PathNode struct {
 	Kind     string        `json:"kind,omitempty"`
 	Location *LocationInfo `json:"location,omitempty"`
 	Role     string        `json:"role"` // entrypoint, intermediate, target
+
+	// Resolved is false when the symbol couldn't be looked up and Name was
+	// derived from the ID by symbolDisplayName
+	Resolved bool `json:"resolved"`
 }
 
 // bfsCache holds per-request caches for BFS traversal
@@ -1485,7 +1900,35 @@ type bfsCache struct {
 
 type symbolCacheEntry struct {
 	name     string
+	kind     string
 	location *LocationInfo
+	resolved bool // False for names derived from the ID after a failed lookup
+}
+
+// symbolDisplayName derives a readable name from a symbol ID alone, for
+// symbols that can't be looked up: the qualified name SCIP IDs encode
+// (e.g. "query.Engine.GetSymbol"), else the ID's trailing name component.
+// Only IDs with no recognizable structure come back unchanged.
+func symbolDisplayName(id string) string {
+	if name, ok := scip.ReadableSymbolID(id); ok {
+		return name
+	}
+	if name := trailingSymbolName(id); name != "" {
+		return name
+	}
+	return id
+}
+
+// callerPathNode builds the entrypoint node of a fallback caller path. The
+// call graph names the callers it found in the index; a caller without a
+// name gets one derived from its ID.
+func callerPathNode(id, name string, loc *LocationInfo) PathNode {
+	node := PathNode{SymbolId: id, Name: name, Role: "entrypoint", Location: loc, Resolved: true}
+	if name == "" || name == id {
+		node.Name = symbolDisplayName(id)
+		node.Resolved = false
+	}
+	return node
 }
 
 // TraceUsage traces how a symbol is reached from system entrypoints.
@@ -1495,12 +1938,16 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 	if opts.MaxPaths <= 0 {
 		opts.MaxPaths = 10
 	}
-	if opts.MaxDepth <= 0 {
-		opts.MaxDepth = 5
+	if opts.MaxPathsPerSource <= 0 {
+		opts.MaxPathsPerSource = 1
 	}
-	if opts.MaxDepth > 5 {
-		opts.MaxDepth = 5 // Heavy tool cap
+	if opts.MaxPathsPerSource > maxTracePathsPerSource {
+		opts.MaxPathsPerSource = maxTracePathsPerSource
 	}
+	depthLimits := e.traversalConfig().TraceUsageLimits()
+	requestedDepth := opts.MaxDepth
+	var depthClamped bool
+	opts.MaxDepth, depthClamped = depthLimits.Resolve(opts.MaxDepth)
 
 	var confidenceBasis []ConfidenceBasisItem
 	var limitations []string
@@ -1513,23 +1960,27 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 	}
 
 	// Resolve target symbol
+	opts.Progress.report("resolving target", 0)
 	targetResp, err := e.GetSymbol(ctx, GetSymbolOptions{SymbolId: opts.SymbolId, RepoStateMode: "full"})
 	if err != nil {
 		return nil, err
 	}
 
 	targetId := opts.SymbolId
-	targetName := opts.SymbolId
+	targetName := symbolDisplayName(opts.SymbolId)
+	targetResolved := false
 	var targetLoc *LocationInfo
 	if targetResp.Symbol != nil {
 		targetId = targetResp.Symbol.StableId
 		targetName = targetResp.Symbol.Name
 		targetLoc = targetResp.Symbol.Location
+		targetResolved = true
 		// Cache the target symbol
-		cache.symbols[targetId] = &symbolCacheEntry{name: targetName, location: targetLoc}
+		cache.symbols[targetId] = &symbolCacheEntry{name: targetName, location: targetLoc, resolved: true}
 	}
 
 	// Get entrypoints to use as start nodes
+	opts.Progress.report("listing entrypoints", 5)
 	entrypointsResp, err := e.ListEntrypoints(ctx, ListEntrypointsOptions{Limit: 50})
 	if err != nil {
 		limitations = append(limitations, "Entrypoint detection failed")
@@ -1548,9 +1999,18 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 
 		if len(entrypoints) > 0 {
 			// Try to find paths from each entrypoint to the target
-			for _, ep := range entrypoints {
-				path := e.findPathBFSCached(ctx, ep.SymbolId, targetId, opts.MaxDepth, cache)
-				if len(path) > 0 {
+			seenPaths := make(map[string]bool)
+		entrypointLoop:
+			for i, ep := range entrypoints {
+				opts.Progress.report("tracing entrypoints", phaseProgress(10, 90, i, len(entrypoints)))
+				for _, path := range e.findPathsBFSCached(ctx, ep.SymbolId, targetId, opts.MaxDepth, opts.MaxPathsPerSource, cache) {
+					// The same route can come back through duplicate entrypoints
+					key := strings.Join(path, "\x00")
+					if seenPaths[key] {
+						continue
+					}
+					seenPaths[key] = true
+
 					// Build path nodes - resolve names only for final path
 					nodes := make([]PathNode, len(path))
 					for i, nodeId := range path {
@@ -1561,26 +2021,23 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 							role = "target"
 						}
 
-						// Get symbol info from cache or resolve
-						nodeName := nodeId
-						var loc *LocationInfo
-						if cached, ok := cache.symbols[nodeId]; ok {
-							nodeName = cached.name
-							loc = cached.location
-						} else {
-							// Resolve and cache
+						// Get symbol info from cache or resolve; a failed lookup
+						// is cached too, under a name derived from the ID
+						cached, ok := cache.symbols[nodeId]
+						if !ok {
+							cached = &symbolCacheEntry{name: symbolDisplayName(nodeId)}
 							if symResp, err := e.GetSymbol(ctx, GetSymbolOptions{SymbolId: nodeId, RepoStateMode: "head"}); err == nil && symResp.Symbol != nil {
-								nodeName = symResp.Symbol.Name
-								loc = symResp.Symbol.Location
-								cache.symbols[nodeId] = &symbolCacheEntry{name: nodeName, location: loc}
+								cached = &symbolCacheEntry{name: symResp.Symbol.Name, location: symResp.Symbol.Location, resolved: true}
 							}
+							cache.symbols[nodeId] = cached
 						}
 
 						nodes[i] = PathNode{
 							SymbolId: nodeId,
-							Name:     nodeName,
+							Name:     cached.name,
 							Role:     role,
-							Location: loc,
+							Location: cached.location,
+							Resolved: cached.resolved,
 						}
 					}
 
@@ -1606,7 +2063,7 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 
 					// Check limit
 					if len(paths) >= opts.MaxPaths {
-						break
+						break entrypointLoop
 					}
 				}
 			}
@@ -1614,10 +2071,11 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 
 		// Fallback: if no paths from entrypoints, use direct callers
 		if len(paths) == 0 {
+			opts.Progress.report("finding callers", 90)
 			limitations = append(limitations, "Entrypoint set unavailable; showing nearest callers")
 
 			// Get callers and build short paths from them
-			graph, err := e.scipAdapter.BuildCallGraph(targetId, scip.CallGraphOptions{
+			graph, err := e.buildCallGraph(ctx, targetId, scip.CallGraphOptions{
 				Direction: scip.DirectionCallers,
 				MaxDepth:  2,
 				MaxNodes:  20,
@@ -1644,17 +2102,13 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 					usagePath := UsagePath{
 						PathType: pathType,
 						Nodes: []PathNode{
-							{
-								SymbolId: caller.SymbolID,
-								Name:     caller.Name,
-								Role:     "entrypoint",
-								Location: callerLoc,
-							},
+							callerPathNode(caller.SymbolID, caller.Name, callerLoc),
 							{
 								SymbolId: targetId,
 								Name:     targetName,
 								Role:     "target",
 								Location: targetLoc,
+								Resolved: targetResolved,
 							},
 						},
 						Confidence: pathConfidence,
@@ -1681,9 +2135,10 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 		limitations = append(limitations, "SCIP index unavailable; path tracing requires static analysis")
 	}
 
-	// Sort paths by ranking score
-	sort.Slice(paths, func(i, j int) bool {
-		return paths[i].Ranking.Score > paths[j].Ranking.Score
+	// Sort paths by ranking score with deterministic tie-breaker
+	sortUsagePaths(paths)
+	paths, filteredPaths := filterByConfidence(paths, opts.MinConfidence, func(p UsagePath) float64 {
+		return p.Confidence
 	})
 
 	// Compute overall confidence
@@ -1711,6 +2166,11 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 		Confidence:      confidence,
 		ConfidenceBasis: confidenceBasis,
 		Limitations:     limitations,
+
+		FilteredByConfidence: filteredPaths,
+	}
+	if depthClamped {
+		response.Truncation = withDepthClamp(nil, requestedDepth, depthLimits.Max)
 	}
 
 	// Add provenance
@@ -1719,6 +2179,7 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 		RepoStateId:     repoState.RepoStateId,
 		RepoStateDirty:  repoState.Dirty,
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
+		Extra:           e.provenanceExtra(ctx),
 	}
 
 	// Add drilldowns
@@ -1726,22 +2187,36 @@ func (e *Engine) TraceUsage(ctx context.Context, opts TraceUsageOptions) (*Trace
 		{
 			Label:          fmt.Sprintf("Call graph for %s", targetName),
 			Query:          fmt.Sprintf("getCallGraph %s", targetId),
+			Tool:           "getCallGraph",
+			Params:         map[string]interface{}{"symbolId": targetId},
 			RelevanceScore: 0.9,
 		},
 		{
 			Label:          fmt.Sprintf("Explain %s", targetName),
 			Query:          fmt.Sprintf("explainSymbol %s", targetId),
+			Tool:           "explainSymbol",
+			Params:         map[string]interface{}{"symbolId": targetId},
 			RelevanceScore: 0.85,
 		},
 	}
 
+	opts.Progress.report("done", 100)
 	return response, nil
 }
 
-// findPathBFSCached performs BFS to find a path from source to target with caching.
-func (e *Engine) findPathBFSCached(ctx context.Context, sourceId, targetId string, maxDepth int, cache *bfsCache) []string {
+// maxTracePathsPerSource caps TraceUsageOptions.MaxPathsPerSource
+const maxTracePathsPerSource = 5
+
+// findPathsBFSCached performs BFS to find up to maxPaths distinct paths from
+// source to target with caching, shortest first. Each node may be reached
+// along at most maxPaths routes, so with maxPaths 1 this is a plain BFS that
+// stops at the first path. Paths never revisit a node.
+func (e *Engine) findPathsBFSCached(ctx context.Context, sourceId, targetId string, maxDepth, maxPaths int, cache *bfsCache) [][]string {
 	if sourceId == targetId {
-		return []string{sourceId}
+		return [][]string{{sourceId}}
+	}
+	if maxPaths <= 0 {
+		maxPaths = 1
 	}
 
 	// BFS state
@@ -1754,9 +2229,10 @@ func (e *Engine) findPathBFSCached(ctx context.Context, sourceId, targetId strin
 	const maxVisitedNodes = 500 // Cap to prevent explosion
 	const maxCalleesPerNode = 30
 
-	visited := make(map[string]bool)
+	visited := make(map[string]int) // symbolId -> routes that reached it
 	queue := []bfsNode{{id: sourceId, path: []string{sourceId}, depth: 0}}
-	visited[sourceId] = true
+	visited[sourceId] = maxPaths
+	var found [][]string
 
 	for len(queue) > 0 && len(visited) < maxVisitedNodes {
 		current := queue[0]
@@ -1774,7 +2250,7 @@ func (e *Engine) findPathBFSCached(ctx context.Context, sourceId, targetId strin
 				continue
 			}
 
-			graph, err := e.scipAdapter.BuildCallGraph(current.id, scip.CallGraphOptions{
+			graph, err := e.buildCallGraph(ctx, current.id, scip.CallGraphOptions{
 				Direction: scip.DirectionCallees,
 				MaxDepth:  1,
 				MaxNodes:  maxCalleesPerNode,
@@ -1794,17 +2270,21 @@ func (e *Engine) findPathBFSCached(ctx context.Context, sourceId, targetId strin
 		}
 
 		for _, calleeId := range callees {
-			if visited[calleeId] {
+			if visited[calleeId] >= maxPaths || pathContains(current.path, calleeId) {
 				continue
 			}
-			visited[calleeId] = true
+			visited[calleeId]++
 
 			newPath := make([]string, len(current.path)+1)
 			copy(newPath, current.path)
 			newPath[len(current.path)] = calleeId
 
 			if calleeId == targetId {
-				return newPath
+				found = append(found, newPath)
+				if len(found) >= maxPaths {
+					return found
+				}
+				continue
 			}
 
 			queue = append(queue, bfsNode{
@@ -1815,7 +2295,17 @@ func (e *Engine) findPathBFSCached(ctx context.Context, sourceId, targetId strin
 		}
 	}
 
-	return nil // No path found
+	return found
+}
+
+// pathContains reports whether id already appears on path.
+func pathContains(path []string, id string) bool {
+	for _, p := range path {
+		if p == id {
+			return true
+		}
+	}
+	return false
 }
 
 // computePathScore calculates a ranking score for a usage path.
@@ -1851,13 +2341,44 @@ func computePathScore(pathType string, pathLength int, confidence float64) float
 	return score
 }
 
-// isTestFilePath checks if a path is a test file.
+// isTestFilePath checks if a path is a test file. Besides the generic
+// patterns (which cover Elixir's _test.exs), it knows ScalaTest and specs2
+// suffixes and Zig's conventional tests.zig.
 func isTestFilePath(path string) bool {
 	pathLower := strings.ToLower(path)
-	return strings.Contains(pathLower, "_test.") ||
+	if strings.Contains(pathLower, "_test.") ||
 		strings.Contains(pathLower, ".test.") ||
 		strings.Contains(pathLower, "/test/") ||
-		strings.Contains(pathLower, "/tests/")
+		strings.Contains(pathLower, "/tests/") {
+		return true
+	}
+
+	base := filepath.Base(path)
+	switch filepath.Ext(base) {
+	case ".scala":
+		return strings.HasSuffix(base, "Spec.scala") ||
+			strings.HasSuffix(base, "Test.scala") ||
+			strings.HasSuffix(base, "Suite.scala")
+	case ".zig":
+		return base == "tests.zig" || base == "test.zig"
+	}
+	return false
+}
+
+// isGeneratedFilePath checks if a path looks like generated code.
+func isGeneratedFilePath(path string) bool {
+	pathLower := strings.ToLower(path)
+	base := pathLower
+	if idx := strings.LastIndex(pathLower, "/"); idx >= 0 {
+		base = pathLower[idx+1:]
+	}
+	return strings.Contains(base, ".pb.") ||
+		strings.Contains(base, "_generated.") ||
+		strings.Contains(base, ".generated.") ||
+		strings.Contains(base, ".gen.") ||
+		strings.HasSuffix(base, "_gen.go") ||
+		strings.HasPrefix(pathLower, "generated/") ||
+		strings.Contains(pathLower, "/generated/")
 }
 
 // SummarizeDiffOptions controls summarizeDiff behavior.
@@ -1866,12 +2387,42 @@ type SummarizeDiffOptions struct {
 	CommitRange *CommitRangeSelector `json:"commitRange,omitempty"`
 	Commit      string               `json:"commit,omitempty"`
 	TimeWindow  *TimeWindowSelector  `json:"timeWindow,omitempty"`
+
+	// CheckErrorHandling scans added lines for ignored errors (Go only)
+	CheckErrorHandling bool `json:"checkErrorHandling,omitempty"`
+
+	// BaseIndexPath is a SCIP index built at the diff base. When set,
+	// modified public symbols get their signatures compared with the
+	// current index.
+	BaseIndexPath string `json:"baseIndexPath,omitempty"`
+
+	// ExcludePaths drops matching files (globs, ** spans directories)
+	// before the file cap, so vendored or generated code neither crowds
+	// out source changes nor produces symbols and risk signals.
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+
+	// IncludeComplexity compares function complexity at base and head for
+	// changed core and entrypoint files and flags increases. Off by default
+	// because it parses every such file twice.
+	IncludeComplexity bool `json:"includeComplexity,omitempty"`
+
+	// CyclomaticThreshold and CognitiveThreshold are the per-function
+	// complexity limits (default: 15 and 20). Crossing one raises a
+	// high-severity complexity-increase signal.
+	CyclomaticThreshold int `json:"cyclomaticThreshold,omitempty"`
+	CognitiveThreshold  int `json:"cognitiveThreshold,omitempty"`
 }
 
 // CommitRangeSelector specifies a base..head range.
 type CommitRangeSelector struct {
 	Base string `json:"base"`
 	Head string `json:"head"`
+
+	// MergeBase diffs head against the merge base of base and head
+	// (git's base...head) instead of against base itself, so only the
+	// changes made on head show, as in a pull request review. Used by
+	// summarizeDiff.
+	MergeBase bool `json:"mergeBase,omitempty"`
 }
 
 // TimeWindowSelector specifies a time range.
@@ -1897,8 +2448,8 @@ type SummarizeDiffResponse struct {
 
 // DiffSelector records which selector was used.
 type DiffSelector struct {
-	Type  string `json:"type"` // commitRange, commit, timeWindow
-	Value string `json:"value"`
+	Type  string `json:"type"`  // commitRange, commit, timeWindow
+	Value string `json:"value"` // For commitRange, base..head or base...head when diffed from the merge base
 }
 
 // DiffFileChange represents a changed file.
@@ -1922,13 +2473,18 @@ type DiffSymbolAffected struct {
 	ChangeType   string `json:"changeType"` // added, modified, deleted
 	IsPublicAPI  bool   `json:"isPublicApi"`
 	IsEntrypoint bool   `json:"isEntrypoint"`
+
+	// SignatureChange is set when a modified public symbol's signature
+	// differs between base and head; nil when unchanged or not comparable
+	SignatureChange *SignatureDelta `json:"signatureChange,omitempty"`
 }
 
 // DiffRiskSignal represents a risk indicator.
 type DiffRiskSignal struct {
-	Type        string  `json:"type"`     // api-change, signature-change, breaking-change, high-churn, test-gap
+	Type        string  `json:"type"`     // api-change, signature-change, breaking-change, high-churn, test-gap, error-handling, complexity-increase
 	Severity    string  `json:"severity"` // low, medium, high
 	FilePath    string  `json:"filePath"`
+	Line        int     `json:"line,omitempty"` // Set when the signal points at one line
 	Description string  `json:"description"`
 	Confidence  float64 `json:"confidence"`
 }
@@ -1982,6 +2538,11 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		return nil, fmt.Errorf("exactly one selector required: commitRange, commit, or timeWindow")
 	}
 
+	excludes, err := compileExcludePaths(opts.ExcludePaths)
+	if err != nil {
+		return nil, err
+	}
+
 	var confidenceBasis []ConfidenceBasisItem
 	var limitations []string
 	changedFiles := []DiffFileChange{}
@@ -2003,12 +2564,18 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 	// Get diff based on selector type
 	var diffStats []git.DiffStats
 	var base, head string
-	var err error
 
 	if opts.CommitRange != nil {
 		selector = DiffSelector{Type: "commitRange", Value: opts.CommitRange.Base + ".." + opts.CommitRange.Head}
 		base = opts.CommitRange.Base
 		head = opts.CommitRange.Head
+		if opts.CommitRange.MergeBase {
+			selector.Value = opts.CommitRange.Base + "..." + opts.CommitRange.Head
+			base, err = e.gitAdapter.GetMergeBase(opts.CommitRange.Base, head)
+			if err != nil {
+				return nil, fmt.Errorf("no common ancestor between %s and %s: %w", opts.CommitRange.Base, head, err)
+			}
+		}
 		diffStats, err = e.gitAdapter.GetCommitRangeDiff(base, head)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get commit range diff: %w", err)
@@ -2026,6 +2593,8 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		}
 	} else if opts.Commit != "" {
 		selector = DiffSelector{Type: "commit", Value: opts.Commit}
+		base = opts.Commit + "^"
+		head = opts.Commit
 		diffStats, err = e.gitAdapter.GetCommitDiff(opts.Commit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get commit diff: %w", err)
@@ -2083,6 +2652,14 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		}
 	}
 
+	if len(excludes) > 0 {
+		var excluded int
+		diffStats, excluded = e.excludeDiffStats(diffStats, excludes)
+		if excluded > 0 {
+			limitations = append(limitations, fmt.Sprintf("Excluded %d files matching excludePaths", excluded))
+		}
+	}
+
 	// Cap files analyzed
 	const maxFiles = 50
 	if len(diffStats) > maxFiles {
@@ -2090,8 +2667,14 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		diffStats = diffStats[:maxFiles]
 	}
 
-	// Process changed files
+	// Process changed files. Paths are normalized to repo-relative form so
+	// they line up with SCIP document paths below.
+	roles := e.newFileRoleClassifier()
 	for _, stat := range diffStats {
+		stat.FilePath = paths.ToRepoRelative(stat.FilePath, e.repoRoot)
+		if stat.OldPath != "" {
+			stat.OldPath = paths.ToRepoRelative(stat.OldPath, e.repoRoot)
+		}
 		changeType := "modified"
 		if stat.IsNew {
 			changeType = "added"
@@ -2102,7 +2685,7 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		}
 
 		language := detectLanguage(stat.FilePath)
-		role := classifyFileRole(stat.FilePath)
+		role := roles.classify(stat.FilePath)
 		riskLevel := classifyFileRiskLevel(stat, role)
 
 		changedFiles = append(changedFiles, DiffFileChange{
@@ -2148,6 +2731,8 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		})
 
 		// For each changed file, find symbols defined there
+		api := e.loadDeclaredAPI()
+		scipPaths := e.scipPaths()
 		for _, file := range changedFiles {
 			if file.ChangeType == "deleted" {
 				continue
@@ -2155,13 +2740,14 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 
 			searchResult, err := e.scipAdapter.SearchSymbols(ctx, "", backends.SearchOptions{
 				MaxResults: 30,
-				Scope:      []string{file.FilePath},
+				Scope:      []string{scipPaths.scipPath(file.FilePath)},
 			})
 
 			if err == nil && searchResult != nil {
 				for _, sym := range searchResult.Symbols {
-					if sym.Location.Path == file.FilePath {
-						isPublicAPI := sym.Visibility == "public" || isExportedSymbol(sym.Name, sym.Visibility, file.Language)
+					if scipPaths.canonical(sym.Location.Path) == file.FilePath {
+						visible := sym.Visibility == "public" || isExportedSymbol(sym.Name, sym.Visibility, file.Language)
+						isPublicAPI, _ := api.isPublicAPI(file.FilePath, sym.Name, sym.ContainerName, visible)
 						symbolsAffected = append(symbolsAffected, DiffSymbolAffected{
 							SymbolId:    sym.StableID,
 							Name:        sym.Name,
@@ -2185,6 +2771,10 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 				}
 			}
 		}
+
+		signatureSignals, signatureLimits := e.addSignatureChanges(ctx, opts.BaseIndexPath, symbolsAffected)
+		riskSignals = append(riskSignals, signatureSignals...)
+		limitations = append(limitations, signatureLimits...)
 	} else {
 		confidenceBasis = append(confidenceBasis, ConfidenceBasisItem{
 			Backend: "scip",
@@ -2193,6 +2783,15 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		limitations = append(limitations, "SCIP index unavailable; symbol-level analysis limited")
 	}
 
+	if opts.CheckErrorHandling && base != "" && head != "" {
+		riskSignals = append(riskSignals, e.errorHandlingSignals(base, head, changedFiles)...)
+	}
+	if opts.IncludeComplexity && base != "" && head != "" {
+		complexitySignals, complexityLimits := e.complexitySignals(ctx, base, head, changedFiles, opts.CyclomaticThreshold, opts.CognitiveThreshold)
+		riskSignals = append(riskSignals, complexitySignals...)
+		limitations = append(limitations, complexityLimits...)
+	}
+
 	// Cap symbols and signals
 	if len(symbolsAffected) > 30 {
 		symbolsAffected = symbolsAffected[:30]
@@ -2247,6 +2846,7 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		RepoStateId:     repoState.RepoStateId,
 		RepoStateDirty:  repoState.Dirty,
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
+		Extra:           e.provenanceExtra(ctx),
 	}
 
 	// Add drilldowns
@@ -2254,6 +2854,7 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		{
 			Label:          "View architecture",
 			Query:          "getArchitecture",
+			Tool:           "getArchitecture",
 			RelevanceScore: 0.7,
 		},
 	}
@@ -2261,6 +2862,8 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		response.Drilldowns = append(response.Drilldowns, output.Drilldown{
 			Label:          fmt.Sprintf("Explain %s", filepath.Base(changedFiles[0].FilePath)),
 			Query:          fmt.Sprintf("explainFile %s", changedFiles[0].FilePath),
+			Tool:           "explainFile",
+			Params:         map[string]interface{}{"filePath": changedFiles[0].FilePath},
 			RelevanceScore: 0.85,
 		})
 	}
@@ -2268,6 +2871,8 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 		response.Drilldowns = append(response.Drilldowns, output.Drilldown{
 			Label:          fmt.Sprintf("Explore %s", symbolsAffected[0].Name),
 			Query:          fmt.Sprintf("explainSymbol %s", symbolsAffected[0].SymbolId),
+			Tool:           "explainSymbol",
+			Params:         map[string]interface{}{"symbolId": symbolsAffected[0].SymbolId},
 			RelevanceScore: 0.8,
 		})
 	}
@@ -2275,6 +2880,19 @@ func (e *Engine) SummarizeDiff(ctx context.Context, opts SummarizeDiffOptions) (
 	return response, nil
 }
 
+// excludeDiffStats drops diff entries whose repo-relative path matches one
+// of excludes, returning the kept entries and the number dropped.
+func (e *Engine) excludeDiffStats(stats []git.DiffStats, excludes []*scopeMatcher) ([]git.DiffStats, int) {
+	kept := stats[:0]
+	for _, stat := range stats {
+		if matchesAny(excludes, paths.ToRepoRelative(stat.FilePath, e.repoRoot)) {
+			continue
+		}
+		kept = append(kept, stat)
+	}
+	return kept, len(stats) - len(kept)
+}
+
 // classifyFileRiskLevel determines the risk level of a file change.
 func classifyFileRiskLevel(stat git.DiffStats, role string) string {
 	totalChanges := stat.Additions + stat.Deletions
@@ -2333,6 +2951,15 @@ func suggestTestPath(filePath, language string) string {
 		dir := filepath.Dir(filePath)
 		name := filepath.Base(base)
 		return filepath.Join(dir, "test_"+name+".py")
+	case "elixir":
+		// Mix mirrors lib/ under test/
+		if rest, ok := strings.CutPrefix(base, "lib/"); ok {
+			return "test/" + rest + "_test.exs"
+		}
+		return base + "_test.exs"
+	case "scala":
+		// sbt mirrors src/main/scala under src/test/scala
+		return strings.Replace(base, "src/main/", "src/test/", 1) + "Spec.scala"
 	default:
 		return ""
 	}
@@ -2434,8 +3061,16 @@ func computeDiffConfidence(basis []ConfidenceBasisItem, limitations []string) fl
 // GetHotspotsOptions controls getHotspots behavior.
 type GetHotspotsOptions struct {
 	TimeWindow *TimeWindowSelector `json:"timeWindow,omitempty"`
-	Scope      string              `json:"scope,omitempty"` // Module to focus on
-	Limit      int                 `json:"limit,omitempty"` // Max results (default 20)
+	Scope      string              `json:"scope,omitempty"`  // Module to focus on
+	Limit      int                 `json:"limit,omitempty"`  // Max results (default 20)
+	Format     string              `json:"format,omitempty"` // "json" (default) or "csv" to also fill Csv
+
+	// ExcludeGenerated drops generated files before ranking (default: true)
+	ExcludeGenerated bool `json:"excludeGenerated,omitempty"`
+
+	// IncludeAuthors fills TopAuthors on each hotspot. Off by default: it
+	// costs one git call per returned hotspot.
+	IncludeAuthors bool `json:"includeAuthors,omitempty"`
 }
 
 // GetHotspotsResponse provides ranked hotspot files.
@@ -2447,6 +3082,7 @@ type GetHotspotsResponse struct {
 	Confidence      float64               `json:"confidence"`
 	ConfidenceBasis []ConfidenceBasisItem `json:"confidenceBasis"`
 	Limitations     []string              `json:"limitations,omitempty"`
+	Csv             string                `json:"csv,omitempty"` // With Format "csv": header plus one row per hotspot
 }
 
 // HotspotV52 represents a hotspot with v5.2 ranking signals.
@@ -2455,11 +3091,24 @@ type HotspotV52 struct {
 	Role       string             `json:"role,omitempty"` // core, test, config, unknown
 	Language   string             `json:"language,omitempty"`
 	Churn      HotspotChurn       `json:"churn"`
+	Pattern    string             `json:"churnPattern,omitempty"` // oscillating, growing, steady, shrinking
 	Coupling   *HotspotCoupling   `json:"coupling,omitempty"`
 	Complexity *HotspotComplexity `json:"complexity,omitempty"` // v6.2.2: tree-sitter complexity
 	Recency    string             `json:"recency"`              // recent, moderate, stale
+	Trend      string             `json:"trend,omitempty"`      // rising, falling, steady; empty when the window is unknown
 	RiskLevel  string             `json:"riskLevel"`            // low, medium, high
 	Ranking    *RankingV52        `json:"ranking"`
+	TopAuthors []AuthorChurn      `json:"topAuthors,omitempty"` // With IncludeAuthors: heaviest committers, max 5
+}
+
+// maxHotspotAuthors caps TopAuthors on a hotspot.
+const maxHotspotAuthors = 5
+
+// AuthorChurn is one author's share of a file's churn.
+type AuthorChurn struct {
+	Author     string  `json:"author"`
+	Commits    int     `json:"commits"`
+	Percentage float64 `json:"percentage"` // Share of all commits to the file in the window
 }
 
 // HotspotChurn contains churn-related metrics.
@@ -2495,6 +3144,11 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 	if opts.Limit > 50 {
 		opts.Limit = 50 // Hard cap per v5.2 spec
 	}
+	switch opts.Format {
+	case "", HotspotsFormatJSON, HotspotsFormatCSV:
+	default:
+		return nil, fmt.Errorf("invalid format %q: must be %q or %q", opts.Format, HotspotsFormatJSON, HotspotsFormatCSV)
+	}
 
 	var confidenceBasis []ConfidenceBasisItem
 	var limitations []string
@@ -2530,6 +3184,9 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 		return nil, fmt.Errorf("failed to get hotspots: %w", err)
 	}
 
+	// Count symlinked files once, under the path of their target
+	gitHotspots = mergeSymlinkedHotspots(gitHotspots, newPathCanonicalizer(e.repoRoot))
+
 	// Apply scope filter if specified
 	if opts.Scope != "" {
 		filtered := []git.ChurnMetrics{}
@@ -2541,12 +3198,38 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 		gitHotspots = filtered
 	}
 
+	if opts.ExcludeGenerated {
+		generated := newGeneratedFileFilter(e.repoRoot)
+		filtered := []git.ChurnMetrics{}
+		for _, h := range gitHotspots {
+			if !generated.isGenerated(h.FilePath) {
+				filtered = append(filtered, h)
+			}
+		}
+		gitHotspots = filtered
+	}
+
+	// Structural coupling needs the SCIP reference graph
+	var scipPaths *scipPathIndex
+	couplingTruncated := 0
+	if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
+		scipPaths = e.scipPaths()
+	}
+
+	// Trends compare the two halves of the window, so they need its bounds
+	windowStart, windowEnd, hasWindow := hotspotWindow(since, opts.TimeWindow)
+
 	// Convert to v5.2 format with enrichment
+	roles := e.newFileRoleClassifier()
 	for _, gh := range gitHotspots {
-		role := classifyFileRole(gh.FilePath)
+		role := roles.classify(gh.FilePath)
 		language := detectLanguage(gh.FilePath)
 		recency := classifyRecency(gh.LastModified)
 		riskLevel := classifyHotspotRisk(gh, role)
+		trend := ""
+		if hasWindow {
+			trend = computeChurnTrend(gh.ChangeTimes, windowStart, windowEnd)
+		}
 
 		// Calculate ranking score
 		// Score = churn_score * recency_multiplier * role_multiplier
@@ -2570,7 +3253,34 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 			roleMultiplier = 1.2
 		}
 
-		score := gh.HotspotScore * recencyMultiplier * roleMultiplier
+		// Files that keep getting removed and re-added point to indecision or
+		// reverts, which steady churn of the same size does not
+		patternMultiplier := 1.0
+		if gh.ChurnPattern == git.ChurnPatternOscillating {
+			patternMultiplier = 1.3
+		}
+
+		// Core code that is heating up matters more than core code cooling down
+		trendMultiplier := 1.0
+		if trend == ChurnTrendRising && (role == "core" || role == "entrypoint") {
+			trendMultiplier = 1.2
+		}
+
+		var coupling *HotspotCoupling
+		if scipPaths != nil {
+			if fc, ok := e.scipAdapter.FileCoupling(scipPaths.scipPath(gh.FilePath), maxCouplingVisits); ok {
+				coupling = hotspotCoupling(fc)
+				if fc.Truncated {
+					couplingTruncated++
+				}
+			}
+		}
+		couplingScore := 0.0
+		if coupling != nil {
+			couplingScore = coupling.Score
+		}
+
+		score := gh.HotspotScore * recencyMultiplier * roleMultiplier * patternMultiplier * trendMultiplier * couplingMultiplier(coupling)
 
 		hotspot := HotspotV52{
 			FilePath: gh.FilePath,
@@ -2582,12 +3292,17 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 				AverageChanges: gh.AverageChanges,
 				Score:          gh.HotspotScore,
 			},
+			Pattern:   gh.ChurnPattern,
+			Coupling:  coupling,
 			Recency:   recency,
+			Trend:     trend,
 			RiskLevel: riskLevel,
 			Ranking: NewRankingV52(score, map[string]interface{}{
 				"churn":    gh.HotspotScore,
-				"coupling": 0.0, // Not yet implemented
+				"coupling": couplingScore,
 				"recency":  recency,
+				"pattern":  gh.ChurnPattern,
+				"trend":    trend,
 			}),
 		}
 
@@ -2595,12 +3310,7 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 	}
 
 	// Sort by ranking score with deterministic tie-breaker
-	sort.Slice(hotspots, func(i, j int) bool {
-		if hotspots[i].Ranking.Score != hotspots[j].Ranking.Score {
-			return hotspots[i].Ranking.Score > hotspots[j].Ranking.Score
-		}
-		return hotspots[i].FilePath < hotspots[j].FilePath
-	})
+	sortHotspots(hotspots)
 
 	// Track total before limiting
 	totalCount := len(hotspots)
@@ -2642,15 +3352,36 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 		}
 	}
 
-	// Add coupling data if SCIP available
-	if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
+	// Per-author churn costs a git call per hotspot, so it is opt-in
+	var authorContrib *BackendContribution
+	if opts.IncludeAuthors {
+		authorStart := time.Now()
+		resolved := 0
+		for i := range hotspots {
+			counts, err := e.gitAdapter.GetFileAuthorCommitCounts(hotspots[i].FilePath, since)
+			if err != nil {
+				continue
+			}
+			hotspots[i].TopAuthors = topAuthorChurn(counts, maxHotspotAuthors)
+			resolved++
+		}
+		authorContrib = &BackendContribution{
+			BackendId:   "git",
+			Available:   true,
+			Used:        true,
+			ResultCount: resolved,
+			DurationMs:  time.Since(authorStart).Milliseconds(),
+		}
+	}
+
+	if scipPaths != nil {
 		confidenceBasis = append(confidenceBasis, ConfidenceBasisItem{
 			Backend: "scip",
-			Status:  "partial", // We're only using it for coupling hints
+			Status:  "available",
 		})
-		// Note: Full coupling analysis would require more work
-		// For now, we mark SCIP as available but coupling is not fully implemented
-		limitations = append(limitations, "Coupling analysis not yet implemented; using churn-only ranking")
+		if couplingTruncated > 0 {
+			limitations = append(limitations, fmt.Sprintf("Coupling counts are lower bounds for %d large files", couplingTruncated))
+		}
 	} else {
 		confidenceBasis = append(confidenceBasis, ConfidenceBasisItem{
 			Backend: "scip",
@@ -2679,6 +3410,13 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 		ConfidenceBasis: confidenceBasis,
 		Limitations:     limitations,
 	}
+	if opts.Format == HotspotsFormatCSV {
+		csvText, err := hotspotsCSV(hotspots)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render hotspots as CSV: %w", err)
+		}
+		response.Csv = csvText
+	}
 
 	// Add provenance
 	repoState, _ := e.GetRepoState(ctx, "head")
@@ -2686,6 +3424,11 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 		RepoStateId:     repoState.RepoStateId,
 		RepoStateDirty:  repoState.Dirty,
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
+		Extra:           e.provenanceExtra(ctx),
+	}
+	if authorContrib != nil {
+		// Author lookups are the costly part; report their share of the duration
+		response.Provenance.Backends = []BackendContribution{*authorContrib}
 	}
 
 	// Add drilldowns
@@ -2694,11 +3437,14 @@ func (e *Engine) GetHotspots(ctx context.Context, opts GetHotspotsOptions) (*Get
 			{
 				Label:          fmt.Sprintf("Explain %s", filepath.Base(hotspots[0].FilePath)),
 				Query:          fmt.Sprintf("explainFile %s", hotspots[0].FilePath),
+				Tool:           "explainFile",
+				Params:         map[string]interface{}{"filePath": hotspots[0].FilePath},
 				RelevanceScore: 0.9,
 			},
 			{
 				Label:          "View recent changes",
 				Query:          "summarizeDiff",
+				Tool:           "summarizeDiff",
 				RelevanceScore: 0.8,
 			},
 		}
@@ -2735,6 +3481,101 @@ func classifyRecency(lastModified string) string {
 	}
 }
 
+// Churn trends compare a file's changes in the newer half of the time window
+// with the older half.
+const (
+	ChurnTrendRising  = "rising"
+	ChurnTrendFalling = "falling"
+	ChurnTrendSteady  = "steady"
+)
+
+// hotspotWindow returns the bounds of the hotspot time window. since is
+// the --since value passed to git; the end is the window's End or now. ok
+// is false when since isn't a date (git also accepts e.g. "2 weeks ago").
+func hotspotWindow(since string, window *TimeWindowSelector) (start, end time.Time, ok bool) {
+	start, ok = parseWindowTime(since)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	end = time.Now()
+	if window != nil && window.End != "" {
+		if t, endOk := parseWindowTime(window.End); endOk {
+			end = t
+		}
+	}
+	return start, end, end.After(start)
+}
+
+// parseWindowTime parses a time window bound given as a date or RFC 3339.
+func parseWindowTime(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// computeChurnTrend splits the window in half and compares how often the
+// file changed in each, with the same thresholds computeUsageTrend applies
+// to call counts. A single change has no direction, so it reads as steady.
+func computeChurnTrend(changeTimes []string, start, end time.Time) string {
+	mid := start.Add(end.Sub(start) / 2)
+	var older, newer int
+	for _, s := range changeTimes {
+		t, ok := parseWindowTime(s)
+		if !ok || t.Before(start) || t.After(end) {
+			continue
+		}
+		if t.Before(mid) {
+			older++
+		} else {
+			newer++
+		}
+	}
+
+	if older+newer < 2 {
+		return ChurnTrendSteady
+	}
+	if older == 0 {
+		return ChurnTrendRising
+	}
+	ratio := float64(newer) / float64(older)
+	switch {
+	case ratio > 1.2:
+		return ChurnTrendRising
+	case ratio < 0.8:
+		return ChurnTrendFalling
+	default:
+		return ChurnTrendSteady
+	}
+}
+
+// topAuthorChurn converts per-author commit counts, most commits first, into
+// the top n authors with their share of all the file's commits.
+func topAuthorChurn(counts []git.AuthorCommitCount, n int) []AuthorChurn {
+	total := 0
+	for _, c := range counts {
+		total += c.Commits
+	}
+	if total == 0 {
+		return nil
+	}
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	authors := make([]AuthorChurn, 0, len(counts))
+	for _, c := range counts {
+		authors = append(authors, AuthorChurn{
+			Author:     c.Author,
+			Commits:    c.Commits,
+			Percentage: math.Round(float64(c.Commits)/float64(total)*1000) / 10,
+		})
+	}
+	return authors
+}
+
 // classifyHotspotRisk determines risk level for a hotspot.
 func classifyHotspotRisk(churn git.ChurnMetrics, role string) string {
 	// High churn + core file = high risk
@@ -2752,6 +3593,14 @@ func classifyHotspotRisk(churn git.ChurnMetrics, role string) string {
 		return "high"
 	}
 
+	// Oscillating files need attention even at moderate change counts
+	if churn.ChurnPattern == git.ChurnPatternOscillating {
+		if churn.ChangeCount > 10 {
+			return "high"
+		}
+		return "medium"
+	}
+
 	// Moderate churn
 	if churn.ChangeCount > 10 {
 		return "medium"
@@ -2828,7 +3677,7 @@ func (e *Engine) ExplainPath(ctx context.Context, opts ExplainPathOptions) (*Exp
 	}
 
 	// Classify the file role using multiple signals
-	role, explanation, basis := classifyPathRole(relPath)
+	role, explanation, basis := e.newFileRoleClassifier().classifyPath(relPath)
 	classificationBasis = basis
 
 	// Add naming-based confidence
@@ -2887,6 +3736,7 @@ func (e *Engine) ExplainPath(ctx context.Context, opts ExplainPathOptions) (*Exp
 		RepoStateId:     repoState.RepoStateId,
 		RepoStateDirty:  repoState.Dirty,
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
+		Extra:           e.provenanceExtra(ctx),
 	}
 
 	// Add drilldowns
@@ -2894,6 +3744,8 @@ func (e *Engine) ExplainPath(ctx context.Context, opts ExplainPathOptions) (*Exp
 		{
 			Label:          "Explain file contents",
 			Query:          fmt.Sprintf("explainFile %s", relPath),
+			Tool:           "explainFile",
+			Params:         map[string]interface{}{"filePath": relPath},
 			RelevanceScore: 0.9,
 		},
 	}
@@ -3089,8 +3941,29 @@ func computePathConfidence(basis []ClassificationBasis) float64 {
 // ListKeyConceptsOptions controls listKeyConcepts behavior.
 type ListKeyConceptsOptions struct {
 	Limit int `json:"limit,omitempty"` // Max concepts (default 12, max 12)
+
+	// ExcludeGenerated skips symbols and files in generated code (default: true)
+	ExcludeGenerated bool `json:"excludeGenerated,omitempty"`
+
+	// RespectGitignore skips paths matched by the repo's .gitignore files
+	// when concepts are taken from file names (default: true)
+	RespectGitignore bool `json:"respectGitignore,omitempty"`
+
+	// MinOccurrences and MinFiles drop concepts seen fewer times or in fewer
+	// files (defaults 2 and 1; values below the defaults are raised to them).
+	// They apply before ranking and the hard cap of 12, so TotalFound counts
+	// every concept that passes them; raising them can promote concepts that
+	// the cap would otherwise have cut.
+	MinOccurrences int `json:"minOccurrences,omitempty"`
+	MinFiles       int `json:"minFiles,omitempty"`
 }
 
+// Default concept thresholds for ListKeyConceptsOptions.
+const (
+	defaultConceptMinOccurrences = 2
+	defaultConceptMinFiles       = 1
+)
+
 // ListKeyConceptsResponse provides main ideas/concepts in the codebase.
 type ListKeyConceptsResponse struct {
 	AINavigationMeta
@@ -3109,6 +3982,7 @@ type ConceptV52 struct {
 	Files       []string    `json:"files,omitempty"`
 	Symbols     []string    `json:"symbols,omitempty"` // Sample symbol IDs
 	Description string      `json:"description,omitempty"`
+	Score       float64     `json:"score"` // Raw occurrences x file spread score
 	Ranking     *RankingV52 `json:"ranking"`
 }
 
@@ -3122,6 +3996,8 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 	if opts.Limit > 12 {
 		opts.Limit = 12 // Hard cap per v5.2 spec
 	}
+	opts.MinOccurrences = max(opts.MinOccurrences, defaultConceptMinOccurrences)
+	opts.MinFiles = max(opts.MinFiles, defaultConceptMinFiles)
 
 	var confidenceBasis []ConfidenceBasisItem
 	var limitations []string
@@ -3134,6 +4010,10 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 	// 4. Rank by frequency and spread
 
 	conceptCounts := make(map[string]*conceptData)
+	generated := newGeneratedFileFilter(e.repoRoot)
+	skipFile := func(relPath string) bool {
+		return opts.ExcludeGenerated && generated.isGenerated(relPath)
+	}
 
 	// Get symbols from SCIP if available
 	if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
@@ -3152,6 +4032,10 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 
 			if results != nil {
 				for _, sym := range results.Symbols {
+					if skipFile(sym.Location.Path) {
+						continue
+					}
+
 					// Extract concept from symbol name
 					conceptName := extractConcept(sym.Name)
 					if conceptName == "" {
@@ -3183,6 +4067,10 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 
 		if funcResults != nil {
 			for _, sym := range funcResults.Symbols {
+				if skipFile(sym.Location.Path) {
+					continue
+				}
+
 				conceptName := extractConcept(sym.Name)
 				if conceptName == "" {
 					continue
@@ -3208,13 +4096,13 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 		limitations = append(limitations, "SCIP index unavailable; concept extraction limited")
 
 		// Fallback: extract from file/directory names
+		walkFilter := e.newTreeWalkFilter(opts.RespectGitignore)
 		_ = filepath.WalkDir(e.repoRoot, func(path string, d os.DirEntry, err error) error {
 			if err != nil {
 				return err // Return error to allow WalkDir to handle permission issues
 			}
 			if d.IsDir() {
-				// Skip hidden and vendor directories
-				if strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor" || d.Name() == "node_modules" {
+				if walkFilter.skipDir(path, path == e.repoRoot) {
 					return filepath.SkipDir
 				}
 				return nil
@@ -3225,6 +4113,14 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 			if ext != ".go" && ext != ".ts" && ext != ".js" && ext != ".py" {
 				return nil
 			}
+			if walkFilter.skipFile(path) {
+				return nil
+			}
+
+			relPath, _ := filepath.Rel(e.repoRoot, path)
+			if skipFile(relPath) {
+				return nil
+			}
 
 			name := strings.TrimSuffix(filepath.Base(path), ext)
 			name = strings.TrimSuffix(name, "_test")
@@ -3235,8 +4131,6 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 				return nil
 			}
 
-			relPath, _ := filepath.Rel(e.repoRoot, path)
-
 			if _, exists := conceptCounts[conceptName]; !exists {
 				conceptCounts[conceptName] = &conceptData{
 					files:   make(map[string]bool),
@@ -3254,8 +4148,8 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 
 	// Convert to concepts and rank
 	for name, data := range conceptCounts {
-		// Skip if too few occurrences or single file
-		if data.count < 2 || len(data.files) < 1 {
+		// Skip concepts below the occurrence or file spread thresholds
+		if data.count < opts.MinOccurrences || len(data.files) < opts.MinFiles {
 			continue
 		}
 
@@ -3280,6 +4174,7 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 			Files:       files,
 			Symbols:     data.symbols,
 			Description: description,
+			Score:       score,
 			Ranking: NewRankingV52(score, map[string]interface{}{
 				"occurrences": data.count,
 				"fileSpread":  len(data.files),
@@ -3288,12 +4183,7 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 	}
 
 	// Sort by ranking score with deterministic tie-breaker
-	sort.Slice(concepts, func(i, j int) bool {
-		if concepts[i].Ranking.Score != concepts[j].Ranking.Score {
-			return concepts[i].Ranking.Score > concepts[j].Ranking.Score
-		}
-		return concepts[i].Name < concepts[j].Name
-	})
+	sortConcepts(concepts)
 
 	// Track total before limiting
 	totalFound := len(concepts)
@@ -3331,6 +4221,7 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 		RepoStateId:     repoState.RepoStateId,
 		RepoStateDirty:  repoState.Dirty,
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
+		Extra:           e.provenanceExtra(ctx),
 	}
 
 	// Add drilldowns
@@ -3339,6 +4230,7 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 			{
 				Label:          "View architecture",
 				Query:          "getArchitecture",
+				Tool:           "getArchitecture",
 				RelevanceScore: 0.85,
 			},
 		}
@@ -3346,6 +4238,8 @@ func (e *Engine) ListKeyConcepts(ctx context.Context, opts ListKeyConceptsOption
 			response.Drilldowns = append(response.Drilldowns, output.Drilldown{
 				Label:          fmt.Sprintf("Explore %s", concepts[0].Name),
 				Query:          fmt.Sprintf("explainSymbol %s", concepts[0].Symbols[0]),
+				Tool:           "explainSymbol",
+				Params:         map[string]interface{}{"symbolId": concepts[0].Symbols[0]},
 				RelevanceScore: 0.8,
 			})
 		}
@@ -3483,9 +4377,12 @@ func generateConceptDescription(name, category string, occurrences, fileCount in
 
 // RecentlyRelevantOptions controls recentlyRelevant behavior.
 type RecentlyRelevantOptions struct {
-	TimeWindow   *TimeWindowSelector `json:"timeWindow,omitempty"`
-	ModuleFilter string              `json:"moduleFilter,omitempty"`
-	Limit        int                 `json:"limit,omitempty"` // Max results (default 20)
+	TimeWindow *TimeWindowSelector `json:"timeWindow,omitempty"`
+	// CommitRange aggregates the commits in base..head (e.g. two release
+	// tags) instead of a time window. Mutually exclusive with TimeWindow.
+	CommitRange  *CommitRangeSelector `json:"commitRange,omitempty"`
+	ModuleFilter string               `json:"moduleFilter,omitempty"`
+	Limit        int                  `json:"limit,omitempty"` // Max results (default 20)
 }
 
 // RecentlyRelevantResponse provides recently active files/symbols.
@@ -3493,12 +4390,16 @@ type RecentlyRelevantResponse struct {
 	AINavigationMeta
 	Items           []RecentItem          `json:"items"`
 	TotalCount      int                   `json:"totalCount"`
-	TimeWindow      string                `json:"timeWindow"`
+	TimeWindow      string                `json:"timeWindow"`            // Window analyzed, or "base..head" for a commit range
+	CommitRange     *CommitRangeSelector  `json:"commitRange,omitempty"` // Set when a commit range was analyzed
 	Confidence      float64               `json:"confidence"`
 	ConfidenceBasis []ConfidenceBasisItem `json:"confidenceBasis"`
 	Limitations     []string              `json:"limitations,omitempty"`
 }
 
+// recentCommitLimit caps the commits recentlyRelevant aggregates.
+const recentCommitLimit = 500
+
 // RecentItem represents a recently relevant file or symbol.
 type RecentItem struct {
 	Type         string      `json:"type"` // file, symbol
@@ -3518,6 +4419,14 @@ func (e *Engine) RecentlyRelevant(ctx context.Context, opts RecentlyRelevantOpti
 	if opts.Limit <= 0 {
 		opts.Limit = 20
 	}
+	if opts.CommitRange != nil {
+		if opts.TimeWindow != nil {
+			return nil, fmt.Errorf("commitRange and timeWindow are mutually exclusive")
+		}
+		if opts.CommitRange.Base == "" || opts.CommitRange.Head == "" {
+			return nil, fmt.Errorf("commitRange requires both base and head")
+		}
+	}
 
 	var confidenceBasis []ConfidenceBasisItem
 	var limitations []string
@@ -3526,7 +4435,9 @@ func (e *Engine) RecentlyRelevant(ctx context.Context, opts RecentlyRelevantOpti
 	// Determine time window
 	var timeWindowStr string
 	var since string
-	if opts.TimeWindow != nil && opts.TimeWindow.Start != "" {
+	if opts.CommitRange != nil {
+		timeWindowStr = opts.CommitRange.Base + ".." + opts.CommitRange.Head
+	} else if opts.TimeWindow != nil && opts.TimeWindow.Start != "" {
 		since = opts.TimeWindow.Start
 		timeWindowStr = opts.TimeWindow.Start
 		if opts.TimeWindow.End != "" {
@@ -3547,11 +4458,20 @@ func (e *Engine) RecentlyRelevant(ctx context.Context, opts RecentlyRelevantOpti
 		Status:  "available",
 	})
 
-	// Get commits in time window
-	commits, err := e.gitAdapter.GetCommitsSinceDate(since, 500)
+	// Get commits in the range or time window
+	var commits []git.CommitInfo
+	var err error
+	if opts.CommitRange != nil {
+		commits, err = e.gitAdapter.GetCommitsInRange(opts.CommitRange.Base, opts.CommitRange.Head, recentCommitLimit)
+	} else {
+		commits, err = e.gitAdapter.GetCommitsSinceDate(since, recentCommitLimit)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commits: %w", err)
 	}
+	if len(commits) == recentCommitLimit {
+		limitations = append(limitations, fmt.Sprintf("Only the newest %d commits were analyzed", recentCommitLimit))
+	}
 
 	// Aggregate file changes
 	fileChanges := make(map[string]*recentFileData)
@@ -3620,12 +4540,7 @@ func (e *Engine) RecentlyRelevant(ctx context.Context, opts RecentlyRelevantOpti
 	}
 
 	// Sort by ranking score with deterministic tie-breaker
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].Ranking.Score != items[j].Ranking.Score {
-			return items[i].Ranking.Score > items[j].Ranking.Score
-		}
-		return items[i].Path < items[j].Path
-	})
+	sortRecentItems(items)
 
 	// Track total before limiting
 	totalCount := len(items)
@@ -3665,6 +4580,7 @@ func (e *Engine) RecentlyRelevant(ctx context.Context, opts RecentlyRelevantOpti
 		Items:           items,
 		TotalCount:      totalCount,
 		TimeWindow:      timeWindowStr,
+		CommitRange:     opts.CommitRange,
 		Confidence:      confidence,
 		ConfidenceBasis: confidenceBasis,
 		Limitations:     limitations,
@@ -3676,6 +4592,7 @@ func (e *Engine) RecentlyRelevant(ctx context.Context, opts RecentlyRelevantOpti
 		RepoStateId:     repoState.RepoStateId,
 		RepoStateDirty:  repoState.Dirty,
 		QueryDurationMs: time.Since(startTime).Milliseconds(),
+		Extra:           e.provenanceExtra(ctx),
 	}
 
 	// Add drilldowns
@@ -3684,16 +4601,20 @@ func (e *Engine) RecentlyRelevant(ctx context.Context, opts RecentlyRelevantOpti
 			{
 				Label:          fmt.Sprintf("Explain %s", items[0].Name),
 				Query:          fmt.Sprintf("explainFile %s", items[0].Path),
+				Tool:           "explainFile",
+				Params:         map[string]interface{}{"filePath": items[0].Path},
 				RelevanceScore: 0.9,
 			},
 			{
 				Label:          "View hotspots",
 				Query:          "getHotspots",
+				Tool:           "getHotspots",
 				RelevanceScore: 0.85,
 			},
 			{
 				Label:          "Summarize changes",
 				Query:          "summarizeDiff",
+				Tool:           "summarizeDiff",
 				RelevanceScore: 0.8,
 			},
 		}
@@ -2,7 +2,15 @@ package query
 
 import (
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+
+	"ckb/internal/backends/git"
 )
 
 // =============================================================================
@@ -502,6 +510,75 @@ func TestSummarizeDiff_TimeWindowSelector(t *testing.T) {
 	}
 }
 
+func TestSummarizeDiff_MergeBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+	root := engine.repoRoot
+
+	runOwnershipGit(t, root, "init", "-q", "-b", "main")
+	writeOwnershipFile(t, root, ".gitignore", ".ckb/\n")
+	writeOwnershipFile(t, root, "base.go", "package main\n")
+	runOwnershipGit(t, root, "add", "-A")
+	commitAs(t, root, "Alice", "alice@example.com")
+
+	runOwnershipGit(t, root, "checkout", "-q", "-b", "feature")
+	writeOwnershipFile(t, root, "feature.go", "package main\n\nfunc feature() {}\n")
+	runOwnershipGit(t, root, "add", "-A")
+	commitAs(t, root, "Alice", "alice@example.com")
+
+	// main moves on after the branch point
+	runOwnershipGit(t, root, "checkout", "-q", "main")
+	writeOwnershipFile(t, root, "mainline.go", "package main\n\nfunc mainline() {}\n")
+	runOwnershipGit(t, root, "add", "-A")
+	commitAs(t, root, "Bob", "bob@example.com")
+
+	// The engine was built before the repository existed
+	adapter, err := git.NewGitAdapter(engine.config, engine.logger)
+	if err != nil {
+		t.Fatalf("NewGitAdapter() error = %v", err)
+	}
+	engine.gitAdapter = adapter
+
+	changed := func(resp *SummarizeDiffResponse) []string {
+		var files []string
+		for _, f := range resp.ChangedFiles {
+			files = append(files, f.FilePath)
+		}
+		sort.Strings(files)
+		return files
+	}
+
+	twoDot, err := engine.SummarizeDiff(context.Background(), SummarizeDiffOptions{
+		CommitRange: &CommitRangeSelector{Base: "main", Head: "feature"},
+	})
+	if err != nil {
+		t.Fatalf("SummarizeDiff(base..head) error = %v", err)
+	}
+	if twoDot.Selector.Value != "main..feature" {
+		t.Errorf("selector = %q, want main..feature", twoDot.Selector.Value)
+	}
+	if got, want := changed(twoDot), []string{"feature.go", "mainline.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("base..head changed files = %v, want %v", got, want)
+	}
+
+	threeDot, err := engine.SummarizeDiff(context.Background(), SummarizeDiffOptions{
+		CommitRange: &CommitRangeSelector{Base: "main", Head: "feature", MergeBase: true},
+	})
+	if err != nil {
+		t.Fatalf("SummarizeDiff(base...head) error = %v", err)
+	}
+	if threeDot.Selector.Value != "main...feature" {
+		t.Errorf("selector = %q, want main...feature", threeDot.Selector.Value)
+	}
+	if got, want := changed(threeDot), []string{"feature.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("base...head changed files = %v, want %v", got, want)
+	}
+}
+
 // =============================================================================
 // GetHotspots Tests
 // =============================================================================
@@ -686,6 +763,50 @@ func TestListKeyConcepts_CustomLimit(t *testing.T) {
 	}
 }
 
+func TestListKeyConcepts_Thresholds(t *testing.T) {
+	t.Parallel()
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	// Without SCIP, concepts come from file names: Payment x3, Invoice x2, Ledger x1
+	for _, name := range []string{"Payment.go", "PaymentHandler.go", "PaymentService.go", "Invoice.go", "InvoiceClient.go", "Ledger.go"} {
+		if err := os.WriteFile(filepath.Join(engine.repoRoot, name), []byte("package billing\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		opts ListKeyConceptsOptions
+		want []string
+	}{
+		{"defaults", ListKeyConceptsOptions{}, []string{"Payment", "Invoice"}},
+		{"below defaults", ListKeyConceptsOptions{MinOccurrences: 1}, []string{"Payment", "Invoice"}},
+		{"min occurrences", ListKeyConceptsOptions{MinOccurrences: 3}, []string{"Payment"}},
+		{"min files", ListKeyConceptsOptions{MinFiles: 4}, nil},
+	}
+	for _, tt := range tests {
+		resp, err := engine.ListKeyConcepts(ctx, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: ListKeyConcepts: %v", tt.name, err)
+		}
+		var got []string
+		for _, c := range resp.Concepts {
+			got = append(got, c.Name)
+			if c.Score != float64(c.Occurrences*len(c.Files)) {
+				t.Errorf("%s: %s score = %v, want %d", tt.name, c.Name, c.Score, c.Occurrences*len(c.Files))
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: concepts = %v, want %v", tt.name, got, tt.want)
+		}
+		if resp.TotalFound != len(tt.want) {
+			t.Errorf("%s: TotalFound = %d, want %d", tt.name, resp.TotalFound, len(tt.want))
+		}
+	}
+}
+
 // =============================================================================
 // RecentlyRelevant Tests
 // =============================================================================
@@ -753,3 +874,60 @@ func TestRecentlyRelevant_CustomTimeWindow(t *testing.T) {
 		})
 	}
 }
+
+func TestFindPathsBFSCached(t *testing.T) {
+	// main -> a -> target, main -> b -> target, main -> a -> b, b -> c -> target
+	cache := &bfsCache{
+		callees: map[string][]string{
+			"main":   {"a", "b"},
+			"a":      {"target", "b"},
+			"b":      {"target", "c"},
+			"c":      {"target"},
+			"target": {},
+		},
+		symbols: map[string]*symbolCacheEntry{},
+	}
+	engine := &Engine{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		maxPaths int
+		want     [][]string
+	}{
+		{
+			name:     "first path only",
+			maxDepth: 5,
+			maxPaths: 1,
+			want:     [][]string{{"main", "a", "target"}},
+		},
+		{
+			name:     "shortest distinct paths",
+			maxDepth: 5,
+			maxPaths: 2,
+			want:     [][]string{{"main", "a", "target"}, {"main", "b", "target"}},
+		},
+		{
+			name:     "longer routes once short ones run out",
+			maxDepth: 5,
+			maxPaths: 3,
+			want:     [][]string{{"main", "a", "target"}, {"main", "b", "target"}, {"main", "a", "b", "target"}},
+		},
+		{
+			name:     "depth cap bounds path length",
+			maxDepth: 2,
+			maxPaths: 5,
+			want:     [][]string{{"main", "a", "target"}, {"main", "b", "target"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := engine.findPathsBFSCached(ctx, "main", "target", tt.maxDepth, tt.maxPaths, cache)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findPathsBFSCached() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package query
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"ckb/internal/backends/git"
+	"ckb/internal/backends/scip"
 )
 
 func TestComputeJustifyVerdict(t *testing.T) {
@@ -42,6 +47,25 @@ func TestComputeJustifyVerdict(t *testing.T) {
 		}
 	})
 
+	t.Run("investigates test-only export despite test callers", func(t *testing.T) {
+		facts := ExplainSymbolFacts{
+			Symbol: &SymbolInfo{TestOnlyExport: true},
+			Usage:  &ExplainUsage{CallerCount: 3},
+			Flags:  &ExplainSymbolFlags{IsExported: true},
+		}
+		verdict, confidence, reasoning := computeJustifyVerdict(facts)
+
+		if verdict != "investigate" {
+			t.Errorf("expected verdict 'investigate', got %q", verdict)
+		}
+		if confidence != 0.7 {
+			t.Errorf("expected confidence 0.7, got %f", confidence)
+		}
+		if reasoning == "" {
+			t.Error("expected non-empty reasoning")
+		}
+	})
+
 	t.Run("removes private symbol with no callers", func(t *testing.T) {
 		facts := ExplainSymbolFacts{
 			Usage: &ExplainUsage{CallerCount: 0},
@@ -161,6 +185,67 @@ func TestBuildExplainSummary(t *testing.T) {
 			t.Errorf("expected empty tldr for empty facts, got %q", summary.Tldr)
 		}
 	})
+
+	t.Run("lists callee names", func(t *testing.T) {
+		facts := ExplainSymbolFacts{
+			Usage:   &ExplainUsage{CallerCount: 1, ReferenceCount: 2, ModuleCount: 1},
+			Callees: []ExplainCallee{{Name: "validateInput"}, {Name: "persist"}, {Name: "notify"}},
+		}
+		summary := buildExplainSummary(facts)
+
+		want := "1 callers, 2 references across 1 modules; calls validateInput, persist and notify"
+		if summary.Usage != want {
+			t.Errorf("usage = %q, want %q", summary.Usage, want)
+		}
+	})
+}
+
+func TestCalleeSummary(t *testing.T) {
+	callees := func(names ...string) []ExplainCallee {
+		var out []ExplainCallee
+		for _, name := range names {
+			out = append(out, ExplainCallee{Name: name})
+		}
+		return out
+	}
+
+	tests := []struct {
+		callees []ExplainCallee
+		want    string
+	}{
+		{nil, ""},
+		{callees("persist"), "calls persist"},
+		{callees("persist", "notify"), "calls persist and notify"},
+		{callees("a", "b", "c", "d", "e"), "calls a, b, c and 2 more"},
+	}
+	for _, tt := range tests {
+		if got := calleeSummary(tt.callees); got != tt.want {
+			t.Errorf("calleeSummary(%d callees) = %q, want %q", len(tt.callees), got, tt.want)
+		}
+	}
+}
+
+func TestExplainCallees(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	// Nothing is indexed, so every lookup fails and names fall back
+	nodes := []*scip.CallGraphNode{
+		{SymbolID: "scip-go gomod example 1.0 `example/pkg`/persist().", Name: "persist", Kind: scip.KindFunction},
+		{SymbolID: "scip-go gomod example 1.0 `example/pkg`/Store#Save().", Kind: scip.KindMethod},
+		{SymbolID: "opaque"},
+		nil,
+	}
+	got := engine.explainCallees(context.Background(), nodes)
+
+	want := []ExplainCallee{
+		{SymbolId: nodes[0].SymbolID, Name: "persist", Kind: string(scip.KindFunction)},
+		{SymbolId: nodes[1].SymbolID, Name: symbolDisplayName(nodes[1].SymbolID), Kind: string(scip.KindMethod)},
+		{SymbolId: "opaque", Name: "opaque"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("explainCallees() =\n%+v\nwant\n%+v", got, want)
+	}
 }
 
 // =============================================================================
@@ -246,6 +331,10 @@ func TestSuggestTestPath(t *testing.T) {
 		{"src/utils.js", "javascript", "src/utils.test.js"},
 		{"lib/helper.py", "python", "lib/test_helper.py"},
 		{"foo.rs", "rust", ""},
+		{"lib/my_app/accounts.ex", "elixir", "test/my_app/accounts_test.exs"},
+		{"scripts/seed.exs", "elixir", "scripts/seed_test.exs"},
+		{"src/main/scala/com/acme/Parser.scala", "scala", "src/test/scala/com/acme/ParserSpec.scala"},
+		{"src/main.zig", "zig", ""},
 	}
 
 	for _, tc := range tests {
@@ -362,6 +451,139 @@ func TestClassifyRecency(t *testing.T) {
 	}
 }
 
+func TestSymbolDisplayName(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"scip-go gomod ckb v1 `ckb/internal/query`/Engine#GetSymbol().", "query.Engine.GetSymbol"},
+		{"pkg/query.Engine#TraceUsage().", "TraceUsage"},
+		{"plainName", "plainName"},
+		{"...", "..."},
+	}
+	for _, tc := range tests {
+		if got := symbolDisplayName(tc.id); got != tc.want {
+			t.Errorf("symbolDisplayName(%q) = %q, want %q", tc.id, got, tc.want)
+		}
+	}
+}
+
+func TestCallerPathNode(t *testing.T) {
+	id := "scip-go gomod ckb v1 `ckb/cmd/ckb`/runTrace()."
+
+	named := callerPathNode(id, "runTrace", nil)
+	if named.Name != "runTrace" || !named.Resolved {
+		t.Errorf("named caller = %+v, want name kept and resolved", named)
+	}
+
+	unnamed := callerPathNode(id, "", nil)
+	if unnamed.Name != "ckb.runTrace" || unnamed.Resolved {
+		t.Errorf("unnamed caller = %+v, want derived name and unresolved", unnamed)
+	}
+	if unnamed.Role != "entrypoint" || unnamed.SymbolId != id {
+		t.Errorf("unnamed caller = %+v, want entrypoint with original ID", unnamed)
+	}
+}
+
+func TestComputeChurnTrend(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 30)
+	day := func(d int) string { return start.AddDate(0, 0, d).Format(time.RFC3339) }
+
+	tests := []struct {
+		name    string
+		changes []string
+		want    string
+	}{
+		{"no changes", nil, ChurnTrendSteady},
+		{"single change", []string{day(25)}, ChurnTrendSteady},
+		{"only recent changes", []string{day(28), day(20)}, ChurnTrendRising},
+		{"more recent changes", []string{day(29), day(25), day(20), day(5)}, ChurnTrendRising},
+		{"more older changes", []string{day(20), day(10), day(5), day(1)}, ChurnTrendFalling},
+		{"balanced", []string{day(25), day(20), day(10), day(5)}, ChurnTrendSteady},
+		{"outside window ignored", []string{day(-10), day(-5), day(20), day(25)}, ChurnTrendRising},
+		{"unparseable ignored", []string{"garbage", day(20), day(5)}, ChurnTrendSteady},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := computeChurnTrend(tc.changes, start, end); got != tc.want {
+				t.Errorf("computeChurnTrend() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHotspotWindow(t *testing.T) {
+	start, end, ok := hotspotWindow("2024-01-01", &TimeWindowSelector{Start: "2024-01-01", End: "2024-02-01"})
+	if !ok || start.Format("2006-01-02") != "2024-01-01" || end.Format("2006-01-02") != "2024-02-01" {
+		t.Errorf("hotspotWindow() = %v, %v, %v", start, end, ok)
+	}
+	if _, _, ok := hotspotWindow("2 weeks ago", nil); ok {
+		t.Error("expected relative since to have no window")
+	}
+	if _, _, ok := hotspotWindow("2024-02-01", &TimeWindowSelector{End: "2024-01-01"}); ok {
+		t.Error("expected an inverted window to be rejected")
+	}
+}
+
+func TestTopAuthorChurn(t *testing.T) {
+	counts := []git.AuthorCommitCount{
+		{Author: "alice", Commits: 5},
+		{Author: "bob", Commits: 3},
+		{Author: "carol", Commits: 1},
+		{Author: "dave", Commits: 1},
+	}
+
+	got := topAuthorChurn(counts, 2)
+	want := []AuthorChurn{
+		{Author: "alice", Commits: 5, Percentage: 50},
+		{Author: "bob", Commits: 3, Percentage: 30},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("topAuthorChurn() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("author %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := topAuthorChurn(nil, 5); got != nil {
+		t.Errorf("topAuthorChurn(nil) = %+v, want nil", got)
+	}
+}
+
+func TestHotspotCoupling(t *testing.T) {
+	tests := []struct {
+		name           string
+		coupling       scip.FileCoupling
+		wantScore      float64
+		wantMultiplier float64
+	}{
+		{"isolated", scip.FileCoupling{}, 0, 1.0},
+		{"partial", scip.FileCoupling{Dependents: 3, Dependencies: 2}, 0.25, 1.25},
+		{"saturated", scip.FileCoupling{Dependents: 40, Dependencies: 10}, 1.0, 2.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hotspotCoupling(tt.coupling)
+			if got.DependentCount != tt.coupling.Dependents || got.DependencyCount != tt.coupling.Dependencies {
+				t.Errorf("counts = %d/%d, want %d/%d", got.DependentCount, got.DependencyCount, tt.coupling.Dependents, tt.coupling.Dependencies)
+			}
+			if !floatEqual(got.Score, tt.wantScore) {
+				t.Errorf("score = %v, want %v", got.Score, tt.wantScore)
+			}
+			if m := couplingMultiplier(got); !floatEqual(m, tt.wantMultiplier) {
+				t.Errorf("multiplier = %v, want %v", m, tt.wantMultiplier)
+			}
+		})
+	}
+
+	if m := couplingMultiplier(nil); m != 1.0 {
+		t.Errorf("couplingMultiplier(nil) = %v, want 1.0", m)
+	}
+}
+
 func TestClassifyHotspotRisk(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -405,6 +627,18 @@ func TestClassifyHotspotRisk(t *testing.T) {
 			role:     "test",
 			expected: "medium",
 		},
+		{
+			name:     "oscillating at low churn",
+			churn:    git.ChurnMetrics{ChangeCount: 4, AuthorCount: 1, ChurnPattern: git.ChurnPatternOscillating},
+			role:     "unknown",
+			expected: "medium",
+		},
+		{
+			name:     "oscillating at moderate churn",
+			churn:    git.ChurnMetrics{ChangeCount: 12, AuthorCount: 2, ChurnPattern: git.ChurnPatternOscillating},
+			role:     "unknown",
+			expected: "high",
+		},
 	}
 
 	for _, tc := range tests {
@@ -755,6 +989,12 @@ func TestDetectLanguage(t *testing.T) {
 		{"file.cpp", "cpp"},
 		{"file.cs", "csharp"},
 		{"file.php", "php"},
+		{"file.scala", "scala"},
+		{"script.sc", "scala"},
+		{"file.ex", "elixir"},
+		{"file_test.exs", "elixir"},
+		{"file.zig", "zig"},
+		{"FILE.ZIG", "zig"},
 		{"file.txt", ""},
 		{"file", ""},
 	}
@@ -808,6 +1048,52 @@ func TestTailTimestamp(t *testing.T) {
 	}
 }
 
+func TestMergeFileHistories(t *testing.T) {
+	if got := mergeFileHistories(nil, 0); got != nil {
+		t.Errorf("expected nil for no histories, got %+v", got)
+	}
+
+	histories := []*git.FileHistory{
+		{
+			FilePath:     "Widget.cs",
+			CommitCount:  2,
+			LastModified: "2024-03-01T10:00:00Z",
+			Commits: []git.CommitInfo{
+				{Hash: "c3", Timestamp: "2024-03-01T10:00:00Z"},
+				{Hash: "c1", Timestamp: "2024-01-01T10:00:00Z"},
+			},
+		},
+		{
+			FilePath:     "Widget.Render.cs",
+			CommitCount:  2,
+			LastModified: "2024-04-01T10:00:00Z",
+			Commits: []git.CommitInfo{
+				{Hash: "c4", Timestamp: "2024-04-01T10:00:00Z"},
+				{Hash: "c3", Timestamp: "2024-03-01T10:00:00Z"},
+			},
+		},
+	}
+
+	got := mergeFileHistories(histories, 0)
+	if got.CommitCount != 3 {
+		t.Errorf("CommitCount = %d, want 3 (shared commit counted once)", got.CommitCount)
+	}
+	if got.CreatedAt != "2024-01-01T10:00:00Z" {
+		t.Errorf("CreatedAt = %q, want earliest commit", got.CreatedAt)
+	}
+	if got.LastModifiedAt != "2024-04-01T10:00:00Z" {
+		t.Errorf("LastModifiedAt = %q, want latest commit", got.LastModifiedAt)
+	}
+
+	// The full count wins over the capped histories, for one file or several
+	for _, hs := range [][]*git.FileHistory{histories, histories[:1]} {
+		got := mergeFileHistories(hs, 57)
+		if got.CommitCount != 57 || got.CommitFrequency != "volatile" {
+			t.Errorf("%d files: count/frequency = %d/%s, want 57/volatile", len(hs), got.CommitCount, got.CommitFrequency)
+		}
+	}
+}
+
 func TestComputeExplainFileConfidence(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1017,6 +1303,15 @@ func TestIsTestFilePath(t *testing.T) {
 		{"src/Button.test.tsx", true},
 		{"src/test/fixtures/data.go", true},
 		{"src/tests/unit/api.go", true},
+		{"test/my_app/accounts_test.exs", true},
+		{"src/test/scala/ParserSpec.scala", true},
+		{"core/ParserSpec.scala", true},
+		{"core/LexerTest.scala", true},
+		{"core/RoundTripSuite.scala", true},
+		{"src/tests.zig", true},
+		{"lib/my_app/accounts.ex", false},
+		{"core/Latest.scala", false},
+		{"src/main.zig", false},
 		{"internal/query/engine.go", false},
 		{"src/Button.tsx", false},
 		{"main.go", false},
@@ -1032,6 +1327,29 @@ func TestIsTestFilePath(t *testing.T) {
 	}
 }
 
+func TestIsGeneratedFilePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"api/v1/service.pb.go", true},
+		{"internal/mocks/store_generated.go", true},
+		{"src/schema.generated.ts", true},
+		{"generated/client.go", true},
+		{"pkg/generated/types.go", true},
+		{"internal/query/engine.go", false},
+		{"internal/query/generator.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isGeneratedFilePath(tt.path); got != tt.expected {
+				t.Errorf("isGeneratedFilePath(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestGenerateConceptDescription(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1054,3 +1372,98 @@ func TestGenerateConceptDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchEntrypointHint(t *testing.T) {
+	hints := []string{"cmd", "apps/*", "services/*/main", "functions"}
+	tests := []struct {
+		path     string
+		wantHint string
+		wantOK   bool
+	}{
+		{"cmd/ckb/main.go", "cmd", true},
+		{"tools/cmd/gen/main.go", "cmd", true},
+		{"apps/web/server.go", "apps/*", true},
+		{"services/billing/main.go", "services/*/main", true},
+		{"services/billing/main/app.go", "services/*/main", true},
+		{"services/billing/handler.go", "", false},
+		{"functions/resize/handler.ts", "functions", true},
+		{"internal/commands/run.go", "", false},
+	}
+	for _, tt := range tests {
+		hint, ok := matchEntrypointHint(tt.path, hints)
+		if hint != tt.wantHint || ok != tt.wantOK {
+			t.Errorf("matchEntrypointHint(%q) = %q, %v; want %q, %v", tt.path, hint, ok, tt.wantHint, tt.wantOK)
+		}
+	}
+
+	if got := withPathHint("naming", "cmd"); got != "naming+path-hint:cmd" {
+		t.Errorf("withPathHint = %q", got)
+	}
+}
+
+func TestHighFanOutWarning(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if got := engine.entrypointFanOutLimit(0); got != 100 {
+		t.Errorf("default limit = %d, want 100", got)
+	}
+	if got := engine.entrypointFanOutLimit(40); got != 40 {
+		t.Errorf("override limit = %d, want 40", got)
+	}
+
+	flagged := func(name string, fanOut int) EntrypointV52 {
+		return EntrypointV52{Name: name, FanOut: fanOut, Ranking: NewRankingV52(200, map[string]interface{}{"highFanOut": true})}
+	}
+	entrypoints := []EntrypointV52{
+		flagged("Orchestrate", 320),
+		{Name: "Serve", FanOut: 12, Ranking: NewRankingV52(104, map[string]interface{}{})},
+		flagged("main", 150),
+	}
+	want := "2 entrypoints call more than 100 functions and may be orchestration hotspots: Orchestrate (320), main (150)"
+	if got := highFanOutWarning(entrypoints, 100); got != want {
+		t.Errorf("warning = %q, want %q", got, want)
+	}
+	if got := highFanOutWarning(entrypoints[1:2], 100); got != "" {
+		t.Errorf("expected no warning, got %q", got)
+	}
+
+	var many []EntrypointV52
+	for i := 0; i < 7; i++ {
+		many = append(many, flagged("Run", 200))
+	}
+	if got := highFanOutWarning(many, 100); !strings.HasPrefix(got, "7 entrypoints") || !strings.HasSuffix(got, ", ...") {
+		t.Errorf("warning for many = %q", got)
+	}
+}
+
+func TestExcludeDiffStats(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	excludes, err := compileExcludePaths([]string{"vendor/**", "**/*.pb.go", " "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(excludes) != 2 {
+		t.Fatalf("expected blank patterns to be skipped, got %d matchers", len(excludes))
+	}
+
+	stats := []git.DiffStats{
+		{FilePath: "internal/api/server.go"},
+		{FilePath: "vendor/github.com/x/y.go"},
+		{FilePath: "proto/gen/service.pb.go"},
+		{FilePath: "cmd/main.go"},
+	}
+	kept, excluded := engine.excludeDiffStats(stats, excludes)
+	if excluded != 2 {
+		t.Errorf("excluded = %d, want 2", excluded)
+	}
+	if len(kept) != 2 || kept[0].FilePath != "internal/api/server.go" || kept[1].FilePath != "cmd/main.go" {
+		t.Errorf("kept = %+v", kept)
+	}
+
+	if _, err := compileExcludePaths([]string{"re:("}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
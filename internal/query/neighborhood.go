@@ -0,0 +1,342 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ckb/internal/output"
+)
+
+// Neighborhood facets. The symbol's definition (location, signature, docs)
+// is always returned since every other facet is derived from it.
+const (
+	FacetCallers   = "callers"
+	FacetCallees   = "callees"
+	FacetTests     = "tests"
+	FacetOwners    = "owners"
+	FacetDecisions = "decisions"
+)
+
+// Facet outcomes
+const (
+	FacetOK       = "ok"
+	FacetDegraded = "degraded" // Returned, but from partial or fallback data
+	FacetTimeout  = "timeout"  // Didn't finish before the deadline
+	FacetFailed   = "failed"
+)
+
+const (
+	defaultNeighborhoodTimeout = 10 * time.Second
+
+	// maxNeighborhoodItems bounds each list facet.
+	maxNeighborhoodItems = 20
+
+	// maxNeighborhoodRefs bounds the reference scan used to find tests.
+	maxNeighborhoodRefs = 500
+)
+
+// AllNeighborhoodFacets lists the facets fetched when none are requested.
+var AllNeighborhoodFacets = []string{FacetCallers, FacetCallees, FacetTests, FacetOwners, FacetDecisions}
+
+// GetSymbolNeighborhoodOptions contains options for getSymbolNeighborhood.
+type GetSymbolNeighborhoodOptions struct {
+	SymbolId string
+	Include  []string      // Facets to fetch; empty fetches all
+	Timeout  time.Duration // Deadline for all facets together; 0 = 10s
+}
+
+// GetSymbolNeighborhoodResponse is everything near a symbol in one object.
+type GetSymbolNeighborhoodResponse struct {
+	Symbol    *SymbolInfo             `json:"symbol"`
+	Callers   []CallGraphNode         `json:"callers,omitempty"`
+	Callees   []CallGraphNode         `json:"callees,omitempty"`
+	Tests     []ReferenceInfo         `json:"tests,omitempty"`
+	Owners    []OwnerInfo             `json:"owners,omitempty"`
+	Decisions []RelatedDecision       `json:"decisions,omitempty"`
+	Facets    map[string]FacetOutcome `json:"facets"`
+
+	Provenance *Provenance        `json:"provenance"`
+	Drilldowns []output.Drilldown `json:"drilldowns,omitempty"`
+}
+
+// FacetOutcome reports how a requested facet was fetched.
+type FacetOutcome struct {
+	Status    string `json:"status"` // ok, degraded, timeout, failed
+	Count     int    `json:"count"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// facetResult is what one facet fetch hands back to the collector.
+type facetResult struct {
+	name    string
+	outcome FacetOutcome
+	apply   func(*GetSymbolNeighborhoodResponse)
+}
+
+// GetSymbolNeighborhood gathers a symbol's definition, callers, callees,
+// tests, owners and related decisions in one call. The symbol is resolved
+// once and its stable ID reused by every facet, which run concurrently
+// against the engine's shared repo-state and query caches. Facets still
+// running at the deadline are reported as timed out rather than failing
+// the whole call.
+func (e *Engine) GetSymbolNeighborhood(ctx context.Context, opts GetSymbolNeighborhoodOptions) (*GetSymbolNeighborhoodResponse, error) {
+	startTime := time.Now()
+
+	facets, err := normalizeFacets(opts.Include)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultNeighborhoodTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	symbolResp, err := e.GetSymbol(ctx, GetSymbolOptions{SymbolId: opts.SymbolId, RepoStateMode: "full"})
+	if err != nil {
+		return nil, err
+	}
+	if symbolResp.Symbol == nil {
+		return nil, fmt.Errorf("symbol %q not found", opts.SymbolId)
+	}
+	sym := symbolResp.Symbol
+
+	resp := &GetSymbolNeighborhoodResponse{
+		Symbol: sym,
+		Facets: make(map[string]FacetOutcome, len(facets)),
+	}
+
+	results := make(chan facetResult, len(facets))
+	for _, facet := range facets {
+		go func(facet string) {
+			results <- e.fetchFacet(ctx, facet, sym)
+		}(facet)
+	}
+
+	pending := make(map[string]bool, len(facets))
+	for _, facet := range facets {
+		pending[facet] = true
+	}
+collect:
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.name)
+			resp.Facets[r.name] = r.outcome
+			if r.apply != nil {
+				r.apply(resp)
+			}
+		case <-ctx.Done():
+			break collect
+		}
+	}
+	for facet := range pending {
+		resp.Facets[facet] = FacetOutcome{Status: FacetTimeout, Reason: fmt.Sprintf("not finished within %s", opts.Timeout)}
+	}
+
+	resp.Provenance = symbolResp.Provenance
+	if resp.Provenance != nil {
+		resp.Provenance.QueryDurationMs = time.Since(startTime).Milliseconds()
+		for _, facet := range facets {
+			if outcome := resp.Facets[facet]; outcome.Status != FacetOK {
+				e.addWarning(resp.Provenance, output.SeverityWarning, WarnAnalysisLimited,
+					fmt.Sprintf("%s facet %s: %s", facet, outcome.Status, outcome.Reason))
+			}
+		}
+	}
+	resp.Drilldowns = neighborhoodDrilldowns(sym, resp.Facets)
+
+	return resp, nil
+}
+
+// normalizeFacets validates the requested facets, dropping duplicates and
+// defaulting to all of them.
+func normalizeFacets(include []string) ([]string, error) {
+	if len(include) == 0 {
+		return AllNeighborhoodFacets, nil
+	}
+	valid := make(map[string]bool, len(AllNeighborhoodFacets))
+	for _, f := range AllNeighborhoodFacets {
+		valid[f] = true
+	}
+	seen := make(map[string]bool)
+	var facets []string
+	for _, f := range include {
+		f = strings.TrimSpace(f)
+		if f == "" || f == "definition" || seen[f] {
+			continue
+		}
+		if !valid[f] {
+			return nil, fmt.Errorf("unknown facet %q (available: %s)", f, strings.Join(AllNeighborhoodFacets, ", "))
+		}
+		seen[f] = true
+		facets = append(facets, f)
+	}
+	return facets, nil
+}
+
+func (e *Engine) fetchFacet(ctx context.Context, facet string, sym *SymbolInfo) facetResult {
+	switch facet {
+	case FacetCallers, FacetCallees:
+		return e.callGraphFacet(ctx, facet, sym)
+	case FacetTests:
+		return e.testsFacet(ctx, sym)
+	case FacetOwners:
+		return e.ownersFacet(ctx, sym)
+	case FacetDecisions:
+		return e.decisionsFacet(sym)
+	}
+	return facetResult{name: facet, outcome: FacetOutcome{Status: FacetFailed, Reason: "unknown facet"}}
+}
+
+func (e *Engine) callGraphFacet(ctx context.Context, facet string, sym *SymbolInfo) facetResult {
+	result := facetResult{name: facet}
+	role := "caller"
+	if facet == FacetCallees {
+		role = "callee"
+	}
+
+	graph, err := e.GetCallGraph(ctx, CallGraphOptions{SymbolId: sym.StableId, Direction: facet, Depth: 1})
+	if err != nil {
+		result.outcome = FacetOutcome{Status: FacetFailed, Reason: err.Error()}
+		return result
+	}
+
+	var nodes []CallGraphNode
+	for _, n := range graph.Nodes {
+		if n.Role == role {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Score > nodes[j].Score })
+
+	result.outcome = facetOutcome(len(nodes), graph.Provenance)
+	if len(nodes) > maxNeighborhoodItems {
+		nodes = nodes[:maxNeighborhoodItems]
+		result.outcome.Truncated = true
+	}
+	result.apply = func(r *GetSymbolNeighborhoodResponse) {
+		if facet == FacetCallers {
+			r.Callers = nodes
+		} else {
+			r.Callees = nodes
+		}
+	}
+	return result
+}
+
+func (e *Engine) testsFacet(ctx context.Context, sym *SymbolInfo) facetResult {
+	result := facetResult{name: FacetTests}
+
+	refs, err := e.FindReferences(ctx, FindReferencesOptions{
+		SymbolId:     sym.StableId,
+		IncludeTests: true,
+		Limit:        maxNeighborhoodRefs,
+	})
+	if err != nil {
+		result.outcome = FacetOutcome{Status: FacetFailed, Reason: err.Error()}
+		return result
+	}
+
+	var tests []ReferenceInfo
+	for _, ref := range refs.References {
+		if ref.IsTest {
+			tests = append(tests, ref)
+		}
+	}
+
+	result.outcome = facetOutcome(len(tests), refs.Provenance)
+	if refs.Truncated {
+		result.outcome.Truncated = true
+	}
+	if len(tests) > maxNeighborhoodItems {
+		tests = tests[:maxNeighborhoodItems]
+		result.outcome.Truncated = true
+	}
+	result.apply = func(r *GetSymbolNeighborhoodResponse) { r.Tests = tests }
+	return result
+}
+
+func (e *Engine) ownersFacet(ctx context.Context, sym *SymbolInfo) facetResult {
+	result := facetResult{name: FacetOwners}
+	if sym.Location == nil || sym.Location.FileId == "" {
+		result.outcome = FacetOutcome{Status: FacetDegraded, Reason: "symbol has no source location"}
+		return result
+	}
+
+	ownership, err := e.GetOwnership(ctx, GetOwnershipOptions{Path: sym.Location.FileId})
+	if err != nil {
+		result.outcome = FacetOutcome{Status: FacetFailed, Reason: err.Error()}
+		return result
+	}
+
+	owners := ownership.Owners
+	result.outcome = facetOutcome(len(owners), ownership.Provenance)
+	if len(owners) > maxNeighborhoodItems {
+		owners = owners[:maxNeighborhoodItems]
+		result.outcome.Truncated = true
+	}
+	result.apply = func(r *GetSymbolNeighborhoodResponse) { r.Owners = owners }
+	return result
+}
+
+// decisionsFacet returns the module's decisions, marking those that name
+// the symbol or its file as symbol-scoped.
+func (e *Engine) decisionsFacet(sym *SymbolInfo) facetResult {
+	decisions := e.getRelatedDecisions(sym.ModuleId)
+	e.linkDecisionsToImpact(decisions, []ImpactItem{{
+		StableId: sym.StableId,
+		Name:     sym.Name,
+		Location: sym.Location,
+	}})
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].Scope == DecisionScopeSymbol && decisions[j].Scope != DecisionScopeSymbol
+	})
+	return facetResult{
+		name:    FacetDecisions,
+		outcome: FacetOutcome{Status: FacetOK, Count: len(decisions)},
+		apply:   func(r *GetSymbolNeighborhoodResponse) { r.Decisions = decisions },
+	}
+}
+
+// facetOutcome rates a facet by the completeness of the call that fed it.
+func facetOutcome(count int, p *Provenance) FacetOutcome {
+	outcome := FacetOutcome{Status: FacetOK, Count: count}
+	if p != nil && p.Completeness.Score < 1.0 {
+		outcome.Status = FacetDegraded
+		outcome.Reason = p.Completeness.Reason
+	}
+	return outcome
+}
+
+// neighborhoodDrilldowns points at the full analyses behind truncated or
+// unfinished facets.
+func neighborhoodDrilldowns(sym *SymbolInfo, facets map[string]FacetOutcome) []output.Drilldown {
+	var drilldowns []output.Drilldown
+	add := func(label, tool string, params map[string]interface{}, score float64) {
+		drilldowns = append(drilldowns, output.Drilldown{
+			Label:          label,
+			Query:          fmt.Sprintf("%s %s", tool, sym.StableId),
+			Tool:           tool,
+			Params:         params,
+			RelevanceScore: score,
+		})
+	}
+
+	needsMore := func(facet string) bool {
+		outcome, ok := facets[facet]
+		return ok && (outcome.Truncated || outcome.Status == FacetTimeout)
+	}
+	if needsMore(FacetCallers) || needsMore(FacetCallees) {
+		add("Full call graph", "getCallGraph", map[string]interface{}{"symbolId": sym.StableId, "depth": 2}, 0.8)
+	}
+	if needsMore(FacetTests) {
+		add("All references, including tests", "findReferences", map[string]interface{}{"symbolId": sym.StableId, "includeTests": true}, 0.7)
+	}
+	add("Impact of changing "+sym.Name, "analyzeImpact", map[string]interface{}{"symbolId": sym.StableId}, 0.6)
+	return drilldowns
+}
@@ -0,0 +1,74 @@
+package query
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeFacets(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		want    []string
+		wantErr bool
+	}{
+		{"default", nil, AllNeighborhoodFacets, false},
+		{"subset keeps order", []string{"tests", "callers"}, []string{"tests", "callers"}, false},
+		{"duplicates and definition dropped", []string{"owners", "definition", " owners "}, []string{"owners"}, false},
+		{"unknown facet", []string{"callers", "history"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeFacets(tt.include)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeFacets(%v) error = %v, wantErr %v", tt.include, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeFacets(%v) = %v, want %v", tt.include, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFacetOutcome(t *testing.T) {
+	if got := facetOutcome(3, nil); got.Status != FacetOK || got.Count != 3 {
+		t.Errorf("facetOutcome without provenance = %+v", got)
+	}
+
+	partial := &Provenance{Completeness: CompletenessInfo{Score: 0.5, Reason: "best-effort-lsp"}}
+	got := facetOutcome(2, partial)
+	if got.Status != FacetDegraded || got.Reason != "best-effort-lsp" {
+		t.Errorf("facetOutcome with partial completeness = %+v", got)
+	}
+}
+
+func TestNeighborhoodDrilldowns(t *testing.T) {
+	sym := &SymbolInfo{StableId: "sym-1", Name: "Handle"}
+
+	drilldowns := neighborhoodDrilldowns(sym, map[string]FacetOutcome{
+		FacetCallers: {Status: FacetOK, Truncated: true},
+		FacetTests:   {Status: FacetOK},
+	})
+	var tools []string
+	for _, d := range drilldowns {
+		tools = append(tools, d.Tool)
+	}
+	if want := []string{"getCallGraph", "analyzeImpact"}; !reflect.DeepEqual(tools, want) {
+		t.Errorf("drilldown tools = %v, want %v", tools, want)
+	}
+}
+
+func TestGetSymbolNeighborhoodRejectsUnknownFacet(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	_, err := engine.GetSymbolNeighborhood(context.Background(), GetSymbolNeighborhoodOptions{
+		SymbolId: "ckb:repo:sym:missing",
+		Include:  []string{"history"},
+	})
+	if err == nil {
+		t.Error("expected error for an unknown facet")
+	}
+}
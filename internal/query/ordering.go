@@ -0,0 +1,82 @@
+package query
+
+import (
+	"math"
+	"sort"
+)
+
+// scoreEpsilon is the tolerance for comparing computed scores. Output rounds
+// floats to 6 decimals (output.RoundFloat), so values closer than that print
+// the same and must sort as ties.
+const scoreEpsilon = 1e-6
+
+// floatEqual reports whether two scores are equal up to rounding noise.
+// Comparators check it before ordering by score so near-equal values fall
+// through to their string tie-breaker instead of ordering on noise.
+func floatEqual(a, b float64) bool {
+	return math.Abs(a-b) < scoreEpsilon
+}
+
+// sortEntrypoints orders by ranking score, then name, then symbol ID.
+func sortEntrypoints(entrypoints []EntrypointV52) {
+	sort.Slice(entrypoints, func(i, j int) bool {
+		a, b := entrypoints[i], entrypoints[j]
+		if !floatEqual(a.Ranking.Score, b.Ranking.Score) {
+			return a.Ranking.Score > b.Ranking.Score
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.SymbolId < b.SymbolId
+	})
+}
+
+// sortHotspots orders by ranking score, then file path.
+func sortHotspots(hotspots []HotspotV52) {
+	sort.Slice(hotspots, func(i, j int) bool {
+		if !floatEqual(hotspots[i].Ranking.Score, hotspots[j].Ranking.Score) {
+			return hotspots[i].Ranking.Score > hotspots[j].Ranking.Score
+		}
+		return hotspots[i].FilePath < hotspots[j].FilePath
+	})
+}
+
+// sortConcepts orders by ranking score, then name.
+func sortConcepts(concepts []ConceptV52) {
+	sort.Slice(concepts, func(i, j int) bool {
+		if !floatEqual(concepts[i].Ranking.Score, concepts[j].Ranking.Score) {
+			return concepts[i].Ranking.Score > concepts[j].Ranking.Score
+		}
+		return concepts[i].Name < concepts[j].Name
+	})
+}
+
+// sortRecentItems orders by ranking score, then path.
+func sortRecentItems(items []RecentItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if !floatEqual(items[i].Ranking.Score, items[j].Ranking.Score) {
+			return items[i].Ranking.Score > items[j].Ranking.Score
+		}
+		return items[i].Path < items[j].Path
+	})
+}
+
+// sortUsagePaths orders by ranking score, then shorter paths, then the
+// symbol IDs along the path.
+func sortUsagePaths(paths []UsagePath) {
+	sort.Slice(paths, func(i, j int) bool {
+		a, b := paths[i], paths[j]
+		if !floatEqual(a.Ranking.Score, b.Ranking.Score) {
+			return a.Ranking.Score > b.Ranking.Score
+		}
+		if len(a.Nodes) != len(b.Nodes) {
+			return len(a.Nodes) < len(b.Nodes)
+		}
+		for k := range a.Nodes {
+			if a.Nodes[k].SymbolId != b.Nodes[k].SymbolId {
+				return a.Nodes[k].SymbolId < b.Nodes[k].SymbolId
+			}
+		}
+		return false
+	})
+}
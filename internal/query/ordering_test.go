@@ -0,0 +1,115 @@
+package query
+
+import "testing"
+
+// noisy and exact print the same at 6 decimals but differ as float64s;
+// noisy is the larger, so ordering on raw floats would put it first.
+var (
+	noisy = 0.1 + 0.2
+	exact = 0.3
+)
+
+func rank(score float64) *RankingV52 {
+	return &RankingV52{Score: score}
+}
+
+func TestFloatEqual(t *testing.T) {
+	tests := []struct {
+		a, b float64
+		want bool
+	}{
+		{noisy, exact, true},
+		{0.5, 0.5, true},
+		{0.5, 0.5000004, true},
+		{0.5, 0.500002, false},
+		{0.9, 0.3, false},
+	}
+	for _, tt := range tests {
+		if got := floatEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("floatEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortEntrypointsNearEqualScores(t *testing.T) {
+	for _, reversed := range []bool{false, true} {
+		eps := []EntrypointV52{
+			{Name: "zeta", SymbolId: "z", Ranking: rank(noisy)},
+			{Name: "alpha", SymbolId: "a", Ranking: rank(exact)},
+			{Name: "top", SymbolId: "t", Ranking: rank(0.9)},
+		}
+		if reversed {
+			eps[0], eps[1] = eps[1], eps[0]
+		}
+		sortEntrypoints(eps)
+		if eps[0].Name != "top" || eps[1].Name != "alpha" || eps[2].Name != "zeta" {
+			t.Errorf("reversed=%v: got order %s, %s, %s", reversed, eps[0].Name, eps[1].Name, eps[2].Name)
+		}
+	}
+}
+
+func TestSortHotspotsNearEqualScores(t *testing.T) {
+	for _, reversed := range []bool{false, true} {
+		hs := []HotspotV52{
+			{FilePath: "b.go", Ranking: rank(noisy)},
+			{FilePath: "a.go", Ranking: rank(exact)},
+		}
+		if reversed {
+			hs[0], hs[1] = hs[1], hs[0]
+		}
+		sortHotspots(hs)
+		if hs[0].FilePath != "a.go" {
+			t.Errorf("reversed=%v: got %s first, want a.go", reversed, hs[0].FilePath)
+		}
+	}
+}
+
+func TestSortConceptsNearEqualScores(t *testing.T) {
+	for _, reversed := range []bool{false, true} {
+		cs := []ConceptV52{
+			{Name: "Session", Ranking: rank(noisy)},
+			{Name: "Auth", Ranking: rank(exact)},
+		}
+		if reversed {
+			cs[0], cs[1] = cs[1], cs[0]
+		}
+		sortConcepts(cs)
+		if cs[0].Name != "Auth" {
+			t.Errorf("reversed=%v: got %s first, want Auth", reversed, cs[0].Name)
+		}
+	}
+}
+
+func TestSortRecentItemsNearEqualScores(t *testing.T) {
+	for _, reversed := range []bool{false, true} {
+		items := []RecentItem{
+			{Path: "pkg/z.go", Ranking: rank(noisy)},
+			{Path: "pkg/a.go", Ranking: rank(exact)},
+		}
+		if reversed {
+			items[0], items[1] = items[1], items[0]
+		}
+		sortRecentItems(items)
+		if items[0].Path != "pkg/a.go" {
+			t.Errorf("reversed=%v: got %s first, want pkg/a.go", reversed, items[0].Path)
+		}
+	}
+}
+
+func TestSortUsagePathsNearEqualScores(t *testing.T) {
+	for _, reversed := range []bool{false, true} {
+		paths := []UsagePath{
+			{Nodes: []PathNode{{SymbolId: "main"}, {SymbolId: "b"}}, Ranking: rank(noisy)},
+			{Nodes: []PathNode{{SymbolId: "main"}, {SymbolId: "a"}}, Ranking: rank(exact)},
+			{Nodes: []PathNode{{SymbolId: "a"}}, Ranking: rank(exact)},
+		}
+		if reversed {
+			paths[0], paths[2] = paths[2], paths[0]
+		}
+		sortUsagePaths(paths)
+		got := []string{paths[0].Nodes[0].SymbolId, paths[1].Nodes[1].SymbolId, paths[2].Nodes[1].SymbolId}
+		if got[0] != "a" || got[1] != "a" || got[2] != "b" {
+			t.Errorf("reversed=%v: got order %v", reversed, got)
+		}
+	}
+}
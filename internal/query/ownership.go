@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -37,10 +38,13 @@ type BlameContributor struct {
 	Percentage float64 `json:"percentage"`
 }
 
-// BlameOwnershipInfo represents git-blame ownership info.
+// BlameOwnershipInfo represents git-blame ownership info. For a directory
+// it combines the blame of the source files beneath it.
 type BlameOwnershipInfo struct {
-	TotalLines   int                `json:"totalLines"`
-	Contributors []BlameContributor `json:"contributors"`
+	TotalLines    int                `json:"totalLines"`
+	Contributors  []BlameContributor `json:"contributors"`
+	FilesAnalyzed int                `json:"filesAnalyzed,omitempty"` // Directories only
+	Truncated     bool               `json:"truncated,omitempty"`     // More than maxOwnershipDirFiles source files
 }
 
 // OwnershipHistoryEvent represents an ownership change event.
@@ -88,6 +92,12 @@ func (e *Engine) GetOwnership(ctx context.Context, opts GetOwnershipOptions) (*G
 		}
 	}
 
+	// A directory aggregates blame across the source files beneath it
+	isDir := false
+	if info, statErr := os.Stat(filepath.Join(e.repoRoot, normalizedPath)); statErr == nil && info.IsDir() {
+		isDir = true
+	}
+
 	// Get repo state
 	repoState, err := e.GetRepoState(ctx, "head")
 	if err != nil {
@@ -103,7 +113,7 @@ func (e *Engine) GetOwnership(ctx context.Context, opts GetOwnershipOptions) (*G
 			limitations = append(limitations, "Failed to parse CODEOWNERS: "+err.Error())
 		} else {
 			// Get CODEOWNERS owners for this path
-			codeownersOwners := codeownersFile.GetOwnersForPath(normalizedPath)
+			codeownersOwners := codeownersFile.GetOwnersForPath(codeownersLookupPath(normalizedPath, isDir))
 			if len(codeownersOwners) > 0 {
 				owners := ownership.CodeownersToOwners(codeownersOwners)
 				for _, o := range owners {
@@ -129,7 +139,18 @@ func (e *Engine) GetOwnership(ctx context.Context, opts GetOwnershipOptions) (*G
 	var blameOwnership *BlameOwnershipInfo
 	if opts.IncludeBlame {
 		blameConfig := ownership.DefaultBlameConfig()
-		blameResult, blameErr := ownership.RunGitBlame(e.repoRoot, normalizedPath)
+		var blameResult *ownership.BlameResult
+		var blameErr error
+		filesAnalyzed, filesFound := 0, 0
+		if isDir {
+			blameResult, filesAnalyzed, filesFound, blameErr = e.blameDirectory(ctx, normalizedPath)
+			if filesFound > maxOwnershipDirFiles {
+				limitations = append(limitations, fmt.Sprintf(
+					"Directory has %d source files; blame covers the first %d", filesFound, maxOwnershipDirFiles))
+			}
+		} else {
+			blameResult, blameErr = ownership.RunGitBlame(e.repoRoot, normalizedPath)
+		}
 		if blameErr != nil {
 			limitations = append(limitations, "Git blame failed: "+blameErr.Error())
 		} else {
@@ -159,8 +180,10 @@ func (e *Engine) GetOwnership(ctx context.Context, opts GetOwnershipOptions) (*G
 			}
 
 			blameOwnership = &BlameOwnershipInfo{
-				TotalLines:   blameOwn.TotalLines,
-				Contributors: contributors,
+				TotalLines:    blameOwn.TotalLines,
+				Contributors:  contributors,
+				FilesAnalyzed: filesAnalyzed,
+				Truncated:     filesFound > maxOwnershipDirFiles,
 			}
 
 			confidenceBasis = append(confidenceBasis, ConfidenceBasisItem{
@@ -206,15 +229,21 @@ func (e *Engine) GetOwnership(ctx context.Context, opts GetOwnershipOptions) (*G
 	}
 
 	// Build provenance
-	provenance := e.buildProvenance(repoState, "head", startTime, nil, completeness)
+	provenance := e.buildProvenance(ctx, repoState, "head", startTime, nil, completeness)
 
 	// Generate drilldowns
 	var drilldowns []output.Drilldown
 	if len(allOwners) > 0 {
 		// Suggest exploring the module
+		modulePath := filepath.Dir(normalizedPath)
+		if isDir {
+			modulePath = normalizedPath
+		}
 		drilldowns = append(drilldowns, output.Drilldown{
 			Label:          "Explore module",
-			Query:          "getModuleOverview for " + filepath.Dir(normalizedPath),
+			Query:          "getModuleOverview for " + modulePath,
+			Tool:           "getModuleOverview",
+			Params:         map[string]interface{}{"path": modulePath},
 			RelevanceScore: 0.8,
 		})
 	}
@@ -235,6 +264,78 @@ func (e *Engine) GetOwnership(ctx context.Context, opts GetOwnershipOptions) (*G
 	}, nil
 }
 
+// maxOwnershipDirFiles caps the files blamed for a directory's ownership.
+const maxOwnershipDirFiles = 200
+
+// codeownersLookupPath returns the path to match CODEOWNERS rules against.
+// A directory is matched as its prefix ("dir/"), so rules covering
+// everything beneath it apply; the repo root matches only global rules.
+func codeownersLookupPath(path string, isDir bool) string {
+	if !isDir {
+		return path
+	}
+	path = filepath.ToSlash(path)
+	if path == "." || path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(path, "/") + "/"
+}
+
+// blameDirectory runs git blame on up to maxOwnershipDirFiles source files
+// under dir, in walk order, and merges the entries into one result so the
+// contributor ranking covers the whole tree. Files git can't blame (e.g.
+// untracked ones) are skipped. found counts every source file seen.
+func (e *Engine) blameDirectory(ctx context.Context, dir string) (result *ownership.BlameResult, analyzed, found int, err error) {
+	filter := e.newTreeWalkFilter(true)
+	root := filepath.Join(e.repoRoot, dir)
+
+	var files []string
+	walkErr := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // skip inaccessible files
+		}
+		if d.IsDir() {
+			if filter.skipDir(path, path == root) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isSourceFile(path) || filter.skipFile(path) {
+			return nil
+		}
+		found++
+		if len(files) < maxOwnershipDirFiles {
+			if rel, relErr := filepath.Rel(e.repoRoot, path); relErr == nil {
+				files = append(files, rel)
+			}
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, 0, found, walkErr
+	}
+	if found == 0 {
+		return nil, 0, 0, fmt.Errorf("no source files under %s", dir)
+	}
+
+	result = &ownership.BlameResult{FilePath: dir}
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, analyzed, found, ctx.Err()
+		}
+		blame, blameErr := ownership.RunGitBlame(e.repoRoot, file)
+		if blameErr != nil {
+			continue
+		}
+		result.Entries = append(result.Entries, blame.Entries...)
+		analyzed++
+	}
+	if analyzed == 0 {
+		return nil, 0, found, fmt.Errorf("no committed source files under %s", dir)
+	}
+	return result, analyzed, found, nil
+}
+
 // OwnershipDriftOptions contains options for getOwnershipDrift.
 type OwnershipDriftOptions struct {
 	Scope          string  `json:"scope"`          // Module or directory path to analyze
@@ -316,6 +417,7 @@ func (e *Engine) GetOwnershipDrift(ctx context.Context, opts OwnershipDriftOptio
 				RepoStateId:     repoState.RepoStateId,
 				RepoStateDirty:  repoState.Dirty,
 				QueryDurationMs: time.Since(startTime).Milliseconds(),
+				Extra:           e.provenanceExtra(ctx),
 			},
 		}, nil
 	}
@@ -459,6 +561,7 @@ func (e *Engine) GetOwnershipDrift(ctx context.Context, opts OwnershipDriftOptio
 			RepoStateId:     repoState.RepoStateId,
 			RepoStateDirty:  repoState.Dirty,
 			QueryDurationMs: time.Since(startTime).Milliseconds(),
+			Extra:           e.provenanceExtra(ctx),
 		},
 	}, nil
 }
@@ -0,0 +1,118 @@
+package query
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodeownersLookupPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		isDir bool
+		want  string
+	}{
+		{"internal/api/handler.go", false, "internal/api/handler.go"},
+		{"internal/api", true, "internal/api/"},
+		{"internal/api/", true, "internal/api/"},
+		{".", true, ""},
+	}
+	for _, tt := range tests {
+		if got := codeownersLookupPath(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("codeownersLookupPath(%q, %v) = %q, want %q", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestGetOwnershipDirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+	root := engine.repoRoot
+
+	runOwnershipGit(t, root, "init")
+	writeOwnershipFile(t, root, "CODEOWNERS", "/pkg/ @pkg-team\n")
+	writeOwnershipFile(t, root, ".gitignore", ".ckb/\n")
+	writeOwnershipFile(t, root, "pkg/a.go", "package pkg\n")
+	writeOwnershipFile(t, root, "pkg/README.md", "not source\n")
+	runOwnershipGit(t, root, "add", "-A")
+	commitAs(t, root, "Alice", "alice@example.com")
+
+	writeOwnershipFile(t, root, "pkg/sub/b.go", "package sub\n")
+	writeOwnershipFile(t, root, "pkg/sub/c.go", "package sub\n")
+	runOwnershipGit(t, root, "add", "-A")
+	commitAs(t, root, "Bob", "bob@example.com")
+
+	resp, err := engine.GetOwnership(context.Background(), GetOwnershipOptions{Path: "pkg", IncludeBlame: true})
+	if err != nil {
+		t.Fatalf("GetOwnership() error = %v", err)
+	}
+
+	blame := resp.BlameOwnership
+	if blame == nil {
+		t.Fatalf("expected blame ownership, limitations: %v", resp.Limitations)
+	}
+	if blame.FilesAnalyzed != 3 || blame.TotalLines != 3 || blame.Truncated {
+		t.Errorf("blame = %d files, %d lines, truncated %v; want 3 files, 3 lines, not truncated",
+			blame.FilesAnalyzed, blame.TotalLines, blame.Truncated)
+	}
+	if len(blame.Contributors) != 2 {
+		t.Fatalf("expected 2 contributors, got %+v", blame.Contributors)
+	}
+	if top := blame.Contributors[0]; top.Email != "bob@example.com" || top.LineCount != 2 {
+		t.Errorf("top contributor = %+v, want bob with 2 lines", top)
+	}
+	if blame.Contributors[0].Percentage <= blame.Contributors[1].Percentage {
+		t.Errorf("contributors not ranked by percentage: %+v", blame.Contributors)
+	}
+
+	hasTeam := false
+	for _, o := range resp.Owners {
+		if o.Source == "codeowners" && strings.Contains(o.ID, "pkg-team") {
+			hasTeam = true
+		}
+	}
+	if !hasTeam {
+		t.Errorf("expected CODEOWNERS owner for directory prefix, got %+v", resp.Owners)
+	}
+
+	// A file path keeps single-file behavior
+	fileResp, err := engine.GetOwnership(context.Background(), GetOwnershipOptions{Path: "pkg/a.go", IncludeBlame: true})
+	if err != nil {
+		t.Fatalf("GetOwnership(file) error = %v", err)
+	}
+	if fileResp.BlameOwnership == nil || fileResp.BlameOwnership.TotalLines != 1 || fileResp.BlameOwnership.FilesAnalyzed != 0 {
+		t.Errorf("unexpected file blame: %+v", fileResp.BlameOwnership)
+	}
+}
+
+func writeOwnershipFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func commitAs(t *testing.T, dir, name, email string) {
+	t.Helper()
+	runOwnershipGit(t, dir, "-c", "user.name="+name, "-c", "user.email="+email, "commit", "-q", "-m", "commit by "+name)
+}
+
+func runOwnershipGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
@@ -234,6 +234,7 @@ func (e *Engine) SummarizePR(ctx context.Context, opts SummarizePROptions) (*Sum
 			RepoStateId:     repoState.RepoStateId,
 			RepoStateDirty:  repoState.Dirty,
 			QueryDurationMs: time.Since(startTime).Milliseconds(),
+			Extra:           e.provenanceExtra(ctx),
 		},
 	}, nil
 }
@@ -254,7 +255,7 @@ func (e *Engine) resolveFileModule(filePath string) string {
 // getFileHotspotScore returns the hotspot score for a file (0-1).
 func (e *Engine) getFileHotspotScore(ctx context.Context, filePath string) float64 {
 	// Try to get hotspot data from cache or compute
-	opts := GetHotspotsOptions{Limit: 100}
+	opts := GetHotspotsOptions{Limit: 100, ExcludeGenerated: true}
 	resp, err := e.GetHotspots(ctx, opts)
 	if err != nil {
 		return 0
@@ -0,0 +1,24 @@
+package query
+
+// ProgressFunc receives progress updates from long-running queries. percent
+// is the overall completion (0-100) and phase names the current step.
+type ProgressFunc func(phase string, percent float64)
+
+// report invokes fn if set. Progress is best-effort: a failing callback must
+// never change the result of the query.
+func (fn ProgressFunc) report(phase string, percent float64) {
+	if fn == nil {
+		return
+	}
+	defer func() { _ = recover() }()
+	fn(phase, percent)
+}
+
+// phaseProgress maps step done of total within a phase spanning [from, to]
+// onto overall percent.
+func phaseProgress(from, to float64, done, total int) float64 {
+	if total <= 0 {
+		return from
+	}
+	return from + (to-from)*float64(done)/float64(total)
+}
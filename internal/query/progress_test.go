@@ -0,0 +1,39 @@
+package query
+
+import "testing"
+
+func TestProgressFuncReport(t *testing.T) {
+	var nilFn ProgressFunc
+	nilFn.report("phase", 10) // must not panic
+
+	var got []float64
+	fn := ProgressFunc(func(phase string, percent float64) {
+		got = append(got, percent)
+	})
+	fn.report("a", 0)
+	fn.report("b", phaseProgress(10, 90, 1, 4))
+	if len(got) != 2 || got[1] != 30 {
+		t.Errorf("unexpected progress values %v", got)
+	}
+
+	panicking := ProgressFunc(func(string, float64) { panic("client went away") })
+	panicking.report("phase", 50) // best-effort: swallowed
+}
+
+func TestPhaseProgress(t *testing.T) {
+	tests := []struct {
+		from, to    float64
+		done, total int
+		want        float64
+	}{
+		{10, 90, 0, 8, 10},
+		{10, 90, 4, 8, 50},
+		{10, 90, 8, 8, 90},
+		{10, 90, 3, 0, 10},
+	}
+	for _, tt := range tests {
+		if got := phaseProgress(tt.from, tt.to, tt.done, tt.total); got != tt.want {
+			t.Errorf("phaseProgress(%v, %v, %d, %d) = %v, want %v", tt.from, tt.to, tt.done, tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package query
+
+import (
+	"context"
+
+	"ckb/internal/output"
+)
+
+// Provenance warning codes
+const (
+	// WarnScipUnavailable: results fell back to heuristics without the index
+	WarnScipUnavailable = "SCIP_UNAVAILABLE"
+	// WarnAnalysisLimited: a limit or missing input narrowed the analysis
+	WarnAnalysisLimited = "ANALYSIS_LIMITED"
+	// WarnIndexStale: the index no longer matches the working tree
+	WarnIndexStale = "INDEX_STALE"
+	// WarnIndexRefreshing: a background reindex is replacing a stale index
+	WarnIndexRefreshing = "INDEX_REFRESHING"
+)
+
+// contextKey is a custom type for context keys to avoid collisions
+type contextKey string
+
+const provenanceExtraKey contextKey = "provenanceExtra"
+
+// WithProvenanceExtra returns a context carrying custom provenance metadata
+// (environment, index build ID, tenant, ...). The values are merged into
+// Provenance.Extra of every response built with this context. Keys already
+// present on the parent context are overridden.
+func WithProvenanceExtra(ctx context.Context, extra map[string]interface{}) context.Context {
+	merged := make(map[string]interface{})
+	if parent, ok := ctx.Value(provenanceExtraKey).(map[string]interface{}); ok {
+		for k, v := range parent {
+			merged[k] = v
+		}
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, provenanceExtraKey, merged)
+}
+
+// provenanceExtra merges the configured provenance extras with those carried
+// by ctx. Context values take precedence. Returns nil when there are none, so
+// the field is omitted and the core provenance encoding is unchanged.
+func (e *Engine) provenanceExtra(ctx context.Context) map[string]interface{} {
+	var extra map[string]interface{}
+	if e.config != nil {
+		for k, v := range e.config.ProvenanceExtra {
+			if extra == nil {
+				extra = make(map[string]interface{})
+			}
+			extra[k] = v
+		}
+	}
+	if ctx != nil {
+		if fromCtx, ok := ctx.Value(provenanceExtraKey).(map[string]interface{}); ok {
+			for k, v := range fromCtx {
+				if extra == nil {
+					extra = make(map[string]interface{})
+				}
+				extra[k] = v
+			}
+		}
+	}
+	return extra
+}
+
+// addWarning records a warning on p unless the warnings.minSeverity policy
+// suppresses it. Warnings are kept in severity order.
+func (e *Engine) addWarning(p *Provenance, severity, code, text string) {
+	if p == nil {
+		return
+	}
+	if e.config != nil && !output.MeetsSeverity(severity, e.config.Warnings.MinSeverity) {
+		return
+	}
+	p.Warnings = append(p.Warnings, output.Warning{Severity: severity, Code: code, Text: text})
+	output.SortWarnings(p.Warnings)
+}
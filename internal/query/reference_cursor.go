@@ -0,0 +1,61 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// referenceCursor is the sort position of the last reference on a
+// findReferences page. References are ordered by fileId, startLine and
+// startColumn (see output.SortReferences), and deduplication makes that
+// position unique, so the next page starts strictly after it.
+type referenceCursor struct {
+	FileId      string `json:"f"`
+	StartLine   int    `json:"l"`
+	StartColumn int    `json:"c"`
+}
+
+// encodeReferenceCursor returns an opaque cursor for the page ending at ref.
+func encodeReferenceCursor(ref ReferenceInfo) string {
+	data, _ := json.Marshal(referenceCursor{
+		FileId:      ref.Location.FileId,
+		StartLine:   ref.Location.StartLine,
+		StartColumn: ref.Location.StartColumn,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeReferenceCursor parses a cursor returned as NextCursor.
+func decodeReferenceCursor(cursor string) (*referenceCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var c referenceCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return &c, nil
+}
+
+// after reports whether loc sorts after the cursor position.
+func (c *referenceCursor) after(loc *LocationInfo) bool {
+	if loc.FileId != c.FileId {
+		return loc.FileId > c.FileId
+	}
+	if loc.StartLine != c.StartLine {
+		return loc.StartLine > c.StartLine
+	}
+	return loc.StartColumn > c.StartColumn
+}
+
+// referencesAfter returns the references following the cursor; refs must
+// already be sorted with sortReferences.
+func referencesAfter(refs []ReferenceInfo, c *referenceCursor) []ReferenceInfo {
+	i := sort.Search(len(refs), func(i int) bool {
+		return c.after(refs[i].Location)
+	})
+	return refs[i:]
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReferenceCursorPaging(t *testing.T) {
+	var refs []ReferenceInfo
+	for _, file := range []string{"b.go", "a.go"} {
+		for line := 3; line >= 1; line-- {
+			for col := 9; col >= 1; col -= 4 {
+				refs = append(refs, ReferenceInfo{Location: &LocationInfo{FileId: file, StartLine: line, StartColumn: col}})
+			}
+		}
+	}
+	sortReferences(refs)
+
+	var seen []string
+	remaining := refs
+	for pages := 0; len(remaining) > 0; pages++ {
+		if pages > len(refs) {
+			t.Fatal("paging did not terminate")
+		}
+		page := remaining
+		if len(page) > 4 {
+			page = page[:4]
+		}
+		for _, r := range page {
+			seen = append(seen, fmt.Sprintf("%s:%d:%d", r.Location.FileId, r.Location.StartLine, r.Location.StartColumn))
+		}
+		cursor, err := decodeReferenceCursor(encodeReferenceCursor(page[len(page)-1]))
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		remaining = referencesAfter(refs, cursor)
+	}
+
+	if len(seen) != len(refs) {
+		t.Fatalf("paged %d references, want %d", len(seen), len(refs))
+	}
+	for i, r := range refs {
+		want := fmt.Sprintf("%s:%d:%d", r.Location.FileId, r.Location.StartLine, r.Location.StartColumn)
+		if seen[i] != want {
+			t.Errorf("page position %d = %s, want %s", i, seen[i], want)
+		}
+	}
+}
+
+func TestDecodeReferenceCursorInvalid(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24"} {
+		if _, err := decodeReferenceCursor(cursor); err == nil {
+			t.Errorf("decodeReferenceCursor(%q) succeeded, want error", cursor)
+		}
+	}
+}
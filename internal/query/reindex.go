@@ -0,0 +1,206 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ckb/internal/backends/scip"
+	"ckb/internal/index"
+	"ckb/internal/jobs"
+	"ckb/internal/project"
+	"ckb/internal/repostate"
+)
+
+// reindexCommand returns the indexer command line used for automatic
+// reindexing: the configured override, else the command `ckb index` builds
+// for the language it detected. Empty means the project can't be reindexed
+// automatically.
+func (e *Engine) reindexCommand() string {
+	if e.config == nil {
+		return ""
+	}
+	if cmd := strings.TrimSpace(e.config.Backends.Scip.ReindexCommand); cmd != "" {
+		return cmd
+	}
+
+	proj, err := project.LoadConfig(e.repoRoot)
+	if err != nil || proj == nil {
+		return ""
+	}
+	indexPath := scip.GetIndexPath(e.repoRoot, e.config.Backends.Scip.IndexPath)
+	command, err := project.BuildIndexCommand(e.repoRoot, proj.Language, indexPath, "")
+	if err != nil {
+		return ""
+	}
+	return command
+}
+
+// AutoReindex starts a background reindex when autoReindex is enabled and
+// the loaded index is stale. It returns the ID of the reindex underway, or
+// "" when there is none.
+func (e *Engine) AutoReindex(ctx context.Context) string {
+	if e.config == nil || !e.config.Backends.Scip.AutoReindex {
+		return ""
+	}
+	jobID, _ := e.reindexIfStale(ctx)
+	return jobID
+}
+
+// RefreshStaleIndex starts a background reindex when the loaded SCIP index
+// is stale, whether or not autoReindex is enabled, and returns the job ID
+// to poll with getJobStatus. It returns "" when there is no stale index to
+// refresh.
+func (e *Engine) RefreshStaleIndex(ctx context.Context) (string, error) {
+	return e.reindexIfStale(ctx)
+}
+
+// reindexIfStale starts a background reindex when the loaded index is
+// stale. Only one reindex runs at a time; one already underway is returned
+// instead of starting another. A repo state is reindexed at most once, so
+// an index that stays stale (e.g. an indexer ignoring uncommitted changes)
+// doesn't loop.
+func (e *Engine) reindexIfStale(ctx context.Context) (string, error) {
+	if e.scipAdapter == nil {
+		return "", nil
+	}
+	info := e.scipAdapter.GetIndexInfo()
+	if info == nil || !info.Available || info.Freshness == nil || !info.Freshness.IsStale() {
+		return "", nil
+	}
+	if e.jobRunner == nil {
+		return "", fmt.Errorf("job runner not available")
+	}
+
+	e.reindexMu.Lock()
+	defer e.reindexMu.Unlock()
+
+	if jobID := e.runningReindexJob(); jobID != "" {
+		return jobID, nil
+	}
+	repoState, err := e.GetRepoState(ctx, "head")
+	if err != nil {
+		return "", err
+	}
+	if e.reindexState == repoState.RepoStateId {
+		return "", nil
+	}
+	return e.submitReindex(repoState.RepoStateId, info.Freshness.Warning)
+}
+
+// runningReindexJob returns the ID of the background reindex while it is
+// still queued or running. Callers hold reindexMu.
+func (e *Engine) runningReindexJob() string {
+	if e.reindexJobID == "" {
+		return ""
+	}
+	if job, err := e.jobRunner.GetJob(e.reindexJobID); err == nil && job != nil && !job.IsTerminal() {
+		return e.reindexJobID
+	}
+	return ""
+}
+
+// submitReindex queues a reindex job for repoStateID and records it as the
+// engine's current one. Callers hold reindexMu.
+func (e *Engine) submitReindex(repoStateID, reason string) (string, error) {
+	command := e.reindexCommand()
+	if command == "" {
+		return "", fmt.Errorf("no indexer command known for this project; set backends.scip.reindexCommand")
+	}
+	job, err := jobs.NewJob(jobs.JobTypeReindex, &jobs.ReindexScope{
+		Command:     command,
+		RepoStateID: repoStateID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := e.jobRunner.Submit(job); err != nil {
+		e.logger.Warn("Failed to submit reindex job", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return "", err
+	}
+	e.reindexJobID = job.ID
+	e.reindexState = repoStateID
+
+	e.logger.Info("Index stale, started background reindex", map[string]interface{}{
+		"jobId":  job.ID,
+		"reason": reason,
+	})
+	return job.ID, nil
+}
+
+// runReindex runs the indexer, records index metadata and swaps the fresh
+// index in. The index lock keeps it from racing `ckb index` or the MCP
+// watcher in another process.
+func (e *Engine) runReindex(ctx context.Context, scope *jobs.ReindexScope, progress func(int)) (*jobs.ReindexResult, error) {
+	parts := strings.Fields(scope.Command)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no indexer command configured")
+	}
+
+	ckbDir := filepath.Join(e.repoRoot, ".ckb")
+	lock, err := index.AcquireLock(ckbDir)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	if dir := filepath.Dir(scip.GetIndexPath(e.repoRoot, e.config.Backends.Scip.IndexPath)); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("creating index directory: %w", err)
+		}
+	}
+
+	progress(10)
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	cmd.Dir = e.repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("indexer failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	duration := time.Since(start)
+	progress(80)
+
+	if err := e.scipAdapter.Reload(); err != nil {
+		return nil, fmt.Errorf("loading new index: %w", err)
+	}
+	if err := e.ClearAllCache(); err != nil {
+		e.logger.Warn("Failed to clear caches after reindex", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	info := e.scipAdapter.GetIndexInfo()
+	meta := &index.IndexMeta{
+		CreatedAt:   time.Now(),
+		FileCount:   info.DocumentCount,
+		Duration:    duration.Round(time.Millisecond * 100).String(),
+		Indexer:     parts[0],
+		IndexerArgs: parts,
+	}
+	if rs, err := repostate.ComputeRepoState(e.repoRoot); err == nil {
+		meta.CommitHash = rs.HeadCommit
+		meta.RepoStateID = rs.RepoStateID
+	}
+	if err := meta.Save(ckbDir); err != nil {
+		e.logger.Warn("Failed to save index metadata", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	progress(100)
+
+	return &jobs.ReindexResult{
+		Duration:      duration.Round(time.Millisecond).String(),
+		IndexedCommit: meta.CommitHash,
+		DocumentCount: info.DocumentCount,
+	}, nil
+}
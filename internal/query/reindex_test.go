@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ckb/internal/project"
+)
+
+func TestReindexCommand(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if got := engine.reindexCommand(); got != "" {
+		t.Errorf("reindexCommand() without a detected project = %q, want empty", got)
+	}
+
+	if err := project.SaveConfig(engine.repoRoot, &project.ProjectConfig{Language: project.LangPython}); err != nil {
+		t.Fatal(err)
+	}
+	if got := engine.reindexCommand(); got != "scip-python index ." {
+		t.Errorf("reindexCommand() for python = %q", got)
+	}
+
+	if err := project.SaveConfig(engine.repoRoot, &project.ProjectConfig{Language: project.LangGo}); err != nil {
+		t.Fatal(err)
+	}
+	want := "scip-go --output " + filepath.Join(engine.repoRoot, ".scip", "index.scip")
+	if got := engine.reindexCommand(); got != want {
+		t.Errorf("reindexCommand() for go = %q, want %q", got, want)
+	}
+
+	// C/C++ needs a compilation database, as for `ckb index`
+	if err := project.SaveConfig(engine.repoRoot, &project.ProjectConfig{Language: project.LangCpp}); err != nil {
+		t.Fatal(err)
+	}
+	if got := engine.reindexCommand(); got != "" {
+		t.Errorf("reindexCommand() for cpp without compile_commands.json = %q, want empty", got)
+	}
+	if err := os.WriteFile(filepath.Join(engine.repoRoot, "compile_commands.json"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := engine.reindexCommand(); got != "scip-clang --compdb-path=compile_commands.json" {
+		t.Errorf("reindexCommand() for cpp = %q", got)
+	}
+
+	engine.config.Backends.Scip.ReindexCommand = "  make scip  "
+	if got := engine.reindexCommand(); got != "make scip" {
+		t.Errorf("reindexCommand() with override = %q, want %q", got, "make scip")
+	}
+}
+
+func TestAutoReindexNeedsIndex(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	engine.config.Backends.Scip.AutoReindex = true
+	if jobID := engine.AutoReindex(context.Background()); jobID != "" || engine.reindexJobID != "" {
+		t.Errorf("expected no reindex without a loaded index, got job %q", jobID)
+	}
+}
+
+func TestRefreshStaleIndexNeedsIndex(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	jobID, err := engine.RefreshStaleIndex(context.Background())
+	if err != nil || jobID != "" || engine.reindexJobID != "" {
+		t.Errorf("RefreshStaleIndex() = %q, %v; want no job without a loaded index", jobID, err)
+	}
+}
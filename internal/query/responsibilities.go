@@ -143,7 +143,7 @@ func (e *Engine) GetModuleResponsibilities(ctx context.Context, opts GetModuleRe
 	}
 
 	// Build provenance
-	provenance := e.buildProvenance(repoState, "head", startTime, nil, completeness)
+	provenance := e.buildProvenance(ctx, repoState, "head", startTime, nil, completeness)
 
 	// Generate drilldowns
 	var drilldowns []output.Drilldown
@@ -151,11 +151,14 @@ func (e *Engine) GetModuleResponsibilities(ctx context.Context, opts GetModuleRe
 		drilldowns = append(drilldowns, output.Drilldown{
 			Label:          "Get architecture",
 			Query:          "getArchitecture",
+			Tool:           "getArchitecture",
 			RelevanceScore: 0.8,
 		})
 		drilldowns = append(drilldowns, output.Drilldown{
 			Label:          "Get ownership",
 			Query:          "getOwnership for " + moduleResponsibilities[0].Path,
+			Tool:           "getOwnership",
+			Params:         map[string]interface{}{"path": moduleResponsibilities[0].Path},
 			RelevanceScore: 0.7,
 		})
 	}
@@ -0,0 +1,449 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ckb/internal/errors"
+	"ckb/internal/output"
+)
+
+// Review checklist categories, in the order they appear in the checklist.
+const (
+	ChecklistHighRisk  = "high-risk"
+	ChecklistTests     = "tests"
+	ChecklistAPIChange = "api-change"
+	ChecklistOwnership = "ownership"
+	ChecklistDecision  = "decision"
+)
+
+var checklistCategoryOrder = map[string]int{
+	ChecklistHighRisk:  0,
+	ChecklistTests:     1,
+	ChecklistAPIChange: 2,
+	ChecklistOwnership: 3,
+	ChecklistDecision:  4,
+}
+
+var checklistPriorityOrder = map[string]int{"high": 0, "medium": 1, "low": 2}
+
+// Default deadline for the whole checklist; phases still pending when it
+// expires are skipped and reported as limitations.
+const defaultReviewChecklistTimeout = 30 * time.Second
+
+const (
+	maxChecklistImpactSymbols  = 10 // Public symbols run through analyzeImpact
+	maxChecklistOwnershipFiles = 30 // Changed files checked for owners
+)
+
+// ReviewChecklistOptions contains options for generateReviewChecklist.
+type ReviewChecklistOptions struct {
+	Base     string        // Base revision (default: main)
+	Head     string        // Head revision (default: HEAD)
+	Timeout  time.Duration // Overall deadline (default: 30s)
+	Progress ProgressFunc  // Optional progress callback
+}
+
+// ReviewChecklistResponse is the response for generateReviewChecklist.
+type ReviewChecklistResponse struct {
+	Base        string                `json:"base"`
+	Head        string                `json:"head"`
+	Items       []ReviewChecklistItem `json:"items"`
+	Counts      map[string]int        `json:"counts"` // Items per category
+	Limitations []string              `json:"limitations,omitempty"`
+	Provenance  *Provenance           `json:"provenance"`
+}
+
+// ReviewChecklistItem is a single thing for the reviewer to check.
+type ReviewChecklistItem struct {
+	Category  string           `json:"category"`
+	Priority  string           `json:"priority"` // high, medium, low
+	Title     string           `json:"title"`
+	Target    string           `json:"target"` // Symbol ID, file, module, or decision ID
+	Detail    string           `json:"detail,omitempty"`
+	Command   string           `json:"command,omitempty"` // Command to run (tests)
+	Drilldown output.Drilldown `json:"drilldown"`
+}
+
+// GenerateReviewChecklist combines summarizeDiff, impact analysis, ownership
+// and decisions into one ordered, de-duplicated review checklist. Each phase
+// degrades independently: failures and deadline overruns become limitations
+// rather than errors.
+func (e *Engine) GenerateReviewChecklist(ctx context.Context, opts ReviewChecklistOptions) (*ReviewChecklistResponse, error) {
+	startTime := time.Now()
+
+	if opts.Base == "" {
+		opts.Base = "main"
+	}
+	if opts.Head == "" {
+		opts.Head = "HEAD"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultReviewChecklistTimeout
+	}
+
+	repoState, err := e.GetRepoState(ctx, "head")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	resp := &ReviewChecklistResponse{
+		Base:   opts.Base,
+		Head:   opts.Head,
+		Items:  []ReviewChecklistItem{},
+		Counts: map[string]int{},
+	}
+
+	opts.Progress.report("summarizing diff", 0)
+	diff, err := e.SummarizeDiff(ctx, SummarizeDiffOptions{
+		CommitRange: &CommitRangeSelector{Base: opts.Base, Head: opts.Head},
+	})
+	if err != nil {
+		resp.Limitations = append(resp.Limitations, fmt.Sprintf("summarizeDiff failed: %v", err))
+		resp.Provenance = e.buildProvenance(ctx, repoState, "head", startTime, nil, CompletenessInfo{Score: 0, Reason: "no-diff"})
+		return resp, nil
+	}
+	resp.Limitations = append(resp.Limitations, diff.Limitations...)
+
+	b := &checklistBuilder{items: map[string]ReviewChecklistItem{}}
+	phases := []struct {
+		name string
+		run  func(context.Context, *checklistBuilder, *SummarizeDiffResponse) []string
+	}{
+		{"high-risk symbols", e.checklistRiskItems},
+		{"affected tests", e.checklistTestItems},
+		{"public API changes", e.checklistAPIItems},
+		{"ownership gaps", e.checklistOwnershipItems},
+		{"related decisions", e.checklistDecisionItems},
+	}
+
+	completed := 0
+	for i, phase := range phases {
+		opts.Progress.report(phase.name, phaseProgress(10, 100, i, len(phases)))
+		if ctx.Err() != nil {
+			resp.Limitations = append(resp.Limitations, fmt.Sprintf("Skipped %s: deadline exceeded", phase.name))
+			continue
+		}
+		resp.Limitations = append(resp.Limitations, phase.run(ctx, b, diff)...)
+		completed++
+	}
+
+	resp.Items = b.sorted()
+	for _, item := range resp.Items {
+		resp.Counts[item.Category]++
+	}
+
+	completeness := CompletenessInfo{Score: 1.0, Reason: "full-backend"}
+	if completed < len(phases) {
+		completeness = CompletenessInfo{
+			Score:  float64(completed) / float64(len(phases)),
+			Reason: "deadline-exceeded",
+		}
+	}
+	resp.Provenance = e.buildProvenance(ctx, repoState, "head", startTime, nil, completeness)
+	opts.Progress.report("done", 100)
+
+	return resp, nil
+}
+
+// checklistRiskItems runs impact analysis on changed public symbols and
+// flags high-churn or breaking files reported by the diff.
+func (e *Engine) checklistRiskItems(ctx context.Context, b *checklistBuilder, diff *SummarizeDiffResponse) []string {
+	var limitations []string
+
+	analyzed := 0
+	for _, sym := range diff.SymbolsAffected {
+		if sym.SymbolId == "" || !sym.IsPublicAPI || sym.ChangeType != "modified" {
+			continue
+		}
+		if analyzed >= maxChecklistImpactSymbols {
+			limitations = append(limitations, fmt.Sprintf("Impact analysis limited to %d symbols", maxChecklistImpactSymbols))
+			break
+		}
+		if ctx.Err() != nil {
+			limitations = append(limitations, "Impact analysis stopped at deadline")
+			break
+		}
+		analyzed++
+
+		impact, err := e.AnalyzeImpact(ctx, AnalyzeImpactOptions{SymbolId: sym.SymbolId, Depth: 2})
+		if err != nil || impact == nil || impact.RiskScore == nil {
+			continue
+		}
+		priority := impact.RiskScore.Level
+		switch priority {
+		case "critical":
+			priority = "high"
+		case "high", "medium":
+		default:
+			continue
+		}
+		b.add(ReviewChecklistItem{
+			Category: ChecklistHighRisk,
+			Priority: priority,
+			Title:    fmt.Sprintf("Scrutinize %s", sym.Name),
+			Target:   sym.SymbolId,
+			Detail:   impact.RiskScore.Explanation,
+			Drilldown: output.Drilldown{
+				Label:  fmt.Sprintf("Impact of changing %s", sym.Name),
+				Query:  fmt.Sprintf("analyzeImpact %s", sym.SymbolId),
+				Tool:   "analyzeImpact",
+				Params: map[string]interface{}{"symbolId": sym.SymbolId},
+			},
+		})
+	}
+
+	for _, signal := range diff.RiskSignals {
+		// API changes are covered per symbol in their own category
+		if signal.Type == "api-change" || signal.FilePath == "" {
+			continue
+		}
+		if signal.Severity != "high" && signal.Severity != "medium" {
+			continue
+		}
+		b.add(ReviewChecklistItem{
+			Category: ChecklistHighRisk,
+			Priority: signal.Severity,
+			Title:    fmt.Sprintf("Review %s", signal.FilePath),
+			Target:   signal.FilePath,
+			Detail:   signal.Description,
+			Drilldown: output.Drilldown{
+				Label:  fmt.Sprintf("Explain %s", signal.FilePath),
+				Query:  fmt.Sprintf("explainFile %s", signal.FilePath),
+				Tool:   "explainFile",
+				Params: map[string]interface{}{"filePath": signal.FilePath},
+			},
+		})
+	}
+
+	return limitations
+}
+
+// checklistTestItems lists the tests suggested by the diff with the
+// command to run them. Suggested test files that do not exist are turned
+// into reminders to add coverage.
+func (e *Engine) checklistTestItems(ctx context.Context, b *checklistBuilder, diff *SummarizeDiffResponse) []string {
+	for _, test := range diff.SuggestedTests {
+		item := ReviewChecklistItem{
+			Category: ChecklistTests,
+			Priority: test.Priority,
+			Title:    fmt.Sprintf("Run %s", test.TestPath),
+			Target:   test.TestPath,
+			Detail:   test.Reason,
+			Command:  testRunCommand(test.TestPath),
+			Drilldown: output.Drilldown{
+				Label:  fmt.Sprintf("Explain %s", test.TestPath),
+				Query:  fmt.Sprintf("explainFile %s", test.TestPath),
+				Tool:   "explainFile",
+				Params: map[string]interface{}{"filePath": test.TestPath},
+			},
+		}
+		if _, err := os.Stat(filepath.Join(e.repoRoot, test.TestPath)); err != nil {
+			item.Title = fmt.Sprintf("Add tests at %s", test.TestPath)
+			item.Detail = fmt.Sprintf("%s (test file not found)", test.Reason)
+			item.Priority = "medium"
+			item.Command = ""
+		}
+		b.add(item)
+	}
+	return nil
+}
+
+// checklistAPIItems asks the reviewer to confirm each public API change.
+func (e *Engine) checklistAPIItems(ctx context.Context, b *checklistBuilder, diff *SummarizeDiffResponse) []string {
+	for _, sym := range diff.SymbolsAffected {
+		if !sym.IsPublicAPI {
+			continue
+		}
+		priority := "high"
+		if sym.ChangeType == "added" {
+			priority = "medium"
+		}
+		target := sym.SymbolId
+		drilldown := output.Drilldown{
+			Label:  fmt.Sprintf("Find callers of %s", sym.Name),
+			Query:  fmt.Sprintf("findReferences %s", sym.SymbolId),
+			Tool:   "findReferences",
+			Params: map[string]interface{}{"symbolId": sym.SymbolId},
+		}
+		if target == "" {
+			target = sym.FilePath + "#" + sym.Name
+			drilldown = output.Drilldown{
+				Label:  fmt.Sprintf("Explain %s", sym.FilePath),
+				Query:  fmt.Sprintf("explainFile %s", sym.FilePath),
+				Tool:   "explainFile",
+				Params: map[string]interface{}{"filePath": sym.FilePath},
+			}
+		}
+		b.add(ReviewChecklistItem{
+			Category:  ChecklistAPIChange,
+			Priority:  priority,
+			Title:     fmt.Sprintf("Confirm public API change: %s (%s)", sym.Name, sym.ChangeType),
+			Target:    target,
+			Detail:    sym.FilePath,
+			Drilldown: drilldown,
+		})
+	}
+	return nil
+}
+
+// checklistOwnershipItems reports modules whose changed files have no owner.
+func (e *Engine) checklistOwnershipItems(ctx context.Context, b *checklistBuilder, diff *SummarizeDiffResponse) []string {
+	var limitations []string
+	unowned := map[string][]string{}
+
+	checked := 0
+	for _, file := range diff.ChangedFiles {
+		if file.ChangeType == "deleted" {
+			continue
+		}
+		if checked >= maxChecklistOwnershipFiles {
+			limitations = append(limitations, fmt.Sprintf("Ownership checked for the first %d files only", maxChecklistOwnershipFiles))
+			break
+		}
+		if ctx.Err() != nil {
+			limitations = append(limitations, "Ownership check stopped at deadline")
+			break
+		}
+		checked++
+
+		owners, err := e.GetOwnership(ctx, GetOwnershipOptions{Path: file.FilePath})
+		if err != nil {
+			limitations = append(limitations, fmt.Sprintf("Ownership unavailable: %v", err))
+			break
+		}
+		if len(owners.Owners) == 0 {
+			module := e.resolveFileModule(file.FilePath)
+			unowned[module] = append(unowned[module], file.FilePath)
+		}
+	}
+
+	for module, files := range unowned {
+		sort.Strings(files)
+		b.add(ReviewChecklistItem{
+			Category: ChecklistOwnership,
+			Priority: "medium",
+			Title:    fmt.Sprintf("Find a reviewer for %s", module),
+			Target:   module,
+			Detail:   fmt.Sprintf("%d changed file(s) without an owner: %s", len(files), strings.Join(files, ", ")),
+			Drilldown: output.Drilldown{
+				Label:  fmt.Sprintf("Ownership of %s", module),
+				Query:  fmt.Sprintf("getOwnership %s", module),
+				Tool:   "getOwnership",
+				Params: map[string]interface{}{"path": module},
+			},
+		})
+	}
+
+	return limitations
+}
+
+// checklistDecisionItems lists active decisions affecting touched modules.
+func (e *Engine) checklistDecisionItems(ctx context.Context, b *checklistBuilder, diff *SummarizeDiffResponse) []string {
+	modules := map[string]bool{}
+	for _, file := range diff.ChangedFiles {
+		if module := e.resolveFileModule(file.FilePath); module != "" {
+			modules[module] = true
+		}
+	}
+
+	moduleIds := make([]string, 0, len(modules))
+	for module := range modules {
+		moduleIds = append(moduleIds, module)
+	}
+	sort.Strings(moduleIds)
+
+	for _, module := range moduleIds {
+		if ctx.Err() != nil {
+			return []string{"Decision lookup stopped at deadline"}
+		}
+		result, err := e.GetDecisions(&DecisionsQuery{ModuleID: module})
+		if err != nil {
+			return []string{fmt.Sprintf("Decisions unavailable: %v", err)}
+		}
+		for _, adr := range result.Decisions {
+			if adr.Status == "superseded" || adr.Status == "deprecated" {
+				continue
+			}
+			b.add(ReviewChecklistItem{
+				Category: ChecklistDecision,
+				Priority: "low",
+				Title:    fmt.Sprintf("Check %s: %s", adr.ID, adr.Title),
+				Target:   adr.ID,
+				Detail:   fmt.Sprintf("Affects %s", module),
+				Drilldown: output.Drilldown{
+					Label:  fmt.Sprintf("Read %s", adr.ID),
+					Query:  fmt.Sprintf("getDecisions %s", adr.ID),
+					Tool:   "getDecisions",
+					Params: map[string]interface{}{"id": adr.ID},
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// checklistBuilder de-duplicates checklist items by category and target,
+// keeping the highest-priority entry.
+type checklistBuilder struct {
+	items map[string]ReviewChecklistItem
+}
+
+func (b *checklistBuilder) add(item ReviewChecklistItem) {
+	if _, ok := checklistPriorityOrder[item.Priority]; !ok {
+		item.Priority = "low"
+	}
+	key := item.Category + "\x00" + item.Target
+	if existing, ok := b.items[key]; ok &&
+		checklistPriorityOrder[existing.Priority] <= checklistPriorityOrder[item.Priority] {
+		return
+	}
+	b.items[key] = item
+}
+
+// sorted returns the items by category, priority, then target.
+func (b *checklistBuilder) sorted() []ReviewChecklistItem {
+	items := make([]ReviewChecklistItem, 0, len(b.items))
+	for _, item := range b.items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		ci, cj := checklistCategoryOrder[items[i].Category], checklistCategoryOrder[items[j].Category]
+		if ci != cj {
+			return ci < cj
+		}
+		pi, pj := checklistPriorityOrder[items[i].Priority], checklistPriorityOrder[items[j].Priority]
+		if pi != pj {
+			return pi < pj
+		}
+		return items[i].Target < items[j].Target
+	})
+	return items
+}
+
+// testRunCommand returns the command that runs a single test file, or ""
+// for languages without a conventional runner.
+func testRunCommand(testPath string) string {
+	switch detectLanguage(testPath) {
+	case "go":
+		dir := filepath.ToSlash(filepath.Dir(testPath))
+		if dir == "." {
+			return "go test ."
+		}
+		return "go test ./" + dir
+	case "typescript", "javascript":
+		return "npx jest " + testPath
+	case "python":
+		return "pytest " + testPath
+	default:
+		return ""
+	}
+}
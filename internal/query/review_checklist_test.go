@@ -0,0 +1,79 @@
+package query
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChecklistBuilderDeduplicatesAndOrders(t *testing.T) {
+	b := &checklistBuilder{items: map[string]ReviewChecklistItem{}}
+	b.add(ReviewChecklistItem{Category: ChecklistDecision, Priority: "low", Target: "ADR-002"})
+	b.add(ReviewChecklistItem{Category: ChecklistTests, Priority: "medium", Target: "a_test.go"})
+	b.add(ReviewChecklistItem{Category: ChecklistHighRisk, Priority: "medium", Target: "sym-b"})
+	b.add(ReviewChecklistItem{Category: ChecklistHighRisk, Priority: "high", Target: "sym-c"})
+	// Duplicate with higher priority replaces, lower priority is ignored
+	b.add(ReviewChecklistItem{Category: ChecklistHighRisk, Priority: "high", Target: "sym-b", Title: "upgraded"})
+	b.add(ReviewChecklistItem{Category: ChecklistHighRisk, Priority: "low", Target: "sym-c", Title: "ignored"})
+	// Same target in another category is a separate item
+	b.add(ReviewChecklistItem{Category: ChecklistAPIChange, Priority: "high", Target: "sym-b"})
+
+	items := b.sorted()
+	want := []struct{ category, target string }{
+		{ChecklistHighRisk, "sym-b"},
+		{ChecklistHighRisk, "sym-c"},
+		{ChecklistTests, "a_test.go"},
+		{ChecklistAPIChange, "sym-b"},
+		{ChecklistDecision, "ADR-002"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d: %+v", len(items), len(want), items)
+	}
+	for i, w := range want {
+		if items[i].Category != w.category || items[i].Target != w.target {
+			t.Errorf("item %d = %s/%s, want %s/%s", i, items[i].Category, items[i].Target, w.category, w.target)
+		}
+	}
+	if items[0].Title != "upgraded" {
+		t.Errorf("expected higher-priority duplicate to win, got %q", items[0].Title)
+	}
+	if items[1].Title == "ignored" {
+		t.Error("expected lower-priority duplicate to be ignored")
+	}
+}
+
+func TestTestRunCommand(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"internal/query/pr_test.go", "go test ./internal/query"},
+		{"main_test.go", "go test ."},
+		{"src/app.test.ts", "npx jest src/app.test.ts"},
+		{"tests/test_api.py", "pytest tests/test_api.py"},
+		{"spec/foo_spec.rb", ""},
+	}
+	for _, tt := range tests {
+		if got := testRunCommand(tt.path); got != tt.want {
+			t.Errorf("testRunCommand(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateReviewChecklistWithoutGit(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	resp, err := engine.GenerateReviewChecklist(context.Background(), ReviewChecklistOptions{Base: "main"})
+	if err != nil {
+		t.Fatalf("GenerateReviewChecklist failed: %v", err)
+	}
+	if resp.Items == nil || len(resp.Items) != 0 {
+		t.Errorf("expected empty non-nil items, got %+v", resp.Items)
+	}
+	if len(resp.Limitations) == 0 {
+		t.Error("expected a limitation when git is unavailable")
+	}
+	if resp.Head != "HEAD" {
+		t.Errorf("Head = %q, want HEAD", resp.Head)
+	}
+}
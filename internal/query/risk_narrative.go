@@ -0,0 +1,43 @@
+package query
+
+import (
+	"sort"
+	"strings"
+)
+
+// minNarrativeContribution drops factors whose weighted value is too small
+// to be worth mentioning.
+const minNarrativeContribution = 0.05
+
+// riskNarrative turns risk factors into a ranked, readable breakdown, e.g.
+// "Primary risk: 47 direct callers across 6 modules; secondary: public API
+// with external consumers". Factors are ordered by contribution
+// (value × weight); those without a Detail are left out.
+func riskNarrative(factors []RiskFactor) string {
+	ranked := make([]RiskFactor, 0, len(factors))
+	for _, f := range factors {
+		if f.Detail == "" || f.Value*f.Weight < minNarrativeContribution {
+			continue
+		}
+		ranked = append(ranked, f)
+	}
+	if len(ranked) == 0 {
+		return ""
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Value*ranked[i].Weight > ranked[j].Value*ranked[j].Weight
+	})
+
+	parts := []string{"Primary risk: " + ranked[0].Detail}
+	if len(ranked) > 1 {
+		parts = append(parts, "secondary: "+ranked[1].Detail)
+	}
+	if len(ranked) > 2 {
+		rest := make([]string, 0, len(ranked)-2)
+		for _, f := range ranked[2:] {
+			rest = append(rest, f.Detail)
+		}
+		parts = append(parts, "also: "+strings.Join(rest, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
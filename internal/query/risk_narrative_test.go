@@ -0,0 +1,52 @@
+package query
+
+import "testing"
+
+func TestRiskNarrative(t *testing.T) {
+	tests := []struct {
+		name    string
+		factors []RiskFactor
+		want    string
+	}{
+		{
+			name:    "no factors",
+			factors: nil,
+			want:    "",
+		},
+		{
+			name: "ranked by contribution",
+			factors: []RiskFactor{
+				{Name: "visibility", Value: 0.9, Weight: 0.3, Detail: "public API with external consumers"},
+				{Name: "direct-callers", Value: 1.0, Weight: 0.35, Detail: "47 direct callers across 6 modules"},
+			},
+			want: "Primary risk: 47 direct callers across 6 modules; secondary: public API with external consumers",
+		},
+		{
+			name: "remaining factors grouped",
+			factors: []RiskFactor{
+				{Name: "impact-kind", Value: 0.7, Weight: 0.1, Detail: "call sites break if the signature changes"},
+				{Name: "module-spread", Value: 0.8, Weight: 0.25, Detail: "impact spans 6 modules"},
+				{Name: "direct-callers", Value: 1.0, Weight: 0.35, Detail: "47 direct callers across 6 modules"},
+				{Name: "visibility", Value: 0.9, Weight: 0.3, Detail: "public API with external consumers"},
+			},
+			want: "Primary risk: 47 direct callers across 6 modules; secondary: public API with external consumers; also: impact spans 6 modules, call sites break if the signature changes",
+		},
+		{
+			name: "negligible and undescribed factors omitted",
+			factors: []RiskFactor{
+				{Name: "direct-callers", Value: 0, Weight: 0.35, Detail: "0 direct callers across 0 modules"},
+				{Name: "custom", Value: 1.0, Weight: 0.5},
+				{Name: "visibility", Value: 0.2, Weight: 0.3, Detail: "private symbol"},
+			},
+			want: "Primary risk: private symbol",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := riskNarrative(tt.factors); got != tt.want {
+				t.Errorf("riskNarrative() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"ckb/internal/config"
+	"ckb/internal/errors"
+)
+
+// defaultRiskTrendLimit is the number of scores getRiskTrend returns when
+// no limit is given.
+const defaultRiskTrendLimit = 20
+
+// GetRiskTrendOptions contains options for getRiskTrend.
+type GetRiskTrendOptions struct {
+	SymbolId string
+	Limit    int // Newest scores to return; 0 uses defaultRiskTrendLimit
+}
+
+// GetRiskTrendResponse is the response for getRiskTrend.
+type GetRiskTrendResponse struct {
+	SymbolId    string           `json:"symbolId"`
+	Points      []RiskTrendPoint `json:"points"` // Oldest first
+	Delta       float64          `json:"delta"`  // Newest score minus oldest; 0 with fewer than two points
+	Limitations []string         `json:"limitations,omitempty"`
+	Provenance  *Provenance      `json:"provenance"`
+}
+
+// RiskTrendPoint is the risk score analyzeImpact computed at one repo state.
+type RiskTrendPoint struct {
+	RepoStateId string    `json:"repoStateId"`
+	Score       float64   `json:"score"`
+	Level       string    `json:"level"`
+	RecordedAt  time.Time `json:"recordedAt"`
+}
+
+// GetRiskTrend returns the risk scores recorded for a symbol by earlier
+// analyzeImpact calls, so callers can see whether it is getting riskier.
+// Scores are only recorded while riskHistory.enabled is set.
+func (e *Engine) GetRiskTrend(ctx context.Context, opts GetRiskTrendOptions) (*GetRiskTrendResponse, error) {
+	startTime := time.Now()
+
+	if opts.SymbolId == "" {
+		return nil, errors.NewCkbError(errors.InvalidParameters, "symbolId is required", nil, nil, nil)
+	}
+	if e.db == nil {
+		return nil, errors.NewCkbError(errors.InternalError, "getRiskTrend needs the CKB database", nil, nil, nil)
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultRiskTrendLimit
+	}
+
+	repoState, err := e.GetRepoState(ctx, "head")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	// History is keyed by stable ID, as AnalyzeImpact records it
+	symbolId := opts.SymbolId
+	if resolved, _ := e.resolver.ResolveSymbolId(opts.SymbolId); resolved != nil && resolved.Symbol != nil {
+		symbolId = resolved.Symbol.StableId
+	}
+
+	records, err := e.db.GetRiskHistory(symbolId, limit)
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	points := make([]RiskTrendPoint, 0, len(records))
+	for _, r := range records {
+		points = append(points, RiskTrendPoint{
+			RepoStateId: r.RepoStateID,
+			Score:       r.Score,
+			Level:       r.Level,
+			RecordedAt:  r.RecordedAt,
+		})
+	}
+
+	var delta float64
+	if len(points) > 1 {
+		delta = points[len(points)-1].Score - points[0].Score
+	}
+
+	var limitations []string
+	if e.config == nil || !e.config.RiskHistory.Enabled {
+		limitations = append(limitations, "Risk history recording is disabled; set riskHistory.enabled to record analyzeImpact scores")
+	}
+
+	completeness := CompletenessInfo{Score: 1.0, Reason: "full-backend"}
+	provenance := e.buildProvenance(ctx, repoState, "head", startTime, nil, completeness)
+
+	return &GetRiskTrendResponse{
+		SymbolId:    symbolId,
+		Points:      points,
+		Delta:       delta,
+		Limitations: limitations,
+		Provenance:  provenance,
+	}, nil
+}
+
+// recordRiskScore adds an analyzeImpact risk score to the symbol's history
+// when riskHistory is enabled. Only modifications are recorded: what-if
+// delete and rename runs score a different change and would skew the
+// trend. Scores are clamped to 0-1 so trends compare like with like.
+// Failures are logged and never fail the analysis.
+func (e *Engine) recordRiskScore(resp *AnalyzeImpactResponse, repoStateId string) {
+	if e.config == nil || !e.config.RiskHistory.Enabled || e.db == nil {
+		return
+	}
+	if resp == nil || resp.RiskScore == nil || resp.Symbol == nil || resp.Symbol.StableId == "" || repoStateId == "" {
+		return
+	}
+	if resp.ChangeKind != "" {
+		return
+	}
+
+	score := resp.RiskScore.Score
+	if score < 0 {
+		score = 0
+	} else if score > 1 {
+		score = 1
+	}
+
+	maxPerSymbol := e.config.RiskHistory.MaxPerSymbol
+	if maxPerSymbol == 0 {
+		maxPerSymbol = config.DefaultRiskHistoryPerSymbol
+	}
+
+	if err := e.db.RecordRiskScore(resp.Symbol.StableId, repoStateId, score, resp.RiskScore.Level, maxPerSymbol); err != nil {
+		e.logger.Warn("Failed to record risk score", map[string]interface{}{
+			"symbolId": resp.Symbol.StableId,
+			"error":    err.Error(),
+		})
+	}
+}
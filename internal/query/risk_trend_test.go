@@ -0,0 +1,74 @@
+package query
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestRiskTrend(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	record := func(state string, score float64, level string) {
+		engine.recordRiskScore(&AnalyzeImpactResponse{
+			Symbol:    &SymbolInfo{StableId: "ckb:test:sym:a"},
+			RiskScore: &RiskScore{Score: score, Level: level},
+		}, state)
+	}
+
+	// Nothing is recorded while history is disabled
+	record("state-0", 0.3, "medium")
+	resp, err := engine.GetRiskTrend(context.Background(), GetRiskTrendOptions{SymbolId: "ckb:test:sym:a"})
+	if err != nil {
+		t.Fatalf("GetRiskTrend failed: %v", err)
+	}
+	if len(resp.Points) != 0 {
+		t.Errorf("expected no points with history disabled, got %d", len(resp.Points))
+	}
+	if len(resp.Limitations) == 0 {
+		t.Error("expected a limitation noting that history is disabled")
+	}
+
+	engine.config.RiskHistory.Enabled = true
+	engine.config.RiskHistory.MaxPerSymbol = 3
+	record("state-1", 0.2, "low")
+	record("state-2", 0.4, "medium")
+	record("state-3", 1.4, "high") // Clamped to 1
+	record("state-4", 0.7, "high")
+
+	// What-if deletes score a different change and stay out of the trend
+	engine.recordRiskScore(&AnalyzeImpactResponse{
+		Symbol:     &SymbolInfo{StableId: "ckb:test:sym:a"},
+		RiskScore:  &RiskScore{Score: 0.9, Level: "high"},
+		ChangeKind: "delete",
+	}, "state-5")
+
+	resp, err = engine.GetRiskTrend(context.Background(), GetRiskTrendOptions{SymbolId: "ckb:test:sym:a"})
+	if err != nil {
+		t.Fatalf("GetRiskTrend failed: %v", err)
+	}
+	if len(resp.Limitations) != 0 {
+		t.Errorf("unexpected limitations: %v", resp.Limitations)
+	}
+	wantScores := []float64{0.4, 1, 0.7}
+	if len(resp.Points) != len(wantScores) {
+		t.Fatalf("expected %d points, got %d: %+v", len(wantScores), len(resp.Points), resp.Points)
+	}
+	for i, want := range wantScores {
+		if resp.Points[i].Score != want {
+			t.Errorf("point %d: score %v, want %v", i, resp.Points[i].Score, want)
+		}
+	}
+	if math.Abs(resp.Delta-0.3) > 1e-9 {
+		t.Errorf("delta = %v, want 0.3", resp.Delta)
+	}
+
+	resp, err = engine.GetRiskTrend(context.Background(), GetRiskTrendOptions{SymbolId: "ckb:test:sym:a", Limit: 1})
+	if err != nil {
+		t.Fatalf("GetRiskTrend failed: %v", err)
+	}
+	if len(resp.Points) != 1 || resp.Points[0].RepoStateId != "state-4" || resp.Delta != 0 {
+		t.Errorf("expected only the newest point and no delta, got %+v (delta %v)", resp.Points, resp.Delta)
+	}
+}
@@ -0,0 +1,78 @@
+package query
+
+import (
+	"path"
+	"strings"
+
+	"ckb/internal/paths"
+)
+
+// scipPathIndex reconciles git's repo-relative paths with the document paths
+// of the loaded SCIP index. Indexers disagree on conventions: some emit
+// "./" prefixes, absolute paths or Windows separators, and an index built in
+// a sub-directory has paths relative to that directory rather than the repo.
+type scipPathIndex struct {
+	repoRoot  string
+	indexRoot string            // Absolute directory the indexer ran in, forward slashes
+	prefix    string            // indexRoot relative to repoRoot; "" when they match
+	docs      map[string]string // Canonical path -> SCIP document path
+}
+
+// newSCIPPathIndex builds the index from the indexer's project root (as
+// recorded in the SCIP metadata) and the document paths it emitted.
+func newSCIPPathIndex(repoRoot, projectRoot string, docPaths []string) *scipPathIndex {
+	x := &scipPathIndex{
+		repoRoot: repoRoot,
+		docs:     make(map[string]string, len(docPaths)),
+	}
+
+	if root := paths.ToRepoRelative(projectRoot, ""); paths.IsAbsSlashPath(root) {
+		rel := paths.ToRepoRelative(root, repoRoot)
+		if rel != "." && !paths.IsAbsSlashPath(rel) && rel != ".." && !strings.HasPrefix(rel, "../") {
+			x.indexRoot = root
+			x.prefix = rel
+		}
+	}
+
+	for _, doc := range docPaths {
+		x.docs[x.canonical(doc)] = doc
+	}
+	return x
+}
+
+// canonical converts a SCIP document path to repo-relative, forward-slash form.
+func (x *scipPathIndex) canonical(p string) string {
+	p = paths.ToRepoRelative(p, "")
+	if paths.IsAbsSlashPath(p) {
+		if x.indexRoot != "" {
+			if rel := paths.ToRepoRelative(p, x.indexRoot); !paths.IsAbsSlashPath(rel) {
+				return path.Join(x.prefix, rel)
+			}
+		}
+		return paths.ToRepoRelative(p, x.repoRoot)
+	}
+	if x.prefix != "" && p != "" {
+		p = path.Join(x.prefix, p)
+	}
+	return p
+}
+
+// scipPath returns the SCIP document path for a canonical path, falling back
+// to the canonical path for files the index doesn't know.
+func (x *scipPathIndex) scipPath(canonical string) string {
+	if doc, ok := x.docs[canonical]; ok {
+		return doc
+	}
+	return canonical
+}
+
+// scipPaths returns the path index for the loaded SCIP index.
+func (e *Engine) scipPaths() *scipPathIndex {
+	info := e.scipAdapter.GetIndexInfo()
+	docs := e.scipAdapter.ListDocuments("")
+	docPaths := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		docPaths = append(docPaths, doc.Path)
+	}
+	return newSCIPPathIndex(e.repoRoot, info.ProjectRoot, docPaths)
+}
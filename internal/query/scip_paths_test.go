@@ -0,0 +1,83 @@
+package query
+
+import "testing"
+
+func TestSCIPPathIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		projectRoot string
+		docs        []string
+		gitPath     string // Repo-relative, as reported by git
+		wantSCIP    string
+	}{
+		{
+			name:        "matching conventions",
+			projectRoot: "file:///repo",
+			docs:        []string{"internal/query/engine.go"},
+			gitPath:     "internal/query/engine.go",
+			wantSCIP:    "internal/query/engine.go",
+		},
+		{
+			name:        "dot-slash prefix",
+			projectRoot: "file:///repo",
+			docs:        []string{"./internal/query/engine.go"},
+			gitPath:     "internal/query/engine.go",
+			wantSCIP:    "./internal/query/engine.go",
+		},
+		{
+			name:        "absolute paths",
+			projectRoot: "file:///repo",
+			docs:        []string{"/repo/internal/query/engine.go"},
+			gitPath:     "internal/query/engine.go",
+			wantSCIP:    "/repo/internal/query/engine.go",
+		},
+		{
+			name:        "windows separators",
+			projectRoot: "",
+			docs:        []string{"internal\\query\\engine.go"},
+			gitPath:     "internal/query/engine.go",
+			wantSCIP:    "internal\\query\\engine.go",
+		},
+		{
+			name:        "indexed from a sub-directory",
+			projectRoot: "file:///repo/services/payments",
+			docs:        []string{"handler.go", "./store/db.go"},
+			gitPath:     "services/payments/store/db.go",
+			wantSCIP:    "./store/db.go",
+		},
+		{
+			name:        "absolute path under sub-directory root",
+			projectRoot: "/repo/services/payments",
+			docs:        []string{"/repo/services/payments/handler.go"},
+			gitPath:     "services/payments/handler.go",
+			wantSCIP:    "/repo/services/payments/handler.go",
+		},
+		{
+			name:        "project root outside the repo",
+			projectRoot: "file:///elsewhere",
+			docs:        []string{"main.go"},
+			gitPath:     "main.go",
+			wantSCIP:    "main.go",
+		},
+		{
+			name:        "unknown file falls back to git path",
+			projectRoot: "file:///repo",
+			docs:        []string{"main.go"},
+			gitPath:     "cmd/tool/main.go",
+			wantSCIP:    "cmd/tool/main.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := newSCIPPathIndex("/repo", tt.projectRoot, tt.docs)
+			got := x.scipPath(tt.gitPath)
+			if got != tt.wantSCIP {
+				t.Errorf("scipPath(%q) = %q, want %q", tt.gitPath, got, tt.wantSCIP)
+			}
+			if canonical := x.canonical(got); canonical != tt.gitPath {
+				t.Errorf("canonical(%q) = %q, want %q", got, canonical, tt.gitPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// scopeRegexPrefix marks a scope as a regular expression, e.g. "re:_test\.go$".
+const scopeRegexPrefix = "re:"
+
+// scopeMatcher decides whether a repo-relative path falls within a scope.
+// A scope is a path prefix, a glob when it contains * or ? (** spans
+// directories), or a regular expression when it starts with "re:". A nil
+// matcher matches every path.
+type scopeMatcher struct {
+	prefix string
+	re     *regexp.Regexp
+}
+
+// compileScope parses a scope string. An empty scope returns a nil matcher.
+func compileScope(scope string) (*scopeMatcher, error) {
+	switch {
+	case scope == "":
+		return nil, nil
+	case strings.HasPrefix(scope, scopeRegexPrefix):
+		re, err := regexp.Compile(strings.TrimPrefix(scope, scopeRegexPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope regular expression %q: %w", scope, err)
+		}
+		return &scopeMatcher{re: re}, nil
+	case isScopeGlob(scope):
+		re, err := regexp.Compile("^" + scopeGlobToRegex(scope) + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope glob %q: %w", scope, err)
+		}
+		return &scopeMatcher{re: re}, nil
+	default:
+		return &scopeMatcher{prefix: scope}, nil
+	}
+}
+
+// compileExcludePaths compiles a list of exclusion patterns, skipping
+// empty entries.
+func compileExcludePaths(patterns []string) ([]*scopeMatcher, error) {
+	var matchers []*scopeMatcher
+	for _, p := range patterns {
+		m, err := compileScope(strings.TrimSpace(p))
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			matchers = append(matchers, m)
+		}
+	}
+	return matchers, nil
+}
+
+// matchesAny reports whether any matcher matches path.
+func matchesAny(matchers []*scopeMatcher, path string) bool {
+	for _, m := range matchers {
+		if m.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether path is within the scope.
+func (m *scopeMatcher) Match(path string) bool {
+	if m == nil {
+		return true
+	}
+	if m.re != nil {
+		return m.re.MatchString(path)
+	}
+	return strings.HasPrefix(path, m.prefix)
+}
+
+// isScopePattern reports whether scope is a glob or regular expression
+// rather than a plain prefix.
+func isScopePattern(scope string) bool {
+	return strings.HasPrefix(scope, scopeRegexPrefix) || isScopeGlob(scope)
+}
+
+func isScopeGlob(scope string) bool {
+	return strings.ContainsAny(scope, "*?")
+}
+
+// backendScope returns the scope to hand to a backend. Backends only
+// understand prefixes, so patterns are left to the caller to apply.
+func backendScope(scope string) []string {
+	if isScopePattern(scope) {
+		return nil
+	}
+	return parseScope(scope)
+}
+
+// scopeGlobToRegex converts a glob to a regular expression body: ** matches
+// across directories, * and ? stay within one path segment.
+func scopeGlobToRegex(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				if i+2 < len(glob) && glob[i+2] == '/' {
+					// "**/" matches zero or more leading directories
+					b.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					b.WriteString(".*")
+					i++
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package query
+
+import "testing"
+
+func TestCompileScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		path  string
+		want  bool
+	}{
+		{"", "any/file.go", true},
+		{"internal/api", "internal/api/handler.go", true},
+		{"internal/api", "internal/query/engine.go", false},
+		{"**/handlers/*.go", "handlers/user.go", true},
+		{"**/handlers/*.go", "internal/api/handlers/user.go", true},
+		{"**/handlers/*.go", "internal/api/handlers/v2/user.go", false},
+		{"**/handlers/*.go", "internal/api/xhandlers/user.go", false},
+		{"internal/*/engine.go", "internal/query/engine.go", true},
+		{"internal/*/engine.go", "internal/a/b/engine.go", false},
+		{"cmd/ckb/?.go", "cmd/ckb/a.go", true},
+		{"internal/**", "internal/a/b/c.go", true},
+		{"re:_test\\.go$", "internal/query/engine_test.go", true},
+		{"re:_test\\.go$", "internal/query/engine.go", false},
+	}
+
+	for _, tt := range tests {
+		m, err := compileScope(tt.scope)
+		if err != nil {
+			t.Fatalf("compileScope(%q): %v", tt.scope, err)
+		}
+		if got := m.Match(tt.path); got != tt.want {
+			t.Errorf("scope %q match %q = %v, want %v", tt.scope, tt.path, got, tt.want)
+		}
+	}
+
+	if _, err := compileScope("re:("); err == nil {
+		t.Error("expected error for invalid regular expression")
+	}
+}
+
+func TestBackendScope(t *testing.T) {
+	if got := backendScope("internal/api"); len(got) != 1 || got[0] != "internal/api" {
+		t.Errorf("prefix scope = %v, want [internal/api]", got)
+	}
+	for _, scope := range []string{"", "**/*.go", "re:api"} {
+		if got := backendScope(scope); got != nil {
+			t.Errorf("backendScope(%q) = %v, want nil", scope, got)
+		}
+	}
+}
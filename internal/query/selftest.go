@@ -0,0 +1,150 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ckb/internal/output"
+)
+
+// DefaultDeterminismTools is the representative tool set exercised by the
+// determinism self-test when no explicit set is given.
+var DefaultDeterminismTools = []string{
+	"getArchitecture",
+	"getHotspots",
+	"getModuleOverview",
+	"listEntrypoints",
+	"listKeyConcepts",
+}
+
+// VerifyDeterminismOptions controls the determinism self-test.
+type VerifyDeterminismOptions struct {
+	Tools []string // Tool names to run (default: DefaultDeterminismTools)
+}
+
+// DeterminismToolResult records the outcome for a single tool.
+type DeterminismToolResult struct {
+	Tool           string `json:"tool"`
+	Deterministic  bool   `json:"deterministic"`
+	FirstDiffField string `json:"firstDiffField,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// VerifyDeterminismResponse is the result of a determinism self-test.
+type VerifyDeterminismResponse struct {
+	Passed    bool                    `json:"passed"`
+	Results   []DeterminismToolResult `json:"results"`
+	FirstDiff *DeterminismToolResult  `json:"firstDiff,omitempty"`
+}
+
+// determinismRunner executes one tool and returns its response.
+type determinismRunner func(ctx context.Context, e *Engine) (interface{}, error)
+
+var determinismRunners = map[string]determinismRunner{
+	"getArchitecture": func(ctx context.Context, e *Engine) (interface{}, error) {
+		return e.GetArchitecture(ctx, GetArchitectureOptions{})
+	},
+	"getHotspots": func(ctx context.Context, e *Engine) (interface{}, error) {
+		return e.GetHotspots(ctx, GetHotspotsOptions{ExcludeGenerated: true})
+	},
+	"getModuleOverview": func(ctx context.Context, e *Engine) (interface{}, error) {
+		return e.GetModuleOverview(ctx, ModuleOverviewOptions{Path: ".", ExcludeGenerated: true, RespectGitignore: true})
+	},
+	"listEntrypoints": func(ctx context.Context, e *Engine) (interface{}, error) {
+		return e.ListEntrypoints(ctx, ListEntrypointsOptions{})
+	},
+	"listKeyConcepts": func(ctx context.Context, e *Engine) (interface{}, error) {
+		return e.ListKeyConcepts(ctx, ListKeyConceptsOptions{ExcludeGenerated: true, RespectGitignore: true})
+	},
+	"recentlyRelevant": func(ctx context.Context, e *Engine) (interface{}, error) {
+		return e.RecentlyRelevant(ctx, RecentlyRelevantOptions{})
+	},
+}
+
+// DeterminismToolNames returns the names of all tools the self-test can run.
+func DeterminismToolNames() []string {
+	names := make([]string, 0, len(determinismRunners))
+	for name := range determinismRunners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VerifyDeterminism runs each tool twice against the current repository and
+// compares the encoded responses with output.CompareSnapshots. Time-varying
+// provenance fields are ignored. A tool that fails cannot be verified, so
+// it fails the check as well; FirstDiff only records actual differences.
+func (e *Engine) VerifyDeterminism(ctx context.Context, opts VerifyDeterminismOptions) (*VerifyDeterminismResponse, error) {
+	tools := opts.Tools
+	if len(tools) == 0 {
+		tools = DefaultDeterminismTools
+	}
+
+	for _, tool := range tools {
+		if _, ok := determinismRunners[tool]; !ok {
+			return nil, fmt.Errorf("unknown tool %q (available: %s)", tool, strings.Join(DeterminismToolNames(), ", "))
+		}
+	}
+
+	resp := &VerifyDeterminismResponse{
+		Passed:  true,
+		Results: make([]DeterminismToolResult, 0, len(tools)),
+	}
+
+	for _, tool := range tools {
+		result := e.checkToolDeterminism(ctx, tool, determinismRunners[tool])
+		resp.Results = append(resp.Results, result)
+		if result.Deterministic {
+			continue
+		}
+		resp.Passed = false
+		if result.Error == "" && resp.FirstDiff == nil {
+			first := result
+			resp.FirstDiff = &first
+		}
+	}
+
+	return resp, nil
+}
+
+// checkToolDeterminism runs a single tool twice and compares the results.
+func (e *Engine) checkToolDeterminism(ctx context.Context, tool string, run determinismRunner) DeterminismToolResult {
+	result := DeterminismToolResult{Tool: tool}
+
+	first, err := encodeDeterminismRun(ctx, e, run)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	second, err := encodeDeterminismRun(ctx, e, run)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	equal, _ := output.CompareSnapshots(first, second)
+	if equal {
+		result.Deterministic = true
+		return result
+	}
+
+	field, err := output.FirstDifference(first, second)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.FirstDiffField = field
+	return result
+}
+
+func encodeDeterminismRun(ctx context.Context, e *Engine, run determinismRunner) ([]byte, error) {
+	resp, err := run(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resp)
+}
@@ -0,0 +1,81 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestVerifyDeterminism(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	resp, err := engine.VerifyDeterminism(ctx, VerifyDeterminismOptions{
+		Tools: []string{"listKeyConcepts", "getModuleOverview"},
+	})
+	if err != nil {
+		t.Fatalf("VerifyDeterminism failed: %v", err)
+	}
+
+	if len(resp.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(resp.Results))
+	}
+	if resp.Results[0].Tool != "listKeyConcepts" || resp.Results[1].Tool != "getModuleOverview" {
+		t.Errorf("results not in requested order: %+v", resp.Results)
+	}
+	if !resp.Passed {
+		t.Errorf("expected determinism check to pass, first diff: %+v", resp.FirstDiff)
+	}
+}
+
+func TestVerifyDeterminismUnknownTool(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	_, err := engine.VerifyDeterminism(context.Background(), VerifyDeterminismOptions{
+		Tools: []string{"noSuchTool"},
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown tool")
+	}
+}
+
+func TestVerifyDeterminismAllToolsFail(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	determinismRunners["alwaysFails"] = func(ctx context.Context, e *Engine) (interface{}, error) {
+		return nil, errors.New("backend unavailable")
+	}
+	defer delete(determinismRunners, "alwaysFails")
+
+	resp, err := engine.VerifyDeterminism(context.Background(), VerifyDeterminismOptions{
+		Tools: []string{"alwaysFails"},
+	})
+	if err != nil {
+		t.Fatalf("VerifyDeterminism failed: %v", err)
+	}
+
+	if resp.Passed {
+		t.Error("expected determinism check to fail when no tool could run")
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Error != "backend unavailable" {
+		t.Errorf("expected the tool error to be reported, got %+v", resp.Results)
+	}
+	if resp.FirstDiff != nil {
+		t.Errorf("a tool error is not a difference, got %+v", resp.FirstDiff)
+	}
+}
+
+func TestDeterminismToolNamesCoverDefaults(t *testing.T) {
+	available := make(map[string]bool)
+	for _, name := range DeterminismToolNames() {
+		available[name] = true
+	}
+	for _, name := range DefaultDeterminismTools {
+		if !available[name] {
+			t.Errorf("default tool %q has no runner", name)
+		}
+	}
+}
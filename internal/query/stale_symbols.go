@@ -0,0 +1,326 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"ckb/internal/backends"
+	"ckb/internal/errors"
+	"ckb/internal/output"
+)
+
+// Staleness concern levels, from most to least worrying.
+const (
+	StaleConcerning = "concerning" // Used, in a module that keeps changing around it
+	StaleWatch      = "watch"      // Some usage or some module activity
+	StaleStable     = "stable"     // Unused, in a quiet module: likely just finished
+)
+
+const (
+	defaultStaleDays  = 365
+	defaultStaleLimit = 20
+
+	// maxStaleFiles bounds how many files are blamed per request. The most
+	// used files go first.
+	maxStaleFiles = 200
+
+	// A module with this many commits in the window counts as fully volatile;
+	// one with at most quietModuleCommits counts as quiet.
+	volatileModuleCommits = 20
+	quietModuleCommits    = 3
+
+	// Callers (or references) at which usage counts as heavy.
+	heavyUsageCount = 20
+)
+
+// GetStaleSymbolsOptions contains options for getStaleSymbols.
+type GetStaleSymbolsOptions struct {
+	Scope     string // Path prefix; empty for the whole repo
+	StaleDays int    // Symbols unmodified for longer are stale (default 365)
+	Limit     int    // Max symbols returned (default 20)
+}
+
+// GetStaleSymbolsResponse is the response for getStaleSymbols.
+type GetStaleSymbolsResponse struct {
+	StaleDays      int                `json:"staleDays"`
+	Cutoff         string             `json:"cutoff"`
+	Symbols        []StaleSymbol      `json:"symbols"`
+	ScannedSymbols int                `json:"scannedSymbols"`
+	StaleCount     int                `json:"staleCount"`
+	Truncated      bool               `json:"truncated,omitempty"`
+	Limitations    []string           `json:"limitations,omitempty"`
+	Provenance     *Provenance        `json:"provenance"`
+	Drilldowns     []output.Drilldown `json:"drilldowns,omitempty"`
+}
+
+// StaleSymbol is a symbol whose lines haven't changed since the cutoff.
+type StaleSymbol struct {
+	StableId          string  `json:"stableId"`
+	Name              string  `json:"name"`
+	Kind              string  `json:"kind"`
+	FileId            string  `json:"fileId"`
+	Line              int     `json:"line"`
+	LastModified      string  `json:"lastModified"`
+	DaysSinceModified int     `json:"daysSinceModified"`
+	CallerCount       int     `json:"callerCount"`
+	ReferenceCount    int     `json:"referenceCount"`
+	ModuleCommits     int     `json:"moduleCommits"` // Commits to the symbol's directory within the window
+	Concern           string  `json:"concern"`       // concerning, watch, stable
+	Score             float64 `json:"score"`         // 0-1, higher is more concerning
+}
+
+// staleCandidate is a symbol considered by GetStaleSymbols.
+type staleCandidate struct {
+	sym     *backends.SymbolResult
+	callers int
+	refs    int
+}
+
+// usage is what the ranking counts: callers for callables, references
+// for everything else.
+func (c staleCandidate) usage() int {
+	if isCallableKind(c.sym.Kind) {
+		return c.callers
+	}
+	return c.refs
+}
+
+// GetStaleSymbols finds symbols whose lines, according to git blame, were
+// all last modified before the staleness cutoff. Stale symbols that are
+// heavily used and sit in a directory still seeing commits rank first;
+// unused code in a quiet directory is reported as stable.
+func (e *Engine) GetStaleSymbols(ctx context.Context, opts GetStaleSymbolsOptions) (*GetStaleSymbolsResponse, error) {
+	startTime := time.Now()
+
+	if opts.StaleDays <= 0 {
+		opts.StaleDays = defaultStaleDays
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultStaleLimit
+	}
+
+	if e.scipAdapter == nil || !e.scipAdapter.IsAvailable() {
+		return nil, errors.NewCkbError(
+			errors.IndexMissing,
+			"getStaleSymbols needs a SCIP index to enumerate symbols and count their callers",
+			nil,
+			[]errors.FixAction{{Type: errors.RunCommand, Command: "ckb index", Safe: true, Description: "Build the SCIP index"}},
+			nil,
+		)
+	}
+	if e.gitAdapter == nil || !e.gitAdapter.IsAvailable() {
+		return nil, errors.NewCkbError(errors.BackendUnavailable, "getStaleSymbols needs git history", nil, nil, nil)
+	}
+
+	repoState, err := e.GetRepoState(ctx, "full")
+	if err != nil {
+		return nil, e.wrapError(err, errors.InternalError)
+	}
+
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -opts.StaleDays)
+	var limitations []string
+
+	moduleCommits, err := e.gitAdapter.GetDirectoryCommitCounts(fmt.Sprintf("%d days ago", opts.StaleDays))
+	if err != nil {
+		limitations = append(limitations, "Module activity unavailable; ranking uses usage only")
+		moduleCommits = map[string]int{}
+	}
+
+	// Group candidate symbols by file; each file is blamed once
+	scope := strings.Trim(strings.TrimPrefix(opts.Scope, "./"), "/")
+	scipPaths := e.scipPaths()
+	byFile := make(map[string][]staleCandidate)
+	fileUsage := make(map[string]int)
+	for _, info := range e.scipAdapter.AllSymbols() {
+		sym, err := e.scipAdapter.GetSymbol(ctx, info.Symbol)
+		if err != nil || sym == nil || sym.Location.Path == "" || sym.Location.Line <= 0 {
+			continue
+		}
+		switch strings.ToLower(sym.Kind) {
+		case "parameter", "local", "unknown":
+			continue
+		}
+		file := scipPaths.canonical(sym.Location.Path)
+		if scope != "" && file != scope && !strings.HasPrefix(file, scope+"/") {
+			continue
+		}
+		if isTestFilePath(file) || isGeneratedFilePath(file) {
+			continue
+		}
+
+		c := staleCandidate{
+			sym:     sym,
+			callers: e.scipAdapter.GetCallerCount(sym.StableID),
+			refs:    e.scipAdapter.GetReferenceCount(sym.StableID),
+		}
+		byFile[file] = append(byFile[file], c)
+		fileUsage[file] += c.usage()
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if fileUsage[files[i]] != fileUsage[files[j]] {
+			return fileUsage[files[i]] > fileUsage[files[j]]
+		}
+		return files[i] < files[j]
+	})
+	filesTruncated := len(files) > maxStaleFiles
+	if filesTruncated {
+		limitations = append(limitations, fmt.Sprintf("Only the %d most-used files were checked; narrow the scope to see the rest", maxStaleFiles))
+		files = files[:maxStaleFiles]
+	}
+
+	var stale []StaleSymbol
+	scanned := 0
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		times, err := e.gitAdapter.GetLineAuthorTimes(file)
+		if err != nil || len(times) == 0 {
+			continue
+		}
+
+		candidates := byFile[file]
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].sym.Location.Line < candidates[j].sym.Location.Line
+		})
+		starts := make([]int, len(candidates))
+		for i, c := range candidates {
+			starts[i] = c.sym.Location.Line
+		}
+
+		commits := moduleCommits[path.Dir(file)]
+		for i, c := range candidates {
+			scanned++
+			last, ok := lastModified(times, starts[i], symbolExtentEnd(starts, i, len(times)))
+			if !ok || !last.Before(cutoff) {
+				continue
+			}
+			score, concern := staleConcern(c.usage(), commits)
+			stale = append(stale, StaleSymbol{
+				StableId:          c.sym.StableID,
+				Name:              c.sym.Name,
+				Kind:              c.sym.Kind,
+				FileId:            file,
+				Line:              c.sym.Location.Line,
+				LastModified:      last.UTC().Format(time.RFC3339),
+				DaysSinceModified: int(now.Sub(last).Hours() / 24),
+				CallerCount:       c.callers,
+				ReferenceCount:    c.refs,
+				ModuleCommits:     commits,
+				Concern:           concern,
+				Score:             score,
+			})
+		}
+	}
+
+	sortStaleSymbols(stale)
+	staleCount := len(stale)
+	truncated := filesTruncated
+	if len(stale) > opts.Limit {
+		truncated = true
+		stale = stale[:opts.Limit]
+	}
+	if stale == nil {
+		stale = []StaleSymbol{}
+	}
+
+	completeness := CompletenessInfo{Score: 1.0, Reason: "full-backend"}
+	if filesTruncated {
+		completeness = CompletenessInfo{Score: 0.8, Reason: "max-files"}
+	}
+	provenance := e.buildProvenance(ctx, repoState, "full", startTime, []BackendContribution{
+		{BackendId: "scip", Available: true, Used: true, ResultCount: scanned, Completeness: completeness.Score},
+		{BackendId: "git", Available: true, Used: true, ResultCount: len(files), Completeness: completeness.Score},
+	}, completeness)
+
+	var drilldowns []output.Drilldown
+	for i, s := range stale {
+		if i >= 3 || s.Concern != StaleConcerning {
+			break
+		}
+		drilldowns = append(drilldowns, output.Drilldown{
+			Label:          fmt.Sprintf("Explain %s", s.Name),
+			Query:          fmt.Sprintf("explainSymbol %s", s.StableId),
+			Tool:           "explainSymbol",
+			Params:         map[string]interface{}{"symbolId": s.StableId},
+			RelevanceScore: 0.9 - float64(i)*0.05,
+		})
+	}
+
+	return &GetStaleSymbolsResponse{
+		StaleDays:      opts.StaleDays,
+		Cutoff:         cutoff.UTC().Format(time.RFC3339),
+		Symbols:        stale,
+		ScannedSymbols: scanned,
+		StaleCount:     staleCount,
+		Truncated:      truncated,
+		Limitations:    limitations,
+		Provenance:     provenance,
+		Drilldowns:     drilldowns,
+	}, nil
+}
+
+// lastModified returns the latest blame time over lines start..end
+// (1-based, inclusive).
+func lastModified(times []time.Time, start, end int) (time.Time, bool) {
+	if start < 1 || start > len(times) {
+		return time.Time{}, false
+	}
+	if end > len(times) {
+		end = len(times)
+	}
+	var last time.Time
+	for _, t := range times[start-1 : end] {
+		if t.After(last) {
+			last = t
+		}
+	}
+	return last, !last.IsZero()
+}
+
+// staleConcern scores how worrying a stale symbol is. Usage weighs most:
+// stale code nobody calls costs little. Activity in the surrounding module
+// adds to it, since code left untouched while its neighbours change is
+// the likeliest to have drifted.
+func staleConcern(usage, moduleCommits int) (float64, string) {
+	usageFactor := math.Min(1, math.Log1p(float64(usage))/math.Log1p(heavyUsageCount))
+	activity := math.Min(1, float64(moduleCommits)/volatileModuleCommits)
+	score := math.Round((0.6*usageFactor+0.4*activity)*100) / 100
+
+	switch {
+	case usage == 0 && moduleCommits <= quietModuleCommits:
+		return score, StaleStable
+	case usage > 0 && moduleCommits > quietModuleCommits && score >= 0.5:
+		return score, StaleConcerning
+	default:
+		return score, StaleWatch
+	}
+}
+
+// sortStaleSymbols orders by concern score, then usage, then age.
+func sortStaleSymbols(symbols []StaleSymbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		a, b := symbols[i], symbols[j]
+		if !floatEqual(a.Score, b.Score) {
+			return a.Score > b.Score
+		}
+		if a.CallerCount+a.ReferenceCount != b.CallerCount+b.ReferenceCount {
+			return a.CallerCount+a.ReferenceCount > b.CallerCount+b.ReferenceCount
+		}
+		if a.DaysSinceModified != b.DaysSinceModified {
+			return a.DaysSinceModified > b.DaysSinceModified
+		}
+		return a.StableId < b.StableId
+	})
+}
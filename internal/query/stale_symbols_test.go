@@ -0,0 +1,81 @@
+package query
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastModified(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	times := []time.Time{day(5), day(1), day(9), day(2)}
+
+	tests := []struct {
+		name       string
+		start, end int
+		want       time.Time
+		wantOK     bool
+	}{
+		{"single line", 2, 2, day(1), true},
+		{"range takes latest", 1, 4, day(9), true},
+		{"end clamped", 3, 10, day(9), true},
+		{"start out of range", 5, 6, time.Time{}, false},
+		{"start zero", 0, 2, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := lastModified(times, tt.start, tt.end)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("lastModified(%d, %d) = %v, %v; want %v, %v", tt.start, tt.end, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestStaleConcern(t *testing.T) {
+	tests := []struct {
+		name          string
+		usage         int
+		moduleCommits int
+		want          string
+	}{
+		{"unused in quiet module", 0, 1, StaleStable},
+		{"unused in busy module", 0, 30, StaleWatch},
+		{"used in quiet module", 50, 0, StaleWatch},
+		{"heavily used in busy module", 50, 25, StaleConcerning},
+		{"lightly used in busy module", 1, 10, StaleWatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, concern := staleConcern(tt.usage, tt.moduleCommits)
+			if concern != tt.want {
+				t.Errorf("staleConcern(%d, %d) = %q (score %.2f), want %q", tt.usage, tt.moduleCommits, concern, score, tt.want)
+			}
+			if score < 0 || score > 1 {
+				t.Errorf("score %.2f out of range", score)
+			}
+		})
+	}
+
+	low, _ := staleConcern(2, 5)
+	high, _ := staleConcern(40, 5)
+	if low >= high {
+		t.Errorf("more usage should score higher: %.2f >= %.2f", low, high)
+	}
+}
+
+func TestSortStaleSymbols(t *testing.T) {
+	symbols := []StaleSymbol{
+		{StableId: "c", Score: 0.4, CallerCount: 1, DaysSinceModified: 400},
+		{StableId: "a", Score: 0.9, CallerCount: 1},
+		{StableId: "d", Score: 0.4, CallerCount: 1, DaysSinceModified: 900},
+		{StableId: "b", Score: 0.4, CallerCount: 5},
+	}
+	sortStaleSymbols(symbols)
+
+	want := []string{"a", "b", "d", "c"}
+	for i, id := range want {
+		if symbols[i].StableId != id {
+			t.Fatalf("position %d: got %s, want %s", i, symbols[i].StableId, id)
+		}
+	}
+}
@@ -37,6 +37,17 @@ type CacheStatus struct {
 	ViewsCached   int     `json:"viewsCached"`
 	HitRate       float64 `json:"hitRate"`
 	SizeBytes     int64   `json:"sizeBytes"`
+
+	CallGraph *CallGraphCacheStatus `json:"callGraph,omitempty"`
+}
+
+// CallGraphCacheStatus describes the in-memory call graph cache.
+type CallGraphCacheStatus struct {
+	Entries  int     `json:"entries"`
+	Capacity int     `json:"capacity"`
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRate  float64 `json:"hitRate"`
 }
 
 // GetStatus returns the current system status.
@@ -65,6 +76,9 @@ func (e *Engine) GetStatus(ctx context.Context) (*StatusResponse, error) {
 
 	// Get cache status
 	cacheStatus := e.getCacheStatus()
+	if e.callGraphCache != nil {
+		cacheStatus.CallGraph = e.callGraphCache.status()
+	}
 
 	// Get tier info
 	tierInfo := e.GetTierInfo()
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Status output formats.
+const (
+	StatusFormatJSON       = "json"
+	StatusFormatPrometheus = "prometheus"
+)
+
+// Prometheus renders the status in the Prometheus text exposition format.
+// Every value comes from the response itself, so the two formats always
+// agree. Metric names carry a ckb_status_ prefix to stay clear of the
+// server's /metrics collector.
+func (r *StatusResponse) Prometheus() string {
+	var b strings.Builder
+
+	writeStatusMetric(&b, "ckb_status_info", "gauge", "CKB version reporting this status",
+		statusSample{labels: `version="` + escapeLabelValue(r.CkbVersion) + `"`, value: 1})
+	writeStatusMetric(&b, "ckb_status_healthy", "gauge", "Whether every available backend is healthy (1) or not (0)",
+		statusSample{value: boolGauge(r.Healthy)})
+
+	available := make([]statusSample, 0, len(r.Backends))
+	healthy := make([]statusSample, 0, len(r.Backends))
+	for _, bs := range r.Backends {
+		labels := `backend="` + escapeLabelValue(bs.Id) + `"`
+		available = append(available, statusSample{labels: labels, value: boolGauge(bs.Available)})
+		healthy = append(healthy, statusSample{labels: labels, value: boolGauge(bs.Healthy)})
+	}
+	writeStatusMetric(&b, "ckb_status_backend_available", "gauge", "Whether the backend is available (1) or not (0)", available...)
+	writeStatusMetric(&b, "ckb_status_backend_healthy", "gauge", "Whether the backend is healthy (1) or not (0)", healthy...)
+
+	if r.RepoState != nil {
+		writeStatusMetric(&b, "ckb_status_repo_dirty", "gauge", "Whether the working tree has uncommitted changes (1) or not (0)",
+			statusSample{value: boolGauge(r.RepoState.Dirty)})
+	}
+
+	if c := r.Cache; c != nil {
+		writeStatusMetric(&b, "ckb_status_cache_size_bytes", "gauge", "Size of cached query results in bytes",
+			statusSample{value: float64(c.SizeBytes)})
+		writeStatusMetric(&b, "ckb_status_cache_hit_rate", "gauge", "Query cache hit rate since startup (0-1)",
+			statusSample{value: c.HitRate})
+		writeStatusMetric(&b, "ckb_status_cache_queries_cached", "gauge", "Number of cached query results",
+			statusSample{value: float64(c.QueriesCached)})
+		writeStatusMetric(&b, "ckb_status_cache_views_cached", "gauge", "Number of cached views",
+			statusSample{value: float64(c.ViewsCached)})
+
+		if cg := c.CallGraph; cg != nil {
+			writeStatusMetric(&b, "ckb_status_callgraph_cache_entries", "gauge", "Number of call graphs in the in-memory cache",
+				statusSample{value: float64(cg.Entries)})
+			writeStatusMetric(&b, "ckb_status_callgraph_cache_capacity", "gauge", "Capacity of the in-memory call graph cache",
+				statusSample{value: float64(cg.Capacity)})
+			writeStatusMetric(&b, "ckb_status_callgraph_cache_hits_total", "counter", "Call graph cache hits since startup",
+				statusSample{value: float64(cg.Hits)})
+			writeStatusMetric(&b, "ckb_status_callgraph_cache_misses_total", "counter", "Call graph cache misses since startup",
+				statusSample{value: float64(cg.Misses)})
+		}
+	}
+
+	writeStatusMetric(&b, "ckb_status_query_duration_seconds", "gauge", "Time taken to collect this status",
+		statusSample{value: float64(r.QueryDurationMs) / 1000})
+
+	return b.String()
+}
+
+// statusSample is one sample of a status metric; labels is the rendered
+// label set without braces.
+type statusSample struct {
+	labels string
+	value  float64
+}
+
+// writeStatusMetric writes a metric's HELP and TYPE lines and its samples.
+func writeStatusMetric(b *strings.Builder, name, typ, help string, samples ...statusSample) {
+	if len(samples) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, typ)
+	for _, s := range samples {
+		value := strconv.FormatFloat(s.value, 'g', -1, 64)
+		if s.labels != "" {
+			fmt.Fprintf(b, "%s{%s} %s\n", name, s.labels, value)
+		} else {
+			fmt.Fprintf(b, "%s %s\n", name, value)
+		}
+	}
+}
+
+func boolGauge(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// escapeLabelValue escapes a label value for the text exposition format.
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
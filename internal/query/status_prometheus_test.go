@@ -0,0 +1,58 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatusResponsePrometheus(t *testing.T) {
+	resp := &StatusResponse{
+		CkbVersion: `7.0 "dev"`,
+		Healthy:    true,
+		RepoState:  &RepoState{Dirty: true},
+		Backends: []BackendStatus{
+			{Id: "scip", Available: true, Healthy: true},
+			{Id: "lsp"},
+		},
+		Cache: &CacheStatus{
+			QueriesCached: 12,
+			ViewsCached:   3,
+			HitRate:       0.75,
+			SizeBytes:     4096,
+			CallGraph:     &CallGraphCacheStatus{Entries: 2, Capacity: 64, Hits: 9, Misses: 1},
+		},
+		QueryDurationMs: 1500,
+	}
+
+	out := resp.Prometheus()
+
+	for _, want := range []string{
+		`ckb_status_info{version="7.0 \"dev\""} 1`,
+		"# HELP ckb_status_healthy ",
+		"# TYPE ckb_status_healthy gauge\nckb_status_healthy 1\n",
+		`ckb_status_backend_available{backend="scip"} 1`,
+		`ckb_status_backend_available{backend="lsp"} 0`,
+		`ckb_status_backend_healthy{backend="lsp"} 0`,
+		"ckb_status_repo_dirty 1\n",
+		"ckb_status_cache_size_bytes 4096\n",
+		"ckb_status_cache_hit_rate 0.75\n",
+		"ckb_status_cache_queries_cached 12\n",
+		"# TYPE ckb_status_callgraph_cache_hits_total counter\nckb_status_callgraph_cache_hits_total 9\n",
+		"ckb_status_query_duration_seconds 1.5\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	// Each metric is declared once, before its samples
+	if n := strings.Count(out, "# TYPE ckb_status_backend_available "); n != 1 {
+		t.Errorf("backend_available declared %d times", n)
+	}
+
+	// Without a cache or repo state those metrics are left out
+	bare := (&StatusResponse{}).Prometheus()
+	if strings.Contains(bare, "ckb_status_cache_") || strings.Contains(bare, "ckb_status_repo_dirty") {
+		t.Errorf("unexpected metrics without cache or repo state:\n%s", bare)
+	}
+}
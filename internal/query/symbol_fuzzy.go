@@ -0,0 +1,137 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ckb/internal/errors"
+)
+
+const (
+	// fuzzySymbolSearchLimit bounds the search run when a symbol ID fails
+	// to resolve.
+	fuzzySymbolSearchLimit = 20
+
+	// fuzzyResolveConfidence is reported for a symbol resolved by name
+	// rather than by ID.
+	fuzzyResolveConfidence = 0.6
+
+	// maxFuzzySuggestions caps the candidates listed in an ambiguity error.
+	maxFuzzySuggestions = 10
+)
+
+// FuzzyCandidate is a symbol that matched the name of an unresolvable ID.
+type FuzzyCandidate struct {
+	StableId string `json:"stableId"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind,omitempty"`
+	FilePath string `json:"filePath,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// getSymbolFuzzy is GetSymbol's last resort when the ID doesn't resolve:
+// it searches for the ID's trailing name component and, when exactly one
+// symbol has that name, resolves to it with ResolvedFrom "fuzzy". Several
+// matches produce a SymbolNotFound error listing them. ok is false when
+// nothing matched, so the caller reports the original error.
+func (e *Engine) getSymbolFuzzy(ctx context.Context, opts GetSymbolOptions) (*GetSymbolResponse, bool, error) {
+	name := trailingSymbolName(opts.SymbolId)
+	if name == "" {
+		return nil, false, nil
+	}
+
+	search, err := e.SearchSymbols(ctx, SearchSymbolsOptions{
+		Query:          name,
+		Limit:          fuzzySymbolSearchLimit,
+		IncludePrivate: true,
+	})
+	if err != nil || search == nil {
+		return nil, false, nil
+	}
+
+	candidates := fuzzyCandidates(search.Symbols, opts.SymbolId)
+	switch len(candidates) {
+	case 0:
+		return nil, false, nil
+	case 1:
+		exact := opts
+		exact.SymbolId = candidates[0].StableId
+		resp, err := e.GetSymbol(ctx, exact)
+		if err != nil {
+			return nil, false, nil
+		}
+		resp.ResolvedFrom = "fuzzy"
+		resp.Confidence = fuzzyResolveConfidence
+		return resp, true, nil
+	default:
+		return nil, true, ambiguousSymbolError(opts.SymbolId, name, candidates)
+	}
+}
+
+// trailingSymbolName extracts the last name component of a symbol ID, so
+// "pkg/query.Engine#GetSymbol()." and "Engine.GetSymbol" both yield
+// "GetSymbol". A bare name is returned unchanged.
+func trailingSymbolName(id string) string {
+	name := strings.TrimRight(strings.TrimSpace(id), ".#:/`")
+	// Drop a trailing parameter list or SCIP overload disambiguator
+	if strings.HasSuffix(name, ")") {
+		if i := strings.LastIndex(name, "("); i >= 0 {
+			name = strings.TrimRight(name[:i], ".#:/`")
+		}
+	}
+	if i := strings.LastIndexAny(name, ".#:/` "); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// fuzzyCandidates keeps the search results whose name matches the query
+// exactly, dropping the unresolved ID itself and duplicate IDs.
+func fuzzyCandidates(results []SearchResultItem, unresolvedId string) []FuzzyCandidate {
+	seen := make(map[string]bool)
+	var candidates []FuzzyCandidate
+	for _, r := range results {
+		if r.Confidence < 1.0 || r.StableId == "" || r.StableId == unresolvedId || seen[r.StableId] {
+			continue
+		}
+		seen[r.StableId] = true
+		c := FuzzyCandidate{StableId: r.StableId, Name: r.Name, Kind: r.Kind}
+		if r.Location != nil {
+			c.FilePath = r.Location.FileId
+			c.Line = r.Location.StartLine
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+// ambiguousSymbolError reports an unresolvable ID whose name matches
+// several symbols. The candidates are part of the message so clients that
+// only show the error text can still pick one.
+func ambiguousSymbolError(id, name string, candidates []FuzzyCandidate) *errors.CkbError {
+	shown := candidates
+	if len(shown) > maxFuzzySuggestions {
+		shown = shown[:maxFuzzySuggestions]
+	}
+
+	suggestions := make([]string, len(shown))
+	drilldowns := make([]errors.Drilldown, len(shown))
+	for i, c := range shown {
+		label := c.StableId
+		if c.FilePath != "" {
+			label = fmt.Sprintf("%s (%s:%d)", c.StableId, c.FilePath, c.Line)
+		}
+		suggestions[i] = label
+		drilldowns[i] = errors.Drilldown{
+			Label: label,
+			Query: fmt.Sprintf("getSymbol %s", c.StableId),
+		}
+	}
+	msg := fmt.Sprintf("symbol %q not found and %d symbols are named %q; use one of: %s",
+		id, len(candidates), name, strings.Join(suggestions, ", "))
+	if len(candidates) > len(shown) {
+		msg += fmt.Sprintf(" (and %d more)", len(candidates)-len(shown))
+	}
+	return errors.NewCkbError(errors.SymbolNotFound, msg, nil, nil, drilldowns).WithDetails(candidates)
+}
@@ -12,43 +12,59 @@ import (
 	"time"
 
 	"ckb/internal/backends"
+	"ckb/internal/backends/scip"
+	"ckb/internal/backends/treesitter"
 	"ckb/internal/compression"
 	"ckb/internal/errors"
 	"ckb/internal/output"
+	"ckb/internal/symbols"
 )
 
 // GetSymbolOptions contains options for getSymbol.
 type GetSymbolOptions struct {
 	SymbolId      string
 	RepoStateMode string // "head" or "full"
+	IncludeSource bool   // Attach the source around the definition
+	ContextLines  int    // Lines of context on each side of the definition line (default: 5, max: 50)
 }
 
+const (
+	defaultSymbolSourceContext = 5
+	maxSymbolSourceContext     = 50
+)
+
 // GetSymbolResponse is the response for getSymbol.
 type GetSymbolResponse struct {
 	Symbol         *SymbolInfo        `json:"symbol,omitempty"`
 	Redirected     bool               `json:"redirected,omitempty"`
 	RedirectedFrom string             `json:"redirectedFrom,omitempty"`
 	RedirectReason string             `json:"redirectReason,omitempty"`
+	ResolvedFrom   string             `json:"resolvedFrom,omitempty"` // "fingerprint-fallback" when the ID was stale, "fuzzy" when resolved by name
+	Confidence     float64            `json:"confidence,omitempty"`   // Resolution confidence, set for fallbacks
 	Deleted        bool               `json:"deleted,omitempty"`
 	DeletedAt      string             `json:"deletedAt,omitempty"`
+	SourceSnippet  *SourceContext     `json:"sourceSnippet,omitempty"` // Set with IncludeSource when the file is readable
 	Provenance     *Provenance        `json:"provenance"`
 	Drilldowns     []output.Drilldown `json:"drilldowns,omitempty"`
 }
 
 // SymbolInfo contains symbol metadata.
 type SymbolInfo struct {
-	StableId            string          `json:"stableId"`
-	Name                string          `json:"name"`
-	Kind                string          `json:"kind"`
-	Signature           string          `json:"signature,omitempty"`
-	SignatureNormalized string          `json:"signatureNormalized,omitempty"`
-	Visibility          *VisibilityInfo `json:"visibility"`
-	ModuleId            string          `json:"moduleId"`
-	ModuleName          string          `json:"moduleName,omitempty"`
-	ContainerName       string          `json:"containerName,omitempty"`
-	Location            *LocationInfo   `json:"location"`
-	LocationFreshness   string          `json:"locationFreshness"`
-	Documentation       string          `json:"documentation,omitempty"`
+	StableId            string                   `json:"stableId"`
+	Name                string                   `json:"name"`
+	Kind                string                   `json:"kind"`
+	Signature           string                   `json:"signature,omitempty"`
+	SignatureNormalized string                   `json:"signatureNormalized,omitempty"`
+	ParsedSignature     *symbols.ParsedSignature `json:"parsedSignature,omitempty"` // Nil when the signature couldn't be parsed
+	Visibility          *VisibilityInfo          `json:"visibility"`
+	ModuleId            string                   `json:"moduleId"`
+	ModuleName          string                   `json:"moduleName,omitempty"`
+	ContainerName       string                   `json:"containerName,omitempty"`
+	Location            *LocationInfo            `json:"location"`
+	AdditionalLocations []LocationInfo           `json:"additionalLocations,omitempty"` // Other definition sites (partial classes)
+	LocationFreshness   string                   `json:"locationFreshness"`
+	Documentation       string                   `json:"documentation,omitempty"`
+	TestOnlyExport      bool                     `json:"testOnlyExport,omitempty"` // Public, but only referenced from tests
 }
 
 // VisibilityInfo describes symbol visibility.
@@ -67,11 +83,56 @@ type LocationInfo struct {
 	EndColumn   int    `json:"endColumn,omitempty"`
 }
 
+// parseSignature parses a symbol's signature in the language of its file.
+func parseSignature(signature, path string) *symbols.ParsedSignature {
+	if signature == "" {
+		return nil
+	}
+	return symbols.ParseSignature(signature, detectLanguage(path))
+}
+
+// convertAdditionalLocations converts backend definition sites to LocationInfo.
+func convertAdditionalLocations(locs []backends.Location) []LocationInfo {
+	if len(locs) == 0 {
+		return nil
+	}
+	result := make([]LocationInfo, 0, len(locs))
+	for _, loc := range locs {
+		result = append(result, LocationInfo{
+			FileId:      loc.Path,
+			StartLine:   loc.Line,
+			StartColumn: loc.Column,
+			EndLine:     loc.EndLine,
+			EndColumn:   loc.EndColumn,
+		})
+	}
+	return result
+}
+
+// definitionFiles returns the distinct files holding a symbol's definitions,
+// primary location first.
+func (s *SymbolInfo) definitionFiles() []string {
+	var files []string
+	seen := make(map[string]bool)
+	if s.Location != nil && s.Location.FileId != "" {
+		files = append(files, s.Location.FileId)
+		seen[s.Location.FileId] = true
+	}
+	for _, loc := range s.AdditionalLocations {
+		if loc.FileId != "" && !seen[loc.FileId] {
+			files = append(files, loc.FileId)
+			seen[loc.FileId] = true
+		}
+	}
+	return files
+}
+
 // TruncationInfo describes why results were truncated.
 type TruncationInfo struct {
 	Reason        string `json:"reason"`
 	OriginalCount int    `json:"originalCount"`
 	ReturnedCount int    `json:"returnedCount"`
+	Note          string `json:"note,omitempty"`
 }
 
 // GetSymbol retrieves symbol information by ID.
@@ -89,6 +150,15 @@ func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymb
 		return nil, e.wrapError(err, errors.InternalError)
 	}
 
+	// Tree-sitter IDs name their file, which is parsed on demand
+	if strings.HasPrefix(opts.SymbolId, treesitter.IDPrefix) {
+		resp, err := e.getSymbolFromTreesitter(ctx, opts, repoState, startTime)
+		if err == nil {
+			e.attachSymbolSource(resp, opts)
+		}
+		return resp, err
+	}
+
 	// Resolve symbol ID through aliases
 	resolved, err := e.resolver.ResolveSymbolId(opts.SymbolId)
 	if err != nil {
@@ -109,13 +179,14 @@ func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymb
 					ResultCount:  1,
 					Completeness: result.Completeness.Score,
 				}}
-				return &GetSymbolResponse{
+				resp := &GetSymbolResponse{
 					Symbol: &SymbolInfo{
 						StableId:            result.StableID,
 						Name:                result.Name,
 						Kind:                result.Kind,
 						Signature:           result.SignatureFull,
 						SignatureNormalized: result.SignatureNormalized,
+						ParsedSignature:     parseSignature(result.SignatureFull, result.Location.Path),
 						ContainerName:       result.ContainerName,
 						ModuleId:            result.ModuleID,
 						Documentation:       result.Documentation,
@@ -132,23 +203,46 @@ func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymb
 							EndLine:     result.Location.EndLine,
 							EndColumn:   result.Location.EndColumn,
 						},
+						AdditionalLocations: convertAdditionalLocations(result.AdditionalLocations),
 					},
-					Provenance: e.buildProvenance(repoState, opts.RepoStateMode, startTime, backendContribs, completeness),
+					Provenance: e.buildProvenance(ctx, repoState, opts.RepoStateMode, startTime, backendContribs, completeness),
 					Drilldowns: []output.Drilldown{
-						{Label: "Find references", Query: fmt.Sprintf("findReferences %s", opts.SymbolId)},
-						{Label: "Get call graph", Query: fmt.Sprintf("getCallGraph %s", opts.SymbolId)},
+						{
+							Label:  "Find references",
+							Query:  fmt.Sprintf("findReferences %s", opts.SymbolId),
+							Tool:   "findReferences",
+							Params: map[string]interface{}{"symbolId": opts.SymbolId},
+						},
+						{
+							Label:  "Get call graph",
+							Query:  fmt.Sprintf("getCallGraph %s", opts.SymbolId),
+							Tool:   "getCallGraph",
+							Params: map[string]interface{}{"symbolId": opts.SymbolId},
+						},
 					},
-				}, nil
+				}
+				e.attachSymbolSource(resp, opts)
+				return resp, nil
 			}
 		}
 
+		// Fall back to resolving the ID's name, when it names exactly one symbol
+		if resp, ok, fuzzyErr := e.getSymbolFuzzy(ctx, opts); ok {
+			return resp, fuzzyErr
+		}
+
 		// Check if it's a known error type
 		if ckbErr, ok := err.(*errors.CkbError); ok {
 			completeness := CompletenessInfo{Score: 0.0, Reason: "symbol-not-found"}
 			return &GetSymbolResponse{
-				Provenance: e.buildProvenance(repoState, opts.RepoStateMode, startTime, nil, completeness),
+				Provenance: e.buildProvenance(ctx, repoState, opts.RepoStateMode, startTime, nil, completeness),
 				Drilldowns: []output.Drilldown{
-					{Label: "Search for similar symbols", Query: fmt.Sprintf("searchSymbols %s", opts.SymbolId)},
+					{
+						Label:  "Search for similar symbols",
+						Query:  fmt.Sprintf("searchSymbols %s", opts.SymbolId),
+						Tool:   "searchSymbols",
+						Params: map[string]interface{}{"query": opts.SymbolId},
+					},
 				},
 			}, ckbErr
 		}
@@ -161,7 +255,7 @@ func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymb
 		return &GetSymbolResponse{
 			Deleted:    true,
 			DeletedAt:  resolved.DeletedAt,
-			Provenance: e.buildProvenance(repoState, opts.RepoStateMode, startTime, nil, completeness),
+			Provenance: e.buildProvenance(ctx, repoState, opts.RepoStateMode, startTime, nil, completeness),
 		}, nil
 	}
 
@@ -170,6 +264,10 @@ func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymb
 		Redirected:     resolved.Redirected,
 		RedirectedFrom: resolved.RedirectedFrom,
 		RedirectReason: string(resolved.RedirectReason),
+		ResolvedFrom:   resolved.ResolvedFrom,
+	}
+	if resolved.ResolvedFrom != "" {
+		response.Confidence = resolved.RedirectConfidence
 	}
 
 	var backendContribs []BackendContribution
@@ -187,6 +285,7 @@ func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymb
 					Kind:                result.Kind,
 					Signature:           result.SignatureFull,
 					SignatureNormalized: result.SignatureNormalized,
+					ParsedSignature:     parseSignature(result.SignatureFull, result.Location.Path),
 					ContainerName:       result.ContainerName,
 					ModuleId:            result.ModuleID,
 					Documentation:       result.Documentation,
@@ -203,6 +302,7 @@ func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymb
 						EndLine:     result.Location.EndLine,
 						EndColumn:   result.Location.EndColumn,
 					},
+					AdditionalLocations: convertAdditionalLocations(result.AdditionalLocations),
 				}
 				backendContribs = append(backendContribs, BackendContribution{
 					BackendId:    "scip",
@@ -246,12 +346,40 @@ func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymb
 		}
 	}
 
-	response.Provenance = e.buildProvenance(repoState, opts.RepoStateMode, startTime, backendContribs, completeness)
+	response.Provenance = e.buildProvenance(ctx, repoState, opts.RepoStateMode, startTime, backendContribs, completeness)
 	response.Drilldowns = e.generateDrilldowns(nil, completeness, opts.SymbolId, nil)
+	e.attachSymbolSource(response, opts)
 
 	return response, nil
 }
 
+// attachSymbolSource sets SourceSnippet to the lines around the symbol's
+// definition when opts.IncludeSource is set. The file is read through the
+// same repo-root check explainFile uses; a missing location or unreadable
+// file leaves the snippet unset rather than failing the lookup.
+func (e *Engine) attachSymbolSource(resp *GetSymbolResponse, opts GetSymbolOptions) {
+	if !opts.IncludeSource || resp.Symbol == nil || resp.Symbol.Location == nil {
+		return
+	}
+	loc := resp.Symbol.Location
+	if loc.FileId == "" {
+		return
+	}
+	resp.SourceSnippet = e.newSourceLineReader().excerpt(loc.FileId, loc.StartLine, symbolSourceContext(opts.ContextLines))
+}
+
+// symbolSourceContext applies the default and cap to a requested number of
+// context lines.
+func symbolSourceContext(n int) int {
+	switch {
+	case n <= 0:
+		return defaultSymbolSourceContext
+	case n > maxSymbolSourceContext:
+		return maxSymbolSourceContext
+	}
+	return n
+}
+
 // getLocationFreshness determines location freshness based on repo state.
 func (e *Engine) getLocationFreshness(repoState *RepoState) string {
 	if repoState.Dirty {
@@ -266,6 +394,12 @@ type SearchSymbolsOptions struct {
 	Scope string
 	Kinds []string
 	Limit int
+
+	// MinConfidence drops results whose match confidence is lower (0 = keep all)
+	MinConfidence float64
+
+	// IncludePrivate keeps private and internal symbols (default: true)
+	IncludePrivate bool
 }
 
 // SearchSymbolsResponse is the response for searchSymbols.
@@ -276,6 +410,9 @@ type SearchSymbolsResponse struct {
 	TruncationInfo *TruncationInfo    `json:"truncationInfo,omitempty"`
 	Provenance     *Provenance        `json:"provenance"`
 	Drilldowns     []output.Drilldown `json:"drilldowns,omitempty"`
+
+	FilteredByConfidence int `json:"filteredByConfidence,omitempty"` // Results dropped by minConfidence
+	FilteredPrivate      int `json:"filteredPrivate,omitempty"`      // Results dropped because includePrivate was off
 }
 
 // RankingV52 contains v5.2 ranking signals for auditable, deterministic ordering.
@@ -304,6 +441,7 @@ type SearchResultItem struct {
 	Location   *LocationInfo   `json:"location,omitempty"`
 	Visibility *VisibilityInfo `json:"visibility,omitempty"`
 	Score      float64         `json:"score"`
+	Confidence float64         `json:"confidence,omitempty"` // How well the name matches the query, 0-1
 	Ranking    *RankingV52     `json:"ranking,omitempty"`
 }
 
@@ -316,6 +454,12 @@ func generateSearchCacheKey(opts SearchSymbolsOptions) string {
 		opts.Scope,
 		fmt.Sprintf("%d", opts.Limit),
 	}
+	if opts.MinConfidence > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("minConfidence=%g", opts.MinConfidence))
+	}
+	if !opts.IncludePrivate {
+		keyParts = append(keyParts, "publicOnly")
+	}
 	if len(opts.Kinds) > 0 {
 		sort.Strings(opts.Kinds)
 		keyParts = append(keyParts, strings.Join(opts.Kinds, ","))
@@ -465,7 +609,7 @@ func (e *Engine) SearchSymbols(ctx context.Context, opts SearchSymbolsOptions) (
 				Reason: string(searchResult.Completeness.Reason),
 			}
 		}
-	} else if len(results) == 0 && e.treesitterExtractor != nil {
+	} else if len(results) == 0 && e.treesitterAdapter != nil {
 		// Tree-sitter fallback when SCIP not available
 		tsResults, err := e.searchWithTreesitter(ctx, opts)
 		if err == nil && len(tsResults) > 0 {
@@ -475,10 +619,10 @@ func (e *Engine) SearchSymbols(ctx context.Context, opts SearchSymbolsOptions) (
 				Available:    true,
 				Used:         true,
 				ResultCount:  len(tsResults),
-				Completeness: 0.7,
+				Completeness: 0.6,
 			})
 			completeness = CompletenessInfo{
-				Score:   0.7,
+				Score:   0.6,
 				Reason:  "treesitter-fallback",
 				Details: "Using tree-sitter analysis. Run 'ckb index' for cross-file references.",
 			}
@@ -492,12 +636,20 @@ func (e *Engine) SearchSymbols(ctx context.Context, opts SearchSymbolsOptions) (
 			Symbols:    []SearchResultItem{},
 			TotalCount: 0,
 			Truncated:  false,
-			Provenance: e.buildProvenance(repoState, "head", startTime, backendContribs, completeness),
+			Provenance: e.buildProvenance(ctx, repoState, "head", startTime, backendContribs, completeness),
 		}, nil
 	}
 
+	var filteredPrivate int
+	if !opts.IncludePrivate {
+		results, filteredPrivate = filterNonPublic(results)
+	}
+
 	// Apply ranking
 	rankSearchResults(results, opts.Query)
+	results, filteredByConfidence := filterByConfidence(results, opts.MinConfidence, func(r SearchResultItem) float64 {
+		return r.Confidence
+	})
 
 	// Sort by score
 	sort.Slice(results, func(i, j int) bool {
@@ -527,7 +679,7 @@ func (e *Engine) SearchSymbols(ctx context.Context, opts SearchSymbolsOptions) (
 	}
 
 	// Build provenance
-	provenance := e.buildProvenance(repoState, "head", startTime, backendContribs, completeness)
+	provenance := e.buildProvenance(ctx, repoState, "head", startTime, backendContribs, completeness)
 
 	// Generate drilldowns
 	var compTrunc *compression.TruncationInfo
@@ -547,6 +699,9 @@ func (e *Engine) SearchSymbols(ctx context.Context, opts SearchSymbolsOptions) (
 		TruncationInfo: truncationInfo,
 		Provenance:     provenance,
 		Drilldowns:     drilldowns,
+
+		FilteredByConfidence: filteredByConfidence,
+		FilteredPrivate:      filteredPrivate,
 	}
 
 	// Store in cache
@@ -563,6 +718,35 @@ func (e *Engine) SearchSymbols(ctx context.Context, opts SearchSymbolsOptions) (
 	return response, nil
 }
 
+// minVisibilityConfidence is how sure a visibility verdict must be before
+// it hides a symbol; less certain verdicts count as unknown.
+const minVisibilityConfidence = 0.5
+
+// filterNonPublic drops results confidently known to be private or
+// internal, keeping public symbols and those of unknown visibility. It
+// returns how many were dropped.
+func filterNonPublic(results []SearchResultItem) ([]SearchResultItem, int) {
+	kept := make([]SearchResultItem, 0, len(results))
+	for _, r := range results {
+		if !isConfidentlyNonPublic(r.Visibility) {
+			kept = append(kept, r)
+		}
+	}
+	return kept, len(results) - len(kept)
+}
+
+func isConfidentlyNonPublic(v *VisibilityInfo) bool {
+	if v == nil || v.Confidence < minVisibilityConfidence {
+		return false
+	}
+	switch strings.ToLower(v.Visibility) {
+	case "private", "internal":
+		return true
+	default:
+		return false
+	}
+}
+
 // parseScope converts scope string to slice.
 func parseScope(scope string) []string {
 	if scope == "" {
@@ -624,6 +808,7 @@ func rankSearchResults(results []SearchResultItem, query string) {
 		}
 
 		results[i].Score = score
+		results[i].Confidence = searchMatchConfidence(results[i].Name, query)
 
 		// Build v5.2 ranking signals
 		scope := ""
@@ -648,6 +833,60 @@ type FindReferencesOptions struct {
 	Scope        string
 	IncludeTests bool
 	Limit        int
+
+	// IncludeDynamicDispatch adds call sites made through interface methods
+	// the symbol implements, labeled as inferred
+	IncludeDynamicDispatch bool
+
+	// ExternalOnly keeps only references from files outside the module
+	// that defines the symbol
+	ExternalOnly bool
+
+	// Cursor resumes after the last reference of a previous page; pass the
+	// NextCursor of that response with otherwise identical options
+	Cursor string
+
+	// Kinds keeps only references of these kinds (see ReferenceKinds);
+	// empty means all kinds
+	Kinds []string
+}
+
+// ReferenceKinds lists the reference kinds FindReferencesOptions.Kinds
+// accepts: the kinds the SCIP backend assigns to occurrences.
+var ReferenceKinds = []string{
+	string(scip.RefDefinition),
+	string(scip.RefReference),
+	string(scip.RefCall),
+	string(scip.RefType),
+	string(scip.RefRead),
+	string(scip.RefWrite),
+	string(scip.RefImport),
+	string(scip.RefImplements),
+	string(scip.RefForwardDecl),
+}
+
+// referenceKindSet validates kinds against ReferenceKinds and returns
+// them as a set, or nil when kinds is empty.
+func referenceKindSet(kinds []string) (map[string]bool, error) {
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+	valid := make(map[string]bool, len(ReferenceKinds))
+	for _, kind := range ReferenceKinds {
+		valid[kind] = true
+	}
+	set := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		if !valid[kind] {
+			return nil, errors.NewCkbError(
+				errors.InvalidParameters,
+				fmt.Sprintf("unknown reference kind %q: must be one of %s", kind, strings.Join(ReferenceKinds, ", ")),
+				nil, nil, nil,
+			)
+		}
+		set[kind] = true
+	}
+	return set, nil
 }
 
 // FindReferencesResponse is the response for findReferences.
@@ -656,8 +895,12 @@ type FindReferencesResponse struct {
 	TotalCount     int                `json:"totalCount"`
 	Truncated      bool               `json:"truncated"`
 	TruncationInfo *TruncationInfo    `json:"truncationInfo,omitempty"`
+	NextCursor     string             `json:"nextCursor,omitempty"` // Set when more references follow this page
 	Provenance     *Provenance        `json:"provenance"`
 	Drilldowns     []output.Drilldown `json:"drilldowns,omitempty"`
+
+	DynamicDispatch *DynamicDispatchInfo   `json:"dynamicDispatch,omitempty"`
+	External        *ExternalReferenceInfo `json:"external,omitempty"`
 }
 
 // ReferenceInfo describes a reference to a symbol.
@@ -666,6 +909,11 @@ type ReferenceInfo struct {
 	Kind     string        `json:"kind"`
 	Context  string        `json:"context,omitempty"`
 	IsTest   bool          `json:"isTest,omitempty"`
+
+	// Set only on references inferred through dynamic dispatch
+	Dispatch   string  `json:"dispatch,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Via        string  `json:"via,omitempty"` // Interface method actually called
 }
 
 // FindReferences finds all references to a symbol.
@@ -677,6 +925,23 @@ func (e *Engine) FindReferences(ctx context.Context, opts FindReferencesOptions)
 		opts.Limit = 100
 	}
 
+	scope, err := compileScope(opts.Scope)
+	if err != nil {
+		return nil, errors.NewCkbError(errors.ScopeInvalid, err.Error(), err, nil, nil)
+	}
+
+	kinds, err := referenceKindSet(opts.Kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor *referenceCursor
+	if opts.Cursor != "" {
+		if cursor, err = decodeReferenceCursor(opts.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
 	// Get repo state (full mode for references)
 	repoState, err := e.GetRepoState(ctx, "full")
 	if err != nil {
@@ -697,20 +962,29 @@ func (e *Engine) FindReferences(ctx context.Context, opts FindReferencesOptions)
 	var backendContribs []BackendContribution
 	var completeness CompletenessInfo
 
+	// Backends return references in index order, so a page is only the
+	// head of the sorted set (and its NextCursor only lines up with the
+	// next page) when the scan isn't cut short at the limit. The wide scan
+	// also leaves room for the filters applied after the fetch.
+	maxResults := maxReferenceScan
+
 	// Query SCIP for references
 	if e.scipAdapter != nil && e.scipAdapter.IsAvailable() {
 		refOpts := backends.RefOptions{
-			MaxResults:         opts.Limit * 2,
+			MaxResults:         maxResults,
 			IncludeTests:       opts.IncludeTests,
 			IncludeDeclaration: true,
-			Scope:              parseScope(opts.Scope),
+			Scope:              backendScope(opts.Scope),
 		}
 		refsResult, err := e.scipAdapter.FindReferences(ctx, symbolIdToQuery, refOpts)
 		if err == nil && refsResult != nil {
+			// References through a symlink are reported against its target
+			// so they dedupe with references to the target itself
+			canon := newPathCanonicalizer(e.repoRoot)
 			for _, ref := range refsResult.References {
 				refs = append(refs, ReferenceInfo{
 					Location: &LocationInfo{
-						FileId:      ref.Location.Path,
+						FileId:      canon.resolve(ref.Location.Path),
 						StartLine:   ref.Location.Line,
 						StartColumn: ref.Location.Column,
 						EndLine:     ref.Location.EndLine,
@@ -734,6 +1008,53 @@ func (e *Engine) FindReferences(ctx context.Context, opts FindReferencesOptions)
 		}
 	}
 
+	// Without an index, tree-sitter symbols get name-matched references
+	// from their own file and the requested scope
+	if len(refs) == 0 && strings.HasPrefix(symbolIdToQuery, treesitter.IDPrefix) && e.treesitterAdapter != nil {
+		refsResult, err := e.treesitterAdapter.FindReferences(ctx, symbolIdToQuery, backends.RefOptions{
+			MaxResults:         maxResults,
+			IncludeTests:       opts.IncludeTests,
+			IncludeDeclaration: true,
+			Scope:              backendScope(opts.Scope),
+		})
+		if err == nil && refsResult != nil {
+			for _, ref := range refsResult.References {
+				refs = append(refs, ReferenceInfo{
+					Location: &LocationInfo{
+						FileId:      ref.Location.Path,
+						StartLine:   ref.Location.Line,
+						StartColumn: ref.Location.Column,
+						EndLine:     ref.Location.EndLine,
+						EndColumn:   ref.Location.EndColumn,
+					},
+					Kind:    ref.Kind,
+					Context: ref.Context,
+				})
+			}
+			backendContribs = append(backendContribs, BackendContribution{
+				BackendId:    "treesitter",
+				Available:    true,
+				Used:         true,
+				ResultCount:  len(refsResult.References),
+				Completeness: refsResult.Completeness.Score,
+			})
+			completeness = CompletenessInfo{
+				Score:   refsResult.Completeness.Score,
+				Reason:  "treesitter-fallback",
+				Details: "References matched by name without an index. Run 'ckb index' for precise results.",
+			}
+		}
+	}
+
+	// Calls through interfaces are recorded against the interface method;
+	// appended after direct references so direct ones win deduplication
+	var dispatchInfo *DynamicDispatchInfo
+	if opts.IncludeDynamicDispatch {
+		var inferred []ReferenceInfo
+		inferred, dispatchInfo = e.dynamicDispatchReferences(ctx, symbolIdToQuery, opts)
+		refs = append(refs, inferred...)
+	}
+
 	// If no results and symbol wasn't found in identity system, return not found
 	if len(refs) == 0 && (resolved == nil || resolved.Symbol == nil) {
 		return nil, errors.NewCkbError(
@@ -746,23 +1067,63 @@ func (e *Engine) FindReferences(ctx context.Context, opts FindReferencesOptions)
 	// Deduplicate
 	refs = deduplicateReferences(refs)
 
+	// Backends only apply prefix scopes; globs and regexes are matched here
+	if scope != nil {
+		scoped := refs[:0]
+		for _, ref := range refs {
+			if scope.Match(ref.Location.FileId) {
+				scoped = append(scoped, ref)
+			}
+		}
+		refs = scoped
+	}
+
+	if kinds != nil {
+		kept := refs[:0]
+		for _, ref := range refs {
+			if kinds[ref.Kind] {
+				kept = append(kept, ref)
+			}
+		}
+		refs = kept
+	}
+
+	var externalInfo *ExternalReferenceInfo
+	if opts.ExternalOnly {
+		refs, externalInfo = e.externalReferences(ctx, symbolIdToQuery, refs)
+	}
+
+	if dispatchInfo != nil {
+		dispatchInfo.InferredCount = 0
+		for _, ref := range refs {
+			if ref.Dispatch == DispatchDynamic {
+				dispatchInfo.InferredCount++
+			}
+		}
+	}
+
 	// Sort deterministically
 	sortReferences(refs)
 
-	// Apply limit and track truncation
+	// Apply limit and track truncation; TotalCount covers all pages
 	totalCount := len(refs)
+	if cursor != nil {
+		refs = referencesAfter(refs, cursor)
+	}
 	var truncationInfo *TruncationInfo
+	var nextCursor string
 	if len(refs) > opts.Limit {
 		truncationInfo = &TruncationInfo{
 			Reason:        "max-refs",
-			OriginalCount: totalCount,
+			OriginalCount: len(refs),
 			ReturnedCount: opts.Limit,
 		}
 		refs = refs[:opts.Limit]
+		nextCursor = encodeReferenceCursor(refs[len(refs)-1])
 	}
 
 	// Build provenance
-	provenance := e.buildProvenance(repoState, "full", startTime, backendContribs, completeness)
+	provenance := e.buildProvenance(ctx, repoState, "full", startTime, backendContribs, completeness)
 
 	// Generate drilldowns
 	var compTrunc *compression.TruncationInfo
@@ -776,12 +1137,15 @@ func (e *Engine) FindReferences(ctx context.Context, opts FindReferencesOptions)
 	drilldowns := e.generateDrilldowns(compTrunc, completeness, opts.SymbolId, nil)
 
 	return &FindReferencesResponse{
-		References:     refs,
-		TotalCount:     totalCount,
-		Truncated:      truncationInfo != nil,
-		TruncationInfo: truncationInfo,
-		Provenance:     provenance,
-		Drilldowns:     drilldowns,
+		References:      refs,
+		TotalCount:      totalCount,
+		Truncated:       truncationInfo != nil,
+		TruncationInfo:  truncationInfo,
+		NextCursor:      nextCursor,
+		Provenance:      provenance,
+		Drilldowns:      drilldowns,
+		DynamicDispatch: dispatchInfo,
+		External:        externalInfo,
 	}, nil
 }
 
@@ -819,69 +1183,34 @@ func sortReferences(refs []ReferenceInfo) {
 
 // searchWithTreesitter performs symbol search using tree-sitter as fallback.
 func (e *Engine) searchWithTreesitter(ctx context.Context, opts SearchSymbolsOptions) ([]SearchResultItem, error) {
-	if e.treesitterExtractor == nil {
+	if e.treesitterAdapter == nil {
 		return nil, nil
 	}
 
-	// Determine search scope
-	searchRoot := e.repoRoot
-	if opts.Scope != "" {
-		searchRoot = filepath.Join(e.repoRoot, opts.Scope)
-	}
-
-	// Extract all symbols from the directory
-	allSymbols, err := e.treesitterExtractor.ExtractDirectory(ctx, searchRoot, nil)
+	searchResult, err := e.treesitterAdapter.SearchSymbols(ctx, opts.Query, backends.SearchOptions{
+		IncludeTests: true,
+		Scope:        parseScope(opts.Scope),
+		Kind:         opts.Kinds,
+	})
 	if err != nil {
 		e.logger.Warn("Tree-sitter extraction failed", map[string]interface{}{
 			"error": err.Error(),
-			"root":  searchRoot,
+			"scope": opts.Scope,
 		})
 		return nil, err
 	}
 
-	// Filter by query string and kinds
-	queryLower := strings.ToLower(opts.Query)
-	var results []SearchResultItem
-
-	for _, sym := range allSymbols {
-		// Name matching
-		nameLower := strings.ToLower(sym.Name)
-		if !strings.Contains(nameLower, queryLower) {
-			continue
-		}
-
-		// Kind filtering
-		if len(opts.Kinds) > 0 {
-			matchesKind := false
-			for _, k := range opts.Kinds {
-				if strings.EqualFold(sym.Kind, k) {
-					matchesKind = true
-					break
-				}
-			}
-			if !matchesKind {
-				continue
-			}
-		}
-
-		// Convert to relative path for moduleId
-		relPath, _ := filepath.Rel(e.repoRoot, sym.Path)
-		if relPath == "" {
-			relPath = sym.Path
-		}
-
-		// Generate a stable ID based on path and position
-		stableId := generateTreesitterSymbolId(relPath, sym.Name, sym.Kind, sym.Line)
-
+	results := make([]SearchResultItem, 0, len(searchResult.Symbols))
+	for _, sym := range searchResult.Symbols {
 		results = append(results, SearchResultItem{
-			StableId: stableId,
+			StableId: sym.StableID,
 			Name:     sym.Name,
 			Kind:     sym.Kind,
-			ModuleId: filepath.Dir(relPath),
+			ModuleId: sym.ModuleID,
 			Location: &LocationInfo{
-				FileId:    relPath,
-				StartLine: sym.Line,
-				EndLine:   sym.EndLine,
+				FileId:    sym.Location.Path,
+				StartLine: sym.Location.Line,
+				EndLine:   sym.Location.EndLine,
 			},
 			Visibility: &VisibilityInfo{
 				Visibility: inferVisibility(sym.Name, sym.Kind),
@@ -894,11 +1223,71 @@ func (e *Engine) searchWithTreesitter(ctx context.Context, opts SearchSymbolsOpt
 	return results, nil
 }
 
+// getSymbolFromTreesitter resolves a tree-sitter symbol ID by re-parsing the
+// file it names. Used when no index is available.
+func (e *Engine) getSymbolFromTreesitter(ctx context.Context, opts GetSymbolOptions, repoState *RepoState, startTime time.Time) (*GetSymbolResponse, error) {
+	if e.treesitterAdapter == nil {
+		return nil, errors.NewCkbError(errors.BackendUnavailable, "tree-sitter backend is not available", nil, nil, nil)
+	}
+
+	result, err := e.treesitterAdapter.GetSymbol(ctx, opts.SymbolId)
+	if err != nil {
+		if ckbErr, ok := err.(*errors.CkbError); ok {
+			return nil, ckbErr
+		}
+		return nil, e.wrapError(err, errors.SymbolNotFound)
+	}
+
+	completeness := CompletenessInfo{
+		Score:   result.Completeness.Score,
+		Reason:  "treesitter-fallback",
+		Details: "Parsed on demand without an index. Run 'ckb index' for cross-file references.",
+	}
+	backendContribs := []BackendContribution{{
+		BackendId:    "treesitter",
+		Available:    true,
+		Used:         true,
+		ResultCount:  1,
+		Completeness: result.Completeness.Score,
+	}}
+
+	return &GetSymbolResponse{
+		Symbol: &SymbolInfo{
+			StableId:            result.StableID,
+			Name:                result.Name,
+			Kind:                result.Kind,
+			Signature:           result.SignatureFull,
+			SignatureNormalized: result.SignatureNormalized,
+			ParsedSignature:     parseSignature(result.SignatureFull, result.Location.Path),
+			ContainerName:       result.ContainerName,
+			ModuleId:            result.ModuleID,
+			LocationFreshness:   e.getLocationFreshness(repoState),
+			Visibility: &VisibilityInfo{
+				Visibility: inferVisibility(result.Name, result.Kind),
+				Confidence: 0.5,
+				Source:     "treesitter",
+			},
+			Location: &LocationInfo{
+				FileId:    result.Location.Path,
+				StartLine: result.Location.Line,
+				EndLine:   result.Location.EndLine,
+			},
+		},
+		Provenance: e.buildProvenance(ctx, repoState, opts.RepoStateMode, startTime, backendContribs, completeness),
+		Drilldowns: []output.Drilldown{
+			{
+				Label:  "Find references",
+				Query:  fmt.Sprintf("findReferences %s", opts.SymbolId),
+				Tool:   "findReferences",
+				Params: map[string]interface{}{"symbolId": opts.SymbolId},
+			},
+		},
+	}, nil
+}
+
 // generateTreesitterSymbolId creates a stable ID for tree-sitter extracted symbols.
 func generateTreesitterSymbolId(path, name, kind string, line int) string {
-	key := fmt.Sprintf("%s:%s:%s:%d", path, name, kind, line)
-	hash := sha256.Sum256([]byte(key))
-	return "ts-" + hex.EncodeToString(hash[:8])
+	return treesitter.SymbolID(path, name, kind, line)
 }
 
 // inferVisibility guesses visibility from naming conventions.
@@ -923,3 +1312,17 @@ func inferVisibility(name, kind string) string {
 
 	return "internal"
 }
+
+// isTestOnlyExport reports whether a public symbol is referenced, but only
+// from test files. Such symbols are exported for testing rather than as API.
+func isTestOnlyExport(visibility *VisibilityInfo, refPaths []string) bool {
+	if visibility == nil || !strings.EqualFold(visibility.Visibility, "public") || len(refPaths) == 0 {
+		return false
+	}
+	for _, path := range refPaths {
+		if !isTestFilePath(path) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,8 +1,14 @@
 package query
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"ckb/internal/backends/treesitter"
+	"ckb/internal/errors"
 )
 
 func TestParseScope(t *testing.T) {
@@ -92,6 +98,41 @@ func TestGenerateSearchCacheKey(t *testing.T) {
 			t.Errorf("generateSearchCacheKey should be kind-order independent: %q != %q", key1, key2)
 		}
 	})
+
+	t.Run("includePrivate changes key", func(t *testing.T) {
+		all := generateSearchCacheKey(SearchSymbolsOptions{Query: "Engine", Limit: 20, IncludePrivate: true})
+		public := generateSearchCacheKey(SearchSymbolsOptions{Query: "Engine", Limit: 20})
+		if all == public {
+			t.Error("generateSearchCacheKey should differ when includePrivate differs")
+		}
+	})
+}
+
+func TestFilterNonPublic(t *testing.T) {
+	item := func(id, visibility string, confidence float64) SearchResultItem {
+		return SearchResultItem{StableId: id, Visibility: &VisibilityInfo{Visibility: visibility, Confidence: confidence}}
+	}
+	results := []SearchResultItem{
+		item("public", "public", 0.9),
+		item("private", "private", 0.9),
+		item("internal", "internal", 0.7),
+		item("unknown", "unknown", 0.9),
+		item("guessed-private", "private", 0.3),
+		{StableId: "no-visibility"},
+	}
+
+	kept, dropped := filterNonPublic(results)
+	var ids []string
+	for _, r := range kept {
+		ids = append(ids, r.StableId)
+	}
+	want := []string{"public", "unknown", "guessed-private", "no-visibility"}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("filterNonPublic() kept %v, want %v", ids, want)
+	}
+	if dropped != 2 {
+		t.Errorf("filterNonPublic() dropped %d, want 2", dropped)
+	}
 }
 
 func TestNewRankingV52(t *testing.T) {
@@ -351,3 +392,173 @@ func TestRankSearchResults(t *testing.T) {
 		}
 	})
 }
+
+func TestIsTestOnlyExport(t *testing.T) {
+	public := &VisibilityInfo{Visibility: "public", Confidence: 0.9, Source: "scip-modifiers"}
+	internal := &VisibilityInfo{Visibility: "internal", Confidence: 0.9, Source: "scip-modifiers"}
+
+	tests := []struct {
+		name       string
+		visibility *VisibilityInfo
+		refPaths   []string
+		expected   bool
+	}{
+		{"public with only test refs", public, []string{"pkg/foo_test.go", "src/tests/unit/api.go"}, true},
+		{"public with mixed refs", public, []string{"pkg/foo_test.go", "pkg/bar.go"}, false},
+		{"public with no refs", public, nil, false},
+		{"internal with only test refs", internal, []string{"pkg/foo_test.go"}, false},
+		{"unknown visibility", nil, []string{"pkg/foo_test.go"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTestOnlyExport(tt.visibility, tt.refPaths); got != tt.expected {
+				t.Errorf("isTestOnlyExport() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIndexFreeFallback(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	if engine.treesitterAdapter == nil {
+		t.Skip("tree-sitter not available")
+	}
+
+	source := `package store
+
+type Store struct{}
+
+func Open() *Store {
+	return &Store{}
+}
+`
+	if err := os.MkdirAll(filepath.Join(engine.repoRoot, "store"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(engine.repoRoot, "store", "store.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	explained, err := engine.ExplainFile(ctx, ExplainFileOptions{FilePath: "store/store.go"})
+	if err != nil {
+		t.Fatalf("ExplainFile error: %v", err)
+	}
+	if len(explained.Facts.Symbols) != 2 {
+		t.Fatalf("expected 2 symbols without an index, got %+v", explained.Facts.Symbols)
+	}
+
+	var openID string
+	for _, sym := range explained.Facts.Symbols {
+		if sym.Name == "Open" {
+			openID = sym.StableId
+		}
+	}
+	if openID == "" {
+		t.Fatal("Open not found by ExplainFile")
+	}
+
+	got, err := engine.GetSymbol(ctx, GetSymbolOptions{SymbolId: openID})
+	if err != nil {
+		t.Fatalf("GetSymbol(%q) error: %v", openID, err)
+	}
+	if got.Symbol == nil || got.Symbol.Name != "Open" || got.Symbol.Location.FileId != "store/store.go" {
+		t.Fatalf("GetSymbol returned %+v", got.Symbol)
+	}
+	if got.Provenance.Completeness.Score >= 0.95 {
+		t.Errorf("completeness %.2f should reflect the lighter analysis", got.Provenance.Completeness.Score)
+	}
+}
+
+func TestTrailingSymbolName(t *testing.T) {
+	tests := map[string]string{
+		"GetSymbol":        "GetSymbol",
+		"Engine.GetSymbol": "GetSymbol",
+		"scip-go gomod ckb v1 `ckb/internal/query`/Engine#GetSymbol().": "GetSymbol",
+		"scip-go gomod ckb v1 `ckb/internal/query`/Engine#":             "Engine",
+		"pkg/Foo#bar(+1).":  "bar",
+		"ckb:repo:sym:abcd": "abcd",
+		"":                  "",
+	}
+	for id, want := range tests {
+		if got := trailingSymbolName(id); got != want {
+			t.Errorf("trailingSymbolName(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestFuzzyCandidates(t *testing.T) {
+	results := []SearchResultItem{
+		{StableId: "a", Name: "Handle", Confidence: 1.0, Location: &LocationInfo{FileId: "a.go", StartLine: 3}},
+		{StableId: "b", Name: "HandleAll", Confidence: 0.8},
+		{StableId: "a", Name: "Handle", Confidence: 1.0},
+		{StableId: "stale", Name: "Handle", Confidence: 1.0},
+	}
+
+	candidates := fuzzyCandidates(results, "stale")
+	if len(candidates) != 1 || candidates[0].StableId != "a" || candidates[0].FilePath != "a.go" {
+		t.Fatalf("fuzzyCandidates = %+v, want only a", candidates)
+	}
+
+	candidates = append(candidates, FuzzyCandidate{StableId: "c", Name: "Handle"})
+	err := ambiguousSymbolError("x.Handle", "Handle", candidates)
+	if err.Code != "SYMBOL_NOT_FOUND" {
+		t.Errorf("code = %s, want SYMBOL_NOT_FOUND", err.Code)
+	}
+	if !strings.Contains(err.Message, "a (a.go:3)") || !strings.Contains(err.Message, "c") {
+		t.Errorf("message should list candidates: %s", err.Message)
+	}
+	if len(err.Drilldowns) != 2 {
+		t.Errorf("drilldowns = %d, want 2", len(err.Drilldowns))
+	}
+}
+
+func TestFindReferencesKinds(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+	if engine.treesitterAdapter == nil {
+		t.Skip("tree-sitter backend not available")
+	}
+
+	src := "package pkg\n\nfunc Helper() {}\n\nfunc Use() {\n\tHelper()\n\tHelper()\n}\n"
+	if err := os.MkdirAll(filepath.Join(engine.repoRoot, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(engine.repoRoot, "pkg", "a.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	symbolID := treesitter.SymbolID("pkg/a.go", "Helper", "function", 3)
+	ctx := context.Background()
+
+	all, err := engine.FindReferences(ctx, FindReferencesOptions{SymbolId: symbolID})
+	if err != nil {
+		t.Fatalf("FindReferences() error = %v", err)
+	}
+	if all.TotalCount != 3 {
+		t.Fatalf("expected 3 references without a kind filter, got %+v", all.References)
+	}
+
+	// The filter applies before the limit, so totals and truncation count
+	// matching references only
+	calls, err := engine.FindReferences(ctx, FindReferencesOptions{SymbolId: symbolID, Kinds: []string{"reference"}, Limit: 1})
+	if err != nil {
+		t.Fatalf("FindReferences(kinds) error = %v", err)
+	}
+	if calls.TotalCount != 2 || len(calls.References) != 1 || !calls.Truncated {
+		t.Errorf("got total %d, %d returned, truncated %v; want 2, 1, true", calls.TotalCount, len(calls.References), calls.Truncated)
+	}
+	for _, ref := range calls.References {
+		if ref.Kind != "reference" {
+			t.Errorf("unexpected %s reference at line %d", ref.Kind, ref.Location.StartLine)
+		}
+	}
+
+	_, err = engine.FindReferences(ctx, FindReferencesOptions{SymbolId: symbolID, Kinds: []string{"calls"}})
+	ckbErr, ok := err.(*errors.CkbError)
+	if !ok || ckbErr.Code != errors.InvalidParameters {
+		t.Errorf("expected INVALID_PARAMETERS for an unknown kind, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package query
+
+import (
+	"ckb/internal/backends/git"
+	"ckb/internal/paths"
+)
+
+// pathCanonicalizer maps repo-relative paths to the file they resolve to,
+// so a symlink and its target are treated as one logical file. Lookups are
+// cached for the lifetime of a single query.
+type pathCanonicalizer struct {
+	repoRoot string
+	cache    map[string]string
+}
+
+func newPathCanonicalizer(repoRoot string) *pathCanonicalizer {
+	return &pathCanonicalizer{repoRoot: repoRoot, cache: make(map[string]string)}
+}
+
+// resolve returns the canonical repo-relative path. Symlinks pointing
+// outside the repository are left unresolved.
+func (c *pathCanonicalizer) resolve(path string) string {
+	if path == "" || c.repoRoot == "" {
+		return path
+	}
+	if canonical, ok := c.cache[path]; ok {
+		return canonical
+	}
+	canonical, ok := paths.ResolveRepoPath(path, c.repoRoot)
+	if !ok {
+		canonical = path
+	}
+	c.cache[path] = canonical
+	return canonical
+}
+
+// mergeSymlinkedHotspots folds churn recorded under a symlink into the entry
+// of the file it points to. Git tracks the link and its target separately,
+// so without this the same logical file can appear twice.
+func mergeSymlinkedHotspots(hotspots []git.ChurnMetrics, canon *pathCanonicalizer) []git.ChurnMetrics {
+	merged := make([]git.ChurnMetrics, 0, len(hotspots))
+	index := make(map[string]int, len(hotspots))
+
+	for _, h := range hotspots {
+		path := canon.resolve(h.FilePath)
+		i, seen := index[path]
+		if !seen {
+			h.FilePath = path
+			index[path] = len(merged)
+			merged = append(merged, h)
+			continue
+		}
+
+		merged[i].Merge(h)
+	}
+
+	return merged
+}
@@ -0,0 +1,93 @@
+package query
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ckb/internal/backends/git"
+)
+
+func TestMergeSymlinkedHotspots(t *testing.T) {
+	repoRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoRoot, "shared"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoRoot, "svc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, "shared", "util.go"), []byte("package shared\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../shared/util.go", filepath.Join(repoRoot, "svc", "util.go")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	merged := mergeSymlinkedHotspots([]git.ChurnMetrics{
+		{FilePath: "shared/util.go", ChangeCount: 6, AuthorCount: 2, AverageChanges: 10, LastModified: "2024-05-01T00:00:00Z", ChurnPattern: git.ChurnPatternSteady},
+		{FilePath: "other.go", ChangeCount: 3, AuthorCount: 1, AverageChanges: 4},
+		{FilePath: "svc/util.go", ChangeCount: 2, AuthorCount: 3, AverageChanges: 2, LastModified: "2024-06-01T00:00:00Z",
+			ChurnPattern: git.ChurnPatternOscillating, ChangeTimes: []string{"2024-06-01T00:00:00Z", "2024-02-01T00:00:00Z"}},
+	}, newPathCanonicalizer(repoRoot))
+
+	if len(merged) != 2 {
+		t.Fatalf("expected symlink folded into its target, got %+v", merged)
+	}
+	m := merged[0]
+	if m.FilePath != "shared/util.go" {
+		t.Errorf("FilePath = %q, want shared/util.go", m.FilePath)
+	}
+	if m.ChangeCount != 8 || m.AuthorCount != 3 || m.AverageChanges != 8 {
+		t.Errorf("unexpected merged churn: %+v", m)
+	}
+	if m.LastModified != "2024-06-01T00:00:00Z" {
+		t.Errorf("LastModified = %q, want the later of the two", m.LastModified)
+	}
+	if m.HotspotScore != git.HotspotScore(8, 3, 8) {
+		t.Errorf("expected recomputed hotspot score, got %v", m.HotspotScore)
+	}
+	if m.ChurnPattern != git.ChurnPatternOscillating || len(m.ChangeTimes) != 2 {
+		t.Errorf("pattern/change times not merged: %q %v", m.ChurnPattern, m.ChangeTimes)
+	}
+}
+
+func TestExplainFileSymlinks(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := os.MkdirAll(filepath.Join(engine.repoRoot, "shared"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(engine.repoRoot, "shared", "util.go"), []byte("package shared\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("shared/util.go", filepath.Join(engine.repoRoot, "util.go")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	external := filepath.Join(t.TempDir(), "secret.go")
+	if err := os.WriteFile(external, []byte("package secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(external, filepath.Join(engine.repoRoot, "escape.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := engine.ExplainFile(ctx, ExplainFileOptions{FilePath: "util.go"})
+	if err != nil {
+		t.Fatalf("ExplainFile: %v", err)
+	}
+	if resp.Facts.Path != "util.go" {
+		t.Errorf("Path = %q, want the requested path", resp.Facts.Path)
+	}
+	if resp.Facts.CanonicalPath != "shared/util.go" {
+		t.Errorf("CanonicalPath = %q, want shared/util.go", resp.Facts.CanonicalPath)
+	}
+
+	_, err = engine.ExplainFile(ctx, ExplainFileOptions{FilePath: "escape.go"})
+	if err == nil || !strings.Contains(err.Error(), "outside repository") {
+		t.Errorf("expected symlink escaping the repo to be rejected, got %v", err)
+	}
+}
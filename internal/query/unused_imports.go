@@ -0,0 +1,146 @@
+package query
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"ckb/internal/backends/scip"
+	"ckb/internal/modules"
+)
+
+// unusedImportLanguages are the languages whose indexers record import and
+// reference occurrences reliably enough to trust an "unused" verdict.
+var unusedImportLanguages = map[string]bool{
+	"go":         true,
+	"typescript": true,
+}
+
+// goBlankImport matches a Go import kept only for its side effects.
+var goBlankImport = regexp.MustCompile(`^\s*(?:import\s+)?_\s+"`)
+
+// importLine is an import statement and the 1-based line it's on.
+type importLine struct {
+	Path string
+	Line int
+}
+
+// findUnusedImports reports the imports of a file that no symbol in the
+// file references. Imports come from the import scanner; usage comes from
+// the SCIP occurrences in the file's document.
+func (e *Engine) findUnusedImports(absPath string, docPaths []string, language string) ([]string, error) {
+	if !unusedImportLanguages[language] {
+		return nil, fmt.Errorf("unused import detection is not supported for %s files", language)
+	}
+	if e.scipAdapter == nil || !e.scipAdapter.IsAvailable() {
+		return nil, fmt.Errorf("unused import detection requires a SCIP index")
+	}
+
+	idx := e.scipAdapter.GetIndex()
+	var doc *scip.Document
+	if idx != nil {
+		for _, p := range docPaths {
+			if doc = idx.GetDocument(p); doc != nil {
+				break
+			}
+		}
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("file is not in the SCIP index")
+	}
+
+	edges, err := modules.NewImportScanner(&e.config.ImportScan, e.logger).ScanFile(absPath, e.repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(content), "\n")
+
+	var imports []importLine
+	for _, edge := range edges {
+		if language == "go" && edge.Line <= len(lines) && goBlankImport.MatchString(lines[edge.Line-1]) {
+			continue
+		}
+		imports = append(imports, importLine{Path: edge.RawImport, Line: edge.Line})
+	}
+	return unusedImports(imports, doc.Occurrences), nil
+}
+
+// unusedImports cross-references imports with a document's occurrences. The
+// symbols occurring on an import's line are what it brings in (for Go, the
+// package; for TypeScript, the imported bindings). The import is unused when
+// none of them, or for a package anything inside it, occurs elsewhere in the
+// file. Imports whose line has no occurrences can't be judged and are never
+// reported.
+func unusedImports(imports []importLine, occurrences []*scip.Occurrence) []string {
+	importLines := make(map[int32]bool, len(imports))
+	for _, imp := range imports {
+		importLines[int32(imp.Line-1)] = true
+	}
+
+	onLine := make(map[int32][]*scip.Occurrence)
+	var uses []string
+	for _, occ := range occurrences {
+		if len(occ.Range) == 0 || strings.HasPrefix(occ.Symbol, "local ") {
+			continue
+		}
+		line := occ.Range[0]
+		if importLines[line] {
+			onLine[line] = append(onLine[line], occ)
+			continue
+		}
+		if occ.SymbolRoles&scip.SymbolRoleDefinition == 0 {
+			uses = append(uses, occ.Symbol)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var unused []string
+	for _, imp := range imports {
+		imported := importedSymbols(onLine[int32(imp.Line-1)])
+		if len(imported) == 0 || seen[imp.Path] {
+			continue
+		}
+		if !anyImportedSymbolUsed(imported, uses) {
+			seen[imp.Path] = true
+			unused = append(unused, imp.Path)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// importedSymbols picks the symbols an import line brings in: occurrences
+// marked as imports, else any non-definition occurrence.
+func importedSymbols(occs []*scip.Occurrence) []string {
+	var marked, other []string
+	for _, occ := range occs {
+		switch {
+		case occ.SymbolRoles&scip.SymbolRoleImport != 0:
+			marked = append(marked, occ.Symbol)
+		case occ.SymbolRoles&scip.SymbolRoleDefinition == 0:
+			other = append(other, occ.Symbol)
+		}
+	}
+	if len(marked) > 0 {
+		return marked
+	}
+	return other
+}
+
+func anyImportedSymbolUsed(imported, uses []string) bool {
+	for _, use := range uses {
+		for _, sym := range imported {
+			// A namespace symbol (trailing "/") covers everything declared in it
+			if use == sym || (strings.HasSuffix(sym, "/") && strings.HasPrefix(use, sym)) {
+				return true
+			}
+		}
+	}
+	return false
+}
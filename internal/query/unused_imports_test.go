@@ -0,0 +1,96 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"ckb/internal/backends/scip"
+)
+
+func occ(line int32, symbol string, roles int32) *scip.Occurrence {
+	return &scip.Occurrence{Range: []int32{line, 0, 4}, Symbol: symbol, SymbolRoles: roles}
+}
+
+func TestUnusedImports(t *testing.T) {
+	const (
+		fmtPkg     = "scip-go gomod std . `fmt`/"
+		stringsPkg = "scip-go gomod std . `strings`/"
+		osPkg      = "scip-go gomod std . `os`/"
+	)
+
+	tests := []struct {
+		name        string
+		imports     []importLine
+		occurrences []*scip.Occurrence
+		want        []string
+	}{
+		{
+			name:    "go package used through a member",
+			imports: []importLine{{Path: "fmt", Line: 4}, {Path: "strings", Line: 5}},
+			occurrences: []*scip.Occurrence{
+				occ(3, fmtPkg, 0),
+				occ(4, stringsPkg, 0),
+				occ(9, fmtPkg+"Println().", 0),
+			},
+			want: []string{"strings"},
+		},
+		{
+			name:    "go package referenced directly",
+			imports: []importLine{{Path: "os", Line: 3}},
+			occurrences: []*scip.Occurrence{
+				occ(2, osPkg, 0),
+				occ(7, osPkg, 0),
+			},
+			want: nil,
+		},
+		{
+			name:        "import line without occurrences is not judged",
+			imports:     []importLine{{Path: "embed", Line: 3}},
+			occurrences: []*scip.Occurrence{occ(9, fmtPkg+"Println().", 0)},
+			want:        nil,
+		},
+		{
+			name:    "typescript bindings",
+			imports: []importLine{{Path: "./utils", Line: 1}, {Path: "lodash", Line: 2}},
+			occurrences: []*scip.Occurrence{
+				occ(0, "scip-typescript npm app 1.0.0 src/`utils.ts`/format().", scip.SymbolRoleImport),
+				occ(0, "scip-typescript npm app 1.0.0 src/`utils.ts`/parse().", scip.SymbolRoleImport),
+				occ(1, "scip-typescript npm lodash 4.17.21 `index.d.ts`/debounce().", scip.SymbolRoleImport),
+				occ(5, "scip-typescript npm app 1.0.0 src/`utils.ts`/parse().", 0),
+			},
+			want: []string{"lodash"},
+		},
+		{
+			name:    "redefinition elsewhere is not a use",
+			imports: []importLine{{Path: "./utils", Line: 1}},
+			occurrences: []*scip.Occurrence{
+				occ(0, "scip-typescript npm app 1.0.0 src/`utils.ts`/format().", scip.SymbolRoleImport),
+				occ(4, "scip-typescript npm app 1.0.0 src/`utils.ts`/format().", scip.SymbolRoleDefinition),
+				occ(6, "local 3", 0),
+			},
+			want: []string{"./utils"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unusedImports(tt.imports, tt.occurrences)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unusedImports = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoBlankImport(t *testing.T) {
+	for line, want := range map[string]bool{
+		`	_ "embed"`:                true,
+		`import _ "net/http/pprof"`: true,
+		`	"fmt"`:                    false,
+		`	f "fmt"`:                  false,
+	} {
+		if got := goBlankImport.MatchString(line); got != want {
+			t.Errorf("goBlankImport(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"path/filepath"
+	"strings"
+
+	"ckb/internal/paths"
+)
+
+// treeWalkFilter decides which directories a file tree walk descends into.
+// Hidden directories are always skipped. With a .gitignore, ignored paths
+// are skipped as well; without one (or when gitignore rules are turned
+// off), vendor and node_modules are.
+type treeWalkFilter struct {
+	repoRoot string // Absolute
+	ignore   *paths.IgnoreMatcher
+}
+
+// newTreeWalkFilter returns a filter for walks under the repo root.
+func (e *Engine) newTreeWalkFilter(respectGitignore bool) *treeWalkFilter {
+	f := &treeWalkFilter{repoRoot: e.repoRoot}
+	if abs, err := filepath.Abs(e.repoRoot); err == nil {
+		f.repoRoot = abs
+	}
+	if respectGitignore {
+		f.ignore = paths.LoadIgnoreMatcher(e.repoRoot)
+	}
+	return f
+}
+
+// skipDir reports whether the walk should not descend into the directory
+// at path. The walk's starting directory is never skipped.
+func (f *treeWalkFilter) skipDir(path string, isRoot bool) bool {
+	if isRoot {
+		return false
+	}
+	name := filepath.Base(path)
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	if f.ignore == nil {
+		return name == "node_modules" || name == "vendor"
+	}
+	return f.ignore.Ignored(f.relPath(path), true)
+}
+
+// skipFile reports whether the file at path is gitignored.
+func (f *treeWalkFilter) skipFile(path string) bool {
+	return f.ignore != nil && f.ignore.Ignored(f.relPath(path), false)
+}
+
+// relPath returns path relative to the repo root, or "" when it lies
+// outside the repo (which nothing ignores).
+func (f *treeWalkFilter) relPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(f.repoRoot, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetModuleOverview_RespectGitignore(t *testing.T) {
+	engine, cleanup := testEngine(t)
+	defer cleanup()
+
+	root := engine.repoRoot
+	files := map[string]string{
+		".gitignore":             "dist/\n__pycache__/\n*.log\n!keep.log\n",
+		"main.go":                "package main\n",
+		"keep.log":               "kept\n",
+		"debug.log":              "ignored\n",
+		"dist/bundle.js":         "ignored\n",
+		"pkg/__pycache__/x.pyc":  "ignored\n",
+		"node_modules/lib/a.js":  "counted when .gitignore doesn't list it\n",
+		"pkg/util/helpers.py":    "def f(): pass\n",
+		"pkg/util/.hidden/a.txt": "hidden directories are always skipped\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name    string
+		respect bool
+		want    int
+	}{
+		// .gitignore, main.go, keep.log, node_modules/lib/a.js, helpers.py
+		{"gitignore", true, 5},
+		// Without gitignore rules node_modules is skipped by name instead
+		{"fallback", false, 7},
+	} {
+		resp, err := engine.GetModuleOverview(ctx, ModuleOverviewOptions{Path: root, RespectGitignore: tt.respect})
+		if err != nil {
+			t.Fatalf("%s: GetModuleOverview: %v", tt.name, err)
+		}
+		if resp.Size.FileCount != tt.want {
+			t.Errorf("%s: file count = %d, want %d", tt.name, resp.Size.FileCount, tt.want)
+		}
+	}
+}
@@ -41,7 +41,7 @@ type Module struct {
 	DetectedAt   time.Time
 	StateID      string
 	// v2 fields for Architectural Memory
-	Boundaries     *string // JSON: {public: [], internal: []}
+	Boundaries     *string // JSON: {public: [], internal: [], api: []}
 	Responsibility *string // one-sentence description
 	OwnerRef       *string // link to ownership
 	Tags           *string // JSON array
@@ -483,6 +483,35 @@ func (r *ModuleRepository) ListAll() ([]*Module, error) {
 	return r.scanModules(rows)
 }
 
+// ListWithBoundaries returns modules that have declared boundaries.
+// Only the module ID, root path and boundaries are populated.
+func (r *ModuleRepository) ListWithBoundaries() ([]*Module, error) {
+	rows, err := r.db.Query(`
+		SELECT module_id, root_path, boundaries
+		FROM modules
+		WHERE boundaries IS NOT NULL AND boundaries != '' AND boundaries != '{}'
+		ORDER BY root_path
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list module boundaries: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var modules []*Module
+	for rows.Next() {
+		var module Module
+		if err := rows.Scan(&module.ModuleID, &module.RootPath, &module.Boundaries); err != nil {
+			return nil, fmt.Errorf("failed to scan module boundaries: %w", err)
+		}
+		modules = append(modules, &module)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating module boundaries: %w", err)
+	}
+
+	return modules, nil
+}
+
 // Delete removes a module
 func (r *ModuleRepository) Delete(moduleID string) error {
 	_, err := r.db.Exec("DELETE FROM modules WHERE module_id = ?", moduleID)
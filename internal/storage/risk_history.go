@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"time"
+)
+
+// riskRecordedAtLayout keeps fractional seconds at a fixed width so that
+// recorded_at sorts chronologically as text.
+const riskRecordedAtLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// RiskScoreRecord is one recorded risk score of a symbol
+type RiskScoreRecord struct {
+	SymbolID    string
+	RepoStateID string
+	Score       float64
+	Level       string
+	RecordedAt  time.Time
+}
+
+// RecordRiskScore stores a symbol's risk score for a repo state, replacing
+// any score recorded for the same state, then prunes the symbol's history
+// to its newest maxPerSymbol scores (0 keeps everything).
+func (db *DB) RecordRiskScore(symbolID, repoStateID string, score float64, level string, maxPerSymbol int) error {
+	_, err := db.Exec(`
+		INSERT INTO risk_score_history (symbol_id, repo_state_id, risk_score, risk_level, recorded_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(symbol_id, repo_state_id) DO UPDATE SET
+			risk_score = excluded.risk_score,
+			risk_level = excluded.risk_level,
+			recorded_at = excluded.recorded_at
+	`, symbolID, repoStateID, score, level, time.Now().UTC().Format(riskRecordedAtLayout))
+	if err != nil {
+		return err
+	}
+	if maxPerSymbol <= 0 {
+		return nil
+	}
+
+	_, err = db.Exec(`
+		DELETE FROM risk_score_history
+		WHERE symbol_id = ? AND repo_state_id NOT IN (
+			SELECT repo_state_id FROM risk_score_history
+			WHERE symbol_id = ?
+			ORDER BY recorded_at DESC, rowid DESC
+			LIMIT ?
+		)
+	`, symbolID, symbolID, maxPerSymbol)
+	return err
+}
+
+// GetRiskHistory returns a symbol's newest limit risk scores, oldest first
+func (db *DB) GetRiskHistory(symbolID string, limit int) ([]RiskScoreRecord, error) {
+	rows, err := db.Query(`
+		SELECT symbol_id, repo_state_id, risk_score, risk_level, recorded_at
+		FROM risk_score_history
+		WHERE symbol_id = ?
+		ORDER BY recorded_at DESC, rowid DESC
+		LIMIT ?
+	`, symbolID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var records []RiskScoreRecord
+	for rows.Next() {
+		var r RiskScoreRecord
+		var recordedAt string
+		if err := rows.Scan(&r.SymbolID, &r.RepoStateID, &r.Score, &r.Level, &recordedAt); err != nil {
+			return nil, err
+		}
+		r.RecordedAt, _ = time.Parse(riskRecordedAtLayout, recordedAt)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	return records, nil
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"os"
+	"testing"
+
+	"ckb/internal/logging"
+)
+
+func TestRiskHistory(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ckb-risk-history-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	logger := logging.NewLogger(logging.Config{
+		Level: logging.ErrorLevel,
+	})
+
+	db, err := Open(tmpDir, logger)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// Recording the same repo state again replaces the earlier score
+	scores := []struct {
+		state string
+		score float64
+		level string
+	}{
+		{"state-1", 0.2, "low"},
+		{"state-2", 0.4, "medium"},
+		{"state-2", 0.5, "medium"},
+		{"state-3", 0.7, "high"},
+		{"state-4", 0.9, "high"},
+	}
+	for _, s := range scores {
+		if err := db.RecordRiskScore("sym:a", s.state, s.score, s.level, 3); err != nil {
+			t.Fatalf("RecordRiskScore(%s) failed: %v", s.state, err)
+		}
+	}
+	if err := db.RecordRiskScore("sym:b", "state-1", 0.1, "low", 3); err != nil {
+		t.Fatalf("RecordRiskScore(sym:b) failed: %v", err)
+	}
+
+	// Only the newest three scores of sym:a are kept, oldest first
+	records, err := db.GetRiskHistory("sym:a", 10)
+	if err != nil {
+		t.Fatalf("GetRiskHistory failed: %v", err)
+	}
+	wantStates := []string{"state-2", "state-3", "state-4"}
+	if len(records) != len(wantStates) {
+		t.Fatalf("expected %d records, got %d: %+v", len(wantStates), len(records), records)
+	}
+	for i, want := range wantStates {
+		if records[i].RepoStateID != want {
+			t.Errorf("record %d: repo state %q, want %q", i, records[i].RepoStateID, want)
+		}
+		if records[i].RecordedAt.IsZero() {
+			t.Errorf("record %d: recordedAt not parsed", i)
+		}
+	}
+	if records[0].Score != 0.5 {
+		t.Errorf("expected re-recorded state-2 score 0.5, got %v", records[0].Score)
+	}
+
+	// The limit keeps the newest records
+	records, err = db.GetRiskHistory("sym:a", 2)
+	if err != nil {
+		t.Fatalf("GetRiskHistory failed: %v", err)
+	}
+	if len(records) != 2 || records[0].RepoStateID != "state-3" || records[1].RepoStateID != "state-4" {
+		t.Errorf("unexpected limited history: %+v", records)
+	}
+
+	// Pruning is per symbol
+	records, err = db.GetRiskHistory("sym:b", 10)
+	if err != nil {
+		t.Fatalf("GetRiskHistory failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Errorf("expected 1 record for sym:b, got %d", len(records))
+	}
+}
@@ -17,7 +17,9 @@ import (
 // v9: FTS5 Symbol Search (symbols_fts_content, symbols_fts)
 // v10: Wide-Result Metrics (wide_result_metrics for MCP tool telemetry)
 // v11: Response Bytes (adds response_bytes column to wide_result_metrics)
-const currentSchemaVersion = 11
+// v12: Telemetry Daily Rollups (observed_usage_daily)
+// v13: Risk Score History (risk_score_history)
+const currentSchemaVersion = 13
 
 // initializeSchema creates all tables for a new database
 func (db *DB) initializeSchema() error {
@@ -104,6 +106,16 @@ func (db *DB) initializeSchema() error {
 			return err
 		}
 
+		// Create v12 Telemetry Daily Rollups table
+		if err := createTelemetryRollupTable(tx); err != nil {
+			return err
+		}
+
+		// Create v13 Risk Score History table
+		if err := createRiskScoreHistoryTable(tx); err != nil {
+			return err
+		}
+
 		// Set initial schema version
 		if err := setSchemaVersion(tx, currentSchemaVersion); err != nil {
 			return err
@@ -198,6 +210,18 @@ func (db *DB) runMigrations() error {
 		}
 	}
 
+	if version < 12 {
+		if err := db.migrateToV12(); err != nil {
+			return fmt.Errorf("failed to migrate to v12: %w", err)
+		}
+	}
+
+	if version < 13 {
+		if err := db.migrateToV13(); err != nil {
+			return fmt.Errorf("failed to migrate to v13: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -1499,3 +1523,122 @@ func (db *DB) migrateToV11() error {
 		return nil
 	})
 }
+
+// ============================================================================
+// v12 Schema: Telemetry Daily Rollups
+// ============================================================================
+
+// migrateToV12 migrates the database from v11 to v12 (Telemetry Daily Rollups)
+func (db *DB) migrateToV12() error {
+	return db.WithTx(func(tx *sql.Tx) error {
+		db.logger.Info("Migrating database to v12 (Telemetry Daily Rollups)", nil)
+
+		if err := createTelemetryRollupTable(tx); err != nil {
+			return err
+		}
+
+		// Backfill from existing usage rows. Weekly and monthly buckets are
+		// attributed to the first day of their period.
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO observed_usage_daily (
+				symbol_id, day, source, call_count, error_count,
+				match_quality, match_confidence, last_ingested_at
+			)
+			SELECT symbol_id,
+				CASE
+					WHEN length(period) = 10 THEN period
+					WHEN length(period) = 7 AND substr(period, 6, 1) != 'W' THEN period || '-01'
+					ELSE date(substr(period, 1, 4) || '-01-01',
+						'+' || ((CAST(substr(period, 7) AS INTEGER) - 1) * 7) || ' days')
+				END AS day,
+				source, SUM(call_count), SUM(COALESCE(error_count, 0)),
+				MAX(match_quality), MAX(match_confidence), MAX(ingested_at)
+			FROM observed_usage
+			GROUP BY symbol_id, day, source
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to backfill observed_usage_daily: %w", err)
+		}
+
+		if err := setSchemaVersion(tx, 12); err != nil {
+			return err
+		}
+
+		db.logger.Info("Database migrated to v12", nil)
+		return nil
+	})
+}
+
+// createTelemetryRollupTable creates the daily telemetry rollup table.
+// Rows are maintained incrementally on ingest so period queries sum a
+// bounded number of rows regardless of raw event volume.
+func createTelemetryRollupTable(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS observed_usage_daily (
+			symbol_id TEXT NOT NULL,
+			day TEXT NOT NULL,
+			source TEXT NOT NULL,
+			call_count INTEGER NOT NULL,
+			error_count INTEGER NOT NULL DEFAULT 0,
+			match_quality TEXT NOT NULL,
+			match_confidence REAL NOT NULL,
+			last_ingested_at TEXT NOT NULL,
+			PRIMARY KEY (symbol_id, day, source)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create observed_usage_daily table: %w", err)
+	}
+
+	if _, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_observed_daily_day ON observed_usage_daily(day)"); err != nil {
+		return fmt.Errorf("failed to create observed_usage_daily index: %w", err)
+	}
+
+	return nil
+}
+
+// ============================================================================
+// v13 Schema: Risk Score History
+// ============================================================================
+
+// migrateToV13 migrates the database from v12 to v13 (Risk Score History)
+func (db *DB) migrateToV13() error {
+	return db.WithTx(func(tx *sql.Tx) error {
+		db.logger.Info("Migrating database to v13 (Risk Score History)", nil)
+
+		if err := createRiskScoreHistoryTable(tx); err != nil {
+			return err
+		}
+
+		if err := setSchemaVersion(tx, 13); err != nil {
+			return err
+		}
+
+		db.logger.Info("Database migrated to v13", nil)
+		return nil
+	})
+}
+
+// createRiskScoreHistoryTable creates the table of recorded analyzeImpact
+// risk scores. A symbol has at most one score per repo state.
+func createRiskScoreHistoryTable(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS risk_score_history (
+			symbol_id TEXT NOT NULL,
+			repo_state_id TEXT NOT NULL,
+			risk_score REAL NOT NULL,
+			risk_level TEXT NOT NULL,
+			recorded_at TEXT NOT NULL,
+			PRIMARY KEY (symbol_id, repo_state_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create risk_score_history table: %w", err)
+	}
+
+	if _, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_risk_history_symbol ON risk_score_history(symbol_id, recorded_at)"); err != nil {
+		return fmt.Errorf("failed to create risk_score_history index: %w", err)
+	}
+
+	return nil
+}
@@ -422,6 +422,56 @@ func TestModuleAndDependencyRepositories(t *testing.T) {
 	}
 }
 
+func TestMigrateToV12BackfillsRollups(t *testing.T) {
+	db, tmpDir := setupTestDB(t)
+	defer teardownTestDB(t, db, tmpDir)
+
+	rows := []struct {
+		period, periodType string
+		calls              int
+	}{
+		{"2024-12-18", "daily", 4},
+		{"2024-W51", "weekly", 6},
+		{"2024-11", "monthly", 10},
+	}
+	for _, r := range rows {
+		_, err := db.Exec(`
+			INSERT INTO observed_usage (symbol_id, match_quality, match_confidence,
+				period, period_type, call_count, error_count, source, ingested_at)
+			VALUES ('sym', 'exact', 0.95, ?, ?, ?, 0, 'otel', '2024-12-20T00:00:00Z')
+		`, r.period, r.periodType, r.calls)
+		if err != nil {
+			t.Fatalf("Failed to insert usage: %v", err)
+		}
+	}
+
+	if err := db.migrateToV12(); err != nil {
+		t.Fatalf("migrateToV12 failed: %v", err)
+	}
+
+	got := map[string]int{}
+	result, err := db.Query("SELECT day, call_count FROM observed_usage_daily WHERE symbol_id = 'sym'")
+	if err != nil {
+		t.Fatalf("Failed to query rollups: %v", err)
+	}
+	defer result.Close()
+	for result.Next() {
+		var day string
+		var calls int
+		if err := result.Scan(&day, &calls); err != nil {
+			t.Fatal(err)
+		}
+		got[day] = calls
+	}
+
+	want := map[string]int{"2024-12-18": 4, "2024-12-16": 6, "2024-11-01": 10}
+	for day, calls := range want {
+		if got[day] != calls {
+			t.Errorf("rollup %s = %d, want %d (all: %v)", day, got[day], calls, got)
+		}
+	}
+}
+
 // Helper functions
 func timePtr(t time.Time) *time.Time {
 	return &t
@@ -0,0 +1,573 @@
+package symbols
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ParsedSignature is a function or method signature split into its parts.
+type ParsedSignature struct {
+	Receiver   *Parameter  `json:"receiver,omitempty"` // Go receiver, Python self/cls, Rust self, Kotlin extension type
+	Parameters []Parameter `json:"parameters"`
+	Returns    []string    `json:"returns,omitempty"` // Return types; empty for void/unit or when undeclared
+}
+
+// Parameter is one parameter of a signature. Name or Type may be empty when
+// the language lets a signature omit it (unnamed Go parameters, untyped JS).
+type Parameter struct {
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Optional bool   `json:"optional,omitempty"` // Has a default value or is marked optional
+	Variadic bool   `json:"variadic,omitempty"`
+}
+
+// Arity returns the number of parameters, not counting the receiver.
+func (p *ParsedSignature) Arity() int {
+	return len(p.Parameters)
+}
+
+// ParseSignature parses a signature as extracted from source (the
+// declaration's first line) for go, typescript, javascript, python, rust,
+// java, kotlin or csharp. It returns nil when the language isn't supported
+// or the signature doesn't look like a function declaration; callers then
+// fall back to the raw string.
+func ParseSignature(signature, language string) *ParsedSignature {
+	sig := strings.TrimSpace(signature)
+	if sig == "" {
+		return nil
+	}
+	switch language {
+	case "go":
+		return parseGoSignature(sig)
+	case "typescript", "javascript":
+		return parseTypeScriptSignature(sig)
+	case "python":
+		return parsePythonSignature(sig)
+	case "rust":
+		return parseRustSignature(sig)
+	case "java", "csharp":
+		return parseJavaSignature(sig)
+	case "kotlin":
+		return parseKotlinSignature(sig)
+	}
+	return nil
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+// goTypeKeywords start a Go type, so a parameter beginning with one is unnamed.
+var goTypeKeywords = map[string]bool{"chan": true, "func": true, "map": true, "struct": true, "interface": true}
+
+func parseGoSignature(sig string) *ParsedSignature {
+	rest, ok := cutPrefixWord(sig, "func")
+	if !ok {
+		return nil
+	}
+	parsed := &ParsedSignature{}
+
+	if strings.HasPrefix(rest, "(") {
+		inner, after, ok := cutGroup(rest)
+		if !ok {
+			return nil
+		}
+		receivers := parseGoParams(inner)
+		if len(receivers) != 1 {
+			return nil
+		}
+		parsed.Receiver = &receivers[0]
+		rest = strings.TrimSpace(after)
+	}
+
+	open := strings.IndexAny(rest, "([")
+	if open <= 0 {
+		return nil
+	}
+	rest = rest[open:]
+	if strings.HasPrefix(rest, "[") { // Type parameters
+		end := matchingClose(rest, 0)
+		if end < 0 {
+			return nil
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+
+	inner, after, ok := cutGroup(rest)
+	if !ok {
+		return nil
+	}
+	parsed.Parameters = parseGoParams(inner)
+
+	results := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(after), "{"))
+	if strings.HasPrefix(results, "(") {
+		inner, _, ok := cutGroup(results)
+		if !ok {
+			return nil
+		}
+		for _, r := range parseGoParams(inner) {
+			parsed.Returns = append(parsed.Returns, r.Type)
+		}
+	} else if results != "" {
+		parsed.Returns = []string{results}
+	}
+	return parsed
+}
+
+// parseGoParams parses a Go parameter list, where either every parameter
+// is named or none is, and consecutive names can share a type ("a, b int").
+func parseGoParams(list string) []Parameter {
+	parts := splitTopLevel(list, ',')
+	named := false
+	for _, part := range parts {
+		if name, typ := splitFirstWord(part); typ != "" && identifierPattern.MatchString(name) && !goTypeKeywords[name] {
+			named = true
+			break
+		}
+	}
+
+	params := make([]Parameter, 0, len(parts))
+	pendingNames := 0
+	for _, part := range parts {
+		var p Parameter
+		if named {
+			name, typ := splitFirstWord(part)
+			p.Name = name
+			p.Type = typ
+		} else {
+			p.Type = part
+		}
+		if strings.HasPrefix(p.Type, "...") {
+			p.Type = strings.TrimPrefix(p.Type, "...")
+			p.Variadic = true
+		}
+		params = append(params, p)
+
+		if named && p.Type == "" {
+			pendingNames++
+			continue
+		}
+		// Earlier bare names share this parameter's type
+		for i := len(params) - 1 - pendingNames; i < len(params)-1; i++ {
+			params[i].Type = p.Type
+		}
+		pendingNames = 0
+	}
+	return params
+}
+
+// tsModifiers may precede a TypeScript or JavaScript function name.
+var tsModifiers = map[string]bool{
+	"export": true, "default": true, "async": true, "function": true, "function*": true,
+	"public": true, "private": true, "protected": true, "static": true, "readonly": true,
+	"abstract": true, "override": true, "declare": true, "const": true, "let": true, "var": true,
+}
+
+func parseTypeScriptSignature(sig string) *ParsedSignature {
+	open := strings.Index(sig, "(")
+	if open < 0 {
+		return nil
+	}
+	head := strings.Fields(strings.TrimSuffix(strings.TrimSpace(sig[:open]), "="))
+	for len(head) > 0 && tsModifiers[head[0]] {
+		head = head[1:]
+	}
+	if len(head) > 1 {
+		return nil // Not a declaration, e.g. a call inside an expression
+	}
+
+	inner, after, ok := cutGroup(sig[open:])
+	if !ok {
+		return nil
+	}
+	parsed := &ParsedSignature{Parameters: parseColonParams(inner, "?")}
+
+	after = strings.TrimSpace(after)
+	after = strings.TrimSpace(strings.TrimSuffix(after, "{"))
+	after = strings.TrimSpace(strings.TrimSuffix(after, "=>"))
+	if strings.HasPrefix(after, ":") {
+		if ret := strings.TrimSpace(after[1:]); ret != "" && ret != "void" {
+			parsed.Returns = []string{ret}
+		}
+	}
+	return parsed
+}
+
+// paramModifiers may precede a parameter name: TypeScript parameter
+// properties, Kotlin constructor properties and Rust bindings.
+var paramModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "readonly": true, "override": true,
+	"val": true, "var": true, "mut": true,
+}
+
+// parseColonParams parses "name: Type = default" parameters, as used by
+// TypeScript, Kotlin, Rust and annotated Python. optionalMarker is a suffix
+// on the name marking it optional (TypeScript's "?").
+func parseColonParams(list, optionalMarker string) []Parameter {
+	params := []Parameter{}
+	for _, part := range splitTopLevel(list, ',') {
+		var p Parameter
+		if def := indexTopLevel(part, '='); def >= 0 {
+			part = strings.TrimSpace(part[:def])
+			p.Optional = true
+		}
+		if colon := indexTopLevel(part, ':'); colon >= 0 {
+			p.Name = strings.TrimSpace(part[:colon])
+			p.Type = strings.TrimSpace(part[colon+1:])
+		} else {
+			p.Name = part
+		}
+		if optionalMarker != "" && strings.HasSuffix(p.Name, optionalMarker) {
+			p.Name = strings.TrimSuffix(p.Name, optionalMarker)
+			p.Optional = true
+		}
+		for _, prefix := range []string{"...", "vararg ", "**", "*"} {
+			if strings.HasPrefix(p.Name, prefix) {
+				p.Name = strings.TrimSpace(strings.TrimPrefix(p.Name, prefix))
+				p.Variadic = true
+				break
+			}
+		}
+		for {
+			word, rest := splitFirstWord(p.Name)
+			if rest == "" || !paramModifiers[word] {
+				break
+			}
+			p.Name = rest
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+func parsePythonSignature(sig string) *ParsedSignature {
+	rest := strings.TrimSpace(strings.TrimPrefix(sig, "async "))
+	rest, ok := cutPrefixWord(rest, "def")
+	if !ok {
+		return nil
+	}
+	open := strings.Index(rest, "(")
+	if open <= 0 {
+		return nil
+	}
+	inner, after, ok := cutGroup(rest[open:])
+	if !ok {
+		return nil
+	}
+
+	parsed := &ParsedSignature{}
+	for _, p := range parseColonParams(inner, "") {
+		if p.Name == "*" || p.Name == "/" || (p.Name == "" && p.Variadic) {
+			continue // Keyword-only and positional-only markers
+		}
+		if parsed.Receiver == nil && len(parsed.Parameters) == 0 && (p.Name == "self" || p.Name == "cls") {
+			receiver := p
+			parsed.Receiver = &receiver
+			continue
+		}
+		parsed.Parameters = append(parsed.Parameters, p)
+	}
+	if parsed.Parameters == nil {
+		parsed.Parameters = []Parameter{}
+	}
+
+	after = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(after), ":"))
+	if ret, ok := strings.CutPrefix(after, "->"); ok {
+		if ret = strings.TrimSpace(ret); ret != "" && ret != "None" {
+			parsed.Returns = []string{ret}
+		}
+	}
+	return parsed
+}
+
+func parseRustSignature(sig string) *ParsedSignature {
+	fn := strings.Index(sig, "fn ")
+	if fn < 0 || (fn > 0 && sig[fn-1] != ' ') {
+		return nil
+	}
+	rest := sig[fn+3:]
+	open := strings.IndexAny(rest, "(<")
+	if open <= 0 {
+		return nil
+	}
+	rest = rest[open:]
+	if strings.HasPrefix(rest, "<") { // Generic parameters
+		end := matchingClose(rest, 0)
+		if end < 0 {
+			return nil
+		}
+		rest = rest[end+1:]
+	}
+	inner, after, ok := cutGroup(rest)
+	if !ok {
+		return nil
+	}
+
+	parsed := &ParsedSignature{Parameters: []Parameter{}}
+	for i, part := range splitTopLevel(inner, ',') {
+		if i == 0 && strings.HasSuffix(part, "self") && indexTopLevel(part, ':') < 0 {
+			// &self, &'a mut self, mut self
+			typ := "Self"
+			switch ref := strings.TrimSpace(strings.TrimSuffix(part, "self")); {
+			case strings.HasSuffix(ref, "&"):
+				typ = ref + typ
+			case strings.HasPrefix(ref, "&"):
+				typ = ref + " " + typ
+			}
+			parsed.Receiver = &Parameter{Name: "self", Type: typ}
+			continue
+		}
+		parsed.Parameters = append(parsed.Parameters, parseColonParams(part, "")...)
+	}
+
+	after = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(after), "{"))
+	if where := strings.Index(after, " where "); where >= 0 {
+		after = after[:where]
+	}
+	if ret, ok := strings.CutPrefix(after, "->"); ok {
+		if ret = strings.TrimSpace(ret); ret != "" && ret != "()" {
+			parsed.Returns = []string{ret}
+		}
+	}
+	return parsed
+}
+
+// javaModifiers may precede a Java or C# method's return type.
+var javaModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "internal": true, "static": true,
+	"final": true, "abstract": true, "synchronized": true, "native": true, "default": true,
+	"strictfp": true, "override": true, "virtual": true, "async": true, "sealed": true,
+	"extern": true, "unsafe": true, "new": true, "readonly": true, "partial": true,
+}
+
+func parseJavaSignature(sig string) *ParsedSignature {
+	open := strings.Index(sig, "(")
+	if open <= 0 {
+		return nil
+	}
+	var head []string
+	for _, word := range splitTopLevel(sig[:open], ' ') {
+		if javaModifiers[word] || strings.HasPrefix(word, "@") || strings.HasPrefix(word, "<") {
+			continue
+		}
+		head = append(head, word)
+	}
+	if len(head) == 0 || len(head) > 2 || !identifierPattern.MatchString(head[len(head)-1]) {
+		return nil
+	}
+
+	inner, _, ok := cutGroup(sig[open:])
+	if !ok {
+		return nil
+	}
+	parsed := &ParsedSignature{Parameters: []Parameter{}}
+	if len(head) == 2 && head[0] != "void" {
+		parsed.Returns = []string{head[0]}
+	}
+
+	for _, part := range splitTopLevel(inner, ',') {
+		optional := false
+		if def := indexTopLevel(part, '='); def >= 0 { // C# default value
+			part = part[:def]
+			optional = true
+		}
+		var words []string
+		for _, word := range splitTopLevel(part, ' ') {
+			if word == "final" || word == "params" || word == "ref" || word == "out" || word == "in" || strings.HasPrefix(word, "@") {
+				continue
+			}
+			words = append(words, word)
+		}
+		if len(words) == 0 {
+			continue
+		}
+		p := Parameter{Name: words[len(words)-1], Optional: optional}
+		if len(words) > 1 {
+			p.Type = strings.Join(words[:len(words)-1], " ")
+		}
+		if strings.HasSuffix(p.Type, "...") || strings.Contains(part, "params ") {
+			p.Type = strings.TrimSuffix(p.Type, "...")
+			p.Variadic = true
+		}
+		parsed.Parameters = append(parsed.Parameters, p)
+	}
+	return parsed
+}
+
+func parseKotlinSignature(sig string) *ParsedSignature {
+	fun := strings.Index(sig, "fun ")
+	if fun < 0 || (fun > 0 && sig[fun-1] != ' ') {
+		return nil
+	}
+	rest := strings.TrimSpace(sig[fun+4:])
+	if strings.HasPrefix(rest, "<") { // Type parameters
+		end := matchingClose(rest, 0)
+		if end < 0 {
+			return nil
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	open := strings.Index(rest, "(")
+	if open <= 0 {
+		return nil
+	}
+
+	parsed := &ParsedSignature{}
+	name := rest[:open]
+	if dot := strings.LastIndex(name, "."); dot > 0 { // Extension function
+		parsed.Receiver = &Parameter{Name: "this", Type: name[:dot]}
+	}
+
+	inner, after, ok := cutGroup(rest[open:])
+	if !ok {
+		return nil
+	}
+	parsed.Parameters = parseColonParams(inner, "")
+
+	after = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(after), "{"))
+	after = strings.TrimSpace(strings.TrimSuffix(after, "="))
+	if ret, ok := strings.CutPrefix(after, ":"); ok {
+		if ret = strings.TrimSpace(ret); ret != "" && ret != "Unit" {
+			parsed.Returns = []string{ret}
+		}
+	}
+	return parsed
+}
+
+// cutPrefixWord removes a leading keyword followed by whitespace or "(".
+func cutPrefixWord(s, word string) (string, bool) {
+	if !strings.HasPrefix(s, word) || len(s) == len(word) {
+		return "", false
+	}
+	next := s[len(word)]
+	if next != ' ' && next != '\t' && next != '(' {
+		return "", false
+	}
+	return strings.TrimSpace(s[len(word):]), true
+}
+
+// cutGroup splits s, which must start with "(", into the text inside the
+// group and the text after it.
+func cutGroup(s string) (inner, after string, ok bool) {
+	if !strings.HasPrefix(s, "(") {
+		return "", "", false
+	}
+	end := matchingClose(s, 0)
+	if end < 0 {
+		return "", "", false
+	}
+	return s[1:end], s[end+1:], true
+}
+
+// matchingClose returns the index of the bracket closing the one at open,
+// or -1 if it's unbalanced.
+func matchingClose(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch {
+		case isOpenBracket(s, i):
+			depth++
+		case isCloseBracket(s, i):
+			depth--
+		case s[i] == '"' || s[i] == '`':
+			i = skipQuoted(s, i)
+			continue
+		default:
+			continue
+		}
+		if depth == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s at sep outside brackets and quotes, trimming the
+// parts and dropping empty ones.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case isOpenBracket(s, i):
+			depth++
+		case isCloseBracket(s, i):
+			depth--
+		case s[i] == '"' || s[i] == '`':
+			i = skipQuoted(s, i)
+		case s[i] == sep && depth == 0:
+			if part := strings.TrimSpace(s[start:i]); part != "" {
+				parts = append(parts, part)
+			}
+			start = i + 1
+		}
+	}
+	if part := strings.TrimSpace(s[start:]); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// indexTopLevel returns the index of the first c outside brackets and
+// quotes, or -1. An "=" that is part of "=>" doesn't count.
+func indexTopLevel(s string, c byte) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case isOpenBracket(s, i):
+			depth++
+		case isCloseBracket(s, i):
+			depth--
+		case s[i] == '"' || s[i] == '`':
+			i = skipQuoted(s, i)
+		case s[i] == c && depth == 0:
+			if c == '=' && i+1 < len(s) && s[i+1] == '>' {
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// isOpenBracket reports whether s[i] opens a group. "<" counts as a
+// generic bracket except in Go's "<-" channel operator.
+func isOpenBracket(s string, i int) bool {
+	switch s[i] {
+	case '(', '[', '{':
+		return true
+	case '<':
+		return i+1 >= len(s) || s[i+1] != '-'
+	}
+	return false
+}
+
+// isCloseBracket reports whether s[i] closes a group; the ">" of "->" and
+// "=>" arrows doesn't.
+func isCloseBracket(s string, i int) bool {
+	switch s[i] {
+	case ')', ']', '}':
+		return true
+	case '>':
+		return i == 0 || (s[i-1] != '-' && s[i-1] != '=')
+	}
+	return false
+}
+
+// skipQuoted returns the index of the quote closing the one at i, or i when
+// it's unterminated. Single quotes are left alone: they are Rust lifetimes
+// as often as they are strings.
+func skipQuoted(s string, i int) int {
+	if end := strings.IndexByte(s[i+1:], s[i]); end >= 0 {
+		return i + end + 1
+	}
+	return i
+}
+
+// splitFirstWord splits "name rest of type" at the first space.
+func splitFirstWord(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return s, ""
+}
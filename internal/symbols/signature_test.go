@@ -0,0 +1,177 @@
+package symbols
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		signature string
+		want      *ParsedSignature
+	}{
+		{
+			name:      "go method",
+			language:  "go",
+			signature: "func (e *Engine) GetSymbol(ctx context.Context, opts GetSymbolOptions) (*GetSymbolResponse, error)",
+			want: &ParsedSignature{
+				Receiver:   &Parameter{Name: "e", Type: "*Engine"},
+				Parameters: []Parameter{{Name: "ctx", Type: "context.Context"}, {Name: "opts", Type: "GetSymbolOptions"}},
+				Returns:    []string{"*GetSymbolResponse", "error"},
+			},
+		},
+		{
+			name:      "go shared types, variadic and func param",
+			language:  "go",
+			signature: "func Walk(a, b int, fn func(string) error, opts ...Option) error {",
+			want: &ParsedSignature{
+				Parameters: []Parameter{
+					{Name: "a", Type: "int"},
+					{Name: "b", Type: "int"},
+					{Name: "fn", Type: "func(string) error"},
+					{Name: "opts", Type: "Option", Variadic: true},
+				},
+				Returns: []string{"error"},
+			},
+		},
+		{
+			name:      "go generics, unnamed params and named results",
+			language:  "go",
+			signature: "func Map[T any, U any](map[string]T, <-chan U) (n int, err error)",
+			want: &ParsedSignature{
+				Parameters: []Parameter{{Type: "map[string]T"}, {Type: "<-chan U"}},
+				Returns:    []string{"int", "error"},
+			},
+		},
+		{
+			name:      "go type declaration",
+			language:  "go",
+			signature: "type Engine struct",
+			want:      nil,
+		},
+		{
+			name:      "typescript function",
+			language:  "typescript",
+			signature: "export async function fetchUser<T>(id: string, opts?: Options, cb: (err: Error) => void = noop): Promise<User<T>> {",
+			want: &ParsedSignature{
+				Parameters: []Parameter{
+					{Name: "id", Type: "string"},
+					{Name: "opts", Type: "Options", Optional: true},
+					{Name: "cb", Type: "(err: Error) => void", Optional: true},
+				},
+				Returns: []string{"Promise<User<T>>"},
+			},
+		},
+		{
+			name:      "typescript arrow and parameter properties",
+			language:  "typescript",
+			signature: "const build = (private readonly name: string, ...rest: number[]): void =>",
+			want: &ParsedSignature{
+				Parameters: []Parameter{{Name: "name", Type: "string"}, {Name: "rest", Type: "number[]", Variadic: true}},
+			},
+		},
+		{
+			name:      "javascript untyped",
+			language:  "javascript",
+			signature: "function add(a, b = 1)",
+			want:      &ParsedSignature{Parameters: []Parameter{{Name: "a"}, {Name: "b", Optional: true}}},
+		},
+		{
+			name:      "python method",
+			language:  "python",
+			signature: "async def load(self, path: str, *args, mode: str = 'r', **kwargs) -> Dict[str, int]:",
+			want: &ParsedSignature{
+				Receiver: &Parameter{Name: "self"},
+				Parameters: []Parameter{
+					{Name: "path", Type: "str"},
+					{Name: "args", Variadic: true},
+					{Name: "mode", Type: "str", Optional: true},
+					{Name: "kwargs", Variadic: true},
+				},
+				Returns: []string{"Dict[str, int]"},
+			},
+		},
+		{
+			name:      "rust method with lifetimes",
+			language:  "rust",
+			signature: "pub fn split<'a, T: Clone>(&'a mut self, mut input: &'a str, sep: char) -> Result<Vec<T>, Error> where T: Debug {",
+			want: &ParsedSignature{
+				Receiver:   &Parameter{Name: "self", Type: "&'a mut Self"},
+				Parameters: []Parameter{{Name: "input", Type: "&'a str"}, {Name: "sep", Type: "char"}},
+				Returns:    []string{"Result<Vec<T>, Error>"},
+			},
+		},
+		{
+			name:      "java method",
+			language:  "java",
+			signature: "public static <T> List<T> of(@NonNull final Map<String, T> items, String... keys) throws IOException",
+			want: &ParsedSignature{
+				Parameters: []Parameter{{Name: "items", Type: "Map<String, T>"}, {Name: "keys", Type: "String", Variadic: true}},
+				Returns:    []string{"List<T>"},
+			},
+		},
+		{
+			name:      "java constructor",
+			language:  "java",
+			signature: "public Engine(Config config)",
+			want:      &ParsedSignature{Parameters: []Parameter{{Name: "config", Type: "Config"}}},
+		},
+		{
+			name:      "csharp defaults and params",
+			language:  "csharp",
+			signature: "public async Task<int> CountAsync(string path, int depth = 2, params string[] globs)",
+			want: &ParsedSignature{
+				Parameters: []Parameter{
+					{Name: "path", Type: "string"},
+					{Name: "depth", Type: "int", Optional: true},
+					{Name: "globs", Type: "string[]", Variadic: true},
+				},
+				Returns: []string{"Task<int>"},
+			},
+		},
+		{
+			name:      "kotlin extension",
+			language:  "kotlin",
+			signature: "override fun <T> List<T>.chunked(size: Int, vararg extra: String = \"\"): List<List<T>> {",
+			want: &ParsedSignature{
+				Receiver:   &Parameter{Name: "this", Type: "List<T>"},
+				Parameters: []Parameter{{Name: "size", Type: "Int"}, {Name: "extra", Type: "String", Optional: true, Variadic: true}},
+				Returns:    []string{"List<List<T>>"},
+			},
+		},
+		{
+			name:      "unbalanced",
+			language:  "go",
+			signature: "func Broken(a int",
+			want:      nil,
+		},
+		{
+			name:      "unsupported language",
+			language:  "ruby",
+			signature: "def foo(a, b)",
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSignature(tt.signature, tt.language)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSignature(%q) =\n  %+v\nwant\n  %+v", tt.signature, describe(got), describe(tt.want))
+			}
+		})
+	}
+}
+
+func describe(p *ParsedSignature) interface{} {
+	if p == nil {
+		return nil
+	}
+	return struct {
+		Receiver *Parameter
+		Params   []Parameter
+		Returns  []string
+	}{p.Receiver, p.Parameters, p.Returns}
+}
@@ -51,6 +51,21 @@ func (e *Extractor) ExtractDirectory(ctx context.Context, root string, filter fu
 	return nil, nil
 }
 
+// Occurrence is a place in a file where an identifier appears.
+type Occurrence struct {
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndColumn int    `json:"endColumn"`
+	Context   string `json:"context"`
+}
+
+// FindIdentifiers returns every identifier token in a file whose text is name.
+// Returns empty when CGO is not available.
+func (e *Extractor) FindIdentifiers(ctx context.Context, path, name string) ([]Occurrence, error) {
+	return nil, nil
+}
+
 // IsAvailable returns whether symbol extraction is available.
 func IsAvailable() bool {
 	return false
@@ -134,6 +134,66 @@ func (e *Extractor) ExtractDirectory(ctx context.Context, root string, filter fu
 	return allSymbols, nil
 }
 
+// Occurrence is a place in a file where an identifier appears.
+type Occurrence struct {
+	Path      string `json:"path"`
+	Line      int    `json:"line"`      // 1-indexed
+	Column    int    `json:"column"`    // 1-indexed
+	EndColumn int    `json:"endColumn"` // 1-indexed, exclusive
+	Context   string `json:"context"`   // Trimmed source line
+}
+
+// FindIdentifiers returns every identifier token in a file whose text is name.
+// This is purely syntactic: shadowed locals and unrelated symbols that share
+// the name are included.
+func (e *Extractor) FindIdentifiers(ctx context.Context, path, name string) ([]Occurrence, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	lang, ok := complexity.LanguageFromExtension(ext)
+	if !ok || name == "" || !strings.Contains(string(source), name) {
+		return nil, nil
+	}
+
+	root, err := e.parser.Parse(ctx, source, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(source), "\n")
+	var occurrences []Occurrence
+
+	var walk func(*sitter.Node)
+	walk = func(node *sitter.Node) {
+		if node == nil {
+			return
+		}
+		if node.ChildCount() == 0 && strings.HasSuffix(node.Type(), "identifier") &&
+			string(source[node.StartByte():node.EndByte()]) == name {
+			row := int(node.StartPoint().Row)
+			occ := Occurrence{
+				Path:      path,
+				Line:      row + 1,
+				Column:    int(node.StartPoint().Column) + 1,
+				EndColumn: int(node.EndPoint().Column) + 1,
+			}
+			if row < len(lines) {
+				occ.Context = strings.TrimSpace(lines[row])
+			}
+			occurrences = append(occurrences, occ)
+		}
+		for i := uint32(0); i < node.ChildCount(); i++ {
+			walk(node.Child(int(i)))
+		}
+	}
+	walk(root)
+
+	return occurrences, nil
+}
+
 // extractFunction extracts a symbol from a function node.
 func (e *Extractor) extractFunction(node *sitter.Node, source []byte, lang complexity.Language, path, container string) *Symbol {
 	name := getFunctionName(node, source, lang)
@@ -4,6 +4,8 @@ package symbols
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"ckb/internal/complexity"
@@ -431,3 +433,34 @@ func TestIsAvailable(t *testing.T) {
 		t.Error("expected IsAvailable() to be true with CGO")
 	}
 }
+
+func TestFindIdentifiers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	source := `package main
+
+func run() {}
+
+func main() {
+	run()
+	runner := "run"
+	_ = runner
+}
+`
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	occurrences, err := NewExtractor().FindIdentifiers(context.Background(), path, "run")
+	if err != nil {
+		t.Fatalf("FindIdentifiers failed: %v", err)
+	}
+
+	// The declaration and the call; not "runner" or the string literal
+	if len(occurrences) != 2 {
+		t.Fatalf("expected 2 occurrences, got %d: %+v", len(occurrences), occurrences)
+	}
+	if occurrences[1].Line != 6 || occurrences[1].Column != 2 || occurrences[1].Context != "run()" {
+		t.Errorf("unexpected call occurrence: %+v", occurrences[1])
+	}
+}
@@ -73,18 +73,16 @@ func (d *DeadCodeDetector) FindCandidates(symbols []SymbolInfo) []DeadCodeCandid
 			continue
 		}
 
-		// Get observed usage
-		usages, err := d.storage.GetObservedUsage(symbol.ID, "")
+		// Get observed usage from the daily rollups, which outlive raw rows
+		rollup, err := d.storage.GetUsageRollup(symbol.ID, "")
 		if err != nil {
 			continue
 		}
 
-		// Calculate total calls
-		var totalCalls int64
+		totalCalls := rollup.TotalCalls
 		var matchQuality MatchQuality = MatchStrong // Default to strong if no usage record
-		for _, u := range usages {
-			totalCalls += u.CallCount
-			matchQuality = u.MatchQuality
+		if len(rollup.Days) > 0 {
+			matchQuality = rollup.MatchQuality
 		}
 
 		// Skip if match quality is weak or unmatched
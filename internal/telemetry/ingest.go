@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"ckb/internal/config"
@@ -18,8 +19,17 @@ type IngestHandler struct {
 	matcher *Matcher
 	config  config.TelemetryConfig
 	logger  *logging.Logger
+
+	// Retention cleanup runs in the background at most once per
+	// cleanupInterval
+	cleanupMu   sync.Mutex
+	lastCleanup time.Time
+	cleaning    bool
 }
 
+// cleanupInterval is how often ingestion expires old telemetry rows
+const cleanupInterval = time.Hour
+
 // NewIngestHandler creates a new ingestion handler
 func NewIngestHandler(storage *Storage, mapper *ServiceMapper, matcher *Matcher, cfg config.TelemetryConfig, logger *logging.Logger) *IngestHandler {
 	return &IngestHandler{
@@ -134,6 +144,7 @@ func (h *IngestHandler) ProcessPayload(payload *IngestPayload) IngestResponse {
 			ServiceVersion:  payload.ServiceVersion,
 			Source:          payload.Source,
 			IngestedAt:      time.Now(),
+			ObservedAt:      call.PeriodStart,
 		}
 
 		if err := h.storage.SaveObservedUsage(usage); err != nil {
@@ -176,9 +187,38 @@ func (h *IngestHandler) ProcessPayload(payload *IngestPayload) IngestResponse {
 	syncLog.CoverageLevel = string(coverage.Overall.Level)
 	_ = h.storage.UpdateSyncLog(syncLog)
 
+	h.scheduleCleanup()
+
 	return response
 }
 
+// scheduleCleanup expires raw rows and rollups past their retention
+// windows. It runs in the background, at most once per cleanupInterval, so
+// ingestion doesn't wait on the deletes.
+func (h *IngestHandler) scheduleCleanup() {
+	h.cleanupMu.Lock()
+	if h.cleaning || time.Since(h.lastCleanup) < cleanupInterval {
+		h.cleanupMu.Unlock()
+		return
+	}
+	h.cleaning = true
+	h.lastCleanup = time.Now()
+	h.cleanupMu.Unlock()
+
+	go func() {
+		defer func() {
+			h.cleanupMu.Lock()
+			h.cleaning = false
+			h.cleanupMu.Unlock()
+		}()
+		if err := h.storage.CleanupOldData(h.config.Aggregation.RawRetentionDays, h.config.Aggregation.RetentionDays); err != nil {
+			h.logger.Warn("Failed to clean up old telemetry data", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}()
+}
+
 // saveUnmatchedEvent saves an event that couldn't be matched
 func (h *IngestHandler) saveUnmatchedEvent(call *CallAggregate, reason string, source string) {
 	if !h.config.Privacy.LogUnmatchedEvents {
@@ -40,6 +40,20 @@ func (s *Storage) InitSchema() error {
 		`CREATE INDEX IF NOT EXISTS idx_observed_quality ON observed_usage(match_quality)`,
 		`CREATE INDEX IF NOT EXISTS idx_observed_calls ON observed_usage(call_count DESC)`,
 
+		// Daily rollups of observed usage, maintained on ingest
+		`CREATE TABLE IF NOT EXISTS observed_usage_daily (
+			symbol_id TEXT NOT NULL,
+			day TEXT NOT NULL,
+			source TEXT NOT NULL,
+			call_count INTEGER NOT NULL,
+			error_count INTEGER NOT NULL DEFAULT 0,
+			match_quality TEXT NOT NULL,
+			match_confidence REAL NOT NULL,
+			last_ingested_at TEXT NOT NULL,
+			PRIMARY KEY (symbol_id, day, source)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_observed_daily_day ON observed_usage_daily(day)`,
+
 		// Unmatched events (separate table for clean deduplication)
 		`CREATE TABLE IF NOT EXISTS observed_unmatched (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -112,9 +126,25 @@ func (s *Storage) InitSchema() error {
 	return nil
 }
 
-// SaveObservedUsage saves or updates an observed usage record
+// rollupDayFormat is the day key of observed_usage_daily
+const rollupDayFormat = "2006-01-02"
+
+// SaveObservedUsage saves or updates an observed usage record and adds its
+// counts to the daily rollup for the day it was observed
 func (s *Storage) SaveObservedUsage(usage *ObservedUsage) error {
-	_, err := s.db.Exec(`
+	observedAt := usage.ObservedAt
+	if observedAt.IsZero() {
+		observedAt = usage.IngestedAt
+	}
+	ingestedAt := usage.IngestedAt.Format(time.RFC3339)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec(`
 		INSERT INTO observed_usage (
 			symbol_id, match_quality, match_confidence, period, period_type,
 			call_count, error_count, service_version, source, ingested_at
@@ -126,9 +156,33 @@ func (s *Storage) SaveObservedUsage(usage *ObservedUsage) error {
 	`,
 		usage.SymbolID, usage.MatchQuality, usage.MatchConfidence,
 		usage.Period, usage.PeriodType, usage.CallCount, usage.ErrorCount,
-		usage.ServiceVersion, usage.Source, usage.IngestedAt.Format(time.RFC3339),
+		usage.ServiceVersion, usage.Source, ingestedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO observed_usage_daily (
+			symbol_id, day, source, call_count, error_count,
+			match_quality, match_confidence, last_ingested_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(symbol_id, day, source) DO UPDATE SET
+			call_count = call_count + excluded.call_count,
+			error_count = error_count + excluded.error_count,
+			match_quality = excluded.match_quality,
+			match_confidence = excluded.match_confidence,
+			last_ingested_at = excluded.last_ingested_at
+	`,
+		usage.SymbolID, observedAt.Format(rollupDayFormat), usage.Source,
+		usage.CallCount, usage.ErrorCount, usage.MatchQuality, usage.MatchConfidence,
+		ingestedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // SaveUnmatchedEvent saves an unmatched telemetry event
@@ -205,6 +259,65 @@ func (s *Storage) GetObservedUsage(symbolID string, periodFilter string) ([]Obse
 	return usages, rows.Err()
 }
 
+// GetUsageRollup sums the daily rollups for a symbol from sinceDay
+// ("2024-12-18", empty for all time). Unlike GetObservedUsage it never
+// touches raw usage rows, so its cost is bounded by the number of days.
+func (s *Storage) GetUsageRollup(symbolID string, sinceDay string) (*UsageRollup, error) {
+	query := `
+		SELECT day, SUM(call_count), SUM(error_count), MAX(last_ingested_at)
+		FROM observed_usage_daily
+		WHERE symbol_id = ?
+	`
+	args := []interface{}{symbolID}
+
+	if sinceDay != "" {
+		query += " AND day >= ?"
+		args = append(args, sinceDay)
+	}
+
+	query += " GROUP BY day ORDER BY day DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	rollup := &UsageRollup{SymbolID: symbolID}
+	for rows.Next() {
+		var d DailyUsage
+		var lastIngested string
+		if err := rows.Scan(&d.Day, &d.CallCount, &d.ErrorCount, &lastIngested); err != nil {
+			return nil, err
+		}
+		rollup.TotalCalls += d.CallCount
+		rollup.TotalErrors += d.ErrorCount
+		if t, err := time.Parse(time.RFC3339, lastIngested); err == nil && t.After(rollup.LastIngestedAt) {
+			rollup.LastIngestedAt = t
+		}
+		rollup.Days = append(rollup.Days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(rollup.Days) > 0 {
+		var quality string
+		err := s.db.QueryRow(`
+			SELECT match_quality FROM observed_usage_daily
+			WHERE symbol_id = ? AND day = ?
+			ORDER BY last_ingested_at DESC
+			LIMIT 1
+		`, symbolID, rollup.Days[0].Day).Scan(&quality)
+		if err != nil {
+			return nil, err
+		}
+		rollup.MatchQuality = MatchQuality(quality)
+	}
+
+	return rollup, nil
+}
+
 // GetObservedCallers retrieves caller breakdown for a symbol
 func (s *Storage) GetObservedCallers(symbolID string, limit int) ([]ObservedCaller, error) {
 	if limit <= 0 {
@@ -511,67 +624,61 @@ func (s *Storage) GetActiveSourcesLast24h() ([]string, error) {
 	return sources, rows.Err()
 }
 
-// GetObservationWindowDays returns the number of days of telemetry data available
+// GetObservationWindowDays returns the number of days of telemetry data
+// available. It reads the daily rollups, which are kept for the full
+// retention window while raw rows are pruned sooner.
 func (s *Storage) GetObservationWindowDays() (int, error) {
-	var minPeriod, maxPeriod sql.NullString
+	var minDay, maxDay sql.NullString
 	err := s.db.QueryRow(`
-		SELECT MIN(period), MAX(period) FROM observed_usage
-	`).Scan(&minPeriod, &maxPeriod)
-	if err != nil || !minPeriod.Valid || !maxPeriod.Valid {
+		SELECT MIN(day), MAX(day) FROM observed_usage_daily
+	`).Scan(&minDay, &maxDay)
+	if err != nil || !minDay.Valid || !maxDay.Valid {
 		return 0, err
 	}
 
-	// Parse periods (format: "2024-12" or "2024-W51")
-	min, err := parsePeriod(minPeriod.String)
+	min, err := time.Parse(rollupDayFormat, minDay.String)
 	if err != nil {
-		return 0, nil //nolint:nilerr // return 0 for unparseable periods
+		return 0, nil //nolint:nilerr // return 0 for unparseable days
 	}
-	max, err := parsePeriod(maxPeriod.String)
+	max, err := time.Parse(rollupDayFormat, maxDay.String)
 	if err != nil {
-		return 0, nil //nolint:nilerr // return 0 for unparseable periods
+		return 0, nil //nolint:nilerr // return 0 for unparseable days
 	}
 
 	return int(max.Sub(min).Hours() / 24), nil
 }
 
-// parsePeriod parses a period string into a time.Time
-func parsePeriod(period string) (time.Time, error) {
-	// Try monthly format "2024-12"
-	if t, err := time.Parse("2006-01", period); err == nil {
-		return t, nil
-	}
-	// Try weekly format "2024-W51"
-	if len(period) >= 8 && period[4] == '-' && period[5] == 'W' {
-		year := period[:4]
-		week := period[6:]
-		// Approximate to first day of that week
-		t, err := time.Parse("2006", year)
-		if err == nil {
-			weekNum := 0
-			fmt.Sscanf(week, "%d", &weekNum)
-			return t.AddDate(0, 0, (weekNum-1)*7), nil
+// CleanupOldData removes raw telemetry rows older than rawRetentionDays and
+// daily rollups older than retentionDays. Rollups usually outlive raw rows,
+// so period totals remain available after the raw data is gone.
+func (s *Storage) CleanupOldData(rawRetentionDays, retentionDays int) error {
+	if retentionDays <= 0 {
+		return nil
+	}
+	if rawRetentionDays <= 0 || rawRetentionDays > retentionDays {
+		rawRetentionDays = retentionDays
+	}
+	rawCutoff := time.Now().AddDate(0, 0, -rawRetentionDays)
+	rollupCutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	// Raw periods are compared in their own bucket format
+	for _, bucket := range []string{"daily", "weekly", "monthly"} {
+		cutoff := computePeriod(rawCutoff, bucket)
+		for _, stmt := range []string{
+			"DELETE FROM observed_usage WHERE period_type = ? AND period < ?",
+			"DELETE FROM observed_unmatched WHERE period_type = ? AND period < ?",
+		} {
+			if _, err := s.db.Exec(stmt, bucket, cutoff); err != nil {
+				return err
+			}
 		}
 	}
-	return time.Time{}, fmt.Errorf("invalid period format: %s", period)
-}
-
-// CleanupOldData removes telemetry data older than retentionDays
-func (s *Storage) CleanupOldData(retentionDays int) error {
-	cutoff := time.Now().AddDate(0, 0, -retentionDays).Format("2006-01") // monthly format
-
-	tables := []string{
-		"DELETE FROM observed_usage WHERE period < ?",
-		"DELETE FROM observed_unmatched WHERE period < ?",
-		"DELETE FROM observed_callers WHERE period < ?",
-	}
 
-	for _, stmt := range tables {
-		if _, err := s.db.Exec(stmt, cutoff); err != nil {
-			return err
-		}
+	if _, err := s.db.Exec("DELETE FROM observed_callers WHERE period < ?", rawCutoff.Format("2006-01")); err != nil {
+		return err
 	}
-
-	return nil
+	_, err := s.db.Exec("DELETE FROM observed_usage_daily WHERE day < ?", rollupCutoff.Format(rollupDayFormat))
+	return err
 }
 
 // GetMatchStats returns match quality distribution
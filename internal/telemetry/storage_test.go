@@ -0,0 +1,202 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"ckb/internal/logging"
+	"ckb/internal/storage"
+)
+
+func setupTelemetryStorage(t *testing.T) *Storage {
+	t.Helper()
+	logger := logging.NewLogger(logging.Config{
+		Format: logging.HumanFormat,
+		Level:  logging.ErrorLevel,
+	})
+	db, err := storage.Open(t.TempDir(), logger)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return NewStorage(db.Conn())
+}
+
+func TestUsageRollupMatchesRawScan(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	events := []struct {
+		symbol string
+		day    string
+		source string
+		calls  int64
+		errors int64
+	}{
+		{"sym-a", "2024-11-28", "otel", 10, 1},
+		{"sym-a", "2024-11-28", "otel", 5, 0},
+		{"sym-a", "2024-12-02", "otel", 7, 2},
+		{"sym-a", "2024-12-02", "datadog", 3, 0},
+		{"sym-a", "2024-12-15", "otel", 40, 0},
+		{"sym-b", "2024-12-15", "otel", 99, 9},
+	}
+
+	for _, bucket := range []string{"daily", "weekly", "monthly"} {
+		t.Run(bucket, func(t *testing.T) {
+			s := setupTelemetryStorage(t)
+			for _, e := range events {
+				observed := day(e.day)
+				err := s.SaveObservedUsage(&ObservedUsage{
+					SymbolID:        e.symbol,
+					MatchQuality:    MatchExact,
+					MatchConfidence: MatchExact.Confidence(),
+					Period:          computePeriod(observed, bucket),
+					PeriodType:      bucket,
+					CallCount:       e.calls,
+					ErrorCount:      e.errors,
+					Source:          e.source,
+					IngestedAt:      time.Now(),
+					ObservedAt:      observed,
+				})
+				if err != nil {
+					t.Fatalf("SaveObservedUsage: %v", err)
+				}
+			}
+
+			raw, err := s.GetTotalCallCount("sym-a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			rollup, err := s.GetUsageRollup("sym-a", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rollup.TotalCalls != raw {
+				t.Errorf("all-time total: rollup %d, raw %d", rollup.TotalCalls, raw)
+			}
+			if rollup.TotalErrors != 3 {
+				t.Errorf("all-time errors = %d, want 3", rollup.TotalErrors)
+			}
+			if len(rollup.Days) != 3 || rollup.Days[0].Day != "2024-12-15" {
+				t.Errorf("expected 3 days newest first, got %+v", rollup.Days)
+			}
+			if rollup.MatchQuality != MatchExact {
+				t.Errorf("MatchQuality = %q, want exact", rollup.MatchQuality)
+			}
+
+			// Raw scans can only be cut on bucket boundaries, so compare a
+			// window starting at one.
+			since := day("2024-12-02")
+			usages, err := s.GetObservedUsage("sym-a", computePeriod(since, bucket))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rawWindow int64
+			for _, u := range usages {
+				rawWindow += u.CallCount
+			}
+			sinceDay := since.Format("2006-01-02")
+			if bucket == "monthly" {
+				sinceDay = "2024-12-01"
+			}
+			windowed, err := s.GetUsageRollup("sym-a", sinceDay)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if windowed.TotalCalls != rawWindow {
+				t.Errorf("windowed total: rollup %d, raw %d", windowed.TotalCalls, rawWindow)
+			}
+		})
+	}
+}
+
+func TestCleanupOldDataKeepsRollups(t *testing.T) {
+	s := setupTelemetryStorage(t)
+	old := time.Now().AddDate(0, 0, -60)
+
+	err := s.SaveObservedUsage(&ObservedUsage{
+		SymbolID:        "sym-a",
+		MatchQuality:    MatchStrong,
+		MatchConfidence: MatchStrong.Confidence(),
+		Period:          computePeriod(old, "daily"),
+		PeriodType:      "daily",
+		CallCount:       12,
+		Source:          "otel",
+		IngestedAt:      old,
+		ObservedAt:      old,
+	})
+	if err != nil {
+		t.Fatalf("SaveObservedUsage: %v", err)
+	}
+
+	if err := s.CleanupOldData(30, 180); err != nil {
+		t.Fatalf("CleanupOldData: %v", err)
+	}
+
+	usages, err := s.GetObservedUsage("sym-a", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usages) != 0 {
+		t.Errorf("expected raw rows past retention to be removed, got %d", len(usages))
+	}
+
+	rollup, err := s.GetUsageRollup("sym-a", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rollup.TotalCalls != 12 {
+		t.Errorf("rollup total = %d, want 12", rollup.TotalCalls)
+	}
+}
+
+func TestDeadCodeAfterRawCleanup(t *testing.T) {
+	s := setupTelemetryStorage(t)
+	save := func(symbolID string, daysAgo int, calls int64) {
+		t.Helper()
+		at := time.Now().AddDate(0, 0, -daysAgo)
+		err := s.SaveObservedUsage(&ObservedUsage{
+			SymbolID:        symbolID,
+			MatchQuality:    MatchExact,
+			MatchConfidence: MatchExact.Confidence(),
+			Period:          computePeriod(at, "daily"),
+			PeriodType:      "daily",
+			CallCount:       calls,
+			Source:          "otel",
+			IngestedAt:      at,
+			ObservedAt:      at,
+		})
+		if err != nil {
+			t.Fatalf("SaveObservedUsage: %v", err)
+		}
+	}
+	save("sym-called", 60, 12) // Only raw row falls outside raw retention
+	save("sym-idle", 100, 0)
+	save("sym-recent", 0, 5)
+
+	if err := s.CleanupOldData(30, 180); err != nil {
+		t.Fatalf("CleanupOldData: %v", err)
+	}
+
+	// The observation window and call totals come from rollups, which
+	// outlive the raw rows
+	if days, err := s.GetObservationWindowDays(); err != nil || days < 99 {
+		t.Errorf("GetObservationWindowDays() = %d, %v; want the full 100 days", days, err)
+	}
+
+	coverage := TelemetryCoverage{Overall: OverallCoverage{Level: CoverageHigh}}
+	detector := NewDeadCodeDetector(s, coverage, DeadCodeOptions{MinObservationDays: 90, Limit: 10})
+	candidates := detector.FindCandidates([]SymbolInfo{
+		{ID: "sym-called", Name: "Called"},
+		{ID: "sym-idle", Name: "Idle"},
+		{ID: "sym-recent", Name: "Recent"},
+	})
+	if len(candidates) != 1 || candidates[0].SymbolID != "sym-idle" {
+		t.Errorf("candidates = %+v, want only sym-idle", candidates)
+	}
+}
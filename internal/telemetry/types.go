@@ -90,6 +90,28 @@ type ObservedUsage struct {
 	ServiceVersion  string       `json:"serviceVersion,omitempty"`
 	Source          string       `json:"source"`
 	IngestedAt      time.Time    `json:"ingestedAt"`
+
+	// ObservedAt is the start of the observation window the calls were
+	// reported for. It selects the daily rollup bucket and is not stored
+	// on the usage row itself; zero means IngestedAt.
+	ObservedAt time.Time `json:"-"`
+}
+
+// UsageRollup is the sum of daily rollups for a symbol over a period
+type UsageRollup struct {
+	SymbolID       string       `json:"symbolId"`
+	TotalCalls     int64        `json:"totalCalls"`
+	TotalErrors    int64        `json:"totalErrors"`
+	Days           []DailyUsage `json:"days"` // Newest first
+	MatchQuality   MatchQuality `json:"matchQuality"`
+	LastIngestedAt time.Time    `json:"lastIngestedAt"`
+}
+
+// DailyUsage is the call volume of a symbol on one day, across sources
+type DailyUsage struct {
+	Day        string `json:"day"` // "2024-12-18"
+	CallCount  int64  `json:"callCount"`
+	ErrorCount int64  `json:"errorCount"`
 }
 
 // ObservedCaller represents a caller breakdown for a symbol